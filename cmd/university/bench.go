@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/bench"
+	"github.com/manedurphy/golang-university/internal/lesson"
+	"github.com/manedurphy/golang-university/internal/report"
+)
+
+var benchCommand = command{
+	name:    "bench",
+	usage:   "[-count n] [-timeout d] [-v] <module> [lesson arguments...]",
+	summary: "Run sibling lesson variants with the same arguments and compare their time, allocations, and peak heap",
+}
+
+var (
+	benchCount   int
+	benchTimeout time.Duration
+	benchVerbose bool
+)
+
+func init() {
+	benchCommand.run = runBench
+}
+
+func runBench(reg *lesson.Registry, args []string) error {
+	fs := newFlagSet(&benchCommand)
+	fs.IntVar(&benchCount, "count", 1, "The number of times to run each variant")
+	fs.DurationVar(&benchTimeout, "timeout", 30*time.Second, "How long a single run may take before it is killed")
+	fs.BoolVar(&benchVerbose, "v", false, "Print the output of every run")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("missing module")
+	}
+
+	if benchCount < 1 {
+		return errors.New("count must be at least 1")
+	}
+
+	// Variants are lessons which share a parent directory, such as
+	// 01-slices and 02-iterators in generators/04-memory-efficiency
+	var (
+		groups []string
+		byDir  = make(map[string][]lesson.Lesson)
+	)
+
+	for l := range reg.Under(fs.Arg(0)) {
+		dir := path.Dir(l.ID)
+		if _, ok := byDir[dir]; !ok {
+			groups = append(groups, dir)
+		}
+
+		byDir[dir] = append(byDir[dir], l)
+	}
+
+	if len(groups) == 0 {
+		return fmt.Errorf("%w: %s", lesson.ErrNotFound, fs.Arg(0))
+	}
+
+	tmp, err := os.MkdirTemp("", "university-bench-")
+	if err != nil {
+		return fmt.Errorf("failed to create build directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	ctx := context.Background()
+	for i, dir := range groups {
+		if i > 0 {
+			fmt.Println()
+		}
+
+		fmt.Printf("%s (count=%d)\n", dir, benchCount)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(w, "variant\ttime\tallocs\talloc bytes\tpeak heap\tGCs\t")
+
+		reports := make(map[string]*report.Report)
+		for _, l := range byDir[dir] {
+			if !runnable(reg, l) {
+				fmt.Fprintf(w, "%s\trequires go%s\t\t\t\t\t\n", path.Base(l.ID), l.Manifest.Go)
+				continue
+			}
+
+			row, rep, err := benchLesson(ctx, reg.Root(), l, tmp, fs.Args()[1:])
+			if err != nil {
+				row = fmt.Sprintf("%s\t%v\t\t\t\t\t", path.Base(l.ID), err)
+			}
+
+			if rep != nil {
+				reports[path.Base(l.ID)] = rep
+			}
+
+			fmt.Fprintln(w, row)
+		}
+
+		w.Flush()
+
+		if len(reports) > 0 {
+			printReports(byDir[dir], reports)
+		}
+	}
+
+	return nil
+}
+
+// benchLesson builds the lesson, runs it count times, and returns a table row
+// with the averages of every run, along with the average of the reports the
+// lesson recorded, if any
+func benchLesson(ctx context.Context, root string, l lesson.Lesson, dir string, args []string) (string, *report.Report, error) {
+	var (
+		total   bench.Stats
+		took    time.Duration
+		reports []*report.Report
+		out     io.Writer = io.Discard
+	)
+
+	bin, err := bench.Build(ctx, root, l, dir)
+	if err != nil {
+		return "", nil, errors.New("build failed")
+	}
+
+	if benchVerbose {
+		out = os.Stderr
+	}
+
+	for range benchCount {
+		res := bin.Run(ctx, args, benchTimeout, out, out)
+		if res.Err != nil {
+			return "", nil, res.Err
+		}
+
+		if res.Stats == nil {
+			return "", nil, errors.New("exited without returning from main")
+		}
+
+		if res.Report != nil {
+			reports = append(reports, res.Report)
+		}
+
+		took += res.Duration
+		total.Mallocs += res.Stats.Mallocs
+		total.TotalAlloc += res.Stats.TotalAlloc
+		total.PeakHeap = max(total.PeakHeap, res.Stats.PeakHeap)
+		total.NumGC += res.Stats.NumGC
+	}
+
+	n := uint64(benchCount)
+
+	// A report which was written on some runs only cannot be averaged
+	var mean *report.Report
+	if len(reports) == benchCount {
+		mean, err = report.Mean(reports)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	row := fmt.Sprintf("%s\t%s\t%d\t%s\t%s\t%d\t",
+		path.Base(l.ID),
+		(took / time.Duration(benchCount)).Round(time.Millisecond),
+		total.Mallocs/n,
+		formatBytes(total.TotalAlloc/n),
+		formatBytes(total.PeakHeap),
+		total.NumGC/uint32(benchCount),
+	)
+
+	return row, mean, nil
+}
+
+// printReports prints the averaged timings and counters which the variants
+// recorded with the report package, in the order of the variants
+func printReports(variants []lesson.Lesson, reports map[string]*report.Report) {
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "variant\tmetric\tvalue\t")
+
+	for _, l := range variants {
+		name := path.Base(l.ID)
+
+		r, ok := reports[name]
+		if !ok {
+			continue
+		}
+
+		for _, metric := range slices.Sorted(maps.Keys(r.Timings)) {
+			t := r.Timings[metric]
+			fmt.Fprintf(w, "%s\t%s\t%s (%dx, %d allocs, %s)\t\n", name, metric, t.Total.Round(time.Microsecond), t.Count, t.Allocs, formatBytes(t.AllocBytes))
+		}
+
+		for _, metric := range slices.Sorted(maps.Keys(r.Counters)) {
+			fmt.Fprintf(w, "%s\t%s\t%d\t\n", name, metric, r.Counters[metric])
+		}
+	}
+
+	w.Flush()
+}
+
+// formatBytes formats a number of bytes with a binary unit
+func formatBytes(b uint64) string {
+	const unit = 1024
+
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/check"
+	"github.com/manedurphy/golang-university/internal/lesson"
+	"github.com/manedurphy/golang-university/internal/runner"
+	"github.com/manedurphy/golang-university/internal/wasm"
+)
+
+var checkCommand = command{
+	name:    "check",
+	usage:   "[-timeout d] [-wasm] <lesson or module>",
+	summary: "Run lessons and verify their output against the expected output",
+}
+
+var (
+	checkTimeout time.Duration
+	checkWASM    bool
+)
+
+func init() {
+	checkCommand.run = runCheck
+}
+
+func runCheck(reg *lesson.Registry, args []string) error {
+	fs := newFlagSet(&checkCommand)
+	fs.DurationVar(&checkTimeout, "timeout", 30*time.Second, "How long a lesson may run before it is killed")
+	fs.BoolVar(&checkWASM, "wasm", false, "Build the lessons which run in the browser for js/wasm, and run them with Node.js")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected a single lesson or module")
+	}
+
+	// A single lesson is always checked, and fails if it has no expected
+	// output. A module checks every lesson in it which has one.
+	var (
+		lessons []lesson.Lesson
+		module  bool
+	)
+
+	l, err := reg.Lookup(fs.Arg(0))
+	switch {
+	case err == nil:
+		warnMissing(reg, l)
+		lessons = append(lessons, l)
+	case errors.Is(err, lesson.ErrNotFound):
+		module = true
+		for l := range reg.Under(fs.Arg(0)) {
+			_, err := check.Load(l.ID, reg.Dir(l))
+			if !errors.Is(err, check.ErrNoVerifier) {
+				lessons = append(lessons, l)
+			}
+		}
+
+		if len(lessons) == 0 {
+			return fmt.Errorf("no lessons with expected output found in %s", fs.Arg(0))
+		}
+	default:
+		return err
+	}
+
+	failed := 0
+	for _, l := range lessons {
+		// A module is checked with whatever the go command can build
+		if module && !runnable(reg, l) {
+			fmt.Printf("skip\t%s\trequires go%s\n", l.ID, l.Manifest.Go)
+			continue
+		}
+
+		if module && checkWASM && !l.Manifest.Browser {
+			continue
+		}
+
+		now := time.Now()
+
+		err := checkLesson(reg, l)
+		if err != nil {
+			failed++
+			fmt.Printf("FAIL\t%s\t%s\n%v\n", l.ID, time.Since(now).Round(time.Millisecond), err)
+			continue
+		}
+
+		fmt.Printf("ok\t%s\t%s\n", l.ID, time.Since(now).Round(time.Millisecond))
+		recordCompletion(reg, l, "check")
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d lessons failed", failed, len(lessons))
+	}
+
+	return nil
+}
+
+// checkLesson runs the lesson and verifies its output
+func checkLesson(reg *lesson.Registry, l lesson.Lesson) error {
+	var stdout, stderr bytes.Buffer
+
+	err := checkToolchain(reg, l)
+	if err != nil {
+		return err
+	}
+
+	v, err := check.Load(l.ID, reg.Dir(l))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+
+	opts := runner.Options{Stdout: &stdout, Stderr: &stderr, Detach: true}
+
+	// go run runs a js/wasm program with the go_js_wasm_exec of the
+	// toolchain, which Env puts on the PATH
+	if checkWASM {
+		if !l.Manifest.Browser {
+			return fmt.Errorf("%w: %s", wasm.ErrNotBrowser, l.ID)
+		}
+
+		opts.Env, err = wasm.Env(ctx, os.Environ())
+		if err != nil {
+			return err
+		}
+	}
+
+	err = runner.Run(ctx, reg.Root(), l, opts)
+	if ctx.Err() != nil {
+		return fmt.Errorf("killed after %s", checkTimeout)
+	}
+	if err != nil {
+		os.Stderr.Write(stderr.Bytes())
+		return err
+	}
+
+	return v.Verify(stdout.Bytes())
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/internal/linediff"
+	"github.com/manedurphy/golang-university/internal/runner"
+)
+
+var diffCommand = command{
+	name:    "diff",
+	usage:   "[-seed n] [-timeout d] [-width n] <lesson> <lesson>",
+	summary: "Run two lessons and show their output side by side, annotated with the differences",
+}
+
+var (
+	diffSeed    uint64
+	diffTimeout time.Duration
+	diffWidth   int
+)
+
+func init() {
+	diffCommand.run = runDiff
+}
+
+func runDiff(reg *lesson.Registry, args []string) error {
+	fs := newFlagSet(&diffCommand)
+	fs.Uint64Var(&diffSeed, "seed", 1, "The seed both lessons are run with")
+	fs.DurationVar(&diffTimeout, "timeout", 30*time.Second, "How long each lesson may run before it is killed")
+	fs.IntVar(&diffWidth, "width", 120, "The width of the output in columns")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errors.New("expected two lessons")
+	}
+
+	var outputs [2][]string
+	for i := range outputs {
+		l, err := reg.Lookup(fs.Arg(i))
+		if err != nil {
+			return err
+		}
+
+		err = checkToolchain(reg, l)
+		if err != nil {
+			return err
+		}
+
+		outputs[i], err = captureLesson(reg, l)
+		if err != nil {
+			return err
+		}
+	}
+
+	return linediff.SideBySide(os.Stdout, fs.Arg(0), fs.Arg(1), outputs[0], outputs[1], diffWidth)
+}
+
+// captureLesson runs the lesson and returns the lines of its output. Stdout
+// and stderr are captured together, so a panic appears where it happened.
+// Lessons configured by lessoncfg are given the seed, and math/rand's
+// top-level functions are seeded the same way on every run, so two lessons
+// which generate random courses generate the same ones.
+func captureLesson(reg *lesson.Registry, l lesson.Lesson) ([]string, error) {
+	var out bytes.Buffer
+
+	ctx, cancel := context.WithTimeout(context.Background(), diffTimeout)
+	defer cancel()
+
+	err := runner.Run(ctx, reg.Root(), l, runner.Options{
+		Stdout: &out,
+		Stderr: &out,
+		Detach: true,
+		Env: append(os.Environ(),
+			lessoncfg.EnvSeed+"="+strconv.FormatUint(diffSeed, 10),
+			"GODEBUG="+godebug("randautoseed=0"),
+		),
+	})
+
+	// A lesson which exits with an error, such as the panic lessons, is
+	// still worth comparing. go run reports its exit status in the output.
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() != nil:
+		return nil, fmt.Errorf("%s: killed after %s", l.ID, diffTimeout)
+	case err != nil && !errors.As(err, &exitErr):
+		return nil, err
+	}
+
+	return strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n"), nil
+}
+
+// godebug adds setting to the GODEBUG settings of the environment
+func godebug(setting string) string {
+	current := os.Getenv("GODEBUG")
+	if current == "" {
+		return setting
+	}
+
+	return current + "," + setting
+}
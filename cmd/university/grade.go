@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/grade"
+	"github.com/manedurphy/golang-university/internal/lesson"
+)
+
+var gradeCommand = command{
+	name:    "grade",
+	usage:   "[-v] [-timeout d] [-fuzztime d] [-solution] [exercise or module]",
+	summary: "Run the hidden test suites of exercises against your implementation",
+}
+
+var (
+	gradeVerbose  bool
+	gradeTimeout  time.Duration
+	gradeFuzzTime time.Duration
+	gradeSolution bool
+)
+
+func init() {
+	gradeCommand.run = runGrade
+}
+
+func runGrade(reg *lesson.Registry, args []string) error {
+	fs := newFlagSet(&gradeCommand)
+	fs.BoolVar(&gradeVerbose, "v", false, "List the tests which passed, and print the goroutine stacks of a crash")
+	fs.DurationVar(&gradeTimeout, "timeout", 30*time.Second, "How long the tests of an exercise may run before they are stopped")
+	fs.DurationVar(&gradeFuzzTime, "fuzztime", 0, "How long to fuzz each fuzz test for once every other test has passed")
+	fs.BoolVar(&gradeSolution, "solution", false, "Grade the reference solutions instead of your implementation")
+	fs.Parse(args)
+
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return errors.New("expected at most one exercise or module")
+	}
+
+	target := fs.Arg(0)
+	if target == "" {
+		target = "exercises"
+	}
+
+	var exercises []lesson.Lesson
+
+	l, err := reg.Lookup(target)
+	switch {
+	case err == nil:
+		if !gradeSolution {
+			warnMissing(reg, l)
+		}
+
+		exercises = append(exercises, l)
+	case errors.Is(err, lesson.ErrNotFound):
+		for l := range reg.Under(target) {
+			if grade.Has(l.ID) {
+				exercises = append(exercises, l)
+			}
+		}
+
+		if len(exercises) == 0 {
+			return fmt.Errorf("no exercises found in %s", target)
+		}
+	default:
+		return err
+	}
+
+	failed := 0
+	for _, l := range exercises {
+		res, err := grade.Grade(context.Background(), reg.Root(), l, grade.Options{
+			Timeout:  gradeTimeout,
+			FuzzTime: gradeFuzzTime,
+			Solution: gradeSolution,
+		})
+		if err != nil {
+			return err
+		}
+
+		printGrade(res)
+
+		if !res.Passed() {
+			failed++
+			continue
+		}
+
+		// Only your own implementation completes the exercise
+		if !gradeSolution {
+			recordCompletion(reg, l, "grade")
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d exercises failed", failed, len(exercises))
+	}
+
+	return nil
+}
+
+// printGrade prints the tests which failed along with their output, and the
+// exercise's score
+func printGrade(res *grade.Result) {
+	for _, test := range res.Tests {
+		switch {
+		case !test.Passed:
+			fmt.Printf("--- FAIL: %s\n%s", test.Name, test.Output)
+		case gradeVerbose:
+			fmt.Printf("--- PASS: %s\n", test.Name)
+		}
+	}
+
+	// The goroutine stacks which follow the panic are only useful when
+	// debugging the exercise
+	if res.Crash != "" {
+		crash := res.Crash
+		if !gradeVerbose {
+			crash, _, _ = strings.Cut(crash, "\n\n")
+		}
+
+		fmt.Printf("the tests stopped early:\n%s", indent(crash))
+	}
+
+	status := "ok"
+	if !res.Passed() {
+		status = "FAIL"
+	}
+
+	passed, total := res.Score()
+	fmt.Printf("%s\t%s\t%d/%d tests passed\n", status, res.Exercise.ID, passed, total)
+}
+
+// indent indents every line of s
+func indent(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return "    " + strings.ReplaceAll(strings.TrimSuffix(s, "\n"), "\n", "\n    ") + "\n"
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+	"github.com/manedurphy/golang-university/internal/progress"
+)
+
+var graphCommand = command{
+	name:    "graph",
+	usage:   "[module...]",
+	summary: "Print the prerequisites of every lesson, or only of the given modules, as a DOT graph",
+}
+
+func init() {
+	graphCommand.run = runGraph
+}
+
+func runGraph(reg *lesson.Registry, args []string) error {
+	fs := newFlagSet(&graphCommand)
+	fs.Parse(args)
+
+	p, err := progress.Load(progressPath(reg))
+	if err != nil {
+		return err
+	}
+
+	modules := fs.Args()
+	lessons := func(yield func(lesson.Lesson) bool) {
+		for l := range reg.Ordered() {
+			if len(modules) > 0 && !slices.Contains(modules, l.Module) {
+				continue
+			}
+
+			if !yield(l) {
+				return
+			}
+		}
+	}
+
+	err = reg.WriteDOT(os.Stdout, lessons, p.IsComplete)
+	if err != nil {
+		return fmt.Errorf("failed to write graph: %w", err)
+	}
+
+	return nil
+}
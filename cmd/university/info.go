@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+)
+
+var infoCommand = command{
+	name:    "info",
+	usage:   "<lesson>",
+	summary: "Describe a lesson: its title, difficulty, prerequisites, and objectives",
+	offline: true,
+}
+
+func init() {
+	infoCommand.run = runInfo
+}
+
+func runInfo(reg *lesson.Registry, args []string) error {
+	fs := newFlagSet(&infoCommand)
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected a single lesson")
+	}
+
+	l, err := reg.Lookup(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	m := l.Manifest
+
+	fmt.Printf("%s\n%s\n", m.Title, l.ID)
+	if m.Difficulty != "" || m.Go != "" || m.Browser {
+		fmt.Println()
+	}
+
+	if m.Difficulty != "" {
+		fmt.Printf("Difficulty: %s\n", m.Difficulty)
+	}
+
+	if m.Go != "" {
+		fmt.Printf("Requires: go%s or later\n", m.Go)
+	}
+
+	if m.Browser {
+		fmt.Println("Runs in the browser: yes")
+	}
+
+	if len(m.Prerequisites) > 0 {
+		fmt.Println("\nPrerequisites:")
+		for _, id := range m.Prerequisites {
+			fmt.Printf("  - %s\n", id)
+		}
+	}
+
+	if len(m.Objectives) > 0 {
+		fmt.Println("\nObjectives:")
+		for _, objective := range m.Objectives {
+			fmt.Printf("  - %s\n", objective)
+		}
+	}
+
+	return nil
+}
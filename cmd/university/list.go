@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+	"github.com/manedurphy/golang-university/internal/manifest"
+)
+
+var listCommand = command{
+	name:    "list",
+	usage:   "[-l] [-difficulty level] [-runnable] [module...]",
+	summary: "List the lessons of every module, or only of the given modules, in curriculum order",
+	offline: true,
+}
+
+var (
+	listLong       bool
+	listDifficulty string
+	listRunnable   bool
+)
+
+func init() {
+	listCommand.run = runList
+}
+
+func runList(reg *lesson.Registry, args []string) error {
+	fs := newFlagSet(&listCommand)
+	fs.BoolVar(&listLong, "l", false, "Show the title and difficulty of each lesson")
+	fs.StringVar(&listDifficulty, "difficulty", "", "Only list lessons of the given difficulty: beginner, intermediate, or advanced")
+	fs.BoolVar(&listRunnable, "runnable", false, "Only list lessons which the go command is new enough to build")
+	fs.Parse(args)
+
+	modules := make(map[string]bool)
+	for _, module := range fs.Args() {
+		modules[module] = true
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	found := false
+	for l := range reg.Ordered() {
+		if len(modules) > 0 && !modules[l.Module] {
+			continue
+		}
+
+		if listDifficulty != "" && l.Manifest.Difficulty != manifest.Difficulty(listDifficulty) {
+			continue
+		}
+
+		ok := runnable(reg, l)
+		if listRunnable && !ok {
+			continue
+		}
+
+		found = true
+		if !listLong {
+			fmt.Fprintln(w, l.ID)
+			continue
+		}
+
+		// Lessons which need a newer Go than the rest of the course say so
+		var requires string
+		switch {
+		case !ok:
+			requires = fmt.Sprintf("requires go%s, not runnable with %s", l.Manifest.Go, toolchainVersion(reg))
+		case l.Manifest.Go != "":
+			requires = fmt.Sprintf("requires go%s", l.Manifest.Go)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", l.ID, l.Manifest.Difficulty, l.Manifest.Title, requires)
+	}
+
+	if !found {
+		return fmt.Errorf("no lessons found in %v", fs.Args())
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+
+	university "github.com/manedurphy/golang-university"
+	"github.com/manedurphy/golang-university/internal/lesson"
+)
+
+// command is a subcommand of the university CLI
+type command struct {
+	name    string
+	usage   string
+	summary string
+	// offline commands only read the lessons, so they also work with the
+	// copy of the course embedded in the binary
+	offline bool
+	run     func(reg *lesson.Registry, args []string) error
+}
+
+// commands holds pointers so that each command's run function, which is set
+// in an init function to avoid an initialization cycle, is visible here
+var commands = []*command{
+	&listCommand,
+	&infoCommand,
+	&showCommand,
+	&nextCommand,
+	&graphCommand,
+	&runCommand,
+	&benchCommand,
+	&diffCommand,
+	&checkCommand,
+	&gradeCommand,
+	&progressCommand,
+	&quizCommand,
+	&newCommand,
+	&serveCommand,
+	&wasmCommand,
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: university <command> [arguments]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun 'university <command> -h' for more information about a command.\n")
+}
+
+// newFlagSet returns a flag set for cmd which prints the command's usage
+func newFlagSet(cmd *command) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: university %s %s\n\n%s\n", cmd.name, cmd.usage, cmd.summary)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// discover finds the lessons of the repository containing dir. Outside of
+// the repository, offline commands fall back to the course embedded in the
+// binary, so that the lessons can be browsed with the binary alone.
+func discover(dir string, cmd *command) (*lesson.Registry, error) {
+	root, err := lesson.FindRoot(dir)
+	if err == nil {
+		return lesson.Discover(root)
+	}
+
+	if !cmd.offline {
+		return nil, fmt.Errorf("%s needs a checkout of the repository: %w", cmd.name, err)
+	}
+
+	return lesson.DiscoverFS(university.Course)
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := flag.Arg(0)
+	i := slices.IndexFunc(commands, func(cmd *command) bool { return cmd.name == name })
+	if i < 0 {
+		fmt.Fprintf(os.Stderr, "university: unknown command %q\n\n", name)
+		usage()
+		os.Exit(2)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "university: failed to get working directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	reg, err := discover(wd, commands[i])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "university: %v\n", err)
+		os.Exit(1)
+	}
+
+	addPacks(reg)
+
+	err = commands[i].run(reg, flag.Args()[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "university %s: %v\n", name, err)
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+	"github.com/manedurphy/golang-university/internal/scaffold"
+)
+
+var newCommand = command{
+	name:    "new",
+	usage:   "[-kind " + strings.Join(scaffold.Kinds, "|") + "] <module>/<name>",
+	summary: "Create the skeleton of a new lesson",
+}
+
+var newKind string
+
+func init() {
+	newCommand.run = runNew
+}
+
+func runNew(reg *lesson.Registry, args []string) error {
+	fs := newFlagSet(&newCommand)
+	fs.StringVar(&newKind, "kind", "iterator", "The kind of lesson: "+strings.Join(scaffold.Kinds, ", "))
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("missing lesson")
+	}
+
+	// Allow flags after the lesson as well, as in: new iterators/06-take -kind=db
+	id := fs.Arg(0)
+	fs.Parse(fs.Args()[1:])
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	created, err := scaffold.Generate(reg.Root(), id, newKind)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range created {
+		fmt.Printf("created %s\n", path)
+	}
+
+	fmt.Printf("\nrun it with:   university run %s\ncheck it with: university check %s\n", id, id)
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/manedurphy/golang-university/internal/check"
+	"github.com/manedurphy/golang-university/internal/grade"
+	"github.com/manedurphy/golang-university/internal/lesson"
+	"github.com/manedurphy/golang-university/internal/progress"
+)
+
+var nextCommand = command{
+	name:    "next",
+	usage:   "",
+	summary: "Suggest the next lesson, whose prerequisites you have completed",
+}
+
+func init() {
+	nextCommand.run = runNext
+}
+
+func runNext(reg *lesson.Registry, args []string) error {
+	fs := newFlagSet(&nextCommand)
+	fs.Parse(args)
+
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return errors.New("expected no arguments")
+	}
+
+	p, err := progress.Load(progressPath(reg))
+	if err != nil {
+		return err
+	}
+
+	// Carry on with the module of the lesson completed most recently
+	var module string
+	id, ok := p.Last()
+	if ok {
+		l, err := reg.Lookup(id)
+		if err == nil {
+			module = l.Module
+		}
+	}
+
+	l, ok := reg.Next(p.IsComplete, module)
+	if !ok {
+		fmt.Println("every lesson is complete")
+		return nil
+	}
+
+	// Suggest the command which completes the lesson
+	cmd := "run"
+	_, err = check.Load(l.ID, reg.Dir(l))
+	switch {
+	case grade.Has(l.ID):
+		cmd = "grade"
+	case !errors.Is(err, check.ErrNoVerifier):
+		cmd = "check"
+	}
+
+	fmt.Printf("%s\n%s\n", l.Manifest.Title, l.ID)
+	if len(l.Manifest.Objectives) > 0 {
+		fmt.Println("\nObjectives:")
+		for _, objective := range l.Manifest.Objectives {
+			fmt.Printf("  - %s\n", objective)
+		}
+	}
+
+	fmt.Printf("\nuniversity %s %s\n", cmd, l.ID)
+
+	return nil
+}
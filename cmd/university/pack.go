@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/check"
+	"github.com/manedurphy/golang-university/internal/lesson"
+)
+
+// packTimeout bounds how long a pack may take to list its lessons, so that a
+// broken pack on the PATH does not hang every command
+const packTimeout = 10 * time.Second
+
+// addPacks adds the lessons of the packs on the PATH to the registry, and
+// registers the verifiers of those with an expected output. A pack which
+// fails is skipped with a warning, so that it does not keep the rest of the
+// course from being used.
+func addPacks(reg *lesson.Registry) {
+	ctx, cancel := context.WithTimeout(context.Background(), packTimeout)
+	defer cancel()
+
+	for _, program := range lesson.FindPacks(os.Getenv("PATH")) {
+		err := reg.AddPack(ctx, program)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "university: warning: skipping pack: %v\n", err)
+		}
+	}
+
+	for l := range reg.All() {
+		if l.Pack == "" || l.ExpectedOutput == "" {
+			continue
+		}
+
+		v, err := check.ParseExpectedOutput([]byte(l.ExpectedOutput))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "university: warning: invalid expected output of %s: %v\n", l.ID, err)
+			continue
+		}
+
+		check.Register(l.ID, v)
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+	"github.com/manedurphy/golang-university/internal/progress"
+	"github.com/manedurphy/golang-university/internal/report"
+)
+
+var progressCommand = command{
+	name:    "progress",
+	usage:   "[-v] | done <lesson> | reset [module or lesson]",
+	summary: "Show the completed lessons of every module, mark a lesson as done, or reset progress",
+}
+
+var progressVerbose bool
+
+func init() {
+	progressCommand.run = runProgress
+}
+
+// progressPath returns the path of the progress file. It is kept in the
+// repository so that each clone of the course has its own progress, and can
+// be overridden with the UNIVERSITY_PROGRESS environment variable.
+func progressPath(reg *lesson.Registry) string {
+	path := os.Getenv("UNIVERSITY_PROGRESS")
+	if path != "" {
+		return path
+	}
+
+	return filepath.Join(reg.Root(), ".university", "progress.json")
+}
+
+// reportPath returns the path of the report which the last run of the lesson
+// wrote, next to the progress file
+func reportPath(reg *lesson.Registry, l lesson.Lesson) string {
+	return filepath.Join(filepath.Dir(progressPath(reg)), "reports", filepath.FromSlash(l.ID)+".json")
+}
+
+// reportEnv returns the environment for running the lesson, which tells it to
+// write its report to reportPath. A report path set by the user is kept, and
+// failing to create the report's directory only means that no report is kept.
+func reportEnv(reg *lesson.Registry, l lesson.Lesson) []string {
+	if os.Getenv(report.Env) != "" {
+		return nil
+	}
+
+	path := reportPath(reg, l)
+
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "university: failed to create report directory: %v\n", err)
+		return nil
+	}
+
+	return append(os.Environ(), report.Env+"="+path)
+}
+
+// summarize formats the duration and counters of the report of a lesson's
+// last run
+func summarize(r *report.Report) string {
+	parts := []string{"last run " + r.Duration.Round(time.Millisecond).String()}
+	for _, name := range slices.Sorted(maps.Keys(r.Counters)) {
+		parts = append(parts, fmt.Sprintf("%s=%d", name, r.Counters[name]))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// recordCompletion marks a lesson as completed. Failing to record progress
+// should never fail the command which completed the lesson, so errors are
+// only reported.
+func recordCompletion(reg *lesson.Registry, l lesson.Lesson, via string) {
+	p, err := progress.Load(progressPath(reg))
+	if err == nil {
+		p.Complete(l.ID, via)
+		err = p.Save()
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "university: failed to record progress: %v\n", err)
+	}
+}
+
+// warnMissing warns about the prerequisites of a lesson which have not been
+// completed. Like recording progress, it never fails the command.
+func warnMissing(reg *lesson.Registry, l lesson.Lesson) {
+	p, err := progress.Load(progressPath(reg))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "university: failed to load progress: %v\n", err)
+		return
+	}
+
+	missing := reg.Missing(l, p.IsComplete)
+	if len(missing) == 0 {
+		return
+	}
+
+	ids := make([]string, 0, len(missing))
+	for _, m := range missing {
+		ids = append(ids, m.ID)
+	}
+
+	fmt.Fprintf(os.Stderr, "university: warning: %s builds on lessons you have not completed: %s\n", l.ID, strings.Join(ids, ", "))
+}
+
+func runProgress(reg *lesson.Registry, args []string) error {
+	fs := newFlagSet(&progressCommand)
+	fs.BoolVar(&progressVerbose, "v", false, "List every lesson and whether it has been completed")
+	fs.Parse(args)
+
+	p, err := progress.Load(progressPath(reg))
+	if err != nil {
+		return err
+	}
+
+	switch fs.Arg(0) {
+	case "":
+		return printProgress(reg, p)
+	case "done":
+		if fs.NArg() != 2 {
+			return errors.New("expected a single lesson")
+		}
+
+		l, err := reg.Lookup(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+
+		p.Complete(l.ID, "done")
+		fmt.Printf("completed %s\n", l.ID)
+
+		return p.Save()
+	case "reset":
+		if fs.NArg() > 2 {
+			return errors.New("expected at most one module or lesson")
+		}
+
+		// A lesson may be given by any ID which Lookup accepts, anything
+		// else is treated as a directory
+		prefix := fs.Arg(1)
+		l, err := reg.Lookup(prefix)
+		if err == nil {
+			prefix = l.ID
+		}
+
+		n := p.Reset(prefix)
+		fmt.Printf("reset %d lessons\n", n)
+
+		return p.Save()
+	}
+
+	fs.Usage()
+	return fmt.Errorf("unknown subcommand %q", fs.Arg(0))
+}
+
+// printProgress prints the number of completed lessons in each module
+func printProgress(reg *lesson.Registry, p *progress.Progress) error {
+	var (
+		modules   []string
+		total     = make(map[string]int)
+		completed = make(map[string]int)
+	)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	for l := range reg.All() {
+		if total[l.Module] == 0 {
+			modules = append(modules, l.Module)
+		}
+
+		total[l.Module]++
+
+		done := p.IsComplete(l.ID)
+		if done {
+			completed[l.Module]++
+		}
+
+		if progressVerbose {
+			mark := " "
+			if done {
+				mark = "x"
+			}
+
+			// Lessons which use the report package also show their last run
+			var last string
+			r, err := report.Read(reportPath(reg, l))
+			if err == nil {
+				last = summarize(r)
+			}
+
+			fmt.Fprintf(w, "[%s]\t%s\t%s\n", mark, l.ID, last)
+		}
+	}
+
+	if progressVerbose {
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w, "module\tcompleted\tquiz\t")
+	for _, module := range modules {
+		score := "-"
+		if q, ok := p.Quizzes[module]; ok {
+			score = fmt.Sprintf("%d/%d", q.Best, q.Total)
+		}
+
+		fmt.Fprintf(w, "%s\t%d/%d (%d%%)\t%s\t\n", module, completed[module], total[module], 100*completed[module]/total[module], score)
+	}
+
+	return w.Flush()
+}
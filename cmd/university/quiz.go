@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+	"github.com/manedurphy/golang-university/internal/progress"
+	"github.com/manedurphy/golang-university/internal/quiz"
+)
+
+var quizCommand = command{
+	name:    "quiz",
+	usage:   "[module]",
+	summary: "Answer a module's quiz and record the score, or list the quizzes and their best scores",
+}
+
+func init() {
+	quizCommand.run = runQuiz
+}
+
+func runQuiz(reg *lesson.Registry, args []string) error {
+	fs := newFlagSet(&quizCommand)
+	fs.Parse(args)
+
+	p, err := progress.Load(progressPath(reg))
+	if err != nil {
+		return err
+	}
+
+	switch fs.NArg() {
+	case 0:
+		return printQuizzes(p)
+	case 1:
+	default:
+		fs.Usage()
+		return errors.New("expected at most one module")
+	}
+
+	module := strings.Trim(fs.Arg(0), "/")
+
+	questions, err := quiz.Load(module)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s quiz, %d questions\n", module, len(questions))
+
+	answers := bufio.NewScanner(os.Stdin)
+
+	score := 0
+	for i, q := range questions {
+		fmt.Printf("\n%d. %s\n", i+1, q.Text)
+
+		prompt := "answer> "
+		if q.Kind() == quiz.MultipleChoice {
+			for j, choice := range q.Choices {
+				fmt.Printf("   %s) %s\n", quiz.Label(j), choice)
+			}
+		} else {
+			fmt.Printf("   Predict a line of output, run 'university show %s' to read the lesson\n", q.Lesson)
+			prompt = "output> "
+		}
+
+		fmt.Print(prompt)
+		if !answers.Scan() {
+			fmt.Println()
+			return errors.New("quiz abandoned, no score was recorded")
+		}
+
+		if q.Check(answers.Text()) {
+			score++
+			fmt.Println("correct")
+		} else if q.Kind() == quiz.MultipleChoice {
+			fmt.Printf("wrong, the answer is %s) %s\n", quiz.Label(slices.Index(q.Choices, q.Answer)), q.Answer)
+		} else {
+			fmt.Printf("wrong, the answer is %q\n", q.Answer)
+		}
+
+		if q.Explanation != "" {
+			fmt.Printf("   %s\n", q.Explanation)
+		}
+	}
+
+	err = answers.Err()
+	if err != nil {
+		return fmt.Errorf("failed to read answers: %w", err)
+	}
+
+	fmt.Printf("\nscore: %d/%d\n", score, len(questions))
+
+	p.RecordQuiz(module, score, len(questions))
+	return p.Save()
+}
+
+// printQuizzes prints every module which has a quiz, along with its best and
+// latest score
+func printQuizzes(p *progress.Progress) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "module\tbest\tlast\t")
+	for _, module := range quiz.Modules() {
+		best, last := "-", "-"
+		if q, ok := p.Quizzes[module]; ok {
+			best = fmt.Sprintf("%d/%d", q.Best, q.Total)
+			last = fmt.Sprintf("%d/%d (%s)", q.Score, q.Total, q.Taken.Local().Format("2006-01-02"))
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t\n", module, best, last)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/check"
+	"github.com/manedurphy/golang-university/internal/grade"
+	"github.com/manedurphy/golang-university/internal/lesson"
+	"github.com/manedurphy/golang-university/internal/lessontrace"
+	"github.com/manedurphy/golang-university/internal/runner"
+)
+
+var runCommand = command{
+	name:    "run",
+	usage:   "[-timeout d] [-max-output n] [-solution] [-trace] <lesson> [lesson arguments...]",
+	summary: "Run a lesson, passing any further arguments to its program",
+}
+
+var (
+	runTimeout   time.Duration
+	runMaxOutput int64
+	runSolution  bool
+	runTrace     bool
+)
+
+func init() {
+	runCommand.run = runRun
+}
+
+func runRun(reg *lesson.Registry, args []string) error {
+	fs := newFlagSet(&runCommand)
+	fs.DurationVar(&runTimeout, "timeout", 0, "Kill the lesson if it runs for longer than this, 0 never kills it")
+	fs.Int64Var(&runMaxOutput, "max-output", 10<<20, "Kill the lesson once it has written more than this many bytes, 0 never kills it")
+	fs.BoolVar(&runSolution, "solution", false, "Run the reference solution of an exercise instead of your implementation")
+	fs.BoolVar(&runTrace, "trace", false, "Number and timestamp every line the lesson prints by the trace rules of its lesson.yaml, and narrate its events once it exits")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("missing lesson")
+	}
+
+	l, err := reg.Lookup(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	err = checkToolchain(reg, l)
+	if err != nil {
+		return err
+	}
+
+	var tags []string
+	if runSolution {
+		if !reg.HasSolution(l) {
+			return fmt.Errorf("%s has no solution", l.ID)
+		}
+
+		tags = []string{lesson.SolutionTag}
+	} else {
+		warnMissing(reg, l)
+	}
+
+	var stdout io.Writer = os.Stdout
+	if runTrace {
+		if len(l.Manifest.Trace) == 0 {
+			return fmt.Errorf("%s has no trace rules", l.ID)
+		}
+
+		tracer, err := lessontrace.New(os.Stdout, l.Manifest.Trace)
+		if err != nil {
+			return err
+		}
+
+		// The narrative is printed even when the lesson fails, since a
+		// panic is often what the sequence of events leads up to
+		defer tracer.Close()
+		stdout = tracer
+	}
+
+	// Ctrl+C is delivered to the lesson as well, and several lessons handle
+	// it to shut down gracefully. Catching the signal here keeps the CLI
+	// alive until the lesson has exited. Ignoring it instead would be
+	// inherited by the lesson.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	err = runner.Run(context.Background(), reg.Root(), l, runner.Options{
+		Args:   fs.Args()[1:],
+		Stdin:  os.Stdin,
+		Stdout: stdout,
+		Stderr: os.Stderr,
+		Env:    reportEnv(reg, l),
+		// An infinite generator whose loop never breaks would otherwise
+		// print until it is interrupted
+		Timeout:   runTimeout,
+		MaxOutput: runMaxOutput,
+		Tags:      tags,
+	})
+	if errors.Is(err, runner.ErrOutputLimit) {
+		// The last line was most likely cut off
+		fmt.Println()
+	}
+	if err != nil {
+		return err
+	}
+
+	// A lesson with an expected output is only completed once its output
+	// has been checked, and an exercise once it has been graded
+	_, err = check.Load(l.ID, reg.Dir(l))
+	if errors.Is(err, check.ErrNoVerifier) && !grade.Has(l.ID) {
+		recordCompletion(reg, l, "run")
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+	"github.com/manedurphy/golang-university/internal/web"
+)
+
+var serveCommand = command{
+	name:    "serve",
+	usage:   "[-addr address] [-timeout d] [-max-runs n]",
+	summary: "Serve a web UI which lists lessons, shows their source, and runs them in the browser",
+}
+
+var (
+	serveAddr    string
+	serveTimeout time.Duration
+	serveMaxRuns int
+)
+
+func init() {
+	serveCommand.run = runServe
+}
+
+func runServe(reg *lesson.Registry, args []string) error {
+	fs := newFlagSet(&serveCommand)
+	fs.StringVar(&serveAddr, "addr", "localhost:8080", "The address to listen on")
+	fs.DurationVar(&serveTimeout, "timeout", 30*time.Second, "How long a lesson may run before it is killed")
+	fs.IntVar(&serveMaxRuns, "max-runs", 4, "The number of lessons which may run at the same time")
+	fs.Parse(args)
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	s, err := web.NewServer(reg, logger, serveTimeout, serveMaxRuns)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	srv := &http.Server{
+		Addr:    serveAddr,
+		Handler: s.Handler(),
+		// Cancelling the base context on shutdown cancels the requests
+		// which are streaming output, which kills their lessons
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		logger.Info("serving lessons", "url", fmt.Sprintf("http://%s", serveAddr))
+		errs <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err = <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = srv.Shutdown(shutdownCtx)
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("failed to shut down: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/manedurphy/golang-university/internal/check"
+	"github.com/manedurphy/golang-university/internal/lesson"
+)
+
+var showCommand = command{
+	name:    "show",
+	usage:   "[-solution] <lesson>",
+	summary: "Print a lesson's objectives, source with line numbers, and expected output",
+	offline: true,
+}
+
+var showSolution bool
+
+func init() {
+	showCommand.run = runShow
+}
+
+func runShow(reg *lesson.Registry, args []string) error {
+	flags := newFlagSet(&showCommand)
+	flags.BoolVar(&showSolution, "solution", false, "Show the reference solution of an exercise instead of the exercise")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("expected a single lesson")
+	}
+
+	l, err := reg.Lookup(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n%s\n", l.Manifest.Title, l.ID)
+	if len(l.Manifest.Objectives) > 0 {
+		fmt.Println("\nObjectives:")
+		for _, objective := range l.Manifest.Objectives {
+			fmt.Printf("  - %s\n", objective)
+		}
+	}
+
+	source := reg.Source
+	if showSolution {
+		if !reg.HasSolution(l) {
+			return fmt.Errorf("%s has no solution", l.ID)
+		}
+
+		source = reg.Solution
+	}
+
+	files, err := source(l)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		fmt.Printf("\n==> %s <==\n", file.Name)
+		err = printNumbered(file.Content)
+		if err != nil {
+			return err
+		}
+	}
+
+	expected, err := reg.ReadFile(l, check.ExpectedOutputFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read expected output: %w", err)
+	}
+
+	fmt.Printf("\n==> %s <==\n", check.ExpectedOutputFile)
+	_, err = os.Stdout.Write(expected)
+	return err
+}
+
+// printNumbered prints the lines of src prefixed with their line numbers,
+// which are aligned to the widest of them
+func printNumbered(src []byte) error {
+	lines := strings.Split(strings.TrimSuffix(string(src), "\n"), "\n")
+	width := len(strconv.Itoa(len(lines)))
+
+	for i, line := range lines {
+		_, err := fmt.Printf("%*d  %s\n", width, i+1, line)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
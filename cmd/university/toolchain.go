@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/manedurphy/golang-university/internal/goversion"
+	"github.com/manedurphy/golang-university/internal/lesson"
+)
+
+// toolchain holds the version of the go command, which is only detected the
+// first time a lesson requires a newer Go version than the rest of the course
+var toolchain struct {
+	once    sync.Once
+	version string
+}
+
+// toolchainVersion returns the version of the go command. It is empty if the
+// version could not be detected, such as outside of a checkout of the
+// repository, in which case every lesson is assumed to be runnable and the
+// go command reports what it cannot build.
+func toolchainVersion(reg *lesson.Registry) string {
+	toolchain.once.Do(func() {
+		if reg.Root() == "" {
+			return
+		}
+
+		v, err := goversion.Detect(context.Background(), reg.Root())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "university: %v\n", err)
+			return
+		}
+
+		toolchain.version = v
+	})
+
+	return toolchain.version
+}
+
+// runnable reports whether the go command can build the lesson
+func runnable(reg *lesson.Registry, l lesson.Lesson) bool {
+	return l.Manifest.Go == "" || goversion.Supports(toolchainVersion(reg), l.Manifest.Go)
+}
+
+// checkToolchain returns an error if the go command is too old to build the
+// lesson
+func checkToolchain(reg *lesson.Registry, l lesson.Lesson) error {
+	if runnable(reg, l) {
+		return nil
+	}
+
+	return fmt.Errorf("%s requires go%s or later, but the go command is %s", l.ID, l.Manifest.Go, toolchainVersion(reg))
+}
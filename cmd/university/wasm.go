@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+	"github.com/manedurphy/golang-university/internal/wasm"
+)
+
+var wasmCommand = command{
+	name:    "wasm",
+	usage:   "[-o dir] [lesson or module...]",
+	summary: "Build the lessons which run in the browser for js/wasm, along with a page which runs them",
+}
+
+var wasmOut string
+
+func init() {
+	wasmCommand.run = runWASM
+}
+
+func runWASM(reg *lesson.Registry, args []string) error {
+	fs := newFlagSet(&wasmCommand)
+	fs.StringVar(&wasmOut, "o", "", "The directory to write the page and the lessons to, which any static web server can serve (default .university/wasm in the repository)")
+	fs.Parse(args)
+
+	if wasmOut == "" {
+		wasmOut = filepath.Join(reg.Root(), ".university", "wasm")
+	}
+
+	// Without arguments, every lesson which runs in the browser is built. A
+	// lesson named on its own has to run in the browser, while a module only
+	// builds those of its lessons which do.
+	var lessons []lesson.Lesson
+	if fs.NArg() == 0 {
+		for l := range reg.Ordered() {
+			if l.Manifest.Browser {
+				lessons = append(lessons, l)
+			}
+		}
+	}
+
+	for _, arg := range fs.Args() {
+		l, err := reg.Lookup(arg)
+		switch {
+		case err == nil:
+			if !l.Manifest.Browser {
+				return fmt.Errorf("%w: %s", wasm.ErrNotBrowser, l.ID)
+			}
+			lessons = append(lessons, l)
+		case errors.Is(err, lesson.ErrNotFound):
+			for l := range reg.Under(arg) {
+				if l.Manifest.Browser {
+					lessons = append(lessons, l)
+				}
+			}
+		default:
+			return err
+		}
+	}
+
+	if len(lessons) == 0 {
+		return errors.New("no lessons which run in the browser found")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	entries, err := wasm.Export(ctx, reg.Root(), lessons, wasmOut)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		fmt.Printf("built\t%s\n", entry.ID)
+	}
+	fmt.Printf("\nServe %s with any static web server, and open %s in a browser\n", wasmOut, wasm.IndexFile)
+
+	return nil
+}
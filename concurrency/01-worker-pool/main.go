@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"iter"
+	"sync/atomic"
+	"time"
+
+	"github.com/manedurphy/golang-university/concurrency/workerpool"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+const (
+	numCourses = 1000000
+	numWorkers = 4
+)
+
+var errBadCourse = errors.New("bad course")
+
+// hashCourse simulates a CPU-bound operation on a course
+func hashCourse(course db.Course) [32]byte {
+	return sha256.Sum256(fmt.Appendf(nil, "%d:%s:%s", course.ID, course.Name, course.University))
+}
+
+// countYields wraps a sequence and counts how many values it produced, so
+// that we can see where the iterator stopped
+func countYields[T any](seq iter.Seq[T], count *atomic.Int64) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for val := range seq {
+			count.Add(1)
+			if !yield(val) {
+				fmt.Println("iterator was stopped by the worker pool")
+				return
+			}
+		}
+	}
+}
+
+func main() {
+	var (
+		processed atomic.Int64
+		yielded   atomic.Int64
+		err       error
+	)
+
+	// Process every course
+	now := time.Now()
+	err = workerpool.Process(context.Background(), db.GenerateCourses(numCourses), numWorkers, func(course db.Course) error {
+		hashCourse(course)
+		processed.Add(1)
+		return nil
+	})
+	fmt.Printf("processed %d courses in %.2f seconds (err=%v)\n", processed.Load(), time.Since(now).Seconds(), err)
+	fmt.Println()
+
+	// Fail on the course with ID 500000
+	processed.Store(0)
+	now = time.Now()
+	err = workerpool.Process(context.Background(), countYields(db.GenerateCourses(numCourses), &yielded), numWorkers, func(course db.Course) error {
+		if course.ID == numCourses/2 {
+			return fmt.Errorf("course %d: %w", course.ID, errBadCourse)
+		}
+
+		hashCourse(course)
+		processed.Add(1)
+		return nil
+	})
+	fmt.Printf("processed %d of %d yielded courses in %.2f seconds (err=%v)\n", processed.Load(), yielded.Load(), time.Since(now).Seconds(), err)
+	fmt.Println()
+
+	// Cancel the context after 100 milliseconds
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	processed.Store(0)
+	yielded.Store(0)
+	err = workerpool.Process(ctx, countYields(db.GenerateCourses(numCourses), &yielded), numWorkers, func(course db.Course) error {
+		hashCourse(course)
+		processed.Add(1)
+		return nil
+	})
+	fmt.Printf("processed %d of %d yielded courses before cancellation (err=%v)\n", processed.Load(), yielded.Load(), err)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+var (
+	numCourses int
+	failAt     int
+)
+
+var errInvalidCourse = errors.New("invalid course")
+
+func init() {
+	flag.IntVar(&numCourses, "num-courses", 100000, "The number of courses to generate")
+	flag.IntVar(&failAt, "fail-at", 0, "The ID of the course that fails validation (0 disables the failure)")
+}
+
+// generate sends every generated course to the returned channel until the
+// done channel is closed
+func generate(wg *sync.WaitGroup, done <-chan struct{}, n int) <-chan db.Course {
+	out := make(chan db.Course)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			fmt.Println("generate stage exited")
+			close(out)
+		}()
+
+		for course := range db.GenerateCourses(n) {
+			select {
+			case out <- course:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// normalize upper-cases the name of every course
+func normalize(wg *sync.WaitGroup, done <-chan struct{}, in <-chan db.Course) <-chan db.Course {
+	out := make(chan db.Course)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			fmt.Println("normalize stage exited")
+			close(out)
+		}()
+
+		for course := range in {
+			course.Name = strings.ToUpper(course.Name)
+
+			select {
+			case out <- course:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// validate rejects the course with the ID specified by the fail-at flag. Any
+// error is sent on the returned error channel, and the caller is responsible
+// for telling the other stages to stop.
+func validate(wg *sync.WaitGroup, done <-chan struct{}, in <-chan db.Course) (<-chan db.Course, <-chan error) {
+	out := make(chan db.Course)
+	errc := make(chan error, 1)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			fmt.Println("validate stage exited")
+			close(out)
+			close(errc)
+		}()
+
+		for course := range in {
+			if course.ID == failAt {
+				errc <- fmt.Errorf("course %d: %w", course.ID, errInvalidCourse)
+				return
+			}
+
+			select {
+			case out <- course:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return out, errc
+}
+
+func main() {
+	var (
+		once   sync.Once
+		wg     sync.WaitGroup
+		counts = make(map[string]int)
+		err    error
+	)
+
+	flag.Parse()
+
+	done := make(chan struct{})
+	stop := func() {
+		once.Do(func() { close(done) })
+	}
+	defer stop()
+
+	courses, errc := validate(&wg, done, normalize(&wg, done, generate(&wg, done, numCourses)))
+
+	for course := range courses {
+		counts[course.University]++
+	}
+
+	// The validate stage closes its output channel when it fails, so we only
+	// learn about the error after the loop ends. We have to close the done
+	// channel ourselves so that the upstream stages do not leak, and wait
+	// for every stage to exit.
+	err = <-errc
+	stop()
+	wg.Wait()
+
+	fmt.Printf("courses per university: %v\n", counts)
+	if err != nil {
+		fmt.Printf("pipeline failed: %v\n", err)
+	}
+}
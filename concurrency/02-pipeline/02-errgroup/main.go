@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+var (
+	numCourses int
+	failAt     int
+)
+
+var errInvalidCourse = errors.New("invalid course")
+
+func init() {
+	flag.IntVar(&numCourses, "num-courses", 100000, "The number of courses to generate")
+	flag.IntVar(&failAt, "fail-at", 0, "The ID of the course that fails validation (0 disables the failure)")
+}
+
+// generate sends every generated course to the out channel until the
+// context is cancelled
+func generate(ctx context.Context, n int, out chan<- db.Course) error {
+	defer func() {
+		fmt.Println("generate stage exited")
+		close(out)
+	}()
+
+	for course := range db.GenerateCourses(n) {
+		select {
+		case out <- course:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// normalize upper-cases the name of every course
+func normalize(ctx context.Context, in <-chan db.Course, out chan<- db.Course) error {
+	defer func() {
+		fmt.Println("normalize stage exited")
+		close(out)
+	}()
+
+	for course := range in {
+		course.Name = strings.ToUpper(course.Name)
+
+		select {
+		case out <- course:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// validate rejects the course with the ID specified by the fail-at flag
+func validate(ctx context.Context, in <-chan db.Course, out chan<- db.Course) error {
+	defer func() {
+		fmt.Println("validate stage exited")
+		close(out)
+	}()
+
+	for course := range in {
+		if course.ID == failAt {
+			return fmt.Errorf("course %d: %w", course.ID, errInvalidCourse)
+		}
+
+		select {
+		case out <- course:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// count tallies the number of courses per university
+func count(ctx context.Context, in <-chan db.Course, counts map[string]int) error {
+	defer fmt.Println("count stage exited")
+
+	for {
+		select {
+		case course, ok := <-in:
+			if !ok {
+				return nil
+			}
+
+			counts[course.University]++
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func main() {
+	var (
+		counts = make(map[string]int)
+		err    error
+	)
+
+	flag.Parse()
+
+	// The context returned by errgroup.WithContext is cancelled the first
+	// time a stage returns a non-nil error, which replaces the done channel
+	g, ctx := errgroup.WithContext(context.Background())
+
+	generated := make(chan db.Course)
+	normalized := make(chan db.Course)
+	validated := make(chan db.Course)
+
+	g.Go(func() error { return generate(ctx, numCourses, generated) })
+	g.Go(func() error { return normalize(ctx, generated, normalized) })
+	g.Go(func() error { return validate(ctx, normalized, validated) })
+	g.Go(func() error { return count(ctx, validated, counts) })
+
+	// Wait blocks until every stage has exited and returns the first error
+	err = g.Wait()
+
+	fmt.Printf("courses per university: %v\n", counts)
+	if err != nil {
+		fmt.Printf("pipeline failed: %v\n", err)
+	}
+}
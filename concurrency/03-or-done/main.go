@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/manedurphy/golang-university/concurrency/chanutil"
+)
+
+// generateNumbers is the generator from the leaking goroutine lesson. Once
+// the consumer stops receiving, the goroutine blocks on its send forever.
+func generateNumbers() <-chan int {
+	ch := make(chan int)
+
+	go func() {
+		for i := 20; i <= 25; i++ {
+			ch <- i
+		}
+
+		close(ch)
+	}()
+
+	return ch
+}
+
+// generateNumbersCtx is the same generator, except every send selects on the
+// context so that the goroutine returns once the context is cancelled
+func generateNumbersCtx(ctx context.Context) <-chan int {
+	ch := make(chan int)
+
+	go func() {
+		defer close(ch)
+
+		for i := 20; i <= 25; i++ {
+			select {
+			case ch <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// goroutines gives exiting goroutines a moment to finish before counting
+func goroutines() int {
+	time.Sleep(10 * time.Millisecond)
+	return runtime.NumGoroutine()
+}
+
+func leaking() {
+	for num := range generateNumbers() {
+		fmt.Printf("number received in range-loop: %d\n", num)
+
+		if num == 23 {
+			break
+		}
+	}
+}
+
+func orDone() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Cancelling the context on the way out is all the consumer has to do. The
+	// loop body does not need a select statement of its own.
+	defer cancel()
+
+	for num := range chanutil.OrDone(ctx, generateNumbersCtx(ctx)) {
+		fmt.Printf("number received in range-loop: %d\n", num)
+
+		if num == 23 {
+			break
+		}
+	}
+}
+
+func main() {
+	before := goroutines()
+	leaking()
+	fmt.Printf("goroutines before: %d, after: %d\n", before, goroutines())
+	fmt.Println()
+
+	before = goroutines()
+	orDone()
+	fmt.Printf("goroutines before: %d, after: %d\n", before, goroutines())
+	fmt.Println()
+
+	// OrDone also lets a consumer give up on a channel that is never closed
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	before = goroutines()
+	for range chanutil.OrDone(ctx, make(chan int)) {
+	}
+	fmt.Printf("stopped waiting on a silent channel: %v\n", ctx.Err())
+	fmt.Printf("goroutines before: %d, after: %d\n", before, goroutines())
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/manedurphy/golang-university/concurrency/chanutil"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/itertools"
+)
+
+const (
+	numEvents  = 100
+	numCourses = 1000000
+)
+
+// generateCourses sends every generated course to the returned channel
+func generateCourses(ctx context.Context, n int) <-chan db.Course {
+	ch := make(chan db.Course)
+
+	go func() {
+		defer close(ch)
+
+		for course := range db.GenerateCourses(n) {
+			select {
+			case ch <- course:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+func teeChan() {
+	var wg sync.WaitGroup
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fast, slow := chanutil.TeeChan(ctx, generateCourses(ctx, numEvents))
+
+	now := time.Now()
+	consume := func(name string, ch <-chan db.Course, delay time.Duration) {
+		defer wg.Done()
+
+		for range ch {
+			time.Sleep(delay)
+		}
+		fmt.Printf("%s consumer finished after %d ms\n", name, time.Since(now).Milliseconds())
+	}
+
+	wg.Add(2)
+	go consume("fast", fast, 0)
+	go consume("slow", slow, time.Millisecond)
+	wg.Wait()
+}
+
+func tee() {
+	var memBefore, memAfter runtime.MemStats
+
+	first, second := itertools.Tee(db.GenerateCourses(numCourses))
+
+	runtime.ReadMemStats(&memBefore)
+
+	// Consuming the first iterator completely buffers every course for the
+	// second iterator
+	count := 0
+	for range first {
+		count++
+	}
+	fmt.Printf("first iterator yielded %d courses\n", count)
+
+	runtime.ReadMemStats(&memAfter)
+	fmt.Printf("memory allocated while buffering: %.2f Mb\n", float64(memAfter.TotalAlloc-memBefore.TotalAlloc)/1e6)
+
+	count = 0
+	for range second {
+		count++
+	}
+	fmt.Printf("second iterator yielded %d courses\n", count)
+}
+
+func main() {
+	teeChan()
+	fmt.Println()
+	tee()
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"golang.org/x/sync/semaphore"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/reportgen"
+)
+
+const (
+	insertSQL    = `INSERT INTO courses(name, university) VALUES (?, ?)`
+	dropTableSQL = `DROP TABLE IF EXISTS courses`
+
+	createTableSQL = `CREATE TABLE IF NOT EXISTS courses (
+        "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+        "name" TEXT,
+        "university" TEXT
+    );`
+)
+
+var (
+	dataDir    string
+	numCourses int
+	batchSize  int
+	asCSV      bool
+)
+
+// resultsTemplate renders a row as soon as its run is done, which is why it
+// pads the columns itself instead of going through a tabwriter
+var resultsTemplate = template.Must(template.New("results").Parse(`
+{{- define "header"}}{{printf "%-8s %-8s %-12s %s" "limit" "peak" "duration" "courses/sec"}}
+{{end}}
+{{- define "row"}}{{printf "%-8d %-8d %-12s %.0f" .Key .Value.Peak .Value.Duration .Value.Rate}}
+{{end}}`))
+
+// result is the outcome of seeding the database with a single limit
+type result struct {
+	Peak     int64
+	Duration time.Duration
+	Rate     float64
+}
+
+func init() {
+	flag.StringVar(&dataDir, "data-dir", os.TempDir(), "The directory for storing the DB file")
+	flag.IntVar(&numCourses, "num-courses", 200000, "The number of courses to create")
+	flag.IntVar(&batchSize, "batch-size", 1000, "The number of courses inserted by each worker")
+	flag.BoolVar(&asCSV, "csv", false, "Print the results as CSV")
+}
+
+// insertBatch inserts a batch of courses in its own transaction
+func insertBatch(conn *sql.DB, courses []db.Course) error {
+	tx, err := conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	statement, err := tx.Prepare(insertSQL)
+	if err != nil {
+		return fmt.Errorf("failed to prepare SQL statement: %w", err)
+	}
+	defer statement.Close()
+
+	for _, course := range courses {
+		_, err = statement.Exec(course.Name, course.University)
+		if err != nil {
+			return fmt.Errorf("failed to insert course: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// seed inserts the courses in batches, with at most limit batches being
+// inserted at the same time. It returns the highest number of workers that
+// were observed running concurrently.
+func seed(conn *sql.DB, limit int) (int64, error) {
+	var (
+		wg      sync.WaitGroup
+		running atomic.Int64
+		peak    atomic.Int64
+		errOnce sync.Once
+		seedErr error
+	)
+
+	_, err := conn.Exec(dropTableSQL)
+	if err != nil {
+		return 0, fmt.Errorf("failed to drop table: %w", err)
+	}
+
+	_, err = conn.Exec(createTableSQL)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create table: %w", err)
+	}
+
+	// A weighted semaphore with a weight of one per worker behaves exactly
+	// like a buffered channel of capacity limit: Acquire sends, Release
+	// receives
+	sem := semaphore.NewWeighted(int64(limit))
+	ctx := context.Background()
+
+	batch := make([]db.Course, 0, batchSize)
+	flush := func() {
+		courses := batch
+		batch = make([]db.Course, 0, batchSize)
+
+		// Acquire blocks the producer once limit workers are running
+		sem.Acquire(ctx, 1)
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer sem.Release(1)
+
+			n := running.Add(1)
+			defer running.Add(-1)
+
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+
+			err := insertBatch(conn, courses)
+			if err != nil {
+				errOnce.Do(func() { seedErr = err })
+			}
+		}()
+	}
+
+	for course := range db.GenerateCourses(numCourses) {
+		batch = append(batch, course)
+		if len(batch) == batchSize {
+			flush()
+		}
+	}
+
+	if len(batch) > 0 {
+		flush()
+	}
+
+	wg.Wait()
+
+	return peak.Load(), seedErr
+}
+
+func main() {
+	flag.Parse()
+
+	// SQLite only allows a single writer at a time. The busy timeout makes
+	// blocked writers wait for the lock instead of failing immediately.
+	dsn := fmt.Sprintf("file:%s?_busy_timeout=10000&_journal_mode=WAL", filepath.Join(dataDir, "semaphore.db"))
+
+	conn, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		fmt.Printf("failed to open database: %v\n", err)
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	// The database is seeded once per limit as the results are rendered.
+	// The first error stops the runs, and is reported once rendering is done.
+	var seedErr error
+	runs := func(yield func(int, result) bool) {
+		for _, limit := range []int{1, 2, 4, 8, 16, 32} {
+			now := time.Now()
+
+			peak, err := seed(conn, limit)
+			if err != nil {
+				seedErr = err
+				return
+			}
+
+			since := time.Since(now)
+			if !yield(limit, result{peak, since.Round(time.Millisecond), float64(numCourses) / since.Seconds()}) {
+				return
+			}
+		}
+	}
+
+	err = render(runs)
+	if err == nil {
+		err = seedErr
+	}
+	if err != nil {
+		fmt.Printf("failed to seed database: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// render writes the results as a table, or as CSV with -csv
+func render(runs iter.Seq2[int, result]) error {
+	if !asCSV {
+		return reportgen.Text(os.Stdout, resultsTemplate, nil, runs)
+	}
+
+	header := []string{"limit", "peak", "duration_ms", "courses_per_sec"}
+	record := make([]string, len(header))
+
+	return reportgen.CSV(os.Stdout, header, runs, func(limit int, r result) []string {
+		record[0] = strconv.Itoa(limit)
+		record[1] = strconv.FormatInt(r.Peak, 10)
+		record[2] = strconv.FormatInt(r.Duration.Milliseconds(), 10)
+		record[3] = strconv.FormatFloat(r.Rate, 'f', 0, 64)
+		return record
+	})
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/manedurphy/golang-university/concurrency/ratelimit"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+func main() {
+	// Pace the course stream at 20 courses per second
+	now := time.Now()
+	for course := range ratelimit.Throttle(context.Background(), db.GenerateCourses(10), 20) {
+		// Each iteration stands in for a call to an external API which only
+		// accepts a limited number of requests per second
+		fmt.Printf("registered course %d after %d ms\n", course.ID, time.Since(now).Milliseconds())
+	}
+	fmt.Println()
+
+	// A limiter with a burst of 5 lets the first 5 requests through at once
+	limiter := ratelimit.NewLimiter(20, 5)
+
+	now = time.Now()
+	for course := range db.GenerateCourses(10) {
+		err := limiter.Wait(context.Background())
+		if err != nil {
+			fmt.Printf("failed to wait for limiter: %v\n", err)
+			return
+		}
+
+		fmt.Printf("registered course %d after %d ms\n", course.ID, time.Since(now).Milliseconds())
+	}
+	fmt.Println()
+
+	// Waiting is bounded by the context
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Millisecond)
+	defer cancel()
+
+	now = time.Now()
+	for course := range db.GenerateCourses(10) {
+		err := limiter.Wait(ctx)
+		if err != nil {
+			fmt.Printf("gave up after %d ms: %v\n", time.Since(now).Milliseconds(), err)
+			break
+		}
+
+		fmt.Printf("registered course %d after %d ms\n", course.ID, time.Since(now).Milliseconds())
+	}
+}
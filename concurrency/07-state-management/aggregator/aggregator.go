@@ -0,0 +1,139 @@
+package aggregator
+
+import (
+	"maps"
+	"sync"
+	"sync/atomic"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+type (
+	// Aggregator counts the number of courses per university. Add may be
+	// called from any number of goroutines at the same time.
+	Aggregator interface {
+		// Add counts a single course
+		Add(course db.Course)
+
+		// Counts returns a snapshot of the number of courses per university
+		Counts() map[string]int
+
+		// Close releases any resources held by the aggregator
+		Close()
+	}
+
+	mutexAggregator struct {
+		mu     sync.Mutex
+		counts map[string]int
+	}
+
+	atomicAggregator struct {
+		counts map[string]*atomic.Int64
+	}
+
+	channelAggregator struct {
+		adds    chan db.Course
+		queries chan chan map[string]int
+		done    chan struct{}
+	}
+)
+
+// NewMutex creates an Aggregator which guards a map with a mutex
+func NewMutex() Aggregator {
+	return &mutexAggregator{
+		counts: make(map[string]int),
+	}
+}
+
+func (a *mutexAggregator) Add(course db.Course) {
+	a.mu.Lock()
+	a.counts[course.University]++
+	a.mu.Unlock()
+}
+
+func (a *mutexAggregator) Counts() map[string]int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return maps.Clone(a.counts)
+}
+
+func (a *mutexAggregator) Close() {}
+
+// NewAtomic creates an Aggregator with an atomic counter per university. The
+// set of universities must be known up front, because the map itself is never
+// written to after it is created.
+func NewAtomic(universities []string) Aggregator {
+	counts := make(map[string]*atomic.Int64, len(universities))
+	for _, university := range universities {
+		counts[university] = new(atomic.Int64)
+	}
+
+	return &atomicAggregator{
+		counts: counts,
+	}
+}
+
+func (a *atomicAggregator) Add(course db.Course) {
+	counter, ok := a.counts[course.University]
+	if !ok {
+		return
+	}
+
+	counter.Add(1)
+}
+
+func (a *atomicAggregator) Counts() map[string]int {
+	counts := make(map[string]int, len(a.counts))
+	for university, counter := range a.counts {
+		counts[university] = int(counter.Load())
+	}
+
+	return counts
+}
+
+func (a *atomicAggregator) Close() {}
+
+// NewChannel creates an Aggregator whose map is owned by a single goroutine.
+// Other goroutines never touch the map, they send it messages instead.
+func NewChannel() Aggregator {
+	a := &channelAggregator{
+		adds:    make(chan db.Course),
+		queries: make(chan chan map[string]int),
+		done:    make(chan struct{}),
+	}
+
+	go a.run()
+
+	return a
+}
+
+func (a *channelAggregator) run() {
+	counts := make(map[string]int)
+
+	for {
+		select {
+		case course := <-a.adds:
+			counts[course.University]++
+		case reply := <-a.queries:
+			reply <- maps.Clone(counts)
+		case <-a.done:
+			return
+		}
+	}
+}
+
+func (a *channelAggregator) Add(course db.Course) {
+	a.adds <- course
+}
+
+func (a *channelAggregator) Counts() map[string]int {
+	reply := make(chan map[string]int)
+	a.queries <- reply
+
+	return <-reply
+}
+
+func (a *channelAggregator) Close() {
+	close(a.done)
+}
@@ -0,0 +1,83 @@
+package aggregator
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+const numCourses = 10000
+
+func newAggregators() map[string]func() Aggregator {
+	return map[string]func() Aggregator{
+		"mutex":   NewMutex,
+		"atomic":  func() Aggregator { return NewAtomic(db.Universities()) },
+		"channel": NewChannel,
+	}
+}
+
+// TestConcurrentAdd is meant to be run with the race detector enabled, which
+// fails the test if any implementation accesses its state without
+// synchronization
+func TestConcurrentAdd(t *testing.T) {
+	for name, newAggregator := range newAggregators() {
+		t.Run(name, func(t *testing.T) {
+			var wg sync.WaitGroup
+
+			agg := newAggregator()
+			defer agg.Close()
+
+			expected := make(map[string]int)
+			courses := make(chan db.Course)
+
+			for range 8 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					for course := range courses {
+						agg.Add(course)
+
+						// Reading while other goroutines write is part of
+						// what the race detector checks
+						agg.Counts()
+					}
+				}()
+			}
+
+			for course := range db.GenerateCourses(numCourses) {
+				expected[course.University]++
+				courses <- course
+			}
+			close(courses)
+			wg.Wait()
+
+			counts := agg.Counts()
+			for university, count := range expected {
+				if counts[university] != count {
+					t.Errorf("expected %d courses for %s, got %d", count, university, counts[university])
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkAdd(b *testing.B) {
+	universities := db.Universities()
+
+	for name, newAggregator := range newAggregators() {
+		b.Run(name, func(b *testing.B) {
+			agg := newAggregator()
+			defer agg.Close()
+
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					agg.Add(db.Course{University: universities[i%len(universities)]})
+					i++
+				}
+			})
+		})
+	}
+}
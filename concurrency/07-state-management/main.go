@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"os"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/manedurphy/golang-university/concurrency/07-state-management/aggregator"
+	"github.com/manedurphy/golang-university/concurrency/workerpool"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/reportgen"
+)
+
+const (
+	numCourses = 1000000
+	numWorkers = 8
+)
+
+// countsTemplate renders the counts with a column per aggregator, and a
+// footer with the total of each, which shows that every course was counted
+var countsTemplate = template.Must(template.New("counts").Parse(`
+{{- define "header"}}university{{range .Names}}	{{.}}{{end}}
+{{end}}
+{{- define "row"}}{{.Key}}{{range .Value}}	{{.}}{{end}}
+{{end}}
+{{- define "footer"}}total{{range .Data.Totals}}	{{.}}{{end}}
+{{end}}`))
+
+// table is what the header and footer of countsTemplate are rendered with
+type table struct {
+	Names  []string
+	Totals []int
+}
+
+// byUniversity returns an iterator over the count of each university in
+// every one of the snapshots, in the order of the universities
+func byUniversity(snapshots []map[string]int) iter.Seq2[string, []int] {
+	return func(yield func(string, []int) bool) {
+		row := make([]int, len(snapshots))
+		for _, university := range db.Universities() {
+			for i, counts := range snapshots {
+				row[i] = counts[university]
+			}
+
+			if !yield(university, row) {
+				return
+			}
+		}
+	}
+}
+
+func main() {
+	aggregators := []struct {
+		name string
+		agg  aggregator.Aggregator
+	}{
+		{"mutex", aggregator.NewMutex()},
+		{"atomic", aggregator.NewAtomic(db.Universities())},
+		{"channel", aggregator.NewChannel()},
+	}
+
+	var (
+		t         table
+		snapshots []map[string]int
+	)
+
+	for _, a := range aggregators {
+		now := time.Now()
+
+		err := workerpool.Process(context.Background(), db.GenerateCourses(numCourses), numWorkers, func(course db.Course) error {
+			a.agg.Add(course)
+			return nil
+		})
+		if err != nil {
+			fmt.Printf("failed to aggregate courses: %v\n", err)
+			return
+		}
+
+		fmt.Printf("%-8s took %.2f seconds\n", a.name, time.Since(now).Seconds())
+
+		counts := a.agg.Counts()
+
+		total := 0
+		for _, n := range counts {
+			total += n
+		}
+
+		t.Names = append(t.Names, a.name)
+		t.Totals = append(t.Totals, total)
+		snapshots = append(snapshots, counts)
+		a.agg.Close()
+	}
+
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	err := reportgen.Text(w, countsTemplate, t, byUniversity(snapshots))
+	if err != nil {
+		fmt.Printf("failed to render counts: %v\n", err)
+	}
+}
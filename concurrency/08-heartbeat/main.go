@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+var (
+	pulseInterval time.Duration
+	workDuration  time.Duration
+	hangAfter     int
+)
+
+var errProducerDead = errors.New("producer stopped sending heartbeats")
+
+func init() {
+	flag.DurationVar(&pulseInterval, "pulse-interval", 50*time.Millisecond, "How often the producer sends a heartbeat")
+	flag.DurationVar(&workDuration, "work-duration", 200*time.Millisecond, "How long the producer takes to produce each course")
+	flag.IntVar(&hangAfter, "hang-after", 3, "The number of courses produced before the producer hangs (0 never hangs)")
+}
+
+// produceCourses slowly produces courses. It sends a heartbeat on a side
+// channel every pulse interval, whether or not it has a course ready, so the
+// consumer can tell a slow producer from a dead one.
+func produceCourses(ctx context.Context, n int) (<-chan struct{}, <-chan db.Course) {
+	heartbeat := make(chan struct{}, 1)
+	results := make(chan db.Course)
+
+	go func() {
+		defer close(heartbeat)
+		defer close(results)
+
+		pulse := time.NewTicker(pulseInterval)
+		defer pulse.Stop()
+
+		// The heartbeat is sent without blocking. If nobody is listening,
+		// the beat is dropped rather than stalling the producer.
+		sendPulse := func() {
+			select {
+			case heartbeat <- struct{}{}:
+			default:
+			}
+		}
+
+		produced := 0
+		for course := range db.GenerateCourses(n) {
+			if hangAfter > 0 && produced == hangAfter {
+				// Simulate a bug which blocks the producer forever, without
+				// sending any more heartbeats
+				fmt.Println("producer: hanging")
+				<-ctx.Done()
+				return
+			}
+
+			work := time.After(workDuration)
+
+		working:
+			for {
+				select {
+				case <-pulse.C:
+					sendPulse()
+				case <-work:
+					break working
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			for {
+				select {
+				case <-pulse.C:
+					sendPulse()
+					continue
+				case results <- course:
+					produced++
+				case <-ctx.Done():
+					return
+				}
+
+				break
+			}
+		}
+	}()
+
+	return heartbeat, results
+}
+
+func main() {
+	flag.Parse()
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	heartbeat, results := produceCourses(ctx, 5)
+
+	// The watchdog cancels the pipeline if two heartbeats in a row are missed
+	timeout := 2 * pulseInterval
+	watchdog := time.NewTimer(timeout)
+	defer watchdog.Stop()
+
+	now := time.Now()
+	for {
+		select {
+		case _, ok := <-heartbeat:
+			if !ok {
+				fmt.Println("consumer: producer finished")
+				return
+			}
+
+			fmt.Printf("consumer: %4d ms: heartbeat, producer is slow but alive\n", time.Since(now).Milliseconds())
+			watchdog.Reset(timeout)
+		case course, ok := <-results:
+			if !ok {
+				// A nil channel blocks forever, which disables this case
+				results = nil
+				continue
+			}
+
+			fmt.Printf("consumer: %4d ms: received course %d\n", time.Since(now).Milliseconds(), course.ID)
+		case <-watchdog.C:
+			cancel(errProducerDead)
+			fmt.Printf("consumer: %4d ms: cancelled pipeline: %v\n", time.Since(now).Milliseconds(), context.Cause(ctx))
+			return
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/manedurphy/golang-university/concurrency/pubsub"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+const (
+	topic      = "course.created"
+	numCourses = 20
+)
+
+type subscriber struct {
+	name string
+	opts []pubsub.Option[db.Course]
+}
+
+// publish publishes course events every 5 milliseconds to subscribers which
+// each take 20 milliseconds to handle an event
+func publish(subscribers []subscriber) {
+	var wg sync.WaitGroup
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	broker := pubsub.New[db.Course]()
+
+	for _, s := range subscribers {
+		var (
+			dropped  atomic.Int64
+			received []int
+		)
+
+		opts := append(s.opts, pubsub.OnDrop(func(pubsub.Message[db.Course]) {
+			dropped.Add(1)
+		}))
+
+		// Subscribe before publishing, so that no message is missed
+		messages := broker.Subscribe(ctx, topic, opts...)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for msg := range messages {
+				time.Sleep(20 * time.Millisecond)
+				received = append(received, msg.Payload.ID)
+			}
+
+			fmt.Printf("%-12s received %2d, dropped %2d: %v\n", s.name, len(received), dropped.Load(), received)
+		}()
+	}
+
+	now := time.Now()
+	for course := range db.GenerateCourses(numCourses) {
+		broker.Publish(topic, course)
+		time.Sleep(5 * time.Millisecond)
+	}
+	fmt.Printf("published %d courses in %d ms\n", numCourses, time.Since(now).Milliseconds())
+
+	broker.Close()
+	wg.Wait()
+}
+
+func main() {
+	dropNewest := subscriber{name: "drop-newest", opts: []pubsub.Option[db.Course]{
+		pubsub.WithBuffer[db.Course](2),
+		pubsub.WithDropPolicy[db.Course](pubsub.DropNewest),
+	}}
+
+	dropOldest := subscriber{name: "drop-oldest", opts: []pubsub.Option[db.Course]{
+		pubsub.WithBuffer[db.Course](2),
+		pubsub.WithDropPolicy[db.Course](pubsub.DropOldest),
+	}}
+
+	blocking := subscriber{name: "blocking", opts: []pubsub.Option[db.Course]{
+		pubsub.WithBuffer[db.Course](2),
+	}}
+
+	publish([]subscriber{dropNewest, dropOldest})
+	fmt.Println()
+
+	// A single blocking subscriber slows down the publisher, and with it
+	// every other subscriber
+	publish([]subscriber{dropNewest, dropOldest, blocking})
+}
@@ -0,0 +1,153 @@
+package catalog
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+type (
+	// Catalog stores courses by ID. It is safe for concurrent use.
+	Catalog interface {
+		// Add stores a course, replacing any course with the same ID
+		Add(course db.Course)
+
+		// Remove deletes the course with the specified ID
+		Remove(id int)
+
+		// ByUniversity returns an iterator over the courses offered by a
+		// university, in order of their IDs
+		ByUniversity(university string) iter.Seq[db.Course]
+
+		// Close stops the catalog
+		Close()
+	}
+
+	// command is a message in the actor's mailbox. It is a function which is
+	// only ever called by the actor's goroutine, so it can access the state
+	// without any locking.
+	command func(courses map[int]db.Course)
+
+	actor struct {
+		mailbox chan command
+		done    chan struct{}
+	}
+
+	mutexCatalog struct {
+		mu      sync.RWMutex
+		courses map[int]db.Course
+	}
+)
+
+// NewActor creates a Catalog whose state is owned by a single goroutine which
+// processes commands from its mailbox one at a time
+func NewActor() Catalog {
+	a := &actor{
+		mailbox: make(chan command, 16),
+		done:    make(chan struct{}),
+	}
+
+	go a.run()
+
+	return a
+}
+
+func (a *actor) run() {
+	defer close(a.done)
+
+	courses := make(map[int]db.Course)
+
+	for cmd := range a.mailbox {
+		cmd(courses)
+	}
+}
+
+func (a *actor) Add(course db.Course) {
+	a.mailbox <- func(courses map[int]db.Course) {
+		courses[course.ID] = course
+	}
+}
+
+func (a *actor) Remove(id int) {
+	a.mailbox <- func(courses map[int]db.Course) {
+		delete(courses, id)
+	}
+}
+
+// ByUniversity asks the actor for a snapshot of the matching courses when the
+// iteration starts. The consumer's loop body runs outside of the actor, so it
+// can take as long as it likes and even send more commands to the actor.
+func (a *actor) ByUniversity(university string) iter.Seq[db.Course] {
+	return func(yield func(db.Course) bool) {
+		reply := make(chan []db.Course)
+
+		a.mailbox <- func(courses map[int]db.Course) {
+			reply <- matching(courses, university)
+		}
+
+		for _, course := range <-reply {
+			if !yield(course) {
+				return
+			}
+		}
+	}
+}
+
+func (a *actor) Close() {
+	close(a.mailbox)
+	<-a.done
+}
+
+// NewMutex creates a Catalog which guards its state with a read-write mutex
+func NewMutex() Catalog {
+	return &mutexCatalog{
+		courses: make(map[int]db.Course),
+	}
+}
+
+func (c *mutexCatalog) Add(course db.Course) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.courses[course.ID] = course
+}
+
+func (c *mutexCatalog) Remove(id int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.courses, id)
+}
+
+// ByUniversity holds the read lock for the whole iteration, which includes the
+// consumer's loop body. Writers are blocked until the loop ends, and calling
+// Add or Remove from inside the loop deadlocks.
+func (c *mutexCatalog) ByUniversity(university string) iter.Seq[db.Course] {
+	return func(yield func(db.Course) bool) {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+
+		for _, course := range matching(c.courses, university) {
+			if !yield(course) {
+				return
+			}
+		}
+	}
+}
+
+func (c *mutexCatalog) Close() {}
+
+func matching(courses map[int]db.Course, university string) []db.Course {
+	var result []db.Course
+
+	for _, id := range slices.Sorted(maps.Keys(courses)) {
+		if courses[id].University == university {
+			result = append(result, courses[id])
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/manedurphy/golang-university/concurrency/10-actor/catalog"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+func demo(name string, c catalog.Catalog) {
+	defer c.Close()
+
+	for course := range db.GenerateCourses(20) {
+		c.Add(course)
+	}
+
+	// A writer tries to add a course while a slow consumer is iterating
+	writerDone := make(chan time.Duration)
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+
+		now := time.Now()
+		c.Add(db.Course{ID: 100, Name: "Biology-1", University: "SJSU"})
+		writerDone <- time.Since(now)
+	}()
+
+	count := 0
+	for range c.ByUniversity("SJSU") {
+		count++
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	fmt.Printf("%-6s iterated over %d courses, the writer waited %d ms\n", name, count, (<-writerDone).Milliseconds())
+}
+
+func main() {
+	demo("actor", catalog.NewActor())
+	demo("mutex", catalog.NewMutex())
+
+	// Only the actor allows the loop body to modify the catalog it is
+	// iterating over. The mutex version would deadlock on the write lock.
+	actor := catalog.NewActor()
+	defer actor.Close()
+
+	for course := range db.GenerateCourses(20) {
+		actor.Add(course)
+	}
+
+	for course := range actor.ByUniversity("UCB") {
+		actor.Remove(course.ID)
+	}
+
+	count := 0
+	for range actor.ByUniversity("UCB") {
+		count++
+	}
+	fmt.Printf("courses left at UCB after removing them inside the loop: %d\n", count)
+}
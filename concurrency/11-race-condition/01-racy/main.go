@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/manedurphy/golang-university/concurrency/11-race-condition/iterator"
+)
+
+const (
+	numValues  = 1000000
+	numWorkers = 4
+)
+
+func main() {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		seen = make(map[int]int)
+	)
+
+	it := iterator.NewIterator(numValues)
+
+	// Every worker calls Next on the same iterator without any
+	// synchronization. Reading and incrementing idx is not atomic, so two
+	// workers can read the same index before either one increments it.
+	for range numWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				val, ok := it.Next()
+				if !ok {
+					return
+				}
+
+				mu.Lock()
+				seen[val]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	received, duplicates := 0, 0
+	for _, count := range seen {
+		received += count
+		duplicates += count - 1
+	}
+
+	fmt.Printf("received %d values, %d unique, %d duplicates, %d missing\n", received, len(seen), duplicates, numValues-len(seen))
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/manedurphy/golang-university/concurrency/11-race-condition/iterator"
+	"github.com/manedurphy/golang-university/iterators/itertools"
+)
+
+const (
+	numValues  = 1000000
+	numWorkers = 4
+)
+
+func main() {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		seen = make(map[int]int)
+	)
+
+	it := iterator.NewIterator(numValues)
+
+	// SyncPull guards Next with a mutex, so every value is returned to
+	// exactly one worker
+	next := itertools.SyncPull(it.Next)
+
+	for range numWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				val, ok := next()
+				if !ok {
+					return
+				}
+
+				mu.Lock()
+				seen[val]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	received, duplicates := 0, 0
+	for _, count := range seen {
+		received += count
+		duplicates += count - 1
+	}
+
+	fmt.Printf("received %d values, %d unique, %d duplicates, %d missing\n", received, len(seen), duplicates, numValues-len(seen))
+}
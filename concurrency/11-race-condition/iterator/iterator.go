@@ -0,0 +1,39 @@
+package iterator
+
+type (
+	Iterator interface {
+		// Next returns the next sequential value and a boolean which
+		// indicates if the value is valid. Next is not safe to call from
+		// multiple goroutines at the same time.
+		Next() (val int, ok bool)
+	}
+
+	iterator struct {
+		idx  int
+		data []int
+	}
+)
+
+// NewIterator creates a pull iterator over the numbers from 0 to n-1
+func NewIterator(n int) Iterator {
+	data := make([]int, n)
+	for i := range data {
+		data[i] = i
+	}
+
+	return &iterator{
+		idx:  0,
+		data: data,
+	}
+}
+
+func (i *iterator) Next() (int, bool) {
+	if i.idx >= len(i.data) {
+		return 0, false
+	}
+
+	val := i.data[i.idx]
+	i.idx++
+
+	return val, true
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+type (
+	// backend is a simulated replica of the courses database
+	backend struct {
+		name    string
+		courses map[int]db.Course
+	}
+
+	// response is the result of a lookup against a single backend
+	response struct {
+		backend string
+		course  db.Course
+		err     error
+	}
+)
+
+var (
+	numBackends int
+	maxLatency  time.Duration
+	failureRate float64
+	numLookups  int
+)
+
+var errUnavailable = errors.New("backend unavailable")
+
+func init() {
+	flag.IntVar(&numBackends, "num-backends", 3, "The number of replicas to send each lookup to")
+	flag.DurationVar(&maxLatency, "max-latency", 200*time.Millisecond, "The maximum latency of a single backend")
+	flag.Float64Var(&failureRate, "failure-rate", 0.3, "The probability of a backend failing a lookup")
+	flag.IntVar(&numLookups, "num-lookups", 5, "The number of lookups to perform")
+}
+
+// lookup finds a course after a random latency. It gives up as soon as the
+// context is cancelled, which is what lets the losing requests clean up.
+func (b *backend) lookup(ctx context.Context, id int) (db.Course, error) {
+	latency := rand.N(maxLatency)
+
+	timer := time.NewTimer(latency)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		fmt.Printf("  %s: cancelled, would have taken %d ms\n", b.name, latency.Milliseconds())
+		return db.Course{}, ctx.Err()
+	}
+
+	if rand.Float64() < failureRate {
+		return db.Course{}, fmt.Errorf("%s: %w", b.name, errUnavailable)
+	}
+
+	course, ok := b.courses[id]
+	if !ok {
+		return db.Course{}, fmt.Errorf("%s: course %d not found", b.name, id)
+	}
+
+	return course, nil
+}
+
+// first sends the same lookup to every backend and returns the first
+// successful response. It only fails if every backend fails.
+func first(ctx context.Context, backends []*backend, id int) (response, error) {
+	var wg sync.WaitGroup
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	// The channel has room for every response, so the losing requests never
+	// block on a send after we have stopped receiving
+	responses := make(chan response, len(backends))
+
+	// Cancel the losing requests and wait for them to return before we do,
+	// otherwise their goroutines would outlive the lookup
+	defer wg.Wait()
+	defer cancel()
+
+	for _, b := range backends {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			course, err := b.lookup(ctx, id)
+			responses <- response{backend: b.name, course: course, err: err}
+		}()
+	}
+
+	for range backends {
+		select {
+		case res := <-responses:
+			if res.err != nil {
+				fmt.Printf("  %v\n", res.err)
+				continue
+			}
+
+			return res, nil
+		case <-ctx.Done():
+			return response{}, ctx.Err()
+		}
+	}
+
+	return response{}, fmt.Errorf("all %d backends failed", len(backends))
+}
+
+func main() {
+	flag.Parse()
+
+	courses := make(map[int]db.Course)
+	for course := range db.GenerateCourses(numLookups) {
+		courses[course.ID] = course
+	}
+
+	backends := make([]*backend, 0, numBackends)
+	for i := range numBackends {
+		backends = append(backends, &backend{name: fmt.Sprintf("replica-%d", i+1), courses: courses})
+	}
+
+	fmt.Printf("goroutines before: %d\n", runtime.NumGoroutine())
+	for id := 1; id <= numLookups; id++ {
+		fmt.Printf("looking up course %d\n", id)
+
+		now := time.Now()
+		res, err := first(context.Background(), backends, id)
+		if err != nil {
+			fmt.Printf("  failed after %d ms: %v\n", time.Since(now).Milliseconds(), err)
+			continue
+		}
+
+		fmt.Printf("  %s won after %d ms: %+v\n", res.backend, time.Since(now).Milliseconds(), res.course)
+	}
+	fmt.Printf("goroutines after: %d\n", runtime.NumGoroutine())
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/manedurphy/golang-university/concurrency/future"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+var (
+	numLookups int
+	maxLatency time.Duration
+	timeout    time.Duration
+)
+
+var errNotFound = errors.New("course not found")
+
+func init() {
+	flag.IntVar(&numLookups, "num-lookups", 5, "The number of courses to look up")
+	flag.DurationVar(&maxLatency, "max-latency", 200*time.Millisecond, "The maximum latency of a single lookup")
+	flag.DurationVar(&timeout, "timeout", 150*time.Millisecond, "How long to wait for the lookups in the last example")
+}
+
+// lookupCourses starts an asynchronous lookup for every course and returns
+// a Future for each of them straight away. The last lookup always fails.
+func lookupCourses(courses map[int]db.Course) []*future.Future[db.Course] {
+	futures := make([]*future.Future[db.Course], 0, numLookups)
+
+	for id := 1; id <= numLookups; id++ {
+		latency := rand.N(maxLatency)
+
+		futures = append(futures, future.Go(func() (db.Course, error) {
+			time.Sleep(latency)
+
+			course, ok := courses[id]
+			if !ok || id == numLookups {
+				return db.Course{}, fmt.Errorf("course %d after %d ms: %w", id, latency.Milliseconds(), errNotFound)
+			}
+
+			return course, nil
+		}))
+	}
+
+	return futures
+}
+
+func main() {
+	flag.Parse()
+
+	courses := make(map[int]db.Course)
+	for course := range db.GenerateCourses(numLookups) {
+		courses[course.ID] = course
+	}
+
+	// Awaiting the futures in order means a slow lookup holds up every
+	// result behind it, even the ones which are already available
+	fmt.Println("in order:")
+	now := time.Now()
+	for _, f := range lookupCourses(courses) {
+		course, err := f.Await(context.Background())
+		if err != nil {
+			fmt.Printf("  %3d ms: %v\n", time.Since(now).Milliseconds(), err)
+			continue
+		}
+
+		fmt.Printf("  %3d ms: %+v\n", time.Since(now).Milliseconds(), course)
+	}
+	fmt.Println()
+
+	// AsCompleted yields each result as soon as its lookup finishes
+	fmt.Println("as completed:")
+	now = time.Now()
+	for course, err := range future.AsCompleted(context.Background(), lookupCourses(courses)...) {
+		if err != nil {
+			fmt.Printf("  %3d ms: %v\n", time.Since(now).Milliseconds(), err)
+			continue
+		}
+
+		fmt.Printf("  %3d ms: %+v\n", time.Since(now).Milliseconds(), course)
+	}
+	fmt.Println()
+
+	// The context bounds the wait for the whole set of lookups
+	fmt.Printf("as completed with a %s timeout:\n", timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	now = time.Now()
+	for course, err := range future.AsCompleted(ctx, lookupCourses(courses)...) {
+		if err != nil {
+			fmt.Printf("  %3d ms: %v\n", time.Since(now).Milliseconds(), err)
+			continue
+		}
+
+		fmt.Printf("  %3d ms: %+v\n", time.Since(now).Milliseconds(), course)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+var (
+	numWorkers int
+	numCourses int
+)
+
+func init() {
+	flag.IntVar(&numWorkers, "num-workers", 8, "The number of goroutines counting courses")
+	flag.IntVar(&numCourses, "num-courses", 100000, "The number of courses counted by each goroutine")
+}
+
+func main() {
+	var (
+		wg      sync.WaitGroup
+		plain   int64
+		counter atomic.Int64
+		largest atomic.Int64
+	)
+
+	flag.Parse()
+
+	for range numWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for course := range db.GenerateCourses(numCourses) {
+				// A plain increment is a load, an add, and a store. Two
+				// goroutines can load the same value, and one of the
+				// increments is lost.
+				plain++
+
+				// Add performs all three steps as a single operation
+				counter.Add(1)
+
+				// Recording a maximum needs a read-modify-write which Add
+				// cannot express. CompareAndSwap only stores the new value
+				// if nobody else has changed it since we loaded it,
+				// otherwise we load it again and retry.
+				id := int64(course.ID)
+				for {
+					current := largest.Load()
+					if id <= current || largest.CompareAndSwap(current, id) {
+						break
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	expected := int64(numWorkers * numCourses)
+	fmt.Printf("expected: %d\n", expected)
+	fmt.Printf("plain:    %d (%d lost)\n", plain, expected-plain)
+	fmt.Printf("atomic:   %d (%d lost)\n", counter.Load(), expected-counter.Load())
+	fmt.Printf("largest course ID: %d\n", largest.Load())
+}
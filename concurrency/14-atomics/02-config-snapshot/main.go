@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// config is never modified once it has been stored. A new version is built
+// as a copy and swapped in, so a reader always sees a consistent snapshot.
+type config struct {
+	version      int
+	universities []string
+}
+
+var (
+	numCourses     int
+	reloadInterval time.Duration
+	workDuration   time.Duration
+)
+
+var current atomic.Pointer[config]
+
+func init() {
+	flag.IntVar(&numCourses, "num-courses", 12, "The number of courses each consumer reads")
+	flag.DurationVar(&reloadInterval, "reload-interval", 25*time.Millisecond, "How often the config is reloaded")
+	flag.DurationVar(&workDuration, "work-duration", 10*time.Millisecond, "How long a consumer takes to process each course")
+}
+
+// reload swaps in a new config every reload interval. Each version leaves
+// out a different university.
+func reload(stop <-chan struct{}) {
+	ticker := time.NewTicker(reloadInterval)
+	defer ticker.Stop()
+
+	universities := db.Universities()
+	for {
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+
+		version := current.Load().version + 1
+		removed := universities[version%len(universities)]
+
+		// Build a new snapshot instead of modifying the old one, since
+		// consumers may still be reading it
+		next := &config{
+			version: version,
+			universities: slices.DeleteFunc(slices.Clone(universities), func(u string) bool {
+				return u == removed
+			}),
+		}
+
+		current.Store(next)
+		fmt.Printf("reloaded config v%d without %s\n", next.version, removed)
+	}
+}
+
+// allowedCourses yields the courses whose university is allowed by the
+// config. The snapshot is loaded once per course, so a reload takes effect
+// on the next course without the consumer having to take a lock.
+func allowedCourses(courses iter.Seq[db.Course]) iter.Seq2[int, db.Course] {
+	return func(yield func(int, db.Course) bool) {
+		for course := range courses {
+			cfg := current.Load()
+			if !slices.Contains(cfg.universities, course.University) {
+				continue
+			}
+
+			if !yield(cfg.version, course) {
+				return
+			}
+		}
+	}
+}
+
+func main() {
+	var (
+		wg   sync.WaitGroup
+		stop = make(chan struct{})
+	)
+
+	flag.Parse()
+
+	current.Store(&config{universities: db.Universities()})
+
+	go reload(stop)
+
+	for consumer := range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for version, course := range allowedCourses(db.GenerateCourses(numCourses)) {
+				fmt.Printf("consumer %d: config v%d: course %d from %s\n", consumer+1, version, course.ID, course.University)
+				time.Sleep(workDuration)
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(stop)
+
+	fmt.Printf("final config: v%d %v\n", current.Load().version, current.Load().universities)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+var (
+	dataDir    string
+	numCourses int
+	numWorkers int
+)
+
+var (
+	dbOnce    sync.Once
+	coursesDB db.CoursesDB
+	dbErr     error
+)
+
+func init() {
+	flag.StringVar(&dataDir, "data-dir", os.TempDir(), "The directory for storing the DB file")
+	flag.IntVar(&numCourses, "num-courses", 1000, "The number of courses to create")
+	flag.IntVar(&numWorkers, "num-workers", 4, "The number of goroutines using the database")
+}
+
+// getDB opens and seeds the shared database the first time it is called.
+// Every other caller blocks until the first call has finished, and then
+// receives the same handle, or the same error.
+func getDB() (db.CoursesDB, error) {
+	dbOnce.Do(func() {
+		fmt.Println("opening database")
+
+		coursesDB, dbErr = db.New(dataDir)
+		if dbErr != nil {
+			dbErr = fmt.Errorf("failed to create database: %w", dbErr)
+			return
+		}
+
+		dbErr = coursesDB.Seed(numCourses)
+		if dbErr != nil {
+			dbErr = fmt.Errorf("failed to seed database: %w", dbErr)
+		}
+	})
+
+	return coursesDB, dbErr
+}
+
+// countCourses counts the courses once. sync.OnceValue wraps the function
+// and remembers its result, so there is no need for package-level variables
+// like the ones used by getDB.
+var countCourses = sync.OnceValue(func() int {
+	fmt.Println("counting courses")
+
+	coursesDB, err := getDB()
+	if err != nil {
+		return 0
+	}
+
+	count := 0
+	for _, err := range coursesDB.GetCourses() {
+		if err == nil {
+			count++
+		}
+	}
+
+	return count
+})
+
+func main() {
+	var wg sync.WaitGroup
+
+	flag.Parse()
+
+	now := time.Now()
+	for worker := range numWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			coursesDB, err := getDB()
+			if err != nil {
+				fmt.Printf("worker %d: %v\n", worker+1, err)
+				return
+			}
+
+			fmt.Printf("worker %d: received handle %p after %d ms, %d courses\n", worker+1, coursesDB, time.Since(now).Milliseconds(), countCourses())
+		}()
+	}
+
+	wg.Wait()
+
+	coursesDB, err := getDB()
+	if err != nil {
+		os.Exit(1)
+	}
+	defer coursesDB.Close()
+}
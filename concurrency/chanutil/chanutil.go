@@ -0,0 +1,68 @@
+package chanutil
+
+import "context"
+
+// OrDone returns a channel which receives every value sent on in until either
+// in is closed or ctx is cancelled. This allows a consumer to range over a
+// channel without having to select on the context in its loop body. The
+// returned channel is always closed, so the goroutine started by OrDone
+// never outlives the context.
+func OrDone[T any](ctx context.Context, in <-chan T) <-chan T {
+	out := make(chan T)
+
+	go func() {
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case val, ok := <-in:
+				if !ok {
+					return
+				}
+
+				select {
+				case out <- val:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// TeeChan returns two channels which each receive every value sent on in.
+// A value is only sent to both channels before the next value is read from
+// in, so a consumer which stalls will also stall the other consumer. Both
+// channels are closed once in is closed or ctx is cancelled.
+func TeeChan[T any](ctx context.Context, in <-chan T) (<-chan T, <-chan T) {
+	out1 := make(chan T)
+	out2 := make(chan T)
+
+	go func() {
+		defer close(out1)
+		defer close(out2)
+
+		for val := range OrDone(ctx, in) {
+			// Shadow the channels so that each one can be set to nil once it
+			// has received the value, which disables its case in the select
+			out1, out2 := out1, out2
+
+			for range 2 {
+				select {
+				case out1 <- val:
+					out1 = nil
+				case out2 <- val:
+					out2 = nil
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out1, out2
+}
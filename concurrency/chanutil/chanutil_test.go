@@ -0,0 +1,132 @@
+package chanutil
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/testutil"
+)
+
+// send returns a channel which receives values, and is closed after them
+func send(values ...int) <-chan int {
+	in := make(chan int, len(values))
+	for _, v := range values {
+		in <- v
+	}
+	close(in)
+
+	return in
+}
+
+// drain receives from c until it is closed, and returns what it received
+func drain(c <-chan int) []int {
+	var values []int
+	for v := range c {
+		values = append(values, v)
+	}
+
+	return values
+}
+
+// requireNothing fails the test if c receives a value, or is closed, within a
+// short wait
+func requireNothing(t *testing.T, c <-chan int) {
+	t.Helper()
+
+	select {
+	case v, ok := <-c:
+		t.Fatalf("expected the channel to block, got %d (open: %t)", v, ok)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestOrDone(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	got := drain(OrDone(context.Background(), send(1, 2, 3)))
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("expected [1 2 3], got %v", got)
+	}
+}
+
+func TestOrDoneCancel(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// in is never closed, so only the cancellation ends the loop
+	in := make(chan int)
+	out := OrDone(ctx, in)
+
+	cancel()
+	if _, ok := <-out; ok {
+		t.Fatal("expected the channel to be closed once ctx is cancelled")
+	}
+}
+
+func TestTeeChan(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	out1, out2 := TeeChan(context.Background(), send(1, 2, 3))
+
+	// Each consumer runs on its own, and both channels are closed once in
+	// is
+	got2 := make(chan []int)
+	go func() { got2 <- drain(out2) }()
+
+	if got := drain(out1); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("expected out1 to receive [1 2 3], got %v", got)
+	}
+	if got := <-got2; !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("expected out2 to receive [1 2 3], got %v", got)
+	}
+}
+
+func TestTeeChanStall(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	out1, out2 := TeeChan(context.Background(), send(1, 2))
+
+	if v := <-out1; v != 1 {
+		t.Fatalf("expected 1, got %d", v)
+	}
+
+	// out2 has not received 1 yet, so 2 is not read from in
+	requireNothing(t, out1)
+
+	if v := <-out2; v != 1 {
+		t.Fatalf("expected 1, got %d", v)
+	}
+
+	// Once the stalled consumer catches up, both receive the rest
+	if v := <-out1; v != 2 {
+		t.Fatalf("expected 2, got %d", v)
+	}
+	if v := <-out2; v != 2 {
+		t.Fatalf("expected 2, got %d", v)
+	}
+
+	drain(out1)
+	drain(out2)
+}
+
+func TestTeeChanCancel(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out1, out2 := TeeChan(ctx, send(1, 2, 3))
+
+	<-out1
+
+	// out2 never receives, so out1 is stuck until ctx is cancelled, which
+	// closes both channels
+	requireNothing(t, out1)
+	cancel()
+
+	if got := drain(out1); len(got) != 0 {
+		t.Fatalf("expected out1 to be closed, got %v", got)
+	}
+	drain(out2)
+}
@@ -0,0 +1,107 @@
+package future
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// Future holds a value which will be available at some point in the future.
+// A Future is resolved exactly once, and may be awaited any number of times
+// from any number of goroutines.
+type Future[T any] struct {
+	once sync.Once
+	done chan struct{}
+	val  T
+	err  error
+}
+
+// New returns an unresolved Future
+func New[T any]() *Future[T] {
+	return &Future[T]{done: make(chan struct{})}
+}
+
+// Go runs fn in a new goroutine and returns a Future which is resolved with
+// its result
+func Go[T any](fn func() (T, error)) *Future[T] {
+	f := New[T]()
+
+	go func() {
+		f.Resolve(fn())
+	}()
+
+	return f
+}
+
+// Resolve sets the value and error of the Future and wakes up every
+// goroutine waiting in Await. Only the first call has any effect.
+func (f *Future[T]) Resolve(val T, err error) {
+	f.once.Do(func() {
+		f.val = val
+		f.err = err
+		close(f.done)
+	})
+}
+
+// Done returns a channel which is closed once the Future is resolved
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Await blocks until the Future is resolved or ctx is cancelled. Cancelling
+// ctx only stops the wait, it does not affect the Future itself.
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		// Closing the channel happens before the receive completes, so the
+		// fields written by Resolve are visible here
+		return f.val, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// AsCompleted yields the result of every Future in the order in which they
+// are resolved, rather than the order in which they were passed. It stops
+// once every Future has been yielded or ctx is cancelled, in which case the
+// context's error is yielded last.
+func AsCompleted[T any](ctx context.Context, futures ...*Future[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var wg sync.WaitGroup
+
+		stop := make(chan struct{})
+		// Every waiter can send without blocking, so none of them are left
+		// behind if the consumer stops early
+		resolved := make(chan *Future[T], len(futures))
+
+		defer wg.Wait()
+		defer close(stop)
+
+		for _, f := range futures {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				select {
+				case <-f.done:
+					resolved <- f
+				case <-stop:
+				}
+			}()
+		}
+
+		for range futures {
+			select {
+			case f := <-resolved:
+				if !yield(f.val, f.err) {
+					return
+				}
+			case <-ctx.Done():
+				var zero T
+				yield(zero, ctx.Err())
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package future
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/testutil"
+)
+
+type result struct {
+	val int
+	err error
+}
+
+// collect ranges over AsCompleted in a new goroutine, sending every result
+// it yields, so that the test can resolve the futures one at a time
+func collect(ctx context.Context, futures ...*Future[int]) <-chan result {
+	results := make(chan result)
+
+	go func() {
+		defer close(results)
+
+		for val, err := range AsCompleted(ctx, futures...) {
+			results <- result{val, err}
+		}
+	}()
+
+	return results
+}
+
+func TestResolveOnce(t *testing.T) {
+	f := New[int]()
+
+	f.Resolve(1, nil)
+	f.Resolve(2, errors.New("resolved twice"))
+
+	val, err := f.Await(context.Background())
+	if val != 1 || err != nil {
+		t.Fatalf("expected the first resolution 1, <nil>, got %d, %v", val, err)
+	}
+}
+
+func TestAwaitCancel(t *testing.T) {
+	f := New[int]()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := f.Await(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+
+	// Cancelling the wait leaves the future to be resolved later
+	f.Resolve(1, nil)
+
+	val, err := f.Await(context.Background())
+	if val != 1 || err != nil {
+		t.Fatalf("expected 1, <nil>, got %d, %v", val, err)
+	}
+}
+
+func TestAsCompleted(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	futures := []*Future[int]{New[int](), New[int](), New[int]()}
+	results := collect(context.Background(), futures...)
+
+	failed := errors.New("failed")
+	for _, i := range []int{2, 0, 1} {
+		var err error
+		if i == 0 {
+			err = failed
+		}
+
+		futures[i].Resolve(i, err)
+
+		got := <-results
+		if got.val != i || got.err != err {
+			t.Fatalf("expected the future resolved next, %d, %v, got %d, %v", i, err, got.val, got.err)
+		}
+	}
+
+	if _, ok := <-results; ok {
+		t.Fatal("expected the results to stop once every future was yielded")
+	}
+}
+
+func TestAsCompletedCancel(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	futures := []*Future[int]{New[int](), New[int]()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := collect(ctx, futures...)
+
+	futures[1].Resolve(1, nil)
+	if got := <-results; got.val != 1 || got.err != nil {
+		t.Fatalf("expected 1, <nil>, got %d, %v", got.val, got.err)
+	}
+
+	cancel()
+	if got := <-results; !errors.Is(got.err, context.Canceled) {
+		t.Fatalf("expected %v to be yielded last, got %d, %v", context.Canceled, got.val, got.err)
+	}
+
+	if _, ok := <-results; ok {
+		t.Fatal("expected the results to stop after the context's error")
+	}
+}
+
+func TestAsCompletedBreak(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	futures := []*Future[int]{New[int](), New[int](), New[int]()}
+	futures[0].Resolve(0, nil)
+
+	for val := range AsCompleted(context.Background(), futures...) {
+		if val != 0 {
+			t.Fatalf("expected the resolved future, got %d", val)
+		}
+
+		// The waiters of the futures which are never resolved must not
+		// be left behind
+		break
+	}
+}
@@ -0,0 +1,231 @@
+package pubsub
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+type (
+	// DropPolicy decides what happens when a message is published to a
+	// subscriber whose buffer is full
+	DropPolicy int
+
+	// Message is a payload published to a topic
+	Message[T any] struct {
+		Topic   string
+		Payload T
+	}
+
+	// Option configures a single subscription
+	Option[T any] func(*subscription[T])
+
+	// Broker delivers messages published to a topic to every subscriber of
+	// that topic. It is safe for concurrent use.
+	Broker[T any] struct {
+		mu          sync.RWMutex
+		subscribers map[string]map[*subscription[T]]struct{}
+		closed      chan struct{}
+		closeOnce   sync.Once
+	}
+
+	subscription[T any] struct {
+		ch       chan Message[T]
+		done     chan struct{}
+		doneOnce sync.Once
+		policy   DropPolicy
+		onDrop   func(Message[T])
+	}
+)
+
+const (
+	// Block makes Publish wait until the subscriber has room for the message
+	Block DropPolicy = iota
+
+	// DropNewest discards the message being published
+	DropNewest
+
+	// DropOldest discards the oldest message in the subscriber's buffer to
+	// make room for the message being published
+	DropOldest
+)
+
+// WithBuffer sets the number of messages buffered for the subscriber. The
+// default is an unbuffered subscription.
+func WithBuffer[T any](size int) Option[T] {
+	return func(s *subscription[T]) {
+		s.ch = make(chan Message[T], size)
+	}
+}
+
+// WithDropPolicy sets what happens when the subscriber's buffer is full. The
+// default is Block.
+func WithDropPolicy[T any](policy DropPolicy) Option[T] {
+	return func(s *subscription[T]) {
+		s.policy = policy
+	}
+}
+
+// OnDrop registers a function which is called with every message dropped for
+// the subscriber
+func OnDrop[T any](fn func(Message[T])) Option[T] {
+	return func(s *subscription[T]) {
+		s.onDrop = fn
+	}
+}
+
+// New creates a new Broker
+func New[T any]() *Broker[T] {
+	return &Broker[T]{
+		subscribers: make(map[string]map[*subscription[T]]struct{}),
+		closed:      make(chan struct{}),
+	}
+}
+
+// Subscribe registers a subscriber to topic and returns an iterator over the
+// messages published to it. Messages published after Subscribe returns are
+// buffered for the subscriber even before the iterator is ranged over. The
+// iteration ends, and the subscriber is removed, when ctx is cancelled, when
+// the consumer stops early, or when the broker is closed and the buffer has
+// been drained. The returned iterator is single-use.
+//
+// The subscriber is removed once ctx is cancelled even if the iterator is
+// never ranged over, so that it does not hold up Publish forever. A
+// subscription which might not be ranged over must be given a context which
+// is cancelled eventually.
+func (b *Broker[T]) Subscribe(ctx context.Context, topic string, opts ...Option[T]) iter.Seq[Message[T]] {
+	sub := &subscription[T]{
+		ch:   make(chan Message[T]),
+		done: make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(sub)
+	}
+
+	b.mu.Lock()
+	if b.subscribers[topic] == nil {
+		b.subscribers[topic] = make(map[*subscription[T]]struct{})
+	}
+	b.subscribers[topic][sub] = struct{}{}
+	b.mu.Unlock()
+
+	stop := context.AfterFunc(ctx, func() {
+		b.unsubscribe(topic, sub)
+	})
+
+	return func(yield func(Message[T]) bool) {
+		defer stop()
+		defer b.unsubscribe(topic, sub)
+
+		for {
+			select {
+			case msg := <-sub.ch:
+				if !yield(msg) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			case <-b.closed:
+				// Deliver whatever is left in the buffer before ending
+				for {
+					select {
+					case msg := <-sub.ch:
+						if !yield(msg) {
+							return
+						}
+					default:
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// Publish delivers a message to every current subscriber of topic, according
+// to each subscriber's drop policy
+func (b *Broker[T]) Publish(topic string, payload T) {
+	msg := Message[T]{Topic: topic, Payload: payload}
+
+	// Deliver to a snapshot of the subscribers, so that a blocked delivery
+	// does not hold the lock that unsubscribing needs
+	b.mu.RLock()
+	subs := make([]*subscription[T], 0, len(b.subscribers[topic]))
+	for sub := range b.subscribers[topic] {
+		subs = append(subs, sub)
+	}
+	b.mu.RUnlock()
+
+	for _, sub := range subs {
+		sub.deliver(msg, b.closed)
+	}
+}
+
+// Close stops the broker. Subscribers receive the messages that are already
+// buffered for them, and their iterators end.
+func (b *Broker[T]) Close() {
+	b.closeOnce.Do(func() {
+		close(b.closed)
+	})
+}
+
+// unsubscribe removes sub from the subscribers of topic, and wakes up a
+// Publish blocked on it. It may be called more than once.
+func (b *Broker[T]) unsubscribe(topic string, sub *subscription[T]) {
+	b.mu.Lock()
+	delete(b.subscribers[topic], sub)
+	if len(b.subscribers[topic]) == 0 {
+		delete(b.subscribers, topic)
+	}
+	b.mu.Unlock()
+
+	sub.doneOnce.Do(func() {
+		close(sub.done)
+	})
+}
+
+func (s *subscription[T]) deliver(msg Message[T], closed <-chan struct{}) {
+	switch s.policy {
+	case DropNewest:
+		select {
+		case s.ch <- msg:
+		default:
+			s.drop(msg)
+		}
+	case DropOldest:
+		for {
+			select {
+			case s.ch <- msg:
+				return
+			default:
+			}
+
+			// An unbuffered subscription has no oldest message to drop
+			if cap(s.ch) == 0 {
+				s.drop(msg)
+				return
+			}
+
+			// The consumer may have emptied the buffer in the meantime, in
+			// which case there is nothing to drop and we try again
+			select {
+			case oldest := <-s.ch:
+				s.drop(oldest)
+			default:
+			}
+		}
+	default:
+		select {
+		case s.ch <- msg:
+		case <-s.done:
+		case <-closed:
+		}
+	}
+}
+
+func (s *subscription[T]) drop(msg Message[T]) {
+	if s.onDrop != nil {
+		s.onDrop(msg)
+	}
+}
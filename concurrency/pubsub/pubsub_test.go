@@ -0,0 +1,220 @@
+package pubsub
+
+import (
+	"context"
+	"iter"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/testutil"
+)
+
+// subscribers returns the number of subscribers of topic
+func subscribers[T any](b *Broker[T], topic string) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return len(b.subscribers[topic])
+}
+
+// payloads returns the payloads of msgs
+func payloads(msgs []Message[int]) []int {
+	var p []int
+	for _, msg := range msgs {
+		p = append(p, msg.Payload)
+	}
+
+	return p
+}
+
+// publishes returns a channel which is closed once Publish returns
+func publishes(b *Broker[int], topic string, payload int) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		b.Publish(topic, payload)
+	}()
+
+	return done
+}
+
+func TestSubscribeReceives(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	b := New[int]()
+	ctx := context.Background()
+
+	courses := b.Subscribe(ctx, "courses", WithBuffer[int](3))
+	other := b.Subscribe(ctx, "grades", WithBuffer[int](3))
+
+	for i := range 3 {
+		b.Publish("courses", i+1)
+	}
+	b.Close()
+
+	var got []Message[int]
+	for msg := range courses {
+		got = append(got, msg)
+	}
+
+	// Close ends the iteration once the buffer is drained
+	if !slices.Equal(payloads(got), []int{1, 2, 3}) || got[0].Topic != "courses" {
+		t.Fatalf("expected the messages of courses, got %v", got)
+	}
+
+	for msg := range other {
+		t.Fatalf("expected no message for another topic, got %v", msg)
+	}
+}
+
+func TestBreakUnsubscribes(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	b := New[int]()
+	defer b.Close()
+
+	sub := b.Subscribe(context.Background(), "courses", WithBuffer[int](1))
+	b.Publish("courses", 1)
+
+	for range sub {
+		break
+	}
+
+	if n := subscribers(b, "courses"); n != 0 {
+		t.Fatalf("expected the subscriber to be removed, got %d subscribers", n)
+	}
+}
+
+// TestCancelUnrangedSubscription subscribes without ever ranging over the
+// subscription, which would block Publish forever under the Block policy if
+// cancelling ctx did not remove the subscriber
+func TestCancelUnrangedSubscription(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	b := New[int]()
+	defer b.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.Subscribe(ctx, "courses")
+
+	done := publishes(b, "courses", 1)
+
+	select {
+	case <-done:
+		t.Fatal("expected Publish to block on the subscriber")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+	<-done
+
+	if n := subscribers(b, "courses"); n != 0 {
+		t.Fatalf("expected the subscriber to be removed, got %d subscribers", n)
+	}
+}
+
+func TestBlock(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	b := New[int]()
+	defer b.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := b.Subscribe(ctx, "courses", WithBuffer[int](1))
+
+	// The first message fills the buffer, and the second waits for room
+	b.Publish("courses", 1)
+	done := publishes(b, "courses", 2)
+
+	select {
+	case <-done:
+		t.Fatal("expected Publish to block while the buffer is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	next, stop := pull(sub, cancel)
+	defer stop()
+
+	if msg := next(); msg.Payload != 1 {
+		t.Fatalf("expected 1, got %d", msg.Payload)
+	}
+	<-done
+
+	if msg := next(); msg.Payload != 2 {
+		t.Fatalf("expected 2, got %d", msg.Payload)
+	}
+}
+
+func TestDropNewest(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	b := New[int]()
+
+	var dropped []int
+	sub := b.Subscribe(context.Background(), "courses",
+		WithBuffer[int](2),
+		WithDropPolicy[int](DropNewest),
+		OnDrop(func(msg Message[int]) { dropped = append(dropped, msg.Payload) }))
+
+	for i := range 4 {
+		b.Publish("courses", i+1)
+	}
+	b.Close()
+
+	var got []Message[int]
+	for msg := range sub {
+		got = append(got, msg)
+	}
+
+	if !slices.Equal(payloads(got), []int{1, 2}) || !slices.Equal(dropped, []int{3, 4}) {
+		t.Fatalf("expected to receive [1 2] and drop [3 4], got %v and %v", payloads(got), dropped)
+	}
+}
+
+func TestDropOldest(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	b := New[int]()
+
+	var dropped []int
+	sub := b.Subscribe(context.Background(), "courses",
+		WithBuffer[int](2),
+		WithDropPolicy[int](DropOldest),
+		OnDrop(func(msg Message[int]) { dropped = append(dropped, msg.Payload) }))
+
+	for i := range 4 {
+		b.Publish("courses", i+1)
+	}
+	b.Close()
+
+	var got []Message[int]
+	for msg := range sub {
+		got = append(got, msg)
+	}
+
+	if !slices.Equal(payloads(got), []int{3, 4}) || !slices.Equal(dropped, []int{1, 2}) {
+		t.Fatalf("expected to receive [3 4] and drop [1 2], got %v and %v", payloads(got), dropped)
+	}
+}
+
+// pull returns a function which returns the next message of seq, ranged in a
+// goroutine of its own so that Publish can run in the test's, and a function
+// which stops the goroutine by calling cancel
+func pull(seq iter.Seq[Message[int]], cancel context.CancelFunc) (func() Message[int], func()) {
+	msgs := make(chan Message[int])
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for msg := range seq {
+			msgs <- msg
+		}
+	}()
+
+	return func() Message[int] { return <-msgs }, func() {
+		cancel()
+		<-done
+	}
+}
@@ -0,0 +1,145 @@
+package ratelimit
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/clock"
+)
+
+type (
+	// Limiter is a token bucket which is refilled at a fixed rate. Each call
+	// to Wait takes a single token from the bucket, and blocks until a token
+	// is available when the bucket is empty.
+	Limiter struct {
+		mu     sync.Mutex
+		clock  clock.Clock
+		rate   float64
+		burst  float64
+		tokens float64
+		last   time.Time
+	}
+)
+
+// NewLimiter creates a Limiter which allows rate events per second, with
+// bursts of up to burst events. The bucket starts out full. It panics if rate
+// is not positive.
+func NewLimiter(rate float64, burst int) *Limiter {
+	return NewLimiterOn(clock.Real, rate, burst)
+}
+
+// NewLimiterOn is like NewLimiter, but the bucket is refilled, and Wait
+// waits, by the time of c
+func NewLimiterOn(c clock.Clock, rate float64, burst int) *Limiter {
+	// The negated comparison also rejects NaN
+	if !(rate > 0) {
+		panic("ratelimit: NewLimiter rate must be positive")
+	}
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &Limiter{
+		clock:  c,
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   c.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is cancelled, in which case
+// the context's error is returned and the token is given back
+func (l *Limiter) Wait(ctx context.Context) error {
+	delay := l.reserve()
+	if delay == 0 {
+		return nil
+	}
+
+	// A timer which is not stopped is garbage collected as of Go 1.23, so
+	// returning early on ctx does not leak the one of After
+	select {
+	case <-l.clock.After(delay):
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+
+		return ctx.Err()
+	}
+}
+
+// Allow takes a token from the bucket if one is available, and reports
+// whether it did. Unlike Wait, it never blocks, for callers which would
+// rather turn an event away than delay it.
+func (l *Limiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill()
+	if l.tokens < 1 {
+		return false
+	}
+
+	l.tokens--
+	return true
+}
+
+// reserve takes a token from the bucket and returns how long the caller has
+// to wait before the token can be used. The bucket may go negative, which
+// queues callers behind each other in the order they called Wait.
+func (l *Limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill()
+	l.tokens--
+
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// refill adds the tokens of the time which passed since the last refill, up
+// to the size of the bucket. l.mu must be held.
+func (l *Limiter) refill() {
+	now := l.clock.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+}
+
+// Throttle returns an iterator which yields the values of seq at no more than
+// rate values per second. The iteration stops when ctx is cancelled while it
+// waits to yield a value. It panics if rate is not positive.
+func Throttle[T any](ctx context.Context, seq iter.Seq[T], rate float64) iter.Seq[T] {
+	return ThrottleOn(ctx, clock.Real, seq, rate)
+}
+
+// ThrottleOn is like Throttle, but paces the values by the time of c
+func ThrottleOn[T any](ctx context.Context, c clock.Clock, seq iter.Seq[T], rate float64) iter.Seq[T] {
+	// The rate is checked now, like NewLimiter does, rather than when the
+	// iteration starts. Each iteration still gets a limiter of its own.
+	if !(rate > 0) {
+		panic("ratelimit: Throttle rate must be positive")
+	}
+
+	return func(yield func(T) bool) {
+		limiter := NewLimiterOn(c, rate, 1)
+
+		for val := range seq {
+			if limiter.Wait(ctx) != nil {
+				return
+			}
+
+			if !yield(val) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package ratelimit
+
+import (
+	"context"
+	"iter"
+	"math"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/clock"
+	"github.com/manedurphy/golang-university/internal/testutil"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestThrottle(t *testing.T) {
+	fake := clock.NewFake(start)
+
+	// The consumer runs in its own goroutine, since it blocks until the
+	// test advances the clock
+	times := make(chan time.Duration)
+	go func() {
+		defer close(times)
+
+		for range ThrottleOn(context.Background(), fake, slices.Values([]int{1, 2, 3}), 10) {
+			times <- fake.Now().Sub(start)
+		}
+	}()
+
+	if got := <-times; got != 0 {
+		t.Fatalf("expected the first value at once, got it after %v", got)
+	}
+
+	for _, expected := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond} {
+		fake.BlockUntil(1)
+		fake.Advance(100 * time.Millisecond)
+
+		if got := <-times; got != expected {
+			t.Fatalf("expected a value after %v, got it after %v", expected, got)
+		}
+	}
+
+	if _, ok := <-times; ok {
+		t.Fatal("expected the values to run out")
+	}
+}
+
+func TestThrottleCancel(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	fake := clock.NewFake(start)
+	seq, stopped := tracked(3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	values := make(chan int)
+	go func() {
+		defer close(values)
+
+		for val := range ThrottleOn(ctx, fake, seq, 10) {
+			values <- val
+		}
+	}()
+
+	if got := <-values; got != 1 {
+		t.Fatalf("expected 1, got %d", got)
+	}
+
+	// The second value waits for the clock, which is never advanced
+	fake.BlockUntil(1)
+	cancel()
+
+	if val, ok := <-values; ok {
+		t.Fatalf("expected the iteration to stop, got %d", val)
+	}
+
+	if !stopped() {
+		t.Fatal("expected the iteration of seq to stop")
+	}
+}
+
+func TestNewLimiterRate(t *testing.T) {
+	for _, rate := range []float64{0, -1, math.NaN()} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected a rate of %v to panic", rate)
+				}
+			}()
+
+			NewLimiter(rate, 1)
+		}()
+	}
+}
+
+func TestThrottleRate(t *testing.T) {
+	for _, rate := range []float64{0, -1, math.NaN()} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected a rate of %v to panic before the iteration starts", rate)
+				}
+			}()
+
+			Throttle(context.Background(), slices.Values([]int{1}), rate)
+		}()
+	}
+}
+
+func TestLimiterBurst(t *testing.T) {
+	fake := clock.NewFake(start)
+	limiter := NewLimiterOn(fake, 10, 3)
+
+	// The bucket starts out full, so a burst goes through without waiting
+	for i := range 3 {
+		if delay := limiter.reserve(); delay != 0 {
+			t.Fatalf("expected token %d at once, got a delay of %v", i, delay)
+		}
+	}
+
+	if delay := limiter.reserve(); delay != 100*time.Millisecond {
+		t.Fatalf("expected a delay of 100ms once the bucket is empty, got %v", delay)
+	}
+
+	// A second later the bucket is full again, and the debt is paid off
+	fake.Advance(time.Second)
+	for i := range 2 {
+		if delay := limiter.reserve(); delay != 0 {
+			t.Fatalf("expected token %d at once, got a delay of %v", i, delay)
+		}
+	}
+}
+
+func TestLimiterWaitCancel(t *testing.T) {
+	fake := clock.NewFake(start)
+	limiter := NewLimiterOn(fake, 1, 1)
+
+	_ = limiter.Wait(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error)
+	go func() { errs <- limiter.Wait(ctx) }()
+
+	fake.BlockUntil(1)
+	cancel()
+
+	if err := <-errs; err != context.Canceled {
+		t.Fatalf("expected the context's error, got %v", err)
+	}
+
+	// The token of the cancelled call is given back
+	if delay := limiter.reserve(); delay != time.Second {
+		t.Fatalf("expected a delay of 1s, got %v", delay)
+	}
+}
+
+func TestLimiterAllow(t *testing.T) {
+	fake := clock.NewFake(start)
+	limiter := NewLimiterOn(fake, 10, 2)
+
+	for i, expected := range []bool{true, true, false, false} {
+		if got := limiter.Allow(); got != expected {
+			t.Fatalf("expected Allow %d to return %t, got %t", i, expected, got)
+		}
+	}
+
+	// A refused call takes no token, so a single refill allows a single call
+	fake.Advance(100 * time.Millisecond)
+	if !limiter.Allow() || limiter.Allow() {
+		t.Fatal("expected a single call to be allowed after 100ms")
+	}
+}
+
+// tracked returns an iterator over 1 to n, and a function which reports
+// whether its iteration has stopped
+func tracked(n int) (iter.Seq[int], func() bool) {
+	var stopped atomic.Bool
+
+	return func(yield func(int) bool) {
+		defer stopped.Store(true)
+
+		for i := 1; i <= n; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}, stopped.Load
+}
@@ -0,0 +1,21 @@
+package workerpool
+
+import (
+	"context"
+	"iter"
+
+	"github.com/manedurphy/golang-university/iterators/itertools"
+)
+
+// Process ranges over seq and hands each element to one of the specified
+// number of workers, which call fn on it. The first error returned by fn,
+// or the cancellation of ctx, stops the iterator and is returned once every
+// worker has exited. Elements are not processed in any particular order.
+//
+// Process is itertools.ForEachConcurrent for a fn which does not need the
+// context the workers are cancelled with.
+func Process[T any](ctx context.Context, seq iter.Seq[T], workers int, fn func(T) error) error {
+	return itertools.ForEachConcurrent(ctx, seq, workers, func(_ context.Context, val T) error {
+		return fn(val)
+	})
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+func generateNumbers(ctx context.Context) <-chan int {
+	ch := make(chan int)
+
+	go func() {
+		defer func() {
+			fmt.Println("closing channel")
+			close(ch)
+		}()
+
+		for i := 20; ; i++ {
+			select {
+			case ch <- i:
+			case <-ctx.Done():
+				fmt.Printf("generator stopped: %v\n", ctx.Err())
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// receiveUntil receives numbers from ch until it receives last, and then
+// calls cancel. It returns once the generator has closed ch, which it only
+// does when its goroutine exits, so nothing is left running.
+func receiveUntil(ch <-chan int, last int, cancel context.CancelFunc) []int {
+	var received []int
+	for num := range ch {
+		fmt.Printf("number received in range-loop: %d\n", num)
+		received = append(received, num)
+
+		if num == last {
+			cancel()
+			break
+		}
+	}
+
+	// Wait for the generator to observe the cancellation. A value it sent
+	// before then is dropped.
+	for range ch {
+	}
+
+	return received
+}
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Cancelling the parent context also cancels every context derived from it
+	child, childCancel := context.WithCancel(ctx)
+	defer childCancel()
+
+	receiveUntil(generateNumbers(child), 23, cancel)
+
+	fmt.Printf("child context error: %v\n", child.Err())
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/testutil"
+)
+
+func TestCancelStopsGenerator(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	got := receiveUntil(generateNumbers(ctx), 23, cancel)
+	if expected := []int{20, 21, 22, 23}; !slices.Equal(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestCancelParentStopsChild(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	parent, cancel := context.WithCancel(context.Background())
+	child, childCancel := context.WithCancel(parent)
+	defer childCancel()
+
+	// The generator only sees the child, and stops when the parent is
+	// cancelled
+	receiveUntil(generateNumbers(child), 20, cancel)
+
+	if child.Err() != context.Canceled {
+		t.Fatalf("expected the child to be cancelled, got %v", child.Err())
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"math"
+	"time"
+)
+
+func isPrime(n int) bool {
+	if n <= 1 {
+		return false
+	}
+
+	sqrtN := int(math.Sqrt(float64(n)))
+	for i := 2; i <= sqrtN; i++ {
+		if n%i == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func generatePrimeNumbers() iter.Seq[int] {
+	return func(yield func(i int) bool) {
+		n := 0
+
+		for {
+			if isPrime(n) {
+				if !yield(n) {
+					return
+				}
+			}
+
+			n++
+		}
+	}
+}
+
+// untilDone wraps an iterator so that it stops yielding once the context is
+// done. The context is checked between yields, so a producer which takes a
+// long time to compute a single value is not interrupted.
+func untilDone[T any](ctx context.Context, seq iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for val := range seq {
+			if ctx.Err() != nil {
+				fmt.Printf("stopping iteration: %v\n", ctx.Err())
+				return
+			}
+
+			if !yield(val) {
+				return
+			}
+		}
+	}
+}
+
+// countPrimes counts the prime numbers generated until ctx is done, and
+// returns the largest of them. The prime number generator is infinite, so
+// the context is the only thing that ends the loop.
+func countPrimes(ctx context.Context) (count, largest int) {
+	for num := range untilDone(ctx, generatePrimeNumbers()) {
+		count++
+		largest = num
+	}
+
+	return count, largest
+}
+
+func main() {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	now := time.Now()
+	count, largest := countPrimes(ctx)
+
+	fmt.Printf("found %d prime numbers in %d ms, the largest was %d\n", count, time.Since(now).Milliseconds(), largest)
+}
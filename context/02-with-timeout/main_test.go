@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/testutil"
+)
+
+func TestTimeoutStopsIteration(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	count, largest := countPrimes(ctx)
+	if count == 0 || !isPrime(largest) {
+		t.Fatalf("expected to find prime numbers before the timeout, got %d with the largest %d", count, largest)
+	}
+
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Fatalf("expected the loop to end with the deadline, got %v", ctx.Err())
+	}
+}
+
+func TestCancelledBeforeStart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The context is checked before the first yield
+	if count, _ := countPrimes(ctx); count != 0 {
+		t.Fatalf("expected no prime numbers from a cancelled context, got %d", count)
+	}
+}
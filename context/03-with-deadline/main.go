@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+)
+
+func generateNumbers() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 20; ; i++ {
+			// Simulate a slow producer
+			time.Sleep(20 * time.Millisecond)
+
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+// untilDone wraps an iterator so that it stops yielding once the context is
+// done
+func untilDone[T any](ctx context.Context, seq iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for val := range seq {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if !yield(val) {
+				return
+			}
+		}
+	}
+}
+
+// receive ranges over the numbers until ctx is done, and returns them
+func receive(ctx context.Context, name string) []int {
+	var received []int
+	for num := range untilDone(ctx, generateNumbers()) {
+		fmt.Printf("%s: number received in range-loop: %d\n", name, num)
+		received = append(received, num)
+	}
+
+	return received
+}
+
+func main() {
+	now := time.Now()
+
+	parent, cancel := context.WithDeadline(context.Background(), now.Add(100*time.Millisecond))
+	defer cancel()
+
+	// A child context can shorten its parent's deadline, but never extend it
+	shorter, cancelShorter := context.WithDeadline(parent, now.Add(50*time.Millisecond))
+	defer cancelShorter()
+
+	longer, cancelLonger := context.WithDeadline(parent, now.Add(time.Second))
+	defer cancelLonger()
+
+	deadline, _ := shorter.Deadline()
+	fmt.Printf("shorter deadline is %d ms from now\n", deadline.Sub(now).Milliseconds())
+
+	deadline, _ = longer.Deadline()
+	fmt.Printf("longer deadline is %d ms from now\n", deadline.Sub(now).Milliseconds())
+
+	receive(shorter, "shorter")
+	fmt.Printf("shorter: %v after %d ms\n", context.Cause(shorter), time.Since(now).Milliseconds())
+
+	receive(longer, "longer")
+	fmt.Printf("longer: %v after %d ms\n", context.Cause(longer), time.Since(now).Milliseconds())
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/testutil"
+)
+
+func TestChildCannotExtendDeadline(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	now := time.Now()
+
+	parent, cancel := context.WithDeadline(context.Background(), now.Add(50*time.Millisecond))
+	defer cancel()
+
+	longer, cancelLonger := context.WithDeadline(parent, now.Add(time.Hour))
+	defer cancelLonger()
+
+	if deadline, _ := longer.Deadline(); !deadline.Equal(now.Add(50 * time.Millisecond)) {
+		t.Fatalf("expected the deadline of the parent, got one %v from now", deadline.Sub(now))
+	}
+
+	// The loop ends with the deadline of the parent, long before the one
+	// of the child
+	receive(longer, "longer")
+	if elapsed := time.Since(now); elapsed > 10*time.Second {
+		t.Fatalf("expected the loop to end with the parent, it took %v", elapsed)
+	}
+
+	if longer.Err() != context.DeadlineExceeded {
+		t.Fatalf("expected the deadline to be exceeded, got %v", longer.Err())
+	}
+}
+
+func TestCancelStopsIteration(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if got := receive(ctx, "cancelled"); len(got) != 0 {
+		t.Fatalf("expected no numbers from a cancelled context, got %v", got)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// contextKey is unexported so that no other package can collide with the keys
+// defined here
+type contextKey int
+
+const requestIDKey contextKey = iota
+
+// withRequestID returns a copy of ctx which carries the request ID
+func withRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// requestID returns the request ID carried by ctx, if there is one
+func requestID(ctx context.Context) string {
+	id, ok := ctx.Value(requestIDKey).(string)
+	if !ok {
+		return "unknown"
+	}
+
+	return id
+}
+
+// normalize upper-cases the name of every course, logging with the request ID
+// carried by the context it was given
+func normalize(ctx context.Context, seq iter.Seq[db.Course]) iter.Seq[db.Course] {
+	return func(yield func(db.Course) bool) {
+		defer fmt.Printf("[%s] normalize finished\n", requestID(ctx))
+
+		for course := range seq {
+			course.Name = strings.ToUpper(course.Name)
+			if !yield(course) {
+				return
+			}
+		}
+	}
+}
+
+// generateCourses sends every generated course to the returned channel until
+// the context is cancelled
+func generateCourses(ctx context.Context, n int) <-chan db.Course {
+	ch := make(chan db.Course)
+
+	go func() {
+		defer func() {
+			fmt.Printf("[%s] generator finished\n", requestID(ctx))
+			close(ch)
+		}()
+
+		for course := range db.GenerateCourses(n) {
+			select {
+			case ch <- course:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+func handleRequest(ctx context.Context, n int) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Values are inherited by derived contexts, so the request ID is visible
+	// to both the channel generator and the iterator wrapper
+	courses := func(yield func(db.Course) bool) {
+		for course := range generateCourses(ctx, n) {
+			if !yield(course) {
+				return
+			}
+		}
+	}
+
+	for course := range normalize(ctx, courses) {
+		fmt.Printf("[%s] course: %+v\n", requestID(ctx), course)
+	}
+}
+
+func main() {
+	handleRequest(withRequestID(context.Background(), "req-1"), 2)
+	handleRequest(withRequestID(context.Background(), "req-2"), 3)
+	handleRequest(context.Background(), 1)
+}
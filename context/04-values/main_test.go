@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/testutil"
+)
+
+func TestRequestIDIsInherited(t *testing.T) {
+	ctx, cancel := context.WithCancel(withRequestID(context.Background(), "req-1"))
+	defer cancel()
+
+	if id := requestID(ctx); id != "req-1" {
+		t.Fatalf("expected req-1, got %s", id)
+	}
+
+	if id := requestID(context.Background()); id != "unknown" {
+		t.Fatalf("expected unknown without a request ID, got %s", id)
+	}
+}
+
+func TestCancelStopsGenerator(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// The generator has far more courses than are received
+	courses := generateCourses(ctx, 1000)
+	<-courses
+	cancel()
+
+	// The channel is only closed once the goroutine exits
+	for range courses {
+	}
+}
+
+func TestHandleCancelledRequest(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	ctx, cancel := context.WithCancel(withRequestID(context.Background(), "req-1"))
+	cancel()
+
+	// The request is cancelled before it starts, so it returns without
+	// generating its courses, and leaves no goroutine behind
+	handleRequest(ctx, 1000)
+}
+
+func TestHandleRequest(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	handleRequest(withRequestID(context.Background(), "req-1"), 3)
+}
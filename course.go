@@ -0,0 +1,12 @@
+// Package university embeds the course, so that the university CLI can show
+// its lessons without a copy of the repository
+package university
+
+import "embed"
+
+// Course holds every module of the course: the source, manifest, and
+// expected output of each lesson. Its paths start at the repository root,
+// like the IDs of the lessons. A new module has to be added to the list.
+//
+//go:embed concurrency context deadlocks encoding errors exercises generators generics grpc http iterators memory profiling reflection shutdown sorting testing time
+var Course embed.FS
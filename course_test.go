@@ -0,0 +1,32 @@
+package university
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+)
+
+func TestCourseEmbedsEveryLesson(t *testing.T) {
+	onDisk, err := lesson.Discover(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	embedded, err := lesson.DiscoverFS(Course)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected, got []string
+	for l := range onDisk.All() {
+		expected = append(expected, l.ID)
+	}
+	for l := range embedded.All() {
+		got = append(got, l.ID)
+	}
+
+	if !slices.Equal(got, expected) {
+		t.Fatalf("expected the embedded course to contain\n%v\ngot\n%v", expected, got)
+	}
+}
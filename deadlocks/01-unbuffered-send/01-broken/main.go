@@ -0,0 +1,14 @@
+package main
+
+import "fmt"
+
+func main() {
+	ch := make(chan int)
+
+	// A send on an unbuffered channel blocks until another goroutine receives
+	// the value. Nobody else is running, so main blocks forever.
+	fmt.Println("sending value")
+	ch <- 1
+
+	fmt.Printf("value received: %d\n", <-ch)
+}
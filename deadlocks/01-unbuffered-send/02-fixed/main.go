@@ -0,0 +1,15 @@
+package main
+
+import "fmt"
+
+func main() {
+	ch := make(chan int)
+
+	// The send happens in its own goroutine, so main is free to receive
+	go func() {
+		fmt.Println("sending value")
+		ch <- 1
+	}()
+
+	fmt.Printf("value received: %d\n", <-ch)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type account struct {
+	mu      sync.Mutex
+	name    string
+	balance int
+}
+
+// transfer locks the account it takes money from first, and the account it
+// gives money to second
+func transfer(from, to *account, amount int) {
+	from.mu.Lock()
+	defer from.mu.Unlock()
+
+	// Give the other transfer time to lock its first account
+	time.Sleep(10 * time.Millisecond)
+
+	to.mu.Lock()
+	defer to.mu.Unlock()
+
+	from.balance -= amount
+	to.balance += amount
+}
+
+func main() {
+	var wg sync.WaitGroup
+
+	alice := &account{name: "alice", balance: 100}
+	bob := &account{name: "bob", balance: 100}
+
+	// Real programs always have other goroutines running, such as a server or
+	// a ticker. While any goroutine can still run, the runtime cannot tell
+	// that the others are deadlocked, and the program hangs.
+	go func() {
+		for range time.Tick(100 * time.Millisecond) {
+			fmt.Println("still waiting for transfers")
+		}
+	}()
+
+	// Each transfer locks one account and then waits for the other
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		transfer(alice, bob, 10)
+	}()
+	go func() {
+		defer wg.Done()
+		transfer(bob, alice, 20)
+	}()
+	wg.Wait()
+
+	fmt.Printf("alice: %d, bob: %d\n", alice.balance, bob.balance)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type account struct {
+	mu      sync.Mutex
+	name    string
+	balance int
+}
+
+// transfer always locks the accounts in the same order, by name, regardless
+// of which direction the money flows. Two transfers can no longer each hold
+// the lock that the other one is waiting for.
+func transfer(from, to *account, amount int) {
+	first, second := from, to
+	if second.name < first.name {
+		first, second = second, first
+	}
+
+	first.mu.Lock()
+	defer first.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	second.mu.Lock()
+	defer second.mu.Unlock()
+
+	from.balance -= amount
+	to.balance += amount
+}
+
+func main() {
+	var wg sync.WaitGroup
+
+	alice := &account{name: "alice", balance: 100}
+	bob := &account{name: "bob", balance: 100}
+
+	go func() {
+		for range time.Tick(100 * time.Millisecond) {
+			fmt.Println("still waiting for transfers")
+		}
+	}()
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		transfer(alice, bob, 10)
+	}()
+	go func() {
+		defer wg.Done()
+		transfer(bob, alice, 20)
+	}()
+	wg.Wait()
+
+	fmt.Printf("alice: %d, bob: %d\n", alice.balance, bob.balance)
+}
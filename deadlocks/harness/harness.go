@@ -0,0 +1,130 @@
+package harness
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type (
+	// Outcome classifies how a program ended
+	Outcome int
+
+	// Result describes a single run of a program
+	Result struct {
+		Outcome  Outcome
+		Output   string
+		Duration time.Duration
+	}
+)
+
+const (
+	// Completed means the program exited successfully
+	Completed Outcome = iota
+
+	// Deadlocked means the runtime detected that every goroutine was blocked
+	// and aborted the program
+	Deadlocked
+
+	// Hung means the program was still running when the timeout expired,
+	// which is how a deadlock looks when other goroutines are still running
+	Hung
+
+	// Crashed means the program exited with an error for any other reason
+	Crashed
+)
+
+// deadlockMessage is printed by the runtime when all goroutines are blocked
+const deadlockMessage = "all goroutines are asleep - deadlock!"
+
+func (o Outcome) String() string {
+	switch o {
+	case Completed:
+		return "completed"
+	case Deadlocked:
+		return "deadlocked"
+	case Hung:
+		return "hung"
+	case Crashed:
+		return "crashed"
+	default:
+		return fmt.Sprintf("Outcome(%d)", int(o))
+	}
+}
+
+// Build compiles the main package in dir into a binary in outDir, and returns
+// the path to the binary
+func Build(ctx context.Context, dir, outDir string) (string, error) {
+	bin := filepath.Join(outDir, filepath.Base(filepath.Dir(dir))+"-"+filepath.Base(dir))
+
+	cmd := exec.CommandContext(ctx, "go", "build", "-o", bin, ".")
+	cmd.Dir = dir
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to build %s: %w: %s", dir, err, out)
+	}
+
+	return bin, nil
+}
+
+// Run executes the binary and classifies the way it ended. The binary is
+// killed if it is still running after the timeout.
+func Run(ctx context.Context, bin string, timeout time.Duration) (Result, error) {
+	var output bytes.Buffer
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, bin)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	cmd.WaitDelay = time.Second
+
+	now := time.Now()
+	err := cmd.Run()
+	result := Result{
+		Output:   output.String(),
+		Duration: time.Since(now),
+	}
+
+	var exitErr *exec.ExitError
+
+	switch {
+	case err == nil:
+		result.Outcome = Completed
+	case ctx.Err() != nil:
+		result.Outcome = Hung
+	case strings.Contains(result.Output, deadlockMessage):
+		result.Outcome = Deadlocked
+	case errors.As(err, &exitErr):
+		result.Outcome = Crashed
+	default:
+		return result, fmt.Errorf("failed to run %s: %w", bin, err)
+	}
+
+	return result, nil
+}
+
+// BuildAndRun builds the main package in dir into a temporary directory and
+// runs it with the timeout
+func BuildAndRun(ctx context.Context, dir string, timeout time.Duration) (Result, error) {
+	outDir, err := os.MkdirTemp("", "deadlocks")
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(outDir)
+
+	bin, err := Build(ctx, dir, outDir)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Run(ctx, bin, timeout)
+}
@@ -0,0 +1,45 @@
+package harness
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPrograms(t *testing.T) {
+	_, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("the go command is required to build the programs")
+	}
+
+	if testing.Short() {
+		t.Skip("building the programs is slow")
+	}
+
+	tests := []struct {
+		dir      string
+		expected Outcome
+	}{
+		{"01-unbuffered-send/01-broken", Deadlocked},
+		{"01-unbuffered-send/02-fixed", Completed},
+		{"02-lock-ordering/01-broken", Hung},
+		{"02-lock-ordering/02-fixed", Completed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := BuildAndRun(context.Background(), filepath.Join("..", tt.dir), 2*time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Outcome != tt.expected {
+				t.Fatalf("expected %s, got %s after %s:\n%s", tt.expected, result.Outcome, result.Duration, result.Output)
+			}
+		})
+	}
+}
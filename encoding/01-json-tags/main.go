@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// untagged has no tags, so each key is the name of its field
+type untagged struct {
+	ID         int
+	Name       string
+	University string
+}
+
+// listing is a course offered for a term. The tags of db.Course name its
+// keys, and the options after the name change how each field is encoded.
+type listing struct {
+	// The fields of an embedded struct are encoded as if they were fields of
+	// listing
+	db.Course
+
+	Seats int `json:"seats"`
+	// omitempty leaves out a zero value
+	Waitlist   int    `json:"waitlist,omitempty"`
+	Instructor string `json:"instructor,omitempty"`
+	// A nil pointer is encoded as null, and is how a value which may be
+	// missing is told apart from a zero one
+	Room *string `json:"room"`
+	// string encodes a number as a string, for readers whose numbers are
+	// float64, which would lose the precision of a large int64
+	Credits int64 `json:"credits,string"`
+	// - leaves a field out, as if it were unexported
+	Notes string `json:"-"`
+	// An unexported field is never encoded
+	term string
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 2, Seed: 1})
+	db.Seed(cfg.Seed)
+
+	courses := slices.Collect(db.GenerateCourses(cfg.Count))
+
+	fmt.Println("untagged:")
+	data, _ := json.Marshal(untagged(courses[0]))
+	fmt.Printf("  %s\n", data)
+
+	fmt.Println("\ntagged:")
+	data, _ = json.Marshal(courses[0])
+	fmt.Printf("  %s\n", data)
+
+	room := "Hall-101"
+	listings := []listing{
+		{Course: courses[0], Seats: 30, Credits: 4, Notes: "moved", term: "fall"},
+		{Course: courses[1], Seats: 20, Waitlist: 3, Instructor: "Lovelace", Room: &room, Credits: 3},
+	}
+
+	fmt.Println("\nlistings:")
+	for _, l := range listings {
+		data, _ = json.Marshal(l)
+		fmt.Printf("  %s\n", data)
+	}
+
+	// Keys are matched to fields by their tags, ignoring case, and keys
+	// without a field are skipped
+	const input = `{"ID": 7, "NAME": "Chem-1", "university": "UCB", "seats": 10, "credits": "4", "capacity": 12}`
+
+	fmt.Println("\ndecoded:")
+	var l listing
+	err := json.Unmarshal([]byte(input), &l)
+	fmt.Printf("  %+v, err: %v\n", l, err)
+
+	// DisallowUnknownFields makes a key without a field an error, which
+	// catches a misspelt key instead of leaving its field zero
+	fmt.Println("\nDisallowUnknownFields:")
+	dec := json.NewDecoder(strings.NewReader(input))
+	dec.DisallowUnknownFields()
+	err = dec.Decode(&l)
+	fmt.Printf("  err: %v\n", err)
+}
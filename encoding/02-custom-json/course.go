@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// ErrInvalidCourse is returned for JSON whose course is missing a field, or
+// whose level is not a positive number
+var ErrInvalidCourse = errors.New("invalid course")
+
+// Course is a db.Course with a JSON encoding of its own. Methods can only be
+// declared in the package of a type, so they are declared on a type defined
+// from db.Course, which converts to and from it for free.
+type Course db.Course
+
+// courseJSON is the encoding of a Course, which splits its name into the
+// subject and level that the name is made of
+type courseJSON struct {
+	ID         int    `json:"id"`
+	Subject    string `json:"subject"`
+	Level      int    `json:"level"`
+	University string `json:"university"`
+}
+
+// MarshalJSON encodes c as a courseJSON. It has a value receiver, so it is
+// called for both a Course and a *Course.
+func (c Course) MarshalJSON() ([]byte, error) {
+	subject, level, ok := strings.Cut(c.Name, "-")
+	if !ok {
+		return nil, fmt.Errorf("%w: name %q has no level", ErrInvalidCourse, c.Name)
+	}
+
+	n, err := strconv.Atoi(level)
+	if err != nil {
+		return nil, fmt.Errorf("%w: name %q: %w", ErrInvalidCourse, c.Name, err)
+	}
+
+	// Marshalling c itself would call MarshalJSON again, forever
+	return json.Marshal(courseJSON{ID: c.ID, Subject: subject, Level: n, University: c.University})
+}
+
+// UnmarshalJSON decodes a courseJSON into c, and checks that it is a course.
+// It has a pointer receiver, as it sets the fields of c.
+func (c *Course) UnmarshalJSON(data []byte) error {
+	var v courseJSON
+
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+
+	if v.ID == 0 || v.Subject == "" || v.University == "" {
+		return fmt.Errorf("%w: missing field in %s", ErrInvalidCourse, data)
+	}
+
+	if v.Level < 1 {
+		return fmt.Errorf("%w: level %d", ErrInvalidCourse, v.Level)
+	}
+
+	*c = Course{ID: v.ID, Name: fmt.Sprintf("%s-%d", v.Subject, v.Level), University: v.University}
+
+	return nil
+}
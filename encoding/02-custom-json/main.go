@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// listing holds a Course in a field, whose MarshalJSON is called like that
+// of a top-level value
+type listing struct {
+	Course Course `json:"course"`
+	Seats  int    `json:"seats"`
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 3, Seed: 1})
+	db.Seed(cfg.Seed)
+
+	course := Course(db.Course{ID: 1, Name: "Calculus-2", University: "UCB"})
+
+	fmt.Println("db.Course:")
+	data, _ := json.Marshal(db.Course(course))
+	fmt.Printf("  %s\n", data)
+
+	fmt.Println("\nCourse:")
+	data, _ = json.Marshal(course)
+	fmt.Printf("  %s\n", data)
+
+	data, _ = json.Marshal(listing{Course: course, Seats: 30})
+	fmt.Printf("  %s\n", data)
+
+	// An error of MarshalJSON is returned by Marshal, wrapped in a
+	// *json.MarshalerError
+	_, err := json.Marshal(Course{ID: 2, Name: "Chemistry", University: "UCB"})
+	fmt.Printf("  err: %v\n", err)
+
+	fmt.Println("\nUnmarshalJSON:")
+	for _, input := range []string{
+		`{"id":4,"subject":"Physics","level":3,"university":"SDSU"}`,
+		`{"id":5,"subject":"Physics","level":0,"university":"SDSU"}`,
+		`{"id":6,"university":"SDSU"}`,
+	} {
+		var c Course
+
+		err := json.Unmarshal([]byte(input), &c)
+		fmt.Printf("  %+v, err: %v\n", c, err)
+	}
+
+	// The courses are encoded as they are generated, a line of JSON each,
+	// and decoded a line at a time
+	fmt.Println("\nJSON lines:")
+	var buf bytes.Buffer
+
+	err = encodeLines(&buf, func(yield func(Course) bool) {
+		for c := range db.GenerateCourses(cfg.Count) {
+			if !yield(Course(c)) {
+				return
+			}
+		}
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	for _, line := range strings.SplitAfter(buf.String(), "\n") {
+		if line != "" {
+			fmt.Printf("  %s", line)
+		}
+	}
+
+	for c, err := range decodeLines[Course](&buf) {
+		fmt.Printf("  decoded %+v, err: %v\n", db.Course(c), err)
+	}
+}
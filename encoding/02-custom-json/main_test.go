@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+func TestRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+
+	courses := slices.Collect(db.GenerateCourses(50))
+	err := encodeLines(&buf, func(yield func(Course) bool) {
+		for _, c := range courses {
+			if !yield(Course(c)) {
+				return
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []db.Course
+	for c, err := range decodeLines[Course](&buf) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, db.Course(c))
+	}
+
+	if !slices.Equal(got, courses) {
+		t.Fatalf("expected %v, got %v", courses, got)
+	}
+}
+
+func TestMarshalJSONInvalid(t *testing.T) {
+	for _, name := range []string{"Chemistry", "Chem-one"} {
+		_, err := json.Marshal(Course{ID: 1, Name: name, University: "UCB"})
+		if !errors.Is(err, ErrInvalidCourse) {
+			t.Errorf("expected ErrInvalidCourse for %q, got %v", name, err)
+		}
+	}
+}
+
+func TestUnmarshalJSONInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"missing id", `{"subject":"Chem","level":1,"university":"UCB"}`},
+		{"missing subject", `{"id":1,"level":1,"university":"UCB"}`},
+		{"zero level", `{"id":1,"subject":"Chem","university":"UCB"}`},
+		{"negative level", `{"id":1,"subject":"Chem","level":-1,"university":"UCB"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c Course
+
+			err := json.Unmarshal([]byte(tt.input), &c)
+			if !errors.Is(err, ErrInvalidCourse) {
+				t.Fatalf("expected ErrInvalidCourse, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDecodeLinesStopsAtError(t *testing.T) {
+	input := `{"id":1,"subject":"Chem","level":1,"university":"UCB"}
+{"id":2,"subject":"Chem","level":0,"university":"UCB"}
+{"id":3,"subject":"Chem","level":1,"university":"UCB"}
+`
+
+	var ids []int
+	var errs []error
+	for c, err := range decodeLines[Course](strings.NewReader(input)) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, c.ID)
+	}
+
+	if !slices.Equal(ids, []int{1}) || len(errs) != 1 || !errors.Is(errs[0], ErrInvalidCourse) {
+		t.Fatalf("expected course 1 and a single ErrInvalidCourse, got %v and %v", ids, errs)
+	}
+}
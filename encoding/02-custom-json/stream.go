@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+)
+
+// encodeLines writes each value of seq to w as a line of JSON. The values are
+// encoded as they are yielded, so the whole sequence is never in memory.
+func encodeLines[T any](w io.Writer, seq iter.Seq[T]) error {
+	enc := json.NewEncoder(w)
+
+	for v := range seq {
+		err := enc.Encode(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeLines returns an iterator over the JSON values of r, which decodes a
+// single value at a time. It stops at the first error, which is yielded.
+func decodeLines[T any](r io.Reader) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		dec := json.NewDecoder(r)
+
+		for {
+			var v T
+
+			err := dec.Decode(&v)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+
+			if !yield(v, err) || err != nil {
+				return
+			}
+		}
+	}
+}
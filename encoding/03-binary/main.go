@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/manedurphy/golang-university/encoding/coursebin"
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// record is a course with fields of fixed sizes, which binary.Write can
+// write. Strings have no fixed size, so the name and university are arrays
+// padded with zeros.
+type record struct {
+	ID         uint32
+	Name       [12]byte
+	University [4]byte
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 1000, Seed: 1})
+	db.Seed(cfg.Seed)
+
+	course := db.Course{ID: 300, Name: "Chem-1", University: "UCB"}
+
+	// The byte order decides which byte of a number comes first
+	fmt.Println("byte order of uint32(300):")
+	fmt.Printf("  BigEndian    % x\n", binary.BigEndian.AppendUint32(nil, 300))
+	fmt.Printf("  LittleEndian % x\n", binary.LittleEndian.AppendUint32(nil, 300))
+
+	fmt.Println("\nbinary.Write of a fixed-size record:")
+	r := record{ID: uint32(course.ID)}
+	copy(r.Name[:], course.Name)
+	copy(r.University[:], course.University)
+
+	var buf bytes.Buffer
+	err := binary.Write(&buf, binary.BigEndian, r)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("  %d bytes: % x\n", buf.Len(), buf.Bytes())
+
+	// A uvarint takes 7 bits of the number per byte, so small numbers take
+	// few bytes, and the high bit of each byte says whether another follows
+	fmt.Println("\nuvarints:")
+	for _, n := range []uint64{1, 127, 128, 300, 1 << 20} {
+		fmt.Printf("  %-7d % x\n", n, binary.AppendUvarint(nil, n))
+	}
+
+	fmt.Println("\ncoursebin.Append:")
+	data := coursebin.Append(nil, course)
+	fmt.Printf("  %d bytes: % x\n", len(data), data)
+
+	// Write streams the courses as they are generated, after a header which
+	// Read checks before it reads them
+	buf.Reset()
+	err = coursebin.Write(&buf, db.GenerateCourses(cfg.Count))
+	if err != nil {
+		panic(err)
+	}
+
+	var jsonSize int
+	for c := range db.GenerateCourses(cfg.Count) {
+		data, _ := json.Marshal(c)
+		jsonSize += len(data) + 1
+	}
+
+	fmt.Printf("\n%d courses:\n", cfg.Count)
+	fmt.Printf("  coursebin %6d bytes, header % x\n", buf.Len(), buf.Bytes()[:6])
+	fmt.Printf("  JSON      %6d bytes\n", jsonSize)
+
+	var read int
+	for c, err := range coursebin.Read(bytes.NewReader(buf.Bytes())) {
+		if err != nil {
+			panic(err)
+		}
+		if read < 2 {
+			fmt.Printf("  read %+v\n", c)
+		}
+		read++
+	}
+	fmt.Printf("  read %d courses\n", read)
+
+	fmt.Println("\nerrors:")
+	for _, data := range [][]byte{
+		[]byte(`{"id":1}`),
+		buf.Bytes()[:buf.Len()-2],
+	} {
+		for _, err := range coursebin.Read(bytes.NewReader(data)) {
+			if err != nil {
+				fmt.Printf("  %v\n", err)
+			}
+		}
+	}
+}
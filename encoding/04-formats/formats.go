@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+
+	"github.com/manedurphy/golang-university/encoding/coursebin"
+	"github.com/manedurphy/golang-university/grpc/courses"
+	"github.com/manedurphy/golang-university/grpc/coursespb"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/csvseq"
+)
+
+// format writes a stream of courses, and reads it back a course at a time
+type format struct {
+	name   string
+	encode func(w io.Writer, courses iter.Seq[db.Course]) error
+	decode func(r io.Reader) iter.Seq2[db.Course, error]
+}
+
+var formats = []format{
+	{"JSON", encodeJSON, decodeJSON},
+	{"CSV", encodeCSV, csvseq.Decode[db.Course]},
+	{"gob", encodeGob, decodeGob},
+	{"protobuf", encodeProto, decodeProto},
+	{"coursebin", coursebin.Write, coursebin.Read},
+}
+
+// encodeJSON writes a line of JSON for each course. Every line repeats the
+// keys of the fields.
+func encodeJSON(w io.Writer, seq iter.Seq[db.Course]) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	for course := range seq {
+		err := enc.Encode(course)
+		if err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+func decodeJSON(r io.Reader) iter.Seq2[db.Course, error] {
+	return decodeAll(json.NewDecoder(r).Decode)
+}
+
+// encodeCSV writes a header naming the columns once, and a record of the
+// fields of each course after it
+func encodeCSV(w io.Writer, seq iter.Seq[db.Course]) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write([]string{"id", "name", "university"})
+	if err != nil {
+		return err
+	}
+
+	for course := range seq {
+		err = cw.Write([]string{strconv.Itoa(course.ID), course.Name, course.University})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// encodeGob writes a message for each course. The first message of the
+// stream describes the type of db.Course, and the others refer to it.
+func encodeGob(w io.Writer, seq iter.Seq[db.Course]) error {
+	bw := bufio.NewWriter(w)
+	enc := gob.NewEncoder(bw)
+
+	for course := range seq {
+		err := enc.Encode(course)
+		if err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+func decodeGob(r io.Reader) iter.Seq2[db.Course, error] {
+	return decodeAll(gob.NewDecoder(r).Decode)
+}
+
+// encodeProto writes each course as a coursespb.Course, prefixed with its
+// size. A protobuf message does not say where it ends, so the messages of a
+// stream are delimited by their sizes.
+func encodeProto(w io.Writer, seq iter.Seq[db.Course]) error {
+	bw := bufio.NewWriter(w)
+
+	for course := range seq {
+		_, err := protodelim.MarshalTo(bw, courses.ToProto(course))
+		if err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+func decodeProto(r io.Reader) iter.Seq2[db.Course, error] {
+	br := bufio.NewReader(r)
+
+	return decodeAll(func(v any) error {
+		var msg coursespb.Course
+
+		err := protodelim.UnmarshalFrom(br, &msg)
+		if err == nil {
+			*v.(*db.Course) = courses.FromProto(&msg)
+		}
+
+		return err
+	})
+}
+
+// decodeAll returns an iterator which calls decode for each course until it
+// returns io.EOF. It stops at the first other error, which is yielded.
+func decodeAll(decode func(v any) error) iter.Seq2[db.Course, error] {
+	return func(yield func(db.Course, error) bool) {
+		for {
+			var course db.Course
+
+			err := decode(&course)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+
+			if !yield(course, err) || err != nil {
+				return
+			}
+		}
+	}
+}
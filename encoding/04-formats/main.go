@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// result is what it took to encode and decode the courses in a format
+type result struct {
+	size           int
+	encode, decode time.Duration
+}
+
+// measure encodes numCourses generated courses in f, as they are generated,
+// and decodes them again, checking that every course survived the trip
+func measure(f format, numCourses int, seed uint64) (result, error) {
+	var buf bytes.Buffer
+
+	db.Seed(seed)
+	start := time.Now()
+	err := f.encode(&buf, db.GenerateCourses(numCourses))
+	if err != nil {
+		return result{}, err
+	}
+	encode := time.Since(start)
+	size := buf.Len()
+
+	// Seeding the generator again makes it yield the same courses, to compare
+	// the decoded ones with
+	db.Seed(seed)
+	next, stop := iter.Pull(db.GenerateCourses(numCourses))
+	defer stop()
+
+	start = time.Now()
+	var decoded int
+	for course, err := range f.decode(&buf) {
+		if err != nil {
+			return result{}, err
+		}
+
+		expected, ok := next()
+		if !ok || course != expected {
+			return result{}, fmt.Errorf("course %d: expected %+v, got %+v", decoded+1, expected, course)
+		}
+		decoded++
+	}
+
+	if decoded != numCourses {
+		return result{}, fmt.Errorf("expected %d courses, decoded %d", numCourses, decoded)
+	}
+
+	return result{size: size, encode: encode, decode: time.Since(start)}, nil
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 10000, Seed: 1})
+
+	fmt.Printf("%d courses:\n", cfg.Count)
+	for _, f := range formats {
+		r, err := measure(f, cfg.Count, cfg.Seed)
+		if err != nil {
+			fmt.Printf("  %-9s failed: %v\n", f.name, err)
+			continue
+		}
+
+		fmt.Printf("  %-9s %7d bytes %5.1f per course, encode %v, decode %v\n",
+			f.name, r.size, float64(r.size)/float64(cfg.Count), r.encode.Round(time.Microsecond), r.decode.Round(time.Microsecond))
+	}
+}
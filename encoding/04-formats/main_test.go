@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+func TestFormats(t *testing.T) {
+	courses := slices.Collect(db.GenerateCourses(100))
+
+	for _, f := range formats {
+		t.Run(f.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := f.encode(&buf, slices.Values(courses))
+			if err != nil {
+				t.Fatal(err)
+			}
+			data := buf.Bytes()
+
+			seqtest.AssertYields2(t, f.decode(bytes.NewReader(data)), courses, make([]error, len(courses)))
+			seqtest.AssertStopsEarly2(t, 3, func() iter.Seq2[db.Course, error] { return f.decode(bytes.NewReader(data)) })
+
+			// A stream cut short is an error, not fewer courses, except for
+			// CSV, whose last record is still a record without its end
+			var failed bool
+			for _, err := range f.decode(bytes.NewReader(data[:len(data)-3])) {
+				failed = failed || err != nil
+			}
+			if !failed && f.name != "CSV" {
+				t.Fatal("expected an error for a stream cut short")
+			}
+		})
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	for _, f := range formats {
+		r, err := measure(f, 50, 1)
+		if err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+
+		if r.size == 0 {
+			t.Fatalf("%s: expected the courses to be encoded", f.name)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package coursebin
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// Version is the version of the format Write writes
+const Version = 1
+
+// Magic starts every file of courses, so that Read can tell one from other
+// data
+var Magic = [4]byte{'C', 'R', 'S', 'B'}
+
+// ErrFormat is returned for data which is not a file of courses of a version
+// Read supports
+var ErrFormat = errors.New("coursebin: not a file of courses")
+
+// Header is written before the courses. It has a fixed size, so it is
+// written and read with binary.Write and binary.Read in a single call.
+type Header struct {
+	Magic   [4]byte
+	Version uint16
+}
+
+// Append appends the encoding of c to b, and returns the extended slice. The
+// ID is a uvarint, and each string is a uvarint of its length followed by its
+// bytes, so small numbers and short strings take few bytes.
+func Append(b []byte, c db.Course) []byte {
+	b = binary.AppendUvarint(b, uint64(c.ID))
+	b = appendString(b, c.Name)
+
+	return appendString(b, c.University)
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+
+	return append(b, s...)
+}
+
+// Write writes the header, and then the courses, to w. A single buffer is
+// reused for every course, so it does not allocate per course.
+func Write(w io.Writer, courses iter.Seq[db.Course]) error {
+	bw := bufio.NewWriter(w)
+
+	err := binary.Write(bw, binary.BigEndian, Header{Magic: Magic, Version: Version})
+	if err != nil {
+		return err
+	}
+
+	var buf []byte
+	for course := range courses {
+		buf = Append(buf[:0], course)
+
+		_, err = bw.Write(buf)
+		if err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// Read returns an iterator over the courses written to r by Write. It stops
+// at the first error, such as a header it does not recognise or a course cut
+// short, which is yielded along with an empty course.
+func Read(r io.Reader) iter.Seq2[db.Course, error] {
+	return func(yield func(db.Course, error) bool) {
+		br := bufio.NewReader(r)
+
+		var h Header
+		err := binary.Read(br, binary.BigEndian, &h)
+		if err != nil {
+			yield(db.Course{}, fmt.Errorf("%w: %w", ErrFormat, err))
+			return
+		}
+
+		if h.Magic != Magic || h.Version != Version {
+			yield(db.Course{}, fmt.Errorf("%w: magic %q, version %d", ErrFormat, h.Magic[:], h.Version))
+			return
+		}
+
+		for {
+			course, err := readCourse(br)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(db.Course{}, err)
+				return
+			}
+
+			if !yield(course, nil) {
+				return
+			}
+		}
+	}
+}
+
+// readCourse reads a single course. It returns io.EOF if r holds no more
+// courses, and io.ErrUnexpectedEOF if it ends in the middle of one.
+func readCourse(r *bufio.Reader) (db.Course, error) {
+	id, err := binary.ReadUvarint(r)
+	if err != nil {
+		return db.Course{}, err
+	}
+
+	name, err := readString(r)
+	if err != nil {
+		return db.Course{}, err
+	}
+
+	university, err := readString(r)
+	if err != nil {
+		return db.Course{}, err
+	}
+
+	return db.Course{ID: int(id), Name: name, University: university}, nil
+}
+
+func readString(r *bufio.Reader) (string, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return "", noEOF(err)
+	}
+
+	b := make([]byte, n)
+	_, err = io.ReadFull(r, b)
+
+	return string(b), noEOF(err)
+}
+
+// noEOF turns io.EOF into io.ErrUnexpectedEOF, for a course which ends before
+// all of its fields were read
+func noEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
@@ -0,0 +1,81 @@
+package coursebin
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+func encode(t *testing.T, courses []db.Course) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	err := Write(&buf, slices.Values(courses))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	courses := slices.Collect(db.GenerateCourses(100))
+	courses = append(courses, db.Course{ID: 1 << 40, Name: "", University: "ÜCB"})
+
+	data := encode(t, courses)
+
+	seqtest.AssertYields2(t, Read(bytes.NewReader(data)), courses, make([]error, len(courses)))
+	seqtest.AssertStopsEarly2(t, 3, func() iter.Seq2[db.Course, error] { return Read(bytes.NewReader(data)) })
+}
+
+func TestAppend(t *testing.T) {
+	got := Append(nil, db.Course{ID: 300, Name: "Chem-1", University: "UCB"})
+
+	// 300 takes two bytes as a uvarint, and each string its length and bytes
+	expected := []byte{0xac, 0x02, 6, 'C', 'h', 'e', 'm', '-', '1', 3, 'U', 'C', 'B'}
+	if !bytes.Equal(got, expected) {
+		t.Fatalf("expected % x, got % x", expected, got)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	data := encode(t, []db.Course{{ID: 1, Name: "Chem-1", University: "UCB"}})
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected error
+	}{
+		{"empty", nil, ErrFormat},
+		{"wrong magic", append([]byte("JSON"), data[4:]...), ErrFormat},
+		{"wrong version", append(slices.Clone(data[:4]), append([]byte{0, 2}, data[6:]...)...), ErrFormat},
+		{"cut short", data[:len(data)-2], io.ErrUnexpectedEOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs []error
+			for _, err := range Read(bytes.NewReader(tt.data)) {
+				if err != nil {
+					errs = append(errs, err)
+				}
+			}
+
+			if len(errs) != 1 || !errors.Is(errs[0], tt.expected) {
+				t.Fatalf("expected a single %v, got %v", tt.expected, errs)
+			}
+		})
+	}
+
+	// A file of no courses is only the header
+	if got := encode(t, nil); len(got) != 6 {
+		t.Fatalf("expected a header of 6 bytes, got % x", got)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// enroll enrolls a student in a course. Each layer adds what it knows to the
+// error of the layer below with %w, which keeps that error in the chain.
+func enroll(ctx context.Context, coursesDB db.CoursesDB, student string, id int) error {
+	course, err := coursesDB.GetCourse(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to enroll %s: %w", student, err)
+	}
+
+	fmt.Printf("  enrolled %s in %s at %s\n", student, course.Name, course.University)
+	return nil
+}
+
+// enrollFlattened is enroll with %v instead of %w. The message is the same,
+// but the error of the layer below is turned into text, and lost.
+func enrollFlattened(ctx context.Context, coursesDB db.CoursesDB, student string, id int) error {
+	_, err := coursesDB.GetCourse(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to enroll %s: %v", student, err)
+	}
+
+	return nil
+}
+
+// printChain prints every error of the chain, from the outermost one to the
+// one at its root
+func printChain(err error) {
+	for depth := 0; err != nil; depth++ {
+		fmt.Printf("  %d: %T: %v\n", depth, err, err)
+		err = errors.Unwrap(err)
+	}
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 10, DataDir: os.TempDir(), Seed: 1})
+	db.Seed(cfg.Seed)
+
+	coursesDB, err := db.New(cfg.DataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create database: %v\n", err)
+		os.Exit(1)
+	}
+	defer coursesDB.Close()
+
+	err = coursesDB.Seed(cfg.Count)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to seed database: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+
+	fmt.Printf("wrapped with %%w:\n")
+	_ = enroll(ctx, coursesDB, "ada", 3)
+	err = enroll(ctx, coursesDB, "ada", 1000)
+	printChain(err)
+	fmt.Printf("  errors.Is(err, db.ErrNotFound): %t\n", errors.Is(err, db.ErrNotFound))
+
+	fmt.Printf("\nformatted with %%v:\n")
+	err = enrollFlattened(ctx, coursesDB, "ada", 1000)
+	printChain(err)
+	fmt.Printf("  errors.Is(err, db.ErrNotFound): %t\n", errors.Is(err, db.ErrNotFound))
+
+	// A cancelled context fails the query, and the context's error is
+	// wrapped on the way up like any other
+	fmt.Println("\ncancelled:")
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	err = enroll(cancelled, coursesDB, "ada", 3)
+	fmt.Printf("  %v\n", err)
+	fmt.Printf("  errors.Is(err, context.Canceled): %t\n", errors.Is(err, context.Canceled))
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// ErrRejected is a sentinel error: a single value which callers compare
+// against with errors.Is. It says what went wrong, and nothing more.
+var ErrRejected = errors.New("enrollment rejected")
+
+type (
+	// FullError is a typed error. Callers get at its fields with errors.As,
+	// such as to offer a place on the waitlist of the course.
+	FullError struct {
+		Course   db.Course
+		Capacity int
+	}
+
+	// PrerequisiteError is returned for a student who has not taken every
+	// course which the course builds on
+	PrerequisiteError struct {
+		Course  db.Course
+		Missing []string
+	}
+)
+
+func (e *FullError) Error() string {
+	return fmt.Sprintf("%s at %s is full with %d students", e.Course.Name, e.Course.University, e.Capacity)
+}
+
+// Unwrap puts ErrRejected in the chain of a FullError, so that a caller which
+// only cares whether the enrollment was rejected can check for that alone
+func (e *FullError) Unwrap() error {
+	return ErrRejected
+}
+
+func (e *PrerequisiteError) Error() string {
+	return fmt.Sprintf("%s requires %s", e.Course.Name, strings.Join(e.Missing, ", "))
+}
+
+func (e *PrerequisiteError) Unwrap() error {
+	return ErrRejected
+}
+
+// registrar enrolls students in the courses of the database
+type registrar struct {
+	courses  db.CoursesDB
+	capacity int
+	enrolled map[int]int
+	taken    map[string][]string
+}
+
+// prerequisites are the courses each course builds on
+var prerequisites = map[string][]string{
+	"Calculus-2": {"Calculus-1"},
+	"Calculus-3": {"Calculus-1", "Calculus-2"},
+	"Physics-2":  {"Physics-1"},
+	"Chem-2":     {"Chem-1"},
+}
+
+func (r *registrar) enroll(ctx context.Context, student string, id int) error {
+	course, err := r.courses.GetCourse(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to enroll %s: %w", student, err)
+	}
+
+	var missing []string
+	for _, p := range prerequisites[course.Name] {
+		if !contains(r.taken[student], p) {
+			missing = append(missing, p)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("failed to enroll %s: %w", student, &PrerequisiteError{Course: course, Missing: missing})
+	}
+
+	if r.enrolled[id] == r.capacity {
+		return fmt.Errorf("failed to enroll %s: %w", student, &FullError{Course: course, Capacity: r.capacity})
+	}
+
+	r.enrolled[id]++
+	return nil
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// explain tells the student what to do about the error, which takes
+// different information from each kind of error
+func explain(err error) string {
+	var (
+		full    *FullError
+		missing *PrerequisiteError
+	)
+
+	switch {
+	case err == nil:
+		return "enrolled"
+	case errors.Is(err, db.ErrNotFound):
+		return "no such course, check the ID"
+	case errors.As(err, &full):
+		return fmt.Sprintf("join the waitlist of %s at %s", full.Course.Name, full.Course.University)
+	case errors.As(err, &missing):
+		return fmt.Sprintf("take %s first", missing.Missing[0])
+	default:
+		return "try again later"
+	}
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 10, DataDir: os.TempDir(), Seed: 1})
+	db.Seed(cfg.Seed)
+
+	coursesDB, err := db.New(cfg.DataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create database: %v\n", err)
+		os.Exit(1)
+	}
+	defer coursesDB.Close()
+
+	err = coursesDB.Seed(cfg.Count)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to seed database: %v\n", err)
+		os.Exit(1)
+	}
+
+	r := &registrar{
+		courses:  coursesDB,
+		capacity: 2,
+		enrolled: make(map[int]int),
+		taken:    map[string][]string{"grace": {"Calculus-1"}},
+	}
+
+	requests := []struct {
+		student string
+		id      int
+	}{
+		{"ada", 3}, {"grace", 3}, {"linus", 3}, {"ada", 10}, {"grace", 10}, {"ada", 1000},
+	}
+
+	ctx := context.Background()
+	for _, req := range requests {
+		err := r.enroll(ctx, req.student, req.id)
+
+		fmt.Printf("%s, course %d:\n", req.student, req.id)
+		if err != nil {
+			fmt.Printf("  error:    %v\n", err)
+			fmt.Printf("  rejected: %t\n", errors.Is(err, ErrRejected))
+		}
+		fmt.Printf("  advice:   %s\n", explain(err))
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+func TestExplain(t *testing.T) {
+	course := db.Course{ID: 1, Name: "Physics-2", University: "MIT"}
+
+	tests := []struct {
+		name     string
+		err      error
+		rejected bool
+		expected string
+	}{
+		{"nil", nil, false, "enrolled"},
+		{"not found", fmt.Errorf("%w: course 7", db.ErrNotFound), false, "no such course, check the ID"},
+		{"full", &FullError{Course: course, Capacity: 30}, true, "join the waitlist of Physics-2 at MIT"},
+		{"prerequisite", &PrerequisiteError{Course: course, Missing: []string{"Physics-1"}}, true, "take Physics-1 first"},
+		{"unknown", errors.New("connection reset"), false, "try again later"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The errors are wrapped, as enroll wraps them
+			err := tt.err
+			if err != nil {
+				err = fmt.Errorf("failed to enroll ada: %w", err)
+			}
+
+			if got := explain(err); got != tt.expected {
+				t.Fatalf("expected %q, got %q", tt.expected, got)
+			}
+
+			if got := errors.Is(err, ErrRejected); got != tt.rejected {
+				t.Fatalf("expected errors.Is(err, ErrRejected) to be %t", tt.rejected)
+			}
+		})
+	}
+}
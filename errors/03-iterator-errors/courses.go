@@ -0,0 +1,43 @@
+package main
+
+import (
+	"iter"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// Courses puts the errors of an iter.Seq2 behind an Err method, like
+// bufio.Scanner and sql.Rows do. All yields the courses alone, and stops at
+// the first error.
+type Courses struct {
+	seq iter.Seq2[db.Course, error]
+	err error
+}
+
+// NewCourses returns the Courses of seq
+func NewCourses(seq iter.Seq2[db.Course, error]) *Courses {
+	return &Courses{seq: seq}
+}
+
+// All yields the courses of seq up to the first error, which Err returns once
+// the loop is over
+func (c *Courses) All() iter.Seq[db.Course] {
+	return func(yield func(db.Course) bool) {
+		for course, err := range c.seq {
+			if err != nil {
+				c.err = err
+				return
+			}
+
+			if !yield(course) {
+				return
+			}
+		}
+	}
+}
+
+// Err returns the error which stopped All, or nil if All ran out of courses
+// or was stopped by its consumer
+func (c *Courses) Err() error {
+	return c.err
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// corruptIDs are the courses whose name is lost. Scanning a NULL name into
+// the string of a Course fails.
+var corruptIDs = []int{3, 7}
+
+// corrupt sets the name of the courses to NULL, behind the back of the
+// CoursesDB
+func corrupt(dataDir string, ids []int) error {
+	conn, err := sql.Open("sqlite3", filepath.Join(dataDir, "courses.db"))
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer conn.Close()
+
+	for _, id := range ids {
+		_, err = conn.Exec(`UPDATE courses SET name = NULL WHERE id = ?`, id)
+		if err != nil {
+			return fmt.Errorf("failed to corrupt course %d: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 10, DataDir: os.TempDir(), Seed: 1})
+	db.Seed(cfg.Seed)
+
+	coursesDB, err := db.New(cfg.DataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create database: %v\n", err)
+		os.Exit(1)
+	}
+	defer coursesDB.Close()
+
+	err = coursesDB.Seed(cfg.Count)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to seed database: %v\n", err)
+		os.Exit(1)
+	}
+
+	err = corrupt(cfg.DataDir, corruptIDs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to corrupt database: %v\n", err)
+		os.Exit(1)
+	}
+
+	// An error with every value lets the consumer decide what a bad row
+	// means. This one skips them, and gets every other course.
+	fmt.Println("per-value errors, iter.Seq2[db.Course, error]:")
+	var found, failed int
+	for _, err := range coursesDB.GetCourses() {
+		if err != nil {
+			fmt.Printf("  skipped: %v\n", err)
+			failed++
+			continue
+		}
+
+		found++
+	}
+	fmt.Printf("  found %d courses, skipped %d\n", found, failed)
+
+	// A terminal error keeps the loop free of error handling, but the
+	// iteration ends at the first error, and the consumer has to ask for it
+	fmt.Println("\nterminal error, iter.Seq[db.Course] and Err:")
+	courses := NewCourses(coursesDB.GetCourses())
+	found = 0
+	for range courses.All() {
+		found++
+	}
+	if err := courses.Err(); err != nil {
+		fmt.Printf("  found %d courses before: %v\n", found, err)
+	}
+
+	// Nothing makes the consumer call Err. One which forgets to gets a
+	// truncated list, and no sign of it.
+	fmt.Println("\nterminal error, Err forgotten:")
+	courses = NewCourses(coursesDB.GetCourses())
+	found = 0
+	for range courses.All() {
+		found++
+	}
+	fmt.Printf("  found %d courses\n", found)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+var errScan = errors.New("scan failed")
+
+// rows yields a course for each id, and errScan for each id of 0
+func rows(ids ...int) iter.Seq2[db.Course, error] {
+	return func(yield func(db.Course, error) bool) {
+		for _, id := range ids {
+			var err error
+			if id == 0 {
+				err = errScan
+			}
+
+			if !yield(db.Course{ID: id}, err) {
+				return
+			}
+		}
+	}
+}
+
+func ids(courses iter.Seq[db.Course]) []int {
+	var ids []int
+	for course := range courses {
+		ids = append(ids, course.ID)
+	}
+
+	return ids
+}
+
+func TestCoursesStopsAtError(t *testing.T) {
+	courses := NewCourses(rows(1, 2, 0, 4))
+
+	if got := ids(courses.All()); !slices.Equal(got, []int{1, 2}) {
+		t.Fatalf("expected the courses before the error, got %v", got)
+	}
+
+	if err := courses.Err(); !errors.Is(err, errScan) {
+		t.Fatalf("expected the error of the scan, got %v", err)
+	}
+}
+
+func TestCoursesErrNil(t *testing.T) {
+	courses := NewCourses(rows(1, 2, 3))
+
+	if got := ids(courses.All()); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("expected every course, got %v", got)
+	}
+
+	if err := courses.Err(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// A consumer which stops early has not run into an error either
+	courses = NewCourses(rows(1, 0))
+	for range courses.All() {
+		break
+	}
+
+	if err := courses.Err(); err != nil {
+		t.Fatalf("expected no error for a consumer which stopped early, got %v", err)
+	}
+}
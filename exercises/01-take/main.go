@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+)
+
+// generateNumbers returns an infinite iterator over the numbers starting at 20
+func generateNumbers() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 20; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func main() {
+	for num := range Take(generateNumbers(), 5) {
+		fmt.Printf("number received in range-loop: %d\n", num)
+	}
+}
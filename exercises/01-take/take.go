@@ -0,0 +1,14 @@
+//go:build !solution
+
+package main
+
+import "iter"
+
+// Take returns an iterator over the first n values of seq. It stops pulling
+// values from seq as soon as it has yielded n of them, so that it can be used
+// with infinite iterators, and yields nothing if n is zero or negative.
+//
+// TODO: implement Take, then run `university grade exercises/01-take`
+func Take[V any](seq iter.Seq[V], n int) iter.Seq[V] {
+	return func(yield func(V) bool) {}
+}
@@ -0,0 +1,28 @@
+//go:build solution
+
+package main
+
+import "iter"
+
+// Take returns an iterator over the first n values of seq. It stops pulling
+// values from seq as soon as it has yielded n of them, so that it can be used
+// with infinite iterators, and yields nothing if n is zero or negative.
+func Take[V any](seq iter.Seq[V], n int) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		if n <= 0 {
+			return
+		}
+
+		i := 0
+		for val := range seq {
+			if !yield(val) {
+				return
+			}
+
+			i++
+			if i == n {
+				return
+			}
+		}
+	}
+}
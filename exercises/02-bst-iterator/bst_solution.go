@@ -0,0 +1,56 @@
+//go:build solution
+
+package main
+
+import (
+	"cmp"
+	"iter"
+)
+
+type (
+	// Tree is a binary search tree. The zero value is an empty tree.
+	Tree[K cmp.Ordered] struct {
+		root *node[K]
+	}
+
+	node[K cmp.Ordered] struct {
+		key         K
+		left, right *node[K]
+	}
+)
+
+// Insert adds key to the tree, unless the tree already contains it
+func (t *Tree[K]) Insert(key K) {
+	current := &t.root
+	for *current != nil {
+		switch {
+		case key < (*current).key:
+			current = &(*current).left
+		case key > (*current).key:
+			current = &(*current).right
+		default:
+			return
+		}
+	}
+
+	*current = &node[K]{key: key}
+}
+
+// All returns an iterator over the keys of the tree in ascending order, which
+// is an in-order walk: the left subtree, the node itself, then the right
+// subtree. The walk must stop as soon as the consumer breaks out of its loop.
+func (t *Tree[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		t.root.walk(yield)
+	}
+}
+
+// walk yields the keys of the subtree rooted at n in ascending order. It
+// returns false once yield has, so that every caller up the recursion stops.
+func (n *node[K]) walk(yield func(K) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	return n.left.walk(yield) && yield(n.key) && n.right.walk(yield)
+}
@@ -0,0 +1,17 @@
+package main
+
+import "fmt"
+
+func main() {
+	var tree Tree[string]
+	for _, name := range []string{"PHYSICS-1", "CHEM-2", "CALCULUS-1", "STATS-1", "CHEM-1", "BIO-2"} {
+		tree.Insert(name)
+	}
+
+	for name := range tree.All() {
+		fmt.Printf("course received in range-loop: %s\n", name)
+		if name == "CHEM-2" {
+			break
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+func main() {
+	evens := slices.Values([]int{20, 22, 24, 26})
+	odds := slices.Values([]int{21, 23, 25})
+
+	for num := range Merge(evens, odds) {
+		fmt.Printf("number received in range-loop: %d\n", num)
+	}
+}
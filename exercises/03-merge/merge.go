@@ -0,0 +1,20 @@
+//go:build !solution
+
+package main
+
+import (
+	"cmp"
+	"iter"
+)
+
+// Merge returns an iterator over the values of a and b in ascending order.
+// Both iterators must already be sorted. When a value is in both, it is
+// yielded twice. Once the consumer breaks out of its loop, or both iterators
+// are exhausted, neither a nor b may be left running.
+//
+// Hint: one of the iterators has to be converted with iter.Pull.
+//
+// TODO: implement Merge, then run `university grade exercises/03-merge`
+func Merge[V cmp.Ordered](a, b iter.Seq[V]) iter.Seq[V] {
+	return func(yield func(V) bool) {}
+}
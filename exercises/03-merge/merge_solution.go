@@ -0,0 +1,44 @@
+//go:build solution
+
+package main
+
+import (
+	"cmp"
+	"iter"
+)
+
+// Merge returns an iterator over the values of a and b in ascending order.
+// Both iterators must already be sorted. When a value is in both, it is
+// yielded twice. Once the consumer breaks out of its loop, or both iterators
+// are exhausted, neither a nor b may be left running.
+func Merge[V cmp.Ordered](a, b iter.Seq[V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		next, stop := iter.Pull(b)
+		defer stop()
+
+		bVal, bOk := next()
+		for aVal := range a {
+			// Yield every value of b which comes before the current value
+			// of a
+			for bOk && bVal < aVal {
+				if !yield(bVal) {
+					return
+				}
+
+				bVal, bOk = next()
+			}
+
+			if !yield(aVal) {
+				return
+			}
+		}
+
+		for bOk {
+			if !yield(bVal) {
+				return
+			}
+
+			bVal, bOk = next()
+		}
+	}
+}
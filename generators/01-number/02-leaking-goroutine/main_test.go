@@ -0,0 +1,16 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/testutil"
+)
+
+func TestMainLeaksGoroutine(t *testing.T) {
+	err := testutil.FindLeaks(main)
+	if err == nil {
+		t.Fatal("expected the generator goroutine to leak after breaking out of the range-loop")
+	}
+
+	t.Logf("detected leak: %v", err)
+}
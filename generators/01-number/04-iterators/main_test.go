@@ -0,0 +1,13 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/testutil"
+)
+
+func TestMainDoesNotLeak(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	main()
+}
@@ -0,0 +1,59 @@
+package events
+
+import (
+	"iter"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/clock"
+)
+
+type (
+	// Event is something which happened at a point in time
+	Event struct {
+		Seq int
+	}
+)
+
+// Tick returns a generator which yields an event every interval, using a
+// time.Ticker. The ticker is stopped when the consumer breaks out of its loop.
+// A ticker drops ticks for a slow consumer rather than queueing them, so the
+// time between events is never less than the interval.
+func Tick(interval time.Duration) iter.Seq2[time.Time, Event] {
+	return TickOn(clock.Real, interval)
+}
+
+// TickOn is like Tick, but ticks by the time of c
+func TickOn(c clock.Clock, interval time.Duration) iter.Seq2[time.Time, Event] {
+	return func(yield func(time.Time, Event) bool) {
+		ticker := c.NewTicker(interval)
+		defer ticker.Stop()
+
+		for seq := 1; ; seq++ {
+			t := <-ticker.C()
+
+			if !yield(t, Event{Seq: seq}) {
+				return
+			}
+		}
+	}
+}
+
+// Sleep returns a generator which sleeps for the interval, then yields an
+// event. The time spent producing and consuming each event is added on top
+// of the interval, so the events drift further from the wall clock over time.
+func Sleep(interval time.Duration) iter.Seq2[time.Time, Event] {
+	return SleepOn(clock.Real, interval)
+}
+
+// SleepOn is like Sleep, but sleeps by the time of c
+func SleepOn(c clock.Clock, interval time.Duration) iter.Seq2[time.Time, Event] {
+	return func(yield func(time.Time, Event) bool) {
+		for seq := 1; ; seq++ {
+			c.Sleep(interval)
+
+			if !yield(c.Now(), Event{Seq: seq}) {
+				return
+			}
+		}
+	}
+}
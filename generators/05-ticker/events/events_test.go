@@ -0,0 +1,108 @@
+package events
+
+import (
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/clock"
+)
+
+const (
+	interval = 20 * time.Millisecond
+	work     = 5 * time.Millisecond
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// consume reads numEvents events in a goroutine of its own, and sleeps for
+// work in the loop body. It sends the time of each event, and then blocks in
+// its sleep until the test advances the clock.
+func consume(fake *clock.Fake, seq iter.Seq2[time.Time, Event], numEvents int) <-chan time.Duration {
+	times := make(chan time.Duration)
+
+	go func() {
+		defer close(times)
+
+		for t, event := range seq {
+			times <- t.Sub(start)
+			fake.Sleep(work)
+
+			if event.Seq == numEvents {
+				break
+			}
+		}
+	}()
+
+	return times
+}
+
+func TestSleepDrifts(t *testing.T) {
+	fake := clock.NewFake(start)
+	times := consume(fake, SleepOn(fake, interval), 3)
+
+	// The work of the loop body is added to every interval
+	for _, expected := range []time.Duration{20, 45, 70} {
+		fake.BlockUntil(1)
+		fake.Advance(interval)
+
+		if got := <-times; got != expected*time.Millisecond {
+			t.Fatalf("expected an event at %v, got one at %v", expected*time.Millisecond, got)
+		}
+
+		fake.BlockUntil(1)
+		fake.Advance(work)
+	}
+
+	if _, ok := <-times; ok {
+		t.Fatal("expected the consumer to stop")
+	}
+}
+
+func TestTickDoesNotDrift(t *testing.T) {
+	fake := clock.NewFake(start)
+	times := consume(fake, TickOn(fake, interval), 3)
+
+	fake.BlockUntil(1)
+	fake.Advance(interval)
+
+	for _, expected := range []time.Duration{20, 40, 60} {
+		if got := <-times; got != expected*time.Millisecond {
+			t.Fatalf("expected an event at %v, got one at %v", expected*time.Millisecond, got)
+		}
+
+		// The ticker and the sleep of the loop body
+		fake.BlockUntil(2)
+		fake.Advance(work)
+		fake.Advance(interval - work)
+	}
+
+	if _, ok := <-times; ok {
+		t.Fatal("expected the consumer to stop")
+	}
+}
+
+func TestTickDropsTicks(t *testing.T) {
+	fake := clock.NewFake(start)
+	times := consume(fake, TickOn(fake, interval), 2)
+
+	fake.BlockUntil(1)
+	fake.Advance(interval)
+	<-times
+
+	// The loop body sleeps through two ticks, and only the first of them is
+	// kept for it
+	fake.BlockUntil(2)
+	fake.Advance(2 * interval)
+
+	if got := <-times; got != 2*interval {
+		t.Fatalf("expected the tick at %v, got %v", 2*interval, got)
+	}
+
+	fake.BlockUntil(2)
+	fake.Advance(work)
+
+	if _, ok := <-times; ok {
+		t.Fatal("expected the consumer to stop")
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/manedurphy/golang-university/generators/05-ticker/events"
+	"github.com/manedurphy/golang-university/internal/clock"
+)
+
+type (
+	// stopClock is a clock whose tickers print when they are stopped, which
+	// shows when the generator stops its ticker
+	stopClock struct {
+		clock.Clock
+	}
+
+	stopTicker struct {
+		clock.Ticker
+	}
+)
+
+const interval = 20 * time.Millisecond
+
+func (c stopClock) NewTicker(d time.Duration) clock.Ticker {
+	return stopTicker{c.Clock.NewTicker(d)}
+}
+
+func (t stopTicker) Stop() {
+	t.Ticker.Stop()
+	fmt.Println("ticker stopped")
+}
+
+// consume prints each event with its drift from the time it was scheduled
+// for, and simulates work in the loop body
+func consume(name string, seq iter.Seq2[time.Time, events.Event], numEvents int, work time.Duration) {
+	start := time.Now()
+
+	for t, event := range seq {
+		scheduled := start.Add(time.Duration(event.Seq) * interval)
+		fmt.Printf("%s: event %d at %3d ms, drift %3d ms\n", name, event.Seq, t.Sub(start).Milliseconds(), t.Sub(scheduled).Milliseconds())
+
+		time.Sleep(work)
+
+		if event.Seq == numEvents {
+			break
+		}
+	}
+}
+
+func main() {
+	// Sleeping adds the work done in the loop body to every interval, so the
+	// drift keeps growing
+	consume("sleep", events.Sleep(interval), 5, 5*time.Millisecond)
+	fmt.Println()
+
+	// The ticker is scheduled against the clock, so the work done in the loop
+	// body does not accumulate
+	consume("ticker", events.TickOn(stopClock{clock.Real}, interval), 5, 5*time.Millisecond)
+	fmt.Println()
+
+	// A consumer slower than the interval makes the ticker drop ticks. Events
+	// arrive at the consumer's pace, not the ticker's.
+	consume("slow", events.TickOn(stopClock{clock.Real}, interval), 5, 50*time.Millisecond)
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/gobseq"
+)
+
+// segmentSize is the number of primes spilled to each segment file
+const segmentSize = 10000
+
+func isPrime(n int) bool {
+	if n <= 1 {
+		return false
+	}
+
+	sqrtN := int(math.Sqrt(float64(n)))
+	for i := 2; i <= sqrtN; i++ {
+		if n%i == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// generatePrimeNumbers generates the prime numbers from n onwards, so that it
+// can pick up where a previous run left off
+func generatePrimeNumbers(n int) iter.Seq[int] {
+	return func(yield func(i int) bool) {
+		for {
+			if isPrime(n) {
+				if !yield(n) {
+					return
+				}
+			}
+
+			n++
+		}
+	}
+}
+
+// segments returns the paths of the complete segments in dir, in the order
+// they were written
+func segments(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "primes-*.gob"))
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(paths)
+	return paths, nil
+}
+
+// checkpoint reads the segments in dir, and returns how many primes they hold
+// and the number to continue generating from
+func checkpoint(dir string) (count, next int, err error) {
+	paths, err := segments(dir)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		for prime, err := range gobseq.Decode[int](f) {
+			if err != nil {
+				f.Close()
+				return 0, 0, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+			}
+
+			count++
+			next = prime + 1
+		}
+
+		f.Close()
+	}
+
+	return count, next, nil
+}
+
+// writeSegment spills primes to a temporary file, and renames it once it is
+// complete. A run which is interrupted in the middle of a segment leaves only
+// the temporary file behind, which the next run ignores.
+func writeSegment(path string, primes iter.Seq[int]) error {
+	f, err := os.Create(path + ".tmp")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	err = gobseq.Encode(f, primes)
+	if err != nil {
+		return fmt.Errorf("failed to write segment: %w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write segment: %w", err)
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+// run generates primes until dir holds total of them, starting from its
+// checkpoint. It stops after writing maxSegments segments if that is more
+// than zero, like a run which is killed part of the way through.
+func run(dir string, total, maxSegments int) error {
+	count, next, err := checkpoint(dir)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("resuming after %d primes, from %d\n", count, next)
+
+	// Pulling from the generator lets every segment continue where the last
+	// one stopped, without starting the generator over
+	pull, stop := iter.Pull(generatePrimeNumbers(next))
+	defer stop()
+
+	for written := 0; count < total; written++ {
+		if maxSegments > 0 && written == maxSegments {
+			fmt.Printf("interrupted after %d segments\n", written)
+			return nil
+		}
+
+		size := min(segmentSize, total-count)
+		path := filepath.Join(dir, fmt.Sprintf("primes-%04d.gob", count/segmentSize))
+
+		err = writeSegment(path, func(yield func(int) bool) {
+			for range size {
+				prime, _ := pull()
+				if !yield(prime) {
+					return
+				}
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		count += size
+		fmt.Printf("wrote %s, %d primes so far\n", filepath.Base(path), count)
+	}
+
+	return nil
+}
+
+func main() {
+	// -count is the number of primes to generate, and -data-dir is where the
+	// segments are written
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 100000, DataDir: os.TempDir()})
+
+	dir, err := os.MkdirTemp(cfg.DataDir, "university-checkpoint-")
+	if err != nil {
+		fmt.Printf("failed to create data directory: %v\n", err)
+		os.Exit(1)
+	}
+	defer os.RemoveAll(dir)
+
+	fmt.Println("run 1:")
+	err = run(dir, cfg.Count, 3)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// A segment which was being written when the run was killed
+	err = os.WriteFile(filepath.Join(dir, "primes-0003.gob.tmp"), []byte("half a segment"), 0o644)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println("\nrun 2:")
+	err = run(dir, cfg.Count, 0)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	count, next, err := checkpoint(dir)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("\nthe segments hold %d primes, the last of which is %d\n", count, next-1)
+}
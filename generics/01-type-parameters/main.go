@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/itertools"
+)
+
+// filterCourses and filterNames are the same function, written twice,
+// because Go without generics has no way to say "a sequence of anything"
+// without giving up the type of its values
+func filterCourses(seq iter.Seq[db.Course], keep func(db.Course) bool) iter.Seq[db.Course] {
+	return func(yield func(db.Course) bool) {
+		for course := range seq {
+			if keep(course) && !yield(course) {
+				return
+			}
+		}
+	}
+}
+
+func filterNames(seq iter.Seq[string], keep func(string) bool) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for name := range seq {
+			if keep(name) && !yield(name) {
+				return
+			}
+		}
+	}
+}
+
+// Filter is both of them at once. T is a type parameter: a placeholder for
+// a type which is chosen each time Filter is used. The constraint any allows
+// every type.
+func Filter[T any](seq iter.Seq[T], keep func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if keep(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Pair is a generic type. Each instantiation, such as Pair[string, int], is a
+// type of its own.
+type Pair[K, V any] struct {
+	Key   K
+	Value V
+}
+
+func (p Pair[K, V]) String() string {
+	return fmt.Sprintf("%v=%v", p.Key, p.Value)
+}
+
+// Pairs collects the pairs of a sequence of pairs, such as the one maps.All
+// returns, into a slice
+func Pairs[K, V any](seq iter.Seq2[K, V]) []Pair[K, V] {
+	var pairs []Pair[K, V]
+	for k, v := range seq {
+		pairs = append(pairs, Pair[K, V]{k, v})
+	}
+
+	return pairs
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 12, Seed: 1})
+	db.Seed(cfg.Seed)
+
+	courses := slices.Collect(db.GenerateCourses(cfg.Count))
+	atSJSU := func(c db.Course) bool { return c.University == "SJSU" }
+	physics := func(name string) bool { return strings.HasPrefix(name, "Physics") }
+
+	fmt.Println("without type parameters:")
+	for course := range filterCourses(slices.Values(courses), atSJSU) {
+		fmt.Printf("  %d %s\n", course.ID, course.Name)
+	}
+
+	// itertools.Map is generic too: it maps a sequence of T to one of U
+	names := itertools.Map(slices.Values(courses), func(c db.Course) string { return c.Name })
+	fmt.Printf("  %v\n", slices.Collect(filterNames(names, physics)))
+
+	fmt.Println("\nwith type parameters:")
+	for course := range Filter[db.Course](slices.Values(courses), atSJSU) {
+		fmt.Printf("  %d %s\n", course.ID, course.Name)
+	}
+	fmt.Printf("  %v\n", slices.Collect(Filter[string](names, physics)))
+
+	fmt.Println("\ngeneric types:")
+	pairs := Pairs(slices.All(slices.Collect(Filter(names, physics))))
+	fmt.Printf("  %T\n  %v\n", pairs, pairs)
+}
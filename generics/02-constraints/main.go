@@ -0,0 +1,115 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/itertools"
+)
+
+// Credits is a named type whose underlying type is int
+type Credits int
+
+// Integer is a constraint which lists the types it allows. The ~ allows every
+// type whose underlying type is int as well, such as Credits. Without it,
+// only int itself would satisfy Integer.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// SumOrdered adds up the values of seq. + is defined for every type of
+// cmp.Ordered, which holds every integer, float, and string type, so the sum
+// of strings is their concatenation.
+func SumOrdered[T cmp.Ordered](seq iter.Seq[T]) T {
+	var sum T
+	for v := range seq {
+		sum += v
+	}
+
+	return sum
+}
+
+// Mean returns the mean of the values of seq, rounded down. Its constraint
+// only allows integers, since rounding down the mean of floats makes no
+// sense.
+func Mean[T Integer](seq iter.Seq[T]) T {
+	var sum, n T
+	for v := range seq {
+		sum += v
+		n++
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	return sum / n
+}
+
+// Named is a constraint with a method. Any type with a Label method satisfies
+// it, and Labels can call the method on its values.
+type Named interface {
+	Label() string
+}
+
+type university string
+
+func (u university) Label() string { return strings.ToLower(string(u)) }
+
+func Labels[T Named](seq iter.Seq[T]) []string {
+	var labels []string
+	for v := range seq {
+		labels = append(labels, v.Label())
+	}
+
+	return labels
+}
+
+// credits is how many credits each course is worth
+var credits = map[string]Credits{
+	"Chem-1": 4, "Chem-2": 4,
+	"Physics-1": 3, "Physics-2": 3, "Physics-3": 3,
+	"Calculus-1": 5, "Calculus-2": 5, "Calculus-3": 5,
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 12, Seed: 1})
+	db.Seed(cfg.Seed)
+
+	courses := slices.Collect(db.GenerateCourses(cfg.Count))
+	creditsOf := itertools.Map(slices.Values(courses), func(c db.Course) Credits { return credits[c.Name] })
+
+	fmt.Println("SumOrdered[T cmp.Ordered]:")
+	fmt.Printf("  ints:    %v\n", SumOrdered(slices.Values([]int{1, 2, 3})))
+	fmt.Printf("  floats:  %v\n", SumOrdered(slices.Values([]float64{0.5, 0.25})))
+	fmt.Printf("  strings: %q\n", SumOrdered(slices.Values([]string{"go", "lang"})))
+	fmt.Printf("  credits: %v\n", SumOrdered(creditsOf))
+
+	fmt.Println("\nMean[T Integer]:")
+	fmt.Printf("  credits: %v\n", Mean(creditsOf))
+	// Mean(slices.Values([]float64{0.5})) does not compile:
+	// float64 does not satisfy Integer (float64 missing in ~int | ~int8 | ~int16 | ~int32 | ~int64)
+
+	fmt.Println("\nLabels[T Named]:")
+	universities := itertools.Map(slices.Values(db.Universities()), func(u string) university { return university(u) })
+	fmt.Printf("  %v\n", Labels(universities))
+
+	// cmp.Compare is generic over cmp.Ordered as well, which makes sorting
+	// by any ordered key a one-liner
+	fmt.Println("\ncredits per university:")
+	perUniversity := make(map[string]Credits)
+	for _, c := range courses {
+		perUniversity[c.University] += credits[c.Name]
+	}
+	for _, u := range slices.SortedFunc(maps.Keys(perUniversity), func(a, b string) int {
+		return cmp.Compare(perUniversity[b], perUniversity[a])
+	}) {
+		fmt.Printf("  %-5s %2d\n", u, perUniversity[u])
+	}
+}
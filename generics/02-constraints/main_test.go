@@ -0,0 +1,30 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSumOrdered(t *testing.T) {
+	if got := SumOrdered(slices.Values([]Credits{3, 4, 5})); got != 12 {
+		t.Fatalf("expected 12 credits, got %d", got)
+	}
+
+	if got := SumOrdered(slices.Values([]string{"a", "b", "c"})); got != "abc" {
+		t.Fatalf("expected the strings to be concatenated, got %q", got)
+	}
+
+	if got := SumOrdered(slices.Values([]float64(nil))); got != 0 {
+		t.Fatalf("expected the zero value for no values, got %v", got)
+	}
+}
+
+func TestMean(t *testing.T) {
+	if got := Mean(slices.Values([]int8{1, 2, 4})); got != 2 {
+		t.Fatalf("expected the mean to be rounded down to 2, got %d", got)
+	}
+
+	if got := Mean(slices.Values([]Credits(nil))); got != 0 {
+		t.Fatalf("expected 0 for no values, got %d", got)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/itertools"
+)
+
+// IDs is a named slice type, such as a package would declare to give the
+// slice methods of its own
+type IDs []int
+
+func (ids IDs) String() string { return fmt.Sprintf("%d IDs", len(ids)) }
+
+// CollectInto appends the values of seq to s. The type of s is a type
+// parameter of its own, S, constrained to slices of E, so that the result
+// has the type of s, such as IDs, and not merely []E.
+func CollectInto[S ~[]E, E any](s S, seq iter.Seq[E]) S {
+	for v := range seq {
+		s = append(s, v)
+	}
+
+	return s
+}
+
+// collectSlice is CollectInto without the S parameter. Its result is an []E,
+// which loses the methods of IDs.
+func collectSlice[E any](s []E, seq iter.Seq[E]) []E {
+	for v := range seq {
+		s = append(s, v)
+	}
+
+	return s
+}
+
+// Zero returns the zero value of T. T only appears in the result, so there
+// is nothing to infer it from, and it has to be given explicitly.
+func Zero[T any]() T {
+	var zero T
+	return zero
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 5, Seed: 1})
+	db.Seed(cfg.Seed)
+
+	courses := db.GenerateCourses(cfg.Count)
+
+	fmt.Println("inferred from the arguments:")
+	// T is db.Course from the type of courses, and U is string from the
+	// result of the function
+	names := itertools.Map(courses, func(c db.Course) string { return c.Name })
+	fmt.Printf("  itertools.Map    %T\n", names)
+
+	// U is inferred from strconv.Itoa, a function value like any other
+	labels := itertools.Map(slices.Values([]int{1, 2}), strconv.Itoa)
+	fmt.Printf("  strconv.Itoa     %T\n", labels)
+
+	fmt.Println("\ninferred from a constraint:")
+	ids := itertools.Map(courses, func(c db.Course) int { return c.ID })
+	fmt.Printf("  CollectInto      %T, %v\n", CollectInto(IDs{}, ids), CollectInto(IDs{}, ids))
+	fmt.Printf("  collectSlice     %T, %v\n", collectSlice(IDs{}, ids), collectSlice(IDs{}, ids))
+
+	fmt.Println("\ngiven explicitly:")
+	fmt.Printf("  Zero[int]()      %v\n", Zero[int]())
+	fmt.Printf("  Zero[string]()   %q\n", Zero[string]())
+	// The first type arguments can be given, and the rest inferred. U is
+	// still inferred from the function.
+	universities := itertools.Map[db.Course](courses, func(c db.Course) string { return c.University })
+	fmt.Printf("  Map[db.Course]   %T, %v\n", universities, slices.Collect(universities))
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"cmp"
+	"iter"
+)
+
+// Heap is a binary heap of values of T. The value for which less reports
+// that it is less than every other value is at the top, so a heap ordered by
+// cmp.Less pops its values from the smallest to the largest.
+//
+// Unlike container/heap, whose values are of type any and which calls the
+// methods of an interface the caller implements, a Heap holds its values
+// in a []T, and needs nothing but less.
+type Heap[T any] struct {
+	values []T
+	less   func(a, b T) bool
+}
+
+// NewHeap returns an empty heap ordered by less
+func NewHeap[T any](less func(a, b T) bool) *Heap[T] {
+	return &Heap[T]{less: less}
+}
+
+// NewOrderedHeap returns an empty heap of a type with a natural order, which
+// pops its values from the smallest to the largest
+func NewOrderedHeap[T cmp.Ordered]() *Heap[T] {
+	return NewHeap(cmp.Less[T])
+}
+
+// Len returns the number of values in the heap
+func (h *Heap[T]) Len() int {
+	return len(h.values)
+}
+
+// Push adds v to the heap
+func (h *Heap[T]) Push(v T) {
+	h.values = append(h.values, v)
+	h.up(len(h.values) - 1)
+}
+
+// Peek returns the value at the top of the heap without removing it, and
+// false if the heap is empty
+func (h *Heap[T]) Peek() (T, bool) {
+	if len(h.values) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return h.values[0], true
+}
+
+// Pop removes the value at the top of the heap and returns it, and false if
+// the heap is empty
+func (h *Heap[T]) Pop() (T, bool) {
+	top, ok := h.Peek()
+	if !ok {
+		return top, false
+	}
+
+	last := len(h.values) - 1
+	h.values[0] = h.values[last]
+
+	// Clear the slot, so that the heap does not keep what it refers to alive
+	var zero T
+	h.values[last] = zero
+	h.values = h.values[:last]
+
+	h.down(0)
+
+	return top, true
+}
+
+// Drain returns an iterator which pops the values of the heap in order until
+// it is empty. Breaking out of the loop leaves the values which were not
+// popped in the heap.
+func (h *Heap[T]) Drain() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			v, ok := h.Pop()
+			if !ok || !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// up moves the value at i up until its parent is not greater than it
+func (h *Heap[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(h.values[i], h.values[parent]) {
+			return
+		}
+
+		h.values[i], h.values[parent] = h.values[parent], h.values[i]
+		i = parent
+	}
+}
+
+// down moves the value at i down until neither of its children is less than
+// it
+func (h *Heap[T]) down(i int) {
+	for {
+		smallest := i
+		for _, child := range []int{2*i + 1, 2*i + 2} {
+			if child < len(h.values) && h.less(h.values[child], h.values[smallest]) {
+				smallest = child
+			}
+		}
+
+		if smallest == i {
+			return
+		}
+
+		h.values[i], h.values[smallest] = h.values[smallest], h.values[i]
+		i = smallest
+	}
+}
+
+// Merge returns an iterator which merges sequences which are each sorted by
+// less into a single sorted sequence. The heap holds the next value of each
+// sequence, so only one value per sequence is held at a time, however long
+// they are.
+//
+// It is the teaching version of itertools.MergeSorted, which the other
+// lessons use, and which also keeps equal values in the order of their
+// sequences.
+func Merge[T any](less func(a, b T) bool, seqs ...iter.Seq[T]) iter.Seq[T] {
+	// head is the next value of the sequence with the given index
+	type head struct {
+		value T
+		seq   int
+	}
+
+	return func(yield func(T) bool) {
+		nexts := make([]func() (T, bool), len(seqs))
+		h := NewHeap(func(a, b head) bool { return less(a.value, b.value) })
+
+		for i, seq := range seqs {
+			next, stop := iter.Pull(seq)
+			defer stop()
+
+			nexts[i] = next
+			if v, ok := next(); ok {
+				h.Push(head{v, i})
+			}
+		}
+
+		for h.Len() > 0 {
+			top, _ := h.Pop()
+			if !yield(top.value) {
+				return
+			}
+
+			if v, ok := nexts[top.seq](); ok {
+				h.Push(head{v, top.seq})
+			}
+		}
+	}
+}
+
+// TopK returns the k greatest values of seq by less, from the greatest down.
+// The heap holds the k greatest values seen so far, with the least of them
+// at the top, ready to be replaced by a greater one.
+//
+// It is the teaching version of itertools.TopK, which the other lessons use.
+func TopK[T any](seq iter.Seq[T], k int, less func(a, b T) bool) []T {
+	h := NewHeap(less)
+	for v := range seq {
+		if h.Len() < k {
+			h.Push(v)
+			continue
+		}
+
+		if top, ok := h.Peek(); ok && less(top, v) {
+			h.Pop()
+			h.Push(v)
+		}
+	}
+
+	// The heap pops from the least, so the values are filled in from the
+	// back
+	top := make([]T, h.Len())
+	for i := len(top) - 1; i >= 0; i-- {
+		top[i], _ = h.Pop()
+	}
+
+	return top
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// byID orders courses by their ID
+func byID(a, b db.Course) bool {
+	return a.ID < b.ID
+}
+
+// atUniversity yields the courses of courses which belong to university
+func atUniversity(courses []db.Course, university string) iter.Seq[db.Course] {
+	return func(yield func(db.Course) bool) {
+		for _, course := range courses {
+			if course.University == university && !yield(course) {
+				return
+			}
+		}
+	}
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 10, Seed: 1})
+	db.Seed(cfg.Seed)
+
+	courses := slices.Collect(db.GenerateCourses(cfg.Count))
+
+	fmt.Println("NewOrderedHeap[int]:")
+	numbers := NewOrderedHeap[int]()
+	for _, n := range []int{5, 2, 8, 1, 9, 3} {
+		numbers.Push(n)
+	}
+	fmt.Printf("  %v\n", slices.Collect(numbers.Drain()))
+
+	// The courses of each university are sorted by ID, like the rows of a
+	// table sharded by university. Merge puts them back in order, holding
+	// a single course per university at a time.
+	fmt.Println("\nMerge:")
+	var shards []iter.Seq[db.Course]
+	for _, university := range db.Universities() {
+		shard := atUniversity(courses, university)
+		shards = append(shards, shard)
+
+		var ids []int
+		for course := range shard {
+			ids = append(ids, course.ID)
+		}
+		fmt.Printf("  %-5s %v\n", university, ids)
+	}
+
+	var merged []int
+	for course := range Merge(byID, shards...) {
+		merged = append(merged, course.ID)
+	}
+	fmt.Printf("  merged %v\n", merged)
+
+	// The same heap, ordered by a different less, finds the courses with
+	// the greatest IDs without sorting all of them
+	fmt.Println("\nTopK:")
+	for _, course := range TopK(slices.Values(courses), 3, byID) {
+		fmt.Printf("  %2d %-5s %s\n", course.ID, course.University, course.Name)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"cmp"
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+	"github.com/manedurphy/golang-university/internal/testutil"
+)
+
+func TestHeapSorts(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+
+	for range 100 {
+		values := make([]int, r.IntN(50))
+		for i := range values {
+			values[i] = r.IntN(20)
+		}
+
+		h := NewOrderedHeap[int]()
+		for _, v := range values {
+			h.Push(v)
+		}
+
+		got := slices.Collect(h.Drain())
+		if expected := slices.Sorted(slices.Values(values)); !slices.Equal(got, expected) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	}
+}
+
+func TestHeapEmpty(t *testing.T) {
+	h := NewHeap(func(a, b string) bool { return a > b })
+
+	if _, ok := h.Pop(); ok {
+		t.Fatal("expected an empty heap to have nothing to pop")
+	}
+
+	h.Push("a")
+	h.Push("c")
+	h.Push("b")
+
+	if top, _ := h.Peek(); top != "c" || h.Len() != 3 {
+		t.Fatalf("expected c at the top of 3 values, got %q of %d", top, h.Len())
+	}
+}
+
+func TestHeapDrainBreak(t *testing.T) {
+	h := NewOrderedHeap[int]()
+	for _, v := range []int{3, 1, 2} {
+		h.Push(v)
+	}
+
+	for range h.Drain() {
+		break
+	}
+
+	if got := slices.Collect(h.Drain()); !slices.Equal(got, []int{2, 3}) {
+		t.Fatalf("expected the values which were not popped to be left, got %v", got)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	seqtest.AssertYields(t, Merge(cmp.Less[int],
+		slices.Values([]int{1, 4, 7}),
+		slices.Values([]int{2, 5}),
+		slices.Values([]int(nil)),
+		slices.Values([]int{3, 6, 8, 9}),
+	), []int{1, 2, 3, 4, 5, 6, 7, 8, 9})
+}
+
+func TestMergeStops(t *testing.T) {
+	testutil.RequireStopped(t)
+
+	seqtest.AssertStopsEarly(t, 3, func() iter.Seq[int] {
+		return Merge(cmp.Less[int], slices.Values([]int{1, 3, 5}), slices.Values([]int{2, 4, 6}))
+	})
+}
+
+func TestTopK(t *testing.T) {
+	values := slices.Values([]int{5, 2, 8, 1, 9, 3})
+
+	if got := TopK(values, 3, cmp.Less[int]); !slices.Equal(got, []int{9, 8, 5}) {
+		t.Fatalf("expected [9 8 5], got %v", got)
+	}
+
+	if got := TopK(values, 10, cmp.Less[int]); len(got) != 6 {
+		t.Fatalf("expected every value when k is larger than seq, got %v", got)
+	}
+
+	if got := TopK(values, 0, cmp.Less[int]); len(got) != 0 {
+		t.Fatalf("expected no values for k of 0, got %v", got)
+	}
+}
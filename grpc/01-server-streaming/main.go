@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/manedurphy/golang-university/grpc/courses"
+	"github.com/manedurphy/golang-university/grpc/coursespb"
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+var serve bool
+
+func init() {
+	flag.BoolVar(&serve, "serve", false, "Run the server instead of the client, which is how the client starts it")
+}
+
+// runServer seeds a database and serves its courses on a random port, which
+// it prints to stdout for the client. It stops once its stdin is closed,
+// which happens when the client exits.
+func runServer(cfg lessoncfg.Config) error {
+	dir, err := os.MkdirTemp(cfg.DataDir, "university-grpc-")
+	if err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	coursesDB, err := db.New(dir)
+	if err != nil {
+		return err
+	}
+	defer coursesDB.Close()
+
+	err = coursesDB.Seed(cfg.Count)
+	if err != nil {
+		return err
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	// Log how each call ended, which shows the client's cancellation
+	// arriving in the other process
+	srv := grpc.NewServer(grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		fmt.Fprintf(os.Stderr, "server: %s returned: %v\n", info.FullMethod, err)
+		return err
+	}))
+	coursespb.RegisterCourseServiceServer(srv, courses.NewServer(coursesDB))
+
+	go func() {
+		io.Copy(io.Discard, os.Stdin)
+		srv.GracefulStop()
+	}()
+
+	fmt.Println(lis.Addr())
+
+	return srv.Serve(lis)
+}
+
+// startServer starts this program again as the server, and returns the
+// address it listens on. Closing the returned writer stops the server.
+func startServer(cfg lessoncfg.Config) (string, *exec.Cmd, io.WriteCloser, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	cmd := exec.Command(exe, "-serve", "-count", strconv.Itoa(cfg.Count), "-seed", strconv.FormatUint(cfg.Seed, 10), "-data-dir", cfg.DataDir)
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to start server: %w", err)
+	}
+
+	addr, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		stdin.Close()
+		cmd.Wait()
+		return "", nil, nil, fmt.Errorf("server exited before listening: %w", err)
+	}
+
+	return strings.TrimSpace(addr), cmd, stdin, nil
+}
+
+func runClient(cfg lessoncfg.Config) error {
+	addr, server, stop, err := startServer(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		stop.Close()
+		server.Wait()
+	}()
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	defer conn.Close()
+
+	client := coursespb.NewCourseServiceClient(conn)
+	ctx := context.Background()
+
+	// The courses come from another process, but the loop is the same as
+	// the one over a local database. Breaking cancels the call.
+	for course, err := range courses.ListCourses(ctx, client, 0) {
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("received course %d: %s at %s\n", course.ID, course.Name, course.University)
+
+		if course.ID == 3 {
+			fmt.Println("breaking out of the loop")
+			break
+		}
+	}
+
+	// The limit is applied by the server, so it never sends more
+	n := 0
+	for _, err := range courses.ListCourses(ctx, client, 100) {
+		if err != nil {
+			return err
+		}
+
+		n++
+	}
+	fmt.Printf("received %d courses with a limit of 100\n", n)
+
+	n = 0
+	for _, err := range courses.ListCourses(ctx, client, 0) {
+		if err != nil {
+			return err
+		}
+
+		n++
+	}
+	fmt.Printf("received all %d courses\n", n)
+
+	return nil
+}
+
+func main() {
+	// -count is the number of courses the server seeds its database with
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 100000, DataDir: os.TempDir()})
+
+	run := runClient
+	if serve {
+		run = runServer
+	}
+
+	err := run(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,116 @@
+package courses
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/manedurphy/golang-university/grpc/coursespb"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// Server implements the CourseService on top of a CoursesDB
+type Server struct {
+	coursespb.UnimplementedCourseServiceServer
+
+	db db.CoursesDB
+}
+
+// NewServer returns a CourseService which serves the courses of the database
+func NewServer(coursesDB db.CoursesDB) *Server {
+	return &Server{db: coursesDB}
+}
+
+// ListCourses sends every course the database's iterator yields as its own
+// message. Send fails once the client has cancelled the call, and returning
+// breaks out of the iterator, which closes the database's rows. The first
+// error from the database stops the stream.
+func (s *Server) ListCourses(req *coursespb.ListCoursesRequest, stream grpc.ServerStreamingServer[coursespb.Course]) error {
+	var sent int64
+
+	for course, err := range s.db.GetCourses() {
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to get course: %v", err)
+		}
+
+		if req.GetLimit() > 0 && sent == req.GetLimit() {
+			break
+		}
+
+		err = stream.Send(ToProto(course))
+		if err != nil {
+			return err
+		}
+
+		sent++
+	}
+
+	return nil
+}
+
+// Recv returns an iterator over the messages of a server stream. The end of
+// the stream ends the iteration, and any other error is yielded along with a
+// nil message. Breaking out of the loop does not end the call, which is what
+// cancelling its context is for.
+func Recv[T any](stream grpc.ServerStreamingClient[T]) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		for {
+			msg, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if !yield(msg, nil) {
+				return
+			}
+		}
+	}
+}
+
+// ListCourses calls the ListCourses RPC and returns an iterator over the
+// courses it streams, so a consumer ranges over a remote database the same
+// way as over a local one. Breaking out of the loop cancels the call, which
+// stops the server from sending more courses. A limit of zero streams every
+// course.
+func ListCourses(ctx context.Context, client coursespb.CourseServiceClient, limit int64) iter.Seq2[db.Course, error] {
+	return func(yield func(db.Course, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		stream, err := client.ListCourses(ctx, &coursespb.ListCoursesRequest{Limit: limit})
+		if err != nil {
+			yield(db.Course{}, err)
+			return
+		}
+
+		for msg, err := range Recv(stream) {
+			if err != nil {
+				yield(db.Course{}, err)
+				return
+			}
+
+			if !yield(FromProto(msg), nil) {
+				return
+			}
+		}
+	}
+}
+
+// ToProto converts a course of the database to its message
+func ToProto(c db.Course) *coursespb.Course {
+	return &coursespb.Course{Id: int64(c.ID), Name: c.Name, University: c.University}
+}
+
+// FromProto converts a message to a course of the database
+func FromProto(c *coursespb.Course) db.Course {
+	return db.Course{ID: int(c.GetId()), Name: c.GetName(), University: c.GetUniversity()}
+}
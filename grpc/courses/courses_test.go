@@ -0,0 +1,120 @@
+package courses
+
+import (
+	"context"
+	"iter"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/manedurphy/golang-university/grpc/coursespb"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// infiniteDB yields courses until the consumer stops, and closes stopped
+// once it has
+type infiniteDB struct {
+	db.CoursesDB
+
+	stopped chan struct{}
+}
+
+func (d *infiniteDB) GetCourses() iter.Seq2[db.Course, error] {
+	return func(yield func(db.Course, error) bool) {
+		defer close(d.stopped)
+
+		for id := 1; ; id++ {
+			if !yield(db.Course{ID: id, Name: "Chem-1", University: "SJSU"}, nil) {
+				return
+			}
+		}
+	}
+}
+
+// newClient serves the database over an in-memory connection
+func newClient(t *testing.T, coursesDB db.CoursesDB) coursespb.CourseServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+
+	srv := grpc.NewServer()
+	coursespb.RegisterCourseServiceServer(srv, NewServer(coursesDB))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return coursespb.NewCourseServiceClient(conn)
+}
+
+func TestListCourses(t *testing.T) {
+	coursesDB, err := db.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer coursesDB.Close()
+
+	err = coursesDB.Seed(25)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := newClient(t, coursesDB)
+
+	for _, test := range []struct {
+		limit    int64
+		expected int
+	}{
+		{limit: 0, expected: 25},
+		{limit: 10, expected: 10},
+	} {
+		n := 0
+		for course, err := range ListCourses(context.Background(), client, test.limit) {
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			n++
+			if course.ID != n || course.Name == "" || course.University == "" {
+				t.Fatalf("expected course %d, got %+v", n, course)
+			}
+		}
+
+		if n != test.expected {
+			t.Fatalf("expected %d courses with a limit of %d, got %d", test.expected, test.limit, n)
+		}
+	}
+}
+
+func TestListCoursesBreak(t *testing.T) {
+	coursesDB := &infiniteDB{stopped: make(chan struct{})}
+	client := newClient(t, coursesDB)
+
+	for course, err := range ListCourses(context.Background(), client, 0) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if course.ID == 3 {
+			break
+		}
+	}
+
+	// Breaking cancels the call, which stops the server's iterator
+	select {
+	case <-coursesDB.stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the server to stop iterating once the client broke out of its loop")
+	}
+}
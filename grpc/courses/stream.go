@@ -0,0 +1,64 @@
+package courses
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+
+	"github.com/manedurphy/golang-university/grpc/coursespb"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// WriteStream writes the courses to w as a stream of records, each of which
+// is the length of a Course message as a varint followed by the message
+// itself. The length is what lets a reader find where one message ends and
+// the next begins, since protobuf messages do not mark their own end.
+func WriteStream(w io.Writer, courses iter.Seq[db.Course]) error {
+	bw := bufio.NewWriter(w)
+
+	// A single message is reused for every course, so writing allocates
+	// nothing per course
+	msg := &coursespb.Course{}
+
+	for course := range courses {
+		msg.Id, msg.Name, msg.University = int64(course.ID), course.Name, course.University
+
+		_, err := protodelim.MarshalTo(bw, msg)
+		if err != nil {
+			return fmt.Errorf("failed to write course %d: %w", course.ID, err)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ReadStream returns an iterator over the courses of a stream written by
+// WriteStream. Reading stops at the first error, such as a stream which ends
+// in the middle of a record, which is yielded along with an empty course.
+func ReadStream(r io.Reader) iter.Seq2[db.Course, error] {
+	return func(yield func(db.Course, error) bool) {
+		br := bufio.NewReader(r)
+		msg := &coursespb.Course{}
+
+		for i := 0; ; i++ {
+			// Unmarshalling resets the message before it decodes the next
+			// one into it
+			err := protodelim.UnmarshalFrom(br, msg)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(db.Course{}, fmt.Errorf("failed to read record %d: %w", i, err))
+				return
+			}
+
+			if !yield(FromProto(msg), nil) {
+				return
+			}
+		}
+	}
+}
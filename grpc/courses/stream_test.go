@@ -0,0 +1,169 @@
+package courses
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/jsonseq"
+)
+
+// benchCourses is the number of courses every benchmark writes or reads
+const benchCourses = 1000000
+
+func TestStream(t *testing.T) {
+	expected := slices.Collect(db.GenerateCourses(1000))
+
+	var buf bytes.Buffer
+	err := WriteStream(&buf, slices.Values(expected))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var courses []db.Course
+	for course, err := range ReadStream(&buf) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		courses = append(courses, course)
+	}
+
+	if !slices.Equal(courses, expected) {
+		t.Fatalf("expected %d courses back, got %d", len(expected), len(courses))
+	}
+}
+
+func TestStreamTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteStream(&buf, db.GenerateCourses(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var n int
+	for _, err = range ReadStream(bytes.NewReader(buf.Bytes()[:buf.Len()-1])) {
+		if err != nil {
+			break
+		}
+
+		n++
+	}
+
+	if !errors.Is(err, io.ErrUnexpectedEOF) || n != 2 {
+		t.Fatalf("expected io.ErrUnexpectedEOF after 2 courses, got %d courses and %v", n, err)
+	}
+}
+
+// encodings write and read the same courses in each format
+var encodings = []struct {
+	name  string
+	write func(io.Writer) error
+	read  func(io.Reader) error
+}{
+	{
+		name: "proto",
+		write: func(w io.Writer) error {
+			return WriteStream(w, db.GenerateCourses(benchCourses))
+		},
+		read: func(r io.Reader) error {
+			for _, err := range ReadStream(r) {
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		name: "json",
+		write: func(w io.Writer) error {
+			return jsonseq.WriteLines(w, db.GenerateCourses(benchCourses))
+		},
+		read: func(r io.Reader) error {
+			for _, err := range jsonseq.Lines[db.Course](r) {
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		// A gob stream describes its types once, before the first value
+		name: "gob",
+		write: func(w io.Writer) error {
+			enc := gob.NewEncoder(w)
+			for course := range db.GenerateCourses(benchCourses) {
+				err := enc.Encode(course)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		read: func(r io.Reader) error {
+			dec := gob.NewDecoder(r)
+			for {
+				var course db.Course
+
+				err := dec.Decode(&course)
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+			}
+		},
+	},
+}
+
+func BenchmarkWrite(b *testing.B) {
+	for _, enc := range encodings {
+		b.Run(enc.name, func(b *testing.B) {
+			var buf bytes.Buffer
+
+			for range b.N {
+				buf.Reset()
+
+				err := enc.write(&buf)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.ReportMetric(float64(buf.Len())/benchCourses, "B/record")
+		})
+	}
+}
+
+func BenchmarkRead(b *testing.B) {
+	for _, enc := range encodings {
+		b.Run(enc.name, func(b *testing.B) {
+			var buf bytes.Buffer
+
+			err := enc.write(&buf)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			data := buf.Bytes()
+			b.ResetTimer()
+
+			for range b.N {
+				err = enc.read(bytes.NewReader(data))
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
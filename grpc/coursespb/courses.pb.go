@@ -0,0 +1,197 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: grpc/coursespb/courses.proto
+
+package coursespb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Course is a course offered by a university
+type Course struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	University    string                 `protobuf:"bytes,3,opt,name=university,proto3" json:"university,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Course) Reset() {
+	*x = Course{}
+	mi := &file_grpc_coursespb_courses_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Course) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Course) ProtoMessage() {}
+
+func (x *Course) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_coursespb_courses_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Course.ProtoReflect.Descriptor instead.
+func (*Course) Descriptor() ([]byte, []int) {
+	return file_grpc_coursespb_courses_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Course) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Course) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Course) GetUniversity() string {
+	if x != nil {
+		return x.University
+	}
+	return ""
+}
+
+// ListCoursesRequest selects the courses to stream
+type ListCoursesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Limit is the largest number of courses to stream, or zero to stream
+	// every course
+	Limit         int64 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCoursesRequest) Reset() {
+	*x = ListCoursesRequest{}
+	mi := &file_grpc_coursespb_courses_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCoursesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCoursesRequest) ProtoMessage() {}
+
+func (x *ListCoursesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_coursespb_courses_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCoursesRequest.ProtoReflect.Descriptor instead.
+func (*ListCoursesRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_coursespb_courses_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListCoursesRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+var File_grpc_coursespb_courses_proto protoreflect.FileDescriptor
+
+const file_grpc_coursespb_courses_proto_rawDesc = "" +
+	"\n" +
+	"\x1cgrpc/coursespb/courses.proto\x12\x15university.courses.v1\"L\n" +
+	"\x06Course\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"university\x18\x03 \x01(\tR\n" +
+	"university\"*\n" +
+	"\x12ListCoursesRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x03R\x05limit2j\n" +
+	"\rCourseService\x12Y\n" +
+	"\vListCourses\x12).university.courses.v1.ListCoursesRequest\x1a\x1d.university.courses.v1.Course0\x01B8Z6github.com/manedurphy/golang-university/grpc/coursespbb\x06proto3"
+
+var (
+	file_grpc_coursespb_courses_proto_rawDescOnce sync.Once
+	file_grpc_coursespb_courses_proto_rawDescData []byte
+)
+
+func file_grpc_coursespb_courses_proto_rawDescGZIP() []byte {
+	file_grpc_coursespb_courses_proto_rawDescOnce.Do(func() {
+		file_grpc_coursespb_courses_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_grpc_coursespb_courses_proto_rawDesc), len(file_grpc_coursespb_courses_proto_rawDesc)))
+	})
+	return file_grpc_coursespb_courses_proto_rawDescData
+}
+
+var file_grpc_coursespb_courses_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_grpc_coursespb_courses_proto_goTypes = []any{
+	(*Course)(nil),             // 0: university.courses.v1.Course
+	(*ListCoursesRequest)(nil), // 1: university.courses.v1.ListCoursesRequest
+}
+var file_grpc_coursespb_courses_proto_depIdxs = []int32{
+	1, // 0: university.courses.v1.CourseService.ListCourses:input_type -> university.courses.v1.ListCoursesRequest
+	0, // 1: university.courses.v1.CourseService.ListCourses:output_type -> university.courses.v1.Course
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_grpc_coursespb_courses_proto_init() }
+func file_grpc_coursespb_courses_proto_init() {
+	if File_grpc_coursespb_courses_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_grpc_coursespb_courses_proto_rawDesc), len(file_grpc_coursespb_courses_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_grpc_coursespb_courses_proto_goTypes,
+		DependencyIndexes: file_grpc_coursespb_courses_proto_depIdxs,
+		MessageInfos:      file_grpc_coursespb_courses_proto_msgTypes,
+	}.Build()
+	File_grpc_coursespb_courses_proto = out.File
+	file_grpc_coursespb_courses_proto_goTypes = nil
+	file_grpc_coursespb_courses_proto_depIdxs = nil
+}
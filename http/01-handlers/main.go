@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// counter is a Handler with state of its own. A server calls ServeHTTP from
+// a goroutine per connection, so the state is shared between them.
+type counter struct {
+	n atomic.Int64
+}
+
+func (c *counter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintf(w, "request %d\n", c.n.Add(1))
+}
+
+// getCourse is a plain function, which http.HandlerFunc turns into a
+// Handler. The headers are set before the status, which sends them.
+func getCourse(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(db.Course{ID: 1, Name: "Chem-1", University: "UCB"})
+}
+
+// lateHeader sets its headers after the status was sent with the first
+// write, so they are never sent
+func lateHeader(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintln(w, "created")
+	w.Header().Set("Location", "/courses/1")
+	w.WriteHeader(http.StatusCreated)
+}
+
+// notFound uses http.Error, which sets the status and writes the message as
+// plain text
+func notFound(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, "course not found", http.StatusNotFound)
+}
+
+// show calls h with a request for path, and prints the response it wrote.
+// Result returns the headers as they were when the status was sent, like a
+// client would receive them.
+func show(name string, h http.Handler, path string) {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	resp := rec.Result()
+
+	fmt.Printf("%s:\n", name)
+	fmt.Printf("  %s\n", resp.Status)
+	for _, key := range []string{"Content-Type", "Location"} {
+		if v := resp.Header.Get(key); v != "" {
+			fmt.Printf("  %s: %s\n", key, v)
+		}
+	}
+	fmt.Printf("  %s\n", strings.TrimSpace(rec.Body.String()))
+}
+
+func main() {
+	c := &counter{}
+	show("counter", c, "/")
+	show("counter", c, "/")
+
+	show("getCourse", http.HandlerFunc(getCourse), "/courses/1")
+	show("lateHeader", http.HandlerFunc(lateHeader), "/courses")
+	show("notFound", http.HandlerFunc(notFound), "/courses/1000")
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+
+	"github.com/manedurphy/golang-university/http/coursesapi"
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// patterns returns a mux whose handlers write the pattern which matched,
+// along with the wildcards of the request
+func patterns() *http.ServeMux {
+	mux := http.NewServeMux()
+
+	for _, pattern := range []string{
+		// A trailing slash matches every path below it
+		"/courses/",
+		// {id} matches a single segment of the path, and is more specific
+		// than /courses/, so it wins for the paths both match
+		"/courses/{id}",
+		// A literal segment is more specific than a wildcard
+		"GET /courses/new",
+		// A method is more specific than none
+		"POST /courses/{id}",
+		// {path...} matches the rest of the path, slashes and all
+		"/files/{path...}",
+		// A host only matches requests for that host
+		"api.example.com/courses/{id}",
+	} {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "%q", r.Pattern)
+			for _, name := range []string{"id", "path"} {
+				if v := r.PathValue(name); v != "" {
+					fmt.Fprintf(w, " %s=%s", name, v)
+				}
+			}
+		})
+	}
+
+	return mux
+}
+
+// register registers the patterns on a new mux, and returns the panic of
+// the first one which conflicts with another, which matches some of the
+// same requests without either being more specific
+func register(patterns ...string) (err any) {
+	defer func() { err = recover() }()
+
+	mux := http.NewServeMux()
+	for _, pattern := range patterns {
+		mux.HandleFunc(pattern, func(http.ResponseWriter, *http.Request) {})
+	}
+
+	return nil
+}
+
+// serve calls h with a request, and returns the status and the first line of
+// the body it wrote
+func serve(h http.Handler, req *http.Request) (int, string) {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	body, _ := io.ReadAll(rec.Body)
+	line, _, _ := strings.Cut(string(body), "\n")
+
+	return rec.Code, line
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 10, DataDir: os.TempDir(), Seed: 1})
+	db.Seed(cfg.Seed)
+
+	fmt.Println("patterns:")
+	mux := patterns()
+	for _, target := range []string{
+		"GET /courses/",
+		"GET /courses/7",
+		"GET /courses/new",
+		"POST /courses/7",
+		"GET /courses/7/students",
+		"GET /files/syllabus/chem-1.pdf",
+		"GET http://api.example.com/courses/7",
+	} {
+		method, url, _ := strings.Cut(target, " ")
+		code, line := serve(mux, httptest.NewRequest(method, url, nil))
+		fmt.Printf("  %-38s %d %s\n", target, code, line)
+	}
+
+	// Neither pattern is more specific than the other, so which of them
+	// should match is found out when the mux is built, instead of by a
+	// request
+	fmt.Println("\nconflicts:")
+	for _, conflict := range [][]string{
+		{"/courses/{id}", "/courses/{name}"},
+		{"POST /courses/{id}", "/courses/new"},
+	} {
+		// The panic names the files the patterns were registered at, and
+		// explains the conflict on its last line
+		msg := fmt.Sprint(register(conflict...))
+		fmt.Printf("  %q and %q:\n    %s\n", conflict[0], conflict[1], strings.TrimSpace(msg[strings.LastIndex(msg, "\n")+1:]))
+	}
+
+	coursesDB, err := db.New(cfg.DataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create database: %v\n", err)
+		os.Exit(1)
+	}
+	defer coursesDB.Close()
+
+	err = coursesDB.Seed(cfg.Count)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to seed database: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\ncourses API:")
+	api := coursesapi.NewServer(coursesDB).Routes()
+	for _, target := range []string{
+		"GET /",
+		"GET /courses",
+		"GET /courses/3",
+		"GET /courses/1000",
+		"GET /courses/three",
+		"GET /universities",
+		"GET /universities/UCB/courses",
+		"GET /universities/MIT/courses",
+		"DELETE /courses/3",
+		"GET /students",
+	} {
+		method, url, _ := strings.Cut(target, " ")
+		code, line := serve(api, httptest.NewRequest(method, url, nil))
+		fmt.Printf("  %-30s %d %s\n", target, code, line)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/manedurphy/golang-university/http/coursesapi"
+	"github.com/manedurphy/golang-university/http/middleware"
+	"github.com/manedurphy/golang-university/internal/clock"
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// routes adds a route which panics to the routes of the API
+func routes(api http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/", api)
+	mux.HandleFunc("GET /boom", func(w http.ResponseWriter, r *http.Request) {
+		var courses map[int]db.Course
+		courses[1] = db.Course{}
+	})
+
+	return mux
+}
+
+// newHandler wraps h in the middleware of the API. Logging is the outermost,
+// so it logs every response, including the 500 of Recovery and the 429 of
+// RateLimit, and the time spent in both.
+func newHandler(h http.Handler, logger *slog.Logger, c clock.Clock) http.Handler {
+	return middleware.Chain(
+		middleware.LoggingOn(c, logger),
+		middleware.Recovery(logger),
+		middleware.RateLimitOn(c, 1, 3),
+	)(h)
+}
+
+// newLogger returns a logger which writes lines without their time to
+// stdout
+func newLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))
+}
+
+// get sends a request for path to h, from the same client every time, and
+// prints its status, after whatever the middleware logged
+func get(h http.Handler, path string) {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	fmt.Printf("  GET %s: %d %s\n", path, rec.Code, http.StatusText(rec.Code))
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 10, DataDir: os.TempDir(), Seed: 1})
+	db.Seed(cfg.Seed)
+
+	coursesDB, err := db.New(cfg.DataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create database: %v\n", err)
+		os.Exit(1)
+	}
+	defer coursesDB.Close()
+
+	err = coursesDB.Seed(cfg.Count)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to seed database: %v\n", err)
+		os.Exit(1)
+	}
+
+	logger := newLogger()
+	h := routes(coursesapi.NewServer(coursesDB).Routes())
+
+	// The fake clock makes the rate limit the same on every run. The client
+	// has a burst of three requests, and gets another one every second.
+	fmt.Println("Logging, Recovery, RateLimit:")
+	fake := clock.NewFake(time.Now())
+	limited := newHandler(h, logger, fake)
+	for _, path := range []string{"/courses/3", "/boom", "/courses/4", "/courses/5"} {
+		get(limited, path)
+	}
+
+	fake.Advance(time.Second)
+	get(limited, "/courses/5")
+
+	// In the other order, the panic unwinds through Logging before Recovery
+	// stops it, so the request which failed is the one which is not logged
+	fmt.Println("\nRecovery, Logging:")
+	get(middleware.Chain(middleware.Recovery(logger), middleware.Logging(logger))(h), "/boom")
+
+	// Without Recovery, the panic reaches the server, which logs it with its
+	// stack, here to nowhere, and closes the connection without a response
+	fmt.Println("\nLogging:")
+	srv := httptest.NewUnstartedServer(middleware.Logging(logger)(h))
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.Start()
+	defer srv.Close()
+
+	_, err = srv.Client().Get(srv.URL + "/boom")
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	fmt.Printf("  GET /boom: %v\n", err)
+}
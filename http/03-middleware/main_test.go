@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/http/coursesapi"
+	"github.com/manedurphy/golang-university/internal/clock"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/04-database/db/dbtest"
+)
+
+func TestHandler(t *testing.T) {
+	var logs bytes.Buffer
+
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	api := coursesapi.NewServer(dbtest.NewFake(db.Course{ID: 1, Name: "Chem-1", University: "UCB"})).Routes()
+	h := newHandler(routes(api), slog.New(slog.NewTextHandler(&logs, nil)), fake)
+
+	// The panic counts against the rate limit like any other request
+	for _, tt := range []struct {
+		path   string
+		status int
+	}{
+		{"/courses/1", http.StatusOK},
+		{"/boom", http.StatusInternalServerError},
+		{"/courses/2", http.StatusNotFound},
+		{"/courses/1", http.StatusTooManyRequests},
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if rec.Code != tt.status {
+			t.Fatalf("GET %s: expected %d, got %d", tt.path, tt.status, rec.Code)
+		}
+	}
+
+	for _, expected := range []string{"status=500", "status=429", `error="assignment to entry in nil map"`} {
+		if !strings.Contains(logs.String(), expected) {
+			t.Errorf("expected the logs to contain %s, got:\n%s", expected, logs.String())
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package coursesapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// Server serves the courses of a CoursesDB over HTTP
+type Server struct {
+	db db.CoursesDB
+}
+
+// NewServer returns a Server for the courses of the database
+func NewServer(coursesDB db.CoursesDB) *Server {
+	return &Server{db: coursesDB}
+}
+
+// Routes returns the handler of the API. The method of each pattern only
+// matches requests with that method, and GET also matches HEAD. A request for
+// a path with a pattern, but with another method, gets 405 Method Not Allowed
+// with the allowed methods in its Allow header.
+//
+//	GET /{$}                               the routes of the API
+//	GET /courses                           every course, as JSON lines
+//	GET /courses/{id}                      a single course
+//	GET /universities                      the universities
+//	GET /universities/{university}/courses the courses of a university
+func (s *Server) Routes() http.Handler {
+	mux := http.NewServeMux()
+	// {$} matches the end of the path, so that / does not match every path
+	// which no other pattern matches
+	mux.HandleFunc("GET /{$}", s.index)
+	mux.HandleFunc("GET /courses", s.listCourses)
+	mux.HandleFunc("GET /courses/{id}", s.getCourse)
+	mux.HandleFunc("GET /universities", s.listUniversities)
+	mux.HandleFunc("GET /universities/{university}/courses", s.listUniversityCourses)
+
+	return mux
+}
+
+func (s *Server) index(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintln(w, "GET /courses")
+	fmt.Fprintln(w, "GET /courses/{id}")
+	fmt.Fprintln(w, "GET /universities")
+	fmt.Fprintln(w, "GET /universities/{university}/courses")
+}
+
+func (s *Server) listCourses(w http.ResponseWriter, r *http.Request) {
+	s.streamCourses(w, func(db.Course) bool { return true })
+}
+
+func (s *Server) getCourse(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid course ID %q", r.PathValue("id")), http.StatusBadRequest)
+		return
+	}
+
+	course, err := s.db.GetCourse(r.Context(), id)
+	if errors.Is(err, db.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, course)
+}
+
+func (s *Server) listUniversities(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, db.Universities())
+}
+
+func (s *Server) listUniversityCourses(w http.ResponseWriter, r *http.Request) {
+	university := r.PathValue("university")
+	if !slices.Contains(db.Universities(), university) {
+		http.Error(w, fmt.Sprintf("university %q not found", university), http.StatusNotFound)
+		return
+	}
+
+	s.streamCourses(w, func(c db.Course) bool { return c.University == university })
+}
+
+// streamCourses writes the courses which keep returns true for as JSON
+// lines, straight from the database's iterator
+func (s *Server) streamCourses(w http.ResponseWriter, keep func(db.Course) bool) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+
+	for course, err := range s.db.GetCourses() {
+		if err != nil {
+			// The status may already have been sent, so the only way to tell the
+			// client that the response is incomplete is to abort it
+			log.Printf("failed to get course: %v", err)
+			panic(http.ErrAbortHandler)
+		}
+
+		if !keep(course) {
+			continue
+		}
+
+		// Writing fails once the client has gone away, which stops the
+		// database's iterator
+		err = enc.Encode(course)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,91 @@
+package coursesapi
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/04-database/db/dbtest"
+)
+
+func newFake() *dbtest.Fake {
+	return dbtest.NewFake(
+		db.Course{ID: 1, Name: "Chem-1", University: "UCB"},
+		db.Course{ID: 2, Name: "Physics-2", University: "SJSU"},
+		db.Course{ID: 3, Name: "Calculus-1", University: "UCB"},
+	)
+}
+
+func TestRoutes(t *testing.T) {
+	tests := []struct {
+		method, path string
+		status       int
+		body         string
+	}{
+		{"GET", "/", 200, "GET /courses\n"},
+		{"GET", "/nowhere", 404, "404 page not found\n"},
+		{"GET", "/courses", 200, `{"id":1,"name":"Chem-1","university":"UCB"}` + "\n" +
+			`{"id":2,"name":"Physics-2","university":"SJSU"}` + "\n" +
+			`{"id":3,"name":"Calculus-1","university":"UCB"}` + "\n"},
+		{"GET", "/courses/2", 200, `{"id":2,"name":"Physics-2","university":"SJSU"}` + "\n"},
+		{"GET", "/courses/4", 404, "course not found: course 4\n"},
+		{"GET", "/courses/two", 400, "invalid course ID \"two\"\n"},
+		{"GET", "/universities", 200, `["SJSU","SDSU","UCB","UCSF"]` + "\n"},
+		{"GET", "/universities/UCB/courses", 200, `{"id":1,"name":"Chem-1","university":"UCB"}` + "\n" +
+			`{"id":3,"name":"Calculus-1","university":"UCB"}` + "\n"},
+		{"GET", "/universities/MIT/courses", 404, "university \"MIT\" not found\n"},
+		{"POST", "/courses", 405, "Method Not Allowed\n"},
+	}
+
+	h := NewServer(newFake()).Routes()
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, rec.Code)
+			}
+
+			if !strings.HasPrefix(rec.Body.String(), tt.body) {
+				t.Fatalf("expected body %q, got %q", tt.body, rec.Body)
+			}
+		})
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewServer(newFake()).Routes().ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/courses/1", nil))
+
+	if got := rec.Header().Get("Allow"); got != "GET, HEAD" {
+		t.Fatalf("expected Allow: GET, HEAD, got %q", got)
+	}
+}
+
+func TestStreamAborted(t *testing.T) {
+	fake := newFake()
+	fake.Fail(2, errors.New("disk on fire"))
+
+	srv := httptest.NewServer(NewServer(fake).Routes())
+	defer srv.Close()
+
+	// The courses written before the failure may still be in the buffer of
+	// the response, in which case not even the status was sent. Either way,
+	// the response is cut short, and the client sees an error instead of
+	// fewer courses.
+	resp, err := srv.Client().Get(srv.URL + "/courses")
+	if err == nil {
+		_, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+
+	if err == nil {
+		t.Fatal("expected the response to be aborted")
+	}
+}
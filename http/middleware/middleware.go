@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/manedurphy/golang-university/concurrency/ratelimit"
+	"github.com/manedurphy/golang-university/internal/clock"
+)
+
+// Middleware wraps a handler in another, which does something before, after,
+// or instead of calling it
+type Middleware func(http.Handler) http.Handler
+
+// Chain returns a middleware which applies mws in order, so that the first
+// is the outermost: a request passes through them from first to last on its
+// way to the handler, and the response from last to first.
+func Chain(mws ...Middleware) Middleware {
+	return func(h http.Handler) http.Handler {
+		for i := len(mws) - 1; i >= 0; i-- {
+			h = mws[i](h)
+		}
+
+		return h
+	}
+}
+
+// responseWriter records the status and size of a response, which a
+// handler writes without returning them
+type responseWriter struct {
+	http.ResponseWriter
+
+	status int
+	size   int
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+
+	return n, err
+}
+
+// Unwrap returns the ResponseWriter it wraps, so that an
+// http.ResponseController can still flush a response through it
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// wrap returns w as a *responseWriter, without wrapping it again if an outer
+// middleware already has
+func wrap(w http.ResponseWriter) *responseWriter {
+	if rw, ok := w.(*responseWriter); ok {
+		return rw
+	}
+
+	return &responseWriter{ResponseWriter: w}
+}
+
+// Logging logs a line for each request once it has been handled, with its
+// status, the size of its body, and how long it took
+func Logging(logger *slog.Logger) Middleware {
+	return LoggingOn(clock.Real, logger)
+}
+
+// LoggingOn is like Logging, but times the requests by the time of c
+func LoggingOn(c clock.Clock, logger *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := c.Now()
+			rw := wrap(w)
+
+			next.ServeHTTP(rw, r)
+
+			// A handler which writes nothing sends a 200 once it returns
+			status := rw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			logger.Info("request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", status,
+				"size", rw.size,
+				"duration", c.Now().Sub(start),
+			)
+		})
+	}
+}
+
+// Recovery turns a panic of a handler into a 500 response, and logs it,
+// instead of letting net/http close the connection without one. A panic
+// with http.ErrAbortHandler is how a handler aborts a response on purpose,
+// so it is passed on. A response whose status was already sent can not be
+// turned into a 500, and is left as it is.
+func Recovery(logger *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := wrap(w)
+
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+
+				logger.Error("panic", "method", r.Method, "path", r.URL.Path, "error", fmt.Sprint(v))
+
+				if rw.status == 0 {
+					http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// RateLimit allows each client rate requests per second, with bursts of up
+// to burst requests, and answers any more with 429 Too Many Requests. A
+// client is told apart by the host of its address, and has a limiter of its
+// own, which is kept for as long as the middleware is. It panics if rate is
+// not positive.
+func RateLimit(rate float64, burst int) Middleware {
+	return RateLimitOn(clock.Real, rate, burst)
+}
+
+// RateLimitOn is like RateLimit, but refills the limiters by the time of c
+func RateLimitOn(c clock.Clock, rate float64, burst int) Middleware {
+	// The rate is checked now rather than by the limiter of the first
+	// client, since Retry-After is computed from it. The negated comparison
+	// also rejects NaN.
+	if !(rate > 0) {
+		panic("middleware: RateLimit rate must be positive")
+	}
+
+	var (
+		mu       sync.Mutex
+		limiters = make(map[string]*ratelimit.Limiter)
+	)
+
+	limiter := func(client string) *ratelimit.Limiter {
+		mu.Lock()
+		defer mu.Unlock()
+
+		l, ok := limiters[client]
+		if !ok {
+			l = ratelimit.NewLimiterOn(c, rate, burst)
+			limiters[client] = l
+		}
+
+		return l
+	}
+
+	// A token is refilled after 1/rate seconds, rounded up to the whole
+	// seconds of Retry-After
+	retryAfter := strconv.Itoa(int(math.Ceil(1 / rate)))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				client = r.RemoteAddr
+			}
+
+			if !limiter(client).Allow() {
+				w.Header().Set("Retry-After", retryAfter)
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/clock"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// newLogger returns a logger which writes lines without their time to buf
+func newLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))
+}
+
+func serve(h http.Handler, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	return rec
+}
+
+func TestChainOrder(t *testing.T) {
+	var order []string
+
+	mw := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name+" before")
+				next.ServeHTTP(w, r)
+				order = append(order, name+" after")
+			})
+		}
+	}
+
+	h := Chain(mw("a"), mw("b"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	}))
+	serve(h, "/")
+
+	expected := []string{"a before", "b before", "handler", "b after", "a after"}
+	if !slices.Equal(order, expected) {
+		t.Fatalf("expected %v, got %v", expected, order)
+	}
+}
+
+func TestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	fake := clock.NewFake(start)
+
+	h := LoggingOn(fake, newLogger(&buf))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.Advance(25 * time.Millisecond)
+		http.Error(w, "teapot", http.StatusTeapot)
+	}))
+	serve(h, "/tea")
+
+	expected := "level=INFO msg=request method=GET path=/tea status=418 size=7 duration=25ms\n"
+	if buf.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestLoggingImplicitStatus(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := Logging(newLogger(&buf))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	serve(h, "/")
+
+	if !strings.Contains(buf.String(), "status=200 size=2") {
+		t.Fatalf("expected a status of 200 for a handler which only writes, got %q", buf.String())
+	}
+}
+
+func TestLoggingNoWrite(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := Logging(newLogger(&buf))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serve(h, "/")
+
+	if !strings.Contains(buf.String(), "status=200 size=0") {
+		t.Fatalf("expected a status of 200 for a handler which writes nothing, got %q", buf.String())
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := Recovery(newLogger(&buf))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	rec := serve(h, "/boom")
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+
+	if !strings.Contains(buf.String(), "error=boom") {
+		t.Fatalf("expected the panic to be logged, got %q", buf.String())
+	}
+}
+
+func TestRecoveryAfterStatus(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := Recovery(newLogger(&buf))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		panic("boom")
+	}))
+
+	// The status can not be taken back, so it is left as it is
+	if rec := serve(h, "/"); rec.Code != http.StatusAccepted || rec.Body.Len() != 0 {
+		t.Fatalf("expected a 202 without a body, got %d %q", rec.Code, rec.Body)
+	}
+}
+
+func TestRecoveryAbort(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := Recovery(newLogger(&buf))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Fatalf("expected http.ErrAbortHandler to be passed on, got %v", v)
+		}
+
+		if buf.Len() != 0 {
+			t.Fatalf("expected nothing to be logged, got %q", buf.String())
+		}
+	}()
+
+	serve(h, "/")
+}
+
+func TestRateLimit(t *testing.T) {
+	fake := clock.NewFake(start)
+	h := RateLimitOn(fake, 0.5, 2)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	request := func(addr string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = addr
+		h.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	// Each client has a bucket of its own, whatever its port
+	for i, expected := range []int{200, 200, 429} {
+		if rec := request(fmt.Sprintf("10.0.0.1:%d", 1000+i)); rec.Code != expected {
+			t.Fatalf("expected request %d to get %d, got %d", i, expected, rec.Code)
+		}
+	}
+
+	if rec := request("10.0.0.2:1000"); rec.Code != http.StatusOK {
+		t.Fatalf("expected another client to get 200, got %d", rec.Code)
+	}
+
+	rec := request("10.0.0.1:1000")
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Fatalf("expected Retry-After: 2, got %q", got)
+	}
+
+	fake.Advance(2 * time.Second)
+	if rec := request("10.0.0.1:1000"); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 once a token is refilled, got %d", rec.Code)
+	}
+}
+
+func TestRateLimitRate(t *testing.T) {
+	for _, rate := range []float64{0, -1, math.NaN()} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected a rate of %v to panic when the middleware is built", rate)
+				}
+			}()
+
+			RateLimit(rate, 1)
+		}()
+	}
+}
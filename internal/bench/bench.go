@@ -0,0 +1,288 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+	"github.com/manedurphy/golang-university/internal/report"
+)
+
+type (
+	// Stats are the runtime statistics recorded by an instrumented lesson
+	Stats struct {
+		// TotalAlloc is the number of bytes allocated on the heap
+		TotalAlloc uint64 `json:"total_alloc"`
+		// Mallocs is the number of heap objects allocated
+		Mallocs uint64 `json:"mallocs"`
+		// PeakHeap is the largest amount of heap memory occupied by objects,
+		// sampled every millisecond
+		PeakHeap uint64 `json:"peak_heap"`
+		// NumGC is the number of completed garbage collection cycles
+		NumGC uint32 `json:"num_gc"`
+	}
+
+	// Result is the outcome of a single run of an instrumented lesson
+	Result struct {
+		Lesson   lesson.Lesson
+		Duration time.Duration
+		// Stats is nil if the lesson exited without returning from main,
+		// for example by calling os.Exit
+		Stats *Stats
+		// Report is what the lesson recorded with the report package, and
+		// is nil if it recorded nothing
+		Report *report.Report
+		Err    error
+	}
+
+	// Binary is a lesson built with instrumentation
+	Binary struct {
+		Lesson lesson.Lesson
+		path   string
+	}
+)
+
+const (
+	// statsEnv is the environment variable which tells the instrumented
+	// program where to write its statistics
+	statsEnv = "UNIVERSITY_BENCH_STATS"
+
+	// lessonMain is what the lesson's main function is renamed to
+	lessonMain = "universityLessonMain"
+
+	wrapperFile = "zz_university_bench.go"
+	wrapperSrc  = `package main
+
+import (
+	"encoding/json"
+	"os"
+	"runtime"
+	"runtime/metrics"
+	"time"
+)
+
+func main() {
+	stop := make(chan struct{})
+	peak := make(chan uint64)
+
+	go func() {
+		sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+
+		var peakHeap uint64
+		for {
+			metrics.Read(sample)
+			peakHeap = max(peakHeap, sample[0].Value.Uint64())
+
+			select {
+			case <-ticker.C:
+			case <-stop:
+				peak <- peakHeap
+				return
+			}
+		}
+	}()
+
+	` + lessonMain + `()
+
+	close(stop)
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	data, err := json.Marshal(map[string]any{
+		"total_alloc": mem.TotalAlloc,
+		"mallocs":     mem.Mallocs,
+		"peak_heap":   max(<-peak, mem.HeapAlloc),
+		"num_gc":      mem.NumGC,
+	})
+	if err == nil {
+		err = os.WriteFile(os.Getenv("` + statsEnv + `"), data, 0o644)
+	}
+	if err != nil {
+		os.Stderr.WriteString("failed to write bench stats: " + err.Error() + "\n")
+		os.Exit(1)
+	}
+}
+`
+)
+
+// Build compiles an instrumented copy of the lesson into dir. The lesson's
+// source is not modified: its main function is renamed in a build overlay,
+// and a new main function which records the runtime statistics once the
+// lesson returns is added next to it.
+func Build(ctx context.Context, root string, l lesson.Lesson, dir string) (*Binary, error) {
+	lessonDir := filepath.Join(root, l.Dir)
+
+	mainFile, src, err := renameMain(lessonDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to instrument %s: %w", l.ID, err)
+	}
+
+	name := strings.ReplaceAll(l.ID, "/", "_")
+
+	replace := map[string]string{
+		mainFile:                              filepath.Join(dir, name+"_main.go"),
+		filepath.Join(lessonDir, wrapperFile): filepath.Join(dir, name+"_wrapper.go"),
+	}
+
+	err = os.WriteFile(replace[mainFile], src, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write instrumented source: %w", err)
+	}
+
+	err = os.WriteFile(replace[filepath.Join(lessonDir, wrapperFile)], []byte(wrapperSrc), 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write wrapper source: %w", err)
+	}
+
+	overlay, err := json.Marshal(map[string]any{"Replace": replace})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode overlay: %w", err)
+	}
+
+	overlayPath := filepath.Join(dir, name+"_overlay.json")
+	err = os.WriteFile(overlayPath, overlay, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write overlay: %w", err)
+	}
+
+	bin := &Binary{Lesson: l, path: filepath.Join(dir, name)}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "go", "build", "-overlay", overlayPath, "-o", bin.path, "./"+filepath.ToSlash(l.Dir))
+	cmd.Dir = root
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s: %w\n%s", l.ID, err, stderr.String())
+	}
+
+	return bin, nil
+}
+
+// Run runs the binary once with args, and kills it if it has not exited
+// within timeout. The program's output is written to stdout and stderr.
+func (b *Binary) Run(ctx context.Context, args []string, timeout time.Duration, stdout, stderr io.Writer) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	statsPath := b.path + ".stats.json"
+	os.Remove(statsPath)
+
+	reportPath := b.path + ".report.json"
+	os.Remove(reportPath)
+
+	cmd := exec.CommandContext(ctx, b.path, args...)
+	cmd.Env = append(os.Environ(), statsEnv+"="+statsPath, report.Env+"="+reportPath)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	now := time.Now()
+	err := cmd.Run()
+	res := Result{Lesson: b.Lesson, Duration: time.Since(now)}
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		res.Err = fmt.Errorf("killed after %s", timeout)
+		return res
+	case err != nil:
+		res.Err = err
+		return res
+	}
+
+	// Only lessons which use the report package write a report
+	rep, err := report.Read(reportPath)
+	switch {
+	case err == nil:
+		res.Report = rep
+	case !errors.Is(err, os.ErrNotExist):
+		res.Err = err
+		return res
+	}
+
+	data, err := os.ReadFile(statsPath)
+	if err != nil {
+		// The lesson exited without returning from main
+		return res
+	}
+
+	var stats Stats
+	err = json.Unmarshal(data, &stats)
+	if err != nil {
+		res.Err = fmt.Errorf("failed to decode stats: %w", err)
+		return res
+	}
+
+	res.Stats = &stats
+	return res
+}
+
+// renameMain finds the file in dir which declares the main function, and
+// returns its path along with its source with main renamed to lessonMain
+func renameMain(dir string) (string, []byte, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		// A lesson with variants for different Go versions declares main
+		// in every variant, but only one of them is built
+		match, err := build.Default.MatchFile(dir, name)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to match %s: %w", name, err)
+		}
+		if !match {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		fset := token.NewFileSet()
+
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || fn.Name.Name != "main" {
+				continue
+			}
+
+			fn.Name.Name = lessonMain
+
+			var buf bytes.Buffer
+			err = printer.Fprint(&buf, fset, file)
+			if err != nil {
+				return "", nil, fmt.Errorf("failed to print %s: %w", name, err)
+			}
+
+			return path, buf.Bytes(), nil
+		}
+	}
+
+	return "", nil, errors.New("failed to find the main function")
+}
@@ -0,0 +1,119 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"go/build"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+)
+
+func TestRenameMain(t *testing.T) {
+	// memory/02-weak declares main in both of its variants, and only the
+	// one matching the toolchain's build tags may be instrumented
+	dir := filepath.Join("..", "..", "memory", "02-weak")
+
+	want := "requires_go124.go"
+	if slices.Contains(build.Default.ReleaseTags, "go1.24") {
+		want = "main.go"
+	}
+
+	path, src, err := renameMain(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if filepath.Base(path) != want {
+		t.Fatalf("expected main to be found in %s, got %s", want, path)
+	}
+
+	if !bytes.Contains(src, []byte("func "+lessonMain+"()")) || bytes.Contains(src, []byte("func main()")) {
+		t.Fatalf("expected main to be renamed to %s, got\n%s", lessonMain, src)
+	}
+}
+
+func TestRenameMainMissing(t *testing.T) {
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "helper.go"), []byte("package main\n\nfunc helper() {}\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "main_test.go"), []byte("package main\n\nfunc main() {}\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = renameMain(dir)
+	if err == nil {
+		t.Fatal("expected an error for a directory without a main function")
+	}
+}
+
+func TestBuild(t *testing.T) {
+	_, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("the go command is required to build the lessons")
+	}
+
+	if testing.Short() {
+		t.Skip("building the lessons is slow")
+	}
+
+	// The go command resolves the paths of the overlay from the root it runs
+	// in, so the root has to be absolute like the one the CLI finds
+	root, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+
+	t.Run("stats", func(t *testing.T) {
+		bin, err := Build(context.Background(), root, lesson.Lesson{ID: "alloc", Dir: "alloc"}, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var stdout bytes.Buffer
+		res := bin.Run(context.Background(), nil, time.Minute, &stdout, os.Stderr)
+		if res.Err != nil {
+			t.Fatal(res.Err)
+		}
+
+		if stdout.String() != "100\n" {
+			t.Fatalf("expected the lesson's output, got %q", stdout.String())
+		}
+
+		if res.Stats == nil {
+			t.Fatal("expected the stats to be recorded")
+		}
+
+		if res.Stats.TotalAlloc < 100*1024 || res.Stats.Mallocs < 100 || res.Stats.PeakHeap == 0 {
+			t.Fatalf("expected the allocations of the lesson to be recorded, got %+v", *res.Stats)
+		}
+	})
+
+	t.Run("exit", func(t *testing.T) {
+		bin, err := Build(context.Background(), root, lesson.Lesson{ID: "exit", Dir: "exit"}, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res := bin.Run(context.Background(), nil, time.Minute, os.Stdout, os.Stderr)
+		if res.Err != nil {
+			t.Fatal(res.Err)
+		}
+
+		if res.Stats != nil {
+			t.Fatalf("expected no stats from a lesson which did not return from main, got %+v", *res.Stats)
+		}
+	})
+}
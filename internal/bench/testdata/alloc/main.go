@@ -0,0 +1,14 @@
+// alloc allocates on the heap before returning from main
+package main
+
+import "fmt"
+
+var sink [][]byte
+
+func main() {
+	for range 100 {
+		sink = append(sink, make([]byte, 1024))
+	}
+
+	fmt.Println(len(sink))
+}
@@ -0,0 +1,8 @@
+// exit exits without returning from main
+package main
+
+import "os"
+
+func main() {
+	os.Exit(0)
+}
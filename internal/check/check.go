@@ -0,0 +1,191 @@
+package check
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type (
+	// Verifier decides whether the output of a lesson is correct
+	Verifier interface {
+		Verify(stdout []byte) error
+	}
+
+	// VerifierFunc adapts a function to the Verifier interface
+	VerifierFunc func(stdout []byte) error
+
+	// ExpectedOutput verifies that the output matches an expected output
+	// file line by line, where each line may contain placeholders for
+	// values which change from run to run
+	ExpectedOutput struct {
+		lines []*regexp.Regexp
+		text  []string
+	}
+
+	// Mismatch describes a line of output which did not match
+	Mismatch struct {
+		// Line is the 1-based line number in the output
+		Line     int
+		Expected string
+		Actual   string
+	}
+
+	// MismatchError is returned by ExpectedOutput when the output does not
+	// match
+	MismatchError struct {
+		Mismatches []Mismatch
+	}
+)
+
+// ExpectedOutputFile is the name of the file a lesson ships its expected
+// output in
+const ExpectedOutputFile = "expected_output.txt"
+
+// ErrNoVerifier is returned when a lesson has neither an expected output file
+// nor a registered verifier
+var ErrNoVerifier = errors.New("lesson has no expected output")
+
+// placeholders maps each placeholder which may be used in an expected output
+// file to the pattern it matches
+var placeholders = map[string]string{
+	"{{int}}":      ` *-?\d+`,
+	"{{float}}":    ` *-?\d+(?:\.\d+)?`,
+	"{{duration}}": `(?:\d+(?:\.\d+)?(?:ns|µs|us|ms|s|m|h))+`,
+	"{{hex}}":      `0x[0-9a-fA-F]+`,
+	"{{word}}":     `\S+`,
+	"{{any}}":      `.*`,
+}
+
+var placeholderRE = regexp.MustCompile(`\{\{[a-z]+\}\}`)
+
+// verifiers holds the verifiers of lessons whose output cannot be described
+// by an expected output file, keyed by lesson ID
+var verifiers = map[string]Verifier{}
+
+// Register registers the verifier for a lesson. A registered verifier takes
+// precedence over an expected output file.
+func Register(id string, v Verifier) {
+	verifiers[id] = v
+}
+
+// Verify calls f(stdout)
+func (f VerifierFunc) Verify(stdout []byte) error {
+	return f(stdout)
+}
+
+// Load returns the verifier for the lesson with the given ID, whose source is
+// in dir
+func Load(id, dir string) (Verifier, error) {
+	v, ok := verifiers[id]
+	if ok {
+		return v, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ExpectedOutputFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoVerifier
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read expected output: %w", err)
+	}
+
+	return ParseExpectedOutput(data)
+}
+
+// ParseExpectedOutput compiles the contents of an expected output file. Any
+// of the following placeholders may be used in place of a value which
+// changes from run to run:
+//
+//	{{int}}       an integer, which may be padded with spaces
+//	{{float}}     an integer or decimal number, which may be padded with spaces
+//	{{duration}}  a time.Duration, such as 1.5s or 300ms
+//	{{hex}}       a hexadecimal number such as a pointer, e.g. 0xc000012345
+//	{{word}}      any run of non-space characters
+//	{{any}}       the rest of the line, or nothing
+func ParseExpectedOutput(data []byte) (*ExpectedOutput, error) {
+	expected := &ExpectedOutput{}
+
+	for i, line := range splitLines(data) {
+		var (
+			pattern strings.Builder
+			last    int
+		)
+
+		pattern.WriteString("^")
+		for _, loc := range placeholderRE.FindAllStringIndex(line, -1) {
+			name := line[loc[0]:loc[1]]
+
+			re, ok := placeholders[name]
+			if !ok {
+				return nil, fmt.Errorf("line %d: unknown placeholder %s", i+1, name)
+			}
+
+			pattern.WriteString(regexp.QuoteMeta(line[last:loc[0]]))
+			pattern.WriteString(re)
+			last = loc[1]
+		}
+		pattern.WriteString(regexp.QuoteMeta(line[last:]))
+		pattern.WriteString("$")
+
+		expected.lines = append(expected.lines, regexp.MustCompile(pattern.String()))
+		expected.text = append(expected.text, line)
+	}
+
+	return expected, nil
+}
+
+// Verify compares the output with the expected output line by line. Trailing
+// whitespace is ignored.
+func (e *ExpectedOutput) Verify(stdout []byte) error {
+	var mismatches []Mismatch
+
+	actual := splitLines(stdout)
+	for i := range max(len(actual), len(e.lines)) {
+		switch {
+		case i >= len(actual):
+			mismatches = append(mismatches, Mismatch{Line: i + 1, Expected: e.text[i]})
+		case i >= len(e.lines):
+			mismatches = append(mismatches, Mismatch{Line: i + 1, Actual: actual[i]})
+		case !e.lines[i].MatchString(actual[i]):
+			mismatches = append(mismatches, Mismatch{Line: i + 1, Expected: e.text[i], Actual: actual[i]})
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return &MismatchError{Mismatches: mismatches}
+	}
+
+	return nil
+}
+
+func (e *MismatchError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%d lines of output did not match", len(e.Mismatches))
+	for _, m := range e.Mismatches {
+		fmt.Fprintf(&b, "\nline %d:\n  - %s\n  + %s", m.Line, m.Expected, m.Actual)
+	}
+
+	return b.String()
+}
+
+// splitLines splits data into lines without their trailing whitespace,
+// ignoring trailing empty lines
+func splitLines(data []byte) []string {
+	data = bytes.TrimRight(data, " \t\r\n")
+	if len(data) == 0 {
+		return nil
+	}
+
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+
+	return lines
+}
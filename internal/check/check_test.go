@@ -0,0 +1,68 @@
+package check
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExpectedOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		actual   string
+		lines    []int
+	}{
+		{name: "exact", expected: "hello\nworld\n", actual: "hello\nworld\n"},
+		{name: "trailing whitespace", expected: "hello\n", actual: "hello  \n\n"},
+		{name: "int", expected: "took {{int}} ms", actual: "took  95 ms"},
+		{name: "float", expected: "took {{float}} seconds", actual: "took 0.57 seconds"},
+		{name: "duration", expected: "timeout {{duration}}", actual: "timeout 1m30.5s"},
+		{name: "hex", expected: "next:{{hex}}}", actual: "next:0xc6aa831a080}"},
+		{name: "word", expected: "{{word}} at {{word}}", actual: "Chem-1 at UCSF"},
+		{name: "any", expected: "time={{any}} msg=done", actual: "time=2024-01-01T00:00:00Z msg=done"},
+		{name: "literal regexp characters", expected: "value: (1+1)", actual: "value: (1+1)"},
+		{name: "changed line", expected: "a\nb\nc", actual: "a\nx\nc", lines: []int{2}},
+		{name: "missing lines", expected: "a\nb\nc", actual: "a", lines: []int{2, 3}},
+		{name: "extra lines", expected: "a", actual: "a\nb", lines: []int{2}},
+		{name: "int does not match word", expected: "{{int}}", actual: "abc", lines: []int{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, err := ParseExpectedOutput([]byte(tt.expected))
+			if err != nil {
+				t.Fatalf("failed to parse expected output: %v", err)
+			}
+
+			err = expected.Verify([]byte(tt.actual))
+			if len(tt.lines) == 0 {
+				if err != nil {
+					t.Fatalf("expected output to match: %v", err)
+				}
+				return
+			}
+
+			var mismatch *MismatchError
+			if !errors.As(err, &mismatch) {
+				t.Fatalf("expected a MismatchError, got %v", err)
+			}
+
+			if len(mismatch.Mismatches) != len(tt.lines) {
+				t.Fatalf("expected %d mismatches, got %d: %v", len(tt.lines), len(mismatch.Mismatches), err)
+			}
+
+			for i, m := range mismatch.Mismatches {
+				if m.Line != tt.lines[i] {
+					t.Errorf("expected mismatch on line %d, got line %d", tt.lines[i], m.Line)
+				}
+			}
+		})
+	}
+}
+
+func TestUnknownPlaceholder(t *testing.T) {
+	_, err := ParseExpectedOutput([]byte("took {{seconds}}"))
+	if err == nil {
+		t.Fatal("expected an error for an unknown placeholder")
+	}
+}
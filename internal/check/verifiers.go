@@ -0,0 +1,35 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+)
+
+func init() {
+	Register("concurrency/14-atomics/01-counters", VerifierFunc(verifyCounters))
+}
+
+// verifyCounters checks that the atomic counter did not lose any updates. The
+// plain counter may or may not lose updates depending on the scheduler, so
+// its line is not checked at all.
+func verifyCounters(stdout []byte) error {
+	var expected, atomic, lost int64 = -1, -1, -1
+
+	scanner := bufio.NewScanner(bytes.NewReader(stdout))
+	for scanner.Scan() {
+		fmt.Sscanf(scanner.Text(), "expected: %d", &expected)
+		fmt.Sscanf(scanner.Text(), "atomic: %d (%d lost)", &atomic, &lost)
+	}
+
+	switch {
+	case expected < 0:
+		return fmt.Errorf("missing expected count")
+	case atomic < 0:
+		return fmt.Errorf("missing atomic count")
+	case atomic != expected || lost != 0:
+		return fmt.Errorf("atomic counter lost %d of %d updates", expected-atomic, expected)
+	}
+
+	return nil
+}
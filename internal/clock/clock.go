@@ -0,0 +1,264 @@
+// Package clock abstracts the passing of time, so that the generators and
+// limiters which wait for it can be tested against a fake clock, instantly
+// and deterministically, instead of sleeping in real time.
+package clock
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+type (
+	// Clock tells the time and waits for it to pass
+	Clock interface {
+		// Now returns the current time
+		Now() time.Time
+		// Sleep blocks until d has passed
+		Sleep(d time.Duration)
+		// After returns a channel which receives the current time once d
+		// has passed
+		After(d time.Duration) <-chan time.Time
+		// NewTicker returns a ticker which sends the current time every d
+		NewTicker(d time.Duration) Ticker
+		// NewTimer returns a timer which sends the current time once d has
+		// passed
+		NewTimer(d time.Duration) Timer
+	}
+
+	// Ticker is a time.Ticker of a Clock
+	Ticker interface {
+		// C returns the channel the ticks are sent to. Like the channel of a
+		// time.Ticker, it holds a single tick, and ticks are dropped for a
+		// slow receiver.
+		C() <-chan time.Time
+		// Stop stops the ticker. No more ticks are sent once it returns.
+		Stop()
+	}
+
+	// Timer is a time.Timer of a Clock
+	Timer interface {
+		// C returns the channel the time is sent to once the timer fires
+		C() <-chan time.Time
+		// Stop stops the timer, and reports whether its time was still to
+		// be received. Like a time.Timer since Go 1.23, no stale time is
+		// received from C once it returns.
+		Stop() bool
+		// Reset stops the timer, and starts it again to fire once d has
+		// passed. It reports what Stop would have.
+		Reset(d time.Duration) bool
+	}
+)
+
+// Real is the Clock of the time package
+var Real Clock = realClock{}
+
+type (
+	realClock  struct{}
+	realTicker struct{ *time.Ticker }
+	realTimer  struct{ *time.Timer }
+)
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+func (realClock) NewTimer(d time.Duration) Timer         { return realTimer{time.NewTimer(d)} }
+
+func (t realTicker) C() <-chan time.Time { return t.Ticker.C }
+func (t realTimer) C() <-chan time.Time  { return t.Timer.C }
+
+// Fake is a Clock whose time only passes when Advance is called. Sleep, After,
+// the tickers and the timers wait for Advance to move the time past the point
+// they are waiting for, so a test drives them from another goroutine, and
+// uses BlockUntil to know that they are waiting.
+type Fake struct {
+	mu      sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	waiters []*waiter
+}
+
+// waiter is a call of Sleep or After, a ticker, or a timer, which waits for
+// the time to pass until
+type waiter struct {
+	until  time.Time
+	period time.Duration
+	c      chan time.Time
+}
+
+// NewFake returns a fake clock which starts at start
+func NewFake(start time.Time) *Fake {
+	f := &Fake{now: start}
+	f.changed = sync.NewCond(&f.mu)
+
+	return f
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+func (f *Fake) Sleep(d time.Duration) {
+	<-f.After(d)
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.add(d, 0).c
+}
+
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	return &fakeTicker{f: f, w: f.add(d, d)}
+}
+
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	return &fakeTimer{f: f, w: f.add(d, 0)}
+}
+
+// add registers a waiter, which is sent the time once d has passed, and
+// every period after that if it is not zero
+func (f *Fake) add(d, period time.Duration) *waiter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &waiter{period: period, c: make(chan time.Time, 1)}
+	f.schedule(w, d)
+
+	return w
+}
+
+// schedule makes w wait until d has passed. f.mu must be held.
+func (f *Fake) schedule(w *waiter, d time.Duration) {
+	w.until = f.now.Add(d)
+	if d <= 0 {
+		w.c <- f.now
+		return
+	}
+
+	f.waiters = append(f.waiters, w)
+	f.changed.Broadcast()
+}
+
+func (f *Fake) remove(w *waiter) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.waiters = slices.DeleteFunc(f.waiters, func(o *waiter) bool { return o == w })
+}
+
+// Advance moves the time forward by d. Every waiter whose time has come is
+// sent the time it was waiting for, in the order of those times, and a ticker
+// is sent a tick for each of its periods which passed, unless its channel
+// still holds an earlier one.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	end := f.now.Add(d)
+	for {
+		i := f.next(end)
+		if i < 0 {
+			break
+		}
+
+		w := f.waiters[i]
+		f.now = w.until
+
+		select {
+		case w.c <- w.until:
+		default:
+		}
+
+		if w.period > 0 {
+			w.until = w.until.Add(w.period)
+		} else {
+			f.waiters = slices.Delete(f.waiters, i, i+1)
+		}
+	}
+
+	f.now = end
+	f.changed.Broadcast()
+}
+
+// next returns the index of the waiter with the earliest time up to end, or
+// -1 if there is none
+func (f *Fake) next(end time.Time) int {
+	next := -1
+	for i, w := range f.waiters {
+		if w.until.After(end) {
+			continue
+		}
+
+		if next < 0 || w.until.Before(f.waiters[next].until) {
+			next = i
+		}
+	}
+
+	return next
+}
+
+// BlockUntil blocks until at least n calls of Sleep and After, tickers, and
+// timers are waiting for the time to pass
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for len(f.waiters) < n {
+		f.changed.Wait()
+	}
+}
+
+type fakeTicker struct {
+	f *Fake
+	w *waiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.w.c }
+func (t *fakeTicker) Stop()               { t.f.remove(t.w) }
+
+type fakeTimer struct {
+	f *Fake
+	w *waiter
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.w.c }
+
+func (t *fakeTimer) Stop() bool {
+	t.f.mu.Lock()
+	defer t.f.mu.Unlock()
+
+	return t.stop()
+}
+
+// stop removes the timer from the waiters, and drains its channel. A time
+// which was sent but not received counts as waiting, as it does for a
+// time.Timer since Go 1.23. t.f.mu must be held.
+func (t *fakeTimer) stop() bool {
+	active := slices.Contains(t.f.waiters, t.w)
+	t.f.waiters = slices.DeleteFunc(t.f.waiters, func(o *waiter) bool { return o == t.w })
+
+	select {
+	case <-t.w.c:
+		active = true
+	default:
+	}
+
+	return active
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.f.mu.Lock()
+	defer t.f.mu.Unlock()
+
+	active := t.stop()
+	t.f.schedule(t.w, d)
+
+	return active
+}
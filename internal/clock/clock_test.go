@@ -0,0 +1,131 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFakeSleep(t *testing.T) {
+	fake := NewFake(start)
+
+	woke := make(chan time.Time)
+	go func() {
+		fake.Sleep(time.Second)
+		woke <- fake.Now()
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(999 * time.Millisecond)
+
+	select {
+	case <-woke:
+		t.Fatal("expected Sleep to wait for the whole second")
+	default:
+	}
+
+	fake.Advance(time.Millisecond)
+	if now := <-woke; !now.Equal(start.Add(time.Second)) {
+		t.Fatalf("expected to wake up a second later, got %v", now.Sub(start))
+	}
+}
+
+func TestFakeAfterOrder(t *testing.T) {
+	fake := NewFake(start)
+
+	late := fake.After(2 * time.Second)
+	early := fake.After(time.Second)
+
+	fake.Advance(time.Hour)
+
+	if got := <-early; !got.Equal(start.Add(time.Second)) {
+		t.Fatalf("expected the time the waiter waited for, got %v", got.Sub(start))
+	}
+
+	if got := <-late; !got.Equal(start.Add(2 * time.Second)) {
+		t.Fatalf("expected the time the waiter waited for, got %v", got.Sub(start))
+	}
+
+	if !fake.Now().Equal(start.Add(time.Hour)) {
+		t.Fatalf("expected the clock to be advanced by an hour, got %v", fake.Now().Sub(start))
+	}
+}
+
+func TestFakeTicker(t *testing.T) {
+	fake := NewFake(start)
+
+	ticker := fake.NewTicker(time.Second)
+
+	fake.Advance(time.Second)
+	if got := <-ticker.C(); !got.Equal(start.Add(time.Second)) {
+		t.Fatalf("expected a tick after a second, got %v", got.Sub(start))
+	}
+
+	// A slow receiver only gets the first of the ticks it missed
+	fake.Advance(3 * time.Second)
+	if got := <-ticker.C(); !got.Equal(start.Add(2 * time.Second)) {
+		t.Fatalf("expected the first missed tick, got %v", got.Sub(start))
+	}
+
+	select {
+	case got := <-ticker.C():
+		t.Fatalf("expected the other ticks to be dropped, got %v", got.Sub(start))
+	default:
+	}
+
+	ticker.Stop()
+	fake.Advance(time.Second)
+
+	select {
+	case got := <-ticker.C():
+		t.Fatalf("expected no tick once stopped, got %v", got.Sub(start))
+	default:
+	}
+}
+
+func TestFakeTimer(t *testing.T) {
+	fake := NewFake(start)
+
+	timer := fake.NewTimer(time.Second)
+	fake.Advance(time.Second)
+
+	if got := <-timer.C(); !got.Equal(start.Add(time.Second)) {
+		t.Fatalf("expected the timer to fire after a second, got %v", got.Sub(start))
+	}
+
+	if timer.Stop() {
+		t.Fatal("expected Stop to report that the timer had fired")
+	}
+
+	// Reset starts the timer over from the current time, and drops a time
+	// which was sent but not received
+	timer.Reset(time.Second)
+	fake.Advance(time.Second)
+	if !timer.Reset(2 * time.Second) {
+		t.Fatal("expected Reset to report that the time had not been received")
+	}
+
+	select {
+	case got := <-timer.C():
+		t.Fatalf("expected no stale time after Reset, got %v", got.Sub(start))
+	default:
+	}
+
+	fake.Advance(2 * time.Second)
+	if got := <-timer.C(); !got.Equal(start.Add(4 * time.Second)) {
+		t.Fatalf("expected the timer to fire two seconds after the Reset, got %v", got.Sub(start))
+	}
+
+	timer.Reset(time.Second)
+	if !timer.Stop() {
+		t.Fatal("expected Stop to report that the timer was waiting")
+	}
+
+	fake.Advance(time.Hour)
+	select {
+	case got := <-timer.C():
+		t.Fatalf("expected no time once stopped, got %v", got.Sub(start))
+	default:
+	}
+}
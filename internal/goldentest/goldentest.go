@@ -0,0 +1,160 @@
+package goldentest
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/linediff"
+)
+
+// Normalizer replaces every match of Pattern with Replacement, which may
+// refer to the submatches of Pattern like regexp.Regexp.ReplaceAll does
+type Normalizer struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// duration matches the String of a time.Duration, such as 1m2.5s or 12.3µs
+const duration = `(\d+h)?(\d+m)?\d+(\.\d+)?(ns|µs|us|ms|s)\b`
+
+// Defaults are the normalizers every output is run through, which replace the
+// values which change from run to run
+var Defaults = []Normalizer{
+	// The padding of a right-aligned duration changes with its length, so it
+	// is collapsed into a single space
+	{regexp.MustCompile(` +(\+?)` + duration), " ${1}<duration>"},
+	{regexp.MustCompile(duration), "<duration>"},
+	{regexp.MustCompile(`0x[0-9a-f]+`), "<hex>"},
+	{regexp.MustCompile(`goroutine \d+`), "goroutine <n>"},
+}
+
+var update bool
+
+// The flag is registered on the default flag set, so that go test accepts it
+// in every package which imports this one
+func init() {
+	flag.BoolVar(&update, "update", false, "Write the output of the golden tests to their golden files instead of comparing it")
+}
+
+// Normalize runs data through the default normalizers, then through the
+// given ones
+func Normalize(data []byte, normalizers ...Normalizer) []byte {
+	for _, n := range append(Defaults, normalizers...) {
+		data = n.Pattern.ReplaceAll(data, []byte(n.Replacement))
+	}
+
+	return data
+}
+
+// Path returns the golden file of the test, testdata/<test name>.golden
+func Path(t testing.TB) string {
+	name := strings.ReplaceAll(t.Name(), "/", "__")
+
+	return filepath.Join("testdata", name+".golden")
+}
+
+// Stdout calls fn, and returns what it printed to stdout. It is how a test
+// captures the output of a lesson's main function, which prints with fmt.
+//
+//	goldentest.Assert(t, goldentest.Stdout(t, main))
+//
+// os.Stdout is swapped for the whole process while fn runs, so a test which
+// calls Stdout must not call t.Parallel, or the output of another test could
+// end up in its capture.
+func Stdout(t testing.TB, fn func()) []byte {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to capture stdout: %v", err)
+	}
+	defer r.Close()
+
+	// The pipe is read while fn runs, so that fn does not block once the
+	// pipe's buffer is full
+	var (
+		buf  bytes.Buffer
+		done = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		_, _ = io.Copy(&buf, r)
+	}()
+
+	stdout := os.Stdout
+	os.Stdout = w
+
+	func() {
+		// A panic of fn still ends the capture, so that it is reported on
+		// the real stdout
+		defer func() {
+			os.Stdout = stdout
+			w.Close()
+			<-done
+		}()
+
+		fn()
+	}()
+
+	return buf.Bytes()
+}
+
+// Assert compares got with the golden file of the test, once it is
+// normalized, and fails the test with the lines which differ. With -update,
+// the golden file is written instead.
+//
+//	go test ./iterators/03-deep-dive/01-sequence-of-events -update
+func Assert(t testing.TB, got []byte, normalizers ...Normalizer) {
+	t.Helper()
+
+	got = Normalize(got, normalizers...)
+	path := Path(t)
+
+	if update {
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err == nil {
+			err = os.WriteFile(path, got, 0o644)
+		}
+		if err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file, run the test with -update to create it: %v", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Fatalf("output does not match %s, run the test with -update if the change is intended:\n%s", path, diff(want, got))
+	}
+}
+
+// diff returns the lines of want and got, marking the lines only in want
+// with a - and the lines only in got with a +
+func diff(want, got []byte) string {
+	a := strings.Split(string(want), "\n")
+	b := strings.Split(string(got), "\n")
+
+	var sb strings.Builder
+	for _, e := range linediff.Lines(a, b) {
+		switch e.Op {
+		case linediff.Equal:
+			sb.WriteString("  " + a[e.A] + "\n")
+		case linediff.Delete:
+			sb.WriteString("- " + a[e.A] + "\n")
+		case linediff.Insert:
+			sb.WriteString("+ " + b[e.B] + "\n")
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,63 @@
+package goldentest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	in := "[01   +12.345µs producer] took 1m2.5s at 0xc000012345 in goroutine 7, id 42\n"
+	expected := "[01 +<duration> producer] took <duration> at <hex> in goroutine <n>, id <id>\n"
+
+	got := Normalize([]byte(in), Normalizer{regexp.MustCompile(`id \d+`), "id <id>"})
+	if string(got) != expected {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
+
+// recorder collects the failures Assert reports, so that a mismatch can be
+// checked without failing the test
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssert(t *testing.T) {
+	// -update would overwrite the golden file with the mismatch below
+	if update {
+		t.Skip("the golden file of TestAssert is written by hand")
+	}
+
+	Assert(t, []byte("hello from iterator\ntook 3ms\n"))
+
+	// The golden file of TestAssert, with a changed line
+	r := &recorder{TB: t}
+	Assert(r, []byte("hello from loop body\ntook 5ms\n"))
+
+	if len(r.failures) != 1 {
+		t.Fatalf("expected a single failure, got %q", r.failures)
+	}
+
+	if !strings.Contains(r.failures[0], "- hello from iterator\n+ hello from loop body\n  took <duration>") {
+		t.Fatalf("expected the failure to show the changed line, got %q", r.failures[0])
+	}
+}
+
+func TestStdout(t *testing.T) {
+	got := Stdout(t, func() {
+		fmt.Println("hello from iterator: n=20")
+		fmt.Print("value: 20")
+	})
+
+	if string(got) != "hello from iterator: n=20\nvalue: 20" {
+		t.Fatalf("expected the printed lines, got %q", got)
+	}
+}
@@ -0,0 +1,40 @@
+package goversion
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/version"
+	"os/exec"
+	"strings"
+)
+
+// Detect returns the version of the go command which builds the lessons in
+// root, such as go1.24.1. It is run in root, so that a toolchain selected by
+// go.mod or GOTOOLCHAIN is detected rather than the one on the PATH.
+func Detect(ctx context.Context, root string) (string, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "go", "env", "GOVERSION")
+	cmd.Dir = root
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to detect the version of the go command: %w\n%s", err, stderr.String())
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Supports reports whether the toolchain, such as go1.24.1, can build a
+// lesson which requires Go min, such as 1.24. A lesson without a minimum
+// version is always supported, and so is every lesson by a toolchain whose
+// version cannot be compared, such as a development build.
+func Supports(toolchain, min string) bool {
+	if min == "" || !version.IsValid(toolchain) {
+		return true
+	}
+
+	return version.Compare(toolchain, "go"+min) >= 0
+}
@@ -0,0 +1,26 @@
+package goversion
+
+import "testing"
+
+func TestSupports(t *testing.T) {
+	tests := []struct {
+		toolchain string
+		min       string
+		expected  bool
+	}{
+		{toolchain: "go1.23.4", min: "", expected: true},
+		{toolchain: "go1.23.4", min: "1.23", expected: true},
+		{toolchain: "go1.23.4", min: "1.24", expected: false},
+		{toolchain: "go1.24rc1", min: "1.24", expected: true},
+		{toolchain: "go1.24.0", min: "1.24", expected: true},
+		{toolchain: "go1.25", min: "1.24", expected: true},
+		{toolchain: "devel go1.25-1a2b3c4", min: "1.24", expected: true},
+	}
+
+	for _, tt := range tests {
+		got := Supports(tt.toolchain, tt.min)
+		if got != tt.expected {
+			t.Errorf("Supports(%q, %q): expected %t, got %t", tt.toolchain, tt.min, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package grade
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+)
+
+type (
+	// Test is the outcome of a single test of a suite. Subtests are reported
+	// on their own, and the tests which only group them are left out.
+	Test struct {
+		Name   string
+		Passed bool
+		// Output is what the test printed, including the reason it failed
+		Output string
+	}
+
+	// Result is the outcome of grading an exercise
+	Result struct {
+		Exercise lesson.Lesson
+		Tests    []Test
+		// Crash is the panic which stopped the test binary before every test
+		// had run, such as a nil pointer dereference in the exercise or the
+		// timeout of a test which never returned. It is empty if every test
+		// ran.
+		Crash string
+	}
+
+	// Options configures how an exercise is graded
+	Options struct {
+		// Timeout is how long the tests may run before the test binary
+		// panics, which is what happens when an exercise never returns
+		Timeout time.Duration
+		// FuzzTime is how long each fuzz test is fuzzed for once every test
+		// has passed. Without it, fuzz tests only run their seed inputs.
+		FuzzTime time.Duration
+		// Solution grades the exercise's reference solution instead of the
+		// student's implementation
+		Solution bool
+	}
+
+	// event is a line of the output of go test -json
+	event struct {
+		Action     string
+		Test       string
+		Output     string
+		OutputType string
+	}
+)
+
+// suiteDir holds a test suite for every exercise, at the exercise's ID. The
+// suites live outside the exercises so that they cannot be read while
+// solving them, and testdata keeps them from being built as part of this
+// package.
+const suiteDir = "testdata/suites"
+
+//go:embed testdata/suites
+var suites embed.FS
+
+// ErrNoSuite is returned when grading a lesson which is not an exercise
+var ErrNoSuite = errors.New("lesson has no test suite")
+
+// Has reports whether the lesson with the given ID is an exercise with a
+// test suite
+func Has(id string) bool {
+	_, err := fs.Stat(suites, path.Join(suiteDir, id))
+	return err == nil
+}
+
+// Score returns the number of tests which passed, and the total number of
+// tests
+func (r *Result) Score() (int, int) {
+	passed := 0
+	for _, test := range r.Tests {
+		if test.Passed {
+			passed++
+		}
+	}
+
+	return passed, len(r.Tests)
+}
+
+// Passed reports whether every test passed
+func (r *Result) Passed() bool {
+	passed, total := r.Score()
+	return r.Crash == "" && total > 0 && passed == total
+}
+
+// Grade runs the exercise's hidden test suite against the student's
+// implementation. The suite is added to the exercise's package with a build
+// overlay, so nothing is written to the exercise's directory. An error is
+// only returned if the tests could not be run, such as when the exercise
+// does not compile.
+func Grade(ctx context.Context, root string, l lesson.Lesson, opts Options) (*Result, error) {
+	if !Has(l.ID) {
+		return nil, fmt.Errorf("%w: %s", ErrNoSuite, l.ID)
+	}
+
+	dir, err := os.MkdirTemp("", "university-grade-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	overlay, err := writeOverlay(root, l, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	if opts.Solution {
+		tags = []string{"-tags", lesson.SolutionTag}
+	}
+
+	res := &Result{Exercise: l}
+
+	res.Tests, res.Crash, err = goTest(ctx, root, l, overlay, append(tags, "-timeout", opts.Timeout.String(), "-run", ".")...)
+	if err != nil {
+		return nil, err
+	}
+
+	if !res.Passed() || opts.FuzzTime <= 0 {
+		return res, nil
+	}
+
+	// Only one fuzz test can be fuzzed at a time
+	var fuzzTests []string
+	for _, test := range res.Tests {
+		name, _, _ := strings.Cut(test.Name, "/")
+		if strings.HasPrefix(name, "Fuzz") && !slices.Contains(fuzzTests, name) {
+			fuzzTests = append(fuzzTests, name)
+		}
+	}
+
+	for _, name := range fuzzTests {
+		tests, crash, err := goTest(ctx, root, l, overlay, append(tags, "-run", "^$", "-fuzz", "^"+name+"$", "-fuzztime", opts.FuzzTime.String())...)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, test := range tests {
+			test.Name += " (fuzzing)"
+			res.Tests = append(res.Tests, test)
+		}
+
+		if crash != "" {
+			res.Crash = crash
+			break
+		}
+	}
+
+	return res, nil
+}
+
+// writeOverlay copies the exercise's suite into dir, and writes an overlay
+// which adds the suite's files to the exercise's directory. It returns the
+// path of the overlay.
+func writeOverlay(root string, l lesson.Lesson, dir string) (string, error) {
+	files := make(map[string]string)
+
+	suite := path.Join(suiteDir, l.ID)
+
+	entries, err := suites.ReadDir(suite)
+	if err != nil {
+		return "", fmt.Errorf("failed to read suite: %w", err)
+	}
+
+	for _, entry := range entries {
+		data, err := suites.ReadFile(path.Join(suite, entry.Name()))
+		if err != nil {
+			return "", fmt.Errorf("failed to read suite: %w", err)
+		}
+
+		dst := filepath.Join(dir, entry.Name())
+		err = os.WriteFile(dst, data, 0o644)
+		if err != nil {
+			return "", fmt.Errorf("failed to write suite: %w", err)
+		}
+
+		files[filepath.Join(root, l.Dir, entry.Name())] = dst
+	}
+
+	overlay, err := json.Marshal(map[string]any{"Replace": files})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode overlay: %w", err)
+	}
+
+	overlayPath := filepath.Join(dir, "overlay.json")
+	err = os.WriteFile(overlayPath, overlay, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("failed to write overlay: %w", err)
+	}
+
+	return overlayPath, nil
+}
+
+// goTest runs go test on the exercise with the overlay and the given flags,
+// and returns the outcome of every test which ran along with the panic which
+// crashed the test binary, if any. A failing test is not an error, but a
+// build failure is.
+func goTest(ctx context.Context, root string, l lesson.Lesson, overlay string, flags ...string) ([]Test, string, error) {
+	args := append([]string{"test", "-json", "-count=1", "-overlay", overlay}, flags...)
+	args = append(args, "./"+filepath.ToSlash(l.Dir))
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = root
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+
+	tests, crash, output, err := parseEvents(&stdout)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// go test only fails without a failing test when the package does not
+	// build
+	if runErr != nil && crash == "" && !slices.ContainsFunc(tests, func(test Test) bool { return !test.Passed }) {
+		return nil, "", fmt.Errorf("failed to test %s: %w\n%s%s", l.ID, runErr, output, stderr.String())
+	}
+
+	return tests, crash, nil
+}
+
+// parseEvents reads the output of go test -json. It returns the outcome of
+// every test in the order they started, the panic which crashed the test
+// binary if there was one, and the output which does not belong to a test.
+func parseEvents(r io.Reader) (tests []Test, crash string, output string, err error) {
+	var (
+		started []string
+		passed  = make(map[string]bool)
+		outputs = make(map[string]*strings.Builder)
+		pkg     strings.Builder
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+
+	for scanner.Scan() {
+		var e event
+		err := json.Unmarshal(scanner.Bytes(), &e)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("failed to decode test event: %w", err)
+		}
+
+		if e.Test == "" {
+			if e.Action == "output" || e.Action == "build-output" {
+				pkg.WriteString(e.Output)
+			}
+
+			continue
+		}
+
+		switch e.Action {
+		case "run":
+			started = append(started, e.Test)
+			outputs[e.Test] = new(strings.Builder)
+		case "output":
+			// Frames such as "=== RUN" only repeat what the events say
+			if outputs[e.Test] != nil && e.OutputType != "frame" {
+				outputs[e.Test].WriteString(e.Output)
+			}
+		case "pass":
+			passed[e.Test] = true
+		}
+
+		// A panic in a subtest is printed by the test which started it
+		if crash == "" && e.Action == "output" && strings.HasPrefix(e.Output, "panic: ") {
+			crash = e.Test
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to read test events: %w", err)
+	}
+
+	// The panic is reported on its own, rather than as the output of the
+	// test which printed it
+	if crash != "" {
+		out := outputs[crash].String()
+		i := strings.Index(out, "panic: ")
+
+		outputs[crash].Reset()
+		outputs[crash].WriteString(out[:i])
+		crash = out[i:]
+	}
+
+	for _, name := range started {
+		// A test whose subtests are reported is only a group, and would
+		// count the same failure twice
+		if slices.ContainsFunc(started, func(other string) bool { return strings.HasPrefix(other, name+"/") }) {
+			continue
+		}
+
+		// A test which never finished was running when the binary crashed,
+		// and has failed along with it
+		tests = append(tests, Test{Name: name, Passed: passed[name], Output: outputs[name].String()})
+	}
+
+	return tests, crash, pkg.String(), nil
+}
@@ -0,0 +1,114 @@
+package grade
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+)
+
+func TestParseEvents(t *testing.T) {
+	// A passing test, a subtest which panicked and crashed the test binary,
+	// and a test which never got to run
+	events := `{"Action":"start","Package":"example"}
+{"Action":"run","Package":"example","Test":"TestA"}
+{"Action":"output","Package":"example","Test":"TestA","Output":"=== RUN   TestA\n","OutputType":"frame"}
+{"Action":"pass","Package":"example","Test":"TestA"}
+{"Action":"run","Package":"example","Test":"TestB"}
+{"Action":"run","Package":"example","Test":"TestB/x"}
+{"Action":"output","Package":"example","Test":"TestB/x","Output":"--- FAIL: TestB/x (0.00s)\n","OutputType":"frame"}
+{"Action":"fail","Package":"example","Test":"TestB/x"}
+{"Action":"output","Package":"example","Test":"TestB","Output":"panic: assignment to entry in nil map [recovered]\n"}
+{"Action":"output","Package":"example","Test":"TestB","Output":"goroutine 8 [running]:\n"}
+{"Action":"fail","Package":"example","Test":"TestB"}
+{"Action":"output","Package":"example","Output":"FAIL\texample\t0.005s\n","OutputType":"frame"}
+{"Action":"fail","Package":"example"}
+`
+
+	tests, crash, output, err := parseEvents(strings.NewReader(events))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tests) != 2 || tests[0].Name != "TestA" || !tests[0].Passed || tests[1].Name != "TestB/x" || tests[1].Passed {
+		t.Fatalf("expected TestA to pass and TestB/x to fail, got %+v", tests)
+	}
+
+	if !strings.HasPrefix(crash, "panic: assignment to entry in nil map") {
+		t.Fatalf("expected the panic to be reported, got %q", crash)
+	}
+
+	if output != "FAIL\texample\t0.005s\n" {
+		t.Fatalf("expected the package output, got %q", output)
+	}
+}
+
+func TestSuites(t *testing.T) {
+	_, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("the go command is required to run the suites")
+	}
+
+	if testing.Short() {
+		t.Skip("running the suites is slow")
+	}
+
+	root, err := lesson.FindRoot(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reg, err := lesson.Discover(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exercises := 0
+	for l := range reg.All() {
+		if !Has(l.ID) {
+			continue
+		}
+
+		exercises++
+
+		t.Run(l.ID, func(t *testing.T) {
+			opts := Options{Timeout: 30 * time.Second}
+
+			// The exercises are shipped unsolved, so at least one test of
+			// every suite must fail
+			res, err := Grade(context.Background(), root, l, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.Passed() {
+				t.Fatal("expected the unsolved exercise to fail")
+			}
+
+			// The reference solution is selected by its build tag
+			opts.Solution = true
+
+			res, err = Grade(context.Background(), root, l, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !res.Passed() {
+				for _, test := range res.Tests {
+					if !test.Passed {
+						t.Errorf("%s failed:\n%s", test.Name, test.Output)
+					}
+				}
+
+				t.Fatalf("expected the solution to pass\n%s", res.Crash)
+			}
+		})
+	}
+
+	if exercises == 0 {
+		t.Fatal("expected to find at least one exercise")
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"testing"
+)
+
+// counting returns an infinite iterator over the numbers starting at 0, and
+// a pointer to the number of values it has yielded
+func counting() (iter.Seq[int], *int) {
+	count := 0
+
+	return func(yield func(int) bool) {
+		for {
+			count++
+			if !yield(count - 1) {
+				return
+			}
+		}
+	}, &count
+}
+
+// collect ranges over seq, breaking out of the loop after limit values, and
+// fails the test instead of crashing it if seq panics
+func collect[V any](t *testing.T, seq iter.Seq[V], limit int) (vals []V) {
+	t.Helper()
+
+	defer func() {
+		r := recover()
+		if r != nil {
+			t.Fatalf("iterator panicked: %v", r)
+		}
+	}()
+
+	for val := range seq {
+		vals = append(vals, val)
+		if len(vals) == limit {
+			break
+		}
+	}
+
+	return vals
+}
+
+func TestTake(t *testing.T) {
+	values := []int{20, 21, 22, 23, 24}
+
+	tests := []struct {
+		n        int
+		expected []int
+	}{
+		{n: -1, expected: nil},
+		{n: 0, expected: nil},
+		{n: 1, expected: []int{20}},
+		{n: 3, expected: []int{20, 21, 22}},
+		{n: 5, expected: []int{20, 21, 22, 23, 24}},
+		{n: 10, expected: []int{20, 21, 22, 23, 24}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("n=%d", tt.n), func(t *testing.T) {
+			got := collect(t, Take(slices.Values(values), tt.n), -1)
+			if !slices.Equal(got, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTakeInfinite(t *testing.T) {
+	seq, _ := counting()
+
+	got := collect(t, Take(seq, 3), 100)
+	if !slices.Equal(got, []int{0, 1, 2}) {
+		t.Fatalf("expected [0 1 2], got %v", got)
+	}
+}
+
+func TestTakeStopsPulling(t *testing.T) {
+	seq, count := counting()
+
+	collect(t, Take(seq, 3), -1)
+	if *count != 3 {
+		t.Fatalf("expected Take to pull 3 values from the iterator, it pulled %d", *count)
+	}
+}
+
+func TestTakeBreak(t *testing.T) {
+	seq, count := counting()
+
+	got := collect(t, Take(seq, 5), 2)
+	if !slices.Equal(got, []int{0, 1}) {
+		t.Fatalf("expected [0 1], got %v", got)
+	}
+
+	if *count != 2 {
+		t.Fatalf("expected Take to pull 2 values from the iterator after the break, it pulled %d", *count)
+	}
+}
+
+func TestTakeReuse(t *testing.T) {
+	seq := Take(slices.Values([]int{20, 21, 22}), 2)
+
+	for i := range 2 {
+		got := collect(t, seq, -1)
+		if !slices.Equal(got, []int{20, 21}) {
+			t.Fatalf("expected [20 21] on iteration %d, got %v", i+1, got)
+		}
+	}
+}
+
+func FuzzTake(f *testing.F) {
+	f.Add([]byte("golang"), 3)
+	f.Add([]byte("university"), 0)
+	f.Add([]byte{}, 2)
+	f.Add([]byte("iter"), 10)
+
+	f.Fuzz(func(t *testing.T, data []byte, n int) {
+		expected := data[:min(max(n, 0), len(data))]
+
+		got := collect(t, Take(slices.Values(data), n), -1)
+		if !slices.Equal(got, expected) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	})
+}
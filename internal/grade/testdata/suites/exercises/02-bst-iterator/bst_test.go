@@ -0,0 +1,122 @@
+package main
+
+import (
+	"iter"
+	"slices"
+	"testing"
+)
+
+// collect ranges over seq, breaking out of the loop after limit values, and
+// fails the test instead of crashing it if seq panics
+func collect[V any](t *testing.T, seq iter.Seq[V], limit int) (vals []V) {
+	t.Helper()
+
+	defer func() {
+		r := recover()
+		if r != nil {
+			t.Fatalf("iterator panicked: %v", r)
+		}
+	}()
+
+	for val := range seq {
+		vals = append(vals, val)
+		if len(vals) == limit {
+			break
+		}
+	}
+
+	return vals
+}
+
+func newTree(keys ...int) *Tree[int] {
+	var tree Tree[int]
+	for _, key := range keys {
+		tree.Insert(key)
+	}
+
+	return &tree
+}
+
+func TestAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []int
+		expected []int
+	}{
+		{name: "empty", keys: nil, expected: nil},
+		{name: "single", keys: []int{20}, expected: []int{20}},
+		{name: "balanced", keys: []int{4, 2, 6, 1, 3, 5, 7}, expected: []int{1, 2, 3, 4, 5, 6, 7}},
+		{name: "ascending", keys: []int{1, 2, 3, 4, 5}, expected: []int{1, 2, 3, 4, 5}},
+		{name: "descending", keys: []int{5, 4, 3, 2, 1}, expected: []int{1, 2, 3, 4, 5}},
+		{name: "zigzag", keys: []int{1, 5, 2, 4, 3}, expected: []int{1, 2, 3, 4, 5}},
+		{name: "duplicates", keys: []int{3, 1, 3, 2, 1}, expected: []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := collect(t, newTree(tt.keys...).All(), -1)
+			if !slices.Equal(got, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAllBreak(t *testing.T) {
+	tree := newTree(4, 2, 6, 1, 3, 5, 7)
+
+	for limit := 1; limit <= 7; limit++ {
+		got := collect(t, tree.All(), limit)
+		if !slices.Equal(got, []int{1, 2, 3, 4, 5, 6, 7}[:limit]) {
+			t.Fatalf("expected the first %d keys, got %v", limit, got)
+		}
+	}
+}
+
+func TestAllDeepTree(t *testing.T) {
+	// Inserting sorted keys degrades the tree into a linked list
+	keys := make([]int, 100000)
+	for i := range keys {
+		keys[i] = i
+	}
+
+	got := collect(t, newTree(keys...).All(), -1)
+	if !slices.Equal(got, keys) {
+		t.Fatalf("expected %d sorted keys, got %d keys", len(keys), len(got))
+	}
+}
+
+func TestAllStrings(t *testing.T) {
+	var tree Tree[string]
+	for _, name := range []string{"PHYSICS-1", "CHEM-2", "CALCULUS-1"} {
+		tree.Insert(name)
+	}
+
+	got := collect(t, tree.All(), -1)
+	if !slices.Equal(got, []string{"CALCULUS-1", "CHEM-2", "PHYSICS-1"}) {
+		t.Fatalf("expected the names in ascending order, got %v", got)
+	}
+}
+
+func FuzzAll(f *testing.F) {
+	f.Add([]byte("golang"), 3)
+	f.Add([]byte("university"), 0)
+	f.Add([]byte{}, 1)
+
+	f.Fuzz(func(t *testing.T, data []byte, limit int) {
+		var tree Tree[byte]
+		for _, key := range data {
+			tree.Insert(key)
+		}
+
+		expected := slices.Compact(slices.Sorted(slices.Values(data)))
+		if limit > 0 && limit < len(expected) {
+			expected = expected[:limit]
+		}
+
+		got := collect(t, tree.All(), limit)
+		if !slices.Equal(got, expected) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"iter"
+	"slices"
+	"testing"
+)
+
+// source is an iterator over vals which records whether it has returned
+type source struct {
+	vals     []int
+	returned bool
+}
+
+func (s *source) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		defer func() { s.returned = true }()
+
+		for _, val := range s.vals {
+			if !yield(val) {
+				return
+			}
+		}
+	}
+}
+
+// collect ranges over seq, breaking out of the loop after limit values, and
+// fails the test instead of crashing it if seq panics
+func collect[V any](t *testing.T, seq iter.Seq[V], limit int) (vals []V) {
+	t.Helper()
+
+	defer func() {
+		r := recover()
+		if r != nil {
+			t.Fatalf("iterator panicked: %v", r)
+		}
+	}()
+
+	for val := range seq {
+		vals = append(vals, val)
+		if len(vals) == limit {
+			break
+		}
+	}
+
+	return vals
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     []int
+		expected []int
+	}{
+		{name: "both empty", a: nil, b: nil, expected: nil},
+		{name: "first empty", a: nil, b: []int{1, 2}, expected: []int{1, 2}},
+		{name: "second empty", a: []int{1, 2}, b: nil, expected: []int{1, 2}},
+		{name: "interleaved", a: []int{20, 22, 24}, b: []int{21, 23, 25}, expected: []int{20, 21, 22, 23, 24, 25}},
+		{name: "first before second", a: []int{1, 2}, b: []int{3, 4}, expected: []int{1, 2, 3, 4}},
+		{name: "second before first", a: []int{3, 4}, b: []int{1, 2}, expected: []int{1, 2, 3, 4}},
+		{name: "duplicates", a: []int{1, 2, 2}, b: []int{2, 3}, expected: []int{1, 2, 2, 2, 3}},
+		{name: "uneven", a: []int{5}, b: []int{1, 2, 3, 4, 6, 7}, expected: []int{1, 2, 3, 4, 5, 6, 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := collect(t, Merge(slices.Values(tt.a), slices.Values(tt.b)), -1)
+			if !slices.Equal(got, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMergeStopsSources(t *testing.T) {
+	for limit := 1; limit <= 6; limit++ {
+		a := &source{vals: []int{1, 3, 5}}
+		b := &source{vals: []int{2, 4, 6}}
+
+		got := collect(t, Merge(a.All(), b.All()), limit)
+		if !slices.Equal(got, []int{1, 2, 3, 4, 5, 6}[:limit]) {
+			t.Fatalf("expected the first %d values, got %v", limit, got)
+		}
+
+		if !a.returned || !b.returned {
+			t.Fatalf("after breaking out of the loop at %d values, first returned: %t, second returned: %t", limit, a.returned, b.returned)
+		}
+	}
+}
+
+func TestMergeExhaustsSources(t *testing.T) {
+	a := &source{vals: []int{1, 3}}
+	b := &source{vals: []int{2, 4, 6, 8}}
+
+	collect(t, Merge(a.All(), b.All()), -1)
+
+	if !a.returned || !b.returned {
+		t.Fatalf("after the loop finished, first returned: %t, second returned: %t", a.returned, b.returned)
+	}
+}
+
+func FuzzMerge(f *testing.F) {
+	f.Add([]byte("golang"), []byte("university"))
+	f.Add([]byte{}, []byte("iter"))
+	f.Add([]byte("pull"), []byte{})
+
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		slices.Sort(a)
+		slices.Sort(b)
+
+		expected := slices.Sorted(slices.Values(slices.Concat(a, b)))
+
+		got := collect(t, Merge(slices.Values(a), slices.Values(b)), -1)
+		if !slices.Equal(got, expected) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package lesson
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+	"strings"
+)
+
+// Prerequisites returns the lessons which l directly depends on, in the
+// order its manifest lists them
+func (r *Registry) Prerequisites(l Lesson) []Lesson {
+	prerequisites := make([]Lesson, 0, len(l.Manifest.Prerequisites))
+	for _, id := range l.Manifest.Prerequisites {
+		i := slices.IndexFunc(r.lessons, func(l Lesson) bool { return l.ID == id })
+		if i >= 0 {
+			prerequisites = append(prerequisites, r.lessons[i])
+		}
+	}
+
+	return prerequisites
+}
+
+// Missing returns the prerequisites of l which are not complete, including
+// the prerequisites of those prerequisites, in curriculum order
+func (r *Registry) Missing(l Lesson, complete func(id string) bool) []Lesson {
+	needed := make(map[string]bool)
+
+	var visit func(l Lesson)
+	visit = func(l Lesson) {
+		for _, p := range r.Prerequisites(l) {
+			if !needed[p.ID] && !complete(p.ID) {
+				needed[p.ID] = true
+				visit(p)
+			}
+		}
+	}
+
+	visit(l)
+
+	var missing []Lesson
+	for l := range r.Ordered() {
+		if needed[l.ID] {
+			missing = append(missing, l)
+		}
+	}
+
+	return missing
+}
+
+// Next returns the lesson to take after the completed ones: the first lesson
+// in curriculum order which is not complete, but whose prerequisites all are.
+// A lesson in module is preferred, so that a student who jumped ahead to a
+// module can carry on with it. It returns false once every lesson is
+// complete.
+func (r *Registry) Next(complete func(id string) bool, module string) (Lesson, bool) {
+	var (
+		next  Lesson
+		found bool
+	)
+
+	for l := range r.Ordered() {
+		if complete(l.ID) || len(r.Missing(l, complete)) > 0 {
+			continue
+		}
+
+		if l.Module == module {
+			return l, true
+		}
+
+		if !found {
+			next, found = l, true
+		}
+	}
+
+	return next, found
+}
+
+// WriteDOT renders the prerequisites of the lessons yielded by seq as a
+// Graphviz DOT graph, with each module in its own cluster. Prerequisites
+// outside of seq are drawn as well, so that every edge has both of its ends.
+// Completed lessons are filled in.
+func (r *Registry) WriteDOT(w io.Writer, seq iter.Seq[Lesson], complete func(id string) bool) error {
+	var (
+		lessons []Lesson
+		seen    = make(map[string]bool)
+	)
+
+	add := func(l Lesson) {
+		if !seen[l.ID] {
+			seen[l.ID] = true
+			lessons = append(lessons, l)
+		}
+	}
+
+	var edges [][2]string
+	for l := range seq {
+		add(l)
+		for _, p := range r.Prerequisites(l) {
+			add(p)
+			edges = append(edges, [2]string{p.ID, l.ID})
+		}
+	}
+
+	// Clusters follow the curriculum order of their first lesson
+	var modules []string
+	byModule := make(map[string][]Lesson)
+	for l := range r.Ordered() {
+		if !seen[l.ID] {
+			continue
+		}
+
+		if byModule[l.Module] == nil {
+			modules = append(modules, l.Module)
+		}
+
+		byModule[l.Module] = append(byModule[l.Module], l)
+	}
+
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "digraph curriculum {")
+	fmt.Fprintln(bw, "\trankdir=LR;")
+	fmt.Fprintln(bw, "\tnode [shape=box, style=rounded];")
+
+	for _, module := range modules {
+		fmt.Fprintf(bw, "\n\tsubgraph %s {\n", quote("cluster_"+module))
+		fmt.Fprintf(bw, "\t\tlabel=%s;\n", quote(module))
+
+		for _, l := range byModule[module] {
+			style := ""
+			if complete(l.ID) {
+				style = `, style="rounded,filled", fillcolor=palegreen`
+			}
+
+			fmt.Fprintf(bw, "\t\t%s [label=%s%s];\n", quote(l.ID), quote(l.Manifest.Title), style)
+		}
+
+		fmt.Fprintln(bw, "\t}")
+	}
+
+	if len(edges) > 0 {
+		fmt.Fprintln(bw)
+	}
+
+	for _, edge := range edges {
+		fmt.Fprintf(bw, "\t%s -> %s;\n", quote(edge[0]), quote(edge[1]))
+	}
+
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
+
+// quote returns s as a DOT string
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`) + `"`
+}
@@ -0,0 +1,337 @@
+package lesson
+
+import (
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"iter"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/manedurphy/golang-university/internal/manifest"
+)
+
+type (
+	// Lesson is a runnable main package in one of the course's modules
+	Lesson struct {
+		// ID identifies the lesson as module/lesson, e.g.
+		// iterators/04-database/01-push
+		ID string
+		// Module is the top-level directory the lesson belongs to
+		Module string
+		// Dir is the lesson's directory, relative to the repository root
+		Dir string
+		// Manifest describes the lesson. Every lesson has one, even if it
+		// does not ship a manifest file.
+		Manifest *manifest.Manifest
+		// Pack is the program of the pack the lesson belongs to, which runs
+		// it, or empty for a lesson of the course. A lesson of a pack has no
+		// directory.
+		Pack string
+		// ExpectedOutput is the expected output of a lesson of a pack, which
+		// has no directory to ship an expected output file in
+		ExpectedOutput string
+	}
+
+	// Registry holds every lesson discovered in the repository
+	Registry struct {
+		root    string
+		fsys    fs.FS
+		lessons []Lesson
+	}
+)
+
+var (
+	// ErrNotFound is returned when no lesson matches an ID
+	ErrNotFound = errors.New("lesson not found")
+	// ErrAmbiguous is returned when more than one lesson matches an ID
+	ErrAmbiguous = errors.New("lesson ID is ambiguous")
+)
+
+// skipDirs are top-level directories which never contain lessons
+var skipDirs = []string{"cmd", "internal", "testdata"}
+
+// FindRoot walks up from dir until it finds the directory containing go.mod
+func FindRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve directory: %w", err)
+	}
+
+	for {
+		_, err = os.Stat(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("failed to find go.mod in any parent directory")
+		}
+
+		dir = parent
+	}
+}
+
+// Discover walks the repository rooted at root and registers every directory
+// containing a main package as a lesson
+func Discover(root string) (*Registry, error) {
+	reg, err := DiscoverFS(os.DirFS(root))
+	if err != nil {
+		return nil, err
+	}
+
+	reg.root = root
+	return reg, nil
+}
+
+// DiscoverFS is like Discover, but finds the lessons in fsys, such as a copy
+// of the course embedded in a binary. The lessons of the registry it returns
+// can be read with Source, but not run, since they are not on disk.
+func DiscoverFS(fsys fs.FS) (*Registry, error) {
+	var lessons []Lesson
+
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() || p == "." {
+			return nil
+		}
+
+		if strings.HasPrefix(d.Name(), ".") || slices.Contains(skipDirs, p) {
+			return fs.SkipDir
+		}
+
+		isMain, err := isMainPackage(fsys, p)
+		if err != nil {
+			return err
+		}
+
+		if isMain {
+			data, err := fs.ReadFile(fsys, path.Join(p, manifest.File))
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("failed to read manifest: %w", err)
+			}
+
+			m, err := manifest.Decode(data, filepath.FromSlash(p))
+			if err != nil {
+				return err
+			}
+
+			lessons = append(lessons, Lesson{
+				ID:       p,
+				Module:   strings.SplitN(p, "/", 2)[0],
+				Dir:      filepath.FromSlash(p),
+				Manifest: m,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover lessons: %w", err)
+	}
+
+	slices.SortFunc(lessons, func(a, b Lesson) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	reg := &Registry{fsys: fsys, lessons: lessons}
+
+	_, err = reg.order()
+	if err != nil {
+		return nil, fmt.Errorf("failed to order lessons: %w", err)
+	}
+
+	return reg, nil
+}
+
+// Root returns the repository root the lessons were discovered in. It is
+// empty if the lessons were not discovered on disk.
+func (r *Registry) Root() string {
+	return r.root
+}
+
+// FS returns the file system the lessons were discovered in, rooted at the
+// repository root
+func (r *Registry) FS() fs.FS {
+	return r.fsys
+}
+
+// Dir returns the absolute path of the lesson's directory
+func (r *Registry) Dir(l Lesson) string {
+	return filepath.Join(r.root, l.Dir)
+}
+
+// All yields every lesson, sorted by ID
+func (r *Registry) All() iter.Seq[Lesson] {
+	return slices.Values(r.lessons)
+}
+
+// Ordered yields every lesson after all of its prerequisites. Lessons which
+// do not depend on each other are yielded in the order of their IDs.
+func (r *Registry) Ordered() iter.Seq[Lesson] {
+	// Discover has already checked that the lessons can be ordered
+	ordered, _ := r.order()
+	return slices.Values(ordered)
+}
+
+// Under yields every lesson whose ID is prefix, or is inside the directory
+// prefix, sorted by ID
+func (r *Registry) Under(prefix string) iter.Seq[Lesson] {
+	prefix = strings.Trim(filepath.ToSlash(prefix), "/")
+
+	return func(yield func(Lesson) bool) {
+		for _, l := range r.lessons {
+			if l.ID != prefix && !strings.HasPrefix(l.ID, prefix+"/") {
+				continue
+			}
+
+			if !yield(l) {
+				return
+			}
+		}
+	}
+}
+
+// Lookup returns the lesson with the given ID. An ID may also be shortened
+// to any suffix which starts at a path separator, such as 04-database/01-push,
+// as long as exactly one lesson matches it.
+func (r *Registry) Lookup(id string) (Lesson, error) {
+	var matches []Lesson
+
+	id = strings.Trim(filepath.ToSlash(id), "/")
+	for _, l := range r.lessons {
+		if l.ID == id {
+			return l, nil
+		}
+
+		if strings.HasSuffix(l.ID, "/"+id) {
+			matches = append(matches, l)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return Lesson{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	case 1:
+		return matches[0], nil
+	}
+
+	ids := make([]string, 0, len(matches))
+	for _, l := range matches {
+		ids = append(ids, l.ID)
+	}
+
+	return Lesson{}, fmt.Errorf("%w: %s matches %s", ErrAmbiguous, id, strings.Join(ids, ", "))
+}
+
+// isMainPackage reports whether dir contains a non-test Go file declaring
+// package main
+func isMainPackage(fsys fs.FS, dir string) (bool, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return false, err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || path.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		src, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return false, err
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), name, src, parser.PackageClauseOnly)
+		if err != nil {
+			return false, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+
+		return file.Name.Name == "main", nil
+	}
+
+	return false, nil
+}
+
+// order sorts the lessons topologically by their prerequisites. Whenever
+// more than one lesson is ready, it prefers staying in the module of the
+// previous lesson, and then the lesson with the smallest ID, so each module
+// is taken in as few stretches as possible.
+func (r *Registry) order() ([]Lesson, error) {
+	var (
+		ordered   = make([]Lesson, 0, len(r.lessons))
+		remaining = make(map[string]int, len(r.lessons))
+		unlocks   = make(map[string][]string, len(r.lessons))
+		byID      = make(map[string]Lesson, len(r.lessons))
+		ready     []Lesson
+	)
+
+	for _, l := range r.lessons {
+		byID[l.ID] = l
+	}
+
+	for _, l := range r.lessons {
+		for _, id := range l.Manifest.Prerequisites {
+			if _, ok := byID[id]; !ok {
+				return nil, fmt.Errorf("%s: unknown prerequisite %s", l.ID, id)
+			}
+
+			unlocks[id] = append(unlocks[id], l.ID)
+		}
+
+		remaining[l.ID] = len(l.Manifest.Prerequisites)
+		if remaining[l.ID] == 0 {
+			ready = append(ready, l)
+		}
+	}
+
+	for len(ready) > 0 {
+		// ready is kept sorted by ID, so the first lesson of the previous
+		// module is the next one in that module
+		next := 0
+		if len(ordered) > 0 {
+			module := ordered[len(ordered)-1].Module
+			i := slices.IndexFunc(ready, func(l Lesson) bool { return l.Module == module })
+			if i >= 0 {
+				next = i
+			}
+		}
+
+		l := ready[next]
+		ready = slices.Delete(ready, next, next+1)
+		ordered = append(ordered, l)
+
+		for _, id := range unlocks[l.ID] {
+			remaining[id]--
+			if remaining[id] == 0 {
+				i, _ := slices.BinarySearchFunc(ready, id, func(l Lesson, id string) int {
+					return strings.Compare(l.ID, id)
+				})
+				ready = slices.Insert(ready, i, byID[id])
+			}
+		}
+	}
+
+	if len(ordered) < len(r.lessons) {
+		var cycle []string
+		for _, l := range r.lessons {
+			if remaining[l.ID] > 0 {
+				cycle = append(cycle, l.ID)
+			}
+		}
+
+		return nil, fmt.Errorf("prerequisites form a cycle between %s", strings.Join(cycle, ", "))
+	}
+
+	return ordered, nil
+}
@@ -0,0 +1,230 @@
+package lesson
+
+import (
+	"errors"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeLesson creates a main package in root/id with the given manifest
+func writeLesson(t *testing.T, root, id, manifest string) {
+	t.Helper()
+
+	dir := filepath.Join(root, filepath.FromSlash(id))
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if manifest != "" {
+		err = os.WriteFile(filepath.Join(dir, "lesson.yaml"), []byte(manifest), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func ids(seq iter.Seq[Lesson]) []string {
+	var ids []string
+	for l := range seq {
+		ids = append(ids, l.ID)
+	}
+
+	return ids
+}
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+
+	writeLesson(t, root, "b/01-first", "")
+	writeLesson(t, root, "b/02-second", "prerequisites: [a/02-second]\n")
+	writeLesson(t, root, "a/01-first", "")
+	writeLesson(t, root, "a/02-second", "title: Second\ndifficulty: beginner\n")
+	writeLesson(t, root, "a/03-third", "prerequisites: [b/02-second]\n")
+	writeLesson(t, root, "internal/tool", "")
+
+	// A helper package is not a lesson
+	err := os.MkdirAll(filepath.Join(root, "a", "helper"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(root, "a", "helper", "helper.go"), []byte("package helper\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reg, err := Discover(root)
+	if err != nil {
+		t.Fatalf("failed to discover lessons: %v", err)
+	}
+
+	all := ids(reg.All())
+	expected := []string{"a/01-first", "a/02-second", "a/03-third", "b/01-first", "b/02-second"}
+	if !slices.Equal(all, expected) {
+		t.Errorf("expected lessons %v, got %v", expected, all)
+	}
+
+	ordered := ids(reg.Ordered())
+	expected = []string{"a/01-first", "a/02-second", "b/01-first", "b/02-second", "a/03-third"}
+	if !slices.Equal(ordered, expected) {
+		t.Errorf("expected order %v, got %v", expected, ordered)
+	}
+
+	l, err := reg.Lookup("a/02-second")
+	if err != nil {
+		t.Fatalf("failed to look up lesson: %v", err)
+	}
+	if l.Manifest.Title != "Second" {
+		t.Errorf("expected title Second, got %q", l.Manifest.Title)
+	}
+
+	l, err = reg.Lookup("03-third")
+	if err != nil {
+		t.Fatalf("failed to look up lesson by suffix: %v", err)
+	}
+	if l.Manifest.Title != "Third" {
+		t.Errorf("expected title derived from the directory, got %q", l.Manifest.Title)
+	}
+
+	_, err = reg.Lookup("02-second")
+	if !errors.Is(err, ErrAmbiguous) {
+		t.Errorf("expected ErrAmbiguous, got %v", err)
+	}
+
+	_, err = reg.Lookup("04-fourth")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDiscoverInvalidPrerequisites(t *testing.T) {
+	tests := []struct {
+		name    string
+		lessons map[string]string
+		err     string
+	}{
+		{
+			name:    "unknown",
+			lessons: map[string]string{"a/01-first": "prerequisites: [a/00-zeroth]\n"},
+			err:     "unknown prerequisite a/00-zeroth",
+		},
+		{
+			name: "cycle",
+			lessons: map[string]string{
+				"a/01-first":  "prerequisites: [a/02-second]\n",
+				"a/02-second": "prerequisites: [a/01-first]\n",
+			},
+			err: "cycle",
+		},
+		{
+			name:    "difficulty",
+			lessons: map[string]string{"a/01-first": "difficulty: impossible\n"},
+			err:     `unknown difficulty "impossible"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for id, manifest := range tt.lessons {
+				writeLesson(t, root, id, manifest)
+			}
+
+			_, err := Discover(root)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected an error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	root := t.TempDir()
+
+	writeLesson(t, root, "a/01-first", "")
+	writeLesson(t, root, "a/02-second", "prerequisites: [a/01-first]\n")
+	writeLesson(t, root, "a/03-third", "prerequisites: [a/02-second, b/01-first]\n")
+	writeLesson(t, root, "b/01-first", "")
+	writeLesson(t, root, "b/02-second", "prerequisites: [b/01-first]\n")
+
+	reg, err := Discover(root)
+	if err != nil {
+		t.Fatalf("failed to discover lessons: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		completed []string
+		module    string
+		expected  string
+	}{
+		{name: "nothing completed", expected: "a/01-first"},
+		{name: "in order", completed: []string{"a/01-first"}, module: "a", expected: "a/02-second"},
+		{name: "prefers module", completed: []string{"a/01-first", "b/01-first"}, module: "b", expected: "b/02-second"},
+		{name: "skipped ahead", completed: []string{"a/02-second"}, module: "a", expected: "a/01-first"},
+		{name: "blocked by other module", completed: []string{"a/01-first", "a/02-second"}, module: "a", expected: "b/01-first"},
+		{name: "done", completed: []string{"a/01-first", "a/02-second", "a/03-third", "b/01-first", "b/02-second"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			complete := func(id string) bool { return slices.Contains(tt.completed, id) }
+
+			l, ok := reg.Next(complete, tt.module)
+			if tt.expected == "" {
+				if ok {
+					t.Fatalf("expected no next lesson, got %s", l.ID)
+				}
+
+				return
+			}
+
+			if !ok || l.ID != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, l.ID)
+			}
+		})
+	}
+
+	l, err := reg.Lookup("a/03-third")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	missing := reg.Missing(l, func(id string) bool { return id == "a/02-second" })
+	if !slices.Equal(ids(slices.Values(missing)), []string{"b/01-first"}) {
+		t.Errorf("expected only b/01-first to be missing, got %v", ids(slices.Values(missing)))
+	}
+
+	missing = reg.Missing(l, func(string) bool { return false })
+	if !slices.Equal(ids(slices.Values(missing)), []string{"a/01-first", "a/02-second", "b/01-first"}) {
+		t.Errorf("expected every prerequisite to be missing, got %v", ids(slices.Values(missing)))
+	}
+
+	var dot strings.Builder
+	err = reg.WriteDOT(&dot, reg.Under("b"), func(id string) bool { return id == "b/01-first" })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		`"b/01-first" [label="First", style="rounded,filled", fillcolor=palegreen];`,
+		`"b/01-first" -> "b/02-second";`,
+	} {
+		if !strings.Contains(dot.String(), line) {
+			t.Errorf("expected the graph to contain %s, got\n%s", line, dot.String())
+		}
+	}
+
+	if strings.Contains(dot.String(), "a/") {
+		t.Errorf("expected the graph to only contain module b, got\n%s", dot.String())
+	}
+}
@@ -0,0 +1,117 @@
+package lesson
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/manedurphy/golang-university/internal/manifest"
+	"github.com/manedurphy/golang-university/pack"
+)
+
+// ErrNoSource is returned for the source of a lesson of a pack, which is
+// compiled into the pack's program
+var ErrNoSource = errors.New("lesson of a pack has no source")
+
+// FindPacks returns the programs on the directories of path, a list like
+// $PATH, whose names start with pack.Prefix. Like a command, a pack in an
+// earlier directory hides one of the same name in a later one.
+func FindPacks(path string) []string {
+	var (
+		found []string
+		names = make(map[string]bool)
+	)
+
+	for _, dir := range filepath.SplitList(path) {
+		matches, _ := filepath.Glob(filepath.Join(dir, pack.Prefix+"*"))
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil || info.IsDir() || info.Mode()&0o111 == 0 {
+				continue
+			}
+
+			name := strings.TrimSuffix(filepath.Base(match), ".exe")
+			if !names[name] {
+				names[name] = true
+				found = append(found, match)
+			}
+		}
+	}
+
+	return found
+}
+
+// AddPack asks the pack's program for its lessons, and adds them to the
+// registry. The lessons of a pack may build on the lessons of the course and
+// of the packs added before it, but may not add lessons to their modules.
+func (r *Registry) AddPack(ctx context.Context, program string) error {
+	out, err := exec.CommandContext(ctx, program, pack.ListCommand).Output()
+	if err != nil {
+		return fmt.Errorf("failed to list the lessons of %s: %w", program, err)
+	}
+
+	var infos []pack.LessonInfo
+	err = json.Unmarshal(out, &infos)
+	if err != nil {
+		return fmt.Errorf("failed to decode the lessons of %s: %w", program, err)
+	}
+
+	modules := make(map[string]bool)
+	for _, l := range r.lessons {
+		modules[l.Module] = true
+	}
+
+	lessons := slices.Clone(r.lessons)
+	for _, info := range infos {
+		if modules[info.Module] || !strings.HasPrefix(info.ID, info.Module+"/") {
+			return fmt.Errorf("%s: lesson %s can not be added to module %s", program, info.ID, info.Module)
+		}
+
+		m := &manifest.Manifest{
+			Title:         info.Metadata.Title,
+			Difficulty:    manifest.Difficulty(info.Metadata.Difficulty),
+			Prerequisites: info.Metadata.Prerequisites,
+			Objectives:    info.Metadata.Objectives,
+		}
+		if m.Title == "" {
+			m.Title = manifest.TitleFromDir(filepath.Base(info.ID))
+		}
+
+		err = m.Validate()
+		if err != nil {
+			return fmt.Errorf("%s: invalid lesson %s: %w", program, info.ID, err)
+		}
+
+		lessons = append(lessons, Lesson{
+			ID:             info.ID,
+			Module:         info.Module,
+			Manifest:       m,
+			Pack:           program,
+			ExpectedOutput: info.ExpectedOutput,
+		})
+	}
+
+	slices.SortFunc(lessons, func(a, b Lesson) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	// The registry is only changed once the lessons of the pack are known to
+	// fit into the order of the others
+	added := &Registry{root: r.root, fsys: r.fsys, lessons: lessons}
+
+	_, err = added.order()
+	if err != nil {
+		return fmt.Errorf("%s: failed to order lessons: %w", program, err)
+	}
+
+	r.lessons = lessons
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package lesson
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/manedurphy/golang-university/pack"
+)
+
+// writePack writes a pack program to dir, which lists the lessons of list,
+// a JSON array of pack.LessonInfo
+func writePack(t *testing.T, dir, name, list string) string {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake pack is a shell script")
+	}
+
+	program := filepath.Join(dir, pack.Prefix+name)
+	script := "#!/bin/sh\ncat <<'EOF'\n" + list + "\nEOF\n"
+
+	err := os.WriteFile(program, []byte(script), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return program
+}
+
+func TestFindPacks(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+
+	a := writePack(t, first, "a", "[]")
+	writePack(t, second, "a", "[]")
+	b := writePack(t, second, "b", "[]")
+
+	// Neither a directory nor a file which is not executable is a pack
+	os.Mkdir(filepath.Join(second, pack.Prefix+"dir"), 0o755)
+	os.WriteFile(filepath.Join(second, pack.Prefix+"readme"), nil, 0o644)
+
+	got := FindPacks(strings.Join([]string{first, second}, string(os.PathListSeparator)))
+	if expected := []string{a, b}; !slices.Equal(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestAddPack(t *testing.T) {
+	root := t.TempDir()
+	writeLesson(t, root, "generators/01-basic", "")
+
+	reg, err := Discover(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	program := writePack(t, t.TempDir(), "greetings", `[
+  {"id": "greetings/02-goodbye", "module": "greetings", "metadata": {"title": "Goodbye", "prerequisites": ["greetings/01-hello"]}},
+  {"id": "greetings/01-hello", "module": "greetings", "metadata": {"prerequisites": ["generators/01-basic"]}, "expected_output": "hello"}
+]`)
+
+	err = reg.AddPack(context.Background(), program)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"generators/01-basic", "greetings/01-hello", "greetings/02-goodbye"}
+	if got := ids(reg.Ordered()); !slices.Equal(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	l, err := reg.Lookup("01-hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if l.Pack != program || l.Module != "greetings" || l.ExpectedOutput != "hello" || l.Manifest.Title != "Hello" {
+		t.Fatalf("expected a lesson of the pack, titled after its name, got %+v", l)
+	}
+
+	if _, err := reg.Source(l); !errors.Is(err, ErrNoSource) {
+		t.Fatalf("expected ErrNoSource, got %v", err)
+	}
+}
+
+func TestAddPackErrors(t *testing.T) {
+	root := t.TempDir()
+	writeLesson(t, root, "generators/01-basic", "")
+
+	tests := []struct {
+		name string
+		list string
+	}{
+		{"module of the course", `[{"id": "generators/02-extra", "module": "generators"}]`},
+		{"ID outside its module", `[{"id": "other/01-hello", "module": "greetings"}]`},
+		{"unknown prerequisite", `[{"id": "greetings/01-hello", "module": "greetings", "metadata": {"prerequisites": ["greetings/00-missing"]}}]`},
+		{"invalid difficulty", `[{"id": "greetings/01-hello", "module": "greetings", "metadata": {"difficulty": "easy"}}]`},
+		{"not JSON", `hello`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg, err := Discover(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = reg.AddPack(context.Background(), writePack(t, t.TempDir(), "bad", tt.list))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			// A pack which fails adds none of its lessons
+			if got := ids(reg.All()); !slices.Equal(got, []string{"generators/01-basic"}) {
+				t.Fatalf("expected only the lessons of the course, got %v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package lesson
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// File is a source file of a lesson
+type File struct {
+	// Name is the file's name, relative to the lesson's directory
+	Name    string
+	Content []byte
+}
+
+// SolutionTag is the build tag which selects the solution of an exercise.
+// A file named like take_solution.go is only built with the tag, and
+// replaces take.go, which is only built without it.
+const SolutionTag = "solution"
+
+// solutionSuffix is the suffix of the files of a solution
+const solutionSuffix = "_" + SolutionTag + ".go"
+
+// Source returns the Go files of the lesson, excluding its tests and the
+// files of its solution. The file declaring the main function, main.go,
+// comes first and the others follow in the order of their names. The files
+// are read from the registry's file system, so they can be read whether or
+// not the lessons are on disk.
+func (r *Registry) Source(l Lesson) ([]File, error) {
+	return r.source(l, false)
+}
+
+// Solution returns the Go files of the lesson like Source, but with the
+// files of its solution in place of the files they replace
+func (r *Registry) Solution(l Lesson) ([]File, error) {
+	return r.source(l, true)
+}
+
+// HasSolution reports whether the lesson is an exercise with a solution
+func (r *Registry) HasSolution(l Lesson) bool {
+	matches, err := fs.Glob(r.fsys, path.Join(l.ID, "*"+solutionSuffix))
+	return err == nil && len(matches) > 0
+}
+
+func (r *Registry) source(l Lesson, solution bool) ([]File, error) {
+	if l.Pack != "" {
+		return nil, fmt.Errorf("%w: %s is part of %s", ErrNoSource, l.ID, filepath.Base(l.Pack))
+	}
+
+	entries, err := fs.ReadDir(r.fsys, l.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", l.ID, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && path.Ext(name) == ".go" && !strings.HasSuffix(name, "_test.go") {
+			names = append(names, name)
+		}
+	}
+
+	var files []File
+	for _, name := range names {
+		// The files of the solution replace the files of the exercise with
+		// the same name
+		isSolution := strings.HasSuffix(name, solutionSuffix)
+		replaced := slices.Contains(names, strings.TrimSuffix(name, ".go")+solutionSuffix)
+		if isSolution && !solution || replaced && solution {
+			continue
+		}
+
+		content, err := fs.ReadFile(r.fsys, path.Join(l.ID, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		files = append(files, File{Name: name, Content: content})
+	}
+
+	slices.SortStableFunc(files, func(a, b File) int {
+		switch {
+		case a.Name == "main.go":
+			return -1
+		case b.Name == "main.go":
+			return 1
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return files, nil
+}
+
+// ReadFile reads a file of the lesson other than its source, such as its
+// expected output, from the registry's file system
+func (r *Registry) ReadFile(l Lesson, name string) ([]byte, error) {
+	return fs.ReadFile(r.fsys, path.Join(l.ID, name))
+}
@@ -0,0 +1,68 @@
+package lesson
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSolution(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a/01-exercise/main.go":          {Data: []byte("package main\n\nfunc main() {}\n")},
+		"a/01-exercise/take.go":          {Data: []byte("//go:build !solution\n\npackage main\n")},
+		"a/01-exercise/take_solution.go": {Data: []byte("//go:build solution\n\npackage main\n")},
+		"a/01-exercise/take_test.go":     {Data: []byte("package main\n")},
+		"a/01-exercise/util.go":          {Data: []byte("package main\n")},
+		"a/02-example/main.go":           {Data: []byte("package main\n\nfunc main() {}\n")},
+	}
+
+	reg, err := DiscoverFS(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := func(files []File, err error) []string {
+		t.Helper()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var names []string
+		for _, f := range files {
+			names = append(names, f.Name)
+		}
+
+		return names
+	}
+
+	exercise, err := reg.Lookup("a/01-exercise")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := names(reg.Source(exercise))
+	expected := []string{"main.go", "take.go", "util.go"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected the exercise to be %v, got %v", expected, got)
+	}
+
+	got = names(reg.Solution(exercise))
+	expected = []string{"main.go", "take_solution.go", "util.go"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected the solution to be %v, got %v", expected, got)
+	}
+
+	if !reg.HasSolution(exercise) {
+		t.Error("expected the exercise to have a solution")
+	}
+
+	example, err := reg.Lookup("a/02-example")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reg.HasSolution(example) {
+		t.Error("expected the example not to have a solution")
+	}
+}
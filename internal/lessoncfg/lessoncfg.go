@@ -0,0 +1,131 @@
+package lessoncfg
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config holds the knobs which are common to every lesson, so that the same
+// flag means the same thing wherever it is used
+type Config struct {
+	// Seed seeds the lesson's random values. Two runs with the same seed
+	// generate the same values.
+	Seed uint64
+	// Count is the number of items the lesson works on, such as courses
+	Count int
+	// Timeout bounds how long the lesson runs. Zero means no limit.
+	Timeout time.Duration
+	// DataDir is the directory the lesson stores its files in, such as its
+	// database
+	DataDir string
+}
+
+// The environment variables which override the lesson's defaults. They make
+// it possible to configure every lesson at once, for example from the
+// university CLI.
+const (
+	EnvSeed    = "UNIVERSITY_SEED"
+	EnvCount   = "UNIVERSITY_COUNT"
+	EnvTimeout = "UNIVERSITY_TIMEOUT"
+	EnvDataDir = "UNIVERSITY_DATA_DIR"
+)
+
+var flags Config
+
+// The flags are registered on the default flag set when the package is
+// imported, so every lesson which imports this package accepts them
+func init() {
+	flag.Uint64Var(&flags.Seed, "seed", 0, "Seed the lesson's random values, a random seed is used if it is zero (overrides $"+EnvSeed+")")
+	flag.IntVar(&flags.Count, "count", 0, "The number of items the lesson works on, such as courses (overrides $"+EnvCount+")")
+	flag.DurationVar(&flags.Timeout, "timeout", 0, "Stop the lesson after the specified duration, 0 never stops it (overrides $"+EnvTimeout+")")
+	flag.StringVar(&flags.DataDir, "data-dir", "", "The directory for storing the lesson's files (overrides $"+EnvDataDir+")")
+}
+
+// Load returns the lesson's configuration. A knob given on the command line
+// wins over its environment variable, which wins over the lesson's default.
+// A seed which is still zero is chosen at random, so the seed of any run can
+// be logged and used again. Load parses the command line if the lesson has
+// not done so yet, and exits like an invalid flag if an environment variable
+// is invalid.
+func Load(defaults Config) Config {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+
+	cfg, err := fromEnv(defaults, os.Getenv)
+	if err != nil {
+		fmt.Fprintln(flag.CommandLine.Output(), err)
+		os.Exit(2)
+	}
+
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "seed":
+			cfg.Seed = flags.Seed
+		case "count":
+			cfg.Count = flags.Count
+		case "timeout":
+			cfg.Timeout = flags.Timeout
+		case "data-dir":
+			cfg.DataDir = flags.DataDir
+		}
+	})
+
+	for cfg.Seed == 0 {
+		cfg.Seed = rand.Uint64()
+	}
+
+	return cfg
+}
+
+// fromEnv overrides the knobs of cfg which have an environment variable
+func fromEnv(cfg Config, getenv func(string) string) (Config, error) {
+	var err error
+
+	if v := getenv(EnvSeed); v != "" {
+		cfg.Seed, err = strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid value %q for $%s: %w", v, EnvSeed, err)
+		}
+	}
+
+	if v := getenv(EnvCount); v != "" {
+		cfg.Count, err = strconv.Atoi(v)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid value %q for $%s: %w", v, EnvCount, err)
+		}
+	}
+
+	if v := getenv(EnvTimeout); v != "" {
+		cfg.Timeout, err = time.ParseDuration(v)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid value %q for $%s: %w", v, EnvTimeout, err)
+		}
+	}
+
+	if v := getenv(EnvDataDir); v != "" {
+		cfg.DataDir = v
+	}
+
+	return cfg, nil
+}
+
+// Context returns a context which is cancelled once the timeout has elapsed,
+// or only when cancel is called if there is no timeout
+func (c Config) Context() (context.Context, context.CancelFunc) {
+	if c.Timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+
+	return context.WithTimeout(context.Background(), c.Timeout)
+}
+
+// Rand returns a random number generator seeded with the configured seed
+func (c Config) Rand() *rand.Rand {
+	return rand.New(rand.NewPCG(c.Seed, 0))
+}
@@ -0,0 +1,54 @@
+package lessoncfg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFromEnv(t *testing.T) {
+	defaults := Config{Count: 3, DataDir: "."}
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected Config
+		err      bool
+	}{
+		{name: "defaults", env: nil, expected: defaults},
+		{
+			name: "overrides",
+			env: map[string]string{
+				EnvSeed:    "7",
+				EnvCount:   "10",
+				EnvTimeout: "1s",
+				EnvDataDir: "/tmp",
+			},
+			expected: Config{Seed: 7, Count: 10, Timeout: time.Second, DataDir: "/tmp"},
+		},
+		{name: "partial", env: map[string]string{EnvCount: "0"}, expected: Config{DataDir: "."}},
+		{name: "invalid seed", env: map[string]string{EnvSeed: "-1"}, err: true},
+		{name: "invalid count", env: map[string]string{EnvCount: "many"}, err: true},
+		{name: "invalid timeout", env: map[string]string{EnvTimeout: "10"}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := fromEnv(defaults, func(key string) string { return tt.env[key] })
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", cfg)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if cfg != tt.expected {
+				t.Fatalf("expected %+v, got %+v", tt.expected, cfg)
+			}
+		})
+	}
+}
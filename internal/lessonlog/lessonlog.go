@@ -0,0 +1,81 @@
+package lessonlog
+
+import (
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// Keys used by every lesson, so that tools reading the output of different
+// lessons can rely on the same names
+const (
+	KeyErr      = "err"
+	KeyDuration = "duration_ms"
+	KeyCourse   = "course"
+	KeyCount    = "count"
+	KeyWorker   = "worker"
+)
+
+// Flags are the values of the -v, -q, and -json flags
+type Flags struct {
+	Verbose bool
+	Quiet   bool
+	JSON    bool
+}
+
+// RegisterFlags registers the -v, -q, and -json flags on fs, which is
+// flag.CommandLine in a lesson, and returns the values they are parsed into
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := new(Flags)
+	fs.BoolVar(&f.Verbose, "v", false, "Log debug messages as well")
+	fs.BoolVar(&f.Quiet, "q", false, "Only log warnings and errors")
+	fs.BoolVar(&f.JSON, "json", false, "Log one JSON object per line instead of text")
+
+	return f
+}
+
+// New returns a logger which writes to stdout, configured by f. It is called
+// once the command line has been parsed.
+func New(f *Flags) *slog.Logger {
+	return newLogger(os.Stdout, f)
+}
+
+// newLogger returns a logger with the given level and format. The text
+// format leaves out the time, so the output of a lesson only changes when
+// its behavior does, and can be compared with an expected output.
+func newLogger(w io.Writer, f *Flags) *slog.Logger {
+	level := slog.LevelInfo
+	switch {
+	case f.Quiet:
+		level = slog.LevelWarn
+	case f.Verbose:
+		level = slog.LevelDebug
+	}
+
+	if f.JSON {
+		return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+	}
+
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))
+}
+
+// Err returns an attribute for an error
+func Err(err error) slog.Attr {
+	return slog.Any(KeyErr, err)
+}
+
+// Duration returns an attribute for a duration in milliseconds
+func Duration(d time.Duration) slog.Attr {
+	return slog.Int64(KeyDuration, d.Milliseconds())
+}
@@ -0,0 +1,99 @@
+package lessonlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// logAll logs a message at every level
+func logAll(w io.Writer, f *Flags) {
+	logger := newLogger(w, f)
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+}
+
+func TestRegisterFlags(t *testing.T) {
+	fs := flag.NewFlagSet("lesson", flag.ContinueOnError)
+	f := RegisterFlags(fs)
+
+	if err := fs.Parse([]string{"-v", "-json"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if want := (Flags{Verbose: true, JSON: true}); *f != want {
+		t.Fatalf("expected %+v, got %+v", want, *f)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags Flags
+		want  []string
+	}{
+		{"default", Flags{}, []string{"info", "warn", "error"}},
+		{"verbose", Flags{Verbose: true}, []string{"debug", "info", "warn", "error"}},
+		{"quiet", Flags{Quiet: true}, []string{"warn", "error"}},
+		{"quiet wins", Flags{Verbose: true, Quiet: true}, []string{"warn", "error"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logAll(&buf, &tt.flags)
+
+			var got []string
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				_, msg, _ := strings.Cut(line, "msg=")
+				got = append(got, msg)
+			}
+
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTextOmitsTime(t *testing.T) {
+	var buf bytes.Buffer
+	newLogger(&buf, &Flags{}).Info("received course", KeyCourse, "Go")
+
+	if got, want := buf.String(), "level=INFO msg=\"received course\" course=Go\n"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var buf bytes.Buffer
+	newLogger(&buf, &Flags{JSON: true}).Error("failed to get course",
+		Err(errors.New("closed")), Duration(1500*time.Millisecond))
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected a JSON object, got %q: %v", buf.String(), err)
+	}
+
+	if _, ok := record["time"]; !ok {
+		t.Fatalf("expected a time, got %v", record)
+	}
+
+	for key, want := range map[string]any{
+		"level":     "ERROR",
+		"msg":       "failed to get course",
+		KeyErr:      "closed",
+		KeyDuration: 1500.0,
+	} {
+		if record[key] != want {
+			t.Fatalf("expected %s to be %v, got %v", key, want, record[key])
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package lessontrace
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type (
+	// Kind is the side of the program an event happened on
+	Kind string
+
+	// Event is a line the lesson printed
+	Event struct {
+		// Seq numbers the events in the order they happened, from 1
+		Seq int
+		// At is the time since the lesson printed its first line
+		At    time.Duration
+		Kind  Kind
+		Label string
+	}
+
+	// Rule names the event of every line of a lesson's output which matches
+	// Match. The rules of a lesson are listed under trace in its lesson.yaml,
+	// so that the lesson itself only prints its messages.
+	Rule struct {
+		Kind  Kind   `yaml:"kind"`
+		Match string `yaml:"match"`
+		// Label may refer to the submatches of Match like
+		// regexp.Regexp.Expand does, such as "yield $1"
+		Label string `yaml:"label"`
+	}
+
+	// Tracer is a writer which numbers and timestamps every line written to
+	// it, by the first rule the line matches, before writing it to the
+	// underlying writer
+	Tracer struct {
+		w       io.Writer
+		rules   []rule
+		start   time.Time
+		events  []Event
+		partial []byte
+	}
+
+	// rule is a Rule whose pattern is compiled
+	rule struct {
+		Rule
+		re *regexp.Regexp
+	}
+)
+
+const (
+	// Producer events happen in an iterator, such as a yield
+	Producer Kind = "producer"
+	// Consumer events happen in the code ranging over an iterator, such as
+	// the loop body
+	Consumer Kind = "consumer"
+	// Defer events happen in a deferred function
+	Defer Kind = "defer"
+)
+
+// narrativeWidth is the width the narrative is wrapped at
+const narrativeWidth = 80
+
+// Validate checks that the kind of the rule is known and that its pattern
+// compiles
+func (r Rule) Validate() error {
+	switch r.Kind {
+	case Producer, Consumer, Defer:
+	default:
+		return fmt.Errorf("unknown kind %q of trace rule %q", r.Kind, r.Match)
+	}
+
+	_, err := regexp.Compile(r.Match)
+	if err != nil {
+		return fmt.Errorf("invalid trace rule: %w", err)
+	}
+
+	return nil
+}
+
+// New returns a Tracer which writes to w. A line which matches none of the
+// rules is labelled with its own text, and has no kind.
+func New(w io.Writer, rules []Rule) (*Tracer, error) {
+	t := &Tracer{w: w}
+
+	for _, r := range rules {
+		err := r.Validate()
+		if err != nil {
+			return nil, err
+		}
+
+		t.rules = append(t.rules, rule{Rule: r, re: regexp.MustCompile(r.Match)})
+	}
+
+	return t, nil
+}
+
+// Write traces every complete line of p. The rest of p is kept until the
+// line is completed by a later call, or until Close.
+func (t *Tracer) Write(p []byte) (int, error) {
+	t.partial = append(t.partial, p...)
+
+	for {
+		i := bytes.IndexByte(t.partial, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+
+		err := t.trace(string(t.partial[:i]))
+		t.partial = t.partial[i+1:]
+
+		if err != nil {
+			return len(p), err
+		}
+	}
+}
+
+// Close traces the last line if it was not terminated by a newline, and
+// narrates the events which happened
+func (t *Tracer) Close() error {
+	if len(t.partial) > 0 {
+		err := t.trace(string(t.partial))
+		t.partial = nil
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return t.narrate()
+}
+
+// trace records the event of line, and writes it after the event's number,
+// time, and kind
+func (t *Tracer) trace(line string) error {
+	now := time.Now()
+	if t.events == nil {
+		t.start = now
+	}
+
+	e := Event{Seq: len(t.events) + 1, At: now.Sub(t.start), Label: line}
+	for _, r := range t.rules {
+		match := r.re.FindStringSubmatchIndex(line)
+		if match == nil {
+			continue
+		}
+
+		e.Kind = r.Kind
+		e.Label = string(r.re.ExpandString(nil, r.Label, line, match))
+		break
+	}
+
+	t.events = append(t.events, e)
+
+	_, err := fmt.Fprintf(t.w, "[%02d %9s %-8s] %s\n", e.Seq, "+"+e.At.Round(time.Microsecond).String(), e.Kind, line)
+	return err
+}
+
+// narrate writes the labels of every event in the order they happened, such
+// as "yield 21 → consumer body → iterator cleanup"
+func (t *Tracer) narrate() error {
+	if len(t.events) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+
+	b.WriteString("\nsequence of events:\n")
+	for _, line := range wrap(t.events, narrativeWidth) {
+		fmt.Fprintf(&b, "  %s\n", line)
+	}
+
+	_, err := io.WriteString(t.w, b.String())
+	return err
+}
+
+// wrap joins the labels of the events with arrows, and wraps them into lines
+// of at most width characters. A label is never split across lines.
+func wrap(events []Event, width int) []string {
+	var (
+		lines []string
+		line  strings.Builder
+	)
+
+	for i, e := range events {
+		step := e.Label
+		if i < len(events)-1 {
+			step += " →"
+		}
+
+		if line.Len() > 0 && len([]rune(line.String()))+1+len([]rune(step)) > width {
+			lines = append(lines, line.String())
+			line.Reset()
+		}
+
+		if line.Len() > 0 {
+			line.WriteByte(' ')
+		}
+		line.WriteString(step)
+	}
+
+	return append(lines, line.String())
+}
+
+// Trace traces the lines of out as if they were printed at once, and returns
+// them along with their narrative. It is how a test checks the events of a
+// lesson's output.
+func Trace(out []byte, rules []Rule) ([]byte, error) {
+	var buf bytes.Buffer
+
+	t, err := New(&buf, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = t.Write(out)
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,72 @@
+package lessontrace
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestTracer(t *testing.T) {
+	var buf bytes.Buffer
+
+	tracer, err := New(&buf, []Rule{
+		{Kind: Producer, Match: `^hello: (\d+)$`, Label: "yield $1"},
+		{Kind: Consumer, Match: `^value`, Label: "consumer body"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A line may be split across writes, and the last one may not end with
+	// a newline
+	for _, p := range []string{"hello: 1\nval", "ue: 1\n", "bye"} {
+		_, err = io.WriteString(tracer, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = tracer.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pattern := regexp.MustCompile(`^\[01 +\+\S+ producer\] hello: 1
+\[02 +\+\S+ consumer\] value: 1
+\[03 +\+\S+ {9}\] bye
+
+sequence of events:
+  yield 1 → consumer body → bye
+$`)
+	if !pattern.MatchString(buf.String()) {
+		t.Fatalf("expected numbered events and a narrative, got\n%s", buf.String())
+	}
+}
+
+func TestNewInvalidRule(t *testing.T) {
+	for _, r := range []Rule{
+		{Kind: "loop", Match: `^value`},
+		{Kind: Consumer, Match: `(`},
+	} {
+		_, err := New(io.Discard, []Rule{r})
+		if err == nil {
+			t.Fatalf("expected the rule %+v to be rejected", r)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	var events []Event
+	for _, label := range []string{"yield 20", "consumer body", "break", "iterator cleanup"} {
+		events = append(events, Event{Label: label})
+	}
+
+	got := wrap(events, 30)
+	expected := []string{"yield 20 → consumer body →", "break → iterator cleanup"}
+
+	if !slices.Equal(got, expected) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
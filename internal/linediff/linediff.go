@@ -0,0 +1,196 @@
+package linediff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Op is the kind of an edit
+type Op int
+
+const (
+	// Equal keeps a line which is in both a and b
+	Equal Op = iota
+	// Delete removes a line which is only in a
+	Delete
+	// Insert adds a line which is only in b
+	Insert
+)
+
+// Edit is a single step of the script which turns a into b. A and B are the
+// indexes of the line in a and b, and are -1 for a line which is not in
+// that side.
+type Edit struct {
+	Op   Op
+	A, B int
+}
+
+// Lines returns the shortest edit script which turns a into b, using Myers'
+// diff algorithm. Deletions come before the insertions next to them, so a
+// changed line is a Delete followed by an Insert.
+func Lines(a, b []string) []Edit {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+
+	// v holds the furthest x reached on each diagonal k = x - y, and trace
+	// holds a copy of v before each round, to walk the path back afterwards
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, slices.Clone(v))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, offset, n, m)
+			}
+		}
+	}
+
+	return nil
+}
+
+// backtrack walks the rounds recorded in trace back from (n, m) to (0, 0),
+// and returns the edits along the way in order
+func backtrack(trace [][]int, offset, n, m int) []Edit {
+	var edits []Edit
+
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		prevK := k - 1
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		}
+
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, Edit{Op: Equal, A: x, B: y})
+		}
+
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, Edit{Op: Insert, A: -1, B: y - 1})
+			} else {
+				edits = append(edits, Edit{Op: Delete, A: x - 1, B: -1})
+			}
+		}
+
+		x, y = prevX, prevY
+	}
+
+	slices.Reverse(edits)
+	return edits
+}
+
+// SideBySide writes a and b next to each other, fitting each row in width
+// columns. Every row is annotated with the line numbers on each side and a
+// marker in the middle: a blank for an equal line, | for a changed line, <
+// for a line only in a, and > for a line only in b. It ends with a summary
+// of the differences.
+func SideBySide(w io.Writer, nameA, nameB string, a, b []string, width int) error {
+	// Two line numbers, the marker, and the spaces around them
+	column := max((width-15)/2, 10)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%4s  %s   %4s  %s\n", "", pad(nameA, column), "", nameB)
+
+	var (
+		edits                      = Lines(a, b)
+		changed, deleted, inserted int
+		first                      = -1
+	)
+
+	for i := 0; i < len(edits); {
+		// Pair up a run of deletions with the insertions which follow it
+		j := i
+		for j < len(edits) && edits[j].Op == Delete {
+			j++
+		}
+
+		k := j
+		for k < len(edits) && edits[k].Op == Insert {
+			k++
+		}
+
+		if edits[i].Op == Equal {
+			e := edits[i]
+			fmt.Fprintf(bw, "%4d  %s   %4d  %s\n", e.A+1, pad(a[e.A], column), e.B+1, clip(b[e.B], column))
+			i++
+			continue
+		}
+
+		// Every edit before the first difference keeps a line, so its index
+		// is the line number on both sides
+		if first < 0 {
+			first = i
+		}
+
+		dels, ins := edits[i:j], edits[j:k]
+		for r := range max(len(dels), len(ins)) {
+			switch {
+			case r < len(dels) && r < len(ins):
+				changed++
+				fmt.Fprintf(bw, "%4d  %s | %4d  %s\n", dels[r].A+1, pad(a[dels[r].A], column), ins[r].B+1, clip(b[ins[r].B], column))
+			case r < len(dels):
+				deleted++
+				fmt.Fprintf(bw, "%4d  %s <\n", dels[r].A+1, pad(a[dels[r].A], column))
+			default:
+				inserted++
+				fmt.Fprintf(bw, "%4s  %s > %4d  %s\n", "", pad("", column), ins[r].B+1, clip(b[ins[r].B], column))
+			}
+		}
+
+		i = k
+	}
+
+	fmt.Fprintln(bw)
+	if first < 0 {
+		fmt.Fprintln(bw, "the outputs are identical")
+	} else {
+		fmt.Fprintf(bw, "the outputs diverge at line %d: %d changed, %d only in %s, %d only in %s\n", first+1, changed, deleted, nameA, inserted, nameB)
+	}
+
+	return bw.Flush()
+}
+
+// clip shortens s to at most width runes, marking that it was cut with an
+// ellipsis. Tabs are expanded so that they do not break the columns.
+func clip(s string, width int) string {
+	s = strings.ReplaceAll(s, "\t", "    ")
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+
+	return string([]rune(s)[:width-1]) + "…"
+}
+
+// pad clips s, and pads it with spaces to exactly width runes
+func pad(s string, width int) string {
+	s = clip(s, width)
+	return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+}
@@ -0,0 +1,84 @@
+package linediff
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  string
+		edits int
+	}{
+		{name: "empty", a: "", b: "", edits: 0},
+		{name: "identical", a: "abc", b: "abc", edits: 0},
+		{name: "only a", a: "abc", b: "", edits: 3},
+		{name: "only b", a: "", b: "abc", edits: 3},
+		{name: "changed line", a: "abc", b: "axc", edits: 2},
+		{name: "moved line", a: "abcd", b: "bcda", edits: 2},
+		{name: "classic", a: "abcabba", b: "cbabac", edits: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := strings.Split(tt.a, ""), strings.Split(tt.b, "")
+
+			edits := Lines(a, b)
+
+			// Replaying the edits must produce both sides
+			var gotA, gotB []string
+			n := 0
+			for _, e := range edits {
+				switch e.Op {
+				case Equal:
+					if a[e.A] != b[e.B] {
+						t.Fatalf("equal edit between different lines %q and %q", a[e.A], b[e.B])
+					}
+					gotA, gotB = append(gotA, a[e.A]), append(gotB, b[e.B])
+				case Delete:
+					gotA = append(gotA, a[e.A])
+					n++
+				case Insert:
+					gotB = append(gotB, b[e.B])
+					n++
+				}
+			}
+
+			if !slices.Equal(gotA, a) || !slices.Equal(gotB, b) {
+				t.Fatalf("edits %v do not replay %v and %v", edits, a, b)
+			}
+
+			if n != tt.edits {
+				t.Fatalf("expected %d insertions and deletions, got %d", tt.edits, n)
+			}
+		})
+	}
+}
+
+func TestSideBySide(t *testing.T) {
+	a := []string{"value: 20", "value: 21", "stopping iteration", "exiting..."}
+	b := []string{"value: 20", "value: 21", "stopping iteration", "deferred from iterator", "exiting..."}
+
+	var out strings.Builder
+	err := SideBySide(&out, "first", "second", a, b, 55)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each column is 20 runes wide, so the longest line is clipped
+	expected := `      first                        second
+   1  value: 20                 1  value: 20
+   2  value: 21                 2  value: 21
+   3  stopping iteration        3  stopping iteration
+                           >    4  deferred from itera…
+   4  exiting...                5  exiting...
+
+the outputs diverge at line 4: 0 changed, 0 only in first, 1 only in second
+`
+
+	if out.String() != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, out.String())
+	}
+}
@@ -0,0 +1,136 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"go/version"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/manedurphy/golang-university/internal/lessontrace"
+)
+
+type (
+	// Difficulty describes how much prior knowledge a lesson expects
+	Difficulty string
+
+	// Manifest describes a lesson. It is read from the lesson.yaml file in
+	// the lesson's directory.
+	Manifest struct {
+		Title      string     `yaml:"title"`
+		Difficulty Difficulty `yaml:"difficulty"`
+		// Prerequisites are the IDs of the lessons which should be completed
+		// before this one
+		Prerequisites []string `yaml:"prerequisites"`
+		// Objectives are what a student should be able to do after
+		// completing the lesson
+		Objectives []string `yaml:"objectives"`
+		// Go is the oldest version of Go which can build the lesson, such as
+		// 1.24, for lessons which use newer features than the rest of the
+		// course
+		Go string `yaml:"go"`
+		// Browser marks a lesson which only computes, without files, a
+		// database, or the network, so that it can be built for js/wasm and
+		// run in the browser
+		Browser bool `yaml:"browser"`
+		// Trace are the rules by which university run -trace names the
+		// events of the lines the lesson prints
+		Trace []lessontrace.Rule `yaml:"trace"`
+	}
+)
+
+const (
+	Beginner     Difficulty = "beginner"
+	Intermediate Difficulty = "intermediate"
+	Advanced     Difficulty = "advanced"
+)
+
+// File is the name of the file a lesson ships its manifest in
+const File = "lesson.yaml"
+
+// Load reads the manifest in dir. A lesson without a manifest gets a default
+// one, whose title is derived from the name of its directory.
+func Load(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, File))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	return Decode(data, dir)
+}
+
+// Decode decodes the manifest of the lesson in dir from data, which is empty
+// if the lesson has no manifest. It is what Load does once it has read the
+// file, for lessons which are not read from disk.
+func Decode(data []byte, dir string) (*Manifest, error) {
+	m := &Manifest{}
+
+	err := yaml.Unmarshal(data, m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode manifest %s: %w", filepath.Join(dir, File), err)
+	}
+
+	if m.Title == "" {
+		m.Title = TitleFromDir(filepath.Base(dir))
+	}
+
+	err = m.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", filepath.Join(dir, File), err)
+	}
+
+	return m, nil
+}
+
+// Validate checks the fields which can be checked without knowing the other
+// lessons
+func (m *Manifest) Validate() error {
+	switch m.Difficulty {
+	case "", Beginner, Intermediate, Advanced:
+	default:
+		return fmt.Errorf("unknown difficulty %q", m.Difficulty)
+	}
+
+	for _, id := range m.Prerequisites {
+		if strings.TrimSpace(id) == "" {
+			return errors.New("empty prerequisite")
+		}
+	}
+
+	if m.Go != "" && !version.IsValid("go"+m.Go) {
+		return fmt.Errorf("invalid Go version %q, expected a version such as 1.24", m.Go)
+	}
+
+	for _, r := range m.Trace {
+		err := r.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TitleFromDir turns a lesson directory name such as 01-sequence-of-events
+// into a title such as Sequence Of Events
+func TitleFromDir(name string) string {
+	words := strings.Split(name, "-")
+
+	// Drop the number which orders the lessons
+	if len(words) > 1 && strings.Trim(words[0], "0123456789") == "" {
+		words = words[1:]
+	}
+
+	for i, word := range words {
+		if word == "" {
+			continue
+		}
+
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+
+	return strings.Join(words, " ")
+}
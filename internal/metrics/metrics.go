@@ -0,0 +1,160 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"iter"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes the name of every metric of the lessons
+const namespace = "university"
+
+var (
+	addr string
+
+	registry = prometheus.NewRegistry()
+
+	items = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "iterator_items_total",
+		Help:      "The number of items yielded by an iterator.",
+	}, []string{"iterator"})
+
+	errs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "iterator_errors_total",
+		Help:      "The number of errors yielded by an iterator.",
+	}, []string{"iterator"})
+
+	latency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "iterator_item_duration_seconds",
+		Help:      "The time an iterator took to produce an item, without the time spent in the loop body.",
+		Buckets:   prometheus.ExponentialBuckets(1e-6, 4, 12),
+	}, []string{"iterator"})
+)
+
+// The flag is registered on the default flag set when the package is
+// imported, so every lesson which imports this package accepts it
+func init() {
+	flag.StringVar(&addr, "metrics-addr", "", "Serve Prometheus metrics at /metrics on the address, such as :9090, until the lesson is interrupted")
+
+	registry.MustRegister(
+		items,
+		errs,
+		latency,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Instrument returns an iterator which yields the values of seq, and counts
+// them and times how long seq took to produce each of them under the given
+// name
+func Instrument[T any](seq iter.Seq[T], name string) iter.Seq[T] {
+	itemsCounter := items.WithLabelValues(name)
+	observer := latency.WithLabelValues(name)
+
+	return func(yield func(T) bool) {
+		start := time.Now()
+		for v := range seq {
+			observer.Observe(time.Since(start).Seconds())
+			itemsCounter.Inc()
+
+			if !yield(v) {
+				return
+			}
+
+			start = time.Now()
+		}
+	}
+}
+
+// Instrument2 is like Instrument for an iterator over pairs. A pair whose
+// second value is a non-nil error is counted as an error instead of an item,
+// so it fits the iter.Seq2[T, error] iterators of the lessons.
+func Instrument2[K, V any](seq iter.Seq2[K, V], name string) iter.Seq2[K, V] {
+	itemsCounter := items.WithLabelValues(name)
+	errsCounter := errs.WithLabelValues(name)
+	observer := latency.WithLabelValues(name)
+
+	return func(yield func(K, V) bool) {
+		start := time.Now()
+		for k, v := range seq {
+			observer.Observe(time.Since(start).Seconds())
+
+			if err, ok := any(v).(error); ok && err != nil {
+				errsCounter.Inc()
+			} else {
+				itemsCounter.Inc()
+			}
+
+			if !yield(k, v) {
+				return
+			}
+
+			start = time.Now()
+		}
+	}
+}
+
+// Handler returns a handler which serves the metrics in the Prometheus text
+// format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Serve serves the metrics at /metrics on the address given by -metrics-addr
+// while the lesson runs, and does nothing without it. It parses the command
+// line if the lesson has not done so yet. Lessons defer the function it
+// returns, which keeps the metrics around to be scraped once the lesson is
+// done, until it is interrupted.
+//
+//	defer metrics.Serve()()
+func Serve() func() {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+
+	if addr == "" {
+		return func() {}
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to serve metrics: %v\n", err)
+		return func() {}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", Handler())
+
+	srv := &http.Server{Handler: mux}
+	go func() {
+		err := srv.Serve(ln)
+		if !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "failed to serve metrics: %v\n", err)
+		}
+	}()
+
+	return func() {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		fmt.Fprintf(os.Stderr, "serving metrics at http://%s/metrics, press Ctrl-C to exit\n", ln.Addr())
+		<-ctx.Done()
+
+		srv.Close()
+	}
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"errors"
+	"iter"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestInstrument(t *testing.T) {
+	seq := Instrument(slices.Values([]int{1, 2, 3, 4}), "numbers")
+
+	var got []int
+	for n := range seq {
+		got = append(got, n)
+		if n == 3 {
+			break
+		}
+	}
+
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("expected [1 2 3], got %v", got)
+	}
+
+	// The item after the break is never produced, so it is not counted
+	if n := testutil.ToFloat64(items.WithLabelValues("numbers")); n != 3 {
+		t.Fatalf("expected 3 items, got %v", n)
+	}
+
+	if n := testutil.CollectAndCount(latency, namespace+"_iterator_item_duration_seconds"); n == 0 {
+		t.Fatal("expected the latency to be observed")
+	}
+}
+
+func TestInstrument2(t *testing.T) {
+	var seq iter.Seq2[string, error] = func(yield func(string, error) bool) {
+		_ = yield("Physics-1", nil) &&
+			yield("", errors.New("connection reset")) &&
+			yield("Chem-1", nil)
+	}
+
+	for range Instrument2(seq, "courses") {
+	}
+
+	if n := testutil.ToFloat64(items.WithLabelValues("courses")); n != 2 {
+		t.Fatalf("expected 2 items, got %v", n)
+	}
+	if n := testutil.ToFloat64(errs.WithLabelValues("courses")); n != 1 {
+		t.Fatalf("expected 1 error, got %v", n)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	for range Instrument(slices.Values([]string{"a"}), "letters") {
+	}
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := rec.Body.String()
+	for _, metric := range []string{
+		`university_iterator_items_total{iterator="letters"} 1`,
+		`university_iterator_item_duration_seconds_count{iterator="letters"} 1`,
+		"go_goroutines",
+	} {
+		if !strings.Contains(body, metric) {
+			t.Fatalf("expected %s in\n%s", metric, body)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package profile
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+var (
+	cpuProfile   string
+	memProfile   string
+	blockProfile string
+	mutexProfile string
+)
+
+// The flags are registered on the default flag set, like the flags of the
+// lessoncfg package, so every lesson which imports this package accepts them
+func init() {
+	flag.StringVar(&cpuProfile, "cpuprofile", "", "Write a CPU profile of the lesson to the file")
+	flag.StringVar(&memProfile, "memprofile", "", "Write a heap profile of the lesson to the file when it ends")
+	flag.StringVar(&blockProfile, "blockprofile", "", "Write a profile of where the lesson's goroutines blocked to the file")
+	flag.StringVar(&mutexProfile, "mutexprofile", "", "Write a profile of the lesson's contended mutexes to the file")
+}
+
+// Start starts the profiles which were asked for on the command line, and
+// returns a function which stops them and writes them to their files. It
+// must be called after the flags are parsed, such as by lessoncfg.Load. The
+// block and mutex profiles record every event, which slows down a program
+// which blocks a lot, so they are only enabled when asked for.
+func Start() (stop func() error, err error) {
+	var cpuFile *os.File
+
+	if cpuProfile != "" {
+		cpuFile, err = os.Create(cpuProfile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+
+		err = pprof.StartCPUProfile(cpuFile)
+		if err != nil {
+			cpuFile.Close()
+			return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+	}
+
+	if blockProfile != "" {
+		runtime.SetBlockProfileRate(1)
+	}
+	if mutexProfile != "" {
+		runtime.SetMutexProfileFraction(1)
+	}
+
+	stop = func() error {
+		var errs []error
+
+		if cpuFile != nil {
+			pprof.StopCPUProfile()
+			errs = append(errs, cpuFile.Close())
+		}
+
+		if memProfile != "" {
+			// The heap profile is as of the last garbage collection, so one
+			// is run to include the allocations since then
+			runtime.GC()
+			errs = append(errs, write("heap", memProfile))
+		}
+
+		if blockProfile != "" {
+			errs = append(errs, write("block", blockProfile))
+			runtime.SetBlockProfileRate(0)
+		}
+
+		if mutexProfile != "" {
+			errs = append(errs, write("mutex", mutexProfile))
+			runtime.SetMutexProfileFraction(0)
+		}
+
+		return errors.Join(errs...)
+	}
+
+	return stop, nil
+}
+
+// write writes the named profile to path
+func write(name, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s profile: %w", name, err)
+	}
+	defer f.Close()
+
+	err = pprof.Lookup(name).WriteTo(f, 0)
+	if err != nil {
+		return fmt.Errorf("failed to write %s profile: %w", name, err)
+	}
+
+	return f.Close()
+}
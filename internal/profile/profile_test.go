@@ -0,0 +1,77 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestStart(t *testing.T) {
+	dir := t.TempDir()
+
+	paths := map[*string]string{
+		&cpuProfile:   filepath.Join(dir, "cpu.pprof"),
+		&memProfile:   filepath.Join(dir, "mem.pprof"),
+		&blockProfile: filepath.Join(dir, "block.pprof"),
+		&mutexProfile: filepath.Join(dir, "mutex.pprof"),
+	}
+	for flag, path := range paths {
+		*flag = path
+	}
+	t.Cleanup(func() {
+		for flag := range paths {
+			*flag = ""
+		}
+	})
+
+	stop, err := Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Some contention, for the block and mutex profiles to record
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for range 1000 {
+				mu.Lock()
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	err = stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("expected a profile: %v", err)
+		}
+
+		if info.Size() == 0 {
+			t.Fatalf("expected %s to hold a profile", filepath.Base(path))
+		}
+	}
+}
+
+func TestStartNothing(t *testing.T) {
+	stop, err := Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := stop(); err != nil {
+		t.Fatalf("expected nothing to fail without profiles, got %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package progress
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type (
+	// Entry records when and how a lesson was completed
+	Entry struct {
+		Completed time.Time `json:"completed"`
+		// Via is the command which completed the lesson, such as check
+		Via string `json:"via"`
+	}
+
+	// Quiz records the latest and the best score of a module's quiz
+	Quiz struct {
+		Taken time.Time `json:"taken"`
+		Score int       `json:"score"`
+		Best  int       `json:"best"`
+		Total int       `json:"total"`
+	}
+
+	// Progress is the set of completed lessons and quiz scores, persisted as
+	// a JSON file
+	Progress struct {
+		path    string
+		Lessons map[string]Entry `json:"lessons"`
+		Quizzes map[string]Quiz  `json:"quizzes,omitempty"`
+	}
+)
+
+// Load reads the progress file at path. A missing file is not an error, it
+// simply means that no lessons have been completed yet.
+func Load(path string) (*Progress, error) {
+	p := &Progress{path: path, Lessons: make(map[string]Entry), Quizzes: make(map[string]Quiz)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read progress: %w", err)
+	}
+
+	err = json.Unmarshal(data, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode progress %s: %w", path, err)
+	}
+
+	if p.Lessons == nil {
+		p.Lessons = make(map[string]Entry)
+	}
+
+	if p.Quizzes == nil {
+		p.Quizzes = make(map[string]Quiz)
+	}
+
+	return p, nil
+}
+
+// Save writes the progress file. The file is written to a temporary file
+// first and then renamed, so an interrupted save never corrupts it.
+func (p *Progress) Save() error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode progress: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(p.path), 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create progress directory: %w", err)
+	}
+
+	tmp := p.path + ".tmp"
+	err = os.WriteFile(tmp, append(data, '\n'), 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write progress: %w", err)
+	}
+
+	err = os.Rename(tmp, p.path)
+	if err != nil {
+		return fmt.Errorf("failed to replace progress: %w", err)
+	}
+
+	return nil
+}
+
+// Complete marks a lesson as completed. A lesson which was already completed
+// keeps its original entry.
+func (p *Progress) Complete(id, via string) {
+	if _, ok := p.Lessons[id]; ok {
+		return
+	}
+
+	p.Lessons[id] = Entry{Completed: time.Now().UTC(), Via: via}
+}
+
+// RecordQuiz records the score of a module's quiz. The best score is kept
+// across attempts, unless the number of questions has changed since.
+func (p *Progress) RecordQuiz(module string, score, total int) {
+	best := score
+	if q, ok := p.Quizzes[module]; ok && q.Total == total {
+		best = max(best, q.Best)
+	}
+
+	p.Quizzes[module] = Quiz{Taken: time.Now().UTC(), Score: score, Best: best, Total: total}
+}
+
+// IsComplete reports whether a lesson has been completed
+func (p *Progress) IsComplete(id string) bool {
+	_, ok := p.Lessons[id]
+	return ok
+}
+
+// Reset forgets every completed lesson whose ID is prefix, or is inside the
+// directory prefix, along with the quiz of a module which is reset. An empty
+// prefix forgets every lesson and quiz. It returns the number of lessons
+// forgotten.
+func (p *Progress) Reset(prefix string) int {
+	prefix = strings.Trim(prefix, "/")
+
+	for module := range p.Quizzes {
+		if prefix == "" || module == prefix {
+			delete(p.Quizzes, module)
+		}
+	}
+
+	n := 0
+	for id := range p.Lessons {
+		if prefix == "" || id == prefix || strings.HasPrefix(id, prefix+"/") {
+			delete(p.Lessons, id)
+			n++
+		}
+	}
+
+	return n
+}
+
+// Last returns the ID of the lesson which was completed most recently, and
+// false if no lesson has been completed
+func (p *Progress) Last() (string, bool) {
+	var (
+		last  string
+		entry Entry
+	)
+
+	for id, e := range p.Lessons {
+		if last == "" || e.Completed.After(entry.Completed) || (e.Completed.Equal(entry.Completed) && id > last) {
+			last, entry = id, e
+		}
+	}
+
+	return last, last != ""
+}
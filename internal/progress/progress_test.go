@@ -0,0 +1,211 @@
+package progress
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// ids returns the IDs of the completed lessons, sorted
+func ids(p *Progress) []string {
+	var ids []string
+	for id := range p.Lessons {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+	return ids
+}
+
+func TestReset(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		n        int
+		expected []string
+		quizzes  []string
+	}{
+		{"iterators", 2, []string{"iteratorsX/01-basic"}, []string{"generics"}},
+		{"/iterators/", 2, []string{"iteratorsX/01-basic"}, []string{"generics"}},
+		{"iterators/01-basic", 1, []string{"iterators/02-range", "iteratorsX/01-basic"}, []string{"generics", "iterators"}},
+		{"iterators/01", 0, []string{"iterators/01-basic", "iterators/02-range", "iteratorsX/01-basic"}, []string{"generics", "iterators"}},
+		{"", 3, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			p, err := Load(filepath.Join(t.TempDir(), "progress.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, id := range []string{"iterators/01-basic", "iterators/02-range", "iteratorsX/01-basic"} {
+				p.Complete(id, "check")
+			}
+			p.RecordQuiz("iterators", 3, 4)
+			p.RecordQuiz("generics", 2, 4)
+
+			if n := p.Reset(tt.prefix); n != tt.n {
+				t.Fatalf("expected %d lessons to be forgotten, got %d", tt.n, n)
+			}
+
+			if got := ids(p); !slices.Equal(got, tt.expected) {
+				t.Fatalf("expected %v to remain, got %v", tt.expected, got)
+			}
+
+			var quizzes []string
+			for module := range p.Quizzes {
+				quizzes = append(quizzes, module)
+			}
+			slices.Sort(quizzes)
+
+			if !slices.Equal(quizzes, tt.quizzes) {
+				t.Fatalf("expected the quizzes %v to remain, got %v", tt.quizzes, quizzes)
+			}
+		})
+	}
+}
+
+func TestLast(t *testing.T) {
+	p, err := Load(filepath.Join(t.TempDir(), "progress.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := p.Last(); ok {
+		t.Fatal("expected no lesson without progress")
+	}
+
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	p.Lessons["iterators/01-basic"] = Entry{Completed: at.Add(time.Hour), Via: "check"}
+	p.Lessons["iterators/02-range"] = Entry{Completed: at, Via: "check"}
+
+	if last, _ := p.Last(); last != "iterators/01-basic" {
+		t.Fatalf("expected the most recent lesson, got %q", last)
+	}
+
+	// Lessons completed at the same time are ordered by their ID, so that
+	// the result does not depend on the order of the map
+	p.Lessons["iterators/03-deep-dive"] = Entry{Completed: at.Add(time.Hour), Via: "run"}
+	p.Lessons["generics/01-type-parameters"] = Entry{Completed: at.Add(time.Hour), Via: "run"}
+
+	for range 10 {
+		if last, _ := p.Last(); last != "iterators/03-deep-dive" {
+			t.Fatalf("expected the greatest ID of a tie, got %q", last)
+		}
+	}
+}
+
+func TestRecordQuiz(t *testing.T) {
+	p, err := Load(filepath.Join(t.TempDir(), "progress.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.RecordQuiz("iterators", 3, 4)
+	p.RecordQuiz("iterators", 1, 4)
+
+	if q := p.Quizzes["iterators"]; q.Score != 1 || q.Best != 3 {
+		t.Fatalf("expected a score of 1 and a best of 3, got %+v", q)
+	}
+
+	// The quiz changed, so the best score of the old one no longer counts
+	p.RecordQuiz("iterators", 2, 5)
+
+	if q := p.Quizzes["iterators"]; q.Score != 2 || q.Best != 2 || q.Total != 5 {
+		t.Fatalf("expected a score and best of 2 out of 5, got %+v", q)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	p, err := Load(filepath.Join(t.TempDir(), "missing", "progress.json"))
+	if err != nil {
+		t.Fatalf("expected no error for a missing file, got %v", err)
+	}
+
+	if len(p.Lessons) != 0 || p.IsComplete("iterators/01-basic") {
+		t.Fatalf("expected no completed lessons, got %v", p.Lessons)
+	}
+
+	// The maps are ready to be written to
+	p.Complete("iterators/01-basic", "check")
+	p.RecordQuiz("iterators", 1, 1)
+}
+
+func TestLoadNullMaps(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.json")
+
+	err := os.WriteFile(path, []byte(`{"lessons": null, "quizzes": null}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Lessons == nil || p.Quizzes == nil {
+		t.Fatal("expected the maps to be created")
+	}
+
+	p.Complete("iterators/01-basic", "check")
+	p.RecordQuiz("iterators", 1, 1)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.json")
+
+	err := os.WriteFile(path, []byte(`{"lessons": [`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Fatal("expected an error for an invalid file")
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".university", "progress.json")
+
+	p, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.Complete("iterators/01-basic", "check")
+	p.Complete("iterators/02-range", "run")
+	p.RecordQuiz("iterators", 3, 4)
+
+	// Completing a lesson again keeps its first entry
+	first := p.Lessons["iterators/01-basic"]
+	p.Complete("iterators/01-basic", "grade")
+
+	err = p.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("expected the temporary file to be renamed, got %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ids(loaded); !slices.Equal(got, []string{"iterators/01-basic", "iterators/02-range"}) {
+		t.Fatalf("expected both lessons to be loaded, got %v", got)
+	}
+
+	if e := loaded.Lessons["iterators/01-basic"]; !e.Completed.Equal(first.Completed) || e.Via != "check" {
+		t.Fatalf("expected %+v, got %+v", first, e)
+	}
+
+	if q := loaded.Quizzes["iterators"]; q != p.Quizzes["iterators"] {
+		t.Fatalf("expected %+v, got %+v", p.Quizzes["iterators"], q)
+	}
+}
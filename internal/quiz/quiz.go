@@ -0,0 +1,162 @@
+package quiz
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// Kind is the way a question is answered
+	Kind int
+
+	// Question is a single question of a module's quiz
+	Question struct {
+		Text string `yaml:"question"`
+		// Lesson is the ID of the lesson the question is about, if any. The
+		// output of a predict-the-output question is that of this lesson.
+		Lesson string `yaml:"lesson"`
+		// Choices are the possible answers of a multiple-choice question. A
+		// question without choices asks for a line of the lesson's output.
+		Choices []string `yaml:"choices"`
+		// Answer is the correct choice, or the expected line of output
+		Answer string `yaml:"answer"`
+		// Explanation is shown once the question has been answered
+		Explanation string `yaml:"explanation"`
+	}
+)
+
+const (
+	MultipleChoice Kind = iota
+	PredictOutput
+)
+
+// bankDir holds a question bank for every module, named after the module
+const bankDir = "banks"
+
+//go:embed banks
+var banks embed.FS
+
+// ErrNoBank is returned when loading the quiz of a module without questions
+var ErrNoBank = errors.New("module has no quiz")
+
+// Modules returns the names of the modules which have a quiz, in
+// alphabetical order
+func Modules() []string {
+	entries, err := banks.ReadDir(bankDir)
+	if err != nil {
+		return nil
+	}
+
+	var modules []string
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".yaml"); ok {
+			modules = append(modules, name)
+		}
+	}
+
+	return modules
+}
+
+// Load returns the questions of a module's quiz, in the order they are asked
+func Load(module string) ([]Question, error) {
+	name := path.Join(bankDir, module+".yaml")
+
+	data, err := banks.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNoBank, module)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read question bank: %w", err)
+	}
+
+	var questions []Question
+	err = yaml.Unmarshal(data, &questions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode question bank %s: %w", name, err)
+	}
+
+	for i, q := range questions {
+		err = q.validate()
+		if err != nil {
+			return nil, fmt.Errorf("invalid question %d of %s: %w", i+1, name, err)
+		}
+	}
+
+	return questions, nil
+}
+
+// validate checks that the question can be asked and answered
+func (q Question) validate() error {
+	if strings.TrimSpace(q.Text) == "" {
+		return errors.New("empty question")
+	}
+
+	if strings.TrimSpace(q.Answer) == "" {
+		return errors.New("empty answer")
+	}
+
+	switch q.Kind() {
+	case MultipleChoice:
+		if len(q.Choices) < 2 {
+			return errors.New("expected at least two choices")
+		}
+
+		if !slices.Contains(q.Choices, q.Answer) {
+			return fmt.Errorf("answer %q is not one of the choices", q.Answer)
+		}
+	case PredictOutput:
+		if q.Lesson == "" {
+			return errors.New("predict-the-output question without a lesson")
+		}
+	}
+
+	return nil
+}
+
+// Kind returns how the question is answered
+func (q Question) Kind() Kind {
+	if len(q.Choices) > 0 {
+		return MultipleChoice
+	}
+
+	return PredictOutput
+}
+
+// Label returns the label a choice is picked by, such as b for the second
+// choice
+func Label(i int) string {
+	return string(rune('a' + i))
+}
+
+// Check reports whether the response answers the question. A choice is
+// picked by its label or its number, and a line of output is compared
+// ignoring the spaces around and between its words.
+func (q Question) Check(response string) bool {
+	response = strings.TrimSpace(response)
+
+	if q.Kind() == PredictOutput {
+		return normalize(response) == normalize(q.Answer)
+	}
+
+	i := slices.Index(q.Choices, q.Answer)
+
+	n, err := strconv.Atoi(response)
+	if err == nil {
+		return n == i+1
+	}
+
+	return strings.EqualFold(response, Label(i))
+}
+
+// normalize collapses the whitespace of a line of output
+func normalize(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
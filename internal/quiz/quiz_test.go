@@ -0,0 +1,91 @@
+package quiz
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/check"
+	"github.com/manedurphy/golang-university/internal/lesson"
+)
+
+func TestCheck(t *testing.T) {
+	q := Question{Text: "q", Choices: []string{"one", "two", "three"}, Answer: "two"}
+
+	for _, response := range []string{"b", "B", " b\n", "2"} {
+		if !q.Check(response) {
+			t.Errorf("expected %q to be correct", response)
+		}
+	}
+
+	for _, response := range []string{"a", "3", "two", ""} {
+		if q.Check(response) {
+			t.Errorf("expected %q to be wrong", response)
+		}
+	}
+
+	q = Question{Text: "q", Lesson: "l", Answer: "numbers: cleanup"}
+	if !q.Check("  numbers:   cleanup ") {
+		t.Error("expected the spaces between words to be ignored")
+	}
+	if q.Check("numbers cleanup") {
+		t.Error("expected a different line to be wrong")
+	}
+}
+
+// TestBanks checks that every question can be answered, and that the
+// answers of predict-the-output questions are printed by their lesson
+func TestBanks(t *testing.T) {
+	root, err := lesson.FindRoot(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reg, err := lesson.Discover(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modules := Modules()
+	if len(modules) == 0 {
+		t.Fatal("expected at least one question bank")
+	}
+
+	for _, module := range modules {
+		t.Run(module, func(t *testing.T) {
+			questions, err := Load(module)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, q := range questions {
+				if q.Lesson == "" {
+					continue
+				}
+
+				l, err := reg.Lookup(q.Lesson)
+				if err != nil {
+					t.Fatalf("question %d: %v", i+1, err)
+				}
+
+				if l.Module != module {
+					t.Errorf("question %d: %s is not in the %s module", i+1, l.ID, module)
+				}
+
+				if q.Kind() != PredictOutput {
+					continue
+				}
+
+				expected, err := reg.ReadFile(l, check.ExpectedOutputFile)
+				if err != nil {
+					t.Fatalf("question %d: %v", i+1, err)
+				}
+
+				lines := strings.Split(string(expected), "\n")
+				if !slices.ContainsFunc(lines, q.Check) {
+					t.Errorf("question %d: %s never prints %q", i+1, l.ID, q.Answer)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+type (
+	// Timing is the time and memory spent in the sections of a lesson which
+	// share a name
+	Timing struct {
+		// Count is the number of times the section ran
+		Count int `json:"count"`
+		// Total is the time spent in every run of the section
+		Total time.Duration `json:"total_ns"`
+		// Allocs and AllocBytes are the number of heap objects and bytes
+		// allocated while the section ran, by any goroutine
+		Allocs     uint64 `json:"allocs"`
+		AllocBytes uint64 `json:"alloc_bytes"`
+	}
+
+	// Report is what a lesson recorded during a single run
+	Report struct {
+		// Duration is the time from the start of the program until the
+		// report was written
+		Duration time.Duration `json:"duration_ns"`
+		// Allocs and AllocBytes are the number of heap objects and bytes
+		// the whole program allocated
+		Allocs     uint64            `json:"allocs"`
+		AllocBytes uint64            `json:"alloc_bytes"`
+		Timings    map[string]Timing `json:"timings,omitempty"`
+		Counters   map[string]int64  `json:"counters,omitempty"`
+	}
+)
+
+// Env is the environment variable which tells a lesson where to write its
+// report. The report is only written if it is set, so running a lesson by
+// hand prints nothing extra, and "-" writes it to stderr.
+const Env = "UNIVERSITY_REPORT"
+
+var (
+	start = time.Now()
+
+	mu       sync.Mutex
+	timings  = make(map[string]Timing)
+	counters = make(map[string]int64)
+)
+
+// Time starts timing a section of the lesson, and returns the function which
+// stops it. Sections with the same name are added up.
+//
+//	defer report.Time("seed")()
+func Time(name string) func() {
+	allocs, bytes := readAllocs()
+	now := time.Now()
+
+	return func() {
+		took := time.Since(now)
+		allocsAfter, bytesAfter := readAllocs()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		t := timings[name]
+		t.Count++
+		t.Total += took
+		t.Allocs += allocsAfter - allocs
+		t.AllocBytes += bytesAfter - bytes
+		timings[name] = t
+	}
+}
+
+// Add adds delta to the counter with the given name. It is safe to call from
+// several goroutines.
+func Add(name string, delta int64) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	counters[name] += delta
+}
+
+// Snapshot returns what the lesson has recorded so far
+func Snapshot() *Report {
+	allocs, bytes := readAllocs()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return &Report{
+		Duration:   time.Since(start),
+		Allocs:     allocs,
+		AllocBytes: bytes,
+		Timings:    maps.Clone(timings),
+		Counters:   maps.Clone(counters),
+	}
+}
+
+// Write writes the report to the file named by $UNIVERSITY_REPORT, and does
+// nothing if it is not set. Lessons defer it at the start of main. Failing
+// to write the report should never fail the lesson, so errors are only
+// printed.
+func Write() {
+	path := os.Getenv(Env)
+	if path == "" {
+		return
+	}
+
+	data, err := json.Marshal(Snapshot())
+	if err == nil && path == "-" {
+		_, err = fmt.Fprintf(os.Stderr, "%s\n", data)
+	} else if err == nil {
+		err = os.WriteFile(path, data, 0o644)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write report: %v\n", err)
+	}
+}
+
+// Exit writes the report and exits with the given code. Deferred functions
+// do not run when a lesson calls os.Exit, so lessons which exit early call
+// Exit instead.
+func Exit(code int) {
+	Write()
+	os.Exit(code)
+}
+
+// Read reads a report written by a lesson
+func Read(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	var r Report
+	err = json.Unmarshal(data, &r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode report %s: %w", path, err)
+	}
+
+	return &r, nil
+}
+
+// Mean returns the average of the reports of several runs of a lesson. A
+// timing or counter which is missing from a run counts as zero in that run.
+func Mean(reports []*Report) (*Report, error) {
+	if len(reports) == 0 {
+		return nil, errors.New("no reports to average")
+	}
+
+	mean := &Report{Timings: make(map[string]Timing), Counters: make(map[string]int64)}
+	for _, r := range reports {
+		mean.Duration += r.Duration
+		mean.Allocs += r.Allocs
+		mean.AllocBytes += r.AllocBytes
+
+		for name, t := range r.Timings {
+			sum := mean.Timings[name]
+			sum.Count += t.Count
+			sum.Total += t.Total
+			sum.Allocs += t.Allocs
+			sum.AllocBytes += t.AllocBytes
+			mean.Timings[name] = sum
+		}
+
+		for name, n := range r.Counters {
+			mean.Counters[name] += n
+		}
+	}
+
+	n := len(reports)
+
+	mean.Duration /= time.Duration(n)
+	mean.Allocs /= uint64(n)
+	mean.AllocBytes /= uint64(n)
+
+	for name, t := range mean.Timings {
+		t.Count /= n
+		t.Total /= time.Duration(n)
+		t.Allocs /= uint64(n)
+		t.AllocBytes /= uint64(n)
+		mean.Timings[name] = t
+	}
+
+	for name := range mean.Counters {
+		mean.Counters[name] /= int64(n)
+	}
+
+	return mean, nil
+}
+
+// readAllocs returns the number of heap objects and bytes allocated since
+// the program started. runtime/metrics is cheaper to read, but only counts
+// small objects once their span is used up, which is too coarse for short
+// sections.
+func readAllocs() (uint64, uint64) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	return mem.Mallocs, mem.TotalAlloc
+}
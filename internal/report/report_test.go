@@ -0,0 +1,72 @@
+package report
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeAndAdd(t *testing.T) {
+	for range 2 {
+		done := Time("section")
+		_ = make([]byte, 1<<20)
+		done()
+	}
+
+	Add("items", 2)
+	Add("items", 3)
+
+	r := Snapshot()
+
+	timing := r.Timings["section"]
+	if timing.Count != 2 || timing.Total <= 0 {
+		t.Fatalf("expected the section to be timed twice, got %+v", timing)
+	}
+
+	if timing.AllocBytes < 2<<20 {
+		t.Fatalf("expected at least 2 MiB to be allocated in the section, got %d bytes", timing.AllocBytes)
+	}
+
+	if r.Counters["items"] != 5 {
+		t.Fatalf("expected the counter to be 5, got %d", r.Counters["items"])
+	}
+}
+
+func TestMean(t *testing.T) {
+	reports := []*Report{
+		{
+			Duration: 2 * time.Second,
+			Allocs:   10,
+			Timings:  map[string]Timing{"seed": {Count: 1, Total: time.Second, Allocs: 4}},
+			Counters: map[string]int64{"courses": 10, "errors": 2},
+		},
+		{
+			Duration: 4 * time.Second,
+			Allocs:   20,
+			Timings:  map[string]Timing{"seed": {Count: 1, Total: 3 * time.Second, Allocs: 8}},
+			Counters: map[string]int64{"courses": 20},
+		},
+	}
+
+	mean, err := Mean(reports)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mean.Duration != 3*time.Second || mean.Allocs != 15 {
+		t.Fatalf("expected a duration of 3s and 15 allocs, got %s and %d", mean.Duration, mean.Allocs)
+	}
+
+	if seed := mean.Timings["seed"]; seed.Count != 1 || seed.Total != 2*time.Second || seed.Allocs != 6 {
+		t.Fatalf("expected the timings to be averaged, got %+v", seed)
+	}
+
+	// A counter missing from a run counts as zero in that run
+	if mean.Counters["courses"] != 15 || mean.Counters["errors"] != 1 {
+		t.Fatalf("expected the counters to be averaged, got %v", mean.Counters)
+	}
+
+	_, err = Mean(nil)
+	if err == nil {
+		t.Fatal("expected an error when there are no reports")
+	}
+}
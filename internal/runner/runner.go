@@ -0,0 +1,232 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+	"github.com/manedurphy/golang-university/pack"
+)
+
+// Options configures how a lesson is run
+type Options struct {
+	// Args are passed to the lesson's program
+	Args []string
+	// Stdin is connected to the program's standard input. A nil reader
+	// reads nothing.
+	Stdin io.Reader
+	// Stdout and Stderr receive the program's output. A nil writer
+	// discards it.
+	Stdout io.Writer
+	Stderr io.Writer
+	// Detach starts the lesson in its own process group, so that it can be
+	// killed along with the go command when ctx is done. A detached lesson
+	// does not receive signals sent from the terminal, such as Ctrl+C.
+	Detach bool
+	// Env replaces the environment of the go command and the program when
+	// it is not nil
+	Env []string
+	// Tags are the build tags the lesson is built with, such as the tag
+	// which selects the solution of an exercise
+	Tags []string
+	// Timeout kills the program once it has run for this long, not counting
+	// the time it took to build. Zero means no limit.
+	Timeout time.Duration
+	// MaxOutput kills the program once it has written more than this many
+	// bytes to stdout and stderr together. Zero means no limit.
+	MaxOutput int64
+}
+
+var (
+	// ErrTimeout is returned when a program is killed for running longer
+	// than Options.Timeout
+	ErrTimeout = errors.New("exceeded the time limit")
+	// ErrOutputLimit is returned when a program is killed for writing more
+	// than Options.MaxOutput
+	ErrOutputLimit = errors.New("exceeded the output limit")
+)
+
+// Run builds and runs a lesson with go run, from the repository root so that
+// relative paths such as the database's data directory behave the same as
+// when the lesson is run by hand. A lesson with a time or output limit is
+// built first and its binary run directly instead, so that the limits only
+// apply to the lesson and killing it does not leave a child process behind.
+func Run(ctx context.Context, root string, l lesson.Lesson, opts Options) error {
+	// A lesson of a pack is run by the pack's program, which is already
+	// built
+	if l.Pack != "" {
+		args := append([]string{pack.RunCommand, l.ID}, opts.Args...)
+		return runProgram(ctx, root, l, l.Pack, args, opts)
+	}
+
+	if opts.Timeout > 0 || opts.MaxOutput > 0 {
+		return runLimited(ctx, root, l, opts)
+	}
+
+	args := append([]string{"run"}, buildFlags(opts)...)
+	args = append(args, "./"+filepath.ToSlash(l.Dir))
+	args = append(args, opts.Args...)
+
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = root
+	cmd.Env = opts.Env
+	cmd.Stdin = opts.Stdin
+	cmd.Stdout = opts.Stdout
+	cmd.Stderr = opts.Stderr
+
+	// Stop waiting for the output shortly after the command has been killed,
+	// in case something else is still holding on to it
+	cmd.WaitDelay = time.Second
+
+	if opts.Detach {
+		killGroup(cmd)
+	}
+
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("failed to run %s: %w", l.ID, err)
+	}
+
+	return nil
+}
+
+// buildFlags returns the flags of the go command which build the lesson
+// with the options
+func buildFlags(opts Options) []string {
+	if len(opts.Tags) == 0 {
+		return nil
+	}
+
+	return []string{"-tags", strings.Join(opts.Tags, ",")}
+}
+
+// runLimited builds the lesson into a temporary directory, and runs the
+// binary until it exits or exceeds one of its limits
+func runLimited(ctx context.Context, root string, l lesson.Lesson, opts Options) error {
+	dir, err := os.MkdirTemp("", "university-run-")
+	if err != nil {
+		return fmt.Errorf("failed to create build directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	bin := filepath.Join(dir, path.Base(l.ID))
+	if runtime.GOOS == "windows" {
+		bin += ".exe"
+	}
+
+	// Build errors are reported on stderr, like go run does
+	args := append([]string{"build", "-o", bin}, buildFlags(opts)...)
+	args = append(args, "./"+filepath.ToSlash(l.Dir))
+
+	build := exec.CommandContext(ctx, "go", args...)
+	build.Dir = root
+	build.Env = opts.Env
+	build.Stderr = opts.Stderr
+
+	err = build.Run()
+	if err != nil {
+		return fmt.Errorf("failed to build %s: %w", l.ID, err)
+	}
+
+	return runProgram(ctx, root, l, bin, opts.Args, opts)
+}
+
+// runProgram runs the program of a lesson with args, until it exits or
+// exceeds one of the limits of opts
+func runProgram(ctx context.Context, root string, l lesson.Lesson, program string, args []string, opts Options) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	if opts.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeoutCause(ctx, opts.Timeout, ErrTimeout)
+		defer cancelTimeout()
+	}
+
+	limit := &outputLimit{max: opts.MaxOutput, exceeded: func() { cancel(ErrOutputLimit) }}
+
+	cmd := exec.CommandContext(ctx, program, args...)
+	cmd.Dir = root
+	cmd.Env = opts.Env
+	cmd.Stdin = opts.Stdin
+	cmd.Stdout = limit.writer(opts.Stdout)
+	cmd.Stderr = limit.writer(opts.Stderr)
+	cmd.WaitDelay = time.Second
+
+	if opts.Detach {
+		killGroup(cmd)
+	}
+
+	err := cmd.Run()
+
+	cause := context.Cause(ctx)
+	switch {
+	case errors.Is(cause, ErrTimeout):
+		return fmt.Errorf("%s was killed: %w of %s", l.ID, ErrTimeout, opts.Timeout)
+	case errors.Is(cause, ErrOutputLimit):
+		return fmt.Errorf("%s was killed: %w of %d bytes", l.ID, ErrOutputLimit, opts.MaxOutput)
+	case err != nil:
+		return fmt.Errorf("failed to run %s: %w", l.ID, err)
+	}
+
+	return nil
+}
+
+// outputLimit is shared by the writers of a program's stdout and stderr, and
+// calls exceeded once more than max bytes have been written to them. Output
+// past the limit is dropped, but the writes still succeed, so the program
+// does not block on a full pipe before it is killed.
+type outputLimit struct {
+	mu       sync.Mutex
+	max      int64
+	written  int64
+	exceeded func()
+}
+
+// writer returns a writer which writes to w within the limit. There is no
+// limit if max is zero.
+func (o *outputLimit) writer(w io.Writer) io.Writer {
+	if w == nil {
+		w = io.Discard
+	}
+
+	if o.max <= 0 {
+		return w
+	}
+
+	return limitedWriter{limit: o, w: w}
+}
+
+type limitedWriter struct {
+	limit *outputLimit
+	w     io.Writer
+}
+
+func (lw limitedWriter) Write(p []byte) (int, error) {
+	o := lw.limit
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var err error
+	if n := min(int64(len(p)), o.max-o.written); n > 0 {
+		_, err = lw.w.Write(p[:n])
+	}
+
+	o.written += int64(len(p))
+	if o.written > o.max {
+		o.exceeded()
+	}
+
+	return len(p), err
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package runner
+
+import "os/exec"
+
+// killGroup is a no-op on platforms without process groups, where cancelling
+// only kills the go command
+func killGroup(cmd *exec.Cmd) {}
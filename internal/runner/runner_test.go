@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+)
+
+func TestOutputLimit(t *testing.T) {
+	var (
+		stdout, stderr bytes.Buffer
+		exceeded       int
+	)
+
+	limit := &outputLimit{max: 10, exceeded: func() { exceeded++ }}
+	w1, w2 := limit.writer(&stdout), limit.writer(&stderr)
+
+	w1.Write([]byte("12345"))
+	w2.Write([]byte("67890"))
+	if exceeded != 0 {
+		t.Fatal("expected output within the limit to be allowed")
+	}
+
+	n, err := w1.Write([]byte("abc"))
+	if n != 3 || err != nil {
+		t.Fatalf("expected writes past the limit to succeed, got %d, %v", n, err)
+	}
+
+	if exceeded == 0 {
+		t.Fatal("expected the limit to be exceeded")
+	}
+
+	if stdout.String() != "12345" || stderr.String() != "67890" {
+		t.Fatalf("expected output past the limit to be dropped, got %q and %q", stdout.String(), stderr.String())
+	}
+}
+
+func TestRunLimits(t *testing.T) {
+	_, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("the go command is required to build the program")
+	}
+
+	if testing.Short() {
+		t.Skip("building the program is slow")
+	}
+
+	l := lesson.Lesson{ID: "forever", Dir: "forever"}
+
+	tests := []struct {
+		name string
+		opts Options
+		want error
+	}{
+		{name: "output", opts: Options{MaxOutput: 1000}, want: ErrOutputLimit},
+		{name: "timeout", opts: Options{Args: []string{"-quiet"}, Timeout: 100 * time.Millisecond}, want: ErrTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			tt.opts.Stdout = &out
+
+			err := Run(context.Background(), "testdata", l, tt.opts)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+
+			if int64(out.Len()) > tt.opts.MaxOutput {
+				t.Fatalf("expected at most %d bytes of output, got %d", tt.opts.MaxOutput, out.Len())
+			}
+		})
+	}
+}
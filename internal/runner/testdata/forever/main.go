@@ -0,0 +1,22 @@
+// forever never exits, like an infinite generator whose consumer never stops
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+func main() {
+	quiet := flag.Bool("quiet", false, "Sleep instead of printing")
+	flag.Parse()
+
+	for i := 0; ; i++ {
+		if *quiet {
+			time.Sleep(time.Second)
+			continue
+		}
+
+		fmt.Printf("number: %d\n", i)
+	}
+}
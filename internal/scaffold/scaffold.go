@@ -0,0 +1,115 @@
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/manedurphy/golang-university/internal/manifest"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// Kinds are the kinds of lessons which can be generated
+var Kinds = []string{"iterator", "channel", "db"}
+
+// lessonName matches the name of a lesson directory, which starts with a
+// number that orders it among its siblings
+var lessonName = regexp.MustCompile(`^[0-9]{2}-[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ErrExists is returned when the lesson directory already exists
+var ErrExists = errors.New("lesson already exists")
+
+// Generate creates the skeleton of a lesson of the given kind in root/id,
+// and returns the paths of the files it created, relative to root. The
+// skeleton consists of a main package, a test which checks that it does not
+// leak goroutines, a manifest, and an expected output which matches what the
+// skeleton prints.
+func Generate(root, id, kind string) ([]string, error) {
+	var created []string
+
+	if !slices.Contains(Kinds, kind) {
+		return nil, fmt.Errorf("unknown kind %q, expected one of %s", kind, strings.Join(Kinds, ", "))
+	}
+
+	id = strings.Trim(filepath.ToSlash(id), "/")
+	if !strings.Contains(id, "/") {
+		return nil, fmt.Errorf("lesson %q must be inside a module, such as iterators/06-%s", id, kind)
+	}
+
+	name := path.Base(id)
+	if !lessonName.MatchString(name) {
+		return nil, fmt.Errorf("lesson name %q must look like 01-name-of-the-lesson", name)
+	}
+
+	dir := filepath.Join(root, filepath.FromSlash(id))
+	_, err := os.Stat(dir)
+	if err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrExists, id)
+	}
+
+	data := map[string]string{
+		"ID":    id,
+		"Title": manifest.TitleFromDir(name),
+	}
+
+	err = os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lesson directory: %w", err)
+	}
+
+	// Files of the kind take precedence over the common files
+	for _, src := range []string{"templates/common", "templates/" + kind} {
+		entries, err := fs.ReadDir(templates, src)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			file := strings.TrimSuffix(entry.Name(), ".tmpl")
+
+			err = render(path.Join(src, entry.Name()), filepath.Join(dir, file), data)
+			if err != nil {
+				return nil, err
+			}
+
+			created = append(created, path.Join(id, file))
+		}
+	}
+
+	slices.Sort(created)
+	return slices.Compact(created), nil
+}
+
+// render executes a template into dst. The templates use [[ and ]] as
+// delimiters, since expected output files use {{ and }} for placeholders.
+func render(src, dst string, data any) error {
+	var buf bytes.Buffer
+
+	tmpl, err := template.New(path.Base(src)).Delims("[[", "]]").ParseFS(templates, src)
+	if err != nil {
+		return fmt.Errorf("failed to parse template %s: %w", src, err)
+	}
+
+	err = tmpl.Execute(&buf, data)
+	if err != nil {
+		return fmt.Errorf("failed to execute template %s: %w", src, err)
+	}
+
+	err = os.WriteFile(dst, buf.Bytes(), 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+	"github.com/manedurphy/golang-university/internal/manifest"
+)
+
+func TestGenerate(t *testing.T) {
+	_, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("the go command is required to build the lessons")
+	}
+
+	if testing.Short() {
+		t.Skip("building the lessons is slow")
+	}
+
+	root, err := lesson.FindRoot(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, kind := range Kinds {
+		t.Run(kind, func(t *testing.T) {
+			dir := t.TempDir()
+			id := "scaffoldtest/01-" + kind + "-lesson"
+
+			created, err := Generate(dir, id, kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := []string{
+				id + "/expected_output.txt",
+				id + "/lesson.yaml",
+				id + "/main.go",
+				id + "/main_test.go",
+			}
+			if !slices.Equal(created, expected) {
+				t.Fatalf("expected %v, got %v", expected, created)
+			}
+
+			m, err := manifest.Load(filepath.Join(dir, id))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if want := manifest.TitleFromDir(filepath.Base(id)); m.Title != want {
+				t.Fatalf("expected the title %q, got %q", want, m.Title)
+			}
+
+			// The lesson imports the internal packages of the module, so it
+			// is built as if it were in the module, through an overlay
+			replace := make(map[string]string)
+			for _, file := range created {
+				if strings.HasSuffix(file, ".go") {
+					replace[filepath.Join(root, file)] = filepath.Join(dir, file)
+				}
+			}
+
+			overlay, err := json.Marshal(map[string]any{"Replace": replace})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			overlayPath := filepath.Join(t.TempDir(), "overlay.json")
+			err = os.WriteFile(overlayPath, overlay, 0o644)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The test binary is built from the lesson along with its test
+			// and without vet, which cannot run in a directory of an overlay
+			bin := filepath.Join(t.TempDir(), "lesson.test")
+			cmd := exec.Command("go", "test", "-c", "-vet=off", "-o", bin, "-overlay", overlayPath, "./"+id)
+			cmd.Dir = root
+
+			out, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("expected the lesson to build, got %v\n%s", err, out)
+			}
+		})
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := Generate(dir, "iterators/01-lesson", "queue")
+	if err == nil || !strings.Contains(err.Error(), "unknown kind") {
+		t.Fatalf("expected an unknown kind, got %v", err)
+	}
+
+	_, err = Generate(dir, "01-lesson", "iterator")
+	if err == nil || !strings.Contains(err.Error(), "inside a module") {
+		t.Fatalf("expected the lesson to need a module, got %v", err)
+	}
+
+	_, err = Generate(dir, "iterators/Lesson", "iterator")
+	if err == nil || !strings.Contains(err.Error(), "must look like") {
+		t.Fatalf("expected an invalid lesson name, got %v", err)
+	}
+
+	_, err = Generate(dir, "iterators/01-lesson", "iterator")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Generate(dir, "iterators/01-lesson", "iterator")
+	if !errors.Is(err, ErrExists) {
+		t.Fatalf("expected %v, got %v", ErrExists, err)
+	}
+}
@@ -0,0 +1,221 @@
+// Package seqtest checks that iterators follow the rules every iter.Seq and
+// iter.Seq2 has to follow: they yield the expected values, they return as
+// soon as yield returns false and never call it again, and they release
+// whatever they hold however the loop over them ends.
+//
+// Every helper has a variant for iter.Seq2, with the same name and a 2
+// appended. Values are compared with reflect.DeepEqual.
+package seqtest
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"testing"
+)
+
+// pair is a single key and value yielded by an iter.Seq2
+type pair[K, V any] struct {
+	key K
+	val V
+}
+
+func (p pair[K, V]) String() string {
+	return fmt.Sprintf("(%v, %v)", p.key, p.val)
+}
+
+// pairs adapts seq to an iter.Seq, passing every call to yield through
+// unchanged, including any made after yield has returned false
+func pairs[K, V any](seq iter.Seq2[K, V]) iter.Seq[pair[K, V]] {
+	return func(yield func(pair[K, V]) bool) {
+		seq(func(k K, v V) bool {
+			return yield(pair[K, V]{k, v})
+		})
+	}
+}
+
+func zip[K, V any](keys []K, values []V) []pair[K, V] {
+	zipped := make([]pair[K, V], len(keys))
+	for i := range keys {
+		zipped[i].key = keys[i]
+		if i < len(values) {
+			zipped[i].val = values[i]
+		}
+	}
+
+	return zipped
+}
+
+// AssertYields fails the test unless ranging over seq yields exactly the
+// values in want, in order. seq is ranged over once, so it may be single-use.
+func AssertYields[T any](t testing.TB, seq iter.Seq[T], want []T) {
+	t.Helper()
+
+	var got []T
+	for val := range seq {
+		got = append(got, val)
+
+		// An iterator which never ends would otherwise hang the test
+		if len(got) > len(want) {
+			break
+		}
+	}
+
+	if len(got) != len(want) || len(want) > 0 && !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %d values %v, got %v", len(want), want, got)
+	}
+}
+
+// AssertYields2 is AssertYields for an iter.Seq2, where the i-th value
+// yielded should be keys[i] and values[i]. keys and values must be the same
+// length.
+func AssertYields2[K, V any](t testing.TB, seq iter.Seq2[K, V], keys []K, values []V) {
+	t.Helper()
+
+	if len(keys) != len(values) {
+		t.Fatalf("expected as many keys as values, got %d keys and %d values", len(keys), len(values))
+	}
+
+	AssertYields(t, pairs(seq), zip(keys, values))
+}
+
+// AssertStopsEarly fails the test unless breaking out of a range over seq
+// after each of its first n values stops it, without yielding any more
+// values, and without the runtime panic a range loop raises for an iterator
+// which calls yield again after it returned false. newSeq is called for every
+// loop, so single-use iterators can be tested too.
+func AssertStopsEarly[T any](t testing.TB, n int, newSeq func() iter.Seq[T]) {
+	t.Helper()
+
+	for stop := 1; stop <= n; stop++ {
+		count, err := breakAfter(newSeq(), stop)
+		if err != nil {
+			t.Errorf("breaking at value %d: %v", stop, err)
+			return
+		}
+
+		if count != stop {
+			t.Errorf("breaking at value %d: expected %d values, got %d", stop, stop, count)
+			return
+		}
+	}
+}
+
+// AssertStopsEarly2 is AssertStopsEarly for an iter.Seq2
+func AssertStopsEarly2[K, V any](t testing.TB, n int, newSeq func() iter.Seq2[K, V]) {
+	t.Helper()
+
+	AssertStopsEarly(t, n, func() iter.Seq[pair[K, V]] { return pairs(newSeq()) })
+}
+
+// breakAfter ranges over seq until it has yielded stop values, and returns
+// how many it yielded, along with the panic raised by the loop, if any
+func breakAfter[T any](seq iter.Seq[T], stop int) (count int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	for range seq {
+		count++
+		if count == stop {
+			break
+		}
+	}
+
+	return count, nil
+}
+
+// AssertNoYieldAfterFalse fails the test if seq calls yield again after yield
+// returned false, for each of its first n values. Unlike AssertStopsEarly, it
+// calls seq directly rather than ranging over it, so it reports every extra
+// call rather than the panic the first one raises. newSeq is called for every
+// value.
+func AssertNoYieldAfterFalse[T any](t testing.TB, n int, newSeq func() iter.Seq[T]) {
+	t.Helper()
+
+	for stop := 1; stop <= n; stop++ {
+		var calls, extra int
+
+		newSeq()(func(T) bool {
+			calls++
+			if calls > stop {
+				extra++
+			}
+
+			return calls < stop
+		})
+
+		if calls < stop {
+			t.Errorf("expected at least %d values, got %d", stop, calls)
+			return
+		}
+
+		if extra > 0 {
+			t.Errorf("yield returned false at value %d, and was called %d more times", stop, extra)
+			return
+		}
+	}
+}
+
+// AssertNoYieldAfterFalse2 is AssertNoYieldAfterFalse for an iter.Seq2
+func AssertNoYieldAfterFalse2[K, V any](t testing.TB, n int, newSeq func() iter.Seq2[K, V]) {
+	t.Helper()
+
+	AssertNoYieldAfterFalse(t, n, func() iter.Seq[pair[K, V]] { return pairs(newSeq()) })
+}
+
+// AssertCleanupRuns fails the test unless the iterator returned by newSeq
+// releases what it holds, such as a file or a goroutine, however the loop over
+// it ends: when it is ranged over to its end, when the loop breaks after each
+// of its first n values, and when the loop body panics. newSeq returns a new
+// iterator along with a function which reports whether its cleanup has run.
+func AssertCleanupRuns[T any](t testing.TB, n int, newSeq func() (iter.Seq[T], func() bool)) {
+	t.Helper()
+
+	seq, cleaned := newSeq()
+	for range seq {
+	}
+	if !cleaned() {
+		t.Errorf("expected cleanup to run once the iterator is exhausted")
+		return
+	}
+
+	for stop := 1; stop <= n; stop++ {
+		seq, cleaned := newSeq()
+
+		_, err := breakAfter(seq, stop)
+		if err != nil {
+			t.Errorf("breaking at value %d: %v", stop, err)
+			return
+		}
+
+		if !cleaned() {
+			t.Errorf("expected cleanup to run after breaking at value %d", stop)
+			return
+		}
+	}
+
+	seq, cleaned = newSeq()
+	func() {
+		defer func() { _ = recover() }()
+
+		for range seq {
+			panic("loop body panicked")
+		}
+	}()
+	if !cleaned() {
+		t.Errorf("expected cleanup to run when the loop body panics")
+	}
+}
+
+// AssertCleanupRuns2 is AssertCleanupRuns for an iter.Seq2
+func AssertCleanupRuns2[K, V any](t testing.TB, n int, newSeq func() (iter.Seq2[K, V], func() bool)) {
+	t.Helper()
+
+	AssertCleanupRuns(t, n, func() (iter.Seq[pair[K, V]], func() bool) {
+		seq, cleaned := newSeq()
+		return pairs(seq), cleaned
+	})
+}
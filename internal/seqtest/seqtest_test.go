@@ -0,0 +1,151 @@
+package seqtest
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// recorder collects the errors a helper reports, so that the tests can check
+// that a broken iterator is caught
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// expectError fails the test unless r recorded a single error containing msg
+func expectError(t *testing.T, r *recorder, msg string) {
+	t.Helper()
+
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], msg) {
+		t.Fatalf("expected an error containing %q, got %q", msg, r.errors)
+	}
+}
+
+// counter yields the numbers from 0 to n-1, and records whether it returned
+type counter struct {
+	n    int
+	done bool
+}
+
+func (c *counter) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		defer func() { c.done = true }()
+
+		for i := range c.n {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+// ignoresFalse yields the numbers from 0 to n-1 whatever yield returns
+func ignoresFalse(n int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := range n {
+			yield(i)
+		}
+	}
+}
+
+// leaksOnBreak only runs its cleanup once it is exhausted
+func leaksOnBreak(n int, done *bool) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := range n {
+			if !yield(i) {
+				return
+			}
+		}
+
+		*done = true
+	}
+}
+
+func TestWellBehaved(t *testing.T) {
+	r := &recorder{TB: t}
+
+	AssertYields(r, (&counter{n: 3}).All(), []int{0, 1, 2})
+	AssertYields(r, (&counter{}).All(), nil)
+	AssertStopsEarly(r, 3, func() iter.Seq[int] { return (&counter{n: 3}).All() })
+	AssertNoYieldAfterFalse(r, 3, func() iter.Seq[int] { return (&counter{n: 3}).All() })
+	AssertCleanupRuns(r, 3, func() (iter.Seq[int], func() bool) {
+		c := &counter{n: 3}
+		return c.All(), func() bool { return c.done }
+	})
+
+	m := map[string]int{"one": 1}
+	AssertYields2(r, maps.All(m), []string{"one"}, []int{1})
+	AssertStopsEarly2(r, 1, func() iter.Seq2[string, int] { return maps.All(m) })
+	AssertNoYieldAfterFalse2(r, 1, func() iter.Seq2[string, int] { return maps.All(m) })
+
+	if len(r.errors) > 0 {
+		t.Fatalf("expected no errors, got %q", r.errors)
+	}
+}
+
+func TestAssertYields(t *testing.T) {
+	r := &recorder{TB: t}
+	AssertYields(r, slices.Values([]int{1, 2, 4}), []int{1, 2, 3})
+	expectError(t, r, "expected 3 values [1 2 3], got [1 2 4]")
+
+	r = &recorder{TB: t}
+	AssertYields(r, (&counter{n: 2}).All(), []int{0, 1, 2})
+	expectError(t, r, "expected 3 values [0 1 2], got [0 1]")
+}
+
+func TestAssertYieldsEndless(t *testing.T) {
+	r := &recorder{TB: t}
+	AssertYields(r, (&counter{n: 1 << 62}).All(), []int{0, 1})
+	expectError(t, r, "got [0 1 2]")
+}
+
+func TestAssertYields2(t *testing.T) {
+	r := &recorder{TB: t}
+	AssertYields2(r, slices.All([]string{"a", "b"}), []int{0, 1}, []string{"a", "c"})
+	expectError(t, r, "got [(0, a) (1, b)]")
+}
+
+func TestAssertStopsEarly(t *testing.T) {
+	r := &recorder{TB: t}
+	AssertStopsEarly(r, 3, func() iter.Seq[int] { return ignoresFalse(3) })
+	expectError(t, r, "breaking at value 1: panic: runtime error: range function continued iteration")
+
+	r = &recorder{TB: t}
+	AssertStopsEarly(r, 3, func() iter.Seq[int] { return (&counter{n: 2}).All() })
+	expectError(t, r, "breaking at value 3: expected 3 values, got 2")
+}
+
+func TestAssertNoYieldAfterFalse(t *testing.T) {
+	r := &recorder{TB: t}
+	AssertNoYieldAfterFalse(r, 3, func() iter.Seq[int] { return ignoresFalse(3) })
+	expectError(t, r, "yield returned false at value 1, and was called 2 more times")
+}
+
+func TestAssertNoYieldAfterFalse2(t *testing.T) {
+	r := &recorder{TB: t}
+	AssertNoYieldAfterFalse2(r, 2, func() iter.Seq2[int, int] {
+		return func(yield func(int, int) bool) {
+			for i := range 2 {
+				yield(i, i)
+			}
+		}
+	})
+	expectError(t, r, "yield returned false at value 1, and was called 1 more times")
+}
+
+func TestAssertCleanupRuns(t *testing.T) {
+	r := &recorder{TB: t}
+	AssertCleanupRuns(r, 2, func() (iter.Seq[int], func() bool) {
+		var done bool
+		return leaksOnBreak(2, &done), func() bool { return done }
+	})
+	expectError(t, r, "expected cleanup to run after breaking at value 1")
+}
@@ -0,0 +1,34 @@
+package testutil
+
+import (
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+// VerifyNoLeaks fails the test if any goroutine started after VerifyNoLeaks
+// was called is still running once the test and its cleanups have finished.
+// It should be called at the start of a test.
+func VerifyNoLeaks(t testing.TB) {
+	t.Helper()
+
+	ignore := goleak.IgnoreCurrent()
+
+	t.Cleanup(func() {
+		err := goleak.Find(ignore)
+		if err != nil {
+			t.Errorf("leaked goroutines: %v", err)
+		}
+	})
+}
+
+// FindLeaks runs fn and returns an error describing every goroutine which was
+// started by fn and is still running after it returns, or nil if there are
+// none. It is useful for asserting that a deliberately broken example leaks.
+func FindLeaks(fn func()) error {
+	ignore := goleak.IgnoreCurrent()
+
+	fn()
+
+	return goleak.Find(ignore)
+}
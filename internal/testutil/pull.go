@@ -0,0 +1,69 @@
+package testutil
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// RequireStopped fails the test if an iterator pulled with iter.Pull or
+// iter.Pull2 after RequireStopped was called has not been stopped once the
+// test and its cleanups have finished. It should be called at the start of a
+// test.
+//
+// A pull iterator runs in a goroutine of its own until it is exhausted or
+// stopped, and that goroutine, along with everything the iterator holds, is
+// never garbage collected, so forgetting to call stop leaks both.
+func RequireStopped(t testing.TB) {
+	t.Helper()
+
+	before := pullGoroutines(allStacks())
+
+	t.Cleanup(func() {
+		var leaked []string
+		for id, stack := range pullGoroutines(allStacks()) {
+			if _, ok := before[id]; !ok {
+				leaked = append(leaked, stack)
+			}
+		}
+
+		if len(leaked) > 0 {
+			t.Errorf("pull iterators were not stopped: %d\n\n%s", len(leaked), strings.Join(leaked, "\n\n"))
+		}
+	})
+}
+
+// allStacks returns the stacks of every goroutine, as formatted by
+// runtime.Stack
+func allStacks() []byte {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// pullGoroutines returns the stacks of the goroutines in stacks which were
+// created by iter.Pull or iter.Pull2, by the header of each stack, such as
+// "goroutine 6 [coroutine]:", which starts with the goroutine's ID
+func pullGoroutines(stacks []byte) map[string]string {
+	pulls := make(map[string]string)
+
+	for _, stack := range bytes.Split(stacks, []byte("\n\n")) {
+		s := strings.TrimSpace(string(stack))
+		if !strings.Contains(s, "\ncreated by iter.Pull") {
+			continue
+		}
+
+		header, _, _ := strings.Cut(s, "\n")
+		id, _, _ := strings.Cut(header, " [")
+		pulls[id] = s
+	}
+
+	return pulls
+}
@@ -0,0 +1,82 @@
+package testutil
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPullGoroutines(t *testing.T) {
+	before := len(pullGoroutines(allStacks()))
+
+	next, stop := iter.Pull(slices.Values([]int{1, 2, 3}))
+	next()
+
+	_, stop2 := iter.Pull2(slices.All([]int{1, 2, 3}))
+
+	if n := len(pullGoroutines(allStacks())) - before; n != 2 {
+		t.Fatalf("expected 2 pull goroutines, got %d", n)
+	}
+
+	stop()
+	stop2()
+
+	if n := len(pullGoroutines(allStacks())) - before; n != 0 {
+		t.Fatalf("expected every pull goroutine to be gone once stopped, got %d", n)
+	}
+}
+
+func TestRequireStopped(t *testing.T) {
+	RequireStopped(t)
+
+	// Pulls which are stopped, or read to their end, pass
+	_, stop := iter.Pull(slices.Values([]int{1, 2, 3}))
+	stop()
+
+	next, _ := iter.Pull(slices.Values([]int{1}))
+	for {
+		if _, ok := next(); !ok {
+			break
+		}
+	}
+}
+
+// recorder records the errors of a test instead of failing it, and runs its
+// cleanups when asked to
+type recorder struct {
+	testing.TB
+	cleanups []func()
+	errors   []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Cleanup(fn func()) {
+	r.cleanups = append(r.cleanups, fn)
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRequireStoppedLeak(t *testing.T) {
+	r := &recorder{TB: t}
+	RequireStopped(r)
+
+	next, stop := iter.Pull(slices.Values([]int{1, 2, 3}))
+	defer stop()
+	next()
+
+	for _, fn := range r.cleanups {
+		fn()
+	}
+
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "pull iterators were not stopped: 1") {
+		t.Fatalf("expected the pull to be reported, got %q", r.errors)
+	}
+	if !strings.Contains(r.errors[0], "created by iter.Pull") {
+		t.Fatalf("expected the stack of the pull, got %q", r.errors[0])
+	}
+}
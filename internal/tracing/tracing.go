@@ -0,0 +1,171 @@
+package tracing
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// BatchSize is the number of values after which Traced adds an event to its
+// span. An event per value would make the span of a long iteration larger
+// than most collectors accept.
+const BatchSize = 1000
+
+// instrumentation names the tracer the spans of this package are created
+// with
+const instrumentation = "github.com/manedurphy/golang-university/internal/tracing"
+
+var endpoint string
+
+// The flag is registered on the default flag set, like the flags of the
+// metrics package, so every lesson which imports this package accepts it
+func init() {
+	flag.StringVar(&endpoint, "otlp-endpoint", "", "Export traces over OTLP/HTTP to the collector at the address, such as localhost:4318")
+}
+
+// Traced returns an iterator which yields the values of seq inside a span
+// named spanName. The span is started as a child of the span in ctx when a
+// loop over the iterator starts, and ended when the loop ends, however it
+// ends. Every BatchSize values, an event records how many values the batch
+// held and how long it took, including the time spent in the loop body. The
+// attributes of the span record the number of values, and whether the loop
+// broke before seq was exhausted.
+func Traced[T any](ctx context.Context, seq iter.Seq[T], spanName string) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s := start(ctx, spanName)
+		defer s.end()
+
+		for v := range seq {
+			s.value()
+
+			if !yield(v) {
+				s.stopped = true
+				return
+			}
+		}
+	}
+}
+
+// Traced2 is like Traced for an iterator over pairs. A pair whose second
+// value is a non-nil error is recorded as an error on the span, and the span
+// ends with an error status if the last pair held one, so it fits the
+// iter.Seq2[T, error] iterators of the lessons.
+func Traced2[K, V any](ctx context.Context, seq iter.Seq2[K, V], spanName string) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		s := start(ctx, spanName)
+		defer s.end()
+
+		for k, v := range seq {
+			s.value()
+
+			err, _ := any(v).(error)
+			if err != nil {
+				s.span.RecordError(err)
+			}
+			s.err = err
+
+			if !yield(k, v) {
+				s.stopped = true
+				return
+			}
+		}
+	}
+}
+
+// span is the span of a single loop over a traced iterator
+type span struct {
+	span       trace.Span
+	items      int
+	batchStart time.Time
+	stopped    bool
+	err        error
+}
+
+func start(ctx context.Context, name string) *span {
+	_, s := otel.Tracer(instrumentation).Start(ctx, name)
+
+	return &span{span: s, batchStart: time.Now()}
+}
+
+// value counts a value, and adds an event once it completes a batch
+func (s *span) value() {
+	s.items++
+	if s.items%BatchSize != 0 {
+		return
+	}
+
+	s.span.AddEvent("batch", trace.WithAttributes(
+		attribute.Int("iterator.batch.items", BatchSize),
+		attribute.Int("iterator.items", s.items),
+		attribute.Int64("iterator.batch.duration_us", time.Since(s.batchStart).Microseconds()),
+	))
+	s.batchStart = time.Now()
+}
+
+func (s *span) end() {
+	s.span.SetAttributes(
+		attribute.Int("iterator.items", s.items),
+		attribute.Bool("iterator.stopped_early", s.stopped),
+	)
+
+	if s.err != nil {
+		s.span.SetStatus(codes.Error, s.err.Error())
+	}
+
+	s.span.End()
+}
+
+// Start installs a tracer provider which the spans of Traced and Traced2 are
+// recorded with, configured with opts, such as a span processor which keeps
+// the spans in memory. Given -otlp-endpoint, the spans are also exported to
+// the collector at that address. It parses the command line if the lesson
+// has not done so yet. Lessons defer the function it returns, which exports
+// the spans which have not been exported yet.
+//
+//	defer tracing.Start()()
+func Start(opts ...sdktrace.TracerProviderOption) func() {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+
+	res := resource.NewSchemaless(semconv.ServiceName(filepath.Base(os.Args[0])))
+	opts = append([]sdktrace.TracerProviderOption{sdktrace.WithResource(res)}, opts...)
+
+	if endpoint != "" {
+		exporter, err := otlptracehttp.New(context.Background(),
+			otlptracehttp.WithEndpoint(endpoint),
+			otlptracehttp.WithInsecure(),
+		)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to export traces: %v\n", err)
+		} else {
+			opts = append(opts, sdktrace.WithBatcher(exporter))
+		}
+	}
+
+	provider := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(provider)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := provider.Shutdown(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to export traces: %v\n", err)
+		}
+	}
+}
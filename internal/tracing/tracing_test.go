@@ -0,0 +1,135 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// record installs a tracer provider which keeps every span in memory
+func record(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	t.Cleanup(Start(sdktrace.WithSpanProcessor(recorder)))
+
+	return recorder
+}
+
+// attr returns the value of the attribute of span with the given key
+func attr(span sdktrace.ReadOnlySpan, key string) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value
+		}
+	}
+
+	return attribute.Value{}
+}
+
+func naturals(n int) func(yield func(int) bool) {
+	return func(yield func(int) bool) {
+		for i := range n {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestTraced(t *testing.T) {
+	recorder := record(t)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+
+	count := 0
+	for range Traced(ctx, naturals(10000), "numbers") {
+		count++
+		if count == 2500 {
+			break
+		}
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name() != "numbers" || span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatalf("expected a numbers span inside the parent, got %q", span.Name())
+	}
+
+	if items := attr(span, "iterator.items").AsInt64(); items != 2500 {
+		t.Fatalf("expected 2500 items, got %d", items)
+	}
+
+	if !attr(span, "iterator.stopped_early").AsBool() {
+		t.Fatal("expected the span to record the break")
+	}
+
+	// One event for each full batch
+	if len(span.Events()) != 2 || span.Events()[1].Name != "batch" {
+		t.Fatalf("expected 2 batch events, got %v", span.Events())
+	}
+}
+
+func TestTraced2(t *testing.T) {
+	recorder := record(t)
+
+	errBroken := errors.New("broken record")
+	seq := func(yield func(int, error) bool) {
+		_ = yield(1, nil) && yield(0, errBroken)
+	}
+
+	for range Traced2(context.Background(), seq, "records") {
+	}
+
+	span := recorder.Ended()[0]
+	if span.Status().Code != codes.Error || span.Status().Description != "broken record" {
+		t.Fatalf("expected an error status, got %v", span.Status())
+	}
+
+	if attr(span, "iterator.stopped_early").AsBool() {
+		t.Fatal("expected the span to record that seq was exhausted")
+	}
+
+	recorded := slices.ContainsFunc(span.Events(), func(e sdktrace.Event) bool { return e.Name == "exception" })
+	if !recorded {
+		t.Fatalf("expected the error to be recorded, got %v", span.Events())
+	}
+}
+
+func TestStartExport(t *testing.T) {
+	var requests atomic.Int32
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/v1/traces" {
+			requests.Add(1)
+		}
+	}))
+	defer collector.Close()
+
+	endpoint = strings.TrimPrefix(collector.URL, "http://")
+	defer func() { endpoint = "" }()
+
+	shutdown := Start()
+	for range Traced(context.Background(), naturals(10), "numbers") {
+	}
+	shutdown()
+
+	if requests.Load() == 0 {
+		t.Fatal("expected the spans to be exported to the collector")
+	}
+}
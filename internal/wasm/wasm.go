@@ -0,0 +1,165 @@
+// Package wasm builds the lessons which only compute for js/wasm, so that a
+// web page can run them in the browser instead of in a process on a server.
+// The lessons are not changed: the go command builds them for GOOS=js and
+// GOARCH=wasm, and the wasm_exec.js of its toolchain runs them.
+package wasm
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+)
+
+// Entry describes a lesson built by Export, in the lessons.json the loader
+// page reads
+type Entry struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	// Path is the path of the lesson's module, relative to lessons.json
+	Path string `json:"path"`
+}
+
+// The files Export writes next to the modules of the lessons
+const (
+	IndexFile   = "index.html"
+	ExecFile    = "wasm_exec.js"
+	LessonsFile = "lessons.json"
+)
+
+// ErrNotBrowser is returned for a lesson whose manifest does not mark it as
+// one which runs in the browser
+var ErrNotBrowser = errors.New("lesson does not run in the browser")
+
+//go:embed loader.html
+var loader embed.FS
+
+// Env returns environ with the variables which make the go command build
+// for js/wasm. The directory of the toolchain's go_js_wasm_exec is added to
+// the PATH as well, which makes go run and go test run the module with
+// Node.js, if it is installed.
+func Env(ctx context.Context, environ []string) ([]string, error) {
+	dir, err := execDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	path := dir
+	for _, kv := range environ {
+		if v, ok := strings.CutPrefix(kv, "PATH="); ok {
+			path = v + string(os.PathListSeparator) + dir
+		}
+	}
+
+	return append(environ, "GOOS=js", "GOARCH=wasm", "PATH="+path), nil
+}
+
+// execDir returns the directory of the toolchain which holds wasm_exec.js,
+// which moved from misc/wasm to lib/wasm in Go 1.24
+func execDir(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, "go", "env", "GOROOT").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find GOROOT: %w", err)
+	}
+	goroot := strings.TrimSpace(string(out))
+
+	for _, dir := range []string{"lib/wasm", "misc/wasm"} {
+		dir = filepath.Join(goroot, filepath.FromSlash(dir))
+
+		_, err := os.Stat(filepath.Join(dir, ExecFile))
+		if err == nil {
+			return dir, nil
+		}
+	}
+
+	return "", fmt.Errorf("failed to find %s in %s", ExecFile, goroot)
+}
+
+// Build builds the lesson into a WebAssembly module at out
+func Build(ctx context.Context, root string, l lesson.Lesson, out string) error {
+	if !l.Manifest.Browser {
+		return fmt.Errorf("%w: %s", ErrNotBrowser, l.ID)
+	}
+
+	env, err := Env(ctx, os.Environ())
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "build", "-o", out, "./"+filepath.ToSlash(l.Dir))
+	cmd.Dir = root
+	cmd.Env = env
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to build %s: %w\n%s", l.ID, err, output)
+	}
+
+	return nil
+}
+
+// Export builds the lessons into dir, along with everything a static web
+// server needs to run them in the browser: the loader page, the
+// wasm_exec.js of the toolchain, and lessons.json, which lists the lessons
+// for the loader page. The module of each lesson is written to
+// lessons/<id>.wasm.
+func Export(ctx context.Context, root string, lessons []lesson.Lesson, dir string) ([]Entry, error) {
+	entries := make([]Entry, 0, len(lessons))
+
+	for _, l := range lessons {
+		entry := Entry{ID: l.ID, Title: l.Manifest.Title, Path: "lessons/" + l.ID + ".wasm"}
+
+		out := filepath.Join(dir, filepath.FromSlash(entry.Path))
+		err := os.MkdirAll(filepath.Dir(out), 0o755)
+		if err != nil {
+			return nil, err
+		}
+
+		err = Build(ctx, root, l, out)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	execDir, err := execDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	execJS, err := os.ReadFile(filepath.Join(execDir, ExecFile))
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := loader.ReadFile("loader.html")
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	for name, data := range map[string][]byte{
+		ExecFile:    execJS,
+		IndexFile:   index,
+		LessonsFile: append(manifest, '\n'),
+	} {
+		err = os.WriteFile(filepath.Join(dir, name), data, 0o644)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,105 @@
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+	"github.com/manedurphy/golang-university/internal/manifest"
+)
+
+func requireGo(t *testing.T) {
+	t.Helper()
+
+	_, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("the go command is required to build for js/wasm")
+	}
+}
+
+func TestEnv(t *testing.T) {
+	requireGo(t)
+
+	env, err := Env(context.Background(), []string{"HOME=/home/ada", "PATH=/usr/bin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{"HOME=/home/ada", "GOOS=js", "GOARCH=wasm"} {
+		if !slices.Contains(env, expected) {
+			t.Errorf("expected %s in %v", expected, env)
+		}
+	}
+
+	// The last PATH wins, and keeps the directories of the original one
+	path := env[len(env)-1]
+	if !strings.HasPrefix(path, "PATH=/usr/bin"+string(os.PathListSeparator)) {
+		t.Fatalf("expected the directory of wasm_exec.js to be added to the PATH, got %s", path)
+	}
+
+	dir := strings.TrimPrefix(path, "PATH=/usr/bin"+string(os.PathListSeparator))
+	if _, err := os.Stat(filepath.Join(dir, ExecFile)); err != nil {
+		t.Fatalf("expected %s in %s: %v", ExecFile, dir, err)
+	}
+}
+
+func TestBuildNotBrowser(t *testing.T) {
+	l := lesson.Lesson{ID: "hello", Dir: "hello", Manifest: &manifest.Manifest{}}
+
+	err := Build(context.Background(), "testdata", l, filepath.Join(t.TempDir(), "hello.wasm"))
+	if !errors.Is(err, ErrNotBrowser) {
+		t.Fatalf("expected ErrNotBrowser, got %v", err)
+	}
+}
+
+func TestExport(t *testing.T) {
+	requireGo(t)
+
+	if testing.Short() {
+		t.Skip("building for js/wasm is slow")
+	}
+
+	dir := t.TempDir()
+	l := lesson.Lesson{ID: "greetings/hello", Dir: "hello", Manifest: &manifest.Manifest{Title: "Hello", Browser: true}}
+
+	entries, err := Export(context.Background(), "testdata", []lesson.Lesson{l}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Entry{{ID: "greetings/hello", Title: "Hello", Path: "lessons/greetings/hello.wasm"}}
+	if !slices.Equal(entries, expected) {
+		t.Fatalf("expected %v, got %v", expected, entries)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, LessonsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var listed []Entry
+	err = json.Unmarshal(data, &listed)
+	if err != nil || !slices.Equal(listed, expected) {
+		t.Fatalf("expected %s to list %v, got %s (%v)", LessonsFile, expected, data, err)
+	}
+
+	// Every WebAssembly module starts with the same magic number
+	module, err := os.ReadFile(filepath.Join(dir, "lessons", "greetings", "hello.wasm"))
+	if err != nil || !bytes.HasPrefix(module, []byte("\x00asm")) {
+		t.Fatalf("expected a WebAssembly module, got %d bytes (%v)", len(module), err)
+	}
+
+	for _, name := range []string{IndexFile, ExecFile} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s to be written: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package web
+
+import (
+	"go/scanner"
+	"go/token"
+	"html"
+	"html/template"
+	"strings"
+)
+
+// highlight renders Go source as HTML, wrapping each token which should be
+// colored in a span whose class names its kind
+func highlight(src []byte) template.HTML {
+	var (
+		b    strings.Builder
+		s    scanner.Scanner
+		last int
+	)
+
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	s.Init(file, src, nil, scanner.ScanComments)
+
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+
+		class := tokenClass(tok)
+		if class == "" {
+			continue
+		}
+
+		offset := file.Offset(pos)
+		end := offset + len(lit)
+		if lit == "" {
+			end = offset + len(tok.String())
+		}
+
+		b.WriteString(html.EscapeString(string(src[last:offset])))
+		b.WriteString(`<span class="` + class + `">`)
+		b.WriteString(html.EscapeString(string(src[offset:end])))
+		b.WriteString(`</span>`)
+		last = end
+	}
+
+	b.WriteString(html.EscapeString(string(src[last:])))
+
+	return template.HTML(b.String())
+}
+
+// tokenClass returns the CSS class of a token, or an empty string if the
+// token is not colored
+func tokenClass(tok token.Token) string {
+	switch {
+	case tok == token.COMMENT:
+		return "comment"
+	case tok == token.STRING || tok == token.CHAR:
+		return "string"
+	case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+		return "number"
+	case tok.IsKeyword():
+		return "keyword"
+	}
+
+	return ""
+}
@@ -0,0 +1,8 @@
+// fail exits with a non-zero status
+package main
+
+import "os"
+
+func main() {
+	os.Exit(3)
+}
@@ -0,0 +1,10 @@
+// forever never exits, like an infinite generator whose consumer never stops
+package main
+
+import "time"
+
+func main() {
+	for {
+		time.Sleep(time.Second)
+	}
+}
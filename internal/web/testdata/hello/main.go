@@ -0,0 +1,8 @@
+// hello prints two lines, the last without a newline
+package main
+
+import "fmt"
+
+func main() {
+	fmt.Print("hello\r\nworld")
+}
@@ -0,0 +1,281 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+	"github.com/manedurphy/golang-university/internal/runner"
+)
+
+type (
+	// Server serves the lessons of a registry over HTTP
+	Server struct {
+		reg       *lesson.Registry
+		logger    *slog.Logger
+		templates *template.Template
+		timeout   time.Duration
+		// runs limits the number of lessons running at the same time
+		runs chan struct{}
+	}
+
+	// sourceFile is a Go file of a lesson, rendered as HTML
+	sourceFile struct {
+		Name string
+		HTML template.HTML
+	}
+
+	// module groups the lessons of a module on the index page
+	module struct {
+		Name    string
+		Lessons []lesson.Lesson
+	}
+
+	// eventWriter sends everything written to it to the browser as
+	// server-sent events, one event per line
+	eventWriter struct {
+		mu      sync.Mutex
+		w       http.ResponseWriter
+		flusher http.Flusher
+		buf     []byte
+	}
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// NewServer returns a server which runs lessons for at most timeout, with at
+// most maxRuns lessons running at the same time
+func NewServer(reg *lesson.Registry, logger *slog.Logger, timeout time.Duration, maxRuns int) (*Server, error) {
+	templates, err := template.ParseFS(templateFS, "templates/*.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse templates: %w", err)
+	}
+
+	return &Server{
+		reg:       reg,
+		logger:    logger,
+		templates: templates,
+		timeout:   timeout,
+		runs:      make(chan struct{}, maxRuns),
+	}, nil
+}
+
+// Handler returns the server's routes
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /lessons/{id...}", s.handleLesson)
+	mux.HandleFunc("GET /run/{id...}", s.handleRun)
+
+	return mux
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	var modules []module
+
+	for l := range s.reg.Ordered() {
+		if len(modules) == 0 || modules[len(modules)-1].Name != l.Module {
+			modules = append(modules, module{Name: l.Module})
+		}
+
+		m := &modules[len(modules)-1]
+		m.Lessons = append(m.Lessons, l)
+	}
+
+	s.render(w, "index.html", modules)
+}
+
+func (s *Server) handleLesson(w http.ResponseWriter, r *http.Request) {
+	l, err := s.reg.Lookup(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	files, err := s.sourceFiles(l)
+	if err != nil {
+		s.logger.Error("failed to read source", "lesson", l.ID, "err", err)
+		http.Error(w, "failed to read source", http.StatusInternalServerError)
+		return
+	}
+
+	s.render(w, "lesson.html", map[string]any{
+		"Lesson": l,
+		"Files":  files,
+	})
+}
+
+// handleRun runs a lesson and streams its output as server-sent events. Each
+// line of output is sent as a message, and an exit event carrying the
+// outcome is sent once the lesson has finished.
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	l, err := s.reg.Lookup(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	select {
+	case s.runs <- struct{}{}:
+		defer func() { <-s.runs }()
+	default:
+		http.Error(w, "too many lessons are running, try again later", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// The lesson is killed once the timeout expires, or as soon as the
+	// browser goes away
+	ctx, cancel := context.WithTimeout(r.Context(), s.timeout)
+	defer cancel()
+
+	out := &eventWriter{w: w, flusher: flusher}
+
+	now := time.Now()
+	err = runner.Run(ctx, s.reg.Root(), l, runner.Options{
+		Stdout: out,
+		Stderr: out,
+		Detach: true,
+		Env:    sandboxEnv(),
+	})
+	out.flush()
+
+	outcome := fmt.Sprintf("exited successfully after %s", time.Since(now).Round(time.Millisecond))
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		outcome = fmt.Sprintf("killed after %s", s.timeout)
+	case errors.As(err, &exitErr):
+		outcome = fmt.Sprintf("exited with status %d after %s", exitErr.ExitCode(), time.Since(now).Round(time.Millisecond))
+	case err != nil:
+		outcome = err.Error()
+	}
+
+	s.logger.Info("ran lesson", "lesson", l.ID, "outcome", outcome)
+	out.event("exit", outcome)
+}
+
+// sourceFiles reads and highlights the lesson's Go files, excluding tests. A
+// lesson of a pack has none, and its page only shows its manifest.
+func (s *Server) sourceFiles(l lesson.Lesson) ([]sourceFile, error) {
+	src, err := s.reg.Source(l)
+	if errors.Is(err, lesson.ErrNoSource) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]sourceFile, 0, len(src))
+	for _, f := range src {
+		files = append(files, sourceFile{Name: f.Name, HTML: highlight(f.Content)})
+	}
+
+	return files, nil
+}
+
+// render executes a template into a buffer first, so that a failing template
+// does not send half a page
+func (s *Server) render(w http.ResponseWriter, name string, data any) {
+	var buf bytes.Buffer
+
+	err := s.templates.ExecuteTemplate(&buf, name, data)
+	if err != nil {
+		s.logger.Error("failed to render template", "template", name, "err", err)
+		http.Error(w, "failed to render page", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	buf.WriteTo(w)
+}
+
+// sandboxEnv returns the environment a lesson is run with from the web UI.
+// Only what the go command needs is passed through, so the lesson cannot see
+// any secrets in the server's environment.
+func sandboxEnv() []string {
+	var env []string
+
+	for _, key := range []string{"PATH", "HOME", "GOPATH", "GOCACHE", "GOMODCACHE", "GOROOT", "GOPROXY", "GOFLAGS", "TMPDIR"} {
+		val, ok := os.LookupEnv(key)
+		if ok {
+			env = append(env, key+"="+val)
+		}
+	}
+
+	return env
+}
+
+// Write buffers p and sends every complete line as a message
+func (e *eventWriter) Write(p []byte) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.buf = append(e.buf, p...)
+	for {
+		i := bytes.IndexByte(e.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		e.send("", string(e.buf[:i]))
+		e.buf = e.buf[i+1:]
+	}
+
+	e.flusher.Flush()
+	return len(p), nil
+}
+
+// flush sends the last line if it did not end with a newline
+func (e *eventWriter) flush() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.buf) > 0 {
+		e.send("", string(e.buf))
+		e.buf = nil
+	}
+
+	e.flusher.Flush()
+}
+
+// event sends a named event
+func (e *eventWriter) event(name, data string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.send(name, data)
+	e.flusher.Flush()
+}
+
+// send writes a single event. A line of data can never contain a newline,
+// so a carriage return is the only thing which needs stripping.
+func (e *eventWriter) send(name, data string) {
+	if name != "" {
+		fmt.Fprintf(e.w, "event: %s\n", name)
+	}
+
+	fmt.Fprintf(e.w, "data: %s\n\n", strings.TrimSuffix(data, "\r"))
+}
@@ -0,0 +1,137 @@
+package web
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+)
+
+// newServer returns a server for the lessons in testdata
+func newServer(t *testing.T, timeout time.Duration, maxRuns int) *Server {
+	t.Helper()
+
+	reg, err := lesson.Discover("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewServer(reg, slog.New(slog.NewTextHandler(io.Discard, nil)), timeout, maxRuns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+// get serves a GET request of path
+func get(s *Server, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	return rec
+}
+
+func TestEventWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	e := &eventWriter{w: rec, flusher: rec}
+
+	e.Write([]byte("first\r\nsec"))
+	e.Write([]byte("ond\nthi"))
+	if got, want := rec.Body.String(), "data: first\n\ndata: second\n\n"; got != want {
+		t.Fatalf("expected only complete lines to be sent as %q, got %q", want, got)
+	}
+
+	e.Write([]byte("rd"))
+	e.flush()
+	e.event("exit", "done")
+
+	want := "data: first\n\ndata: second\n\ndata: third\n\nevent: exit\ndata: done\n\n"
+	if got := rec.Body.String(); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	if !rec.Flushed {
+		t.Fatal("expected the events to be flushed")
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	s := newServer(t, time.Minute, 1)
+
+	for _, path := range []string{"/lessons/missing", "/run/missing"} {
+		rec := get(s, path)
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("expected %s to be %d, got %d", path, http.StatusNotFound, rec.Code)
+		}
+	}
+}
+
+func TestRunBusy(t *testing.T) {
+	s := newServer(t, time.Minute, 1)
+	s.runs <- struct{}{}
+
+	rec := get(s, "/run/hello")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected %d while every run is taken, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+}
+
+func TestRun(t *testing.T) {
+	_, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("the go command is required to run the lessons")
+	}
+
+	if testing.Short() {
+		t.Skip("building the lessons is slow")
+	}
+
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		output  []string
+		exit    string
+	}{
+		{name: "hello", timeout: time.Minute, output: []string{"data: hello\n\n", "data: world\n\n"}, exit: "exited successfully after "},
+		{name: "fail", timeout: time.Minute, exit: "exited with status 1 after "},
+		{name: "forever", timeout: 100 * time.Millisecond, exit: "killed after 100ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newServer(t, tt.timeout, 1)
+
+			rec := get(s, "/run/"+tt.name)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+			}
+
+			if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+				t.Fatalf("expected an event stream, got %q", got)
+			}
+
+			body := rec.Body.String()
+			for _, line := range tt.output {
+				if !strings.Contains(body, line) {
+					t.Fatalf("expected the output to contain %q, got %q", line, body)
+				}
+			}
+
+			_, exit, ok := strings.Cut(body, "event: exit\ndata: ")
+			if !ok || !strings.HasPrefix(exit, tt.exit) {
+				t.Fatalf("expected an exit event starting with %q, got %q", tt.exit, body)
+			}
+
+			if len(s.runs) != 0 {
+				t.Fatal("expected the run to be released")
+			}
+		})
+	}
+}
@@ -27,11 +27,12 @@ func (i *iterator) GetNumbers(ctx context.Context) <-chan int {
 		defer close(ch)
 
 		for _, val := range i.data {
+			// The send is a case of the select, so a cancelled context
+			// wakes up a goroutine which is blocked on it
 			select {
+			case ch <- val:
 			case <-ctx.Done():
 				return
-			default:
-				ch <- val
 			}
 		}
 	}()
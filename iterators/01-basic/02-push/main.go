@@ -16,6 +16,8 @@ func main() {
 	for val := range it.GetNumbers(ctx) {
 		fmt.Printf("value: %d\n", val)
 
+		// Cancelling ctx stops the goroutine sending the values, and the
+		// break stops the loop
 		if val == 45 {
 			cancel()
 			break
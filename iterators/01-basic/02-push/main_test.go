@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/testutil"
+	"github.com/manedurphy/golang-university/iterators/01-basic/02-push/iterator"
+)
+
+// TestCancelStopsProducer breaks out of the loop while the goroutine of
+// GetNumbers is blocked sending the next value, which cancelling the context
+// has to wake up
+func TestCancelStopsProducer(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	for val := range iterator.NewIterator().GetNumbers(ctx) {
+		if val == 45 {
+			break
+		}
+	}
+
+	cancel()
+}
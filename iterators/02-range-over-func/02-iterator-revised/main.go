@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/manedurphy/golang-university/iterators/02-range-over-func/02-iterator-revised/iterator"
+	"github.com/manedurphy/golang-university/iterators/itertools"
 )
 
 func main() {
@@ -14,4 +17,17 @@ func main() {
 	}
 
 	fmt.Println("no more values")
+
+	// The timeout decides when to stop rather than the loop body, which
+	// spends 100ms on every value. The context is checked before each value,
+	// so the loop ends on the first value after the timeout has expired.
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+
+	for val := range itertools.WithContext(ctx, it.GetNumbers()) {
+		fmt.Printf("value: %d\n", val)
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	fmt.Printf("stopped: %v\n", ctx.Err())
 }
@@ -32,6 +32,11 @@ func (ll *LinkedList) Append(value int) {
 	current.next = &newNode
 }
 
+// Value returns the value stored in the node
+func (n *Node) Value() int {
+	return n.value
+}
+
 // Traverse returns an iterator for sequential access to all nodes in the linked list
 func (ll *LinkedList) Traverse() iter.Seq[*Node] {
 	return func(yield func(*Node) bool) {
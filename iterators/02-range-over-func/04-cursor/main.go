@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/manedurphy/golang-university/iterators/02-range-over-func/02-iterator-revised/iterator"
+	"github.com/manedurphy/golang-university/iterators/itertools"
+)
+
+var data = []int{3, 2, 45, 4, 6, 7}
+
+func main() {
+	// The pull style of the first basic lesson: the consumer asks for every
+	// value with Next
+	fmt.Println("pull:")
+	c := itertools.CursorOf(data)
+	for {
+		val, ok := c.Next()
+		if !ok {
+			fmt.Println("no more values")
+			break
+		}
+
+		fmt.Printf("value: %d\n", val)
+	}
+
+	// The push style of the second basic lesson: the cursor yields the
+	// values to the loop body, and the loop breaks at 45
+	fmt.Println("\npush:")
+	c = itertools.CursorOf(data)
+	for val := range c.All() {
+		fmt.Printf("value: %d\n", val)
+
+		if val == 45 {
+			break
+		}
+	}
+	fmt.Println("no more values")
+
+	// Both styles share the same position, so the values after the break are
+	// still there. The cursor below wraps the range-over-func iterator of the
+	// revised lesson, and must be stopped if it is not read to its end.
+	fmt.Println("\nboth:")
+	c = itertools.NewCursor(iterator.NewIterator().GetNumbers())
+	defer c.Stop()
+
+	for val := range c.All() {
+		fmt.Printf("value: %d\n", val)
+
+		if val == 45 {
+			break
+		}
+	}
+
+	next, _ := c.Peek()
+	fmt.Printf("after the break, the next value is %d\n", next)
+
+	for val := range c.All() {
+		fmt.Printf("value: %d\n", val)
+	}
+	fmt.Println("no more values")
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/goldentest"
+	"github.com/manedurphy/golang-university/internal/lessontrace"
+	"github.com/manedurphy/golang-university/internal/manifest"
+)
+
+func TestOutput(t *testing.T) {
+	out := goldentest.Stdout(t, main)
+
+	t.Run("trace=false", func(t *testing.T) {
+		goldentest.Assert(t, out)
+	})
+
+	// The events are named by the trace rules of lesson.yaml, like they are
+	// by university run -trace
+	t.Run("trace=true", func(t *testing.T) {
+		m, err := manifest.Load(".")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		traced, err := lessontrace.Trace(out, m.Trace)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goldentest.Assert(t, traced)
+	})
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/manedurphy/golang-university/internal/metrics"
+)
+
+// stage wraps a stage of the pipeline so that it logs when it observes that
+// its consumer has stopped, and when its cleanup runs
+func stage[T any](name string, log func(string), seq iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		defer log(fmt.Sprintf("%s: cleanup", name))
+
+		for val := range seq {
+			if !yield(val) {
+				log(fmt.Sprintf("%s: consumer stopped", name))
+				return
+			}
+		}
+	}
+}
+
+// numbers is the source of the pipeline
+func numbers(log func(string)) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		defer log("numbers: cleanup")
+
+		for n := 1; ; n++ {
+			log(fmt.Sprintf("numbers: yielding %d", n))
+			if !yield(n) {
+				log("numbers: consumer stopped")
+				return
+			}
+		}
+	}
+}
+
+func filter(seq iter.Seq[int], keep func(int) bool) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for n := range seq {
+			if keep(n) && !yield(n) {
+				return
+			}
+		}
+	}
+}
+
+func square(seq iter.Seq[int]) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for n := range seq {
+			if !yield(n * n) {
+				return
+			}
+		}
+	}
+}
+
+func format(seq iter.Seq[int]) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for n := range seq {
+			if !yield(fmt.Sprintf("<%d>", n)) {
+				return
+			}
+		}
+	}
+}
+
+// pipeline composes four stages. The source is infinite, so the pipeline only
+// ends when the consumer breaks. Every stage is instrumented, so -metrics-addr
+// shows how many values each of them produced.
+func pipeline(log func(string)) iter.Seq[string] {
+	source := metrics.Instrument(numbers(log), "numbers")
+	evens := stage("filter", log, metrics.Instrument(filter(source, func(n int) bool { return n%2 == 0 }), "filter"))
+	squares := stage("square", log, metrics.Instrument(square(evens), "square"))
+
+	return stage("format", log, metrics.Instrument(format(squares), "format"))
+}
+
+// run consumes the pipeline until it has received the specified number of
+// values
+func run(log func(string), count int) {
+	received := 0
+
+	for val := range pipeline(log) {
+		log(fmt.Sprintf("consumer: received %s", val))
+
+		received++
+		if received == count {
+			log("consumer: break")
+			break
+		}
+	}
+
+	log("consumer: loop exited")
+}
+
+func main() {
+	defer metrics.Serve()()
+
+	run(func(s string) { fmt.Println(s) }, 2)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEveryStageObservesTheStop(t *testing.T) {
+	var events []string
+
+	run(func(s string) { events = append(events, s) }, 2)
+
+	// The stop travels from the stage closest to the consumer back to the
+	// source. Each stage is still inside the range-loop over the stage before
+	// it, so the cleanups run in the opposite order, from the source back to
+	// the consumer. All of that happens before the consumer's loop exits.
+	expected := []string{
+		"consumer: break",
+		"format: consumer stopped",
+		"square: consumer stopped",
+		"filter: consumer stopped",
+		"numbers: consumer stopped",
+		"numbers: cleanup",
+		"filter: cleanup",
+		"square: cleanup",
+		"format: cleanup",
+		"consumer: loop exited",
+	}
+
+	idx := slices.Index(events, "consumer: break")
+	if idx < 0 {
+		t.Fatalf("consumer never broke out of the loop: %v", events)
+	}
+
+	if !slices.Equal(events[idx:], expected) {
+		t.Fatalf("expected events after the break to be\n%v\ngot\n%v", expected, events[idx:])
+	}
+}
@@ -3,22 +3,15 @@ package main
 import (
 	"flag"
 	"log/slog"
-	"os"
 	"time"
 
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/internal/lessonlog"
+	"github.com/manedurphy/golang-university/internal/metrics"
+	"github.com/manedurphy/golang-university/internal/report"
 	"github.com/manedurphy/golang-university/iterators/04-database/db"
 )
 
-var (
-	dataDir    string
-	numCourses int
-)
-
-func init() {
-	flag.StringVar(&dataDir, "data-dir", ".", "The directory for storing the DB file")
-	flag.IntVar(&numCourses, "num-courses", 0, "The number of courses to create")
-}
-
 func main() {
 	var (
 		coursesDB db.CoursesDB
@@ -27,32 +20,58 @@ func main() {
 		err       error
 	)
 
-	flag.Parse()
+	// -v, -q, and -json configure the logger
+	logFlags := lessonlog.RegisterFlags(flag.CommandLine)
+
+	// -count is the number of courses, and -seed makes them the same on
+	// every run
+	cfg := lessoncfg.Load(lessoncfg.Config{DataDir: "."})
+	db.Seed(cfg.Seed)
 
-	logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
+	logger = lessonlog.New(logFlags)
+	logger.Debug("loaded config", "seed", cfg.Seed)
+
+	// The run's report is written once main returns, or by report.Exit
+	defer report.Write()
+
+	// With -metrics-addr, the iteration's metrics can be scraped once it
+	// is done
+	defer metrics.Serve()()
+
+	ctx, cancel := cfg.Context()
+	defer cancel()
 
 	// Create new database instance
-	coursesDB, err = db.New(dataDir)
+	coursesDB, err = db.New(cfg.DataDir)
 	if err != nil {
-		logger.Error("failed to create database", "err", err)
-		os.Exit(1)
+		logger.Error("failed to create database", lessonlog.Err(err))
+		report.Exit(1)
 	}
 	defer coursesDB.Close()
 
-	now, err = time.Now(), coursesDB.Seed(numCourses)
+	done := report.Time("seed")
+	now, err = time.Now(), coursesDB.Seed(cfg.Count)
+	done()
 	if err != nil {
-		logger.Error("failed to seed database", "err", err)
-		os.Exit(1)
+		logger.Error("failed to seed database", lessonlog.Err(err))
+		report.Exit(1)
 	}
-	logger.Info("successfully seeded database", "duration_ms", time.Since(now).Milliseconds())
+	logger.Info("successfully seeded database", lessonlog.Duration(time.Since(now)))
 
 	// Get courses from database using iterator
-	for course, err := range coursesDB.GetCourses() {
+	for course, err := range metrics.Instrument2(coursesDB.GetCourses(), "courses") {
+		if ctx.Err() != nil {
+			logger.Warn("stopping iteration", lessonlog.Err(ctx.Err()))
+			break
+		}
+
 		if err != nil {
-			logger.Error("failed to get course", "err", err)
+			logger.Error("failed to get course", lessonlog.Err(err))
+			report.Add("errors", 1)
 			continue
 		}
 
-		logger.Info("received course", "course", course)
+		logger.Info("received course", lessonlog.KeyCourse, course)
+		report.Add("courses", 1)
 	}
 }
@@ -4,22 +4,15 @@ import (
 	"flag"
 	"iter"
 	"log/slog"
-	"os"
 	"time"
 
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/internal/lessonlog"
+	"github.com/manedurphy/golang-university/internal/metrics"
+	"github.com/manedurphy/golang-university/internal/report"
 	"github.com/manedurphy/golang-university/iterators/04-database/db"
 )
 
-var (
-	dataDir    string
-	numCourses int
-)
-
-func init() {
-	flag.StringVar(&dataDir, "data-dir", ".", "The directory for storing the DB file")
-	flag.IntVar(&numCourses, "num-courses", 0, "The number of courses to create")
-}
-
 func main() {
 	var (
 		coursesDB db.CoursesDB
@@ -28,30 +21,49 @@ func main() {
 		err       error
 	)
 
-	flag.Parse()
+	// -v, -q, and -json configure the logger
+	logFlags := lessonlog.RegisterFlags(flag.CommandLine)
+
+	// -count is the number of courses, and -seed makes them the same on
+	// every run
+	cfg := lessoncfg.Load(lessoncfg.Config{DataDir: "."})
+	db.Seed(cfg.Seed)
 
-	logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
+	logger = lessonlog.New(logFlags)
+	logger.Debug("loaded config", "seed", cfg.Seed)
+
+	// The run's report is written once main returns, or by report.Exit
+	defer report.Write()
+
+	// With -metrics-addr, the iteration's metrics can be scraped once it
+	// is done
+	defer metrics.Serve()()
+
+	ctx, cancel := cfg.Context()
+	defer cancel()
 
 	// Create new database instance
-	coursesDB, err = db.New(dataDir)
+	coursesDB, err = db.New(cfg.DataDir)
 	if err != nil {
-		logger.Error("failed to create database", "err", err)
-		os.Exit(1)
+		logger.Error("failed to create database", lessonlog.Err(err))
+		report.Exit(1)
 	}
 	defer coursesDB.Close()
 
-	now, err = time.Now(), coursesDB.Seed(numCourses)
+	done := report.Time("seed")
+	now, err = time.Now(), coursesDB.Seed(cfg.Count)
+	done()
 	if err != nil {
-		logger.Error("failed to seed database", "err", err)
-		os.Exit(1)
+		logger.Error("failed to seed database", lessonlog.Err(err))
+		report.Exit(1)
 	}
-	logger.Info("successfully seeded database", "duration_ms", time.Since(now).Milliseconds())
+	logger.Info("successfully seeded database", lessonlog.Duration(time.Since(now)))
 
-	next, stop := iter.Pull2(coursesDB.GetCourses())
+	next, stop := iter.Pull2(metrics.Instrument2(coursesDB.GetCourses(), "courses"))
 	defer stop()
 
 	// Get courses from database using iterator
-	for {
+	for ctx.Err() == nil {
 		course, err, valid := next()
 		if !valid {
 			logger.Info("iteration has completed")
@@ -59,10 +71,16 @@ func main() {
 		}
 
 		if err != nil {
-			logger.Error("failed to get course", "err", err)
+			logger.Error("failed to get course", lessonlog.Err(err))
+			report.Add("errors", 1)
 			continue
 		}
 
-		logger.Info("received course", "course", course)
+		logger.Info("received course", lessonlog.KeyCourse, course)
+		report.Add("courses", 1)
+	}
+
+	if ctx.Err() != nil {
+		logger.Warn("stopped iteration", lessonlog.Err(ctx.Err()))
 	}
 }
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/internal/lessonlog"
+	"github.com/manedurphy/golang-university/internal/report"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/csvseq"
+)
+
+// brokenCSV has a course whose ID is not a number on its third line
+const brokenCSV = `id,name,university
+1,Chem-1,SJSU
+two,Chem-2,UCB
+3,Physics-1,SDSU
+`
+
+// writeCSV writes the generated courses to a CSV file, with a header naming
+// the column of each field
+func writeCSV(path string, numCourses int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file: %w", err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+
+	err = w.Write([]string{"id", "name", "university"})
+	if err != nil {
+		return fmt.Errorf("failed to write CSV file: %w", err)
+	}
+
+	for course := range db.GenerateCourses(numCourses) {
+		err = w.Write([]string{strconv.Itoa(course.ID), course.Name, course.University})
+		if err != nil {
+			return fmt.Errorf("failed to write CSV file: %w", err)
+		}
+	}
+
+	w.Flush()
+	err = w.Error()
+	if err != nil {
+		return fmt.Errorf("failed to write CSV file: %w", err)
+	}
+
+	return f.Close()
+}
+
+// countCourses returns the number of courses in the database
+func countCourses(coursesDB db.CoursesDB) (int, error) {
+	n := 0
+	for _, err := range coursesDB.GetCourses() {
+		if err != nil {
+			return n, err
+		}
+
+		n++
+	}
+
+	return n, nil
+}
+
+func main() {
+	// -v, -q, and -json configure the logger
+	logFlags := lessonlog.RegisterFlags(flag.CommandLine)
+
+	// -count is the number of courses in the CSV file, and -seed makes them
+	// the same on every run
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 1000, DataDir: "."})
+	db.Seed(cfg.Seed)
+
+	logger := lessonlog.New(logFlags)
+
+	defer report.Write()
+
+	ctx, cancel := cfg.Context()
+	defer cancel()
+
+	coursesDB, err := db.New(cfg.DataDir)
+	if err != nil {
+		logger.Error("failed to create database", lessonlog.Err(err))
+		report.Exit(1)
+	}
+	defer coursesDB.Close()
+
+	path := filepath.Join(cfg.DataDir, "courses.csv")
+	err = writeCSV(path, cfg.Count)
+	if err != nil {
+		logger.Error("failed to write courses", lessonlog.Err(err))
+		report.Exit(1)
+	}
+	defer os.Remove(path)
+
+	f, err := os.Open(path)
+	if err != nil {
+		logger.Error("failed to open courses", lessonlog.Err(err))
+		report.Exit(1)
+	}
+	defer f.Close()
+
+	// The courses are decoded as they are inserted, so only one record of
+	// the file is in memory at a time
+	done := report.Time("seed")
+	now := time.Now()
+	err = coursesDB.SeedFrom(ctx, csvseq.Decode[db.Course](f))
+	done()
+	if err != nil {
+		logger.Error("failed to seed database", lessonlog.Err(err))
+		report.Exit(1)
+	}
+
+	n, err := countCourses(coursesDB)
+	if err != nil {
+		logger.Error("failed to count courses", lessonlog.Err(err))
+		report.Exit(1)
+	}
+	logger.Info("seeded database from CSV", lessonlog.KeyCount, n, lessonlog.Duration(time.Since(now)))
+	report.Add("courses", int64(n))
+
+	// An invalid record stops the iterator with an error, which rolls back
+	// the whole transaction
+	err = coursesDB.SeedFrom(context.Background(), csvseq.Decode[db.Course](strings.NewReader(brokenCSV)))
+	logger.Warn("failed to seed database from broken CSV", lessonlog.Err(err))
+
+	n, err = countCourses(coursesDB)
+	if err != nil {
+		logger.Error("failed to count courses", lessonlog.Err(err))
+		report.Exit(1)
+	}
+	logger.Info("nothing was inserted from the broken CSV", lessonlog.KeyCount, n)
+}
@@ -1,12 +1,17 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"iter"
-	"math/rand"
+	"math/rand/v2"
+	"slices"
+	"sync/atomic"
 
 	_ "github.com/mattn/go-sqlite3"
+	"golang.org/x/sync/errgroup"
 )
 
 type (
@@ -14,17 +19,29 @@ type (
 		// Seed seeds the database with the number of courses specified
 		Seed(numCourses int) error
 
+		// SeedConcurrent seeds the database with the number of courses
+		// specified, split evenly across the number of workers specified
+		SeedConcurrent(ctx context.Context, numCourses, workers int) error
+
+		// SeedFrom seeds the database with the courses of an iterator, such
+		// as one which decodes them from a file. Nothing is inserted if the
+		// iterator yields an error.
+		SeedFrom(ctx context.Context, courses iter.Seq2[Course, error]) error
+
 		// GetCourses returns an iterator of Course objects
 		GetCourses() iter.Seq2[Course, error]
 
+		// GetCourse returns the course with the given ID, or ErrNotFound
+		GetCourse(ctx context.Context, id int) (Course, error)
+
 		// Close closes the database
 		Close() error
 	}
 
 	Course struct {
-		ID         int
-		Name       string
-		University string
+		ID         int    `csv:"id" json:"id"`
+		Name       string `csv:"name" json:"name"`
+		University string `csv:"university" json:"university"`
 	}
 
 	coursesDB struct {
@@ -34,6 +51,7 @@ type (
 
 const (
 	selectSQL    = `SELECT * FROM courses`
+	selectOneSQL = `SELECT * FROM courses WHERE id = ?`
 	insertSQL    = `INSERT INTO courses(name, university) VALUES (?, ?)`
 	dropTableSQL = `DROP TABLE IF EXISTS courses`
 
@@ -44,6 +62,9 @@ const (
     );`
 )
 
+// ErrNotFound is returned when getting a course which does not exist
+var ErrNotFound = errors.New("course not found")
+
 var (
 	courseNames = []string{
 		"Chem-1",
@@ -62,6 +83,12 @@ var (
 		"UCB",
 		"UCSF",
 	}
+
+	// seed is the seed set by Seed, or zero to generate different courses
+	// on every run. generated counts the iterations over GenerateCourses
+	// since the seed was set, so that each one generates different courses.
+	seed      atomic.Uint64
+	generated atomic.Uint64
 )
 
 // New creates a new CoursesDB instance
@@ -71,8 +98,9 @@ func New(dataDir string) (CoursesDB, error) {
 		err error
 	)
 
-	// Open database
-	db, err = sql.Open("sqlite3", fmt.Sprintf("%s/courses.db", dataDir))
+	// Open database. SQLite only allows one writer at a time, and the busy
+	// timeout makes other writers wait for the lock instead of failing.
+	db, err = sql.Open("sqlite3", fmt.Sprintf("%s/courses.db?_busy_timeout=10000", dataDir))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -83,48 +111,59 @@ func New(dataDir string) (CoursesDB, error) {
 }
 
 func (d *coursesDB) Seed(numCourses int) error {
-	var (
-		tx        *sql.Tx
-		statement *sql.Stmt
-		err       error
-	)
-
-	_, err = d.db.Exec(dropTableSQL)
+	err := d.createTable()
 	if err != nil {
-		return fmt.Errorf("failed to drop table: %w", err)
+		return err
 	}
 
-	_, err = d.db.Exec(createTableSQL)
-	if err != nil {
-		return fmt.Errorf("failed to create table: %w", err)
-	}
+	return d.insertCourses(context.Background(), withoutErrors(GenerateCourses(numCourses)))
+}
 
-	tx, err = d.db.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to start transaction: %w", err)
+// SeedConcurrent partitions the courses across the workers, and each worker
+// inserts its share in its own transaction. SQLite serializes write
+// transactions, so the workers spend most of their time waiting for each
+// other, and this is usually slower than Seed. It is a useful baseline for
+// databases which do support concurrent writers.
+func (d *coursesDB) SeedConcurrent(ctx context.Context, numCourses, workers int) error {
+	var g *errgroup.Group
+
+	if workers < 1 {
+		workers = 1
 	}
 
-	statement, err = tx.Prepare(insertSQL)
+	err := d.createTable()
 	if err != nil {
-		return fmt.Errorf("failed to prepare SQL statment: %w", err)
+		return err
 	}
-	defer statement.Close()
 
-	// Seed database
-	for course := range d.generateCourses(numCourses) {
-		_, err = statement.Exec(course.Name, course.University)
-		if err != nil {
-			tx.Rollback()
-			return fmt.Errorf("failed to prepare SQL statment: %w", err)
+	g, ctx = errgroup.WithContext(ctx)
+
+	for w := range workers {
+		// Each worker gets an equal share, and the first workers get one
+		// extra course each until the remainder is used up
+		n := numCourses / workers
+		if w < numCourses%workers {
+			n++
 		}
+
+		g.Go(func() error {
+			return d.insertCourses(ctx, withoutErrors(GenerateCourses(n)))
+		})
 	}
 
-	err = tx.Commit()
+	return g.Wait()
+}
+
+// SeedFrom inserts the courses in a single transaction, like Seed, and rolls
+// it back if the iterator yields an error. The IDs of the courses are ignored,
+// since the database assigns its own.
+func (d *coursesDB) SeedFrom(ctx context.Context, courses iter.Seq2[Course, error]) error {
+	err := d.createTable()
 	if err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return err
 	}
 
-	return nil
+	return d.insertCourses(ctx, courses)
 }
 
 func (d *coursesDB) GetCourses() iter.Seq2[Course, error] {
@@ -162,17 +201,126 @@ func (d *coursesDB) GetCourses() iter.Seq2[Course, error] {
 	}
 }
 
+func (d *coursesDB) GetCourse(ctx context.Context, id int) (Course, error) {
+	var c Course
+
+	err := d.db.QueryRowContext(ctx, selectOneSQL, id).Scan(&c.ID, &c.Name, &c.University)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Course{}, fmt.Errorf("%w: course %d", ErrNotFound, id)
+	}
+	if err != nil {
+		return Course{}, fmt.Errorf("failed to get course %d: %w", id, err)
+	}
+
+	return c, nil
+}
+
 func (d *coursesDB) Close() error {
 	return d.db.Close()
 }
 
-// Generator of Course objects
-func (d *coursesDB) generateCourses(numCourses int) iter.Seq[Course] {
+// createTable replaces the courses table with an empty one
+func (d *coursesDB) createTable() error {
+	_, err := d.db.Exec(dropTableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to drop table: %w", err)
+	}
+
+	_, err = d.db.Exec(createTableSQL)
+	if err != nil {
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+
+	return nil
+}
+
+// insertCourses inserts every course in a single transaction, which is rolled
+// back if the iterator yields an error
+func (d *coursesDB) insertCourses(ctx context.Context, courses iter.Seq2[Course, error]) error {
+	var (
+		tx        *sql.Tx
+		statement *sql.Stmt
+		err       error
+	)
+
+	tx, err = d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	statement, err = tx.PrepareContext(ctx, insertSQL)
+	if err != nil {
+		return fmt.Errorf("failed to prepare SQL statment: %w", err)
+	}
+	defer statement.Close()
+
+	for course, err := range courses {
+		if err != nil {
+			return fmt.Errorf("failed to read course: %w", err)
+		}
+
+		_, err = statement.ExecContext(ctx, course.Name, course.University)
+		if err != nil {
+			return fmt.Errorf("failed to insert course: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// withoutErrors adapts an iterator of courses which cannot fail to the
+// iterator insertCourses reads
+func withoutErrors(courses iter.Seq[Course]) iter.Seq2[Course, error] {
+	return func(yield func(Course, error) bool) {
+		for course := range courses {
+			if !yield(course, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Universities returns the universities that generated courses belong to
+func Universities() []string {
+	return slices.Clone(universities)
+}
+
+// Seed makes the courses generated from now on the same on every run which
+// uses the same seed. A seed of zero generates different courses on every
+// run, which is the default.
+func Seed(s uint64) {
+	seed.Store(s)
+	generated.Store(0)
+}
+
+// newRand returns the random number generator for an iteration over
+// GenerateCourses
+func newRand() *rand.Rand {
+	s := seed.Load()
+	if s == 0 {
+		return rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+
+	return rand.New(rand.NewPCG(s, generated.Add(1)))
+}
+
+// GenerateCourses returns a generator of randomly populated Course objects.
+// The ID of each course is its 1-based position in the sequence.
+func GenerateCourses(numCourses int) iter.Seq[Course] {
 	return func(yield func(Course) bool) {
-		for range numCourses {
+		r := newRand()
+
+		for i := range numCourses {
 			course := Course{
-				Name:       courseNames[rand.Intn(len(courseNames))],
-				University: universities[rand.Intn(len(universities))],
+				ID:         i + 1,
+				Name:       courseNames[r.IntN(len(courseNames))],
+				University: universities[r.IntN(len(universities))],
 			}
 
 			if !yield(course) {
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+const numCourses = 100000
+
+func newTestDB(tb testing.TB) CoursesDB {
+	tb.Helper()
+
+	coursesDB, err := New(tb.TempDir())
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { coursesDB.Close() })
+
+	return coursesDB
+}
+
+func TestSeedFrom(t *testing.T) {
+	coursesDB := newTestDB(t)
+
+	courses := slices.Collect(GenerateCourses(10))
+
+	err := coursesDB.SeedFrom(context.Background(), withoutErrors(slices.Values(courses)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An error from the iterator rolls back every course inserted before it
+	errBroken := errors.New("broken record")
+	err = coursesDB.SeedFrom(context.Background(), func(yield func(Course, error) bool) {
+		_ = yield(courses[0], nil) && yield(Course{}, errBroken)
+	})
+	if !errors.Is(err, errBroken) {
+		t.Fatalf("expected the iterator's error, got %v", err)
+	}
+
+	for _, err := range coursesDB.GetCourses() {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Fatal("expected no courses after the failed seed")
+	}
+}
+
+func TestGetCourse(t *testing.T) {
+	coursesDB := newTestDB(t)
+
+	err := coursesDB.Seed(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	course, err := coursesDB.GetCourse(context.Background(), 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if course.ID != 7 || course.Name == "" || course.University == "" {
+		t.Fatalf("expected course 7, got %+v", course)
+	}
+
+	_, err = coursesDB.GetCourse(context.Background(), 11)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func BenchmarkSeed(b *testing.B) {
+	coursesDB := newTestDB(b)
+
+	for range b.N {
+		err := coursesDB.Seed(numCourses)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSeedConcurrent(b *testing.B) {
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			coursesDB := newTestDB(b)
+
+			for range b.N {
+				err := coursesDB.SeedConcurrent(context.Background(), numCourses, workers)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateCoursesConformance(t *testing.T) {
+	newSeq := func() iter.Seq[Course] { return GenerateCourses(5) }
+
+	seqtest.AssertStopsEarly(t, 5, newSeq)
+	seqtest.AssertNoYieldAfterFalse(t, 5, newSeq)
+}
+
+func TestGetCoursesConformance(t *testing.T) {
+	testDB := newTestDB(t)
+
+	courses := slices.Collect(GenerateCourses(3))
+
+	err := testDB.SeedFrom(context.Background(), withoutErrors(slices.Values(courses)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seqtest.AssertYields2(t, testDB.GetCourses(), courses, make([]error, len(courses)))
+	seqtest.AssertStopsEarly2(t, len(courses), testDB.GetCourses)
+	seqtest.AssertNoYieldAfterFalse2(t, len(courses), testDB.GetCourses)
+
+	// The rows hold on to a connection until they are closed
+	sqlDB := testDB.(*coursesDB).db
+	seqtest.AssertCleanupRuns2(t, len(courses), func() (iter.Seq2[Course, error], func() bool) {
+		return testDB.GetCourses(), func() bool { return sqlDB.Stats().InUse == 0 }
+	})
+}
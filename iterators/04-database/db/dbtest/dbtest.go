@@ -0,0 +1,191 @@
+package dbtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// ErrClosed is returned by the methods of a Fake once it is closed
+var ErrClosed = errors.New("database is closed")
+
+// Fake is an in-memory db.CoursesDB for tests. Its courses are numbered from
+// 1, like the rows of the real database. It can be told to fail, and records
+// the calls made to it.
+type Fake struct {
+	mu      sync.Mutex
+	courses []db.Course
+	calls   []string
+	closed  bool
+
+	err       error
+	failAfter int
+}
+
+var _ db.CoursesDB = (*Fake)(nil)
+
+// NewFake returns a Fake which holds the courses, with the IDs they are given
+func NewFake(courses ...db.Course) *Fake {
+	return &Fake{courses: slices.Clone(courses), failAfter: -1}
+}
+
+// Fail makes GetCourses yield err after n courses, and stop. An n of 0 fails
+// at once, and GetCourse fails with err as well.
+func (f *Fake) Fail(n int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.failAfter, f.err = n, err
+}
+
+// Calls returns the calls which have been made to the Fake, in order, such as
+// "GetCourse(42)"
+func (f *Fake) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.calls)
+}
+
+// Closed reports whether Close has been called
+func (f *Fake) Closed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.closed
+}
+
+// record adds a call to the calls of the Fake, and returns ErrClosed if the
+// Fake is closed. f.mu must be held.
+func (f *Fake) record(format string, args ...any) error {
+	f.calls = append(f.calls, fmt.Sprintf(format, args...))
+	if f.closed {
+		return ErrClosed
+	}
+
+	return nil
+}
+
+func (f *Fake) Seed(numCourses int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	err := f.record("Seed(%d)", numCourses)
+	if err != nil {
+		return err
+	}
+
+	f.courses = slices.Collect(db.GenerateCourses(numCourses))
+	return nil
+}
+
+// SeedConcurrent seeds the Fake like Seed does, as there is nothing to gain
+// from workers in memory
+func (f *Fake) SeedConcurrent(ctx context.Context, numCourses, workers int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	err := f.record("SeedConcurrent(%d, %d)", numCourses, workers)
+	if err != nil {
+		return err
+	}
+
+	f.courses = slices.Collect(db.GenerateCourses(numCourses))
+	return nil
+}
+
+func (f *Fake) SeedFrom(ctx context.Context, courses iter.Seq2[db.Course, error]) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	err := f.record("SeedFrom")
+	if err != nil {
+		return err
+	}
+
+	var seeded []db.Course
+	for course, err := range courses {
+		if err != nil {
+			return err
+		}
+
+		course.ID = len(seeded) + 1
+		seeded = append(seeded, course)
+	}
+
+	f.courses = seeded
+	return nil
+}
+
+// GetCourses yields a copy of the courses taken when the loop starts, so a
+// consumer may call the other methods of the Fake from its loop body
+func (f *Fake) GetCourses() iter.Seq2[db.Course, error] {
+	return func(yield func(db.Course, error) bool) {
+		f.mu.Lock()
+		err := f.record("GetCourses")
+		courses, failAfter, failErr := slices.Clone(f.courses), f.failAfter, f.err
+		f.mu.Unlock()
+
+		if err != nil {
+			yield(db.Course{}, err)
+			return
+		}
+
+		for i, course := range courses {
+			if i == failAfter {
+				yield(db.Course{}, failErr)
+				return
+			}
+
+			if !yield(course, nil) {
+				return
+			}
+		}
+
+		if len(courses) == failAfter {
+			yield(db.Course{}, failErr)
+		}
+	}
+}
+
+func (f *Fake) GetCourse(ctx context.Context, id int) (db.Course, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	err := f.record("GetCourse(%d)", id)
+	if err != nil {
+		return db.Course{}, err
+	}
+
+	if f.failAfter == 0 {
+		return db.Course{}, f.err
+	}
+
+	err = ctx.Err()
+	if err != nil {
+		return db.Course{}, err
+	}
+
+	for _, course := range f.courses {
+		if course.ID == id {
+			return course, nil
+		}
+	}
+
+	return db.Course{}, fmt.Errorf("%w: course %d", db.ErrNotFound, id)
+}
+
+func (f *Fake) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	err := f.record("Close")
+	f.closed = true
+
+	return err
+}
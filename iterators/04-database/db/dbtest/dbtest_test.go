@@ -0,0 +1,90 @@
+package dbtest
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+var courses = []db.Course{
+	{ID: 1, Name: "Calculus-1", University: "SJSU"},
+	{ID: 2, Name: "Physics-1", University: "UCB"},
+	{ID: 3, Name: "Chem-1", University: "UCSF"},
+}
+
+func TestFakeGetCourses(t *testing.T) {
+	fake := NewFake(courses...)
+
+	seqtest.AssertYields2(t, fake.GetCourses(), courses, []error{nil, nil, nil})
+	seqtest.AssertNoYieldAfterFalse2(t, 2, func() iter.Seq2[db.Course, error] { return fake.GetCourses() })
+}
+
+func TestFakeFail(t *testing.T) {
+	errBroken := errors.New("broken")
+
+	for _, n := range []int{0, 2, 3} {
+		fake := NewFake(courses...)
+		fake.Fail(n, errBroken)
+
+		var (
+			got []db.Course
+			err error
+		)
+		for course, e := range fake.GetCourses() {
+			if e != nil {
+				err = e
+				break
+			}
+
+			got = append(got, course)
+		}
+
+		if !slices.Equal(got, courses[:n]) || err != errBroken {
+			t.Fatalf("expected %d courses and the error, got %v and %v", n, got, err)
+		}
+	}
+
+	fake := NewFake(courses...)
+	fake.Fail(0, errBroken)
+
+	if _, err := fake.GetCourse(context.Background(), 1); err != errBroken {
+		t.Fatalf("expected GetCourse to fail with the error, got %v", err)
+	}
+}
+
+func TestFakeGetCourse(t *testing.T) {
+	fake := NewFake(courses...)
+
+	course, err := fake.GetCourse(context.Background(), 2)
+	if err != nil || course != courses[1] {
+		t.Fatalf("expected %v, got %v and %v", courses[1], course, err)
+	}
+
+	if _, err := fake.GetCourse(context.Background(), 4); !errors.Is(err, db.ErrNotFound) {
+		t.Fatalf("expected db.ErrNotFound, got %v", err)
+	}
+}
+
+func TestFakeCalls(t *testing.T) {
+	fake := NewFake()
+
+	_ = fake.Seed(5)
+	for range fake.GetCourses() {
+	}
+	_, _ = fake.GetCourse(context.Background(), 3)
+	_ = fake.Close()
+
+	if err := fake.Close(); err != ErrClosed {
+		t.Fatalf("expected ErrClosed from a closed Fake, got %v", err)
+	}
+
+	expected := []string{"Seed(5)", "GetCourses", "GetCourse(3)", "Close", "Close"}
+	if got := fake.Calls(); !slices.Equal(got, expected) {
+		t.Fatalf("expected the calls %q, got %q", expected, got)
+	}
+}
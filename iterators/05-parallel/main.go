@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/itertools"
+)
+
+const (
+	numCourses = 8
+	numWorkers = 4
+)
+
+var errCourseUnavailable = errors.New("course unavailable")
+
+// lookupCourse simulates a remote lookup, where the first course is much
+// slower than the others
+func lookupCourse(ctx context.Context, course db.Course) (string, error) {
+	delay := 10 * time.Millisecond
+	if course.ID == 1 {
+		delay = 200 * time.Millisecond
+	}
+
+	select {
+	case <-time.After(delay):
+		return fmt.Sprintf("%s at %s", course.Name, course.University), nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func main() {
+	// ParallelMap yields in input order, so nothing is yielded until the slow
+	// first course is done
+	now := time.Now()
+	for description, err := range itertools.ParallelMap(db.GenerateCourses(numCourses), numWorkers, func(course db.Course) (string, error) {
+		return lookupCourse(context.Background(), course)
+	}) {
+		if err != nil {
+			fmt.Printf("failed to look up course: %v\n", err)
+			continue
+		}
+
+		fmt.Printf("ordered: %3d ms: %s\n", time.Since(now).Milliseconds(), description)
+	}
+	fmt.Println()
+
+	// ForEachConcurrent handles each course as soon as it is done
+	now = time.Now()
+	err := itertools.ForEachConcurrent(context.Background(), db.GenerateCourses(numCourses), numWorkers, func(ctx context.Context, course db.Course) error {
+		description, err := lookupCourse(ctx, course)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("unordered: %3d ms: %s\n", time.Since(now).Milliseconds(), description)
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("failed to look up courses: %v\n", err)
+	}
+	fmt.Println()
+
+	// The first error cancels the slow lookup that is still in flight
+	now = time.Now()
+	err = itertools.ForEachConcurrent(context.Background(), db.GenerateCourses(numCourses), numWorkers, func(ctx context.Context, course db.Course) error {
+		if course.ID == 3 {
+			return fmt.Errorf("course %d: %w", course.ID, errCourseUnavailable)
+		}
+
+		_, err := lookupCourse(ctx, course)
+		return err
+	})
+	fmt.Printf("stopped after %d ms: %v\n", time.Since(now).Milliseconds(), err)
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/internal/report"
+	"github.com/manedurphy/golang-university/iterators/textio"
+)
+
+const mib = 1 << 20
+
+var (
+	levels   = []string{"DEBUG", "INFO", "INFO", "INFO", "WARN", "ERROR"}
+	messages = []string{"enrolled student", "dropped course", "graded exam", "updated syllabus"}
+)
+
+// generateLog writes a log of about size bytes to path. Every so often a
+// line carries a stack trace which is longer than bufio.Scanner accepts by
+// default, like the dump of a panicking service would.
+func generateLog(path string, size int64, r *rand.Rand) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create log: %w", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriterSize(f, mib)
+
+	var (
+		line    []byte
+		written int64
+		now     = time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+		trace   = strings.Repeat(" goroutine 1 [running]: main.enroll(...)", 8000)
+	)
+
+	for i := 0; written < size; i++ {
+		level := levels[r.IntN(len(levels))]
+
+		line = now.Add(time.Duration(i)*time.Millisecond).AppendFormat(line[:0], time.RFC3339Nano)
+		line = append(line, " level="...)
+		line = append(line, level...)
+		line = append(line, " course="...)
+		line = strconv.AppendInt(line, r.Int64N(100000), 10)
+		line = append(line, ` msg="`...)
+		line = append(line, messages[r.IntN(len(messages))]...)
+		line = append(line, '"')
+
+		if level == "ERROR" && r.IntN(10000) == 0 {
+			line = append(line, " stack="...)
+			line = append(line, trace...)
+		}
+
+		line = append(line, '\n')
+
+		n, err := w.Write(line)
+		if err != nil {
+			return fmt.Errorf("failed to write log: %w", err)
+		}
+
+		written += int64(n)
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to write log: %w", err)
+	}
+
+	return f.Close()
+}
+
+// scanDefault reads the log with a bufio.Scanner and its default buffer,
+// which gives up on the first line longer than 64 KiB
+func scanDefault(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	n := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		n++
+	}
+
+	return n + 1, scanner.Err()
+}
+
+// stats is what streaming the log found out about it
+type stats struct {
+	lines    int
+	longest  int
+	levels   map[string]int
+	peakHeap uint64
+}
+
+// stream reads every line of the log with textio.Lines. The heap is sampled
+// every so often, to show that it does not grow with the size of the file.
+func stream(ctx context.Context, path string) (*stats, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := &stats{levels: make(map[string]int)}
+
+	for line, err := range textio.Lines(f) {
+		if err != nil {
+			return nil, err
+		}
+
+		s.lines++
+		s.longest = max(s.longest, len(line))
+
+		_, rest, _ := strings.Cut(line, "level=")
+		level, _, _ := strings.Cut(rest, " ")
+		s.levels[level]++
+
+		if s.lines%(1<<16) == 0 {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			var mem runtime.MemStats
+			runtime.ReadMemStats(&mem)
+			s.peakHeap = max(s.peakHeap, mem.HeapInuse)
+		}
+	}
+
+	return s, nil
+}
+
+func main() {
+	// -count is the size of the log in MiB, and -data-dir is where it is
+	// written
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 2048, DataDir: os.TempDir()})
+
+	defer report.Write()
+
+	ctx, cancel := cfg.Context()
+	defer cancel()
+
+	// The log is removed on the way out, including before report.Exit, which
+	// skips deferred calls
+	path := filepath.Join(cfg.DataDir, "university-large-file.log")
+	defer os.Remove(path)
+
+	done := report.Time("generate")
+	now := time.Now()
+	err := generateLog(path, int64(cfg.Count)*mib, cfg.Rand())
+	done()
+	if err != nil {
+		fmt.Println(err)
+		os.Remove(path)
+		report.Exit(1)
+	}
+	fmt.Printf("generated a %d MiB log in %.2f seconds\n", cfg.Count, time.Since(now).Seconds())
+
+	// The default buffer of bufio.Scanner is too small for the stack traces
+	n, err := scanDefault(path)
+	fmt.Printf("bufio.Scanner stopped at line %d: %v\n", n, err)
+
+	done = report.Time("stream")
+	now = time.Now()
+	s, err := stream(ctx, path)
+	done()
+	if err != nil {
+		fmt.Printf("failed to stream log: %v\n", err)
+		os.Remove(path)
+		report.Exit(1)
+	}
+	report.Add("lines", int64(s.lines))
+
+	fmt.Printf("streamed %d lines in %.2f seconds, the longest was %d KiB\n", s.lines, time.Since(now).Seconds(), s.longest/1024)
+	for _, level := range []string{"DEBUG", "INFO", "WARN", "ERROR"} {
+		fmt.Printf("  %-5s %d\n", level, s.levels[level])
+	}
+	fmt.Printf("peak heap in use: %.2f MiB\n", float64(s.peakHeap)/mib)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/internal/report"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/jsonseq"
+)
+
+const mib = 1 << 20
+
+// writeArray writes the generated courses to path as a single JSON array.
+// Each course is encoded on its own, so writing the file does not need the
+// whole array in memory either.
+func writeArray(path string, numCourses int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create JSON file: %w", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+
+	w.WriteString("[\n")
+	for course := range db.GenerateCourses(numCourses) {
+		if course.ID > 1 {
+			w.WriteString(",")
+		}
+
+		err = enc.Encode(course)
+		if err != nil {
+			return fmt.Errorf("failed to encode course: %w", err)
+		}
+	}
+	w.WriteString("]\n")
+
+	err = w.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to write JSON file: %w", err)
+	}
+
+	return f.Close()
+}
+
+// memory returns the size of the heap, and the number of bytes allocated
+// since the program started
+func memory() (uint64, uint64) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	return mem.HeapAlloc, mem.TotalAlloc
+}
+
+// unmarshal decodes the whole array at once, which needs every course in
+// memory before the first one can be used
+func unmarshal(path string) (int, uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	var courses []db.Course
+	err = json.NewDecoder(f).Decode(&courses)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to decode courses: %w", err)
+	}
+
+	heap, _ := memory()
+	return len(courses), heap, nil
+}
+
+// stream decodes the array one course at a time. The heap is sampled every
+// so often, to show that it does not grow with the size of the array.
+func stream(path string) (int, uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	var (
+		n    int
+		peak uint64
+	)
+
+	for _, err := range jsonseq.Array[db.Course](bufio.NewReader(f)) {
+		if err != nil {
+			return n, peak, err
+		}
+
+		n++
+		if n%10000 == 0 {
+			heap, _ := memory()
+			peak = max(peak, heap)
+		}
+	}
+
+	return n, peak, nil
+}
+
+func main() {
+	// -count is the number of courses in the array, and -data-dir is where
+	// the JSON file is written
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 1000000, DataDir: os.TempDir()})
+	db.Seed(cfg.Seed)
+
+	defer report.Write()
+
+	path := filepath.Join(cfg.DataDir, "university-courses.json")
+	err := writeArray(path, cfg.Count)
+	if err != nil {
+		fmt.Println(err)
+		report.Exit(1)
+	}
+	defer os.Remove(path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		fmt.Println(err)
+		report.Exit(1)
+	}
+	fmt.Printf("wrote %d courses to a %.2f MiB JSON array\n", cfg.Count, float64(info.Size())/mib)
+
+	for _, decoder := range []struct {
+		name   string
+		decode func(string) (int, uint64, error)
+	}{
+		{"unmarshal", unmarshal},
+		{"stream", stream},
+	} {
+		// Start each decoder from a clean heap
+		runtime.GC()
+		_, allocated := memory()
+
+		done := report.Time(decoder.name)
+		now := time.Now()
+		n, heap, err := decoder.decode(path)
+		done()
+		if err != nil {
+			fmt.Println(err)
+			report.Exit(1)
+		}
+
+		_, total := memory()
+		fmt.Printf("%-9s decoded %d courses in %.2f seconds, heap %6.2f MiB, allocated %7.2f MiB\n",
+			decoder.name, n, time.Since(now).Seconds(), float64(heap)/mib, float64(total-allocated)/mib)
+	}
+}
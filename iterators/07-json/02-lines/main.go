@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/internal/report"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/jsonseq"
+)
+
+// compare pulls the original courses one at a time, and checks that the
+// replayed courses match them in the same order. It returns the number of
+// courses which matched.
+func compare(original iter.Seq[db.Course], replayed iter.Seq2[db.Course, error]) (int, error) {
+	next, stop := iter.Pull(original)
+	defer stop()
+
+	n := 0
+	for course, err := range replayed {
+		if err != nil {
+			return n, err
+		}
+
+		want, ok := next()
+		if !ok {
+			return n, fmt.Errorf("replayed more than the %d original courses", n)
+		}
+
+		if course != want {
+			return n, fmt.Errorf("course %d was replayed as %+v, expected %+v", n+1, course, want)
+		}
+
+		n++
+	}
+
+	if _, ok := next(); ok {
+		return n, fmt.Errorf("only %d courses were replayed", n)
+	}
+
+	return n, nil
+}
+
+func main() {
+	// -count is the number of courses, and -data-dir is where they are
+	// persisted
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 1000000, DataDir: os.TempDir()})
+
+	defer report.Write()
+
+	path := filepath.Join(cfg.DataDir, "university-courses.jsonl")
+	defer os.Remove(path)
+
+	f, err := os.Create(path)
+	if err != nil {
+		fmt.Println(err)
+		report.Exit(1)
+	}
+
+	// Persist the stream of courses as it is generated
+	db.Seed(cfg.Seed)
+
+	done := report.Time("write")
+	now := time.Now()
+	err = jsonseq.WriteLines(f, db.GenerateCourses(cfg.Count))
+	if err == nil {
+		err = f.Close()
+	}
+	done()
+	if err != nil {
+		fmt.Println(err)
+		report.Exit(1)
+	}
+	fmt.Printf("wrote %d courses in %.2f seconds\n", cfg.Count, time.Since(now).Seconds())
+
+	f, err = os.Open(path)
+	if err != nil {
+		fmt.Println(err)
+		report.Exit(1)
+	}
+	defer f.Close()
+
+	// Seeding again generates the same courses, which the replayed ones are
+	// compared with
+	db.Seed(cfg.Seed)
+
+	done = report.Time("replay")
+	now = time.Now()
+	n, err := compare(db.GenerateCourses(cfg.Count), jsonseq.Lines[db.Course](f))
+	done()
+	if err != nil {
+		fmt.Println(err)
+		report.Exit(1)
+	}
+	report.Add("courses", int64(n))
+
+	fmt.Printf("replayed %d courses in %.2f seconds, every course matched\n", n, time.Since(now).Seconds())
+}
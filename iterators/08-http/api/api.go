@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+type (
+	// Page is the body of a response of the courses API. Next is the path of
+	// the next page, and is left out of the last page.
+	Page struct {
+		Courses []db.Course `json:"courses"`
+		Next    string      `json:"next,omitempty"`
+	}
+
+	// Server serves a fixed list of courses, a page at a time, from
+	// /courses?page=N. Pages are numbered from 1.
+	Server struct {
+		courses  []db.Course
+		pageSize int
+		requests atomic.Int64
+	}
+)
+
+// NewServer returns a server which serves the courses, pageSize courses at a
+// time
+func NewServer(courses []db.Course, pageSize int) *Server {
+	return &Server{courses: courses, pageSize: max(pageSize, 1)}
+}
+
+// Requests returns the number of pages which have been requested
+func (s *Server) Requests() int {
+	return int(s.requests.Load())
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/courses" {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.requests.Add(1)
+
+	page := 1
+	if p := r.URL.Query().Get("page"); p != "" {
+		var err error
+
+		page, err = strconv.Atoi(p)
+		if err != nil || page < 1 {
+			http.Error(w, fmt.Sprintf("invalid page %q", p), http.StatusBadRequest)
+			return
+		}
+	}
+
+	start := min((page-1)*s.pageSize, len(s.courses))
+	end := min(start+s.pageSize, len(s.courses))
+
+	body := Page{Courses: s.courses[start:end]}
+	if end < len(s.courses) {
+		body.Next = fmt.Sprintf("/courses?page=%d", page+1)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/08-http/api"
+	"github.com/manedurphy/golang-university/iterators/httpseq"
+)
+
+// parsePage decodes a page of the courses API
+func parsePage(resp *http.Response) (httpseq.Page[db.Course], error) {
+	var page api.Page
+
+	err := json.NewDecoder(resp.Body).Decode(&page)
+	if err != nil {
+		return httpseq.Page[db.Course]{}, err
+	}
+
+	return httpseq.Page[db.Course]{Items: page.Courses, Next: page.Next}, nil
+}
+
+func main() {
+	// The API runs in the same process, so that the lesson needs no network
+	courses := api.NewServer(slices.Collect(db.GenerateCourses(100)), 10)
+
+	srv := httptest.NewServer(courses)
+	defer srv.Close()
+
+	ctx := context.Background()
+
+	// The consumer only needs the first 25 courses, so only the first 3 of
+	// the 10 pages are ever requested
+	for course, err := range httpseq.Paginate(ctx, srv.Client(), srv.URL+"/courses", parsePage) {
+		if err != nil {
+			fmt.Printf("failed to get course: %v\n", err)
+			return
+		}
+
+		if course.ID%5 == 0 {
+			fmt.Printf("course %d: %s at %s, %d pages requested\n", course.ID, course.Name, course.University, courses.Requests())
+		}
+
+		if course.ID == 25 {
+			break
+		}
+	}
+	fmt.Printf("stopped after requesting %d pages\n\n", courses.Requests())
+
+	// Ranging over every course follows the links to the last page
+	before := courses.Requests()
+
+	n := 0
+	for _, err := range httpseq.Paginate(ctx, srv.Client(), srv.URL+"/courses", parsePage) {
+		if err != nil {
+			fmt.Printf("failed to get course: %v\n", err)
+			return
+		}
+
+		n++
+	}
+	fmt.Printf("received all %d courses from %d pages\n\n", n, courses.Requests()-before)
+
+	// A page which does not exist stops the iteration with an error
+	for _, err := range httpseq.Paginate(ctx, srv.Client(), srv.URL+"/courses?page=zero", parsePage) {
+		fmt.Printf("failed to get course: %v\n", err)
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// flushEvery is the number of courses written between flushes. Flushing
+// sends what has been written so far to the client, instead of waiting for
+// the response writer's buffer to fill up.
+const flushEvery = 100
+
+const mib = 1 << 20
+
+type server struct {
+	db db.CoursesDB
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /courses", s.listCourses)
+	mux.HandleFunc("GET /courses.json", s.listCoursesBuffered)
+	mux.HandleFunc("GET /courses/{id}", s.getCourse)
+
+	return mux
+}
+
+// queryInt returns the value of an integer query parameter, or def if it is
+// not set
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s %q, expected a number of at least 0", name, v)
+	}
+
+	return n, nil
+}
+
+// listCourses streams the courses as JSON Lines, straight from the
+// database's iterator, so only the current course is in memory no matter how
+// many there are. offset skips courses and limit stops after that many, with
+// zero meaning no limit.
+func (s *server) listCourses(w http.ResponseWriter, r *http.Request) {
+	offset, err := queryInt(r, "offset", 0)
+	if err == nil {
+		var limit int
+
+		limit, err = queryInt(r, "limit", 0)
+		if err == nil {
+			s.streamCourses(w, r, offset, limit)
+			return
+		}
+	}
+
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
+
+func (s *server) streamCourses(w http.ResponseWriter, r *http.Request, offset, limit int) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+
+	rc := http.NewResponseController(w)
+	enc := json.NewEncoder(w)
+
+	skipped, sent := 0, 0
+	for course, err := range s.db.GetCourses() {
+		if err != nil {
+			// The status has already been sent, so the only way to tell the
+			// client that the response is incomplete is to abort it
+			log.Printf("failed to get course: %v", err)
+			panic(http.ErrAbortHandler)
+		}
+
+		if skipped < offset {
+			skipped++
+			continue
+		}
+
+		if limit > 0 && sent == limit {
+			break
+		}
+
+		// Writing fails once the client has gone away, which stops the
+		// database's iterator
+		err = enc.Encode(course)
+		if err != nil {
+			return
+		}
+
+		sent++
+		if sent%flushEvery == 0 {
+			err = rc.Flush()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// listCoursesBuffered collects every course before encoding them as a single
+// array, which is what streaming avoids
+func (s *server) listCoursesBuffered(w http.ResponseWriter, r *http.Request) {
+	var courses []db.Course
+	for course, err := range s.db.GetCourses() {
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		courses = append(courses, course)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(courses)
+}
+
+func (s *server) getCourse(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid course ID %q", r.PathValue("id")), http.StatusBadRequest)
+		return
+	}
+
+	course, err := s.db.GetCourse(r.Context(), id)
+	if errors.Is(err, db.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(course)
+}
+
+// get requests a path and prints its status and body
+func get(url, path string) error {
+	resp, err := http.Get(url + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("GET %s: %s\n%s", path, resp.Status, body)
+	return nil
+}
+
+// measure requests a path and discards its body, while sampling the heap to
+// find how far it grew above where it started
+func measure(url, path string) error {
+	runtime.GC()
+
+	var (
+		mem      runtime.MemStats
+		wg       sync.WaitGroup
+		peak     uint64
+		done     = make(chan struct{})
+		interval = time.Millisecond
+	)
+
+	runtime.ReadMemStats(&mem)
+	baseline := mem.HeapAlloc
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			var mem runtime.MemStats
+			runtime.ReadMemStats(&mem)
+			peak = max(peak, mem.HeapAlloc)
+
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	now := time.Now()
+
+	resp, err := http.Get(url + path)
+	if err != nil {
+		close(done)
+		wg.Wait()
+		return err
+	}
+	defer resp.Body.Close()
+
+	// The first byte shows how long the client waits before it can start
+	// working on the response
+	first := make([]byte, 1)
+	_, err = io.ReadFull(resp.Body, first)
+	firstByte := time.Since(now)
+
+	var n int64
+	if err == nil {
+		n, err = io.Copy(io.Discard, resp.Body)
+	}
+
+	close(done)
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("GET %-13s %6.2f MiB, first byte after %4d ms, done after %4d ms, peak heap growth %6.2f MiB\n",
+		path, float64(n+1)/mib, firstByte.Milliseconds(), time.Since(now).Milliseconds(), float64(peak-min(peak, baseline))/mib)
+
+	return nil
+}
+
+func run(cfg lessoncfg.Config) error {
+	dir, err := os.MkdirTemp(cfg.DataDir, "university-http-")
+	if err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	coursesDB, err := db.New(dir)
+	if err != nil {
+		return err
+	}
+	defer coursesDB.Close()
+
+	err = coursesDB.Seed(cfg.Count)
+	if err != nil {
+		return err
+	}
+
+	s := &server{db: coursesDB}
+
+	srv := httptest.NewServer(s.routes())
+	defer srv.Close()
+
+	for _, path := range []string{"/courses/42", "/courses/0", "/courses/forty-two", "/courses?offset=10&limit=3", "/courses?limit=-1"} {
+		err = get(srv.URL, path)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Println()
+
+	for _, path := range []string{"/courses", "/courses.json"} {
+		err = measure(srv.URL, path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func main() {
+	// -count is the number of courses the database is seeded with
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 200000, DataDir: os.TempDir()})
+	db.Seed(cfg.Seed)
+
+	err := run(cfg)
+	if err != nil {
+		fmt.Println(strings.TrimSpace(err.Error()))
+		os.Exit(1)
+	}
+}
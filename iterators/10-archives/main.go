@@ -0,0 +1,272 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"math/rand/v2"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/internal/report"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/archiveseq"
+)
+
+const mib = 1 << 20
+
+const (
+	// lectures are large files which the search has no interest in, like the
+	// recordings of an online course
+	lectures    = 16
+	lectureSize = 8 * mib
+
+	// pattern matches the courses the search is interested in
+	pattern = "courses/UCSF/*.json"
+)
+
+// archiveWriter writes the same files to a tar.gz and a zip archive
+type archiveWriter struct {
+	tw *tar.Writer
+	zw *zip.Writer
+}
+
+func (w *archiveWriter) add(name string, content []byte, method uint16) error {
+	err := w.tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), ModTime: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	_, err = w.tw.Write(content)
+	if err != nil {
+		return err
+	}
+
+	f, err := w.zw.CreateHeader(&zip.FileHeader{Name: name, Method: method, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(content)
+	return err
+}
+
+// writeArchives writes a course per file under courses/<university>/, and a
+// few large lectures, to a tar.gz and a zip archive in dir
+func writeArchives(dir string, numCourses int, r *rand.Rand) (tarPath, zipPath string, err error) {
+	tarPath = filepath.Join(dir, "courses.tar.gz")
+	zipPath = filepath.Join(dir, "courses.zip")
+
+	tf, err := os.Create(tarPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create tar.gz: %w", err)
+	}
+	defer tf.Close()
+
+	zf, err := os.Create(zipPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create zip: %w", err)
+	}
+	defer zf.Close()
+
+	gw, _ := gzip.NewWriterLevel(tf, gzip.BestSpeed)
+	w := &archiveWriter{tw: tar.NewWriter(gw), zw: zip.NewWriter(zf)}
+
+	lecture := make([]byte, lectureSize)
+	for i := range lectures {
+		for j := 0; j < len(lecture); j += 8 {
+			n := r.Uint64()
+			for k := range 8 {
+				lecture[j+k] = byte(n >> (8 * k))
+			}
+		}
+
+		// Random bytes do not compress, so the zip stores them as they are
+		err = w.add(fmt.Sprintf("lectures/lecture-%02d.mp4", i+1), lecture, zip.Store)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to write lecture: %w", err)
+		}
+	}
+
+	for course := range db.GenerateCourses(numCourses) {
+		content, err := json.Marshal(course)
+		if err != nil {
+			return "", "", err
+		}
+
+		err = w.add(path.Join("courses", course.University, strconv.Itoa(course.ID)+".json"), content, zip.Deflate)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to write course: %w", err)
+		}
+	}
+
+	for _, c := range []io.Closer{w.tw, gw, w.zw, tf, zf} {
+		err = c.Close()
+		if err != nil {
+			return "", "", fmt.Errorf("failed to write archives: %w", err)
+		}
+	}
+
+	return tarPath, zipPath, nil
+}
+
+// counter counts the bytes read from the archive file
+type counter struct {
+	f *os.File
+	n int64
+}
+
+func (c *counter) Read(p []byte) (int, error) {
+	n, err := c.f.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *counter) ReadAt(p []byte, off int64) (int, error) {
+	n, err := c.f.ReadAt(p, off)
+	c.n += int64(n)
+	return n, err
+}
+
+// search ranges over the entries of an archive, and only opens those which
+// match the pattern, to count the physics courses among them
+func search(entries iter.Seq2[archiveseq.Entry, error]) (found, opened int, err error) {
+	for entry, err := range entries {
+		if err != nil {
+			return 0, 0, err
+		}
+
+		ok, _ := path.Match(pattern, entry.Name)
+		if !ok {
+			continue
+		}
+
+		rc, err := entry.Open()
+		if err != nil {
+			return 0, 0, err
+		}
+
+		var course db.Course
+		err = json.NewDecoder(rc).Decode(&course)
+		rc.Close()
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to decode %s: %w", entry.Name, err)
+		}
+
+		opened++
+		if strings.HasPrefix(course.Name, "Physics") {
+			found++
+		}
+	}
+
+	return found, opened, nil
+}
+
+// searchTar searches the tar.gz archive at path. The whole archive has to be
+// decompressed to find the entries, even those which are never opened.
+func searchTar(path string) (found, opened int, read int64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer f.Close()
+
+	c := &counter{f: f}
+
+	gr, err := gzip.NewReader(c)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to read tar.gz: %w", err)
+	}
+	defer gr.Close()
+
+	found, opened, err = search(archiveseq.Tar(gr))
+	return found, opened, c.n, err
+}
+
+// searchZip searches the zip archive at path. Only the central directory and
+// the entries which are opened are read.
+func searchZip(path string) (found, opened int, read int64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	c := &counter{f: f}
+
+	found, opened, err = search(archiveseq.Zip(c, info.Size()))
+	return found, opened, c.n, err
+}
+
+func size(path string) float64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+
+	return float64(info.Size()) / mib
+}
+
+func main() {
+	// -count is the number of courses in the archives, and -data-dir is where
+	// they are written
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 50000, DataDir: os.TempDir()})
+	db.Seed(cfg.Seed)
+
+	defer report.Write()
+
+	dir, err := os.MkdirTemp(cfg.DataDir, "university-archives-")
+	if err != nil {
+		fmt.Printf("failed to create data directory: %v\n", err)
+		report.Exit(1)
+	}
+	defer os.RemoveAll(dir)
+
+	done := report.Time("generate")
+	tarPath, zipPath, err := writeArchives(dir, cfg.Count, cfg.Rand())
+	done()
+	if err != nil {
+		fmt.Println(err)
+		os.RemoveAll(dir)
+		report.Exit(1)
+	}
+	fmt.Printf("archived %d courses and %d lectures of %d MiB\n\n", cfg.Count, lectures, lectureSize/mib)
+
+	searches := []struct {
+		name   string
+		path   string
+		search func(string) (int, int, int64, error)
+	}{
+		{"tar.gz", tarPath, searchTar},
+		{"zip", zipPath, searchZip},
+	}
+
+	for _, s := range searches {
+		done = report.Time(s.name)
+		now := time.Now()
+		found, opened, read, err := s.search(s.path)
+		done()
+		if err != nil {
+			fmt.Printf("failed to search %s: %v\n", s.name, err)
+			os.RemoveAll(dir)
+			report.Exit(1)
+		}
+
+		fmt.Printf("%-6s found %d physics courses among %d matching %s in %d ms, reading %.2f of %.2f MiB\n",
+			s.name, found, opened, pattern, time.Since(now).Milliseconds(), float64(read)/mib, size(s.path))
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/internal/report"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/compressio"
+	"github.com/manedurphy/golang-university/iterators/jsonseq"
+)
+
+const mib = 1 << 20
+
+// export writes the generated courses to path as JSON Lines, compressed in
+// the format given by its extension
+func export(path string, numCourses int) error {
+	w, err := compressio.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create export: %w", err)
+	}
+
+	err = jsonseq.WriteLines(w, db.GenerateCourses(numCourses))
+	if err != nil {
+		w.Close()
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	// Closing writes the end of the compressed data, so its error matters
+	return w.Close()
+}
+
+// load reads the courses back from path. The heap is sampled every so often,
+// to show that decompressing does not need more memory than reading the
+// file as it is.
+func load(path string) (n int, peakHeap uint64, err error) {
+	r, err := compressio.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer r.Close()
+
+	for _, err := range jsonseq.Lines[db.Course](r) {
+		if err != nil {
+			return 0, 0, err
+		}
+
+		n++
+		if n%(1<<16) == 0 {
+			var mem runtime.MemStats
+			runtime.ReadMemStats(&mem)
+			peakHeap = max(peakHeap, mem.HeapInuse)
+		}
+	}
+
+	return n, peakHeap, nil
+}
+
+func main() {
+	// -count is the number of courses exported, and -data-dir is where the
+	// exports are written
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 1000000, DataDir: os.TempDir()})
+	db.Seed(cfg.Seed)
+
+	defer report.Write()
+
+	dir, err := os.MkdirTemp(cfg.DataDir, "university-compression-")
+	if err != nil {
+		fmt.Printf("failed to create data directory: %v\n", err)
+		report.Exit(1)
+	}
+	defer os.RemoveAll(dir)
+
+	// The throughput is measured in MiB of JSON, before compression, so that
+	// the formats can be compared with each other
+	var jsonSize float64
+
+	fmt.Printf("%-18s %10s %12s %12s %10s\n", "export", "size", "write", "read", "peak heap")
+	for _, name := range []string{"courses.ndjson", "courses.ndjson.gz", "courses.ndjson.zst"} {
+		path := filepath.Join(dir, name)
+		format := compressio.FormatOf(name)
+
+		done := report.Time("write " + format.String())
+		now := time.Now()
+		err = export(path, cfg.Count)
+		done()
+		if err != nil {
+			fmt.Println(err)
+			os.RemoveAll(dir)
+			report.Exit(1)
+		}
+		written := time.Since(now)
+
+		info, err := os.Stat(path)
+		if err != nil {
+			fmt.Println(err)
+			os.RemoveAll(dir)
+			report.Exit(1)
+		}
+
+		size := float64(info.Size()) / mib
+		if format == compressio.None {
+			jsonSize = size
+		}
+
+		done = report.Time("read " + format.String())
+		now = time.Now()
+		n, peakHeap, err := load(path)
+		done()
+		if err != nil {
+			fmt.Printf("failed to read %s: %v\n", name, err)
+			os.RemoveAll(dir)
+			report.Exit(1)
+		}
+		read := time.Since(now)
+
+		if n != cfg.Count {
+			fmt.Printf("expected %d courses in %s, got %d\n", cfg.Count, name, n)
+			os.RemoveAll(dir)
+			report.Exit(1)
+		}
+
+		fmt.Printf("%-18s %6.2f MiB %7.1f MiB/s %7.1f MiB/s %6.2f MiB\n",
+			name, size, jsonSize/written.Seconds(), jsonSize/read.Seconds(), float64(peakHeap)/mib)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/manedurphy/golang-university/internal/lesson"
+	"github.com/manedurphy/golang-university/iterators/fsseq"
+)
+
+// opts skip the directories which never contain lessons: hidden ones such as
+// .git, the command line tool and its packages, and test fixtures
+var opts = []fsseq.Option{
+	fsseq.SkipDirs("cmd", "internal", "testdata"),
+	fsseq.SkipDirFunc(func(_ string, d fs.DirEntry) bool {
+		return strings.HasPrefix(d.Name(), ".")
+	}),
+	fsseq.OnError(func(p string, err error) {
+		fmt.Printf("skipped %s: %v\n", p, err)
+	}),
+}
+
+func main() {
+	root, err := lesson.FindRoot(".")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fsys := os.DirFS(root)
+
+	// Every lesson is a main package with a main.go, so counting them per
+	// module only needs the paths
+	var (
+		modules []string
+		counts  = make(map[string]int)
+		total   int
+	)
+
+	for p, d := range fsseq.Walk(fsys, ".", opts...) {
+		if d.IsDir() || d.Name() != "main.go" {
+			continue
+		}
+
+		module, _, _ := strings.Cut(p, "/")
+		if counts[module] == 0 {
+			modules = append(modules, module)
+		}
+
+		counts[module]++
+		total++
+	}
+
+	fmt.Printf("found %d main.go files\n", total)
+	for _, module := range modules {
+		fmt.Printf("  %-14s %d\n", module, counts[module])
+	}
+
+	// Breaking out of the loop stops the walk, so only the entries up to the
+	// first lesson with a manifest are ever read
+	visited := 0
+	for p, d := range fsseq.Walk(fsys, ".", opts...) {
+		visited++
+
+		if d.IsDir() {
+			continue
+		}
+
+		if d.Name() == "lesson.yaml" {
+			fmt.Printf("\nthe first lesson with a manifest is %s, found after visiting %d entries\n", path.Dir(p), visited)
+			break
+		}
+	}
+}
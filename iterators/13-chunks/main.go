@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/internal/report"
+	"github.com/manedurphy/golang-university/iterators/ioseq"
+)
+
+const mib = 1 << 20
+
+// chunkSize is the size of the chunks the file is hashed in
+const chunkSize = mib
+
+// generateFile writes size bytes of random data to path
+func generateFile(path string, size int64, r *rand.Rand) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer f.Close()
+
+	buf := make([]byte, mib)
+	for written := int64(0); written < size; written += int64(len(buf)) {
+		for i := 0; i < len(buf); i += 8 {
+			n := r.Uint64()
+			for j := range 8 {
+				buf[i+j] = byte(n >> (8 * j))
+			}
+		}
+
+		_, err = f.Write(buf)
+		if err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+	}
+
+	return f.Close()
+}
+
+// hashReadFile reads the whole file into memory before hashing it
+func hashReadFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// hashChunks hashes the file one chunk at a time. The hash only needs to see
+// each chunk once, so the chunks are never kept.
+func hashChunks(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	for chunk, err := range ioseq.Chunks(f, chunkSize) {
+		if err != nil {
+			return "", err
+		}
+
+		h.Write(chunk)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// measure runs hash and prints how long it took and how much it allocated
+func measure(name, path string, hash func(string) (string, error)) (string, error) {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	done := report.Time(name)
+	now := time.Now()
+	sum, err := hash(path)
+	done()
+	if err != nil {
+		return "", err
+	}
+
+	runtime.ReadMemStats(&after)
+
+	fmt.Printf("%-16s %s... in %4d ms, allocated %8.2f MiB\n",
+		name, sum[:16], time.Since(now).Milliseconds(), float64(after.TotalAlloc-before.TotalAlloc)/mib)
+
+	return sum, nil
+}
+
+// keepChunks keeps the first few chunks of r, copying them if clone is true,
+// and returns how many of them are different
+func keepChunks(r io.Reader, n int, clone bool) (int, error) {
+	var kept [][]byte
+	for chunk, err := range ioseq.Chunks(r, chunkSize) {
+		if err != nil {
+			return 0, err
+		}
+
+		if clone {
+			chunk = bytes.Clone(chunk)
+		}
+
+		kept = append(kept, chunk)
+		if len(kept) == n {
+			break
+		}
+	}
+
+	different := 0
+	for i := range kept {
+		if i == 0 || !bytes.Equal(kept[i], kept[i-1]) {
+			different++
+		}
+	}
+
+	return different, nil
+}
+
+func main() {
+	// -count is the size of the file in MiB, and -data-dir is where it is
+	// written
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 1024, DataDir: os.TempDir()})
+
+	defer report.Write()
+
+	// The file is removed on the way out, including before report.Exit, which
+	// skips deferred calls
+	path := filepath.Join(cfg.DataDir, "university-chunks.bin")
+	defer os.Remove(path)
+
+	done := report.Time("generate")
+	err := generateFile(path, int64(cfg.Count)*mib, cfg.Rand())
+	done()
+	if err != nil {
+		fmt.Println(err)
+		os.Remove(path)
+		report.Exit(1)
+	}
+	fmt.Printf("generated a %d MiB file\n\n", cfg.Count)
+
+	// The second run of the chunks takes its buffer from the pool the first
+	// one put it back in
+	var sums []string
+	for _, h := range []struct {
+		name string
+		hash func(string) (string, error)
+	}{
+		{"os.ReadFile", hashReadFile},
+		{"ioseq.Chunks", hashChunks},
+		{"ioseq.Chunks", hashChunks},
+	} {
+		sum, err := measure(h.name, path, h.hash)
+		if err != nil {
+			fmt.Printf("failed to hash file: %v\n", err)
+			os.Remove(path)
+			report.Exit(1)
+		}
+
+		sums = append(sums, sum)
+	}
+	fmt.Printf("hashes match: %t\n\n", sums[0] == sums[1] && sums[1] == sums[2])
+
+	// Every chunk is the same buffer, so keeping them without a copy keeps
+	// the last one several times over
+	for _, clone := range []bool{false, true} {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Println(err)
+			os.Remove(path)
+			report.Exit(1)
+		}
+
+		different, err := keepChunks(f, 4, clone)
+		f.Close()
+		if err != nil {
+			fmt.Printf("failed to read file: %v\n", err)
+			os.Remove(path)
+			report.Exit(1)
+		}
+
+		how := "without copying"
+		if clone {
+			how = "with bytes.Clone"
+		}
+
+		fmt.Printf("kept 4 chunks %s, %d of them different\n", how, different)
+	}
+}
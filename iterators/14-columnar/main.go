@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/internal/report"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/14-columnar/parquetfile"
+	"github.com/manedurphy/golang-university/iterators/csvseq"
+)
+
+const mib = 1 << 20
+
+// rowGroupSize is the number of rows the Parquet writer holds in memory
+// before it writes them out column by column
+const rowGroupSize = 64 << 10
+
+// writeCSV writes the generated courses to path as CSV, a row at a time
+func writeCSV(path string, numCourses int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV: %w", err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(bufio.NewWriter(f))
+	w.Write([]string{"id", "name", "university"})
+
+	for course := range db.GenerateCourses(numCourses) {
+		w.Write([]string{strconv.Itoa(course.ID), course.Name, course.University})
+	}
+
+	w.Flush()
+	if w.Error() != nil {
+		return fmt.Errorf("failed to write CSV: %w", w.Error())
+	}
+
+	return f.Close()
+}
+
+// writeParquet writes the generated courses to path as a Parquet file
+func writeParquet(path string, numCourses int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create Parquet file: %w", err)
+	}
+	defer f.Close()
+
+	err = parquetfile.Write(f, db.GenerateCourses(numCourses), rowGroupSize)
+	if err != nil {
+		return fmt.Errorf("failed to write Parquet file: %w", err)
+	}
+
+	return f.Close()
+}
+
+// counter counts the bytes read from a file
+type counter struct {
+	f *os.File
+	n int64
+}
+
+func (c *counter) Read(p []byte) (int, error) {
+	n, err := c.f.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *counter) ReadAt(p []byte, off int64) (int, error) {
+	n, err := c.f.ReadAt(p, off)
+	c.n += int64(n)
+	return n, err
+}
+
+// scanCSV counts the courses of every university in the CSV. Every row has
+// to be read and split into all of its fields to get to the university.
+func scanCSV(c *counter, _ int64) (map[string]int, error) {
+	counts := make(map[string]int)
+	for course, err := range csvseq.Decode[db.Course](c) {
+		if err != nil {
+			return nil, err
+		}
+
+		counts[course.University]++
+	}
+
+	return counts, nil
+}
+
+// scanColumns returns a scan which counts the courses of every university in
+// the Parquet file, reading only the given columns
+func scanColumns(columns ...string) func(*counter, int64) (map[string]int, error) {
+	return func(c *counter, size int64) (map[string]int, error) {
+		f, err := parquetfile.Open(c, size)
+		if err != nil {
+			return nil, err
+		}
+
+		counts := make(map[string]int)
+		for course, err := range f.Scan(columns...) {
+			if err != nil {
+				return nil, err
+			}
+
+			counts[course.University]++
+		}
+
+		return counts, nil
+	}
+}
+
+func main() {
+	// -count is the number of courses written, and -data-dir is where the
+	// files are written
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 1000000, DataDir: os.TempDir()})
+	db.Seed(cfg.Seed)
+
+	defer report.Write()
+
+	dir, err := os.MkdirTemp(cfg.DataDir, "university-columnar-")
+	if err != nil {
+		fmt.Printf("failed to create data directory: %v\n", err)
+		report.Exit(1)
+	}
+	defer os.RemoveAll(dir)
+
+	csvPath := filepath.Join(dir, "courses.csv")
+	parquetPath := filepath.Join(dir, "courses.parquet")
+
+	// Both files get the same courses, since the generator is seeded again
+	// before each of them
+	for _, w := range []struct {
+		path  string
+		write func(string, int) error
+	}{
+		{csvPath, writeCSV},
+		{parquetPath, writeParquet},
+	} {
+		db.Seed(cfg.Seed)
+
+		err = w.write(w.path, cfg.Count)
+		if err != nil {
+			fmt.Println(err)
+			os.RemoveAll(dir)
+			report.Exit(1)
+		}
+
+		info, err := os.Stat(w.path)
+		if err != nil {
+			fmt.Println(err)
+			os.RemoveAll(dir)
+			report.Exit(1)
+		}
+
+		fmt.Printf("wrote %d courses to %-15s %6.2f MiB\n", cfg.Count, filepath.Base(w.path), float64(info.Size())/mib)
+	}
+	fmt.Println()
+
+	var expected map[string]int
+
+	for _, s := range []struct {
+		name string
+		path string
+		scan func(*counter, int64) (map[string]int, error)
+	}{
+		{"csv", csvPath, scanCSV},
+		{"parquet, all", parquetPath, scanColumns()},
+		{"parquet, university", parquetPath, scanColumns("university")},
+	} {
+		f, err := os.Open(s.path)
+		if err != nil {
+			fmt.Println(err)
+			os.RemoveAll(dir)
+			report.Exit(1)
+		}
+
+		info, _ := f.Stat()
+		c := &counter{f: f}
+
+		done := report.Time(s.name)
+		now := time.Now()
+		counts, err := s.scan(c, info.Size())
+		done()
+		f.Close()
+		if err != nil {
+			fmt.Printf("failed to scan %s: %v\n", s.name, err)
+			os.RemoveAll(dir)
+			report.Exit(1)
+		}
+
+		if expected == nil {
+			expected = counts
+		}
+
+		same := len(counts) == len(expected)
+		for university, n := range expected {
+			same = same && counts[university] == n
+		}
+
+		fmt.Printf("%-21s %4d ms, read %6.2f MiB, same counts as csv: %t\n",
+			s.name, time.Since(now).Milliseconds(), float64(c.n)/mib, same)
+	}
+
+	fmt.Println()
+	for _, university := range db.Universities() {
+		fmt.Printf("%-5s %d courses\n", university, expected[university])
+	}
+}
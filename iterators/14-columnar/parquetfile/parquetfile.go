@@ -0,0 +1,184 @@
+// Package parquetfile writes courses to Parquet files, and scans them back a
+// column at a time, with github.com/parquet-go/parquet-go.
+package parquetfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// Columns are the columns of a file, in the order of its schema
+var Columns = []string{"id", "name", "university"}
+
+// row is a course as it is laid out in a file. The IDs of the courses only
+// ever grow by one, so they are delta encoded, and the few distinct names and
+// universities are dictionary encoded, which stores each of them once per
+// chunk, and the index of every row's value in it.
+type row struct {
+	ID         int64  `parquet:"id,delta"`
+	Name       string `parquet:"name,dict"`
+	University string `parquet:"university,dict"`
+}
+
+// File is a Parquet file of courses opened for reading
+type File struct {
+	f *parquet.File
+}
+
+// Write writes the courses to w as a Parquet file. The writer buffers the
+// rows of a row group, rowGroupSize of them, and writes them out column by
+// column, so only a single row group is ever in memory.
+func Write(w io.Writer, courses iter.Seq[db.Course], rowGroupSize int) error {
+	pw := parquet.NewGenericWriter[row](w, parquet.MaxRowsPerRowGroup(int64(rowGroupSize)))
+
+	batch := make([]row, 0, 1024)
+	for course := range courses {
+		batch = append(batch, row{int64(course.ID), course.Name, course.University})
+		if len(batch) < cap(batch) {
+			continue
+		}
+
+		_, err := pw.Write(batch)
+		if err != nil {
+			return err
+		}
+		batch = batch[:0]
+	}
+
+	_, err := pw.Write(batch)
+	if err != nil {
+		return err
+	}
+
+	// Close flushes the last row group, and writes the footer, which
+	// locates the chunk of every column of every row group
+	return pw.Close()
+}
+
+// Open reads the footer of the file of the given size read from r
+func Open(r io.ReaderAt, size int64) (*File, error) {
+	f, err := parquet.OpenFile(r, size, parquet.SkipPageIndex(true), parquet.SkipBloomFilters(true))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, column := range Columns {
+		if _, ok := f.Schema().Lookup(column); !ok {
+			return nil, fmt.Errorf("file has no column %q, it is not a course file", column)
+		}
+	}
+
+	return &File{f: f}, nil
+}
+
+// Rows returns the number of rows in the file
+func (f *File) Rows() int {
+	return int(f.f.NumRows())
+}
+
+// Scan returns an iterator over the courses of the file, with only the given
+// columns filled in, or every column if none are given. Only the chunks of
+// those columns are read, a row group at a time, which is what makes reading
+// a few columns of a wide file cheap. Scanning stops at the first error,
+// which is yielded along with an empty course.
+func (f *File) Scan(columns ...string) iter.Seq2[db.Course, error] {
+	return func(yield func(db.Course, error) bool) {
+		if len(columns) == 0 {
+			columns = Columns
+		}
+
+		indices := make([]int, len(columns))
+		for i, column := range columns {
+			leaf, ok := f.f.Schema().Lookup(column)
+			if !ok {
+				yield(db.Course{}, fmt.Errorf("file has no column %q", column))
+				return
+			}
+
+			indices[i] = leaf.ColumnIndex
+		}
+
+		var group []db.Course
+
+		for g, rg := range f.f.RowGroups() {
+			group = append(group[:0], make([]db.Course, rg.NumRows())...)
+
+			for i, index := range indices {
+				err := decode(rg.ColumnChunks()[index], columns[i], group)
+				if err != nil {
+					yield(db.Course{}, fmt.Errorf("failed to read column %q of row group %d: %w", columns[i], g, err))
+					return
+				}
+			}
+
+			for _, course := range group {
+				if !yield(course, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// decode reads the pages of the chunk of a column into the courses of its
+// row group
+func decode(chunk parquet.ColumnChunk, column string, group []db.Course) error {
+	pages := chunk.Pages()
+	defer pages.Close()
+
+	values := make([]parquet.Value, 1024)
+	i := 0
+
+	for {
+		page, err := pages.ReadPage()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		r := page.Values()
+		for {
+			n, err := r.ReadValues(values)
+			for _, v := range values[:n] {
+				if i >= len(group) {
+					parquet.Release(page)
+					return errors.New("chunk has more values than its row group has rows")
+				}
+
+				switch column {
+				case "id":
+					group[i].ID = int(v.Int64())
+				case "name":
+					group[i].Name = string(v.ByteArray())
+				case "university":
+					group[i].University = string(v.ByteArray())
+				}
+				i++
+			}
+
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				parquet.Release(page)
+				return err
+			}
+		}
+
+		parquet.Release(page)
+	}
+
+	if i != len(group) {
+		return fmt.Errorf("chunk has %d values for %d rows", i, len(group))
+	}
+
+	return nil
+}
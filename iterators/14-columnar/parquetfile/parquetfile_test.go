@@ -0,0 +1,124 @@
+package parquetfile
+
+import (
+	"bytes"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+func write(t *testing.T, courses []db.Course) *File {
+	t.Helper()
+
+	var buf bytes.Buffer
+	err := Write(&buf, slices.Values(courses), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := Open(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return f
+}
+
+func scan(t *testing.T, f *File, columns ...string) []db.Course {
+	t.Helper()
+
+	var courses []db.Course
+	for course, err := range f.Scan(columns...) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		courses = append(courses, course)
+	}
+
+	return courses
+}
+
+func TestRoundTrip(t *testing.T) {
+	// 250 courses make two full row groups and a partial one
+	expected := slices.Collect(db.GenerateCourses(250))
+
+	f := write(t, expected)
+	if f.Rows() != len(expected) {
+		t.Fatalf("expected %d rows, got %d", len(expected), f.Rows())
+	}
+
+	courses := scan(t, f)
+	if !slices.Equal(courses, expected) {
+		t.Fatalf("expected %d courses back, got %d", len(expected), len(courses))
+	}
+}
+
+func TestProjection(t *testing.T) {
+	expected := slices.Collect(db.GenerateCourses(150))
+
+	courses := scan(t, write(t, expected), "university")
+	for i, course := range courses {
+		if course != (db.Course{University: expected[i].University}) {
+			t.Fatalf("expected only the university of course %d, got %+v", i, course)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	var err error
+	for _, err = range write(t, nil).Scan("credits") {
+	}
+
+	if err == nil || err.Error() != `file has no column "credits"` {
+		t.Fatalf("expected an unknown column error, got %v", err)
+	}
+
+	data := []byte("PAR1 this is not a course file PAR1")
+	_, err = Open(bytes.NewReader(data), int64(len(data)))
+	if err == nil {
+		t.Fatal("expected an error for a file without a footer")
+	}
+
+	// A Parquet file of something other than courses
+	type grade struct {
+		Student string `parquet:"student"`
+		Score   int64  `parquet:"score"`
+	}
+
+	var buf bytes.Buffer
+	err = parquet.Write(&buf, []grade{{"ada", 90}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Open(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err == nil || !strings.Contains(err.Error(), "not a course file") {
+		t.Fatalf("expected an error for a file of grades, got %v", err)
+	}
+}
+
+func TestScanConformance(t *testing.T) {
+	// 5 courses with a row group size of 2 cross two row group boundaries
+	var buf bytes.Buffer
+	err := Write(&buf, db.GenerateCourses(5), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := Open(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newSeq := func() iter.Seq2[db.Course, error] { return f.Scan() }
+
+	seqtest.AssertStopsEarly2(t, 5, newSeq)
+	seqtest.AssertNoYieldAfterFalse2(t, 5, newSeq)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/queueseq"
+)
+
+// workers is the number of consumers which share the queue in the second
+// part of the lesson
+const workers = 3
+
+// crashOnce consumes the queue until it gets to the message with the given
+// ID, and breaks out of the loop without handling it, like a worker which
+// crashes in the middle of a message
+func crashOnce(ctx context.Context, q *queueseq.MemQueue, crashAt uint64) error {
+	for msg, err := range queueseq.Consume(ctx, q) {
+		if err != nil {
+			return err
+		}
+
+		if msg.ID == crashAt {
+			fmt.Printf("worker 1: crashed on message %d\n", msg.ID)
+			break
+		}
+
+		fmt.Printf("worker 1: %s, attempt %d\n", msg.Body, msg.Attempt)
+	}
+
+	return nil
+}
+
+// drain consumes the queue until it has handled n messages, and then
+// cancels its context, which stops the iteration once the last message is
+// acknowledged
+func drain(ctx context.Context, q *queueseq.MemQueue, n int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	handled := 0
+	for msg, err := range queueseq.Consume(ctx, q) {
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("worker 2: %s, attempt %d\n", msg.Body, msg.Attempt)
+
+		handled++
+		if handled == n {
+			cancel()
+		}
+	}
+
+	return nil
+}
+
+func main() {
+	// -count is the number of messages the workers share
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 1000})
+
+	ctx, cancel := cfg.Context()
+	defer cancel()
+
+	q := queueseq.NewMemQueue()
+	for i := range 6 {
+		q.Publish(fmt.Appendf(nil, "enroll student %d in Chem-1", i+1))
+	}
+
+	// The message the first worker crashed on goes back to the front of the
+	// queue, and the second worker gets it on its second attempt
+	err := crashOnce(ctx, q, 4)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	ready, inFlight := q.Len()
+	fmt.Printf("queue: %d ready, %d in flight\n", ready, inFlight)
+
+	err = drain(ctx, q, 3)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	ready, inFlight = q.Len()
+	fmt.Printf("queue: %d ready, %d in flight\n\n", ready, inFlight)
+
+	// The workers share the queue while it is being published to. Every
+	// tenth message fails on its first attempt, and the worker which got it
+	// starts consuming again, like a process which is restarted.
+	ctx, cancel = context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg          sync.WaitGroup
+		handled     atomic.Int64
+		redelivered atomic.Int64
+	)
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for ctx.Err() == nil {
+				for msg, err := range queueseq.Consume(ctx, q) {
+					if err != nil {
+						fmt.Println(err)
+						cancel()
+						return
+					}
+
+					if msg.Attempt > 1 {
+						redelivered.Add(1)
+					}
+
+					if msg.ID%10 == 0 && msg.Attempt == 1 {
+						break
+					}
+
+					if handled.Add(1) == int64(cfg.Count) {
+						cancel()
+					}
+				}
+			}
+		}()
+	}
+
+	for i := range cfg.Count {
+		q.Publish(fmt.Appendf(nil, "enroll student %d in Physics-1", i+1))
+	}
+
+	wg.Wait()
+
+	ready, inFlight = q.Len()
+	fmt.Printf("%d workers handled %d messages, %d of them redelivered\n", workers, handled.Load(), redelivered.Load())
+	fmt.Printf("queue: %d ready, %d in flight\n", ready, inFlight)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/redisseq"
+)
+
+// pageSize is the COUNT hint of every scan
+const pageSize = 1000
+
+// commandCounter counts the commands sent to the server, to show how many
+// pages every scan fetched
+type commandCounter struct {
+	counts map[string]int
+}
+
+func (c *commandCounter) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (c *commandCounter) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		c.counts[cmd.Name()]++
+		return next(ctx, cmd)
+	}
+}
+
+func (c *commandCounter) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+// seed stores every course as a hash at course:<id>, and adds its ID to the
+// set of its university at university:<name>
+func seed(ctx context.Context, client *redis.Client, numCourses int) error {
+	_, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for course := range db.GenerateCourses(numCourses) {
+			id := strconv.Itoa(course.ID)
+
+			pipe.HSet(ctx, "course:"+id, "name", course.Name, "university", course.University)
+			pipe.SAdd(ctx, "university:"+course.University, id)
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+func run(ctx context.Context, cfg lessoncfg.Config) error {
+	// miniredis is a Redis server which runs inside the process, so the
+	// lesson needs no Redis of its own
+	mr, err := miniredis.Run()
+	if err != nil {
+		return fmt.Errorf("failed to start redis: %w", err)
+	}
+	defer mr.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	err = seed(ctx, client, cfg.Count)
+	if err != nil {
+		return fmt.Errorf("failed to seed redis: %w", err)
+	}
+
+	counter := &commandCounter{counts: make(map[string]int)}
+	client.AddHook(counter)
+
+	// The keys of the students are skipped by the pattern
+	err = client.Set(ctx, "student:1", "Ada", 0).Err()
+	if err != nil {
+		return err
+	}
+
+	// miniredis returns every matching key from a single SCAN, while Redis
+	// returns about as many keys as the COUNT hint asks for, with a cursor
+	// for the next page
+	n := 0
+	for _, err := range redisseq.Scan(ctx, client, "course:*", pageSize) {
+		if err != nil {
+			return err
+		}
+
+		n++
+	}
+	fmt.Printf("scanned %d course keys with %d SCAN commands\n\n", n, counter.counts["scan"])
+
+	// The members of a set are paged through the same way by miniredis and
+	// Redis
+	for _, university := range db.Universities() {
+		clear(counter.counts)
+
+		n = 0
+		for _, err := range redisseq.SScan(ctx, client, "university:"+university, "", pageSize) {
+			if err != nil {
+				return err
+			}
+
+			n++
+		}
+
+		fmt.Printf("%-5s %d courses with %d SSCAN commands\n", university, n, counter.counts["sscan"])
+	}
+
+	// Breaking out of the loop stops the scan before the next page
+	clear(counter.counts)
+
+	n = 0
+	for _, err := range redisseq.SScan(ctx, client, "university:UCSF", "", pageSize) {
+		if err != nil {
+			return err
+		}
+
+		n++
+		if n == 5 {
+			break
+		}
+	}
+	fmt.Printf("UCSF  stopped after %d courses with %d SSCAN commands\n\n", n, counter.counts["sscan"])
+
+	for field, err := range redisseq.HScan(ctx, client, "course:42", "", 0) {
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("course:42 %s = %s\n", field.Name, field.Value)
+	}
+
+	return nil
+}
+
+func main() {
+	// -count is the number of courses stored in Redis
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 10000})
+	db.Seed(cfg.Seed)
+
+	ctx, cancel := cfg.Context()
+	defer cancel()
+
+	err := run(ctx, cfg)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
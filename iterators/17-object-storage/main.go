@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"runtime"
+	"strconv"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/s3seq"
+	"github.com/manedurphy/golang-university/iterators/s3seq/s3test"
+)
+
+const mib = 1 << 20
+
+const (
+	bucket = "university"
+
+	// pageSize is the number of keys requested per page
+	pageSize = 100
+
+	// lectures are large objects which are downloaded a chunk at a time
+	lectures    = 4
+	lectureSize = 32 * mib
+	chunkSize   = mib
+)
+
+// seed stores a syllabus per course under syllabi/<university>/, and a few
+// lectures under lectures/
+func seed(srv *s3test.Server, numCourses int, r *rand.Rand) {
+	for course := range db.GenerateCourses(numCourses) {
+		key := "syllabi/" + course.University + "/" + strconv.Itoa(course.ID) + ".txt"
+		srv.Put(bucket, key, fmt.Appendf(nil, "%s at %s", course.Name, course.University))
+	}
+
+	for i := range lectures {
+		data := make([]byte, lectureSize)
+		for j := 0; j < len(data); j += 8 {
+			n := r.Uint64()
+			for k := range 8 {
+				data[j+k] = byte(n >> (8 * k))
+			}
+		}
+
+		srv.Put(bucket, fmt.Sprintf("lectures/lecture-%02d.mp4", i+1), data)
+	}
+}
+
+// hash downloads an object a chunk at a time, and returns its SHA-256 along
+// with how far the heap grew while it was being downloaded
+func hash(ctx context.Context, client *s3seq.Client, key string) (string, uint64, error) {
+	var mem runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&mem)
+	baseline, peak := mem.HeapAlloc, mem.HeapAlloc
+
+	h := sha256.New()
+	n := 0
+	for chunk, err := range client.Chunks(ctx, bucket, key, chunkSize) {
+		if err != nil {
+			return "", 0, err
+		}
+
+		h.Write(chunk)
+
+		n++
+		if n%4 == 0 {
+			runtime.ReadMemStats(&mem)
+			peak = max(peak, mem.HeapAlloc)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), peak - baseline, nil
+}
+
+func run(ctx context.Context, cfg lessoncfg.Config) error {
+	// The server keeps its objects in memory and runs inside the process, so
+	// the lesson needs no MinIO of its own. Pointing the client at a MinIO or
+	// S3 endpoint instead changes nothing else.
+	srv := s3test.NewServer()
+	defer srv.Close()
+
+	seed(srv, cfg.Count, cfg.Rand())
+
+	mc, err := minio.New(srv.Endpoint(), &minio.Options{
+		Creds:        credentials.NewStaticV4("university", "university", ""),
+		Region:       "us-east-1",
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		return err
+	}
+
+	client := s3seq.New(mc, pageSize)
+
+	for _, university := range db.Universities() {
+		before, _ := srv.Requests()
+
+		var (
+			n    int
+			size int64
+		)
+
+		for obj, err := range client.ListObjects(ctx, bucket, "syllabi/"+university+"/") {
+			if err != nil {
+				return err
+			}
+
+			n++
+			size += obj.Size
+		}
+
+		lists, _ := srv.Requests()
+		fmt.Printf("%-5s %d syllabi, %d bytes, %d pages\n", university, n, size, lists-before)
+	}
+
+	// Breaking out of the loop stops the listing before the next page
+	before, _ := srv.Requests()
+
+	n := 0
+	for _, err := range client.ListObjects(ctx, bucket, "syllabi/") {
+		if err != nil {
+			return err
+		}
+
+		n++
+		if n == 150 {
+			break
+		}
+	}
+
+	lists, _ := srv.Requests()
+	fmt.Printf("stopped after %d syllabi, %d pages\n\n", n, lists-before)
+
+	for obj, err := range client.ListObjects(ctx, bucket, "lectures/") {
+		if err != nil {
+			return err
+		}
+
+		sum, growth, err := hash(ctx, client, obj.Key)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s %d MiB, sha256 %s..., heap grew %.2f MiB\n", obj.Key, obj.Size/mib, sum[:16], float64(growth)/mib)
+	}
+
+	return nil
+}
+
+func main() {
+	// -count is the number of courses with a syllabus in the bucket
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 1000})
+	db.Seed(cfg.Seed)
+
+	ctx, cancel := cfg.Context()
+	defer cancel()
+
+	err := run(ctx, cfg)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/tailseq"
+)
+
+// interval is how often the log is polled, and pause is how long the server
+// waits between writes, which gives the iterator a few polls to catch up
+const (
+	interval = 10 * time.Millisecond
+	pause    = 5 * interval
+)
+
+// serve writes count requests to the log at path like a server would,
+// rotating the log by renaming it a third of the way through and truncating
+// it two thirds of the way through, and writes a last line when it shuts
+// down
+func serve(path string, count int) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		f.Close()
+	}()
+
+	logf := func(format string, args ...any) error {
+		time.Sleep(pause)
+		_, err := fmt.Fprintf(f, format+"\n", args...)
+		return err
+	}
+
+	for i := range count {
+		switch i {
+		case count / 3:
+			err = logf("rotating log")
+			if err != nil {
+				return err
+			}
+
+			err = os.Rename(path, path+".1")
+			if err != nil {
+				return err
+			}
+			f.Close()
+
+			// A log which is not opened in append mode keeps writing at its
+			// old offset once it is truncated, which leaves a gap of zeros
+			f, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+			if err != nil {
+				return err
+			}
+		case 2 * count / 3:
+			err = logf("truncating log")
+			if err != nil {
+				return err
+			}
+
+			// Let the iterator read the line before the log is emptied
+			time.Sleep(pause)
+			err = f.Truncate(0)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = logf("GET /courses/%d 200", i+1)
+		if err != nil {
+			return err
+		}
+	}
+
+	return logf("shutting down")
+}
+
+func main() {
+	// -count is the number of requests the server logs
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 9})
+
+	ctx, cancel := cfg.Context()
+	defer cancel()
+
+	dir, err := os.MkdirTemp("", "tail")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "server.log")
+	err = os.WriteFile(path, []byte("server started\n"), 0o644)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// The server waits before its first write, so the iterator has opened the
+	// log by then and skipped the line which was already in it
+	go func() {
+		err := serve(path, cfg.Count)
+		if err != nil {
+			fmt.Println(err)
+			cancel()
+		}
+	}()
+
+	// The loop never ends on its own: it stops when the context is canceled,
+	// here once the server says it is shutting down
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	lines := 0
+	for line, err := range tailseq.FollowEvery(ctx, path, interval) {
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		lines++
+		fmt.Printf("%2d: %s\n", lines, line)
+
+		if strings.HasPrefix(line, "shutting down") {
+			stop()
+		}
+	}
+
+	fmt.Printf("followed %d lines across a rotation and a truncation\n", lines)
+}
@@ -0,0 +1,233 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// Courses returns an iterator over the courses of a catalog page read from r.
+// Each course is an article with the course class, its ID in a data-id
+// attribute, its name in a heading, and its university in an element with
+// the university class:
+//
+//	<article class="course" data-id="42">
+//	  <h2><a href="/courses/42">Physics-1</a></h2>
+//	  <p class="university">UCB</p>
+//	</article>
+//
+// The page is tokenized as it is read, so a course is yielded as soon as its
+// closing tag has been read, and the rest of the page is never read if the
+// loop breaks. The iterator yields an error and stops if the page cannot be
+// read or a course is missing one of its fields.
+func Courses(r io.Reader) iter.Seq2[db.Course, error] {
+	return func(yield func(db.Course, error) bool) {
+		z := html.NewTokenizer(r)
+
+		var (
+			p scraper
+			n int
+		)
+
+		for {
+			tt := z.Next()
+			switch tt {
+			case html.ErrorToken:
+				err := z.Err()
+				if !errors.Is(err, io.EOF) {
+					yield(db.Course{}, fmt.Errorf("failed to read course %d: %w", n+1, err))
+				}
+				return
+			case html.StartTagToken, html.SelfClosingTagToken:
+				err := p.start(z, tt == html.SelfClosingTagToken)
+				if err != nil {
+					yield(db.Course{}, fmt.Errorf("failed to parse course %d: %w", n+1, err))
+					return
+				}
+			case html.EndTagToken:
+				course, done, err := p.end(z)
+				if err != nil {
+					yield(db.Course{}, fmt.Errorf("failed to parse course %d: %w", n+1, err))
+					return
+				}
+				if !done {
+					continue
+				}
+
+				n++
+				if !yield(course, nil) {
+					return
+				}
+			case html.TextToken:
+				p.text(z)
+			}
+		}
+	}
+}
+
+// field is the field of a course whose text is being read
+type field int
+
+const (
+	noField field = iota
+	nameField
+	universityField
+)
+
+// scraper holds the state of the course which is being read. Only the
+// elements inside a course are tracked, so the rest of the page costs no
+// more than tokenizing it.
+type scraper struct {
+	inCourse bool
+	course   db.Course
+	hasID    bool
+	// field is the field whose text is being read, and depth is the number
+	// of elements it is nested in, so that the field ends with the element
+	// which started it rather than with an element inside it
+	field field
+	depth int
+	buf   strings.Builder
+}
+
+func (p *scraper) start(z *html.Tokenizer, selfClosing bool) error {
+	name, hasAttr := z.TagName()
+	a := atom.Lookup(name)
+
+	if !p.inCourse {
+		if a != atom.Article {
+			return nil
+		}
+
+		attrs := attributes(z, hasAttr)
+		if !hasClass(attrs["class"], "course") {
+			return nil
+		}
+
+		p.inCourse, p.course, p.hasID = true, db.Course{}, false
+		if id, ok := attrs["data-id"]; ok {
+			n, err := strconv.Atoi(id)
+			if err != nil {
+				return fmt.Errorf("invalid ID %q", id)
+			}
+
+			p.course.ID, p.hasID = n, true
+		}
+
+		return nil
+	}
+
+	if p.field != noField {
+		if !selfClosing && !isVoid(a) {
+			p.depth++
+		}
+		return nil
+	}
+
+	switch {
+	case a == atom.H2:
+		p.field = nameField
+	case hasClass(attributes(z, hasAttr)["class"], "university"):
+		p.field = universityField
+	default:
+		return nil
+	}
+
+	p.depth = 0
+	p.buf.Reset()
+	if selfClosing {
+		p.endField()
+	}
+
+	return nil
+}
+
+func (p *scraper) end(z *html.Tokenizer) (db.Course, bool, error) {
+	if !p.inCourse {
+		return db.Course{}, false, nil
+	}
+
+	if p.field != noField {
+		if p.depth > 0 {
+			p.depth--
+			return db.Course{}, false, nil
+		}
+
+		p.endField()
+		return db.Course{}, false, nil
+	}
+
+	name, _ := z.TagName()
+	if atom.Lookup(name) != atom.Article {
+		return db.Course{}, false, nil
+	}
+
+	p.inCourse = false
+	switch {
+	case !p.hasID:
+		return db.Course{}, false, errors.New("missing ID")
+	case p.course.Name == "":
+		return db.Course{}, false, fmt.Errorf("course %d has no name", p.course.ID)
+	case p.course.University == "":
+		return db.Course{}, false, fmt.Errorf("course %d has no university", p.course.ID)
+	}
+
+	return p.course, true, nil
+}
+
+func (p *scraper) text(z *html.Tokenizer) {
+	if p.field != noField {
+		// Text unescapes entities such as &amp;
+		p.buf.Write(z.Text())
+	}
+}
+
+// endField stores the text which was read for the current field
+func (p *scraper) endField() {
+	text := strings.Join(strings.Fields(p.buf.String()), " ")
+
+	switch p.field {
+	case nameField:
+		p.course.Name = text
+	case universityField:
+		p.course.University = text
+	}
+
+	p.field = noField
+}
+
+// attributes returns the attributes of the current tag. The tokenizer only
+// hands them out once, so this must be called at most once per tag.
+func attributes(z *html.Tokenizer, hasAttr bool) map[string]string {
+	attrs := make(map[string]string)
+	for hasAttr {
+		var key, value []byte
+		key, value, hasAttr = z.TagAttr()
+		attrs[string(key)] = string(value)
+	}
+
+	return attrs
+}
+
+func hasClass(classes, class string) bool {
+	return slices.Contains(strings.Fields(classes), class)
+}
+
+// isVoid reports whether an element never has a closing tag, such as <br>
+func isVoid(a atom.Atom) bool {
+	switch a {
+	case atom.Area, atom.Base, atom.Br, atom.Col, atom.Embed, atom.Hr, atom.Img,
+		atom.Input, atom.Link, atom.Meta, atom.Source, atom.Track, atom.Wbr:
+		return true
+	}
+
+	return false
+}
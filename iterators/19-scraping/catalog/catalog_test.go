@@ -0,0 +1,142 @@
+package catalog
+
+import (
+	"errors"
+	"io"
+	"iter"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+func TestCourses(t *testing.T) {
+	f, err := os.Open("testdata/catalog.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var courses []db.Course
+	for course, err := range Courses(f) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		courses = append(courses, course)
+	}
+
+	expected := []db.Course{
+		{ID: 1, Name: "Physics-1", University: "UCB"},
+		{ID: 2, Name: "Chemistry & Biology-2", University: "SJSU"},
+		{ID: 3, Name: "Math-3", University: "UCSF"},
+	}
+
+	if !slices.Equal(courses, expected) {
+		t.Fatalf("expected %v, got %v", expected, courses)
+	}
+}
+
+// countingReader counts the bytes read from it
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestCoursesBreak(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("<main>")
+	for range 10000 {
+		b.WriteString(`<article class="course" data-id="1"><h2>Physics-1</h2><p class="university">UCB</p></article>`)
+	}
+	b.WriteString("</main>")
+
+	r := &countingReader{r: strings.NewReader(b.String())}
+	for _, err := range Courses(r) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		break
+	}
+
+	if r.n >= b.Len()/10 {
+		t.Fatalf("expected to read a small part of %d bytes, read %d", b.Len(), r.n)
+	}
+}
+
+func TestCoursesInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		err  string
+	}{
+		{"missing ID", `<article class="course"><h2>Physics-1</h2><p class="university">UCB</p></article>`, "missing ID"},
+		{"invalid ID", `<article class="course" data-id="one"></article>`, `invalid ID "one"`},
+		{"missing name", `<article class="course" data-id="1"><p class="university">UCB</p></article>`, "course 1 has no name"},
+		{"missing university", `<article class="course" data-id="1"><h2>Physics-1</h2></article>`, "course 1 has no university"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs []error
+			for _, err := range Courses(strings.NewReader(tt.html)) {
+				errs = append(errs, err)
+			}
+
+			if len(errs) != 1 || errs[0] == nil {
+				t.Fatalf("expected a single error, got %v", errs)
+			}
+			if !strings.Contains(errs[0].Error(), tt.err) {
+				t.Fatalf("expected an error containing %q, got %v", tt.err, errs[0])
+			}
+		})
+	}
+}
+
+func TestCoursesReadError(t *testing.T) {
+	errBroken := errors.New("connection reset")
+	r := io.MultiReader(
+		strings.NewReader(`<article class="course" data-id="1"><h2>Physics-1</h2><p class="university">UCB</p></article>`),
+		iotest.ErrReader(errBroken),
+	)
+
+	var (
+		courses int
+		errs    []error
+	)
+	for _, err := range Courses(r) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		courses++
+	}
+
+	if courses != 1 {
+		t.Fatalf("expected 1 course before the error, got %d", courses)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], errBroken) {
+		t.Fatalf("expected %v, got %v", errBroken, errs)
+	}
+}
+
+func TestCoursesConformance(t *testing.T) {
+	data, err := os.ReadFile("testdata/catalog.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newSeq := func() iter.Seq2[db.Course, error] { return Courses(strings.NewReader(string(data))) }
+
+	seqtest.AssertStopsEarly2(t, 3, newSeq)
+	seqtest.AssertNoYieldAfterFalse2(t, 3, newSeq)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/19-scraping/catalog"
+)
+
+const mib = 1 << 20
+
+//go:embed page.html
+var pageHTML string
+
+var page = template.Must(template.New("page").Parse(pageHTML))
+
+// serveCatalog renders the catalog page a course at a time, so the page is
+// never held in memory on the server either
+func serveCatalog(numCourses int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+		err := page.ExecuteTemplate(w, "header", "Spring")
+		if err != nil {
+			return
+		}
+
+		for course := range db.GenerateCourses(numCourses) {
+			// An error means the client went away, so there is no one left
+			// to render the page for
+			err = page.ExecuteTemplate(w, "course", course)
+			if err != nil {
+				return
+			}
+		}
+
+		page.ExecuteTemplate(w, "footer", nil)
+	}
+}
+
+// countingReader counts the bytes read from it
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+// scrape fetches the catalog page and calls fn with every course on it until
+// fn returns false. It returns the number of bytes of the page it read.
+func scrape(ctx context.Context, url string, fn func(db.Course) bool) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch catalog: %w", err)
+	}
+	// Closing the body before it has been read to the end drops the
+	// connection, which stops the server from rendering the rest
+	defer resp.Body.Close()
+
+	body := &countingReader{r: resp.Body}
+	for course, err := range catalog.Courses(body) {
+		if err != nil {
+			return body.n, err
+		}
+
+		if !fn(course) {
+			break
+		}
+	}
+
+	return body.n, nil
+}
+
+func main() {
+	// -count is the number of courses in the catalog
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 100000})
+	db.Seed(cfg.Seed)
+
+	ctx, cancel := cfg.Context()
+	defer cancel()
+
+	srv := httptest.NewServer(serveCatalog(cfg.Count))
+	defer srv.Close()
+
+	// The whole catalog is scraped, with each course counted as soon as it
+	// has been parsed
+	counts := make(map[string]int)
+	now := time.Now()
+	size, err := scrape(ctx, srv.URL, func(course db.Course) bool {
+		counts[course.University]++
+		return true
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Printf("scraped %d courses from %.2f MiB of HTML in %d ms\n", cfg.Count, float64(size)/mib, time.Since(now).Milliseconds())
+	for _, university := range slices.Sorted(maps.Keys(counts)) {
+		fmt.Printf("%-5s %d courses\n", university, counts[university])
+	}
+	fmt.Println()
+
+	// Looking for a single course stops reading the page as soon as it is
+	// found
+	for _, want := range []db.Course{
+		{Name: "Physics-3", University: "UCSF"},
+		{Name: "Calculus-1", University: "SDSU"},
+	} {
+		var found db.Course
+		read, err := scrape(ctx, srv.URL, func(course db.Course) bool {
+			if course.Name == want.Name && course.University == want.University {
+				found = course
+				return false
+			}
+			return true
+		})
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		fmt.Printf("found %s at %s with ID %d after reading %.1f KiB of %.2f MiB\n",
+			found.Name, found.University, found.ID, float64(read)/1024, float64(size)/mib)
+	}
+}
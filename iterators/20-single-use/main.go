@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"iter"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/itertools"
+	"github.com/manedurphy/golang-university/iterators/jsonseq"
+)
+
+// preview is the number of courses printed before the rest are processed
+const preview = 3
+
+// export returns an iterator over the generated courses, decoded from JSON
+// Lines as they are read, like the body of a response. It can only be
+// ranged over once, because the reader is not rewound between loops.
+func export(numCourses int) iter.Seq2[db.Course, error] {
+	var b bytes.Buffer
+	jsonseq.WriteLines(&b, db.GenerateCourses(numCourses))
+
+	return jsonseq.Lines[db.Course](&b)
+}
+
+// countThenProcess counts the courses before processing them, which ranges
+// over courses twice
+func countThenProcess(courses iter.Seq2[db.Course, error]) (counted, processed int, err error) {
+	for range courses {
+		counted++
+	}
+
+	for _, err := range courses {
+		if err != nil {
+			return counted, processed, err
+		}
+		processed++
+	}
+
+	return counted, processed, nil
+}
+
+// previewThenProcess prints the first few courses, and processes the rest in
+// a second loop, which is expected to pick up where the first one broke
+func previewThenProcess(courses iter.Seq2[db.Course, error]) (processed int, err error) {
+	n := 0
+	for course, err := range courses {
+		if err != nil {
+			return 0, err
+		}
+
+		fmt.Printf("  preview: %d %s at %s\n", course.ID, course.Name, course.University)
+
+		n++
+		if n == preview {
+			break
+		}
+	}
+
+	for _, err := range courses {
+		if err != nil {
+			return processed, err
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+// previewWithPull does what previewThenProcess meant to do. A single
+// iteration is pulled from, so the second loop really does continue where
+// the first one stopped.
+func previewWithPull(courses iter.Seq2[db.Course, error]) (processed int, err error) {
+	next, stop := iter.Pull2(courses)
+	defer stop()
+
+	for range preview {
+		course, err, ok := next()
+		if !ok {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		fmt.Printf("  preview: %d %s at %s\n", course.ID, course.Name, course.University)
+	}
+
+	for {
+		_, err, ok := next()
+		if !ok {
+			return processed, nil
+		}
+		if err != nil {
+			return processed, err
+		}
+		processed++
+	}
+}
+
+// catch runs fn, and prints what it panicked with
+func catch(fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Printf("  panic: %v\n", r)
+		}
+	}()
+
+	fn()
+}
+
+func main() {
+	// -count is the number of courses in the export
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 1000})
+	db.Seed(cfg.Seed)
+
+	// The second loop finds the reader at its end, and quietly processes
+	// nothing
+	fmt.Println("count, then process:")
+	counted, processed, err := countThenProcess(export(cfg.Count))
+	fmt.Printf("  counted %d courses, processed %d, error: %v\n\n", counted, processed, err)
+
+	// The first loop's scanner had read ahead of the third course, so the
+	// second loop starts wherever that left the reader, which is usually in
+	// the middle of a line
+	fmt.Println("preview, then process the rest:")
+	processed, err = previewThenProcess(export(cfg.Count))
+	fmt.Printf("  processed %d of the other %d courses, error: %v\n\n", processed, cfg.Count-preview, err)
+
+	// Guarding the iterators turns both bugs into a panic which points at
+	// the two loops
+	fmt.Println("guarded with itertools.Once2:")
+	catch(func() { countThenProcess(itertools.Once2(export(cfg.Count))) })
+	catch(func() { previewThenProcess(itertools.Once2(export(cfg.Count))) })
+	fmt.Println()
+
+	fmt.Println("preview with iter.Pull2, then process the rest:")
+	processed, err = previewWithPull(itertools.Once2(export(cfg.Count)))
+	fmt.Printf("  processed %d of the other %d courses, error: %v\n", processed, cfg.Count-preview, err)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/itertools"
+)
+
+const (
+	numPages = 5
+	pageSize = 100
+
+	// latency is how long the feed takes for a page, except for slowPage,
+	// which takes stall
+	latency  = 20 * time.Millisecond
+	slowPage = 3
+	stall    = 250 * time.Millisecond
+
+	// timeout is how long a consumer waits for a page at a time
+	timeout = 100 * time.Millisecond
+)
+
+// feed stands in for a remote feed of courses, which hands them out a page
+// at a time. One page is much slower than the others. The returned channel
+// is closed once the feed's cleanup has run.
+func feed(numCourses int) (iter.Seq[[]db.Course], <-chan struct{}) {
+	cleaned := make(chan struct{})
+
+	seq := func(yield func([]db.Course) bool) {
+		page := 0
+		defer func() {
+			fmt.Printf("  feed: cleaned up after page %d\n", page)
+			close(cleaned)
+		}()
+
+		var courses []db.Course
+		for course := range db.GenerateCourses(numCourses) {
+			courses = append(courses, course)
+			if len(courses) < pageSize {
+				continue
+			}
+
+			page++
+			if page == slowPage {
+				time.Sleep(stall)
+			} else {
+				time.Sleep(latency)
+			}
+
+			if !yield(courses) {
+				return
+			}
+			courses = nil
+		}
+	}
+
+	return seq, cleaned
+}
+
+// consume reads every page of the feed, and waits for a late page up to
+// maxWaits times before it gives up
+func consume(maxWaits int) {
+	pages, cleaned := feed(numPages * pageSize)
+
+	next, stop := itertools.PullWithTimeout(pages, timeout)
+
+	page, waits := 1, 0
+	for {
+		courses, ok, err := next()
+		if errors.Is(err, itertools.ErrTimeout) {
+			waits++
+			fmt.Printf("  page %d is late: %v\n", page, err)
+
+			if waits < maxWaits {
+				continue
+			}
+
+			fmt.Printf("  giving up on page %d\n", page)
+			break
+		}
+		if !ok {
+			break
+		}
+
+		fmt.Printf("  page %d: %d courses\n", page, len(courses))
+		page, waits = page+1, 0
+	}
+
+	// Stopping does not wait for the page the feed is stuck on, so the
+	// consumer can move on right away, while the feed cleans up once the
+	// page is done
+	stop()
+	fmt.Println("  consumer: stopped")
+	<-cleaned
+}
+
+// consumeBefore reads the pages of the feed until the deadline, which bounds
+// how long the feed as a whole is waited for
+func consumeBefore(ctx context.Context, d time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	pages, cleaned := feed(numPages * pageSize)
+
+	next, stop := itertools.PullContext(ctx, pages)
+
+	for page := 1; ; page++ {
+		courses, ok, err := next()
+		if err != nil {
+			fmt.Printf("  page %d: %v\n", page, err)
+			break
+		}
+		if !ok {
+			break
+		}
+
+		fmt.Printf("  page %d: %d courses\n", page, len(courses))
+	}
+
+	stop()
+	fmt.Println("  consumer: stopped")
+	<-cleaned
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{})
+	db.Seed(cfg.Seed)
+
+	ctx, cancel := cfg.Context()
+	defer cancel()
+
+	fmt.Printf("a patient consumer waits for page %d up to 3 times:\n", slowPage)
+	consume(3)
+
+	fmt.Printf("\nan impatient consumer waits for page %d once:\n", slowPage)
+	consume(1)
+
+	fmt.Println("\na consumer with a deadline of 150ms for the whole feed:")
+	consumeBefore(ctx, 150*time.Millisecond)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"runtime"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+const (
+	kib = 1 << 10
+	mib = 1 << 20
+
+	// bufSize is the size of the buffer every iterator holds, like one
+	// which reads its courses from a file. The buffer does not escape, so
+	// it is allocated on the stack of the iterator.
+	bufSize = 16 * kib
+)
+
+// courses returns an iterator over a few generated courses, which holds a
+// buffer for as long as it runs
+func courses() iter.Seq[db.Course] {
+	return func(yield func(db.Course) bool) {
+		buf := make([]byte, bufSize)
+
+		for course := range db.GenerateCourses(10) {
+			buf[course.ID]++
+			if !yield(course) {
+				return
+			}
+		}
+	}
+}
+
+// usage is a snapshot of what the program is using
+type usage struct {
+	goroutines int
+	stack      uint64
+	heap       uint64
+}
+
+func measure() usage {
+	runtime.GC()
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	return usage{runtime.NumGoroutine(), mem.StackInuse, mem.HeapInuse}
+}
+
+// print prints how much more the program uses than it did at base, in total
+// and per iterator
+func (u usage) print(label string, base usage, n int) {
+	stack := float64(u.stack) - float64(base.stack)
+	heap := float64(u.heap) - float64(base.heap)
+
+	fmt.Printf("%-32s %6d goroutines, stacks %+7.2f MiB (%+5.1f KiB each), heap %+7.2f MiB (%+5.1f KiB each)\n",
+		label, u.goroutines-base.goroutines, stack/mib, stack/kib/float64(n), heap/mib, heap/kib/float64(n))
+}
+
+// firstCourse returns the first course of seq, but forgets to stop the pull
+// iterator it reads it with
+func firstCourse(seq iter.Seq[db.Course]) db.Course {
+	next, _ := iter.Pull(seq)
+
+	course, _ := next()
+	return course
+}
+
+// firstCourseStopped is firstCourse with the call to stop it forgot
+func firstCourseStopped(seq iter.Seq[db.Course]) db.Course {
+	next, stop := iter.Pull(seq)
+	defer stop()
+
+	course, _ := next()
+	return course
+}
+
+// firstCourseRange reads the first course with a loop, which needs no
+// goroutine and nothing to stop
+func firstCourseRange(seq iter.Seq[db.Course]) db.Course {
+	for course := range seq {
+		return course
+	}
+
+	return db.Course{}
+}
+
+func main() {
+	// -count is the number of iterators
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 10000})
+	db.Seed(cfg.Seed)
+
+	n := cfg.Count
+	base := measure()
+
+	// Every pull iterator is a goroutine from the moment iter.Pull returns,
+	// even before its first value is pulled
+	type pull struct {
+		next func() (db.Course, bool)
+		stop func()
+	}
+
+	pulls := make([]pull, n)
+	for i := range pulls {
+		pulls[i].next, pulls[i].stop = iter.Pull(courses())
+	}
+	measure().print("pulled", base, n)
+
+	// Pulling a value runs the iterator up to its first yield, which grows
+	// its stack to make room for the buffer
+	for _, p := range pulls {
+		p.next()
+	}
+	measure().print("after the first value", base, n)
+
+	for _, p := range pulls {
+		p.stop()
+	}
+	pulls = nil
+	measure().print("stopped", base, n)
+	fmt.Println()
+
+	// Forgetting to stop a pull iterator leaks its goroutine, and with it
+	// everything the iterator refers to. No reference to the iterator is
+	// left, but a goroutine is never garbage collected.
+	for range n {
+		firstCourse(courses())
+	}
+	leaked := measure()
+	leaked.print("firstCourse without stop", base, n)
+
+	base = leaked
+	for range n {
+		firstCourseStopped(courses())
+	}
+	measure().print("firstCourse with stop", base, n)
+
+	for range n {
+		firstCourseRange(courses())
+	}
+	measure().print("firstCourse with range", base, n)
+}
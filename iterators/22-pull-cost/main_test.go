@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/testutil"
+)
+
+// The tests fail if any of the functions leaves a pull iterator running.
+// Adding firstCourse to the list fails with the stack of every pull it
+// forgot to stop.
+func TestFirstCourse(t *testing.T) {
+	tests := []struct {
+		name  string
+		first func() string
+	}{
+		{"stop", func() string { return firstCourseStopped(courses()).Name }},
+		{"range", func() string { return firstCourseRange(courses()).Name }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.RequireStopped(t)
+
+			if tt.first() == "" {
+				t.Fatal("expected a course")
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/internal/tracing"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// wanted is the number of courses the lesson looks for before it stops
+// reading the database
+const wanted = 1000
+
+// atUniversity yields the courses of courses which belong to university,
+// along with every error
+func atUniversity(courses iter.Seq2[db.Course, error], university string) iter.Seq2[db.Course, error] {
+	return func(yield func(db.Course, error) bool) {
+		for course, err := range courses {
+			if err == nil && course.University != university {
+				continue
+			}
+
+			if !yield(course, err) {
+				return
+			}
+		}
+	}
+}
+
+// find returns the first courses of the university, reading the database
+// through a pipeline whose stages are each traced in a span of their own
+func find(ctx context.Context, coursesDB db.CoursesDB, university string) ([]db.Course, error) {
+	courses := tracing.Traced2(ctx, coursesDB.GetCourses(), "db.GetCourses")
+	matches := tracing.Traced2(ctx, atUniversity(courses, university), "atUniversity")
+
+	var found []db.Course
+	for course, err := range matches {
+		if err != nil {
+			return found, err
+		}
+
+		found = append(found, course)
+		if len(found) == wanted {
+			break
+		}
+	}
+
+	return found, nil
+}
+
+// printTrace prints the spans as a tree, each below its parent, in the order
+// they started
+func printTrace(spans []sdktrace.ReadOnlySpan) {
+	slices.SortFunc(spans, func(a, b sdktrace.ReadOnlySpan) int {
+		return a.StartTime().Compare(b.StartTime())
+	})
+
+	depth := make(map[string]int)
+	for _, span := range spans {
+		d := 0
+		if parent := span.Parent(); parent.IsValid() {
+			d = depth[parent.SpanID().String()] + 1
+		}
+		depth[span.SpanContext().SpanID().String()] = d
+
+		duration := span.EndTime().Sub(span.StartTime()).Round(time.Microsecond)
+		fmt.Printf("%s%s %s", strings.Repeat("  ", d), span.Name(), duration)
+
+		for _, kv := range span.Attributes() {
+			fmt.Printf("  %s=%s", strings.TrimPrefix(string(kv.Key), "iterator."), kv.Value.Emit())
+		}
+
+		if n := len(span.Events()); n > 0 {
+			fmt.Printf("  events=%d", n)
+		}
+		fmt.Println()
+	}
+}
+
+func main() {
+	// -count is the number of courses, and -seed makes them the same on
+	// every run
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 10000, DataDir: os.TempDir()})
+	db.Seed(cfg.Seed)
+
+	// The recorder keeps the spans in memory to print them, and with
+	// -otlp-endpoint they are also exported to a collector
+	recorder := tracetest.NewSpanRecorder()
+	defer tracing.Start(sdktrace.WithSpanProcessor(recorder))()
+
+	ctx, lesson := otel.Tracer("lesson").Start(context.Background(), "lesson")
+
+	coursesDB, err := db.New(cfg.DataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create database: %v\n", err)
+		os.Exit(1)
+	}
+	defer coursesDB.Close()
+
+	_, seed := otel.Tracer("lesson").Start(ctx, "seed")
+	err = coursesDB.Seed(cfg.Count)
+	seed.End()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to seed database: %v\n", err)
+		os.Exit(1)
+	}
+
+	found, err := find(ctx, coursesDB, "SJSU")
+	lesson.End()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to find courses: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("found %d courses at SJSU among %d courses\n\n", len(found), cfg.Count)
+	printTrace(recorder.Ended())
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/itertools"
+)
+
+// failureRate is the share of queries the flaky database drops
+const failureRate = 0.5
+
+// ErrUnavailable is the transient error of the flaky database. The same
+// query may well succeed a moment later.
+var ErrUnavailable = errors.New("database unavailable")
+
+// flakyDB stands in for a database behind an unreliable network, which drops
+// some of the queries of GetCourse. It counts how often each course was
+// asked for.
+type flakyDB struct {
+	db.CoursesDB
+	rnd     *rand.Rand
+	queries map[int]int
+}
+
+func newFlakyDB(coursesDB db.CoursesDB, seed uint64) *flakyDB {
+	return &flakyDB{
+		CoursesDB: coursesDB,
+		rnd:       rand.New(rand.NewPCG(seed, seed)),
+		queries:   make(map[int]int),
+	}
+}
+
+func (f *flakyDB) GetCourse(ctx context.Context, id int) (db.Course, error) {
+	f.queries[id]++
+	if f.rnd.Float64() < failureRate {
+		return db.Course{}, fmt.Errorf("failed to get course %d: %w", id, ErrUnavailable)
+	}
+
+	return f.CoursesDB.GetCourse(ctx, id)
+}
+
+// parseIDs yields the course IDs of a request, and an error for each one
+// which is not a number
+func parseIDs(request []string) iter.Seq2[int, error] {
+	return func(yield func(int, error) bool) {
+		for _, s := range request {
+			id, err := strconv.Atoi(s)
+			if err != nil {
+				err = fmt.Errorf("invalid course ID %q", s)
+			}
+
+			if !yield(id, err) {
+				return
+			}
+		}
+	}
+}
+
+// getCourses gets the courses of the request from the flaky database, and
+// retries each query as the policy says
+func getCourses(ctx context.Context, flaky *flakyDB, request []string, policy itertools.RetryPolicy) {
+	getCourse := func(id int) (db.Course, error) {
+		return flaky.GetCourse(ctx, id)
+	}
+
+	failed := 0
+	for course, err := range itertools.RetryEach(parseIDs(request), policy, getCourse) {
+		if err != nil {
+			failed++
+			fmt.Printf("  error: %v\n", err)
+			continue
+		}
+
+		fmt.Printf("  %3d %-8s %-12s queries=%d\n", course.ID, course.University, course.Name, flaky.queries[course.ID])
+	}
+
+	fmt.Printf("  %d of %d failed\n", failed, len(request))
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 100, DataDir: os.TempDir(), Seed: 1})
+	db.Seed(cfg.Seed)
+
+	coursesDB, err := db.New(cfg.DataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create database: %v\n", err)
+		os.Exit(1)
+	}
+	defer coursesDB.Close()
+
+	err = coursesDB.Seed(cfg.Count)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to seed database: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	request := []string{"1", "2", "3", "4", "five", "6", "7", "8", "9", "1000", "11", "12"}
+
+	fmt.Println("without retries:")
+	getCourses(ctx, newFlakyDB(coursesDB, cfg.Seed), request, itertools.RetryPolicy{MaxAttempts: 1})
+
+	// A course which does not exist will not exist a moment later either
+	policy := itertools.RetryPolicy{
+		MaxAttempts: 4,
+		Backoff:     10 * time.Millisecond,
+		MaxBackoff:  50 * time.Millisecond,
+		Retryable:   func(err error) bool { return errors.Is(err, ErrUnavailable) },
+	}
+
+	fmt.Println("\nwith up to 4 attempts:")
+	start := time.Now()
+	getCourses(ctx, newFlakyDB(coursesDB, cfg.Seed), request, policy)
+	fmt.Printf("  took %v\n", time.Since(start).Round(time.Millisecond))
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/itertools"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// corruptIDs are the courses whose name is lost. Scanning a NULL name into
+// the string of a Course fails.
+var corruptIDs = []int{3, 17, 42, 86}
+
+// corrupt sets the name of the courses to NULL, behind the back of the
+// CoursesDB
+func corrupt(dataDir string, ids []int) error {
+	conn, err := sql.Open("sqlite3", filepath.Join(dataDir, "courses.db"))
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer conn.Close()
+
+	for _, id := range ids {
+		_, err = conn.Exec(`UPDATE courses SET name = NULL WHERE id = ?`, id)
+		if err != nil {
+			return fmt.Errorf("failed to corrupt course %d: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// withRow adds the number of the row to the errors of courses, since the
+// error of a failed scan does not say which row it was
+func withRow(courses iter.Seq2[db.Course, error]) iter.Seq2[db.Course, error] {
+	return func(yield func(db.Course, error) bool) {
+		row := 0
+		for course, err := range courses {
+			row++
+			if err != nil {
+				err = fmt.Errorf("row %d: %w", row, err)
+			}
+
+			if !yield(course, err) {
+				return
+			}
+		}
+	}
+}
+
+// failFast returns the courses up to the first error, and the error
+func failFast(courses iter.Seq2[db.Course, error]) ([]db.Course, error) {
+	var found []db.Course
+	for course, err := range courses {
+		if err != nil {
+			return found, err
+		}
+
+		found = append(found, course)
+	}
+
+	return found, nil
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 100, DataDir: os.TempDir(), Seed: 1})
+	db.Seed(cfg.Seed)
+
+	coursesDB, err := db.New(cfg.DataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create database: %v\n", err)
+		os.Exit(1)
+	}
+	defer coursesDB.Close()
+
+	err = coursesDB.Seed(cfg.Count)
+	if err == nil {
+		err = corrupt(cfg.DataDir, corruptIDs)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to seed database: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("fail fast:")
+	courses, err := failFast(withRow(coursesDB.GetCourses()))
+	fmt.Printf("  %d courses\n", len(courses))
+	fmt.Printf("  error: %v\n", err)
+
+	fmt.Println("\naccumulate all:")
+	courses, err = itertools.CollectErrors(withRow(coursesDB.GetCourses()))
+	fmt.Printf("  %d courses\n", len(courses))
+
+	// errors.Join separates the errors with newlines, and keeps them for
+	// errors.Is and errors.As
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			fmt.Printf("  error: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"iter"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// Catalog holds courses in a slice, and offers iterators over them written
+// in several ways, which yield the same courses at a different cost
+type Catalog struct {
+	courses []db.Course
+}
+
+// Boxed yields every course as an any, the way an iterator written before
+// generics would. Converting a Course to an interface copies it to the heap,
+// since it is larger than a pointer.
+func (c *Catalog) Boxed() iter.Seq[any] {
+	return func(yield func(any) bool) {
+		for _, course := range c.courses {
+			if !yield(course) {
+				return
+			}
+		}
+	}
+}
+
+// Copies yields a pointer to a copy of every course. The compiler cannot see
+// what yield does with the pointer, so it has to assume that the loop body
+// keeps it, and the copy escapes to the heap.
+func (c *Catalog) Copies() iter.Seq[*db.Course] {
+	return func(yield func(*db.Course) bool) {
+		for _, course := range c.courses {
+			if !yield(&course) {
+				return
+			}
+		}
+	}
+}
+
+// Lazy yields a function which returns each course. The closure captures the
+// course, and escapes through yield like the pointer of Copies.
+func (c *Catalog) Lazy() iter.Seq[func() db.Course] {
+	return func(yield func(func() db.Course) bool) {
+		for _, course := range c.courses {
+			if !yield(func() db.Course { return course }) {
+				return
+			}
+		}
+	}
+}
+
+// All yields every course by value. A value passed to yield is copied into
+// its arguments, so nothing escapes, and the loop does not allocate at all.
+func (c *Catalog) All() iter.Seq[db.Course] {
+	return func(yield func(db.Course) bool) {
+		for _, course := range c.courses {
+			if !yield(course) {
+				return
+			}
+		}
+	}
+}
+
+// Pointers yields a pointer to every course in the slice of the catalog,
+// which is already on the heap. Nothing is copied, and nothing is allocated,
+// but the loop body can change the courses of the catalog.
+func (c *Catalog) Pointers() iter.Seq[*db.Course] {
+	return func(yield func(*db.Course) bool) {
+		for i := range c.courses {
+			if !yield(&c.courses[i]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// sumIDs adds up the IDs of the courses of seq. The compiler inlines a
+// small iterator into the loop over it, and can then see that nothing
+// escapes through yield. It cannot once the iterator is handed to code which
+// is not inlined, such as a function which is too large, as most consumers
+// in a real program are, and which noinline stands in for.
+//
+//go:noinline
+func sumIDs[T any](seq iter.Seq[T], id func(T) int) int {
+	sum := 0
+	for v := range seq {
+		sum += id(v)
+	}
+
+	return sum
+}
+
+// loops range over every course of the catalog in each of its styles
+var loops = []struct {
+	name string
+	loop func(c *Catalog) int
+}{
+	{"Boxed (any)", func(c *Catalog) int {
+		return sumIDs(c.Boxed(), func(v any) int { return v.(db.Course).ID })
+	}},
+	{"Copies (*Course)", func(c *Catalog) int {
+		return sumIDs(c.Copies(), func(course *db.Course) int { return course.ID })
+	}},
+	{"Lazy (func() Course)", func(c *Catalog) int {
+		return sumIDs(c.Lazy(), func(course func() db.Course) int { return course().ID })
+	}},
+	{"All (Course)", func(c *Catalog) int {
+		return sumIDs(c.All(), func(course db.Course) int { return course.ID })
+	}},
+	{"Pointers (*Course)", func(c *Catalog) int {
+		return sumIDs(c.Pointers(), func(course *db.Course) int { return course.ID })
+	}},
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 1000})
+	db.Seed(cfg.Seed)
+
+	catalog := &Catalog{courses: slices.Collect(db.GenerateCourses(cfg.Count))}
+
+	fmt.Printf("allocations of a loop over %d courses\n\n", cfg.Count)
+	for _, l := range loops {
+		allocs := testing.AllocsPerRun(100, func() { l.loop(catalog) })
+		fmt.Printf("%-24s %6.0f per loop, %4.2f per course\n", l.name, allocs, allocs/float64(cfg.Count))
+	}
+}
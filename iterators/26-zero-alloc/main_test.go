@@ -0,0 +1,65 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+func catalogOf(n int) *Catalog {
+	return &Catalog{courses: slices.Collect(db.GenerateCourses(n))}
+}
+
+// TestAllocsPerCourse checks which styles allocate for every course, by
+// comparing the allocations of a loop over 10 courses with one over 1000.
+// A style which allocates per loop, but not per course, allocates as much
+// for both.
+func TestAllocsPerCourse(t *testing.T) {
+	small, large := catalogOf(10), catalogOf(1000)
+
+	perCourse := map[string]bool{
+		"Boxed (any)":          true,
+		"Copies (*Course)":     true,
+		"Lazy (func() Course)": true,
+		"All (Course)":         false,
+		"Pointers (*Course)":   false,
+	}
+
+	for _, l := range loops {
+		t.Run(l.name, func(t *testing.T) {
+			allocsSmall := testing.AllocsPerRun(100, func() { l.loop(small) })
+			allocsLarge := testing.AllocsPerRun(100, func() { l.loop(large) })
+
+			got := (allocsLarge-allocsSmall)/990 >= 1
+			if got != perCourse[l.name] {
+				t.Fatalf("expected allocations per course to be %t, got %v for 10 courses and %v for 1000", perCourse[l.name], allocsSmall, allocsLarge)
+			}
+		})
+	}
+}
+
+func TestLoopsAgree(t *testing.T) {
+	catalog := catalogOf(100)
+
+	expected := loops[0].loop(catalog)
+	for _, l := range loops[1:] {
+		if got := l.loop(catalog); got != expected {
+			t.Fatalf("%s: expected the IDs to add up to %d, got %d", l.name, expected, got)
+		}
+	}
+}
+
+func BenchmarkLoops(b *testing.B) {
+	catalog := catalogOf(1000)
+
+	for _, l := range loops {
+		b.Run(l.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for range b.N {
+				l.loop(catalog)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/itertools"
+)
+
+const (
+	// buffer is the number of values the channel to the worker holds,
+	// whether they are courses or batches of them
+	buffer = 16
+
+	// rounds is how often each strategy is timed. The fastest round is
+	// reported, which is the one the least disturbed by everything else
+	// running on the machine.
+	rounds = 5
+
+	// tolerance is how much slower than the fastest batch size a smaller
+	// one may be to be recommended
+	tolerance = 0.1
+)
+
+var batchSizes = []int{4, 16, 64, 256, 1024, 4096}
+
+// score stands in for the work done on a course, which is cheap compared to
+// handing the course to another goroutine
+func score(course db.Course) int {
+	return len(course.Name) + len(course.University)
+}
+
+// perCourse hands every course to a worker on its own. Every course costs a
+// channel send and a receive, and the worker is woken up far more often.
+func perCourse(courses iter.Seq[db.Course]) int {
+	ch := make(chan db.Course, buffer)
+	total := make(chan int)
+
+	go func() {
+		sum := 0
+		for course := range ch {
+			sum += score(course)
+		}
+		total <- sum
+	}()
+
+	for course := range courses {
+		ch <- course
+	}
+	close(ch)
+
+	return <-total
+}
+
+// batched hands the courses to a worker in batches of size. The cost of the
+// channel is shared by every course of a batch, and the worker goes through
+// the courses of a batch in a tight loop over memory which is next to each
+// other.
+func batched(courses iter.Seq[db.Course], size int) int {
+	ch := make(chan []db.Course, buffer)
+	total := make(chan int)
+
+	go func() {
+		sum := 0
+		for batch := range ch {
+			for _, course := range batch {
+				sum += score(course)
+			}
+		}
+		total <- sum
+	}()
+
+	for batch := range itertools.Chunk(courses, size) {
+		ch <- batch
+	}
+	close(ch)
+
+	return <-total
+}
+
+// fastest returns the duration of the fastest of the rounds of fn
+func fastest(fn func()) time.Duration {
+	var best time.Duration
+	for i := range rounds {
+		start := time.Now()
+		fn()
+
+		if d := time.Since(start); i == 0 || d < best {
+			best = d
+		}
+	}
+
+	return best
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 100000})
+	db.Seed(cfg.Seed)
+
+	courses := slices.Collect(db.GenerateCourses(cfg.Count))
+	perNs := func(d time.Duration) float64 { return float64(d.Nanoseconds()) / float64(cfg.Count) }
+
+	base := fastest(func() { perCourse(slices.Values(courses)) })
+	fmt.Printf("processing %d courses\n\n", cfg.Count)
+	fmt.Printf("%-17s %7.1f ns/course\n", "per course", perNs(base))
+
+	durations := make([]time.Duration, len(batchSizes))
+	for i, size := range batchSizes {
+		durations[i] = fastest(func() { batched(slices.Values(courses), size) })
+		fmt.Printf("batches of %-6d %7.1f ns/course, %5.1fx faster\n", size, perNs(durations[i]), float64(base)/float64(durations[i]))
+	}
+
+	// Larger batches hold more courses in memory, and make the worker wait
+	// longer for the first of them, so the smallest batch size which is
+	// about as fast as the fastest one is the best choice
+	best := slices.Min(durations)
+	for i, d := range durations {
+		if float64(d) <= float64(best)*(1+tolerance) {
+			fmt.Printf("\nrecommended: batches of %d, the smallest within %.0f%% of the fastest\n", batchSizes[i], tolerance*100)
+			break
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+func TestBatchedAgrees(t *testing.T) {
+	courses := slices.Collect(db.GenerateCourses(1000))
+
+	expected := perCourse(slices.Values(courses))
+	for _, size := range append(batchSizes, 1, 999, 2000) {
+		if got := batched(slices.Values(courses), size); got != expected {
+			t.Fatalf("batches of %d: expected a score of %d, got %d", size, expected, got)
+		}
+	}
+}
+
+func BenchmarkBatching(b *testing.B) {
+	courses := slices.Collect(db.GenerateCourses(10000))
+
+	b.Run("per course", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for range b.N {
+			perCourse(slices.Values(courses))
+		}
+	})
+
+	for _, size := range batchSizes {
+		b.Run(fmt.Sprintf("batches of %d", size), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for range b.N {
+				batched(slices.Values(courses), size)
+			}
+		})
+	}
+}
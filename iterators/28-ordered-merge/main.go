@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/itertools"
+)
+
+const (
+	// maxGap is the longest time between two enrollments at a university
+	maxGap = 3 * time.Second
+
+	// maxDelay is the longest an enrollment takes to be delivered, which is
+	// how far out of order the stream of a university can be
+	maxDelay = 2 * time.Second
+)
+
+var start = time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+// Enrollment is an event of a university, when a student enrolls in a course
+type Enrollment struct {
+	University string
+	Course     string
+}
+
+// delivered is an enrollment, along with when it happened, and when it was
+// delivered
+type delivered struct {
+	Enrollment
+	at, arrival time.Time
+}
+
+// enrollments simulates the stream of enrollments of university, which are n
+// events at random times, delivered after a random delay of up to maxDelay.
+// The stream yields them in the order they are delivered, so an enrollment
+// may come after a later one which was delivered sooner.
+func enrollments(r *rand.Rand, university string, n int) iter.Seq2[time.Time, Enrollment] {
+	events := make([]delivered, n)
+
+	at := start
+	for i, course := range slices.Collect(db.GenerateCourses(n)) {
+		at = at.Add(time.Duration(r.Int64N(int64(maxGap))))
+
+		events[i] = delivered{
+			Enrollment: Enrollment{University: university, Course: course.Name},
+			at:         at,
+			arrival:    at.Add(time.Duration(r.Int64N(int64(maxDelay)))),
+		}
+	}
+
+	slices.SortStableFunc(events, func(a, b delivered) int {
+		return a.arrival.Compare(b.arrival)
+	})
+
+	return func(yield func(time.Time, Enrollment) bool) {
+		for _, e := range events {
+			if !yield(e.at, e.Enrollment) {
+				return
+			}
+		}
+	}
+}
+
+// streams returns the streams of every university, with n enrollments each.
+// They are generated from the same seed every time, so every merge sees the
+// same streams.
+func streams(cfg lessoncfg.Config, n int) []iter.Seq2[time.Time, Enrollment] {
+	db.Seed(cfg.Seed)
+	r := cfg.Rand()
+
+	var seqs []iter.Seq2[time.Time, Enrollment]
+	for _, university := range db.Universities() {
+		seqs = append(seqs, enrollments(r, university, n))
+	}
+
+	return seqs
+}
+
+// merge merges the streams with the given window, and prints the enrollments
+// which were yielded if print is set. It returns the number of enrollments
+// which were yielded, which were out of order, and which were late.
+func merge(seqs []iter.Seq2[time.Time, Enrollment], window time.Duration, print bool) (yielded, unordered, late int) {
+	onLate := func(time.Time, Enrollment) { late++ }
+
+	var last time.Time
+	for at, e := range itertools.MergeByTime(window, onLate, seqs...) {
+		yielded++
+		if at.Before(last) {
+			unordered++
+		}
+		last = at
+
+		if print {
+			fmt.Printf("  %5.1fs  %-5s %s\n", at.Sub(start).Seconds(), e.University, e.Course)
+		}
+	}
+
+	return yielded, unordered, late
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 5, Seed: 1})
+
+	// Each stream is out of order by up to maxDelay, which is what the
+	// window has to cover. An enrollment which comes after a later one is
+	// marked with a *.
+	fmt.Println("Streams, as delivered:")
+	for i, seq := range streams(cfg, cfg.Count) {
+		fmt.Printf("  %-5s", db.Universities()[i])
+
+		var latest time.Time
+		for at := range seq {
+			mark := "*"
+			if !at.Before(latest) {
+				mark = ""
+				latest = at
+			}
+
+			fmt.Printf(" %7s", fmt.Sprintf("%s%.1fs", mark, at.Sub(start).Seconds()))
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("\nMerged with a window of %v:\n", maxDelay)
+	yielded, unordered, late := merge(streams(cfg, cfg.Count), maxDelay, true)
+	fmt.Printf("  %d enrollments, %d out of order, %d late\n", yielded, unordered, late)
+
+	// A smaller window holds fewer enrollments back, for less time, but
+	// drops the ones which are delivered later than it covers
+	fmt.Println("\nOther windows, with 100 enrollments per university:")
+	for _, window := range []time.Duration{0, 500 * time.Millisecond, time.Second, maxDelay} {
+		yielded, unordered, late := merge(streams(cfg, 100), window, false)
+		fmt.Printf("  %-5v %3d enrollments, %d out of order, %3d late\n", window, yielded, unordered, late)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/itertools"
+)
+
+const (
+	// inspect is the number of courses which are printed
+	inspect = 5
+
+	// estimate is the size of the sample the shares of the universities are
+	// estimated from
+	estimate = 1000
+)
+
+// shares returns the share of each university among courses
+func shares(courses iter.Seq[db.Course]) map[string]float64 {
+	var total float64
+	counts := make(map[string]float64)
+	for c := range courses {
+		counts[c.University]++
+		total++
+	}
+
+	for university := range counts {
+		counts[university] /= total
+	}
+
+	return counts
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 1000000, Seed: 1})
+	r := cfg.Rand()
+
+	// Every pass over the courses generates them again from the same seed,
+	// so that every sample is taken from the same stream, and no pass holds
+	// more than its sample.
+	//
+	// A reservoir holds k courses however many there are, and is only
+	// complete once the stream ends. It is not in the order of the stream.
+	db.Seed(cfg.Seed)
+	fmt.Printf("%d of %d courses, from a reservoir:\n", inspect, cfg.Count)
+	for _, c := range itertools.ReservoirSample(db.GenerateCourses(cfg.Count), inspect, r) {
+		fmt.Printf("  %7d %-5s %s\n", c.ID, c.University, c.Name)
+	}
+
+	// A fraction yields each course as the stream reaches it, in order, so
+	// the loop can stop once it has seen enough
+	db.Seed(cfg.Seed)
+	fraction := float64(inspect) / float64(cfg.Count)
+	fmt.Printf("\nAbout %d of %d courses, a fraction of %g:\n", inspect, cfg.Count, fraction)
+	for c := range itertools.SampleFraction(db.GenerateCourses(cfg.Count), fraction, r) {
+		fmt.Printf("  %7d %-5s %s\n", c.ID, c.University, c.Name)
+	}
+
+	// The share of each university in a sample of a thousand courses is
+	// close to its share of all of them
+	db.Seed(cfg.Seed)
+	sampled := shares(slices.Values(itertools.ReservoirSample(db.GenerateCourses(cfg.Count), estimate, r)))
+
+	db.Seed(cfg.Seed)
+	actual := shares(db.GenerateCourses(cfg.Count))
+
+	fmt.Printf("\nShares of the universities, from %d courses and from all of them:\n", estimate)
+	for _, university := range slices.Sorted(maps.Keys(actual)) {
+		fmt.Printf("  %-5s %5.1f%% %5.1f%%\n", university, 100*sampled[university], 100*actual[university])
+	}
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+	"runtime/metrics"
+	"slices"
+	"time"
+	"unsafe"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/gobseq"
+	"github.com/manedurphy/golang-university/iterators/itertools"
+	"github.com/manedurphy/golang-university/iterators/jsonseq"
+)
+
+const (
+	// runSize is the number of courses which are sorted in memory at a
+	// time, which is the memory budget of the sort
+	runSize = 100000
+
+	// fanIn is the number of runs which are merged at a time. Each of them
+	// holds a file open and a buffer, so a sort of many runs merges them in
+	// several passes rather than all at once.
+	fanIn = 16
+)
+
+var format = flag.String("format", "gob", "The format of the runs on disk, gob or json")
+
+// codec writes and reads the courses of a run
+type codec struct {
+	ext    string
+	encode func(io.Writer, iter.Seq[db.Course]) error
+	decode func(io.Reader) iter.Seq2[db.Course, error]
+}
+
+var codecs = map[string]codec{
+	"gob":  {ext: ".gob", encode: gobseq.Encode[db.Course], decode: gobseq.Decode[db.Course]},
+	"json": {ext: ".jsonl", encode: jsonseq.WriteLines[db.Course], decode: jsonseq.Lines[db.Course]},
+}
+
+// compare orders courses by name, then by university, and then by ID
+func compare(a, b db.Course) int {
+	return cmp.Or(
+		cmp.Compare(a.Name, b.Name),
+		cmp.Compare(a.University, b.University),
+		cmp.Compare(a.ID, b.ID),
+	)
+}
+
+// less is compare for itertools.MergeSorted
+func less(a, b db.Course) bool {
+	return compare(a, b) < 0
+}
+
+// sorter spills sorted runs to dir, and merges them
+type sorter struct {
+	dir   string
+	codec codec
+	runs  int
+	bytes int64
+}
+
+// create writes seq to a new run, and returns its path
+func (s *sorter) create(seq iter.Seq[db.Course]) (string, error) {
+	path := filepath.Join(s.dir, fmt.Sprintf("run-%05d%s", s.runs, s.codec.ext))
+	s.runs++
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.codec.encode(f, seq)
+	if err == nil {
+		var info os.FileInfo
+		info, err = f.Stat()
+		if err == nil {
+			s.bytes += info.Size()
+		}
+	}
+
+	return path, errors.Join(err, f.Close())
+}
+
+// spill sorts the courses of seq runSize at a time, and writes each sorted
+// run to a file of its own. Only a single run is in memory at a time.
+func (s *sorter) spill(seq iter.Seq[db.Course]) ([]string, error) {
+	var paths []string
+
+	for run := range itertools.Chunk(seq, runSize) {
+		slices.SortFunc(run, compare)
+
+		path, err := s.create(slices.Values(run))
+		if err != nil {
+			return nil, fmt.Errorf("failed to write run: %w", err)
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// open returns the merge of the runs at paths, and a function which closes
+// them and returns the first error any of them had
+func (s *sorter) open(paths []string) (iter.Seq[db.Course], func() error, error) {
+	var (
+		files []*os.File
+		runs  []iter.Seq[db.Course]
+		errs  = make([]error, len(paths))
+	)
+
+	closeAll := func() error {
+		for _, f := range files {
+			errs = append(errs, f.Close())
+		}
+
+		return errors.Join(errs...)
+	}
+
+	for i, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+		files = append(files, f)
+
+		// A run which fails to decode ends early, and its error is kept for
+		// once the merge is done, as the merge only takes values
+		runs = append(runs, func(yield func(db.Course) bool) {
+			for c, err := range s.codec.decode(f) {
+				if err != nil {
+					errs[i] = fmt.Errorf("%s: %w", filepath.Base(path), err)
+					return
+				}
+
+				if !yield(c) {
+					return
+				}
+			}
+		})
+	}
+
+	return itertools.MergeSorted(less, runs...), closeAll, nil
+}
+
+// merge merges the runs at paths into fanIn runs or fewer, a group of fanIn at
+// a time, and removes the runs it merged
+func (s *sorter) merge(paths []string) ([]string, error) {
+	var merged []string
+
+	for group := range slices.Chunk(paths, fanIn) {
+		seq, closeRuns, err := s.open(group)
+		if err != nil {
+			return nil, err
+		}
+
+		path, err := s.create(seq)
+		err = errors.Join(err, closeRuns())
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge runs: %w", err)
+		}
+
+		for _, p := range group {
+			os.Remove(p)
+		}
+		merged = append(merged, path)
+	}
+
+	return merged, nil
+}
+
+// peakHeap samples the memory occupied by objects on the heap every
+// millisecond, until the returned function is called, which returns the
+// largest sample
+func peakHeap() func() uint64 {
+	stop := make(chan struct{})
+	peak := make(chan uint64)
+
+	go func() {
+		sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+
+		var largest uint64
+		for {
+			metrics.Read(sample)
+			largest = max(largest, sample[0].Value.Uint64())
+
+			select {
+			case <-ticker.C:
+			case <-stop:
+				peak <- largest
+				return
+			}
+		}
+	}()
+
+	return func() uint64 {
+		close(stop)
+		return <-peak
+	}
+}
+
+func main() {
+	// -count is the number of courses to sort, -data-dir is where the runs
+	// are written, and -format is how
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 1000000, Seed: 1, DataDir: os.TempDir()})
+	db.Seed(cfg.Seed)
+
+	c, ok := codecs[*format]
+	if !ok {
+		fmt.Printf("unknown format %q, use gob or json\n", *format)
+		os.Exit(2)
+	}
+
+	dir, err := os.MkdirTemp(cfg.DataDir, "university-external-sort-")
+	if err != nil {
+		fmt.Printf("failed to create data directory: %v\n", err)
+		os.Exit(1)
+	}
+	defer os.RemoveAll(dir)
+
+	s := &sorter{dir: dir, codec: c}
+	stopSampling := peakHeap()
+
+	paths, err := s.spill(db.GenerateCourses(cfg.Count))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	spilled := len(paths)
+	fmt.Printf("spilled %d courses to %d sorted runs of %d\n", cfg.Count, spilled, runSize)
+
+	// Each pass merges the runs fanIn at a time, until a single pass can
+	// merge what is left
+	passes := 1
+	for ; len(paths) > fanIn; passes++ {
+		paths, err = s.merge(paths)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+
+	sorted, closeRuns, err := s.open(paths)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// The last pass is read rather than written, and checked on the way
+	var (
+		count    int
+		previous db.Course
+		first    []db.Course
+	)
+	for course := range sorted {
+		if count > 0 && less(course, previous) {
+			fmt.Printf("course %d is out of order\n", course.ID)
+			closeRuns()
+			return
+		}
+
+		if count < 3 {
+			first = append(first, course)
+		}
+		previous = course
+		count++
+	}
+
+	err = closeRuns()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	peak := stopSampling()
+
+	unit := "passes"
+	if passes == 1 {
+		unit = "pass"
+	}
+	fmt.Printf("merged %d runs in %d %s, at most %d at a time\n", spilled, passes, unit, fanIn)
+	fmt.Printf("read %d courses in order, the first of which are:\n", count)
+	for _, course := range first {
+		fmt.Printf("  %-10s %-5s %d\n", course.Name, course.University, course.ID)
+	}
+
+	// Sorting in memory would hold every course at once, which is at least
+	// the size of the structs, without the slice growing to hold them
+	fmt.Printf("\nwrote %.1f MiB of runs, over every pass\n", float64(s.bytes)/(1<<20))
+	fmt.Printf("in memory, the courses would take %.1f MiB\n", float64(cfg.Count)*float64(unsafe.Sizeof(db.Course{}))/(1<<20))
+	fmt.Printf("peak heap %.1f MiB\n", float64(peak)/(1<<20))
+}
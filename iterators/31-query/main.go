@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/query"
+)
+
+// counted yields the values of s, and counts them in scanned, so that the
+// lesson can tell how much of the table a query read
+func counted[T any](s []T, scanned *int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range s {
+			*scanned++
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// run prints the plan of q, and the rows it returns as a table
+func run(q *query.Query) {
+	fmt.Print(q.Explain())
+
+	columns, rows, err := q.Rows()
+	if err != nil {
+		fmt.Printf("error: %v\n", err)
+		return
+	}
+
+	fmt.Println()
+	printTable(columns, rows)
+}
+
+// printTable prints a column of width 12 per column, under a header
+func printTable(columns []string, rows iter.Seq[query.Row]) {
+	var header strings.Builder
+	for _, column := range columns {
+		fmt.Fprintf(&header, "%-12s", column)
+	}
+	fmt.Println(strings.TrimSpace(header.String()))
+
+	for row := range rows {
+		var line strings.Builder
+		for _, v := range row {
+			fmt.Fprintf(&line, "%-12v", v)
+		}
+		fmt.Println(strings.TrimSpace(line.String()))
+	}
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 1000, Seed: 1, DataDir: os.TempDir()})
+	db.Seed(cfg.Seed)
+
+	// Each iteration over GenerateCourses generates different courses, so
+	// they are collected into a table the queries share
+	courses := slices.Collect(db.GenerateCourses(cfg.Count))
+
+	fmt.Println("The most common courses at UCB:")
+	var scanned int
+	q := query.From("courses", counted(courses, &scanned)).
+		Where(query.Col("University").Eq("UCB")).
+		GroupBy(query.Col("Name")).
+		Aggregate(query.Count().As("courses"), query.Min(query.Col("ID")).As("first")).
+		OrderBy(query.Col("courses").Desc()).
+		Limit(3)
+	run(q)
+	fmt.Printf("scanned %d of %d courses\n", scanned, len(courses))
+
+	// The filter and the limit are streamed, so the scan stops as soon as
+	// the limit is reached
+	fmt.Println("\nThe first Chem-1 courses outside of UCB:")
+	scanned = 0
+	q = query.From("courses", counted(courses, &scanned)).
+		Where(query.Col("Name").Eq("Chem-1").And(query.Not(query.Col("University").Eq("UCB")))).
+		Select(query.Col("ID"), query.Col("University")).
+		Limit(4)
+	run(q)
+	fmt.Printf("scanned %d of %d courses\n", scanned, len(courses))
+
+	// Columns are resolved when the query is compiled, before any course is
+	// read
+	fmt.Println("\nA column the courses do not have:")
+	scanned = 0
+	run(query.From("courses", counted(courses, &scanned)).Where(query.Col("Teacher").Eq("Smith")))
+	fmt.Printf("scanned %d of %d courses\n", scanned, len(courses))
+}
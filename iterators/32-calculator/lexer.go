@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"strconv"
+)
+
+// Kind is the kind of a token
+type Kind int
+
+const (
+	Number Kind = iota
+	Plus
+	Minus
+	Star
+	Slash
+	Caret
+	LParen
+	RParen
+	// EOF is the end of the input. The lexer does not yield it, it is what
+	// the parser sees once the tokens run out.
+	EOF
+)
+
+var kindNames = [...]string{"number", "+", "-", "*", "/", "^", "(", ")", "end of input"}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// operators maps the characters of the operators and parentheses to their
+// kinds
+var operators = map[byte]Kind{
+	'+': Plus,
+	'-': Minus,
+	'*': Star,
+	'/': Slash,
+	'^': Caret,
+	'(': LParen,
+	')': RParen,
+}
+
+// Token is a token of an expression, along with its position, the offset of
+// its first byte in the expression
+type Token struct {
+	Kind  Kind
+	Text  string
+	Pos   int
+	Value float64
+}
+
+func (t Token) String() string {
+	if t.Kind == Number {
+		return t.Text
+	}
+
+	return t.Kind.String()
+}
+
+// SyntaxError is an error in an expression, at the offset Pos
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Pos, e.Msg)
+}
+
+// Lex returns the tokens of src. It only reads as far into src as the tokens
+// which are ranged over, and a character which starts no token is yielded as
+// a *SyntaxError, after which Lex stops.
+func Lex(src string) iter.Seq2[Token, error] {
+	return func(yield func(Token, error) bool) {
+		for i := 0; i < len(src); {
+			c := src[i]
+
+			switch {
+			case c == ' ' || c == '\t' || c == '\n':
+				i++
+				continue
+
+			case isDigit(c) || c == '.':
+				start := i
+				for i < len(src) && (isDigit(src[i]) || src[i] == '.') {
+					i++
+				}
+
+				v, err := strconv.ParseFloat(src[start:i], 64)
+				if err != nil {
+					yield(Token{}, &SyntaxError{start, fmt.Sprintf("invalid number %q", src[start:i])})
+					return
+				}
+
+				if !yield(Token{Kind: Number, Text: src[start:i], Pos: start, Value: v}, nil) {
+					return
+				}
+
+			default:
+				kind, ok := operators[c]
+				if !ok {
+					yield(Token{}, &SyntaxError{i, fmt.Sprintf("unexpected character %q", c)})
+					return
+				}
+
+				if !yield(Token{Kind: kind, Text: src[i : i+1], Pos: i}, nil) {
+					return
+				}
+				i++
+			}
+		}
+	}
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
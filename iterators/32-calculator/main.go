@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	// The lexer is an iterator like any other, which can be ranged over
+	// with the push style
+	src := "2 * (3 + 4.5) ^ 2"
+	fmt.Printf("tokens of %q:\n", src)
+	for tok, err := range Lex(src) {
+		if err != nil {
+			fmt.Printf("  error: %v\n", err)
+			break
+		}
+		fmt.Printf("  %2d %s\n", tok.Pos, tok)
+	}
+
+	// The parser pulls the tokens instead, one at a time, when a rule of
+	// the grammar needs to look at the next one
+	fmt.Println("\nevaluated:")
+	for _, src := range []string{
+		"1 + 2 * 3",
+		"(1 + 2) * 3",
+		"2 * (3 + 4.5) ^ 2",
+		"-2 ^ 2",
+		"2 ^ 3 ^ 2",
+		"2 ^ -1 - -3",
+		"10 / 4 / 5",
+	} {
+		v, err := Evaluate(src)
+		if err != nil {
+			fmt.Printf("  %-20s error: %v\n", src, err)
+			continue
+		}
+		fmt.Printf("  %-20s = %s\n", src, strconv.FormatFloat(v, 'g', -1, 64))
+	}
+
+	// The lexer only runs as far as the parser pulls, so an error stops it
+	// before it reads the rest of the input
+	fmt.Println("\nerrors:")
+	long := "1 + * 2" + strings.Repeat(" + 2", 1000)
+	for i, src := range []string{"2 * (3 + 4", "3 4", "1 + x", "1 / (2 - 2)", long} {
+		if i > 0 {
+			fmt.Println()
+		}
+
+		_, lexed, err := evaluate(src)
+
+		var syntaxErr *SyntaxError
+		switch {
+		case errors.As(err, &syntaxErr):
+			fmt.Printf("  %s\n  %s^ %s\n", truncate(src, 20), strings.Repeat(" ", syntaxErr.Pos), syntaxErr.Msg)
+		case err != nil:
+			fmt.Printf("  %s\n  %v\n", truncate(src, 20), err)
+		}
+
+		fmt.Printf("  lexed %d of %d tokens\n", lexed, count(src))
+	}
+}
+
+// truncate returns the first n bytes of s, followed by ... if it is longer
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	return s[:n] + "..."
+}
+
+// count returns the number of tokens in src, up to the first error
+func count(src string) int {
+	n := 0
+	for _, err := range Lex(src) {
+		if err != nil {
+			break
+		}
+		n++
+	}
+
+	return n
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+func TestLex(t *testing.T) {
+	var texts []string
+	var kinds []Kind
+	for tok, err := range Lex(" 12.5*(3-x") {
+		if err != nil {
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) || syntaxErr.Pos != 9 {
+				t.Fatalf("expected a syntax error at position 9, got %v", err)
+			}
+			break
+		}
+
+		texts = append(texts, tok.Text)
+		kinds = append(kinds, tok.Kind)
+	}
+
+	if got := strings.Join(texts, " "); got != "12.5 * ( 3 -" {
+		t.Fatalf("expected the tokens before the error, got %q", got)
+	}
+
+	if expected := []Kind{Number, Star, LParen, Number, Minus}; !slices.Equal(kinds, expected) {
+		t.Fatalf("expected kinds %v, got %v", expected, kinds)
+	}
+
+	seqtest.AssertStopsEarly2(t, 2, func() iter.Seq2[Token, error] { return Lex("1 + 2 + 3") })
+	seqtest.AssertNoYieldAfterFalse2(t, 2, func() iter.Seq2[Token, error] { return Lex("1 + 2 + 3") })
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected float64
+	}{
+		{"42", 42},
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"10 - 4 - 3", 3},
+		{"10 / 4 / 5", 0.5},
+		{"-2 ^ 2", -4},
+		{"2 ^ 3 ^ 2", 512},
+		{"2 ^ -1", 0.5},
+		{"--3", 3},
+		{"((.5))", 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			got, err := Evaluate(tt.src)
+			if err != nil {
+				t.Fatalf("failed to evaluate: %v", err)
+			}
+
+			if got != tt.expected {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		pos int
+	}{
+		{"", 0},
+		{"1 +", 3},
+		{"3 4", 2},
+		{"(1 + 2", 6},
+		{"1 + )", 4},
+		{"1 $ 2", 2},
+		{"1.2.3", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := Evaluate(tt.src)
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) || syntaxErr.Pos != tt.pos {
+				t.Fatalf("expected a syntax error at position %d, got %v", tt.pos, err)
+			}
+		})
+	}
+}
+
+func TestDivisionByZero(t *testing.T) {
+	if _, err := Evaluate("1 / (2 - 2)"); !errors.Is(err, ErrDivisionByZero) {
+		t.Fatalf("expected ErrDivisionByZero, got %v", err)
+	}
+}
+
+// TestPullsLazily checks that the parser stops pulling tokens at the first
+// error, instead of lexing all of the input
+func TestPullsLazily(t *testing.T) {
+	_, lexed, err := evaluate("1 + * 2" + strings.Repeat(" + 2", 1000))
+	if err == nil {
+		t.Fatal("expected a syntax error")
+	}
+
+	if lexed != 3 {
+		t.Fatalf("expected 3 tokens to be lexed, got %d", lexed)
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"math"
+)
+
+// ErrDivisionByZero is returned when evaluating an expression which divides
+// by zero
+var ErrDivisionByZero = errors.New("division by zero")
+
+// parser evaluates an expression as it parses it, by recursive descent. Each
+// rule of the grammar is a method, which reads the tokens of its rule and
+// returns their value:
+//
+//	expr    = term { ("+" | "-") term }
+//	term    = unary { ("*" | "/") unary }
+//	unary   = "-" unary | power
+//	power   = primary [ "^" unary ]
+//	primary = number | "(" expr ")"
+//
+// The rules only ever need to look at the next token to decide what to do,
+// which the parser pulls from the lexer with iter.Pull2 and holds in tok.
+type parser struct {
+	next func() (Token, error, bool)
+
+	// tok is the next token, which has not been consumed yet
+	tok Token
+	// end is the position of the end of the input, which EOF is given
+	end int
+	// lexed counts the tokens pulled from the lexer
+	lexed int
+}
+
+// Evaluate parses src, and returns the value of the expression
+func Evaluate(src string) (float64, error) {
+	v, _, err := evaluate(src)
+	return v, err
+}
+
+// evaluate is Evaluate, which also returns the number of tokens pulled from
+// the lexer
+func evaluate(src string) (float64, int, error) {
+	next, stop := iter.Pull2(Lex(src))
+	defer stop()
+
+	p := &parser{next: next, end: len(src)}
+	if err := p.advance(); err != nil {
+		return 0, p.lexed, err
+	}
+
+	v, err := p.expr()
+	if err == nil && p.tok.Kind != EOF {
+		err = p.unexpected("an operator")
+	}
+
+	return v, p.lexed, err
+}
+
+// advance consumes tok, and pulls the next token from the lexer into it
+func (p *parser) advance() error {
+	tok, err, ok := p.next()
+	if !ok {
+		p.tok = Token{Kind: EOF, Pos: p.end}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	p.lexed++
+	p.tok = tok
+
+	return nil
+}
+
+// unexpected returns the error of finding tok where expected was
+func (p *parser) unexpected(expected string) error {
+	return &SyntaxError{p.tok.Pos, fmt.Sprintf("expected %s, got %s", expected, p.tok)}
+}
+
+func (p *parser) expr() (float64, error) {
+	v, err := p.term()
+	if err != nil {
+		return 0, err
+	}
+
+	for p.tok.Kind == Plus || p.tok.Kind == Minus {
+		op := p.tok.Kind
+		if err := p.advance(); err != nil {
+			return 0, err
+		}
+
+		w, err := p.term()
+		if err != nil {
+			return 0, err
+		}
+
+		if op == Plus {
+			v += w
+		} else {
+			v -= w
+		}
+	}
+
+	return v, nil
+}
+
+func (p *parser) term() (float64, error) {
+	v, err := p.unary()
+	if err != nil {
+		return 0, err
+	}
+
+	for p.tok.Kind == Star || p.tok.Kind == Slash {
+		op := p.tok
+		if err := p.advance(); err != nil {
+			return 0, err
+		}
+
+		w, err := p.unary()
+		if err != nil {
+			return 0, err
+		}
+
+		switch {
+		case op.Kind == Star:
+			v *= w
+		case w == 0:
+			return 0, fmt.Errorf("position %d: %w", op.Pos, ErrDivisionByZero)
+		default:
+			v /= w
+		}
+	}
+
+	return v, nil
+}
+
+func (p *parser) unary() (float64, error) {
+	if p.tok.Kind != Minus {
+		return p.power()
+	}
+
+	if err := p.advance(); err != nil {
+		return 0, err
+	}
+
+	v, err := p.unary()
+	return -v, err
+}
+
+// power binds tighter than a unary minus on its left, so -2^2 is -4, but
+// its exponent is a unary, so 2^-1 is 0.5, and 2^3^2 is 2^(3^2)
+func (p *parser) power() (float64, error) {
+	base, err := p.primary()
+	if err != nil || p.tok.Kind != Caret {
+		return base, err
+	}
+
+	if err := p.advance(); err != nil {
+		return 0, err
+	}
+
+	exp, err := p.unary()
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Pow(base, exp), nil
+}
+
+func (p *parser) primary() (float64, error) {
+	switch p.tok.Kind {
+	case Number:
+		v := p.tok.Value
+		return v, p.advance()
+
+	case LParen:
+		if err := p.advance(); err != nil {
+			return 0, err
+		}
+
+		v, err := p.expr()
+		if err != nil {
+			return 0, err
+		}
+
+		if p.tok.Kind != RParen {
+			return 0, p.unexpected(")")
+		}
+
+		return v, p.advance()
+
+	default:
+		return 0, p.unexpected("a number or (")
+	}
+}
@@ -0,0 +1,131 @@
+package archiveseq
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"time"
+)
+
+// ErrEntryClosed is returned when the content of a tar entry is opened or
+// read after the iteration has moved on to the next entry
+var ErrEntryClosed = errors.New("entry is no longer the current entry of the archive")
+
+// Entry is a file, directory, or link in an archive. Its content is only read
+// when it is opened, so entries which are not needed cost nothing more than
+// their header.
+type Entry struct {
+	Name    string
+	Size    int64
+	Mode    fs.FileMode
+	ModTime time.Time
+
+	open func() (io.ReadCloser, error)
+}
+
+// Open returns a reader over the content of the entry, which must be closed
+func (e Entry) Open() (io.ReadCloser, error) {
+	return e.open()
+}
+
+// IsDir reports whether the entry is a directory
+func (e Entry) IsDir() bool {
+	return e.Mode.IsDir()
+}
+
+// Tar returns an iterator over the entries of the tar archive read from r. A
+// tar archive is read front to back, so the content of an entry can only be
+// read until the iteration moves on, after which its reader returns
+// ErrEntryClosed. Wrap r with gzip.NewReader to read a .tar.gz archive.
+// Reading stops at the first error, which is yielded along with an empty
+// entry.
+func Tar(r io.Reader) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		tr := tar.NewReader(r)
+
+		for i := 0; ; i++ {
+			hdr, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(Entry{}, fmt.Errorf("failed to read entry %d: %w", i, err))
+				return
+			}
+
+			current := &tarEntry{tr: tr}
+
+			entry := Entry{
+				Name:    hdr.Name,
+				Size:    hdr.Size,
+				Mode:    hdr.FileInfo().Mode(),
+				ModTime: hdr.ModTime,
+				open:    current.open,
+			}
+
+			ok := yield(entry, nil)
+			current.closed = true
+
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// tarEntry reads the content of the current entry of a tar archive, until
+// the iteration moves on
+type tarEntry struct {
+	tr     *tar.Reader
+	closed bool
+}
+
+func (e *tarEntry) open() (io.ReadCloser, error) {
+	if e.closed {
+		return nil, ErrEntryClosed
+	}
+
+	return io.NopCloser(e), nil
+}
+
+func (e *tarEntry) Read(p []byte) (int, error) {
+	if e.closed {
+		return 0, ErrEntryClosed
+	}
+
+	return e.tr.Read(p)
+}
+
+// Zip returns an iterator over the entries of the zip archive of the given
+// size read from r. The entries are listed in the central directory at the
+// end of the archive, so only the directory and the content of the entries
+// which are opened are read, and an entry may still be opened after the
+// iteration has moved on. If the archive cannot be read, the error is
+// yielded along with an empty entry.
+func Zip(r io.ReaderAt, size int64) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		zr, err := zip.NewReader(r, size)
+		if err != nil {
+			yield(Entry{}, fmt.Errorf("failed to read zip: %w", err))
+			return
+		}
+
+		for _, f := range zr.File {
+			entry := Entry{
+				Name:    f.Name,
+				Size:    int64(f.UncompressedSize64),
+				Mode:    f.Mode(),
+				ModTime: f.Modified,
+				open:    f.Open,
+			}
+
+			if !yield(entry, nil) {
+				return
+			}
+		}
+	}
+}
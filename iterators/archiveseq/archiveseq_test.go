@@ -0,0 +1,208 @@
+package archiveseq
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"iter"
+	"maps"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+var files = map[string]string{
+	"courses/1.json": `{"id":1}`,
+	"courses/2.json": `{"id":2}`,
+	"README.md":      "# Courses",
+}
+
+// names are the files in the order they are written
+var names = []string{"README.md", "courses/1.json", "courses/2.json"}
+
+func tarArchive(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+
+	for _, name := range names {
+		err := w.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(files[name]))})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = io.WriteString(w, files[name])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func zipArchive(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+
+	for _, name := range names {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = io.WriteString(f, files[name])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// read returns the content of every entry of seq, read while it is current
+func read(t *testing.T, seq iter.Seq2[Entry, error]) map[string]string {
+	t.Helper()
+
+	contents := make(map[string]string)
+	for entry, err := range seq {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if entry.Size != int64(len(files[entry.Name])) {
+			t.Fatalf("expected %s to have %d bytes, got %d", entry.Name, len(files[entry.Name]), entry.Size)
+		}
+
+		rc, err := entry.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		contents[entry.Name] = string(b)
+	}
+
+	return contents
+}
+
+func TestTar(t *testing.T) {
+	contents := read(t, Tar(bytes.NewReader(tarArchive(t))))
+	if !maps.Equal(contents, files) {
+		t.Fatalf("expected %q, got %q", files, contents)
+	}
+}
+
+func TestZip(t *testing.T) {
+	data := zipArchive(t)
+
+	contents := read(t, Zip(bytes.NewReader(data), int64(len(data))))
+	if !maps.Equal(contents, files) {
+		t.Fatalf("expected %q, got %q", files, contents)
+	}
+}
+
+func TestTarEntryClosed(t *testing.T) {
+	var entries []Entry
+	for entry, err := range Tar(bytes.NewReader(tarArchive(t))) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if len(entries) != len(names) {
+		t.Fatalf("expected %d entries, got %d", len(names), len(entries))
+	}
+
+	_, err := entries[0].Open()
+	if !errors.Is(err, ErrEntryClosed) {
+		t.Fatalf("expected ErrEntryClosed, got %v", err)
+	}
+}
+
+func TestZipOpenLater(t *testing.T) {
+	data := zipArchive(t)
+
+	var entries []Entry
+	for entry, err := range Zip(bytes.NewReader(data), int64(len(data))) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	rc, err := entries[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+
+	b, err := io.ReadAll(rc)
+	if err != nil || string(b) != files[names[0]] {
+		t.Fatalf("expected %q, got %q, %v", files[names[0]], b, err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	data := tarArchive(t)
+
+	var (
+		n   int
+		err error
+	)
+
+	// Every header and content is padded to 512 bytes, so the second header
+	// starts at 1024
+	for _, err = range Tar(bytes.NewReader(data[:1024+100])) {
+		if err != nil {
+			break
+		}
+
+		n++
+	}
+
+	if err == nil || n != 1 {
+		t.Fatalf("expected an error after one entry, got %d entries and %v", n, err)
+	}
+
+	for _, err = range Zip(bytes.NewReader([]byte("not a zip")), 9) {
+	}
+
+	if !errors.Is(err, zip.ErrFormat) {
+		t.Fatalf("expected zip.ErrFormat, got %v", err)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	tarData, zipData := tarArchive(t), zipArchive(t)
+
+	newTar := func() iter.Seq2[Entry, error] { return Tar(bytes.NewReader(tarData)) }
+	newZip := func() iter.Seq2[Entry, error] { return Zip(bytes.NewReader(zipData), int64(len(zipData))) }
+
+	seqtest.AssertStopsEarly2(t, len(names), newTar)
+	seqtest.AssertNoYieldAfterFalse2(t, len(names), newTar)
+	seqtest.AssertStopsEarly2(t, len(names), newZip)
+	seqtest.AssertNoYieldAfterFalse2(t, len(names), newZip)
+}
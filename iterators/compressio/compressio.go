@@ -0,0 +1,151 @@
+package compressio
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Format is a compression format
+type Format int
+
+const (
+	// None reads and writes data as it is
+	None Format = iota
+	Gzip
+	Zstd
+)
+
+func (f Format) String() string {
+	switch f {
+	case None:
+		return "none"
+	case Gzip:
+		return "gzip"
+	case Zstd:
+		return "zstd"
+	}
+
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// FormatOf returns the format of a file, based on the extension of its name:
+// .gz for Gzip, .zst for Zstd, and None for anything else
+func FormatOf(name string) Format {
+	switch filepath.Ext(name) {
+	case ".gz":
+		return Gzip
+	case ".zst":
+		return Zstd
+	}
+
+	return None
+}
+
+// NewReader returns a reader which decompresses the data read from r in the
+// given format. Closing it releases the decompressor, but does not close r.
+func NewReader(r io.Reader, f Format) (io.ReadCloser, error) {
+	switch f {
+	case None:
+		return io.NopCloser(r), nil
+	case Gzip:
+		return gzip.NewReader(r)
+	case Zstd:
+		// Decoding in the background would buffer blocks ahead of the reader,
+		// which a stream read one line at a time has no use for
+		dec, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+
+		return dec.IOReadCloser(), nil
+	}
+
+	return nil, fmt.Errorf("unknown compression format %v", f)
+}
+
+// NewWriter returns a writer which compresses the data written to it in the
+// given format before writing it to w. It must be closed to write the end of
+// the compressed data, which does not close w.
+func NewWriter(w io.Writer, f Format) (io.WriteCloser, error) {
+	switch f {
+	case None:
+		return nopWriteCloser{w}, nil
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case Zstd:
+		return zstd.NewWriter(w)
+	}
+
+	return nil, fmt.Errorf("unknown compression format %v", f)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// Open opens the file at path for reading, and decompresses it in the format
+// given by the extension of its name. Closing the reader closes the file.
+//
+//	f, err := compressio.Open("courses.ndjson.gz")
+//	...
+//	defer f.Close()
+//
+//	for course, err := range jsonseq.Lines[db.Course](f) {
+func Open(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := NewReader(f, FormatOf(path))
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return &file{Reader: r, closers: []io.Closer{r, f}}, nil
+}
+
+// Create creates the file at path, and compresses what is written to it in
+// the format given by the extension of its name. Closing the writer finishes
+// the compressed data and closes the file, and its error must be checked.
+func Create(path string) (io.WriteCloser, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := NewWriter(f, FormatOf(path))
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return &file{Writer: w, closers: []io.Closer{w, f}}, nil
+}
+
+// file is a compressed file, which closes the compressor before the file
+type file struct {
+	io.Reader
+	io.Writer
+	closers []io.Closer
+}
+
+func (f *file) Close() error {
+	var errs []error
+	for _, c := range f.closers {
+		errs = append(errs, c.Close())
+	}
+
+	return errors.Join(errs...)
+}
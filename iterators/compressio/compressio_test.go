@@ -0,0 +1,129 @@
+package compressio
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatOf(t *testing.T) {
+	tests := map[string]Format{
+		"courses.ndjson":     None,
+		"courses.ndjson.gz":  Gzip,
+		"courses.csv.zst":    Zstd,
+		"archive.tar.gz":     Gzip,
+		"gz":                 None,
+		"courses.zst/readme": None,
+	}
+
+	for name, expected := range tests {
+		if f := FormatOf(name); f != expected {
+			t.Errorf("expected %s to be %v, got %v", name, expected, f)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	data := strings.Repeat(`{"id":1,"name":"Chem-1","university":"UCSF"}`+"\n", 1000)
+
+	for _, f := range []Format{None, Gzip, Zstd} {
+		t.Run(f.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+
+			w, err := NewWriter(&buf, f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = io.WriteString(w, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = w.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if f != None && buf.Len() >= len(data)/10 {
+				t.Errorf("expected repetitive data to compress, got %d of %d bytes", buf.Len(), len(data))
+			}
+
+			r, err := NewReader(&buf, f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			b, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(b) != data {
+				t.Fatalf("expected %d bytes back, got %d", len(data), len(b))
+			}
+		})
+	}
+}
+
+func TestCreateOpen(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{"courses.ndjson", "courses.ndjson.gz", "courses.ndjson.zst"} {
+		path := filepath.Join(dir, name)
+
+		w, err := Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = io.WriteString(w, "one\ntwo\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = w.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(b) != "one\ntwo\n" {
+			t.Fatalf("expected %s to hold what was written, got %q", name, b)
+		}
+	}
+}
+
+func TestOpenNotCompressed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "courses.ndjson.gz")
+
+	err := os.WriteFile(path, []byte("this file is not compressed"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Open(path)
+	if !errors.Is(err, gzip.ErrHeader) {
+		t.Fatalf("expected gzip.ErrHeader, got %v", err)
+	}
+}
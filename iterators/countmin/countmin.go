@@ -0,0 +1,198 @@
+// Package countmin counts how often the keys of a stream occur, approximately,
+// in a fixed amount of memory, with a count-min sketch. A map of exact counts
+// grows with the number of distinct keys, while a sketch is a table of
+// counters whose size is chosen up front, and which never undercounts a key,
+// but may overcount it by the occurrences of other keys which share its
+// counters.
+package countmin
+
+import (
+	"cmp"
+	"iter"
+	"math"
+
+	"github.com/manedurphy/golang-university/iterators/itertools"
+)
+
+// Sketch is a count-min sketch: depth rows of width counters. Each row hashes
+// a key to one of its counters, with a hash of its own, and every occurrence
+// of the key adds to its counter in every row. A counter also holds the
+// counts of the other keys which hash to it, so every row overcounts, and
+// the count of a key is the least of its counters, which is the row where it
+// collided the least.
+//
+// With a width of e/ε and a depth of ln(1/δ), the count of a key is more than
+// its true count plus ε times the total of all counts with a chance of at
+// most δ.
+type Sketch struct {
+	width    uint64
+	depth    int
+	counters []uint64
+	total    uint64
+}
+
+// New returns an empty sketch of depth rows of width counters. It panics if
+// either is less than 1.
+func New(width, depth int) *Sketch {
+	if width < 1 || depth < 1 {
+		panic("countmin: width and depth must be at least 1")
+	}
+
+	return &Sketch{
+		width:    uint64(width),
+		depth:    depth,
+		counters: make([]uint64, width*depth),
+	}
+}
+
+// NewWithError returns an empty sketch whose counts are more than ε times the
+// total count too high with a chance of at most δ. It panics unless both are
+// between 0 and 1.
+func NewWithError(epsilon, delta float64) *Sketch {
+	if epsilon <= 0 || epsilon >= 1 || delta <= 0 || delta >= 1 {
+		panic("countmin: epsilon and delta must be between 0 and 1")
+	}
+
+	width := int(math.Ceil(math.E / epsilon))
+	depth := int(math.Ceil(math.Log(1 / delta)))
+
+	return New(width, depth)
+}
+
+// Width returns the number of counters of each row
+func (s *Sketch) Width() int { return int(s.width) }
+
+// Depth returns the number of rows
+func (s *Sketch) Depth() int { return s.depth }
+
+// Size returns the size of the counters in bytes, which does not change with
+// the number of keys
+func (s *Sketch) Size() int { return len(s.counters) * 8 }
+
+// Total returns the sum of every count added to the sketch
+func (s *Sketch) Total() uint64 { return s.total }
+
+// Add adds n occurrences of key, and returns the count of key with them
+func (s *Sketch) Add(key string, n uint64) uint64 {
+	s.total += n
+
+	count := uint64(math.MaxUint64)
+	for i := range s.index(key) {
+		s.counters[i] += n
+		count = min(count, s.counters[i])
+	}
+
+	return count
+}
+
+// Count returns the count of key, which is at least the number of its
+// occurrences which were added
+func (s *Sketch) Count(key string) uint64 {
+	count := uint64(math.MaxUint64)
+	for i := range s.index(key) {
+		count = min(count, s.counters[i])
+	}
+
+	return count
+}
+
+// index yields the index of the counter of key in each row. The hashes of
+// the rows are derived from the two halves of a single hash of the key,
+// which works about as well as a hash per row, for the cost of one.
+func (s *Sketch) index(key string) iter.Seq[int] {
+	h := fnv1a(key)
+	h1, h2 := h&math.MaxUint32, h>>32|1
+
+	return func(yield func(int) bool) {
+		for row := range s.depth {
+			col := (h1 + uint64(row)*h2) % s.width
+			if !yield(row*int(s.width) + int(col)) {
+				return
+			}
+		}
+	}
+}
+
+// fnv1a returns the 64-bit FNV-1a hash of key. Unlike hash/fnv, it hashes a
+// string without converting it to a []byte, which would allocate.
+func fnv1a(key string) uint64 {
+	const (
+		offset = 14695981039346656037
+		prime  = 1099511628211
+	)
+
+	h := uint64(offset)
+	for i := range len(key) {
+		h ^= uint64(key[i])
+		h *= prime
+	}
+
+	return h
+}
+
+// Count is a key and its count
+type Count struct {
+	Key   string
+	Count uint64
+}
+
+// TopK adds every key of seq to s, and returns the k keys with the greatest
+// counts, from the greatest down. Besides the sketch, it only holds the k
+// keys with the greatest counts so far, so it finds the most common keys of
+// a stream with more distinct keys than fit in memory. Keys with the same
+// count are in alphabetical order.
+//
+// The counts are those of the sketch, which may be too high, so a key which
+// collides with a common one can take the place of a key which is more
+// common than it. A wider sketch makes it less likely.
+//
+// TopK panics if k is negative.
+func TopK(seq iter.Seq[string], k int, s *Sketch) []Count {
+	if k < 0 {
+		panic("countmin: TopK k must not be negative")
+	}
+
+	candidates := make(map[string]uint64, k+1)
+
+	for key := range seq {
+		count := s.Add(key, 1)
+
+		if _, ok := candidates[key]; ok || len(candidates) < k {
+			candidates[key] = count
+			continue
+		}
+
+		// The key replaces the least common candidate once it is more
+		// common. There are only k candidates, so they are searched rather
+		// than kept in a heap, whose order every count would change.
+		least, leastCount := "", uint64(math.MaxUint64)
+		for c, n := range candidates {
+			if n < leastCount || (n == leastCount && c > least) {
+				least, leastCount = c, n
+			}
+		}
+
+		if count > leastCount {
+			delete(candidates, least)
+			candidates[key] = count
+		}
+	}
+
+	// The counts of the candidates may have grown since they were last
+	// seen, from the keys they collide with
+	counts := func(yield func(Count) bool) {
+		for key := range candidates {
+			if !yield(Count{Key: key, Count: s.Count(key)}) {
+				return
+			}
+		}
+	}
+
+	return itertools.TopK(counts, k, func(a, b Count) bool {
+		if c := cmp.Compare(a.Count, b.Count); c != 0 {
+			return c < 0
+		}
+
+		return a.Key > b.Key
+	})
+}
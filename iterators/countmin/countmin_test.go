@@ -0,0 +1,145 @@
+package countmin
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// zipf returns n keys drawn from a Zipf distribution over keys distinct keys,
+// where a few keys are very common and most are rare, like the words of a
+// text. The seed is fixed, so the accuracy tests are deterministic.
+func zipf(n, keys int) []string {
+	z := rand.NewZipf(rand.New(rand.NewPCG(1, 2)), 1.2, 1, uint64(keys-1))
+
+	stream := make([]string, n)
+	for i := range stream {
+		stream[i] = fmt.Sprintf("key-%d", z.Uint64())
+	}
+
+	return stream
+}
+
+// exact returns the exact count of every key of stream
+func exact(stream []string) map[string]uint64 {
+	counts := make(map[string]uint64)
+	for _, key := range stream {
+		counts[key]++
+	}
+
+	return counts
+}
+
+func TestSketchExact(t *testing.T) {
+	// Wide enough that the few keys are unlikely to collide in every row
+	s := New(1024, 4)
+
+	s.Add("Chem-1", 3)
+	s.Add("Physics-1", 1)
+	if got := s.Add("Chem-1", 2); got != 5 {
+		t.Fatalf("expected Add to return 5, got %d", got)
+	}
+
+	for key, expected := range map[string]uint64{"Chem-1": 5, "Physics-1": 1, "Calculus-1": 0} {
+		if got := s.Count(key); got != expected {
+			t.Fatalf("%s: expected %d, got %d", key, expected, got)
+		}
+	}
+
+	if s.Total() != 6 {
+		t.Fatalf("expected a total of 6, got %d", s.Total())
+	}
+}
+
+func TestSketchAccuracy(t *testing.T) {
+	const (
+		epsilon = 0.001
+		delta   = 0.01
+	)
+
+	stream := zipf(200000, 50000)
+	counts := exact(stream)
+
+	s := NewWithError(epsilon, delta)
+	for _, key := range stream {
+		s.Add(key, 1)
+	}
+
+	if s.Width() != 2719 || s.Depth() != 5 {
+		t.Fatalf("expected a sketch of 5 rows of 2719 counters, got %d of %d", s.Depth(), s.Width())
+	}
+
+	// A count is never too low, and is more than ε times the total too high
+	// for a share of δ of the keys at most
+	bound := uint64(epsilon * float64(s.Total()))
+
+	var over int
+	for key, n := range counts {
+		got := s.Count(key)
+		if got < n {
+			t.Fatalf("%s: counted %d, which is less than its %d occurrences", key, got, n)
+		}
+
+		if got-n > bound {
+			over++
+		}
+	}
+
+	if share := float64(over) / float64(len(counts)); share > delta {
+		t.Fatalf("expected at most %.0f%% of the counts to be more than %d too high, got %.2f%%", 100*delta, bound, 100*share)
+	}
+
+	// The sketch is a fraction of the size of the map
+	if s.Size() >= len(counts)*16 {
+		t.Fatalf("expected the sketch to be smaller than the %d counts, got %d bytes", len(counts), s.Size())
+	}
+}
+
+func TestTopK(t *testing.T) {
+	stream := zipf(200000, 50000)
+	counts := exact(stream)
+
+	keys := slices.SortedFunc(maps.Keys(counts), func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), strings.Compare(a, b))
+	})
+
+	got := TopK(slices.Values(stream), 10, NewWithError(0.001, 0.01))
+	if len(got) != 10 {
+		t.Fatalf("expected 10 keys, got %v", got)
+	}
+
+	// The most common keys of a Zipf distribution are far apart, so the
+	// sketch finds them all, in order, with counts which are close
+	for i, c := range got {
+		if c.Key != keys[i] {
+			t.Fatalf("expected %s in place %d, got %v", keys[i], i+1, got)
+		}
+
+		if n := counts[c.Key]; c.Count < n || float64(c.Count-n) > 0.001*float64(len(stream)) {
+			t.Fatalf("%s: expected a count close to %d, got %d", c.Key, n, c.Count)
+		}
+	}
+}
+
+func TestTopKTies(t *testing.T) {
+	stream := []string{"b", "a", "c", "a", "b", "c", "d"}
+
+	got := TopK(slices.Values(stream), 2, New(1024, 4))
+	if expected := []Count{{"a", 2}, {"b", 2}}; !slices.Equal(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func BenchmarkAdd(b *testing.B) {
+	stream := zipf(100000, 50000)
+	s := NewWithError(0.001, 0.01)
+	b.ReportAllocs()
+
+	for i := range b.N {
+		s.Add(stream[i%len(stream)], 1)
+	}
+}
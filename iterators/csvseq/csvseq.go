@@ -0,0 +1,211 @@
+package csvseq
+
+import (
+	"encoding"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// field is a field of the struct records are decoded into, and the column it
+// is decoded from
+type field struct {
+	index  int
+	name   string
+	column int
+}
+
+// Records returns an iterator over the records of the CSV data read from r.
+// Each record is a new slice, so it may be kept after the iteration moves on.
+// Reading stops at the first error, such as a malformed line or a record with
+// a different number of fields than the first one, which is yielded along
+// with a nil record.
+func Records(r io.Reader) iter.Seq2[[]string, error] {
+	return records(csv.NewReader(r))
+}
+
+// records reads the remaining records of reader
+func records(reader *csv.Reader) iter.Seq2[[]string, error] {
+	return func(yield func([]string, error) bool) {
+		for {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if !yield(record, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Decode returns an iterator which decodes the records of the CSV data read
+// from r into values of the struct type T. The first record is the header,
+// which names the column of each field. A field is decoded from the column
+// named by its csv tag, or by its name if it has none, compared without
+// regard to case. Fields tagged with "-" and columns without a field are
+// ignored, while a field without a column is an error.
+//
+// Fields may be strings, booleans, integers, floats, or implement
+// encoding.TextUnmarshaler.
+//
+//	type Course struct {
+//		ID   int    `csv:"id"`
+//		Name string `csv:"name"`
+//	}
+//
+//	for course, err := range csvseq.Decode[Course](f) {
+func Decode[T any](r io.Reader) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		reader := csv.NewReader(r)
+
+		header, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		fields, err := mapFields(reflect.TypeFor[T](), header)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		for record, err := range records(reader) {
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			var val T
+			v := reflect.ValueOf(&val).Elem()
+
+			for _, f := range fields {
+				err = set(v.Field(f.index), record[f.column])
+				if err != nil {
+					line, _ := reader.FieldPos(f.column)
+					yield(zero, fmt.Errorf("line %d, column %q: %w", line, f.name, err))
+					return
+				}
+			}
+
+			if !yield(val, nil) {
+				return
+			}
+		}
+	}
+}
+
+// mapFields returns the fields of the struct type t, along with the column
+// of the header each of them is decoded from
+func mapFields(t reflect.Type, header []string) ([]field, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot decode records into %s, which is not a struct", t)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	var fields []field
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name := sf.Tag.Get("csv")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		if !supported(sf.Type) {
+			return nil, fmt.Errorf("cannot decode field %s of unsupported type %s", sf.Name, sf.Type)
+		}
+
+		column, ok := columns[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("header has no column %q for field %s", name, sf.Name)
+		}
+
+		fields = append(fields, field{index: i, name: name, column: column})
+	}
+
+	return fields, nil
+}
+
+// set decodes s into the field v
+func set(v reflect.Value, s string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	}
+
+	return nil
+}
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// supported reports whether set can decode into a field of type t
+func supported(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,156 @@
+package csvseq
+
+import (
+	"encoding/csv"
+	"errors"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+type course struct {
+	ID       int     `csv:"id"`
+	Name     string  `csv:"name"`
+	Credits  float64 `csv:"credits"`
+	Elective bool
+	Start    time.Time `csv:"start"`
+	Internal string    `csv:"-"`
+}
+
+func TestRecords(t *testing.T) {
+	var records [][]string
+	for record, err := range Records(strings.NewReader("a,b\n\"c,d\",e\n")) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		records = append(records, record)
+	}
+
+	if len(records) != 2 || !slices.Equal(records[0], []string{"a", "b"}) || !slices.Equal(records[1], []string{"c,d", "e"}) {
+		t.Fatalf("expected both records, got %q", records)
+	}
+}
+
+func TestRecordsError(t *testing.T) {
+	var (
+		n   int
+		err error
+	)
+
+	for _, err = range Records(strings.NewReader("a,b\nc\nd,e\n")) {
+		if err != nil {
+			break
+		}
+
+		n++
+	}
+
+	if !errors.Is(err, csv.ErrFieldCount) {
+		t.Fatalf("expected csv.ErrFieldCount, got %v", err)
+	}
+
+	if n != 1 {
+		t.Fatalf("expected one record before the error, got %d", n)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	data := "Name,ID,extra,elective,credits,start\n" +
+		"Chem-1,1,x,true,4.5,2024-09-01T00:00:00Z\n" +
+		"Physics-1,2,y,false,3,2025-01-15T00:00:00Z\n"
+
+	var courses []course
+	for c, err := range Decode[course](strings.NewReader(data)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		courses = append(courses, c)
+	}
+
+	expected := []course{
+		{ID: 1, Name: "Chem-1", Credits: 4.5, Elective: true, Start: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: 2, Name: "Physics-1", Credits: 3, Start: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)},
+	}
+
+	if !slices.Equal(courses, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, courses)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{
+			name: "missing column",
+			data: "id,name,credits,elective\n",
+			err:  `header has no column "start" for field Start`,
+		},
+		{
+			name: "invalid value",
+			data: "id,name,credits,elective,start\n1,a,1,true,2024-09-01T00:00:00Z\nfoo,b,1,true,2024-09-01T00:00:00Z\n",
+			err:  `line 3, column "id": strconv.ParseInt: parsing "foo": invalid syntax`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var err error
+			for _, err = range Decode[course](strings.NewReader(test.data)) {
+				if err != nil {
+					break
+				}
+			}
+
+			if err == nil || err.Error() != test.err {
+				t.Fatalf("expected %q, got %v", test.err, err)
+			}
+		})
+	}
+
+	var err error
+	for _, err = range Decode[struct{ C complex128 }](strings.NewReader("c\n1\n")) {
+	}
+
+	if err == nil || !strings.Contains(err.Error(), "unsupported type complex128") {
+		t.Fatalf("expected an unsupported type error, got %v", err)
+	}
+
+	for _, err = range Decode[int](strings.NewReader("n\n1\n")) {
+	}
+
+	if err == nil {
+		t.Fatal("expected an error for a type which is not a struct")
+	}
+}
+
+func TestRecordsConformance(t *testing.T) {
+	newSeq := func() iter.Seq2[[]string, error] { return Records(strings.NewReader("a,b\nc,d\n")) }
+
+	seqtest.AssertYields2(t, newSeq(), [][]string{{"a", "b"}, {"c", "d"}}, make([]error, 2))
+	seqtest.AssertStopsEarly2(t, 2, newSeq)
+	seqtest.AssertNoYieldAfterFalse2(t, 2, newSeq)
+}
+
+func TestDecodeConformance(t *testing.T) {
+	type row struct {
+		ID   int    `csv:"id"`
+		Name string `csv:"name"`
+	}
+
+	newSeq := func() iter.Seq2[row, error] {
+		return Decode[row](strings.NewReader("id,name\n1,Chem-1\n2,Physics-1\n"))
+	}
+
+	seqtest.AssertYields2(t, newSeq(), []row{{1, "Chem-1"}, {2, "Physics-1"}}, make([]error, 2))
+	seqtest.AssertStopsEarly2(t, 2, newSeq)
+	seqtest.AssertNoYieldAfterFalse2(t, 2, newSeq)
+}
@@ -0,0 +1,92 @@
+package fsseq
+
+import (
+	"io/fs"
+	"iter"
+	"slices"
+)
+
+// Option configures Walk
+type Option func(*walker)
+
+type walker struct {
+	skip    []func(p string, d fs.DirEntry) bool
+	onError func(p string, err error)
+}
+
+// SkipDirs skips the directories with any of the given names, along with
+// everything in them
+func SkipDirs(names ...string) Option {
+	return SkipDirFunc(func(_ string, d fs.DirEntry) bool {
+		return slices.Contains(names, d.Name())
+	})
+}
+
+// SkipDirFunc skips the directories for which skip returns true, along with
+// everything in them. Skipped directories are not yielded.
+func SkipDirFunc(skip func(p string, d fs.DirEntry) bool) Option {
+	return func(w *walker) {
+		w.skip = append(w.skip, skip)
+	}
+}
+
+// OnError calls fn with the path and error of every entry which cannot be
+// read, such as a directory without permission to list it. Without it, such
+// entries are skipped silently.
+func OnError(fn func(p string, err error)) Option {
+	return func(w *walker) {
+		w.onError = fn
+	}
+}
+
+// Walk returns an iterator over the path and entry of every file and
+// directory in the tree rooted at root, root included, in lexical order.
+// Directories are yielded before what they contain. Breaking out of the loop
+// stops the walk, so the rest of the tree is never read.
+//
+//	for p, d := range fsseq.Walk(os.DirFS("."), ".", fsseq.SkipDirs(".git")) {
+func Walk(fsys fs.FS, root string, opts ...Option) iter.Seq2[string, fs.DirEntry] {
+	w := &walker{}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return func(yield func(string, fs.DirEntry) bool) {
+		fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if w.onError != nil {
+					w.onError(p, err)
+				}
+
+				// An unreadable directory is reported a second time with its
+				// entry, which has already been yielded
+				if d != nil && d.IsDir() {
+					return fs.SkipDir
+				}
+
+				return nil
+			}
+
+			if d.IsDir() && p != root && w.skipped(p, d) {
+				return fs.SkipDir
+			}
+
+			if !yield(p, d) {
+				return fs.SkipAll
+			}
+
+			return nil
+		})
+	}
+}
+
+// skipped reports whether the directory at p is skipped by any option
+func (w *walker) skipped(p string, d fs.DirEntry) bool {
+	for _, skip := range w.skip {
+		if skip(p, d) {
+			return true
+		}
+	}
+
+	return false
+}
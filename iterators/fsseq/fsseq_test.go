@@ -0,0 +1,111 @@
+package fsseq
+
+import (
+	"errors"
+	"io/fs"
+	"iter"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+var fsys = fstest.MapFS{
+	"go.mod":                       {},
+	".git/HEAD":                    {},
+	"iterators/01-basic/main.go":   {},
+	"iterators/02-range/main.go":   {},
+	"iterators/textio/textio.go":   {},
+	"generators/01-basic/main.go":  {},
+	"generators/testdata/input.go": {},
+}
+
+func walk(root string, opts ...Option) []string {
+	var paths []string
+	for p := range Walk(fsys, root, opts...) {
+		paths = append(paths, p)
+	}
+
+	return paths
+}
+
+func TestWalk(t *testing.T) {
+	paths := walk("iterators")
+
+	expected := []string{
+		"iterators",
+		"iterators/01-basic",
+		"iterators/01-basic/main.go",
+		"iterators/02-range",
+		"iterators/02-range/main.go",
+		"iterators/textio",
+		"iterators/textio/textio.go",
+	}
+
+	if !slices.Equal(paths, expected) {
+		t.Fatalf("expected %q, got %q", expected, paths)
+	}
+}
+
+func TestWalkSkip(t *testing.T) {
+	paths := walk(".", SkipDirs(".git", "testdata"), SkipDirFunc(func(p string, _ fs.DirEntry) bool {
+		return p == "iterators/textio"
+	}))
+
+	expected := []string{
+		".",
+		"generators",
+		"generators/01-basic",
+		"generators/01-basic/main.go",
+		"go.mod",
+		"iterators",
+		"iterators/01-basic",
+		"iterators/01-basic/main.go",
+		"iterators/02-range",
+		"iterators/02-range/main.go",
+	}
+
+	if !slices.Equal(paths, expected) {
+		t.Fatalf("expected %q, got %q", expected, paths)
+	}
+}
+
+func TestWalkBreak(t *testing.T) {
+	var paths []string
+	for p, d := range Walk(fsys, ".") {
+		paths = append(paths, p)
+
+		if d.Name() == "main.go" {
+			break
+		}
+	}
+
+	expected := []string{".", ".git", ".git/HEAD", "generators", "generators/01-basic", "generators/01-basic/main.go"}
+	if !slices.Equal(paths, expected) {
+		t.Fatalf("expected %q, got %q", expected, paths)
+	}
+}
+
+func TestWalkError(t *testing.T) {
+	var errs []error
+
+	paths := walk("missing", OnError(func(p string, err error) {
+		errs = append(errs, err)
+	}))
+
+	if len(paths) != 0 {
+		t.Fatalf("expected no paths, got %q", paths)
+	}
+
+	if len(errs) != 1 || !errors.Is(errs[0], fs.ErrNotExist) {
+		t.Fatalf("expected fs.ErrNotExist, got %v", errs)
+	}
+}
+
+func TestWalkConformance(t *testing.T) {
+	newSeq := func() iter.Seq2[string, fs.DirEntry] { return Walk(fsys, "iterators") }
+
+	seqtest.AssertStopsEarly2(t, len(walk("iterators")), newSeq)
+	seqtest.AssertNoYieldAfterFalse2(t, len(walk("iterators")), newSeq)
+}
@@ -0,0 +1,61 @@
+package gobseq
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// Encode writes every value of seq to w as a gob stream, which Decode reads
+// back. The stream describes T once, before the first value, so it must be
+// read with a single decoder from the start: two streams written by separate
+// calls cannot be appended to each other and read as one. Spill each batch of
+// a pipeline to a file of its own instead.
+func Encode[T any](w io.Writer, seq iter.Seq[T]) error {
+	bw := bufio.NewWriter(w)
+	enc := gob.NewEncoder(bw)
+
+	n := 0
+	for val := range seq {
+		err := enc.Encode(val)
+		if err != nil {
+			return fmt.Errorf("failed to encode value %d: %w", n, err)
+		}
+
+		n++
+	}
+
+	return bw.Flush()
+}
+
+// Decode returns an iterator over the values of type T of the gob stream read
+// from r. Decoding stops at the first error, such as a stream which was cut
+// off in the middle of a value, which is yielded along with the zero value
+// of T.
+func Decode[T any](r io.Reader) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		dec := gob.NewDecoder(bufio.NewReader(r))
+
+		for i := 0; ; i++ {
+			var val T
+
+			err := dec.Decode(&val)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(zero, fmt.Errorf("failed to decode value %d: %w", i, err))
+				return
+			}
+
+			if !yield(val, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package gobseq
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+type checkpoint struct {
+	Stage string
+	Value int
+}
+
+func TestRoundTrip(t *testing.T) {
+	expected := []checkpoint{{"primes", 2}, {"primes", 3}, {"primes", 5}}
+
+	var buf bytes.Buffer
+	err := Encode(&buf, slices.Values(expected))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var values []checkpoint
+	for val, err := range Decode[checkpoint](&buf) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		values = append(values, val)
+	}
+
+	if !slices.Equal(values, expected) {
+		t.Fatalf("expected %v, got %v", expected, values)
+	}
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	err := Encode(&buf, slices.Values([]int{1, 2, 3}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var values []int
+	for val, err := range Decode[int](bytes.NewReader(buf.Bytes()[:buf.Len()-1])) {
+		if err != nil {
+			if !errors.Is(err, io.ErrUnexpectedEOF) {
+				t.Fatalf("expected io.ErrUnexpectedEOF, got %v", err)
+			}
+			break
+		}
+
+		values = append(values, val)
+	}
+
+	if !slices.Equal(values, []int{1, 2}) {
+		t.Fatalf("expected the values before the cut, got %v", values)
+	}
+}
+
+func TestDecodeWrongType(t *testing.T) {
+	var buf bytes.Buffer
+	err := Encode(&buf, slices.Values([]string{"a"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var n int
+	for _, err = range Decode[int](&buf) {
+		n++
+	}
+
+	if err == nil || n != 1 {
+		t.Fatalf("expected a single error, got %d values and %v", n, err)
+	}
+}
+
+func TestDecodeEmpty(t *testing.T) {
+	for val, err := range Decode[int](bytes.NewReader(nil)) {
+		t.Fatalf("expected no values from an empty stream, got %v, %v", val, err)
+	}
+}
+
+func TestDecodeConformance(t *testing.T) {
+	expected := []checkpoint{{"primes", 2}, {"primes", 3}, {"primes", 5}}
+
+	var buf bytes.Buffer
+	err := Encode(&buf, slices.Values(expected))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newSeq := func() iter.Seq2[checkpoint, error] { return Decode[checkpoint](bytes.NewReader(buf.Bytes())) }
+
+	seqtest.AssertYields2(t, newSeq(), expected, make([]error, len(expected)))
+	seqtest.AssertStopsEarly2(t, len(expected), newSeq)
+	seqtest.AssertNoYieldAfterFalse2(t, len(expected), newSeq)
+}
@@ -0,0 +1,97 @@
+package httpseq
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+)
+
+// Page is a page of results of a paginated API
+type Page[T any] struct {
+	Items []T
+	// Next is the URL of the next page, which may be relative to the URL of
+	// this page. It is empty on the last page.
+	Next string
+}
+
+// Paginate returns an iterator over the items of every page of a paginated
+// API, starting at firstURL and following the link to the next page which
+// parsePage finds in each response. Pages are fetched lazily, so a page is
+// only requested once the consumer has ranged over every item of the page
+// before it, and a consumer which stops early never requests the rest.
+//
+// A request which fails, a response without a 2xx status, or an error from
+// parsePage stops the iteration, and is yielded along with the zero value of
+// T. parsePage does not need to close the response's body.
+func Paginate[T any](ctx context.Context, client *http.Client, firstURL string, parsePage func(*http.Response) (Page[T], error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		visited := make(map[string]bool)
+
+		next := firstURL
+		for next != "" {
+			if visited[next] {
+				yield(zero, fmt.Errorf("page %s links back to a page which was already fetched", next))
+				return
+			}
+			visited[next] = true
+
+			page, base, err := fetch(ctx, client, next, parsePage)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			next = ""
+			if page.Next != "" {
+				// The next page may be given relative to the current one
+				u, err := base.Parse(page.Next)
+				if err != nil {
+					yield(zero, fmt.Errorf("invalid next page %q: %w", page.Next, err))
+					return
+				}
+
+				next = u.String()
+			}
+		}
+	}
+}
+
+// fetch requests a single page and parses it. It returns the page along with
+// the URL the page was served from, after any redirects.
+func fetch[T any](ctx context.Context, client *http.Client, rawURL string, parsePage func(*http.Response) (Page[T], error)) (Page[T], *url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return Page[T]{}, nil, fmt.Errorf("failed to create request for %s: %w", rawURL, err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Page[T]{}, nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return Page[T]{}, nil, fmt.Errorf("failed to fetch page %s: %s", rawURL, resp.Status)
+	}
+
+	page, err := parsePage(resp)
+	if err != nil {
+		return Page[T]{}, nil, fmt.Errorf("failed to parse page %s: %w", rawURL, err)
+	}
+
+	// Drain the body so that the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return page, resp.Request.URL, nil
+}
@@ -0,0 +1,151 @@
+package httpseq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// page is the body of a page served by newServer
+type page struct {
+	Items []int  `json:"items"`
+	Next  string `json:"next"`
+}
+
+func parsePage(resp *http.Response) (Page[int], error) {
+	var p page
+
+	err := json.NewDecoder(resp.Body).Decode(&p)
+	return Page[int]{Items: p.Items, Next: p.Next}, err
+}
+
+// newServer serves the pages from /pages/N, linking each page to the next
+// with a relative URL. It returns the server and the paths it served.
+func newServer(t *testing.T, pages [][]int) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var served []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = append(served, r.URL.Path)
+
+		n, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/pages/"))
+		if err != nil || n >= len(pages) {
+			http.NotFound(w, r)
+			return
+		}
+
+		p := page{Items: pages[n]}
+		if n+1 < len(pages) {
+			p.Next = strconv.Itoa(n + 1)
+		}
+
+		json.NewEncoder(w).Encode(p)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &served
+}
+
+func TestPaginate(t *testing.T) {
+	srv, served := newServer(t, [][]int{{1, 2}, {}, {3}, {4, 5}})
+
+	var items []int
+	for item, err := range Paginate(context.Background(), srv.Client(), srv.URL+"/pages/0", parsePage) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		items = append(items, item)
+	}
+
+	if !slices.Equal(items, []int{1, 2, 3, 4, 5}) {
+		t.Fatalf("expected the items of every page, got %v", items)
+	}
+
+	if !slices.Equal(*served, []string{"/pages/0", "/pages/1", "/pages/2", "/pages/3"}) {
+		t.Fatalf("expected every page to be requested once, got %v", *served)
+	}
+}
+
+func TestPaginateLazily(t *testing.T) {
+	srv, served := newServer(t, [][]int{{1, 2}, {3, 4}, {5, 6}})
+
+	for item, err := range Paginate(context.Background(), srv.Client(), srv.URL+"/pages/0", parsePage) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if item == 2 && len(*served) != 1 {
+			t.Fatalf("expected the second page not to be requested before the first one is consumed, got %v", *served)
+		}
+
+		if item == 3 {
+			break
+		}
+	}
+
+	if len(*served) != 2 {
+		t.Fatalf("expected the last page never to be requested, got %v", *served)
+	}
+}
+
+func TestPaginateErrors(t *testing.T) {
+	srv, _ := newServer(t, [][]int{{1}})
+
+	loop := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"items": [1], "next": "/again"}`)
+	}))
+	t.Cleanup(loop.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errParse := errors.New("bad page")
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		url   string
+		parse func(*http.Response) (Page[int], error)
+		n     int
+		err   string
+	}{
+		{name: "status", ctx: context.Background(), url: srv.URL + "/pages/7", parse: parsePage, err: "404 Not Found"},
+		{name: "parse", ctx: context.Background(), url: srv.URL + "/pages/0", parse: func(*http.Response) (Page[int], error) { return Page[int]{}, errParse }, err: "bad page"},
+		{name: "loop", ctx: context.Background(), url: loop.URL + "/again", parse: parsePage, n: 1, err: "links back to a page"},
+		{name: "cancelled", ctx: ctx, url: srv.URL + "/pages/0", parse: parsePage, err: "context canceled"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				n   int
+				err error
+			)
+
+			for _, err = range Paginate(test.ctx, srv.Client(), test.url, test.parse) {
+				if err != nil {
+					break
+				}
+
+				n++
+			}
+
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("expected an error containing %q, got %v", test.err, err)
+			}
+
+			if n != test.n {
+				t.Fatalf("expected %d items before the error, got %d", test.n, n)
+			}
+		})
+	}
+}
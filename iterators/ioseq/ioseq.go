@@ -0,0 +1,80 @@
+package ioseq
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"sync"
+)
+
+// pools holds a *sync.Pool of buffers for each chunk size, so iterating over
+// many readers with the same size reuses the same few buffers
+var pools sync.Map
+
+// getBuffer returns a buffer of size bytes from the pool for that size
+func getBuffer(size int) *[]byte {
+	p, _ := pools.LoadOrStore(size, &sync.Pool{
+		New: func() any {
+			buf := make([]byte, size)
+			return &buf
+		},
+	})
+
+	return p.(*sync.Pool).Get().(*[]byte)
+}
+
+// putBuffer returns a buffer to the pool for its size
+func putBuffer(buf *[]byte) {
+	p, ok := pools.Load(len(*buf))
+	if ok {
+		p.(*sync.Pool).Put(buf)
+	}
+}
+
+// Chunks returns an iterator over the content of r in chunks of size bytes,
+// the last of which may be shorter. Reading stops at the first error, which
+// is yielded along with a nil chunk, after any data read before it.
+//
+// The chunks share a single buffer, which is taken from a pool when the
+// iteration starts and put back when it ends, so reading a reader of any
+// size, or many readers one after the other, allocates next to nothing. The
+// iterator owns the buffer: a chunk is only valid until the loop moves on to
+// the next one, after which its content is overwritten. Use bytes.Clone to
+// keep a chunk for longer, and do not keep it after the loop either, since
+// the buffer may then be handed to another iteration.
+//
+//	h := sha256.New()
+//	for chunk, err := range ioseq.Chunks(f, 1<<20) {
+//		if err != nil {
+//			return err
+//		}
+//
+//		h.Write(chunk)
+//	}
+func Chunks(r io.Reader, size int) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		if size <= 0 {
+			yield(nil, fmt.Errorf("invalid chunk size %d", size))
+			return
+		}
+
+		buf := getBuffer(size)
+		defer putBuffer(buf)
+
+		for {
+			n, err := io.ReadFull(r, *buf)
+			if n > 0 && !yield((*buf)[:n], nil) {
+				return
+			}
+
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package ioseq
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"iter"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+func TestChunks(t *testing.T) {
+	tests := []struct {
+		data     string
+		size     int
+		expected []string
+	}{
+		{data: "", size: 4, expected: nil},
+		{data: "abcdefgh", size: 4, expected: []string{"abcd", "efgh"}},
+		{data: "abcdefghij", size: 4, expected: []string{"abcd", "efgh", "ij"}},
+		{data: "ab", size: 4, expected: []string{"ab"}},
+	}
+
+	for _, test := range tests {
+		// OneByteReader checks that short reads still fill whole chunks
+		var chunks []string
+		for chunk, err := range Chunks(iotest.OneByteReader(strings.NewReader(test.data)), test.size) {
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			chunks = append(chunks, string(chunk))
+		}
+
+		if len(chunks) != len(test.expected) || strings.Join(chunks, "|") != strings.Join(test.expected, "|") {
+			t.Errorf("expected %q in chunks of %d to be %q, got %q", test.data, test.size, test.expected, chunks)
+		}
+	}
+}
+
+func TestChunksShareBuffer(t *testing.T) {
+	var chunks [][]byte
+	for chunk, err := range Chunks(strings.NewReader("abcdef"), 3) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		chunks = append(chunks, chunk)
+	}
+
+	// Both chunks point to the same buffer, which holds the last one
+	if string(chunks[0]) != "def" || &chunks[0][0] != &chunks[1][0] {
+		t.Fatalf("expected the chunks to share a buffer, got %q", chunks)
+	}
+}
+
+func TestChunksError(t *testing.T) {
+	errRead := errors.New("disk on fire")
+
+	r := io.MultiReader(strings.NewReader("abcde"), iotest.ErrReader(errRead))
+
+	var (
+		data, chunk []byte
+		err         error
+	)
+
+	for chunk, err = range Chunks(r, 4) {
+		if err != nil {
+			break
+		}
+
+		data = append(data, chunk...)
+	}
+
+	if !errors.Is(err, errRead) {
+		t.Fatalf("expected the read error, got %v", err)
+	}
+
+	if !bytes.Equal(data, []byte("abcde")) {
+		t.Fatalf("expected the data before the error, got %q", data)
+	}
+
+	for _, err = range Chunks(r, 0) {
+	}
+
+	if err == nil {
+		t.Fatal("expected an error for a chunk size of 0")
+	}
+}
+
+func TestChunksPool(t *testing.T) {
+	const (
+		runs = 100
+		size = 64 << 10
+	)
+
+	data := bytes.Repeat([]byte("x"), 1<<20)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	for range runs {
+		for range Chunks(bytes.NewReader(data), size) {
+		}
+	}
+
+	runtime.ReadMemStats(&after)
+
+	// Without the pool, every run would allocate a buffer of its own
+	allocated := after.TotalAlloc - before.TotalAlloc
+	if allocated > runs*size/2 {
+		t.Fatalf("expected the buffers to come from the pool, got %d bytes allocated in %d runs", allocated, runs)
+	}
+}
+
+// The chunks share a buffer, so only how Chunks stops is checked here
+func TestChunksConformance(t *testing.T) {
+	newSeq := func() iter.Seq2[[]byte, error] { return Chunks(strings.NewReader("abcdefghij"), 4) }
+
+	seqtest.AssertStopsEarly2(t, 3, newSeq)
+	seqtest.AssertNoYieldAfterFalse2(t, 3, newSeq)
+}
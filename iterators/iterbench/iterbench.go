@@ -0,0 +1,52 @@
+package iterbench
+
+import (
+	"context"
+	"iter"
+)
+
+// The producers below all hand out the numbers from 0 to n-1, each in a
+// different style, so that the cost of the style itself can be compared with
+// a plain loop over the numbers
+
+// Callback calls fn with every number until it returns false, which is how
+// iterators were written before range-over-func
+func Callback(n int, fn func(int) bool) {
+	for i := range n {
+		if !fn(i) {
+			return
+		}
+	}
+}
+
+// Seq returns a range-over-func iterator over the numbers
+func Seq(n int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := range n {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+// Channel sends the numbers on a channel with the given buffer size from a
+// goroutine, like the generators of the generators course. The goroutine
+// stops once ctx is done.
+func Channel(ctx context.Context, n, buffer int) <-chan int {
+	ch := make(chan int, buffer)
+
+	go func() {
+		defer close(ch)
+
+		for i := range n {
+			select {
+			case ch <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
@@ -0,0 +1,98 @@
+package iterbench
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"testing"
+)
+
+// sink keeps the compiler from optimizing the loops away
+var sink int
+
+// counts are the numbers of elements every style is measured with, from a
+// loop whose setup dominates to one whose elements do
+var counts = []int{10, 1000, 100000}
+
+// styles consume n numbers in every style, and return their sum
+var styles = []struct {
+	name string
+	sum  func(n int) int
+}{
+	{"loop", func(n int) int {
+		sum := 0
+		for v := range n {
+			sum += v
+		}
+		return sum
+	}},
+	{"callback", func(n int) int {
+		sum := 0
+		Callback(n, func(v int) bool {
+			sum += v
+			return true
+		})
+		return sum
+	}},
+	{"range-over-func", func(n int) int {
+		sum := 0
+		for v := range Seq(n) {
+			sum += v
+		}
+		return sum
+	}},
+	{"pull", func(n int) int {
+		next, stop := iter.Pull(Seq(n))
+		defer stop()
+
+		sum := 0
+		for {
+			v, ok := next()
+			if !ok {
+				return sum
+			}
+			sum += v
+		}
+	}},
+	{"channel", func(n int) int {
+		sum := 0
+		for v := range Channel(context.Background(), n, 0) {
+			sum += v
+		}
+		return sum
+	}},
+	{"channel-buffered", func(n int) int {
+		sum := 0
+		for v := range Channel(context.Background(), n, 64) {
+			sum += v
+		}
+		return sum
+	}},
+}
+
+func TestStyles(t *testing.T) {
+	for _, s := range styles {
+		for _, n := range counts {
+			if sum := s.sum(n); sum != n*(n-1)/2 {
+				t.Fatalf("%s: expected the sum of %d numbers to be %d, got %d", s.name, n, n*(n-1)/2, sum)
+			}
+		}
+	}
+}
+
+// BenchmarkIteration consumes n numbers per operation in every style, and
+// reports the time per element along with the time per operation. The loop
+// without a producer is the floor the other styles are compared with.
+func BenchmarkIteration(b *testing.B) {
+	for _, s := range styles {
+		for _, n := range counts {
+			b.Run(fmt.Sprintf("%s/n=%d", s.name, n), func(b *testing.B) {
+				for range b.N {
+					sink += s.sum(n)
+				}
+
+				b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*n), "ns/elem")
+			})
+		}
+	}
+}
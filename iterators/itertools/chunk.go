@@ -0,0 +1,35 @@
+package itertools
+
+import "iter"
+
+// Chunk returns an iterator which yields the values of seq in slices of size
+// values, except for the last one, which holds what is left. It is the
+// slices.Chunk of an iterator. Every chunk is a new slice, so the loop body
+// may keep it, or hand it to another goroutine.
+//
+// Chunk panics if size is less than 1.
+func Chunk[T any](seq iter.Seq[T], size int) iter.Seq[[]T] {
+	if size < 1 {
+		panic("itertools: Chunk size must be at least 1")
+	}
+
+	return func(yield func([]T) bool) {
+		chunk := make([]T, 0, size)
+
+		for v := range seq {
+			chunk = append(chunk, v)
+			if len(chunk) < size {
+				continue
+			}
+
+			if !yield(chunk) {
+				return
+			}
+			chunk = make([]T, 0, size)
+		}
+
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package itertools
+
+import (
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+func TestChunk(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []int
+		size     int
+		expected [][]int
+	}{
+		{"even", []int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {3, 4}}},
+		{"remainder", []int{1, 2, 3, 4, 5}, 2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{"larger than seq", []int{1, 2}, 5, [][]int{{1, 2}}},
+		{"empty", nil, 3, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slices.Collect(Chunk(slices.Values(tt.values), tt.size))
+
+			if !slices.EqualFunc(got, tt.expected, slices.Equal) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestChunkKeep(t *testing.T) {
+	// A chunk which is kept is not overwritten by the next one
+	chunks := slices.Collect(Chunk(slices.Values([]int{1, 2, 3, 4}), 2))
+	if !slices.Equal(chunks[0], []int{1, 2}) {
+		t.Fatalf("expected the first chunk to be kept, got %v", chunks[0])
+	}
+}
+
+func TestChunkConformance(t *testing.T) {
+	seqtest.AssertStopsEarly(t, 2, func() iter.Seq[[]int] {
+		return Chunk(slices.Values([]int{1, 2, 3, 4, 5, 6}), 2)
+	})
+
+	seqtest.AssertCleanupRuns(t, 2, func() (iter.Seq[[]int], func() bool) {
+		seq, done := tracked(10)
+		return Chunk(seq, 3), done
+	})
+}
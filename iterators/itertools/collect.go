@@ -0,0 +1,29 @@
+package itertools
+
+import (
+	"errors"
+	"iter"
+)
+
+// CollectErrors reads seq to its end, and returns the values of the pairs
+// without an error, along with every error joined with errors.Join, or nil if
+// there was none. Unlike a loop which returns on the first error, it reports
+// everything which went wrong at once, at the cost of reading the rest of seq
+// after the first error.
+func CollectErrors[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var (
+		values []T
+		errs   []error
+	)
+
+	for v, err := range seq {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		values = append(values, v)
+	}
+
+	return values, errors.Join(errs...)
+}
@@ -0,0 +1,40 @@
+package itertools
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCollectErrors(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	seq := func(yield func(int, error) bool) {
+		_ = yield(1, nil) && yield(0, errFirst) && yield(2, nil) && yield(0, errSecond) && yield(3, nil)
+	}
+
+	values, err := CollectErrors(seq)
+	if expected := []int{1, 2, 3}; !slices.Equal(values, expected) {
+		t.Fatalf("expected %v, got %v", expected, values)
+	}
+
+	if !errors.Is(err, errFirst) || !errors.Is(err, errSecond) {
+		t.Fatalf("expected both errors, got %v", err)
+	}
+
+	if err.Error() != "first\nsecond" {
+		t.Fatalf("expected the errors in the order they were yielded, got %q", err)
+	}
+}
+
+func TestCollectErrorsNone(t *testing.T) {
+	values, err := CollectErrors(withoutErrors([]int{1, 2}))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !slices.Equal(values, []int{1, 2}) {
+		t.Fatalf("expected [1 2], got %v", values)
+	}
+}
@@ -0,0 +1,33 @@
+package itertools
+
+import (
+	"context"
+	"iter"
+)
+
+// WithContext returns an iterator which yields the values of seq until ctx is
+// cancelled. ctx is checked before each value is yielded, so cancelling it
+// from the loop body ends the loop before the next value, and stops seq
+// like breaking out of the loop would. A seq which blocks while producing a
+// value is not interrupted, since it is only checked between values; such an
+// iterator has to watch ctx itself, or be pulled with PullContext.
+func WithContext[T any](ctx context.Context, seq iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for val := range seq {
+			if ctx.Err() != nil || !yield(val) {
+				return
+			}
+		}
+	}
+}
+
+// WithContext2 is like WithContext for an iterator over pairs
+func WithContext2[K, V any](ctx context.Context, seq iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range seq {
+			if ctx.Err() != nil || !yield(k, v) {
+				return
+			}
+		}
+	}
+}
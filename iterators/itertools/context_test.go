@@ -0,0 +1,78 @@
+package itertools
+
+import (
+	"context"
+	"iter"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+func TestWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	seq, done := tracked(10)
+
+	var got []int
+	for val := range WithContext(ctx, seq) {
+		got = append(got, val)
+		if val == 2 {
+			cancel()
+		}
+	}
+
+	if !slices.Equal(got, []int{0, 1, 2}) {
+		t.Fatalf("expected the values up to the cancellation, got %v", got)
+	}
+
+	if !done() {
+		t.Fatal("expected the cancellation to stop the iterator")
+	}
+}
+
+func TestWithContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for val := range WithContext(ctx, naturals()) {
+		t.Fatalf("expected no values from a cancelled context, got %d", val)
+	}
+}
+
+func TestWithContext2(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var got []string
+	for _, v := range WithContext2(ctx, slices.All([]string{"a", "b", "c"})) {
+		got = append(got, v)
+		cancel()
+	}
+
+	if !slices.Equal(got, []string{"a"}) {
+		t.Fatalf("expected the first value, got %v", got)
+	}
+}
+
+func TestWithContextConformance(t *testing.T) {
+	ctx := context.Background()
+
+	newSeq := func() iter.Seq[int] { return WithContext(ctx, slices.Values([]int{1, 2, 3})) }
+
+	seqtest.AssertYields(t, newSeq(), []int{1, 2, 3})
+	seqtest.AssertStopsEarly(t, 3, newSeq)
+	seqtest.AssertNoYieldAfterFalse(t, 3, newSeq)
+	seqtest.AssertCleanupRuns(t, 3, func() (iter.Seq[int], func() bool) {
+		seq, done := tracked(3)
+		return WithContext(ctx, seq), done
+	})
+
+	m := map[string]int{"one": 1, "two": 2}
+	newSeq2 := func() iter.Seq2[string, int] { return WithContext2(ctx, maps.All(m)) }
+
+	seqtest.AssertStopsEarly2(t, 2, newSeq2)
+	seqtest.AssertNoYieldAfterFalse2(t, 2, newSeq2)
+}
@@ -0,0 +1,122 @@
+package itertools
+
+import "iter"
+
+// Cursor reads the values of a sequence one at a time, either by calling
+// Next, in the pull style, or by ranging over All, in the push style. Both
+// share the same position, so a loop over All can be broken out of and the
+// rest of the values read with Next, or the other way around.
+//
+// A Cursor must not be used from different goroutines at the same time.
+type Cursor[T any] struct {
+	next func() (T, bool)
+	stop func()
+
+	// peeked holds the value returned by Peek until it is read
+	peeked  T
+	hasPeek bool
+	done    bool
+}
+
+// NewCursor returns a cursor over the values of seq. seq is only started
+// once the first value is read, and Stop must be called if the cursor is
+// not read to its end, to run the cleanup of seq.
+func NewCursor[T any](seq iter.Seq[T]) *Cursor[T] {
+	c := &Cursor[T]{}
+
+	// iter.Pull is deferred to the first read, so a cursor which is never
+	// read never starts seq
+	c.next = func() (T, bool) {
+		c.next, c.stop = iter.Pull(seq)
+		return c.next()
+	}
+	c.stop = func() {}
+
+	return c
+}
+
+// CursorOf returns a cursor over the values of s. Unlike NewCursor, it reads
+// s directly, without the cost of iter.Pull, and does not need to be
+// stopped.
+func CursorOf[T any](s []T) *Cursor[T] {
+	i := 0
+
+	return &Cursor[T]{
+		next: func() (T, bool) {
+			if i >= len(s) {
+				var zero T
+				return zero, false
+			}
+
+			i++
+			return s[i-1], true
+		},
+		stop: func() {},
+	}
+}
+
+// Next returns the next value and true, or the zero value and false once
+// there are no more values or the cursor has been stopped
+func (c *Cursor[T]) Next() (T, bool) {
+	if c.hasPeek {
+		val := c.peeked
+
+		var zero T
+		c.peeked, c.hasPeek = zero, false
+		return val, true
+	}
+
+	var zero T
+	if c.done {
+		return zero, false
+	}
+
+	val, ok := c.next()
+	if !ok {
+		c.Stop()
+		return zero, false
+	}
+
+	return val, true
+}
+
+// Peek returns the value the next call to Next will return, without moving
+// the cursor past it
+func (c *Cursor[T]) Peek() (T, bool) {
+	if !c.hasPeek {
+		val, ok := c.Next()
+		if !ok {
+			return val, false
+		}
+
+		c.peeked, c.hasPeek = val, true
+	}
+
+	return c.peeked, true
+}
+
+// Stop stops the cursor, after which Next and Peek return false, including
+// for a value which was peeked at. It may be called any number of times.
+func (c *Cursor[T]) Stop() {
+	if c.done {
+		return
+	}
+
+	var zero T
+	c.done, c.peeked, c.hasPeek = true, zero, false
+	c.stop()
+}
+
+// All returns an iterator over the values which have not been read yet.
+// Breaking out of a loop over it does not stop the cursor, which is left
+// after the last value the loop received.
+func (c *Cursor[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			val, ok := c.Next()
+			if !ok || !yield(val) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package itertools
+
+import (
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+// cursors returns a cursor of each kind over the same values, along with a
+// pointer to whether the cleanup of the sequence has run
+func cursors(values []int) (map[string]*Cursor[int], *bool) {
+	cleaned := new(bool)
+	seq := func(yield func(int) bool) {
+		defer func() { *cleaned = true }()
+
+		for _, v := range values {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+
+	return map[string]*Cursor[int]{
+		"seq":   NewCursor[int](seq),
+		"slice": CursorOf(values),
+	}, cleaned
+}
+
+func TestCursorNext(t *testing.T) {
+	cs, _ := cursors([]int{3, 2, 45})
+
+	for name, c := range cs {
+		var got []int
+		for {
+			v, ok := c.Next()
+			if !ok {
+				break
+			}
+			got = append(got, v)
+		}
+
+		if !slices.Equal(got, []int{3, 2, 45}) {
+			t.Fatalf("%s: expected [3 2 45], got %v", name, got)
+		}
+
+		if _, ok := c.Next(); ok {
+			t.Fatalf("%s: expected the cursor to stay exhausted", name)
+		}
+	}
+}
+
+func TestCursorPeek(t *testing.T) {
+	cs, _ := cursors([]int{3, 2})
+
+	for name, c := range cs {
+		for range 2 {
+			v, ok := c.Peek()
+			if v != 3 || !ok {
+				t.Fatalf("%s: expected to peek at 3, got %d and %t", name, v, ok)
+			}
+		}
+
+		v, _ := c.Next()
+		w, _ := c.Next()
+		if v != 3 || w != 2 {
+			t.Fatalf("%s: expected 3 and 2, got %d and %d", name, v, w)
+		}
+
+		if _, ok := c.Peek(); ok {
+			t.Fatalf("%s: expected nothing to peek at", name)
+		}
+	}
+}
+
+func TestCursorAll(t *testing.T) {
+	cs, _ := cursors([]int{3, 2, 45, 4, 6, 7})
+
+	for name, c := range cs {
+		// A loop over All and calls to Next share the same position
+		var got []int
+		for v := range c.All() {
+			got = append(got, v)
+			if v == 45 {
+				break
+			}
+		}
+
+		v, _ := c.Next()
+		got = append(got, v)
+
+		c.Peek()
+		for v := range c.All() {
+			got = append(got, v)
+		}
+
+		if !slices.Equal(got, []int{3, 2, 45, 4, 6, 7}) {
+			t.Fatalf("%s: expected every value once, got %v", name, got)
+		}
+	}
+}
+
+func TestCursorStop(t *testing.T) {
+	cs, cleaned := cursors([]int{3, 2, 45})
+	c := cs["seq"]
+
+	c.Peek()
+	if *cleaned {
+		t.Fatal("expected the sequence to still be running")
+	}
+
+	c.Stop()
+	c.Stop()
+
+	if !*cleaned {
+		t.Fatal("expected Stop to run the cleanup of the sequence")
+	}
+	if _, ok := c.Next(); ok {
+		t.Fatal("expected a stopped cursor, including its peeked value, to be empty")
+	}
+}
+
+func TestCursorStopUnstarted(t *testing.T) {
+	started := false
+	c := NewCursor[int](func(yield func(int) bool) {
+		started = true
+	})
+
+	c.Stop()
+
+	if started {
+		t.Fatal("expected a cursor which was never read to never start its sequence")
+	}
+}
+
+// Breaking out of All leaves the cursor running, so there is no cleanup to
+// check
+func TestCursorAllConformance(t *testing.T) {
+	newSeq := func() iter.Seq[int] { return CursorOf([]int{1, 2, 3}).All() }
+
+	seqtest.AssertYields(t, newSeq(), []int{1, 2, 3})
+	seqtest.AssertStopsEarly(t, 3, newSeq)
+	seqtest.AssertNoYieldAfterFalse(t, 3, newSeq)
+}
@@ -0,0 +1,62 @@
+package itertools
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// ForEachConcurrent calls fn on each value of seq using up to workers
+// goroutines, without preserving the order of seq. Each value is handed to
+// the first idle worker, so a slow value never holds up the others. The first
+// error returned by fn, or the cancellation of ctx, stops seq before it
+// produces another value and cancels the context passed to fn, so that
+// in-flight calls can return early. The error is returned once every worker
+// has exited.
+func ForEachConcurrent[T any](ctx context.Context, seq iter.Seq[T], workers int, fn func(context.Context, T) error) error {
+	var wg sync.WaitGroup
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	jobs := make(chan T)
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for job := range jobs {
+				err := fn(ctx, job)
+				if err != nil {
+					// Record the error as the cause of the cancellation so
+					// that the producer stops sending new jobs
+					cancel(err)
+					return
+				}
+			}
+		}()
+	}
+
+	// Breaking out of this loop makes yield return false, which stops the
+	// iterator that is producing the jobs
+	for val := range seq {
+		if ctx.Err() != nil {
+			break
+		}
+
+		select {
+		case jobs <- val:
+		case <-ctx.Done():
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return context.Cause(ctx)
+}
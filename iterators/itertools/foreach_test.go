@@ -0,0 +1,149 @@
+package itertools
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/testutil"
+)
+
+func TestForEachConcurrent(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	var (
+		mu   sync.Mutex
+		seen = make(map[int]bool)
+	)
+
+	err := ForEachConcurrent(context.Background(), naturalsUpTo(100), 4, func(_ context.Context, n int) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		seen[n] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(seen) != 100 {
+		t.Fatalf("expected every value to be handled once, got %d of them", len(seen))
+	}
+}
+
+func TestForEachConcurrentStopsOnError(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	errFailed := errors.New("failed")
+	seq, done := tracked(1000)
+
+	err := ForEachConcurrent(context.Background(), naturals(), 4, func(_ context.Context, n int) error {
+		if n == 10 {
+			return errFailed
+		}
+
+		return nil
+	})
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("expected the error of fn, got %v", err)
+	}
+
+	// The infinite iterator only stops because the error stopped it. A
+	// tracked iterator shows that it runs its cleanup.
+	err = ForEachConcurrent(context.Background(), seq, 2, func(_ context.Context, n int) error {
+		if n == 5 {
+			return errFailed
+		}
+
+		return nil
+	})
+	if !errors.Is(err, errFailed) || !done() {
+		t.Fatalf("expected the error and the iterator to stop, got %v and stopped %t", err, done())
+	}
+}
+
+func TestForEachConcurrentCancelsInFlight(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	errFailed := errors.New("failed")
+	started := make(chan struct{})
+
+	// One worker blocks until its context is cancelled, which the error of
+	// the other worker does
+	err := ForEachConcurrent(context.Background(), naturalsUpTo(2), 2, func(ctx context.Context, n int) error {
+		if n == 0 {
+			close(started)
+			<-ctx.Done()
+			return nil
+		}
+
+		<-started
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("expected the error of fn, got %v", err)
+	}
+}
+
+func TestForEachConcurrentContext(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := ForEachConcurrent(ctx, naturals(), 3, func(_ context.Context, n int) error {
+		if n == 20 {
+			cancel()
+		}
+
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestForEachConcurrentBound(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	const workers = 3
+
+	var (
+		running atomic.Int64
+		peak    atomic.Int64
+	)
+
+	// Each call waits at a barrier for workers calls, so the test only ends
+	// if all of the workers run at the same time
+	var barrier sync.WaitGroup
+	barrier.Add(workers)
+
+	err := ForEachConcurrent(context.Background(), naturalsUpTo(workers*10), workers, func(_ context.Context, n int) error {
+		now := running.Add(1)
+		defer running.Add(-1)
+
+		for {
+			p := peak.Load()
+			if now <= p || peak.CompareAndSwap(p, now) {
+				break
+			}
+		}
+
+		if n < workers {
+			barrier.Done()
+			barrier.Wait()
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := peak.Load(); got != workers {
+		t.Fatalf("expected %d calls at a time at most, got %d", workers, got)
+	}
+}
@@ -0,0 +1,259 @@
+package itertools
+
+import (
+	"bytes"
+	"iter"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// The fuzz targets check the invariants of the combinators for random input
+// sequences, read in random ways. Without -fuzz, go test only runs them with
+// the seed inputs added below.
+
+// trackedOf yields the values of s, along with a function which reports
+// whether the iterator has returned
+func trackedOf[T any](s []T) (iter.Seq[T], func() bool) {
+	var (
+		mu   sync.Mutex
+		done bool
+	)
+
+	seq := func(yield func(T) bool) {
+		defer func() {
+			mu.Lock()
+			done = true
+			mu.Unlock()
+		}()
+
+		for _, v := range s {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+
+	return seq, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return done
+	}
+}
+
+// FuzzMap checks that breaking out of Map after stop values yields exactly
+// the first stop values of the input, transformed, and stops the input
+func FuzzMap(f *testing.F) {
+	f.Add([]byte("abc"), 2)
+	f.Add([]byte{}, 0)
+	f.Add([]byte("courses"), 100)
+
+	f.Fuzz(func(t *testing.T, data []byte, stop int) {
+		if stop < 0 {
+			stop = -stop
+		}
+
+		seq, done := trackedOf(data)
+
+		var got []byte
+		for b := range Map(seq, func(b byte) byte { return b ^ 0xff }) {
+			if len(got) == stop {
+				break
+			}
+			got = append(got, b^0xff)
+		}
+
+		want := data[:min(stop, len(data))]
+		if !bytes.Equal(got, want) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+
+		if !done() {
+			t.Fatal("expected the input to be stopped")
+		}
+	})
+}
+
+// FuzzParallelMap checks that ParallelMap yields its results in the order of
+// the input for any number of workers, however early the loop breaks
+func FuzzParallelMap(f *testing.F) {
+	f.Add([]byte("abcdefgh"), uint8(3), 5)
+	f.Add([]byte{}, uint8(1), 0)
+	f.Add([]byte("a"), uint8(0), 1)
+
+	f.Fuzz(func(t *testing.T, data []byte, workers uint8, stop int) {
+		if stop < 0 {
+			stop = -stop
+		}
+
+		seq, done := trackedOf(data)
+
+		var got []byte
+		for b, err := range ParallelMap(seq, int(workers%16), func(b byte) (byte, error) { return b, nil }) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) == stop {
+				break
+			}
+			got = append(got, b)
+		}
+
+		want := data[:min(stop, len(data))]
+		if !bytes.Equal(got, want) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+
+		if !done() {
+			t.Fatal("expected the input to be stopped before ParallelMap returns")
+		}
+	})
+}
+
+// FuzzTee reads both branches of Tee in an order given by steps, where each
+// step reads the next value of one branch or stops it. Whatever the order,
+// each branch yields a prefix of the input, a branch read to its end yields
+// all of it, and the input is stopped once both branches are. A branch which
+// is stopped before its first value never runs, like one which is never
+// ranged over, so Tee cannot know that it is done, and the input is only
+// checked when both branches were started.
+func FuzzTee(f *testing.F) {
+	f.Add([]byte("abcdef"), []byte{0, 1, 0, 0, 1, 1})
+	f.Add([]byte("abc"), []byte{0, 0, 0, 0, 1, 1, 1, 1})
+	f.Add([]byte("abc"), []byte{2, 1, 3})
+	f.Add([]byte{}, []byte{0, 1})
+
+	f.Fuzz(func(t *testing.T, data []byte, steps []byte) {
+		seq, done := trackedOf(data)
+		a, b := Tee(seq)
+
+		var (
+			nexts    [2]func() (byte, bool)
+			stops    [2]func()
+			got      [2][]byte
+			started  [2]bool
+			finished [2]bool
+		)
+
+		nexts[0], stops[0] = iter.Pull(a)
+		nexts[1], stops[1] = iter.Pull(b)
+
+		for _, step := range steps {
+			i := step % 2
+
+			// Steps of 2 and 3 stop a branch, as breaking out of it would
+			if step%4 >= 2 {
+				stops[i]()
+				finished[i] = true
+				continue
+			}
+
+			if !finished[i] {
+				started[i] = true
+			}
+
+			v, ok := nexts[i]()
+			if !ok {
+				if !finished[i] && len(got[i]) != len(data) {
+					t.Fatalf("branch %d ended after %d of %d values", i, len(got[i]), len(data))
+				}
+				finished[i] = true
+				continue
+			}
+			got[i] = append(got[i], v)
+		}
+
+		for i := range 2 {
+			if !bytes.HasPrefix(data, got[i]) {
+				t.Fatalf("expected branch %d to yield a prefix of %v, got %v", i, data, got[i])
+			}
+		}
+
+		stops[0]()
+		stops[1]()
+
+		if started[0] && started[1] && !done() {
+			t.Fatal("expected the input to be stopped once both branches are")
+		}
+	})
+}
+
+// FuzzCursor reads a cursor with a mix of Next, Peek, and loops over All
+// which break early, given by steps. However the reads are mixed, the values
+// read, followed by the rest of the cursor, are the input.
+func FuzzCursor(f *testing.F) {
+	f.Add([]byte("abcdef"), []byte{0, 1, 1, 2, 0, 5})
+	f.Add([]byte("abc"), []byte{1, 1, 1, 1})
+	f.Add([]byte{}, []byte{0, 1, 2})
+
+	f.Fuzz(func(t *testing.T, data []byte, steps []byte) {
+		seq, done := trackedOf(data)
+		c := NewCursor(seq)
+		defer c.Stop()
+
+		var got []byte
+		for _, step := range steps {
+			switch step % 3 {
+			case 0:
+				if v, ok := c.Next(); ok {
+					got = append(got, v)
+				}
+			case 1:
+				peeked, ok := c.Peek()
+				if ok && peeked != data[len(got)] {
+					t.Fatalf("expected to peek at %v, got %v", data[len(got)], peeked)
+				}
+			case 2:
+				// Loops over All break after step/3+1 values, and the value
+				// the loop breaks at has been read
+				n := int(step / 3)
+				for v := range c.All() {
+					got = append(got, v)
+					if n == 0 {
+						break
+					}
+					n--
+				}
+			}
+		}
+
+		got = append(got, slices.Collect(c.All())...)
+		if !bytes.Equal(got, data) {
+			t.Fatalf("expected %v, got %v", data, got)
+		}
+
+		if !done() {
+			t.Fatal("expected the input to be stopped once the cursor is read to its end")
+		}
+	})
+}
+
+// FuzzSharedPull checks that every value is returned to exactly one of the
+// workers, however many there are
+func FuzzSharedPull(f *testing.F) {
+	f.Add(100, uint8(4))
+	f.Add(0, uint8(1))
+	f.Add(1, uint8(8))
+
+	f.Fuzz(func(t *testing.T, n int, workers uint8) {
+		n %= 10000
+		if n < 0 {
+			n = -n
+		}
+
+		next, stop := SharedPull(naturalsUpTo(n))
+		defer stop()
+
+		seen := drain(next, int(workers%16)+1)
+		if len(seen) != n {
+			t.Fatalf("expected %d distinct values, got %d", n, len(seen))
+		}
+
+		for v, count := range seen {
+			if count != 1 {
+				t.Fatalf("expected %d to be returned once, got %d", v, count)
+			}
+		}
+	})
+}
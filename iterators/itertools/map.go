@@ -0,0 +1,134 @@
+package itertools
+
+import (
+	"iter"
+	"sync"
+)
+
+// Map returns an iterator which yields the result of calling fn on each value
+// of seq
+func Map[T, U any](seq iter.Seq[T], fn func(T) U) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for val := range seq {
+			if !yield(fn(val)) {
+				return
+			}
+		}
+	}
+}
+
+// ParallelMap is like Map, except that fn is called on up to workers values
+// at the same time. Results are yielded in the same order as the values of
+// seq, together with the error returned by fn. A reorder buffer holds results
+// which finished ahead of an earlier value, and the number of values in
+// flight is limited to twice the number of workers so that the buffer stays
+// bounded when a single value is slow.
+//
+// The values of seq are produced in a separate goroutine, which is stopped
+// along with the workers before ParallelMap returns.
+func ParallelMap[T, U any](seq iter.Seq[T], workers int, fn func(T) (U, error)) iter.Seq2[U, error] {
+	type (
+		job struct {
+			idx int
+			val T
+		}
+
+		result struct {
+			idx int
+			val U
+			err error
+		}
+	)
+
+	return func(yield func(U, error) bool) {
+		var wg, workersWG sync.WaitGroup
+
+		if workers < 1 {
+			workers = 1
+		}
+
+		done := make(chan struct{})
+		tokens := make(chan struct{}, 2*workers)
+		jobs := make(chan job)
+		results := make(chan result)
+
+		// Stop the producer and the workers if the consumer breaks early,
+		// and wait for them so that no goroutine outlives the iteration
+		defer func() {
+			close(done)
+			wg.Wait()
+		}()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(jobs)
+
+			idx := 0
+			for val := range seq {
+				// A token is returned once the result has been yielded
+				select {
+				case tokens <- struct{}{}:
+				case <-done:
+					return
+				}
+
+				select {
+				case jobs <- job{idx: idx, val: val}:
+				case <-done:
+					return
+				}
+
+				idx++
+			}
+		}()
+
+		for range workers {
+			workersWG.Add(1)
+			go func() {
+				defer workersWG.Done()
+
+				for j := range jobs {
+					val, err := fn(j.val)
+
+					select {
+					case results <- result{idx: j.idx, val: val, err: err}:
+					case <-done:
+						return
+					}
+				}
+			}()
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			workersWG.Wait()
+			close(results)
+		}()
+
+		pending := make(map[int]result)
+		next := 0
+
+		for r := range results {
+			pending[r.idx] = r
+
+			// Yield every result which is now at the front of the line
+			for {
+				r, ok := pending[next]
+				if !ok {
+					break
+				}
+
+				delete(pending, next)
+				next++
+				<-tokens
+
+				if !yield(r.val, r.err) {
+					return
+				}
+			}
+		}
+	}
+}
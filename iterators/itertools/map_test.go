@@ -0,0 +1,162 @@
+package itertools
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+	"github.com/manedurphy/golang-university/internal/testutil"
+)
+
+func TestParallelMapPreservesOrder(t *testing.T) {
+	var got []int
+
+	errOdd := errors.New("odd")
+
+	for val, err := range ParallelMap(slices.Values([]int{5, 1, 4, 2, 3, 0}), 3, func(n int) (int, error) {
+		// Make earlier values finish later than the ones after them
+		time.Sleep(time.Duration(n) * time.Millisecond)
+
+		if n%2 == 1 {
+			return 0, errOdd
+		}
+
+		return n * 10, nil
+	}) {
+		if err != nil {
+			got = append(got, -1)
+			continue
+		}
+
+		got = append(got, val)
+	}
+
+	expected := []int{-1, -1, 40, 20, -1, 0}
+	if !slices.Equal(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestParallelMapStopsEarly(t *testing.T) {
+	count := 0
+
+	for range ParallelMap(naturals(), 4, func(n int) (int, error) { return n, nil }) {
+		count++
+		if count == 10 {
+			break
+		}
+	}
+
+	if count != 10 {
+		t.Fatalf("expected 10 values, got %d", count)
+	}
+}
+
+// tracked yields the numbers from 0 to n-1, along with a function which
+// reports whether the iterator has returned
+func tracked(n int) (iter.Seq[int], func() bool) {
+	var done atomic.Bool
+
+	seq := func(yield func(int) bool) {
+		defer done.Store(true)
+
+		for i := range n {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	return seq, done.Load
+}
+
+// naturals is an infinite iterator, which is only safe to use with
+// combinators that stop it
+func naturals() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+// hash is a CPU-bound transform which takes a few microseconds
+func hash(n int) [32]byte {
+	sum := sha256.Sum256(fmt.Append(nil, n))
+	for range 30 {
+		sum = sha256.Sum256(sum[:])
+	}
+
+	return sum
+}
+
+func BenchmarkMap(b *testing.B) {
+	for range b.N {
+		for range Map(naturalsUpTo(1000), hash) {
+		}
+	}
+}
+
+func BenchmarkParallelMap(b *testing.B) {
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for range b.N {
+				for range ParallelMap(naturalsUpTo(1000), workers, func(n int) ([32]byte, error) {
+					return hash(n), nil
+				}) {
+				}
+			}
+		})
+	}
+}
+
+func naturalsUpTo(n int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := range n {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func double(n int) int { return 2 * n }
+
+func TestMapConformance(t *testing.T) {
+	newSeq := func() iter.Seq[int] { return Map(slices.Values([]int{1, 2, 3}), double) }
+
+	seqtest.AssertYields(t, newSeq(), []int{2, 4, 6})
+	seqtest.AssertStopsEarly(t, 3, newSeq)
+	seqtest.AssertNoYieldAfterFalse(t, 3, newSeq)
+	seqtest.AssertCleanupRuns(t, 3, func() (iter.Seq[int], func() bool) {
+		seq, done := tracked(3)
+		return Map(seq, double), done
+	})
+}
+
+func TestParallelMapConformance(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	fn := func(n int) (int, error) { return double(n), nil }
+
+	newSeq := func() iter.Seq2[int, error] { return ParallelMap(slices.Values([]int{1, 2, 3, 4}), 2, fn) }
+
+	seqtest.AssertYields2(t, newSeq(), []int{2, 4, 6, 8}, make([]error, 4))
+	seqtest.AssertStopsEarly2(t, 4, newSeq)
+	seqtest.AssertNoYieldAfterFalse2(t, 4, newSeq)
+
+	// The source is ranged over by a goroutine of its own, which has to have
+	// stopped before ParallelMap returns
+	seqtest.AssertCleanupRuns2(t, 4, func() (iter.Seq2[int, error], func() bool) {
+		seq, done := tracked(4)
+		return ParallelMap(seq, 2, fn), done
+	})
+}
@@ -0,0 +1,238 @@
+package itertools
+
+import (
+	"container/heap"
+	"iter"
+	"time"
+)
+
+// MergeByTime returns an iterator which merges streams of timestamped values
+// into a single stream, ordered by time. Values with the same time are
+// yielded in the order of their streams, and of the values of a stream.
+//
+// Each stream is expected to be in order, give or take window: a value may
+// come after values which are up to window later than it, as events do when
+// they are delivered with a delay. MergeByTime holds the values back in a
+// buffer until no stream can still produce an earlier one, which is once
+// every stream which has not ended has produced a value more than window
+// later. A value which comes even later than that would be out of order, so
+// it is dropped, and handed to late, unless late is nil. A larger window drops
+// fewer values, at the cost of holding more of them, and for longer.
+//
+// The streams are pulled in the goroutine of the loop, one value at a time,
+// from the stream which holds the others back, and they are stopped when the
+// loop ends.
+func MergeByTime[T any](window time.Duration, late func(time.Time, T), seqs ...iter.Seq2[time.Time, T]) iter.Seq2[time.Time, T] {
+	return func(yield func(time.Time, T) bool) {
+		streams := make([]*timedStream[T], len(seqs))
+		for i, seq := range seqs {
+			next, stop := iter.Pull2(seq)
+			defer stop()
+
+			streams[i] = &timedStream[T]{next: next}
+		}
+
+		var (
+			pending timedHeap[T]
+			// arrived numbers the values, so that the heap keeps the values
+			// with the same time in the order they came in
+			arrived int
+			// emitted is the time of the last value yielded, before which
+			// nothing can be yielded any more
+			emitted time.Time
+			started bool
+		)
+
+		// pull reads the next value of s into the buffer, or drops it if it
+		// is too late
+		pull := func(i int) {
+			s := streams[i]
+
+			t, v, ok := s.next()
+			if !ok {
+				s.ended = true
+				return
+			}
+
+			if !s.started || t.After(s.latest) {
+				s.latest = t
+				s.started = true
+			}
+
+			if started && t.Before(emitted) {
+				if late != nil {
+					late(t, v)
+				}
+				return
+			}
+
+			heap.Push(&pending, timedValue[T]{t: t, v: v, stream: i, arrived: arrived})
+			arrived++
+		}
+
+		// Every stream needs a value before anything can be yielded, as
+		// the earliest one could be the first value of any of them
+		for i := range streams {
+			pull(i)
+		}
+
+		for {
+			// The stream which has not ended and whose values are the
+			// furthest behind can still produce a value as early as window
+			// before its latest one, so the values from there on are held
+			slowest := -1
+			for i, s := range streams {
+				if !s.ended && (slowest < 0 || s.latest.Before(streams[slowest].latest)) {
+					slowest = i
+				}
+			}
+
+			for pending.Len() > 0 {
+				first := pending[0]
+				if slowest >= 0 && !first.t.Before(streams[slowest].latest.Add(-window)) {
+					break
+				}
+
+				heap.Pop(&pending)
+				emitted, started = first.t, true
+
+				if !yield(first.t, first.v) {
+					return
+				}
+			}
+
+			if slowest < 0 {
+				return
+			}
+
+			pull(slowest)
+		}
+	}
+}
+
+// timedStream is a stream of MergeByTime
+type timedStream[T any] struct {
+	next func() (time.Time, T, bool)
+	// latest is the latest time of the values the stream produced so far
+	latest  time.Time
+	started bool
+	ended   bool
+}
+
+// timedValue is a value of a stream which waits to be yielded
+type timedValue[T any] struct {
+	t       time.Time
+	v       T
+	stream  int
+	arrived int
+}
+
+// timedHeap is a min-heap of the values which wait to be yielded, ordered by
+// their time, then by their stream, and then by the order they came in
+type timedHeap[T any] []timedValue[T]
+
+func (h timedHeap[T]) Len() int      { return len(h) }
+func (h timedHeap[T]) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h timedHeap[T]) Less(i, j int) bool {
+	if c := h[i].t.Compare(h[j].t); c != 0 {
+		return c < 0
+	}
+
+	if h[i].stream != h[j].stream {
+		return h[i].stream < h[j].stream
+	}
+
+	return h[i].arrived < h[j].arrived
+}
+
+func (h *timedHeap[T]) Push(x any) { *h = append(*h, x.(timedValue[T])) }
+
+func (h *timedHeap[T]) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+
+	// Clear the slot, so that the heap does not keep the value alive
+	old[len(old)-1] = timedValue[T]{}
+	*h = old[:len(old)-1]
+
+	return last
+}
+
+// MergeSorted returns an iterator which merges sequences which are each
+// sorted by less into a single sorted sequence. Values which are equal by
+// less are yielded in the order of their sequences, and of the values of a
+// sequence, so merging the sorted runs of a stable sort keeps it stable.
+//
+// The sequences are pulled in the goroutine of the loop, and a heap holds the
+// next value of each of them, so only one value per sequence is held at a
+// time, however long they are. They are stopped when the loop ends.
+func MergeSorted[T any](less func(a, b T) bool, seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		nexts := make([]func() (T, bool), len(seqs))
+		h := &headHeap[T]{less: less}
+
+		for i, seq := range seqs {
+			next, stop := iter.Pull(seq)
+			defer stop()
+
+			nexts[i] = next
+			if v, ok := next(); ok {
+				h.heads = append(h.heads, head[T]{value: v, seq: i})
+			}
+		}
+		heap.Init(h)
+
+		for h.Len() > 0 {
+			top := h.heads[0]
+			if !yield(top.value) {
+				return
+			}
+
+			// The next value of the same sequence takes the place of the
+			// one which was yielded, which is cheaper than a pop and a push
+			if v, ok := nexts[top.seq](); ok {
+				h.heads[0].value = v
+				heap.Fix(h, 0)
+			} else {
+				heap.Pop(h)
+			}
+		}
+	}
+}
+
+// head is the next value of the sequence with the index seq
+type head[T any] struct {
+	value T
+	seq   int
+}
+
+// headHeap is a min-heap of the next values of the sequences of MergeSorted,
+// ordered by less, and then by their sequence
+type headHeap[T any] struct {
+	heads []head[T]
+	less  func(a, b T) bool
+}
+
+func (h *headHeap[T]) Len() int      { return len(h.heads) }
+func (h *headHeap[T]) Swap(i, j int) { h.heads[i], h.heads[j] = h.heads[j], h.heads[i] }
+func (h *headHeap[T]) Push(x any)    { h.heads = append(h.heads, x.(head[T])) }
+
+func (h *headHeap[T]) Less(i, j int) bool {
+	a, b := h.heads[i], h.heads[j]
+	if h.less(a.value, b.value) {
+		return true
+	}
+
+	return !h.less(b.value, a.value) && a.seq < b.seq
+}
+
+func (h *headHeap[T]) Pop() any {
+	last := h.heads[len(h.heads)-1]
+
+	// Clear the slot, so that the heap does not keep the value alive
+	h.heads[len(h.heads)-1] = head[T]{}
+	h.heads = h.heads[:len(h.heads)-1]
+
+	return last
+}
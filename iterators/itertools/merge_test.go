@@ -0,0 +1,211 @@
+package itertools
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// events returns a stream of the values of name, at the given seconds after
+// epoch, in the order given
+func events(name string, seconds ...int) iter.Seq2[time.Time, string] {
+	return func(yield func(time.Time, string) bool) {
+		for _, s := range seconds {
+			if !yield(epoch.Add(time.Duration(s)*time.Second), name) {
+				return
+			}
+		}
+	}
+}
+
+// times returns the seconds after epoch of the given times
+func times(ts []time.Time) []int {
+	seconds := make([]int, len(ts))
+	for i, t := range ts {
+		seconds[i] = int(t.Sub(epoch) / time.Second)
+	}
+
+	return seconds
+}
+
+// collectTimed collects the times and values of seq
+func collectTimed(seq iter.Seq2[time.Time, string]) ([]int, []string) {
+	var (
+		ts     []time.Time
+		values []string
+	)
+
+	for t, v := range seq {
+		ts = append(ts, t)
+		values = append(values, v)
+	}
+
+	return times(ts), values
+}
+
+func TestMergeByTime(t *testing.T) {
+	merged := MergeByTime(0, nil,
+		events("a", 1, 4, 4, 7),
+		events("b", 2, 4, 9),
+		events("c"),
+		events("d", 3),
+	)
+
+	// The values at 4 are in the order of their streams
+	seconds, values := collectTimed(merged)
+	if expected := []int{1, 2, 3, 4, 4, 4, 7, 9}; !slices.Equal(seconds, expected) {
+		t.Fatalf("expected %v, got %v", expected, seconds)
+	}
+
+	if expected := []string{"a", "b", "d", "a", "a", "b", "a", "b"}; !slices.Equal(values, expected) {
+		t.Fatalf("expected %v, got %v", expected, values)
+	}
+}
+
+func TestMergeByTimeWindow(t *testing.T) {
+	var late []int
+	onLate := func(t time.Time, _ string) {
+		late = append(late, times([]time.Time{t})...)
+	}
+
+	// Within each stream, no value comes more than 3s after a later one
+	merged := MergeByTime(3*time.Second, onLate,
+		events("a", 3, 1, 2, 6, 4, 8),
+		events("b", 2, 5, 3, 7),
+	)
+
+	seconds, _ := collectTimed(merged)
+	if expected := []int{1, 2, 2, 3, 3, 4, 5, 6, 7, 8}; !slices.Equal(seconds, expected) {
+		t.Fatalf("expected %v, got %v", expected, seconds)
+	}
+
+	if len(late) != 0 {
+		t.Fatalf("expected no late values, got %v", late)
+	}
+}
+
+func TestMergeByTimeLate(t *testing.T) {
+	var late []int
+	onLate := func(t time.Time, _ string) {
+		late = append(late, times([]time.Time{t})...)
+	}
+
+	// 1 comes 4s after 5, and b has moved past it by then
+	merged := MergeByTime(2*time.Second, onLate,
+		events("a", 2, 5, 1, 6),
+		events("b", 3, 4, 6, 8),
+	)
+
+	seconds, _ := collectTimed(merged)
+	if expected := []int{2, 3, 4, 5, 6, 6, 8}; !slices.Equal(seconds, expected) {
+		t.Fatalf("expected %v, got %v", expected, seconds)
+	}
+
+	if !slices.Equal(late, []int{1}) {
+		t.Fatalf("expected 1 to be late, got %v", late)
+	}
+
+	// Without late, the value is only dropped
+	seconds, _ = collectTimed(MergeByTime(2*time.Second, nil, events("a", 2, 5, 1, 6), events("b", 3, 4, 6, 8)))
+	if expected := []int{2, 3, 4, 5, 6, 6, 8}; !slices.Equal(seconds, expected) {
+		t.Fatalf("expected %v, got %v", expected, seconds)
+	}
+}
+
+func TestMergeByTimeNoStreams(t *testing.T) {
+	for range MergeByTime[string](time.Second, nil) {
+		t.Fatal("expected no values")
+	}
+}
+
+func TestMergeByTimeConformance(t *testing.T) {
+	seqtest.AssertStopsEarly2(t, 5, func() iter.Seq2[time.Time, string] {
+		return MergeByTime(time.Second, nil, events("a", 1, 3, 5), events("b", 2, 4))
+	})
+
+	seqtest.AssertCleanupRuns2(t, 3, func() (iter.Seq2[time.Time, string], func() bool) {
+		var stopped [2]bool
+
+		stream := func(i int, seconds ...int) iter.Seq2[time.Time, string] {
+			return func(yield func(time.Time, string) bool) {
+				defer func() { stopped[i] = true }()
+
+				for t, v := range events("a", seconds...) {
+					if !yield(t, v) {
+						return
+					}
+				}
+			}
+		}
+
+		merged := MergeByTime(time.Second, nil, stream(0, 1, 3, 5), stream(1, 2, 4, 6))
+
+		return merged, func() bool { return stopped[0] && stopped[1] }
+	})
+}
+
+func TestMergeSorted(t *testing.T) {
+	merged := MergeSorted(cmp.Less[int],
+		slices.Values([]int{1, 4, 7, 10}),
+		slices.Values([]int{2, 5, 8}),
+		slices.Values([]int{}),
+		slices.Values([]int{3, 6, 9, 11, 12}),
+	)
+
+	seqtest.AssertYields(t, merged, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12})
+}
+
+func TestMergeSortedStable(t *testing.T) {
+	type course struct {
+		name string
+		id   int
+	}
+
+	byName := func(a, b course) bool { return a.name < b.name }
+
+	// The courses with the same name come in the order of their runs
+	merged := MergeSorted(byName,
+		slices.Values([]course{{"Chem-1", 1}, {"Chem-1", 2}, {"Physics-1", 3}}),
+		slices.Values([]course{{"Calculus-1", 4}, {"Chem-1", 5}}),
+		slices.Values([]course{{"Chem-1", 6}, {"Physics-1", 7}}),
+	)
+
+	var ids []int
+	for c := range merged {
+		ids = append(ids, c.id)
+	}
+
+	if expected := []int{4, 1, 2, 5, 6, 3, 7}; !slices.Equal(ids, expected) {
+		t.Fatalf("expected %v, got %v", expected, ids)
+	}
+}
+
+func TestMergeSortedConformance(t *testing.T) {
+	seqtest.AssertStopsEarly(t, 5, func() iter.Seq[int] {
+		return MergeSorted(cmp.Less[int], slices.Values([]int{1, 3, 5}), slices.Values([]int{2, 4}))
+	})
+
+	seqtest.AssertCleanupRuns(t, 3, func() (iter.Seq[int], func() bool) {
+		var stopped [2]bool
+
+		run := func(i int, values ...int) iter.Seq[int] {
+			return func(yield func(int) bool) {
+				defer func() { stopped[i] = true }()
+
+				for _, v := range values {
+					if !yield(v) {
+						return
+					}
+				}
+			}
+		}
+
+		return MergeSorted(cmp.Less[int], run(0, 1, 3, 5), run(1, 2, 4, 6)), func() bool { return stopped[0] && stopped[1] }
+	})
+}
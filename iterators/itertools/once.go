@@ -0,0 +1,64 @@
+package itertools
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// ErrReused is what Once and Once2 panic with, wrapped with where the
+// iterator was first ranged over, when it is ranged over a second time
+var ErrReused = errors.New("single-use iterator ranged over more than once")
+
+// Once returns an iterator which yields the values of seq, and panics with
+// an error wrapping ErrReused if it is ranged over more than once, even if
+// the first loop broke early. Many iterators can only be ranged over once,
+// such as those over the rows of a query, a channel, or a reader, and a
+// second loop over them quietly yields nothing, or picks up wherever the
+// first one stopped. Once turns that into a panic where the second loop
+// starts.
+func Once[T any](seq iter.Seq[T]) iter.Seq[T] {
+	u := &use{}
+
+	return func(yield func(T) bool) {
+		u.start()
+		seq(yield)
+	}
+}
+
+// Once2 is like Once for an iterator over pairs
+func Once2[K, V any](seq iter.Seq2[K, V]) iter.Seq2[K, V] {
+	u := &use{}
+
+	return func(yield func(K, V) bool) {
+		u.start()
+		seq(yield)
+	}
+}
+
+// use records where a single-use iterator was first ranged over
+type use struct {
+	mu    sync.Mutex
+	used  bool
+	first string
+}
+
+// start panics if the iterator has been ranged over before. It is called
+// directly by the iterator function, so the caller two frames up is the
+// function with the loop.
+func (u *use) start() {
+	_, file, line, _ := runtime.Caller(2)
+	at := fmt.Sprintf("%s:%d", filepath.Base(file), line)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.used {
+		panic(fmt.Errorf("%w: first at %s, again at %s", ErrReused, u.first, at))
+	}
+
+	u.used, u.first = true, at
+}
@@ -0,0 +1,123 @@
+package itertools
+
+import (
+	"errors"
+	"iter"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+// reuse ranges over seq once, breaking after the first value, and returns
+// what the second range over it panicked with
+func reuse(seq iter.Seq[int]) (r any) {
+	for range seq {
+		break
+	}
+
+	defer func() { r = recover() }()
+	for range seq {
+	}
+
+	return nil
+}
+
+func TestOnce(t *testing.T) {
+	seq := Once(slices.Values([]int{1, 2, 3}))
+
+	r := reuse(seq)
+
+	err, ok := r.(error)
+	if !ok || !errors.Is(err, ErrReused) {
+		t.Fatalf("expected a panic with ErrReused, got %v", r)
+	}
+
+	// Both loops are in reuse, which is what the error points at
+	if strings.Count(err.Error(), "once_test.go:") != 2 {
+		t.Fatalf("expected the error to name both loops, got %v", err)
+	}
+}
+
+func TestOnceFirstRange(t *testing.T) {
+	var got []int
+	for n := range Once(slices.Values([]int{1, 2, 3})) {
+		got = append(got, n)
+	}
+
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("expected [1 2 3], got %v", got)
+	}
+}
+
+func TestOnce2(t *testing.T) {
+	seq := Once2(slices.All([]string{"Chem-1", "Physics-1"}))
+
+	var got []string
+	for _, name := range seq {
+		got = append(got, name)
+	}
+
+	if !slices.Equal(got, []string{"Chem-1", "Physics-1"}) {
+		t.Fatalf("expected both names, got %v", got)
+	}
+
+	func() {
+		defer func() {
+			err, _ := recover().(error)
+			if !errors.Is(err, ErrReused) {
+				t.Fatalf("expected a panic with ErrReused, got %v", err)
+			}
+		}()
+
+		for range seq {
+		}
+	}()
+}
+
+func TestOnceConcurrent(t *testing.T) {
+	seq := Once(slices.Values([]int{1, 2, 3}))
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		panics int
+	)
+
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if recover() != nil {
+					mu.Lock()
+					panics++
+					mu.Unlock()
+				}
+			}()
+
+			for range seq {
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if panics != 7 {
+		t.Fatalf("expected every range but one to panic, got %d panics", panics)
+	}
+}
+
+func TestOnceConformance(t *testing.T) {
+	newSeq := func() iter.Seq[int] { return Once(slices.Values([]int{1, 2, 3})) }
+
+	seqtest.AssertYields(t, newSeq(), []int{1, 2, 3})
+	seqtest.AssertStopsEarly(t, 3, newSeq)
+	seqtest.AssertNoYieldAfterFalse(t, 3, newSeq)
+	seqtest.AssertCleanupRuns(t, 3, func() (iter.Seq[int], func() bool) {
+		seq, done := tracked(3)
+		return Once(seq), done
+	})
+}
@@ -0,0 +1,96 @@
+package itertools
+
+import (
+	"fmt"
+	"iter"
+	"time"
+)
+
+// RetryPolicy decides how often and how long RetryEach retries a value
+type RetryPolicy struct {
+	// MaxAttempts is the number of times fn is called on a value before its
+	// error is yielded. A value is always attempted at least once.
+	MaxAttempts int
+	// Backoff is how long RetryEach waits before the second attempt. The
+	// wait doubles after every attempt, up to MaxBackoff if it is set.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Retryable reports whether an error is worth another attempt. Every
+	// error is if it is nil.
+	Retryable func(error) bool
+}
+
+// backoff returns how long to wait after the given attempt failed
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.Backoff
+	for range attempt - 1 {
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+
+	return d
+}
+
+// RetryError is the error RetryEach yields for a value it gave up on
+type RetryError struct {
+	// Attempts is the number of times fn was called on the value
+	Attempts int
+	// Err is the error of the last attempt
+	Err error
+}
+
+func (e *RetryError) Error() string {
+	if e.Attempts == 1 {
+		return fmt.Sprintf("failed after 1 attempt: %v", e.Err)
+	}
+
+	return fmt.Sprintf("failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the last attempt, so that errors.Is and
+// errors.As see through the retries
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// RetryEach returns an iterator which yields the result of calling fn on
+// each value of seq. When fn fails, it is called again on the same value,
+// after a backoff, until it succeeds or the policy gives up, and only then is
+// the error yielded, as a *RetryError. The iteration carries on with the next
+// value either way, so that one value which keeps failing does not lose the
+// rest.
+//
+// An error yielded by seq itself is yielded as it is, without a retry, since
+// an iterator cannot be asked for the same value again.
+func RetryEach[T, U any](seq iter.Seq2[T, error], policy RetryPolicy, fn func(T) (U, error)) iter.Seq2[U, error] {
+	return func(yield func(U, error) bool) {
+		for val, err := range seq {
+			var result U
+			if err == nil {
+				result, err = retry(policy, func() (U, error) { return fn(val) })
+			}
+
+			if !yield(result, err) {
+				return
+			}
+		}
+	}
+}
+
+// retry calls fn until it succeeds or the policy gives up
+func retry[U any](policy RetryPolicy, fn func() (U, error)) (U, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := fn()
+		if err == nil {
+			return result, nil
+		}
+
+		if attempt >= policy.MaxAttempts || (policy.Retryable != nil && !policy.Retryable(err)) {
+			return result, &RetryError{Attempts: attempt, Err: err}
+		}
+
+		time.Sleep(policy.backoff(attempt))
+	}
+}
@@ -0,0 +1,143 @@
+package itertools
+
+import (
+	"errors"
+	"iter"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+// withoutErrors yields the values of s with a nil error
+func withoutErrors[T any](s []T) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for _, v := range s {
+			if !yield(v, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestRetryEach(t *testing.T) {
+	errTransient := errors.New("transient")
+
+	// Each value n fails n times before it succeeds
+	attempts := make(map[int]int)
+	fn := func(n int) (int, error) {
+		attempts[n]++
+		if attempts[n] <= n {
+			return 0, errTransient
+		}
+
+		return n * 10, nil
+	}
+
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+
+	var got []int
+	var errs []error
+	for val, err := range RetryEach(withoutErrors([]int{0, 1, 2, 3, 1}), policy, fn) {
+		got = append(got, val)
+		errs = append(errs, err)
+	}
+
+	if expected := []int{0, 10, 20, 0, 10}; !slices.Equal(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	for i, err := range errs {
+		if i != 3 && err != nil {
+			t.Fatalf("expected no error for value %d, got %v", i, err)
+		}
+	}
+
+	var re *RetryError
+	if !errors.As(errs[3], &re) || re.Attempts != 3 || !errors.Is(errs[3], errTransient) {
+		t.Fatalf("expected a RetryError after 3 attempts, got %v", errs[3])
+	}
+
+	if attempts[3] != 3 {
+		t.Fatalf("expected value 3 to be attempted 3 times, got %d", attempts[3])
+	}
+}
+
+func TestRetryEachNotRetryable(t *testing.T) {
+	errPermanent := errors.New("permanent")
+
+	calls := 0
+	fn := func(n int) (int, error) {
+		calls++
+		return 0, errPermanent
+	}
+
+	policy := RetryPolicy{
+		MaxAttempts: 5,
+		Retryable:   func(err error) bool { return !errors.Is(err, errPermanent) },
+	}
+
+	for _, err := range RetryEach(withoutErrors([]int{1}), policy, fn) {
+		var re *RetryError
+		if !errors.As(err, &re) || re.Attempts != 1 {
+			t.Fatalf("expected a RetryError after 1 attempt, got %v", err)
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected a single attempt, got %d", calls)
+	}
+}
+
+func TestRetryEachSourceError(t *testing.T) {
+	errSource := errors.New("source")
+	seq := func(yield func(int, error) bool) {
+		_ = yield(1, nil) && yield(0, errSource) && yield(2, nil)
+	}
+
+	calls := 0
+	fn := func(n int) (int, error) {
+		calls++
+		return n, nil
+	}
+
+	var errs []error
+	for _, err := range RetryEach(seq, RetryPolicy{MaxAttempts: 3}, fn) {
+		errs = append(errs, err)
+	}
+
+	if len(errs) != 3 || errs[1] != errSource {
+		t.Fatalf("expected the error of seq to be yielded as it is, got %v", errs)
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected fn to be called for the 2 values, got %d", calls)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{Backoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+
+	var got []time.Duration
+	for attempt := 1; attempt <= 5; attempt++ {
+		got = append(got, policy.backoff(attempt))
+	}
+
+	expected := []time.Duration{10, 20, 40, 50, 50}
+	for i := range expected {
+		expected[i] *= time.Millisecond
+	}
+
+	if !slices.Equal(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestRetryEachConformance(t *testing.T) {
+	identity := func(n int) (int, error) { return n, nil }
+
+	seqtest.AssertStopsEarly2(t, 3, func() iter.Seq2[int, error] {
+		return RetryEach(withoutErrors([]int{1, 2, 3, 4, 5}), RetryPolicy{}, identity)
+	})
+}
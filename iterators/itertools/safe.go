@@ -0,0 +1,64 @@
+package itertools
+
+import (
+	"fmt"
+	"iter"
+	"runtime/debug"
+)
+
+// PanicError is the error Safe yields when the iterator it wraps panics
+type PanicError struct {
+	// Value is the value the iterator panicked with
+	Value any
+	// Stack is the stack of the goroutine at the time of the panic
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("iterator panicked: %v", e.Value)
+}
+
+// Unwrap returns the value the iterator panicked with if it is an error, so
+// that errors.Is and errors.As see through the panic
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Safe returns an iterator which yields the values of seq with a nil error.
+// If seq panics, the panic is recovered and yielded as a *PanicError, and the
+// iteration stops, instead of the panic crashing the consumer.
+//
+// Only panics of seq itself are recovered. A panic in the loop body travels
+// through the yield call of seq like any other panic, and is not turned into
+// an error, since the consumer is the one which has to handle it. Neither is
+// a panic of seq once the consumer has stopped, such as in the cleanup after
+// a break, because there is no one left to yield it to.
+func Safe[T any](seq iter.Seq[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var inBody, stopped bool
+
+		defer func() {
+			// Not calling recover lets the panic carry on up the stack
+			if inBody || stopped {
+				return
+			}
+
+			if r := recover(); r != nil {
+				var zero T
+				yield(zero, &PanicError{Value: r, Stack: debug.Stack()})
+			}
+		}()
+
+		for v := range seq {
+			inBody = true
+			ok := yield(v, nil)
+			inBody = false
+
+			if !ok {
+				stopped = true
+				return
+			}
+		}
+	}
+}
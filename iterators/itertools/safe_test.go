@@ -0,0 +1,161 @@
+package itertools
+
+import (
+	"errors"
+	"io"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+// collect ranges over seq, and returns the values and errors it yielded
+func collect[T any](seq iter.Seq2[T, error]) ([]T, []error) {
+	var (
+		vals []T
+		errs []error
+	)
+
+	for v, err := range seq {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		vals = append(vals, v)
+	}
+
+	return vals, errs
+}
+
+func TestSafePanicBeforeYield(t *testing.T) {
+	vals, errs := collect(Safe(func(yield func(int) bool) {
+		panic("no database")
+	}))
+
+	if len(vals) != 0 {
+		t.Fatalf("expected no values, got %v", vals)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+
+	var pe *PanicError
+	if !errors.As(errs[0], &pe) {
+		t.Fatalf("expected a *PanicError, got %T", errs[0])
+	}
+	if pe.Value != "no database" {
+		t.Fatalf("expected the panic value, got %v", pe.Value)
+	}
+	if !strings.Contains(string(pe.Stack), "TestSafePanicBeforeYield") {
+		t.Fatalf("expected the stack of the panic, got\n%s", pe.Stack)
+	}
+}
+
+func TestSafePanicAfterYield(t *testing.T) {
+	vals, errs := collect(Safe(func(yield func(int) bool) {
+		for n := range 3 {
+			if !yield(n) {
+				return
+			}
+		}
+
+		panic(io.ErrUnexpectedEOF)
+	}))
+
+	if !slices.Equal(vals, []int{0, 1, 2}) {
+		t.Fatalf("expected [0 1 2], got %v", vals)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], io.ErrUnexpectedEOF) {
+		t.Fatalf("expected the error the iterator panicked with, got %v", errs)
+	}
+}
+
+func TestSafePanicDuringYield(t *testing.T) {
+	var cleanedUp bool
+
+	seq := Safe(func(yield func(int) bool) {
+		defer func() { cleanedUp = true }()
+
+		for n := range 3 {
+			if !yield(n) {
+				return
+			}
+		}
+	})
+
+	// The panic of the loop body is the consumer's to handle, so it must
+	// reach it as a panic rather than as an error
+	r := func() (r any) {
+		defer func() { r = recover() }()
+
+		for n, err := range seq {
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if n == 1 {
+				panic("bad course")
+			}
+		}
+
+		return nil
+	}()
+
+	if r != "bad course" {
+		t.Fatalf("expected the panic of the loop body, got %v", r)
+	}
+	if !cleanedUp {
+		t.Fatal("expected the iterator's deferred calls to run")
+	}
+}
+
+func TestSafePanicAfterBreak(t *testing.T) {
+	seq := Safe(func(yield func(int) bool) {
+		defer func() { panic("cleanup failed") }()
+
+		for n := 0; ; n++ {
+			if !yield(n) {
+				return
+			}
+		}
+	})
+
+	// Once the consumer has stopped, there is no one to yield the error to
+	r := func() (r any) {
+		defer func() { r = recover() }()
+
+		for range seq {
+			break
+		}
+
+		return nil
+	}()
+
+	if r != "cleanup failed" {
+		t.Fatalf("expected the panic of the cleanup, got %v", r)
+	}
+}
+
+func TestSafeNoPanic(t *testing.T) {
+	vals, errs := collect(Safe(slices.Values([]string{"Chem-1", "Physics-1"})))
+
+	if !slices.Equal(vals, []string{"Chem-1", "Physics-1"}) || len(errs) != 0 {
+		t.Fatalf("expected both values and no error, got %v and %v", vals, errs)
+	}
+}
+
+func TestSafeConformance(t *testing.T) {
+	newSeq := func() iter.Seq2[int, error] { return Safe(slices.Values([]int{1, 2, 3})) }
+
+	seqtest.AssertYields2(t, newSeq(), []int{1, 2, 3}, make([]error, 3))
+	seqtest.AssertStopsEarly2(t, 3, newSeq)
+	seqtest.AssertNoYieldAfterFalse2(t, 3, newSeq)
+
+	// A panic in the loop body is not recovered, so the cleanup of the
+	// iterator still runs as the panic unwinds through it
+	seqtest.AssertCleanupRuns2(t, 3, func() (iter.Seq2[int, error], func() bool) {
+		seq, done := tracked(3)
+		return Safe(seq), done
+	})
+}
@@ -0,0 +1,64 @@
+package itertools
+
+import (
+	"iter"
+	"math/rand/v2"
+)
+
+// ReservoirSample reads seq to its end, and returns k of its values, chosen
+// at random so that every value is as likely to be in the sample as any
+// other, or every value if there are no more than k. It holds no more than k
+// values at a time, however long seq is, so it samples a stream which does
+// not fit in memory, or whose length is not known in advance, in a single
+// pass. The sample is not in the order of seq.
+//
+// ReservoirSample panics if k is negative.
+func ReservoirSample[T any](seq iter.Seq[T], k int, r *rand.Rand) []T {
+	if k < 0 {
+		panic("itertools: ReservoirSample k must not be negative")
+	}
+
+	if k == 0 {
+		return nil
+	}
+
+	sample := make([]T, 0, k)
+
+	var seen int
+	for v := range seq {
+		seen++
+
+		// The first k values fill the reservoir. After that, the value
+		// seen in nth place replaces a random one of them with a chance of
+		// k/n, which leaves every value seen so far in the reservoir with a
+		// chance of k/n.
+		if len(sample) < k {
+			sample = append(sample, v)
+		} else if i := r.IntN(seen); i < k {
+			sample[i] = v
+		}
+	}
+
+	return sample
+}
+
+// SampleFraction returns an iterator which yields each value of seq with a
+// chance of p, independently of the others, and in the order of seq. The
+// number of values it yields is random, about p times the length of seq, and
+// it holds none of them, so it can sample an endless stream.
+//
+// SampleFraction panics if p is not between 0 and 1.
+func SampleFraction[T any](seq iter.Seq[T], p float64, r *rand.Rand) iter.Seq[T] {
+	// The negated comparison also rejects NaN
+	if !(p >= 0 && p <= 1) {
+		panic("itertools: SampleFraction p must be between 0 and 1")
+	}
+
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if r.Float64() < p && !yield(v) {
+				return
+			}
+		}
+	}
+}
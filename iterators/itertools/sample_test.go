@@ -0,0 +1,162 @@
+package itertools
+
+import (
+	"iter"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+// The seeds are fixed, so the statistical tests are deterministic: they
+// check that the samples have the right distribution, without failing at
+// random once in a while like a test of a random seed would
+func newSampleRand() *rand.Rand {
+	return rand.New(rand.NewPCG(1, 2))
+}
+
+// chiSquared returns the chi-squared statistic of the observed counts, against
+// the same expected count for each
+func chiSquared(observed []int, expected float64) float64 {
+	var x float64
+	for _, o := range observed {
+		d := float64(o) - expected
+		x += d * d / expected
+	}
+
+	return x
+}
+
+// chiSquaredCritical9 is the value of the chi-squared distribution with 9
+// degrees of freedom which is exceeded with a chance of 0.001
+const chiSquaredCritical9 = 27.877
+
+func TestReservoirSample(t *testing.T) {
+	r := newSampleRand()
+
+	for _, tt := range []struct {
+		n, k, expected int
+	}{
+		{10, 3, 3},
+		{3, 3, 3},
+		{2, 5, 2},
+		{0, 5, 0},
+		{10, 0, 0},
+	} {
+		sample := ReservoirSample(naturalsUpTo(tt.n), tt.k, r)
+		if len(sample) != tt.expected {
+			t.Fatalf("sampling %d of %d values: expected %d values, got %v", tt.k, tt.n, tt.expected, sample)
+		}
+
+		// The values are distinct values of the sequence
+		seen := make(map[int]bool)
+		for _, v := range sample {
+			if v < 0 || v >= tt.n || seen[v] {
+				t.Fatalf("sampling %d of %d values: got %v", tt.k, tt.n, sample)
+			}
+			seen[v] = true
+		}
+	}
+}
+
+func TestReservoirSampleUniform(t *testing.T) {
+	const (
+		n      = 10
+		k      = 3
+		trials = 30000
+	)
+
+	r := newSampleRand()
+
+	// Every value is in the sample with a chance of k/n
+	counts := make([]int, n)
+	for range trials {
+		for _, v := range ReservoirSample(naturalsUpTo(n), k, r) {
+			counts[v]++
+		}
+	}
+
+	if x := chiSquared(counts, trials*k/n); x > chiSquaredCritical9 {
+		t.Fatalf("expected every value to be sampled %d times, got %v, chi-squared %.1f", trials*k/n, counts, x)
+	}
+}
+
+func TestReservoirSampleNegative(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for a negative k")
+		}
+	}()
+
+	ReservoirSample(naturalsUpTo(3), -1, newSampleRand())
+}
+
+func TestSampleFraction(t *testing.T) {
+	r := newSampleRand()
+
+	if got := slices.Collect(SampleFraction(naturalsUpTo(100), 0, r)); len(got) != 0 {
+		t.Fatalf("expected no values for p = 0, got %v", got)
+	}
+
+	if got := slices.Collect(SampleFraction(naturalsUpTo(100), 1, r)); !slices.Equal(got, slices.Collect(naturalsUpTo(100))) {
+		t.Fatalf("expected every value for p = 1, got %v", got)
+	}
+
+	// The sample is in the order of the sequence
+	if got := slices.Collect(SampleFraction(naturalsUpTo(1000), 0.5, r)); !slices.IsSorted(got) {
+		t.Fatalf("expected the values in order, got %v", got)
+	}
+}
+
+func TestSampleFractionDistribution(t *testing.T) {
+	const (
+		n      = 10
+		p      = 0.2
+		trials = 30000
+	)
+
+	r := newSampleRand()
+
+	counts := make([]int, n)
+	var total int
+	for range trials {
+		for v := range SampleFraction(naturalsUpTo(n), p, r) {
+			counts[v]++
+			total++
+		}
+	}
+
+	// Every value is sampled with a chance of p
+	if x := chiSquared(counts, trials*p); x > chiSquaredCritical9 {
+		t.Fatalf("expected every value to be sampled %.0f times, got %v, chi-squared %.1f", trials*p, counts, x)
+	}
+
+	// The size of the sample is binomial, with a mean of n*p per trial and a
+	// standard deviation of sqrt(trials*n*p*(1-p)), which is about 219, so
+	// the total is well within 4 of them
+	if expected := trials * n * p; float64(total) < expected-4*219 || float64(total) > expected+4*219 {
+		t.Fatalf("expected about %.0f values, got %d", expected, total)
+	}
+}
+
+func TestSampleFractionInvalid(t *testing.T) {
+	for _, p := range []float64{-0.1, 1.1, math.NaN()} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected a panic for p = %v", p)
+				}
+			}()
+
+			SampleFraction(naturalsUpTo(3), p, newSampleRand())
+		}()
+	}
+}
+
+func TestSampleFractionConformance(t *testing.T) {
+	seqtest.AssertStopsEarly(t, 5, func() iter.Seq[int] {
+		return SampleFraction(naturalsUpTo(100), 0.5, newSampleRand())
+	})
+}
@@ -0,0 +1,51 @@
+package itertools
+
+import (
+	"iter"
+	"sync"
+)
+
+// SyncPull wraps the next function of a pull iterator, such as the one
+// returned by iter.Pull, so that it can be called from multiple goroutines at
+// the same time. Each value is returned to exactly one caller.
+func SyncPull[T any](next func() (T, bool)) func() (T, bool) {
+	var mu sync.Mutex
+
+	return func() (T, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return next()
+	}
+}
+
+// SharedPull converts seq into a pull iterator like iter.Pull, except that
+// next and stop can be called from multiple goroutines at the same time, so
+// that several workers can split the values of a single sequence between them
+// without a channel. Each value is returned to exactly one caller. Once seq
+// is exhausted or stop has been called, next returns the zero value of T and
+// false to every caller. Like iter.Pull, stop must be called unless next has
+// returned false.
+func SharedPull[T any](seq iter.Seq[T]) (next func() (T, bool), stop func()) {
+	var mu sync.Mutex
+
+	pullNext, pullStop := iter.Pull(seq)
+
+	next = func() (T, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return pullNext()
+	}
+
+	// iter.Pull does not allow stop to be called while next is running, so
+	// it waits for the value being pulled to be returned
+	stop = func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		pullStop()
+	}
+
+	return next, stop
+}
@@ -0,0 +1,188 @@
+package itertools
+
+import (
+	"fmt"
+	"iter"
+	"sync"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/testutil"
+)
+
+// counter is a minimal stateful pull iterator
+type counter struct {
+	n int
+}
+
+func (c *counter) Next() (int, bool) {
+	c.n++
+	return c.n, true
+}
+
+func BenchmarkPull(b *testing.B) {
+	b.Run("unsynchronized", func(b *testing.B) {
+		c := &counter{}
+		for range b.N {
+			c.Next()
+		}
+	})
+
+	b.Run("SyncPull", func(b *testing.B) {
+		next := SyncPull((&counter{}).Next)
+		for range b.N {
+			next()
+		}
+	})
+
+	b.Run("SyncPull/parallel", func(b *testing.B) {
+		next := SyncPull((&counter{}).Next)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				next()
+			}
+		})
+	})
+
+	b.Run("iter.Pull", func(b *testing.B) {
+		next, stop := iter.Pull(naturals())
+		defer stop()
+
+		for range b.N {
+			next()
+		}
+	})
+
+	b.Run("iter.Pull/SyncPull/parallel", func(b *testing.B) {
+		next, stop := iter.Pull(naturals())
+		defer stop()
+
+		next = SyncPull(next)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				next()
+			}
+		})
+	})
+}
+
+// drain calls next from the given number of goroutines until it returns
+// false, and counts how often each value was received
+func drain(next func() (int, bool), workers int) map[int]int {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		seen = make(map[int]int)
+	)
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				n, ok := next()
+				if !ok {
+					return
+				}
+
+				mu.Lock()
+				seen[n]++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	return seen
+}
+
+func TestSharedPull(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	const n = 10000
+
+	next, stop := SharedPull(naturalsUpTo(n))
+	defer stop()
+
+	seen := drain(next, 8)
+	if len(seen) != n {
+		t.Fatalf("expected %d distinct values, got %d", n, len(seen))
+	}
+
+	for i := range n {
+		if seen[i] != 1 {
+			t.Fatalf("expected %d to be received once, got %d", i, seen[i])
+		}
+	}
+
+	if _, ok := next(); ok {
+		t.Fatal("expected next to return false once the sequence is exhausted")
+	}
+}
+
+func TestSharedPullStop(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	next, stop := SharedPull(naturals())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		// Stopping while the workers are pulling must not race with them
+		for {
+			if n, _ := next(); n >= 1000 {
+				stop()
+				return
+			}
+		}
+	}()
+
+	seen := drain(next, 4)
+	wg.Wait()
+
+	for n, count := range seen {
+		if count != 1 {
+			t.Fatalf("expected %d to be received once, got %d", n, count)
+		}
+	}
+
+	// Calling stop again has no effect
+	stop()
+}
+
+// BenchmarkFanOut splits 1,000 values between several workers, either by
+// sharing a pull iterator or by sending them on a channel from a producer
+// goroutine, which is the usual way to fan out work
+func BenchmarkFanOut(b *testing.B) {
+	const n = 1000
+
+	for _, workers := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("SharedPull/workers=%d", workers), func(b *testing.B) {
+			for range b.N {
+				next, stop := SharedPull(naturalsUpTo(n))
+				drain(next, workers)
+				stop()
+			}
+		})
+
+		b.Run(fmt.Sprintf("channel/workers=%d", workers), func(b *testing.B) {
+			for range b.N {
+				ch := make(chan int)
+				go func() {
+					defer close(ch)
+
+					for i := range naturalsUpTo(n) {
+						ch <- i
+					}
+				}()
+
+				drain(func() (int, bool) {
+					i, ok := <-ch
+					return i, ok
+				}, workers)
+			}
+		})
+	}
+}
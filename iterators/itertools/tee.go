@@ -0,0 +1,73 @@
+package itertools
+
+import "iter"
+
+// Tee returns two iterators which each yield every value of seq, while seq
+// itself is only iterated once. Values pulled from seq by one iterator are
+// buffered until the other iterator yields them, so the buffer grows with the
+// distance between the two consumers. The returned iterators are single-use
+// and must not be ranged over from different goroutines at the same time.
+//
+// seq is pulled by a goroutine of iter.Pull, which is only stopped once both
+// iterators are done with it: when both loops have ended, or when seq is
+// exhausted. Both iterators therefore have to be ranged over, even if one of
+// the loops breaks at once. If one loop breaks early and the other iterator
+// is dropped without being ranged over, the goroutine leaks.
+func Tee[T any](seq iter.Seq[T]) (iter.Seq[T], iter.Seq[T]) {
+	var (
+		next      func() (T, bool)
+		stop      func()
+		queues    [2][]T
+		done      [2]bool
+		exhausted bool
+	)
+
+	branch := func(i int) iter.Seq[T] {
+		return func(yield func(T) bool) {
+			if next == nil {
+				next, stop = iter.Pull(seq)
+			}
+
+			defer func() {
+				done[i] = true
+				queues[i] = nil
+
+				// The underlying iterator can only be stopped once neither
+				// consumer needs any more values from it
+				if done[0] && done[1] {
+					stop()
+				}
+			}()
+
+			for {
+				var val T
+
+				if len(queues[i]) > 0 {
+					val = queues[i][0]
+					queues[i] = queues[i][1:]
+				} else {
+					if exhausted {
+						return
+					}
+
+					v, ok := next()
+					if !ok {
+						exhausted = true
+						return
+					}
+
+					val = v
+					if !done[1-i] {
+						queues[1-i] = append(queues[1-i], v)
+					}
+				}
+
+				if !yield(val) {
+					return
+				}
+			}
+		}
+	}
+
+	return branch(0), branch(1)
+}
@@ -0,0 +1,123 @@
+package itertools
+
+import (
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+	"github.com/manedurphy/golang-university/internal/testutil"
+)
+
+func TestTeeConformance(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	a, b := Tee(Map(naturalsUpTo(3), double))
+	seqtest.AssertYields(t, a, []int{0, 2, 4})
+	seqtest.AssertYields(t, b, []int{0, 2, 4})
+
+	// The second branch is ranged over once the test is done, which stops
+	// the source and its goroutine
+	first := func() iter.Seq[int] {
+		a, b := Tee(naturalsUpTo(3))
+		t.Cleanup(func() {
+			for range b {
+			}
+		})
+
+		return a
+	}
+	seqtest.AssertStopsEarly(t, 3, first)
+	seqtest.AssertNoYieldAfterFalse(t, 3, first)
+
+	// The source can only be stopped once the second branch is done with it,
+	// which here is the one being checked
+	seqtest.AssertCleanupRuns(t, 3, func() (iter.Seq[int], func() bool) {
+		seq, done := tracked(3)
+
+		a, b := Tee(seq)
+		for range a {
+			break
+		}
+
+		return b, done
+	})
+}
+
+func TestTeeInterleaved(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	a, b := Tee(naturalsUpTo(4))
+
+	nextA, stopA := iter.Pull(a)
+	defer stopA()
+	nextB, stopB := iter.Pull(b)
+	defer stopB()
+
+	// The values pulled by a are buffered for b, and the other way around
+	var got []int
+	for _, next := range []func() (int, bool){nextA, nextA, nextB, nextB, nextB, nextA, nextA, nextB} {
+		v, ok := next()
+		if !ok {
+			t.Fatalf("expected a value, got none after %v", got)
+		}
+
+		got = append(got, v)
+	}
+
+	if expected := []int{0, 1, 0, 1, 2, 2, 3, 3}; !slices.Equal(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestTeeBreakStopsSource(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	seq, done := tracked(100)
+	a, b := Tee(seq)
+
+	for v := range a {
+		if v == 2 {
+			break
+		}
+	}
+
+	if done() {
+		t.Fatal("expected the source to run until the second branch is done")
+	}
+
+	// The second branch gets the values the first one pulled, and then
+	// pulls the rest itself
+	var got []int
+	for v := range b {
+		got = append(got, v)
+		if v == 4 {
+			break
+		}
+	}
+
+	if !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+		t.Fatalf("expected [0 1 2 3 4], got %v", got)
+	}
+
+	if !done() {
+		t.Fatal("expected the source to be stopped once both branches are done")
+	}
+}
+
+func TestTeeExhausted(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	seq, done := tracked(3)
+	a, _ := Tee(seq)
+
+	// Reading one branch to its end exhausts the source, which stops its
+	// goroutine even though the other branch is never ranged over
+	if got := slices.Collect(a); !slices.Equal(got, []int{0, 1, 2}) {
+		t.Fatalf("expected [0 1 2], got %v", got)
+	}
+
+	if !done() {
+		t.Fatal("expected the source to be exhausted")
+	}
+}
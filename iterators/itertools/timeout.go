@@ -0,0 +1,145 @@
+package itertools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+)
+
+// ErrTimeout is what the next function of PullWithTimeout returns, wrapped
+// with the timeout, when the iterator does not produce a value in time
+var ErrTimeout = errors.New("iterator did not yield in time")
+
+// PullWithTimeout is like iter.Pull, except that a call to next gives up
+// with an error wrapping ErrTimeout if seq has not produced a value within
+// d. The value which was being waited for is not lost: the next call to next
+// keeps waiting for it, with a new timeout. Once next has returned false or
+// stop has been called, next returns false and no error.
+//
+// seq runs in a goroutine of its own, so that next can return while seq is
+// blocked. stop must be called once the values are no longer needed. It
+// returns without waiting for seq to stop, since seq may be stuck, and seq's
+// cleanup runs once the value it is producing, if any, is done. Like the
+// functions of iter.Pull, next and stop must not be called from different
+// goroutines at the same time.
+func PullWithTimeout[T any](seq iter.Seq[T], d time.Duration) (next func() (T, bool, error), stop func()) {
+	p := startPull(seq)
+
+	next = func() (T, bool, error) {
+		ctx, cancel := context.WithTimeoutCause(context.Background(), d, fmt.Errorf("%w after %s", ErrTimeout, d))
+		defer cancel()
+
+		return p.next(ctx)
+	}
+
+	return next, p.stop
+}
+
+// PullContext is like PullWithTimeout, except that a call to next gives up
+// with the error of ctx once ctx is done, which bounds how long the values of
+// seq are waited for as a whole, such as with a deadline
+func PullContext[T any](ctx context.Context, seq iter.Seq[T]) (next func() (T, bool, error), stop func()) {
+	p := startPull(seq)
+
+	next = func() (T, bool, error) {
+		return p.next(ctx)
+	}
+
+	return next, p.stop
+}
+
+// asyncPull pulls the values of an iterator in its own goroutine, one value
+// per request
+type asyncPull[T any] struct {
+	requests chan struct{}
+	results  chan pullResult[T]
+	done     chan struct{}
+	// waiting is true while a requested value has not been handed out, such
+	// as after a timeout
+	waiting  bool
+	finished bool
+}
+
+type pullResult[T any] struct {
+	val T
+	ok  bool
+}
+
+func startPull[T any](seq iter.Seq[T]) *asyncPull[T] {
+	p := &asyncPull[T]{
+		requests: make(chan struct{}),
+		results:  make(chan pullResult[T], 1),
+		done:     make(chan struct{}),
+	}
+
+	go func() {
+		// iter.Pull's next and stop must be called from the same goroutine
+		// at a time, so they are only ever called from this one
+		next, stop := iter.Pull(seq)
+		defer stop()
+
+		for {
+			select {
+			case <-p.requests:
+			case <-p.done:
+				return
+			}
+
+			val, ok := next()
+
+			// The buffer holds the one result which can be outstanding, so
+			// this never blocks
+			p.results <- pullResult[T]{val, ok}
+			if !ok {
+				return
+			}
+		}
+	}()
+
+	return p
+}
+
+// next waits for the next value until ctx is done, and then returns the
+// cause of ctx
+func (p *asyncPull[T]) next(ctx context.Context) (T, bool, error) {
+	var zero T
+
+	if p.finished {
+		return zero, false, nil
+	}
+
+	if !p.waiting {
+		p.requests <- struct{}{}
+		p.waiting = true
+	}
+
+	// A value which is already there wins over a context which is done by
+	// now, so that a value which was produced in time is not held back
+	select {
+	case r := <-p.results:
+		return p.handOut(r)
+	default:
+	}
+
+	select {
+	case r := <-p.results:
+		return p.handOut(r)
+	case <-ctx.Done():
+		return zero, false, context.Cause(ctx)
+	}
+}
+
+func (p *asyncPull[T]) handOut(r pullResult[T]) (T, bool, error) {
+	p.waiting = false
+	p.finished = !r.ok
+	return r.val, r.ok, nil
+}
+
+func (p *asyncPull[T]) stop() {
+	if !p.finished {
+		p.finished = true
+		close(p.done)
+	}
+}
@@ -0,0 +1,163 @@
+package itertools
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/testutil"
+)
+
+func TestPullWithTimeout(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	next, stop := PullWithTimeout(slices.Values([]int{1, 2, 3}), time.Second)
+	defer stop()
+
+	var got []int
+	for {
+		n, ok, err := next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		got = append(got, n)
+	}
+
+	if !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("expected [1 2 3], got %v", got)
+	}
+
+	// An exhausted iterator stays exhausted
+	_, ok, err := next()
+	if ok || err != nil {
+		t.Fatalf("expected false and no error, got %t and %v", ok, err)
+	}
+}
+
+func TestPullWithTimeoutSlow(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	release := make(chan struct{})
+	seq := func(yield func(int) bool) {
+		if !yield(1) {
+			return
+		}
+
+		<-release
+		yield(2)
+	}
+
+	next, stop := PullWithTimeout(seq, 10*time.Millisecond)
+	defer stop()
+
+	n, ok, err := next()
+	if n != 1 || !ok || err != nil {
+		t.Fatalf("expected 1, got %d, %t, %v", n, ok, err)
+	}
+
+	for range 2 {
+		_, ok, err = next()
+		if ok || !errors.Is(err, ErrTimeout) {
+			t.Fatalf("expected ErrTimeout, got %t and %v", ok, err)
+		}
+	}
+
+	// The value which timed out is handed out once it is produced
+	close(release)
+
+	n, ok, err = next()
+	if n != 2 || !ok || err != nil {
+		t.Fatalf("expected 2, got %d, %t, %v", n, ok, err)
+	}
+}
+
+func TestPullWithTimeoutStop(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	var (
+		release = make(chan struct{})
+		cleaned = make(chan struct{})
+	)
+	seq := func(yield func(int) bool) {
+		defer close(cleaned)
+
+		<-release
+		yield(1)
+	}
+
+	next, stop := PullWithTimeout(seq, 10*time.Millisecond)
+
+	_, _, err := next()
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected ErrTimeout, got %v", err)
+	}
+
+	// Stop must not wait for the stuck producer
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("expected stop to return while the producer is stuck")
+	}
+
+	_, ok, err := next()
+	if ok || err != nil {
+		t.Fatalf("expected false and no error after stop, got %t and %v", ok, err)
+	}
+
+	// Once the producer is unstuck, its cleanup runs
+	close(release)
+
+	select {
+	case <-cleaned:
+	case <-time.After(time.Second):
+		t.Fatal("expected the producer's cleanup to run")
+	}
+}
+
+func TestPullContext(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	seq := func(yield func(int) bool) {
+		for n := 0; ; n++ {
+			if n > 0 {
+				time.Sleep(20 * time.Millisecond)
+			}
+			if !yield(n) {
+				return
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	next, stop := PullContext(ctx, seq)
+	defer stop()
+
+	var got []int
+	for {
+		n, ok, err := next()
+		if errors.Is(err, context.DeadlineExceeded) {
+			break
+		}
+		if err != nil || !ok {
+			t.Fatalf("expected a value or the deadline, got %t and %v", ok, err)
+		}
+		got = append(got, n)
+	}
+
+	if len(got) < 1 || len(got) > 3 || got[0] != 0 {
+		t.Fatalf("expected the values produced before the deadline, got %v", got)
+	}
+}
@@ -0,0 +1,68 @@
+package itertools
+
+import (
+	"container/heap"
+	"iter"
+)
+
+// TopK reads seq to its end, and returns its k greatest values by less, from
+// the greatest down, or all of its values if there are no more than k. It
+// holds no more than k values at a time, in a heap whose least value is the
+// next one to be replaced by a greater one, so it finds the greatest values
+// of a stream which does not fit in memory, or which would take too long to
+// sort, in a single pass.
+//
+// TopK panics if k is negative.
+func TopK[T any](seq iter.Seq[T], k int, less func(a, b T) bool) []T {
+	if k < 0 {
+		panic("itertools: TopK k must not be negative")
+	}
+
+	if k == 0 {
+		return nil
+	}
+
+	h := &leastHeap[T]{values: make([]T, 0, k), less: less}
+	for v := range seq {
+		if h.Len() < k {
+			heap.Push(h, v)
+			continue
+		}
+
+		if less(h.values[0], v) {
+			h.values[0] = v
+			heap.Fix(h, 0)
+		}
+	}
+
+	// The heap pops from the least, so the values are filled in from the
+	// back
+	top := make([]T, h.Len())
+	for i := len(top) - 1; i >= 0; i-- {
+		top[i] = heap.Pop(h).(T)
+	}
+
+	return top
+}
+
+// leastHeap is a min-heap of values ordered by less
+type leastHeap[T any] struct {
+	values []T
+	less   func(a, b T) bool
+}
+
+func (h *leastHeap[T]) Len() int           { return len(h.values) }
+func (h *leastHeap[T]) Less(i, j int) bool { return h.less(h.values[i], h.values[j]) }
+func (h *leastHeap[T]) Swap(i, j int)      { h.values[i], h.values[j] = h.values[j], h.values[i] }
+func (h *leastHeap[T]) Push(x any)         { h.values = append(h.values, x.(T)) }
+
+func (h *leastHeap[T]) Pop() any {
+	last := h.values[len(h.values)-1]
+
+	// Clear the slot, so that the heap does not keep the value alive
+	var zero T
+	h.values[len(h.values)-1] = zero
+	h.values = h.values[:len(h.values)-1]
+
+	return last
+}
@@ -0,0 +1,60 @@
+package itertools
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestTopK(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []int
+		k        int
+		expected []int
+	}{
+		{"greatest", []int{5, 1, 9, 3, 7, 2, 8}, 3, []int{9, 8, 7}},
+		{"duplicates", []int{4, 4, 1, 4, 2}, 2, []int{4, 4}},
+		{"fewer than k", []int{2, 3, 1}, 5, []int{3, 2, 1}},
+		{"zero", []int{2, 3, 1}, 0, nil},
+		{"empty", nil, 3, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TopK(slices.Values(tt.values), tt.k, cmp.Less[int])
+
+			if !slices.Equal(got, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTopKAgreesWithSort(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	values := make([]int, 10000)
+	for i := range values {
+		values[i] = r.IntN(1000)
+	}
+
+	sorted := slices.Sorted(slices.Values(values))
+	slices.Reverse(sorted)
+
+	for _, k := range []int{1, 10, 100} {
+		if got := TopK(slices.Values(values), k, cmp.Less[int]); !slices.Equal(got, sorted[:k]) {
+			t.Fatalf("top %d: expected %v, got %v", k, sorted[:k], got)
+		}
+	}
+}
+
+func TestTopKLess(t *testing.T) {
+	words := []string{"iterator", "go", "yield", "range", "pull"}
+
+	// The shortest words, by a less which reverses the order
+	shorter := func(a, b string) bool { return len(a) > len(b) }
+	if got := TopK(slices.Values(words), 2, shorter); !slices.Equal(got, []string{"go", "pull"}) {
+		t.Fatalf("expected the two shortest words, got %v", got)
+	}
+}
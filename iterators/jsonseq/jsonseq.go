@@ -0,0 +1,113 @@
+package jsonseq
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+
+	"github.com/manedurphy/golang-university/iterators/textio"
+)
+
+// Array returns an iterator which decodes the elements of the JSON array read
+// from r one at a time, so only the current element is kept in memory rather
+// than the whole array. Decoding stops at the first error, such as an element
+// which does not fit T or data which is not an array, which is yielded along
+// with the zero value of T. Anything after the end of the array is not read.
+func Array[T any](r io.Reader) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		dec := json.NewDecoder(r)
+
+		tok, err := dec.Token()
+		if err != nil {
+			yield(zero, fmt.Errorf("failed to read array: %w", err))
+			return
+		}
+
+		if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+			yield(zero, fmt.Errorf("expected an array, got %v at offset %d", tok, dec.InputOffset()))
+			return
+		}
+
+		for i := 0; dec.More(); i++ {
+			var val T
+
+			err = dec.Decode(&val)
+			if err != nil {
+				yield(zero, fmt.Errorf("failed to decode element %d: %w", i, err))
+				return
+			}
+
+			if !yield(val, nil) {
+				return
+			}
+		}
+
+		// The closing bracket, which is all that can follow the last element
+		_, err = dec.Token()
+		if err != nil {
+			yield(zero, fmt.Errorf("failed to read end of array: %w", err))
+		}
+	}
+}
+
+// Lines returns an iterator which decodes the values of the JSON Lines data
+// read from r, where every line holds a single value. Blank lines are
+// skipped. Decoding stops at the first error, which names the line it was
+// found on.
+func Lines[T any](r io.Reader) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		n := 0
+		for line, err := range textio.Lines(r) {
+			n++
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+
+			var val T
+			err = json.Unmarshal([]byte(line), &val)
+			if err != nil {
+				yield(zero, fmt.Errorf("failed to decode line %d: %w", n, err))
+				return
+			}
+
+			if !yield(val, nil) {
+				return
+			}
+		}
+	}
+}
+
+// WriteLines writes every value of seq to w as JSON Lines, which Lines reads
+// back. Writes are buffered, and w is written to for the last time before
+// WriteLines returns.
+func WriteLines[T any](w io.Writer, seq iter.Seq[T]) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	for val := range seq {
+		// Encode ends every value with a newline
+		err := enc.Encode(val)
+		if err != nil {
+			return fmt.Errorf("failed to encode value: %w", err)
+		}
+	}
+
+	err := bw.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to write values: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package jsonseq
+
+import (
+	"errors"
+	"io"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+type course struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// collect returns the values of seq, and the error which stopped it
+func collect[T any](seq func(func(T, error) bool)) ([]T, error) {
+	var vals []T
+	for val, err := range seq {
+		if err != nil {
+			return vals, err
+		}
+
+		vals = append(vals, val)
+	}
+
+	return vals, nil
+}
+
+func TestArray(t *testing.T) {
+	courses, err := collect(Array[course](strings.NewReader(` [{"id": 1, "name": "Chem-1"}, {"id": 2, "name": "Physics-1"}] `)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []course{{1, "Chem-1"}, {2, "Physics-1"}}
+	if !slices.Equal(courses, expected) {
+		t.Fatalf("expected %v, got %v", expected, courses)
+	}
+
+	courses, err = collect(Array[course](strings.NewReader(`[]`)))
+	if err != nil || len(courses) != 0 {
+		t.Fatalf("expected an empty array to yield nothing, got %v, %v", courses, err)
+	}
+}
+
+func TestArrayErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		n    int
+		err  string
+	}{
+		{name: "not an array", data: `{"id": 1}`, err: "expected an array, got { at offset 1"},
+		{name: "empty", data: ``, err: "failed to read array: EOF"},
+		{name: "wrong type", data: `[{"id": 1}, {"id": "two"}]`, n: 1, err: "failed to decode element 1: json: cannot unmarshal string into Go struct field course.id of type int"},
+		{name: "truncated", data: `[{"id": 1}, {"id": 2}`, n: 2, err: "failed to decode element 2: unexpected end of JSON input"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			courses, err := collect(Array[course](strings.NewReader(test.data)))
+			if err == nil || err.Error() != test.err {
+				t.Fatalf("expected %q, got %v", test.err, err)
+			}
+
+			if len(courses) != test.n {
+				t.Fatalf("expected %d courses before the error, got %d", test.n, len(courses))
+			}
+		})
+	}
+}
+
+func TestArrayBreak(t *testing.T) {
+	// Breaking after the first element never reads the malformed rest
+	r := io.MultiReader(strings.NewReader(`[{"id": 1}, `), iotest.ErrReader(errors.New("read past the first element")))
+
+	for c, err := range Array[course](r) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if c.ID == 1 {
+			break
+		}
+	}
+}
+
+func TestLines(t *testing.T) {
+	expected := []course{{1, "Chem-1"}, {2, "Physics-1"}, {3, "Calculus-1"}}
+
+	var b strings.Builder
+	err := WriteLines(&b, slices.Values(expected))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b.String() != "{\"id\":1,\"name\":\"Chem-1\"}\n{\"id\":2,\"name\":\"Physics-1\"}\n{\"id\":3,\"name\":\"Calculus-1\"}\n" {
+		t.Fatalf("expected one course per line, got %q", b.String())
+	}
+
+	// Blank lines and a missing final newline are fine
+	data := strings.Replace(strings.TrimSuffix(b.String(), "\n"), "\n", "\n\n", 1)
+
+	courses, err := collect(Lines[course](strings.NewReader(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(courses, expected) {
+		t.Fatalf("expected %v, got %v", expected, courses)
+	}
+}
+
+func TestLinesError(t *testing.T) {
+	courses, err := collect(Lines[course](strings.NewReader("{\"id\":1}\n\n{\"id\":\n{\"id\":3}\n")))
+	if err == nil || err.Error() != "failed to decode line 3: unexpected end of JSON input" {
+		t.Fatalf("expected the error to name the line, got %v", err)
+	}
+
+	if len(courses) != 1 {
+		t.Fatalf("expected one course before the error, got %d", len(courses))
+	}
+}
+
+func TestArrayConformance(t *testing.T) {
+	const data = `[{"id": 1}, {"id": 2}, {"id": 3}]`
+
+	newSeq := func() iter.Seq2[course, error] { return Array[course](strings.NewReader(data)) }
+
+	seqtest.AssertYields2(t, newSeq(), []course{{ID: 1}, {ID: 2}, {ID: 3}}, make([]error, 3))
+	seqtest.AssertStopsEarly2(t, 3, newSeq)
+	seqtest.AssertNoYieldAfterFalse2(t, 3, newSeq)
+}
+
+func TestLinesConformance(t *testing.T) {
+	const data = "{\"id\": 1}\n\n{\"id\": 2}\n"
+
+	newSeq := func() iter.Seq2[course, error] { return Lines[course](strings.NewReader(data)) }
+
+	seqtest.AssertYields2(t, newSeq(), []course{{ID: 1}, {ID: 2}}, make([]error, 2))
+	seqtest.AssertStopsEarly2(t, 2, newSeq)
+	seqtest.AssertNoYieldAfterFalse2(t, 2, newSeq)
+}
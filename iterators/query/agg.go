@@ -0,0 +1,151 @@
+package query
+
+// Agg is an aggregate of the rows of a group, such as their number, or the
+// sum of a column
+type Agg struct {
+	fn    string
+	expr  *Expr
+	alias string
+	// accumulator returns a new accumulator of a group
+	accumulator func() accumulator
+}
+
+// accumulator aggregates the values of the rows of a group, one row at a time
+type accumulator interface {
+	add(v any)
+	result() any
+}
+
+// Count is the number of rows
+func Count() Agg {
+	return Agg{fn: "count", accumulator: func() accumulator { return &count{} }}
+}
+
+// Sum is the sum of the numbers e evaluates to, an int64 if they are all
+// integers, and a float64 otherwise
+func Sum(e Expr) Agg {
+	return Agg{fn: "sum", expr: &e, accumulator: func() accumulator { return &sum{} }}
+}
+
+// Avg is the mean of the numbers e evaluates to, or nil if there are none
+func Avg(e Expr) Agg {
+	return Agg{fn: "avg", expr: &e, accumulator: func() accumulator { return &avg{} }}
+}
+
+// Min is the least of the values e evaluates to
+func Min(e Expr) Agg {
+	return Agg{fn: "min", expr: &e, accumulator: func() accumulator { return &extreme{want: -1} }}
+}
+
+// Max is the greatest of the values e evaluates to
+func Max(e Expr) Agg {
+	return Agg{fn: "max", expr: &e, accumulator: func() accumulator { return &extreme{want: 1} }}
+}
+
+// As names the column of the aggregate
+func (a Agg) As(name string) Agg {
+	a.alias = name
+	return a
+}
+
+func (a Agg) String() string {
+	s := a.fn + "()"
+	if a.expr != nil {
+		s = a.fn + "(" + a.expr.String() + ")"
+	}
+
+	if a.alias != "" {
+		s += " AS " + a.alias
+	}
+
+	return s
+}
+
+// name is the name of the column of the aggregate
+func (a Agg) name() string {
+	if a.alias != "" {
+		return a.alias
+	}
+
+	return Agg{fn: a.fn, expr: a.expr}.String()
+}
+
+type count struct {
+	n int64
+}
+
+func (c *count) add(any)     { c.n++ }
+func (c *count) result() any { return c.n }
+
+// sum adds integers as integers until a float comes along. Values which are
+// not numbers are skipped.
+type sum struct {
+	i       int64
+	f       float64
+	isFloat bool
+}
+
+func (s *sum) add(v any) {
+	switch v := v.(type) {
+	case int64:
+		s.i += v
+	case float64:
+		s.f += v
+		s.isFloat = true
+	}
+}
+
+func (s *sum) result() any {
+	if s.isFloat {
+		return s.f + float64(s.i)
+	}
+
+	return s.i
+}
+
+type avg struct {
+	total float64
+	n     int
+}
+
+func (a *avg) add(v any) {
+	switch v := v.(type) {
+	case int64:
+		a.total += float64(v)
+	case float64:
+		a.total += v
+	default:
+		return
+	}
+
+	a.n++
+}
+
+func (a *avg) result() any {
+	if a.n == 0 {
+		return nil
+	}
+
+	return a.total / float64(a.n)
+}
+
+// extreme keeps the value which compares to the others as want, -1 for the
+// least and 1 for the greatest. Values which do not compare with it are
+// skipped.
+type extreme struct {
+	v    any
+	want int
+}
+
+func (e *extreme) add(v any) {
+	if e.v == nil {
+		e.v = v
+		return
+	}
+
+	if n, ok := compare(v, e.v); ok && n == e.want {
+		e.v = v
+	}
+}
+
+func (e *extreme) result() any { return e.v }
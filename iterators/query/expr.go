@@ -0,0 +1,304 @@
+package query
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Expr is an expression over the columns of a row, such as a column, a
+// literal, or a comparison of two expressions. An expression is only a
+// description, which prints as it reads, until the query compiles it against
+// the columns of its input.
+type Expr struct {
+	n node
+}
+
+// node is the description of an expression
+type node interface {
+	fmt.Stringer
+	// bind compiles the expression against the columns of a row, into a
+	// function which evaluates it
+	bind(columns []string) (func(Row) any, error)
+}
+
+// Col is the column of the given name
+func Col(name string) Expr {
+	return Expr{column(name)}
+}
+
+// Lit is a literal value
+func Lit(v any) Expr {
+	return Expr{literal{normalize(v)}}
+}
+
+// expr returns v if it is an Expr, and a literal of v otherwise
+func expr(v any) Expr {
+	if e, ok := v.(Expr); ok {
+		return e
+	}
+
+	return Lit(v)
+}
+
+func (e Expr) String() string { return e.n.String() }
+
+// name is the name of the column an expression is selected as: the name of
+// a column, the alias of one, or else how the expression prints
+func (e Expr) name() string {
+	switch n := e.n.(type) {
+	case column:
+		return string(n)
+	case alias:
+		return n.name
+	}
+
+	return e.String()
+}
+
+// As names the column the expression is selected as
+func (e Expr) As(name string) Expr {
+	return Expr{alias{e, name}}
+}
+
+// Eq is true if the expression is equal to v, which is an Expr or a literal
+func (e Expr) Eq(v any) Expr { return e.compare("=", v) }
+
+// Ne is true if the expression is not equal to v
+func (e Expr) Ne(v any) Expr { return e.compare("!=", v) }
+
+// Lt is true if the expression is less than v
+func (e Expr) Lt(v any) Expr { return e.compare("<", v) }
+
+// Le is true if the expression is less than or equal to v
+func (e Expr) Le(v any) Expr { return e.compare("<=", v) }
+
+// Gt is true if the expression is greater than v
+func (e Expr) Gt(v any) Expr { return e.compare(">", v) }
+
+// Ge is true if the expression is greater than or equal to v
+func (e Expr) Ge(v any) Expr { return e.compare(">=", v) }
+
+func (e Expr) compare(op string, v any) Expr {
+	return Expr{comparison{op: op, left: e, right: expr(v)}}
+}
+
+// And is true if both the expression and other are true
+func (e Expr) And(other Expr) Expr {
+	return Expr{logical{op: "AND", left: e, right: other}}
+}
+
+// Or is true if the expression or other is true
+func (e Expr) Or(other Expr) Expr {
+	return Expr{logical{op: "OR", left: e, right: other}}
+}
+
+// Not is true if the expression is not
+func Not(e Expr) Expr {
+	return Expr{not{e}}
+}
+
+// Asc orders the rows by the expression, from the least value up
+func (e Expr) Asc() Order { return Order{e, false} }
+
+// Desc orders the rows by the expression, from the greatest value down
+func (e Expr) Desc() Order { return Order{e, true} }
+
+// Order is an expression to order rows by, and its direction
+type Order struct {
+	expr Expr
+	desc bool
+}
+
+func (o Order) String() string {
+	if o.desc {
+		return o.expr.String() + " DESC"
+	}
+
+	return o.expr.String()
+}
+
+type column string
+
+func (c column) String() string { return string(c) }
+
+func (c column) bind(columns []string) (func(Row) any, error) {
+	for i, name := range columns {
+		if name == string(c) {
+			return func(r Row) any { return r[i] }, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w %q, the columns are %s", ErrUnknownColumn, string(c), strings.Join(columns, ", "))
+}
+
+type literal struct {
+	v any
+}
+
+func (l literal) String() string {
+	if s, ok := l.v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+
+	return fmt.Sprint(l.v)
+}
+
+func (l literal) bind([]string) (func(Row) any, error) {
+	return func(Row) any { return l.v }, nil
+}
+
+type alias struct {
+	expr Expr
+	name string
+}
+
+func (a alias) String() string { return a.expr.String() + " AS " + a.name }
+
+func (a alias) bind(columns []string) (func(Row) any, error) {
+	return a.expr.n.bind(columns)
+}
+
+type comparison struct {
+	op          string
+	left, right Expr
+}
+
+func (c comparison) String() string {
+	return c.left.String() + " " + c.op + " " + c.right.String()
+}
+
+func (c comparison) bind(columns []string) (func(Row) any, error) {
+	left, err := c.left.n.bind(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := c.right.n.bind(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	// The operator is looked up once, rather than for every row
+	var holds func(int) bool
+	switch c.op {
+	case "=":
+		holds = func(n int) bool { return n == 0 }
+	case "!=":
+		holds = func(n int) bool { return n != 0 }
+	case "<":
+		holds = func(n int) bool { return n < 0 }
+	case "<=":
+		holds = func(n int) bool { return n <= 0 }
+	case ">":
+		holds = func(n int) bool { return n > 0 }
+	case ">=":
+		holds = func(n int) bool { return n >= 0 }
+	}
+
+	return func(r Row) any {
+		n, ok := compare(left(r), right(r))
+		return ok && holds(n)
+	}, nil
+}
+
+type logical struct {
+	op          string
+	left, right Expr
+}
+
+func (l logical) String() string {
+	return "(" + l.left.String() + " " + l.op + " " + l.right.String() + ")"
+}
+
+func (l logical) bind(columns []string) (func(Row) any, error) {
+	left, err := l.left.n.bind(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := l.right.n.bind(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.op == "AND" {
+		return func(r Row) any { return left(r) == true && right(r) == true }, nil
+	}
+
+	return func(r Row) any { return left(r) == true || right(r) == true }, nil
+}
+
+type not struct {
+	expr Expr
+}
+
+func (n not) String() string { return "NOT " + n.expr.String() }
+
+func (n not) bind(columns []string) (func(Row) any, error) {
+	e, err := n.expr.n.bind(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(r Row) any { return e(r) != true }, nil
+}
+
+// normalize converts every integer to an int64, and every float to a
+// float64, so that values of different sizes compare with each other
+func normalize(v any) any {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	}
+
+	return v
+}
+
+// compare compares two normalized values, and reports whether they are of
+// types which compare: two numbers, two strings, or two booleans, where
+// false is less than true
+func compare(a, b any) (int, bool) {
+	switch a := a.(type) {
+	case int64:
+		switch b := b.(type) {
+		case int64:
+			return cmp.Compare(a, b), true
+		case float64:
+			return cmp.Compare(float64(a), b), true
+		}
+	case float64:
+		switch b := b.(type) {
+		case int64:
+			return cmp.Compare(a, float64(b)), true
+		case float64:
+			return cmp.Compare(a, b), true
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			return strings.Compare(a, b), true
+		}
+	case bool:
+		if b, ok := b.(bool); ok {
+			switch {
+			case a == b:
+				return 0, true
+			case b:
+				return -1, true
+			default:
+				return 1, true
+			}
+		}
+	}
+
+	return 0, false
+}
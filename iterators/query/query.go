@@ -0,0 +1,551 @@
+// Package query is a small query engine over iterators, in the style of LINQ
+// and of the query builders of ORMs. A query is built by chaining its
+// operators, which only describe it:
+//
+//	q := query.From("courses", courses).
+//		Where(query.Col("University").Eq("UCB")).
+//		GroupBy(query.Col("Name")).
+//		Aggregate(query.Count().As("courses")).
+//		OrderBy(query.Col("courses").Desc()).
+//		Limit(3)
+//
+// Rows compiles the description into iterators composed like a pipeline of
+// range-over-func functions, which run lazily: nothing is read from the
+// source until the rows are ranged over, and only as much as the rows which
+// are taken need. Explain prints the plan the query compiles to.
+package query
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/manedurphy/golang-university/iterators/itertools"
+	"github.com/manedurphy/golang-university/reflection/structseq"
+)
+
+// ErrUnknownColumn is returned by Rows for an expression which refers to a
+// column its input does not have
+var ErrUnknownColumn = errors.New("unknown column")
+
+// Row is a row of a query, with a value per column
+type Row []any
+
+// Query is a query, which is an operator along with the query it reads from.
+// A Query is immutable: each method returns a new query which reads from it,
+// so a query can be shared, and extended in several ways.
+type Query struct {
+	op operator
+	in *Query
+}
+
+// operator is an operator of a query, such as a filter or a sort
+type operator interface {
+	// describe returns the line of the operator in the plan
+	describe() string
+	// compile compiles the operator against the columns of its input, and
+	// returns its own columns, and a function which applies it to the rows
+	// of its input
+	compile(columns []string) ([]string, func(iter.Seq[Row]) iter.Seq[Row], error)
+}
+
+// From returns a query which scans the values of seq, which is named name in
+// the plan. The columns of a struct are its exported fields, as
+// structseq.Fields yields them, and any other value is a single column named
+// value.
+func From[T any](name string, seq iter.Seq[T]) *Query {
+	var columns []string
+
+	var zero T
+	isStruct := reflect.TypeOf(zero) != nil && reflect.TypeOf(zero).Kind() == reflect.Struct
+	if isStruct {
+		for f := range structseq.Fields(zero) {
+			columns = append(columns, f.Name)
+		}
+	} else {
+		columns = []string{"value"}
+	}
+
+	rows := func(yield func(Row) bool) {
+		for v := range seq {
+			row := make(Row, 0, len(columns))
+			if isStruct {
+				for _, field := range structseq.Fields(v) {
+					row = append(row, normalize(field.Interface()))
+				}
+			} else {
+				row = append(row, normalize(v))
+			}
+
+			if !yield(row) {
+				return
+			}
+		}
+	}
+
+	return &Query{op: scan{name: name, columns: columns, rows: rows}}
+}
+
+// then returns a query which applies op to the rows of q
+func (q *Query) then(op operator) *Query {
+	return &Query{op: op, in: q}
+}
+
+// Where keeps the rows for which cond is true
+func (q *Query) Where(cond Expr) *Query {
+	return q.then(filter{cond})
+}
+
+// Select evaluates exprs for every row, and returns a row of their values.
+// The column of an expression is named after the column it is, its alias,
+// or else how it prints.
+func (q *Query) Select(exprs ...Expr) *Query {
+	return q.then(project{exprs})
+}
+
+// OrderBy sorts the rows by the first order, then by the next one for the
+// rows which are equal by it, and so on. Rows which are equal by every order
+// keep the order they came in. The sort needs every row before it can yield
+// the first one.
+func (q *Query) OrderBy(orders ...Order) *Query {
+	return q.then(sorting{orders})
+}
+
+// Limit keeps the first n rows, and stops reading its input once it has
+// them. It panics if n is negative.
+func (q *Query) Limit(n int) *Query {
+	if n < 0 {
+		panic("query: Limit must not be negative")
+	}
+
+	return q.then(limit{n})
+}
+
+// Grouping is a query whose rows are grouped, and wait to be aggregated
+type Grouping struct {
+	q    *Query
+	keys []Expr
+}
+
+// GroupBy groups the rows which are equal by every key
+func (q *Query) GroupBy(keys ...Expr) *Grouping {
+	return &Grouping{q: q, keys: keys}
+}
+
+// Aggregate returns a row per group, with the values of the keys of the group
+// followed by the aggregates of its rows. The groups are in the order their
+// first row came in. Grouping needs every row before it can yield the first
+// group.
+func (g *Grouping) Aggregate(aggs ...Agg) *Query {
+	return g.q.then(group{keys: g.keys, aggs: aggs})
+}
+
+// plan returns the operators of the query, from the last one back to the
+// scan, once the query is optimized
+func (q *Query) plan() []operator {
+	var ops []operator
+	for ; q != nil; q = q.in {
+		ops = append(ops, q.op)
+	}
+
+	return optimize(ops)
+}
+
+// optimize rewrites the operators of a plan, from the last one back to the
+// scan, into an equivalent plan which runs faster. A limit of a sort only
+// needs the first rows of the sort, which a bounded heap finds without
+// sorting every row.
+func optimize(ops []operator) []operator {
+	optimized := make([]operator, 0, len(ops))
+
+	for i := 0; i < len(ops); i++ {
+		if l, ok := ops[i].(limit); ok && i+1 < len(ops) {
+			if s, ok := ops[i+1].(sorting); ok {
+				optimized = append(optimized, topK{orders: s.orders, n: l.n})
+				i++
+				continue
+			}
+		}
+
+		optimized = append(optimized, ops[i])
+	}
+
+	return optimized
+}
+
+// Explain returns the plan of the query, an operator per line, each reading
+// from the one below it
+func (q *Query) Explain() string {
+	var sb strings.Builder
+
+	for i, op := range q.plan() {
+		if i > 0 {
+			sb.WriteString(strings.Repeat("  ", i) + "-> ")
+		}
+		sb.WriteString(op.describe() + "\n")
+	}
+
+	return sb.String()
+}
+
+// Rows compiles the query, and returns its columns, and an iterator over its
+// rows, which runs the query every time it is ranged over. It returns an
+// error wrapping ErrUnknownColumn if an expression refers to a column which
+// is not there.
+func (q *Query) Rows() ([]string, iter.Seq[Row], error) {
+	ops := q.plan()
+	slices.Reverse(ops)
+
+	var (
+		columns []string
+		apply   []func(iter.Seq[Row]) iter.Seq[Row]
+	)
+
+	for _, op := range ops {
+		var (
+			fn  func(iter.Seq[Row]) iter.Seq[Row]
+			err error
+		)
+
+		columns, fn, err = op.compile(columns)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op.describe(), err)
+		}
+
+		apply = append(apply, fn)
+	}
+
+	// The scan ignores its input, and every other operator wraps the one
+	// before it
+	var rows iter.Seq[Row]
+	for _, fn := range apply {
+		rows = fn(rows)
+	}
+
+	return columns, rows, nil
+}
+
+// bindAll binds every expression to columns
+func bindAll(exprs []Expr, columns []string) ([]func(Row) any, error) {
+	fns := make([]func(Row) any, len(exprs))
+	for i, e := range exprs {
+		fn, err := e.n.bind(columns)
+		if err != nil {
+			return nil, err
+		}
+
+		fns[i] = fn
+	}
+
+	return fns, nil
+}
+
+// joinExprs prints the expressions of an operator
+func joinExprs[T fmt.Stringer](exprs []T) string {
+	s := make([]string, len(exprs))
+	for i, e := range exprs {
+		s[i] = e.String()
+	}
+
+	return strings.Join(s, ", ")
+}
+
+type scan struct {
+	name    string
+	columns []string
+	rows    iter.Seq[Row]
+}
+
+func (s scan) describe() string {
+	return fmt.Sprintf("Scan %s (%s)", s.name, strings.Join(s.columns, ", "))
+}
+
+func (s scan) compile([]string) ([]string, func(iter.Seq[Row]) iter.Seq[Row], error) {
+	return s.columns, func(iter.Seq[Row]) iter.Seq[Row] { return s.rows }, nil
+}
+
+type filter struct {
+	cond Expr
+}
+
+func (f filter) describe() string { return "Filter " + f.cond.String() }
+
+func (f filter) compile(columns []string) ([]string, func(iter.Seq[Row]) iter.Seq[Row], error) {
+	cond, err := f.cond.n.bind(columns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return columns, func(in iter.Seq[Row]) iter.Seq[Row] {
+		return func(yield func(Row) bool) {
+			for row := range in {
+				if cond(row) == true && !yield(row) {
+					return
+				}
+			}
+		}
+	}, nil
+}
+
+type project struct {
+	exprs []Expr
+}
+
+func (p project) describe() string { return "Project " + joinExprs(p.exprs) }
+
+func (p project) compile(columns []string) ([]string, func(iter.Seq[Row]) iter.Seq[Row], error) {
+	fns, err := bindAll(p.exprs, columns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := make([]string, len(p.exprs))
+	for i, e := range p.exprs {
+		names[i] = e.name()
+	}
+
+	return names, func(in iter.Seq[Row]) iter.Seq[Row] {
+		return func(yield func(Row) bool) {
+			for row := range in {
+				out := make(Row, len(fns))
+				for i, fn := range fns {
+					out[i] = fn(row)
+				}
+
+				if !yield(out) {
+					return
+				}
+			}
+		}
+	}, nil
+}
+
+type group struct {
+	keys []Expr
+	aggs []Agg
+}
+
+func (g group) describe() string {
+	s := "Aggregate " + joinExprs(g.aggs)
+	if len(g.keys) > 0 {
+		s += " by " + joinExprs(g.keys)
+	}
+
+	return s
+}
+
+func (g group) compile(columns []string) ([]string, func(iter.Seq[Row]) iter.Seq[Row], error) {
+	keys, err := bindAll(g.keys, columns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := make([]func(Row) any, len(g.aggs))
+	for i, a := range g.aggs {
+		if a.expr == nil {
+			continue
+		}
+
+		values[i], err = a.expr.n.bind(columns)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var names []string
+	for _, k := range g.keys {
+		names = append(names, k.name())
+	}
+	for _, a := range g.aggs {
+		names = append(names, a.name())
+	}
+
+	// groupState is the key of a group, and the accumulators of its
+	// aggregates
+	type groupState struct {
+		key  Row
+		accs []accumulator
+	}
+
+	return names, func(in iter.Seq[Row]) iter.Seq[Row] {
+		return func(yield func(Row) bool) {
+			var groups []*groupState
+			index := make(map[string]*groupState)
+
+			for row := range in {
+				key := make(Row, len(keys))
+				for i, k := range keys {
+					key[i] = k(row)
+				}
+
+				// The key is printed with its types, so that 1 and "1"
+				// are different groups
+				id := fmt.Sprintf("%#v", key)
+				s, ok := index[id]
+				if !ok {
+					s = &groupState{key: key, accs: make([]accumulator, len(g.aggs))}
+					for i, a := range g.aggs {
+						s.accs[i] = a.accumulator()
+					}
+
+					index[id] = s
+					groups = append(groups, s)
+				}
+
+				for i, acc := range s.accs {
+					var v any
+					if values[i] != nil {
+						v = values[i](row)
+					}
+					acc.add(v)
+				}
+			}
+
+			for _, s := range groups {
+				out := slices.Clone(s.key)
+				for _, acc := range s.accs {
+					out = append(out, acc.result())
+				}
+
+				if !yield(out) {
+					return
+				}
+			}
+		}
+	}, nil
+}
+
+type sorting struct {
+	orders []Order
+}
+
+func (s sorting) describe() string { return "Sort " + joinExprs(s.orders) }
+
+// compareRows returns a function which compares two rows by orders. Values
+// which do not compare, such as a number and a string, are equal.
+func compareRows(orders []Order, columns []string) (func(a, b Row) int, error) {
+	exprs := make([]Expr, len(orders))
+	for i, o := range orders {
+		exprs[i] = o.expr
+	}
+
+	fns, err := bindAll(exprs, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(a, b Row) int {
+		for i, fn := range fns {
+			n, _ := compare(fn(a), fn(b))
+			if orders[i].desc {
+				n = -n
+			}
+
+			if n != 0 {
+				return n
+			}
+		}
+
+		return 0
+	}, nil
+}
+
+func (s sorting) compile(columns []string) ([]string, func(iter.Seq[Row]) iter.Seq[Row], error) {
+	compareFn, err := compareRows(s.orders, columns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return columns, func(in iter.Seq[Row]) iter.Seq[Row] {
+		return func(yield func(Row) bool) {
+			rows := slices.Collect(in)
+			slices.SortStableFunc(rows, compareFn)
+
+			for _, row := range rows {
+				if !yield(row) {
+					return
+				}
+			}
+		}
+	}, nil
+}
+
+type limit struct {
+	n int
+}
+
+func (l limit) describe() string { return fmt.Sprintf("Limit %d", l.n) }
+
+func (l limit) compile(columns []string) ([]string, func(iter.Seq[Row]) iter.Seq[Row], error) {
+	return columns, func(in iter.Seq[Row]) iter.Seq[Row] {
+		return func(yield func(Row) bool) {
+			if l.n == 0 {
+				return
+			}
+
+			taken := 0
+			for row := range in {
+				taken++
+				if !yield(row) || taken == l.n {
+					return
+				}
+			}
+		}
+	}, nil
+}
+
+// topK is a sort followed by a limit, which only keeps the first n rows of
+// the sort in a heap
+type topK struct {
+	orders []Order
+	n      int
+}
+
+func (t topK) describe() string { return fmt.Sprintf("TopK %d by %s", t.n, joinExprs(t.orders)) }
+
+func (t topK) compile(columns []string) ([]string, func(iter.Seq[Row]) iter.Seq[Row], error) {
+	compareFn, err := compareRows(t.orders, columns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// numbered is a row along with its position in the input, which breaks
+	// the ties between rows the way the stable sort does
+	type numbered struct {
+		row Row
+		i   int
+	}
+
+	// The greatest values of TopK are the first rows of the sort, so a row
+	// is less than another if it comes after it
+	after := func(a, b numbered) bool {
+		if n := compareFn(a.row, b.row); n != 0 {
+			return n > 0
+		}
+
+		return a.i > b.i
+	}
+
+	return columns, func(in iter.Seq[Row]) iter.Seq[Row] {
+		return func(yield func(Row) bool) {
+			i := 0
+			rows := func(yield func(numbered) bool) {
+				for row := range in {
+					if !yield(numbered{row, i}) {
+						return
+					}
+					i++
+				}
+			}
+
+			for _, r := range itertools.TopK(rows, t.n, after) {
+				if !yield(r.row) {
+					return
+				}
+			}
+		}
+	}, nil
+}
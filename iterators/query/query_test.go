@@ -0,0 +1,247 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+type course struct {
+	ID         int
+	Name       string
+	University string
+	Units      float64
+}
+
+var courses = []course{
+	{1, "Chem-1", "UCB", 4},
+	{2, "Physics-1", "SJSU", 3},
+	{3, "Chem-1", "SJSU", 4},
+	{4, "Calculus-1", "UCB", 5},
+	{5, "Chem-1", "UCB", 3.5},
+	{6, "Physics-1", "UCB", 3},
+}
+
+// run returns the columns and rows of q, and fails the test if it does not
+// compile
+func run(t *testing.T, q *Query) ([]string, []Row) {
+	t.Helper()
+
+	columns, rows, err := q.Rows()
+	if err != nil {
+		t.Fatalf("failed to compile the query: %v", err)
+	}
+
+	return columns, slices.Collect(rows)
+}
+
+func TestScan(t *testing.T) {
+	columns, rows := run(t, From("courses", slices.Values(courses[:2])))
+
+	if expected := []string{"ID", "Name", "University", "Units"}; !slices.Equal(columns, expected) {
+		t.Fatalf("expected columns %v, got %v", expected, columns)
+	}
+
+	// Integers are int64, and floats float64
+	expected := []Row{{int64(1), "Chem-1", "UCB", 4.0}, {int64(2), "Physics-1", "SJSU", 3.0}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("expected %v, got %v", expected, rows)
+	}
+
+	columns, rows = run(t, From("numbers", slices.Values([]int32{3, 1})))
+	if !slices.Equal(columns, []string{"value"}) || !reflect.DeepEqual(rows, []Row{{int64(3)}, {int64(1)}}) {
+		t.Fatalf("expected a value column of int64, got %v %v", columns, rows)
+	}
+}
+
+func TestWhereSelect(t *testing.T) {
+	q := From("courses", slices.Values(courses)).
+		Where(Col("University").Eq("UCB").And(Col("Units").Gt(3))).
+		Select(Col("ID"), Col("Name").As("course"), Col("Units").Ge(4))
+
+	columns, rows := run(t, q)
+	if expected := []string{"ID", "course", "Units >= 4"}; !slices.Equal(columns, expected) {
+		t.Fatalf("expected columns %v, got %v", expected, columns)
+	}
+
+	expected := []Row{{int64(1), "Chem-1", true}, {int64(4), "Calculus-1", true}, {int64(5), "Chem-1", false}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("expected %v, got %v", expected, rows)
+	}
+}
+
+func TestExpressions(t *testing.T) {
+	tests := []struct {
+		cond     Expr
+		expected []int64
+	}{
+		{Col("ID").Lt(3), []int64{1, 2}},
+		{Col("ID").Le(2.5), []int64{1, 2}},
+		{Col("ID").Ne(1).And(Col("ID").Lt(4)), []int64{2, 3}},
+		{Col("Name").Eq("Physics-1").Or(Col("ID").Eq(1)), []int64{1, 2, 6}},
+		{Not(Col("University").Eq("UCB")), []int64{2, 3}},
+		{Col("Units").Gt(Col("ID")), []int64{1, 2, 3, 4}},
+		// A number and a string do not compare, so neither holds
+		{Col("ID").Eq("1").Or(Col("ID").Ne("1")), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cond.String(), func(t *testing.T) {
+			_, rows := run(t, From("courses", slices.Values(courses)).Where(tt.cond).Select(Col("ID")))
+
+			var ids []int64
+			for _, row := range rows {
+				ids = append(ids, row[0].(int64))
+			}
+
+			if !slices.Equal(ids, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, ids)
+			}
+		})
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	q := From("courses", slices.Values(courses)).
+		GroupBy(Col("University")).
+		Aggregate(Count(), Sum(Col("ID")).As("ids"), Sum(Col("Units")), Avg(Col("Units")), Min(Col("Name")), Max(Col("Name")))
+
+	columns, rows := run(t, q)
+	if expected := []string{"University", "count()", "ids", "sum(Units)", "avg(Units)", "min(Name)", "max(Name)"}; !slices.Equal(columns, expected) {
+		t.Fatalf("expected columns %v, got %v", expected, columns)
+	}
+
+	// The groups are in the order of their first row
+	expected := []Row{
+		{"UCB", int64(4), int64(16), 15.5, 3.875, "Calculus-1", "Physics-1"},
+		{"SJSU", int64(2), int64(5), 7.0, 3.5, "Chem-1", "Physics-1"},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("expected %v, got %v", expected, rows)
+	}
+
+	// Without keys, every row is a single group
+	_, rows = run(t, From("courses", slices.Values(courses)).GroupBy().Aggregate(Count()))
+	if !reflect.DeepEqual(rows, []Row{{int64(6)}}) {
+		t.Fatalf("expected a single count of 6, got %v", rows)
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	q := From("courses", slices.Values(courses)).
+		OrderBy(Col("Name").Asc(), Col("Units").Desc()).
+		Select(Col("ID"))
+
+	_, rows := run(t, q)
+
+	var ids []int64
+	for _, row := range rows {
+		ids = append(ids, row[0].(int64))
+	}
+
+	// The Chem-1 courses with 4 units keep their order
+	if expected := []int64{4, 1, 3, 5, 2, 6}; !slices.Equal(ids, expected) {
+		t.Fatalf("expected %v, got %v", expected, ids)
+	}
+}
+
+func TestTopKAgreesWithSort(t *testing.T) {
+	var many []course
+	for i := range 200 {
+		many = append(many, course{ID: i, Name: fmt.Sprintf("Course-%d", i%7), Units: float64(i % 5)})
+	}
+
+	for _, n := range []int{0, 1, 10, 300} {
+		limited := From("courses", slices.Values(many)).OrderBy(Col("Units").Desc(), Col("Name").Asc()).Limit(n)
+
+		// Projecting between the sort and the limit keeps them apart
+		sorted := From("courses", slices.Values(many)).OrderBy(Col("Units").Desc(), Col("Name").Asc()).Select(Col("ID"), Col("Name"), Col("University"), Col("Units"))
+
+		_, got := run(t, limited)
+		_, all := run(t, sorted)
+
+		expected := all[:min(n, len(all))]
+		if !reflect.DeepEqual(got, expected) && !(len(got) == 0 && len(expected) == 0) {
+			t.Fatalf("limit %d: expected %v, got %v", n, expected, got)
+		}
+	}
+}
+
+func TestLazy(t *testing.T) {
+	var scanned int
+	seq := func(yield func(course) bool) {
+		for _, c := range courses {
+			scanned++
+			if !yield(c) {
+				return
+			}
+		}
+	}
+
+	q := From("courses", seq).Where(Col("University").Eq("UCB")).Limit(2)
+
+	_, rows, err := q.Rows()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if scanned != 0 {
+		t.Fatalf("expected nothing to be scanned before the rows are ranged over, got %d", scanned)
+	}
+
+	// The second course of UCB is the fourth course
+	if got := slices.Collect(rows); len(got) != 2 || scanned != 4 {
+		t.Fatalf("expected 2 rows after scanning 4 courses, got %d after %d", len(got), scanned)
+	}
+
+	seqtest.AssertStopsEarly(t, 2, func() iter.Seq[Row] { return rows })
+}
+
+func TestUnknownColumn(t *testing.T) {
+	queries := []*Query{
+		From("courses", slices.Values(courses)).Where(Col("Teacher").Eq("Smith")),
+		From("courses", slices.Values(courses)).Select(Col("Name").As("course")).OrderBy(Col("Name").Asc()),
+		From("courses", slices.Values(courses)).GroupBy(Col("Name")).Aggregate(Sum(Col("Credits"))),
+	}
+
+	for _, q := range queries {
+		if _, _, err := q.Rows(); !errors.Is(err, ErrUnknownColumn) {
+			t.Fatalf("expected ErrUnknownColumn for\n%s\ngot %v", q.Explain(), err)
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	q := From("courses", slices.Values(courses)).
+		Where(Col("University").Eq("UCB").Or(Col("Units").Lt(4))).
+		GroupBy(Col("Name")).
+		Aggregate(Count().As("courses")).
+		OrderBy(Col("courses").Desc()).
+		Limit(2)
+
+	expected := `TopK 2 by courses DESC
+  -> Aggregate count() AS courses by Name
+    -> Filter (University = "UCB" OR Units < 4)
+      -> Scan courses (ID, Name, University, Units)
+`
+	if got := q.Explain(); got != expected {
+		t.Fatalf("expected the plan\n%s\ngot\n%s", expected, got)
+	}
+}
+
+func TestQueryIsImmutable(t *testing.T) {
+	base := From("courses", slices.Values(courses)).Where(Col("University").Eq("UCB"))
+
+	// Two queries extend the same one
+	_, chem := run(t, base.Where(Col("Name").Eq("Chem-1")))
+	_, all := run(t, base)
+
+	if len(chem) != 2 || len(all) != 4 {
+		t.Fatalf("expected 2 and 4 rows, got %d and %d", len(chem), len(all))
+	}
+}
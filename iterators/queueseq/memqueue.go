@@ -0,0 +1,111 @@
+package queueseq
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// MemQueue is an in-memory queue with at-least-once delivery, which stands in
+// for a message broker. Any number of consumers may receive from it at the
+// same time, and each message is delivered to one of them.
+type MemQueue struct {
+	mu       sync.Mutex
+	nextID   uint64
+	ready    []Msg
+	inFlight map[uint64]Msg
+	// notify is closed when a message becomes ready, to wake up the
+	// consumers waiting for one, and replaced with a new channel
+	notify chan struct{}
+}
+
+// NewMemQueue returns an empty queue
+func NewMemQueue() *MemQueue {
+	return &MemQueue{inFlight: make(map[uint64]Msg), notify: make(chan struct{})}
+}
+
+// Publish adds a message with the given body to the end of the queue
+func (q *MemQueue) Publish(body []byte) uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.nextID++
+	q.ready = append(q.ready, Msg{ID: q.nextID, Body: body})
+	q.wake()
+
+	return q.nextID
+}
+
+// wake wakes up the consumers waiting for a message. It must be called with
+// the lock held.
+func (q *MemQueue) wake() {
+	close(q.notify)
+	q.notify = make(chan struct{})
+}
+
+// Len returns the number of messages which are ready to be received, and the
+// number which are in flight
+func (q *MemQueue) Len() (ready, inFlight int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.ready), len(q.inFlight)
+}
+
+// Receive implements Consumer
+func (q *MemQueue) Receive(ctx context.Context) (Msg, error) {
+	for {
+		q.mu.Lock()
+		if len(q.ready) > 0 {
+			msg := q.ready[0]
+			q.ready = q.ready[1:]
+
+			msg.Attempt++
+			q.inFlight[msg.ID] = msg
+			q.mu.Unlock()
+
+			return msg, nil
+		}
+
+		notify := q.notify
+		q.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return Msg{}, ctx.Err()
+		case <-notify:
+		}
+	}
+}
+
+// Ack implements Consumer
+func (q *MemQueue) Ack(_ context.Context, msg Msg) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	_, ok := q.inFlight[msg.ID]
+	if !ok {
+		return fmt.Errorf("message %d is not in flight", msg.ID)
+	}
+
+	delete(q.inFlight, msg.ID)
+	return nil
+}
+
+// Nack implements Consumer. The message is put back at the front of the
+// queue, so that it is the next one to be delivered.
+func (q *MemQueue) Nack(_ context.Context, msg Msg) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	msg, ok := q.inFlight[msg.ID]
+	if !ok {
+		return fmt.Errorf("message %d is not in flight", msg.ID)
+	}
+
+	delete(q.inFlight, msg.ID)
+	q.ready = append([]Msg{msg}, q.ready...)
+	q.wake()
+
+	return nil
+}
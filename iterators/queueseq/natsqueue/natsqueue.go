@@ -0,0 +1,151 @@
+// Package natsqueue is a queueseq.Consumer of a NATS JetStream stream, which
+// receives its messages through a durable pull consumer.
+package natsqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/manedurphy/golang-university/iterators/queueseq"
+)
+
+// Consumer receives the messages of a stream through a durable pull consumer.
+// Every Consumer of the same durable name shares its messages, and each
+// message is delivered to one of them, like the consumers of a MemQueue.
+type Consumer struct {
+	sub *nats.Subscription
+
+	mu sync.Mutex
+	// inFlight holds the NATS message of every message which has been
+	// received and not settled yet, by its sequence in the stream, since a
+	// message is acknowledged through its NATS message
+	inFlight map[uint64]*nats.Msg
+}
+
+var _ queueseq.Consumer = (*Consumer)(nil)
+
+// New subscribes to subject through the durable pull consumer named durable,
+// which is created on the stream holding subject if it does not exist yet.
+// The consumer is created here rather than by the subscription, since the
+// NATS client deletes a consumer it created when its subscription is closed,
+// which would take it from the other Consumers sharing it.
+func New(js nats.JetStreamContext, subject, durable string) (*Consumer, error) {
+	stream, err := js.StreamNameBySubject(subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the stream of %s: %w", subject, err)
+	}
+
+	_, err = js.AddConsumer(stream, &nats.ConsumerConfig{
+		Durable:       durable,
+		FilterSubject: subject,
+		AckPolicy:     nats.AckExplicitPolicy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer %s: %w", durable, err)
+	}
+
+	sub, err := js.PullSubscribe(subject, durable, nats.Bind(stream, durable))
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+	}
+
+	return &Consumer{sub: sub, inFlight: make(map[uint64]*nats.Msg)}, nil
+}
+
+// Receive implements queueseq.Consumer. A fetch which times out on the
+// server, since no message arrived, is retried until ctx is done. The ID of a
+// message is its sequence in the stream.
+func (c *Consumer) Receive(ctx context.Context) (queueseq.Msg, error) {
+	for {
+		msgs, err := c.sub.Fetch(1, nats.Context(ctx))
+		if ctx.Err() != nil {
+			// A message fetched as ctx was cancelled is handed back
+			for _, m := range msgs {
+				m.Nak()
+			}
+
+			return queueseq.Msg{}, ctx.Err()
+		}
+		if errors.Is(err, nats.ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+			continue
+		}
+		if err != nil {
+			return queueseq.Msg{}, err
+		}
+		if len(msgs) == 0 {
+			continue
+		}
+
+		m := msgs[0]
+
+		meta, err := m.Metadata()
+		if err != nil {
+			m.Nak()
+			return queueseq.Msg{}, fmt.Errorf("failed to read the metadata of a message: %w", err)
+		}
+
+		c.mu.Lock()
+		c.inFlight[meta.Sequence.Stream] = m
+		c.mu.Unlock()
+
+		return queueseq.Msg{ID: meta.Sequence.Stream, Body: m.Data, Attempt: int(meta.NumDelivered)}, nil
+	}
+}
+
+// take removes the message with the given ID from the messages in flight,
+// and returns its NATS message
+func (c *Consumer) take(id uint64) (*nats.Msg, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m, ok := c.inFlight[id]
+	if !ok {
+		return nil, fmt.Errorf("message %d is not in flight", id)
+	}
+
+	delete(c.inFlight, id)
+	return m, nil
+}
+
+// Ack implements queueseq.Consumer. It waits for the server to confirm the
+// acknowledgement, until ctx is done or, if ctx has no deadline, for the
+// default wait of the JetStream context.
+func (c *Consumer) Ack(ctx context.Context, msg queueseq.Msg) error {
+	m, err := c.take(msg.ID)
+	if err != nil {
+		return err
+	}
+
+	return m.AckSync(ackOpts(ctx)...)
+}
+
+// Nack implements queueseq.Consumer. The server delivers the message again
+// right away, to this consumer or another one.
+func (c *Consumer) Nack(ctx context.Context, msg queueseq.Msg) error {
+	m, err := c.take(msg.ID)
+	if err != nil {
+		return err
+	}
+
+	return m.Nak(ackOpts(ctx)...)
+}
+
+// ackOpts bounds an acknowledgement by ctx, if ctx has a deadline. The NATS
+// client refuses a context without one.
+func ackOpts(ctx context.Context) []nats.AckOpt {
+	if _, ok := ctx.Deadline(); ok {
+		return []nats.AckOpt{nats.Context(ctx)}
+	}
+
+	return nil
+}
+
+// Close unsubscribes the consumer. The messages still in flight are
+// delivered again once their ack wait expires.
+func (c *Consumer) Close() error {
+	return c.sub.Unsubscribe()
+}
@@ -0,0 +1,184 @@
+package natsqueue
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+
+	"github.com/manedurphy/golang-university/iterators/queueseq"
+)
+
+const subject = "enrollments.chem"
+
+// newStream starts a NATS server with JetStream inside the test, and returns
+// a JetStream context with a stream holding subject
+func newStream(t *testing.T) nats.JetStreamContext {
+	t.Helper()
+
+	s, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, JetStream: true, StoreDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create NATS server: %v", err)
+	}
+
+	go s.Start()
+	t.Cleanup(s.Shutdown)
+
+	if !s.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server did not start")
+	}
+
+	nc, err := nats.Connect(s.ClientURL())
+	if err != nil {
+		t.Fatalf("failed to connect to NATS: %v", err)
+	}
+	t.Cleanup(nc.Close)
+
+	js, err := nc.JetStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = js.AddStream(&nats.StreamConfig{Name: "ENROLLMENTS", Subjects: []string{"enrollments.>"}})
+	if err != nil {
+		t.Fatalf("failed to create stream: %v", err)
+	}
+
+	return js
+}
+
+func publish(t *testing.T, js nats.JetStreamContext, n int) {
+	t.Helper()
+
+	for i := range n {
+		_, err := js.Publish(subject, fmt.Appendf(nil, "%d", i+1))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func newConsumer(t *testing.T, js nats.JetStreamContext) *Consumer {
+	t.Helper()
+
+	c, err := New(js, subject, "workers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	return c
+}
+
+// pending returns the number of messages of the consumer which have not been
+// delivered, and the number which have been delivered but not acknowledged
+func pending(t *testing.T, js nats.JetStreamContext) (ready, inFlight int) {
+	t.Helper()
+
+	info, err := js.ConsumerInfo("ENROLLMENTS", "workers")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return int(info.NumPending), info.NumAckPending
+}
+
+func TestConsumeAcks(t *testing.T) {
+	js := newStream(t)
+	publish(t, js, 3)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var bodies []string
+	for msg, err := range queueseq.Consume(ctx, newConsumer(t, js)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		bodies = append(bodies, string(msg.Body))
+		if len(bodies) == 3 {
+			cancel()
+		}
+	}
+
+	if fmt.Sprint(bodies) != "[1 2 3]" {
+		t.Fatalf("expected every message in order, got %v", bodies)
+	}
+
+	if ready, inFlight := pending(t, js); ready != 0 || inFlight != 0 {
+		t.Fatalf("expected every message to be acked, got %d ready and %d in flight", ready, inFlight)
+	}
+}
+
+func TestConsumeBreakRedelivers(t *testing.T) {
+	js := newStream(t)
+	publish(t, js, 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for msg, err := range queueseq.Consume(ctx, newConsumer(t, js)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if msg.ID == 2 {
+			break
+		}
+	}
+
+	// A second consumer of the same durable gets the message the first one
+	// handed back
+	msg, err := newConsumer(t, js).Receive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.ID != 2 || msg.Attempt != 2 {
+		t.Fatalf("expected message 2 on its second attempt, got %d on attempt %d", msg.ID, msg.Attempt)
+	}
+}
+
+func TestReceiveWaits(t *testing.T) {
+	js := newStream(t)
+	c := newConsumer(t, js)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		js.Publish(subject, []byte("late"))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	msg, err := c.Receive(ctx)
+	if err != nil || string(msg.Body) != "late" {
+		t.Fatalf("expected the late message, got %q and %v", msg.Body, err)
+	}
+
+	err = c.Ack(ctx, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Ack(ctx, msg); err == nil {
+		t.Fatal("expected an error for a message which is no longer in flight")
+	}
+}
+
+func TestReceiveCancelled(t *testing.T) {
+	js := newStream(t)
+	c := newConsumer(t, js)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := c.Receive(ctx)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected the deadline of ctx, got %v", err)
+	}
+}
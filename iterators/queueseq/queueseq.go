@@ -0,0 +1,105 @@
+package queueseq
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+// Msg is a message consumed from a queue
+type Msg struct {
+	ID   uint64
+	Body []byte
+	// Attempt is the number of times the message has been delivered,
+	// starting at 1. A message which was handed back is delivered again.
+	Attempt int
+}
+
+// Consumer receives messages from a queue, such as a subscription of a NATS
+// or Kafka client. A message which has been received is in flight until it is
+// acknowledged, which removes it from the queue, or handed back, which makes
+// it available to be received again.
+type Consumer interface {
+	// Receive blocks until a message is available or ctx is done
+	Receive(ctx context.Context) (Msg, error)
+	Ack(ctx context.Context, msg Msg) error
+	Nack(ctx context.Context, msg Msg) error
+}
+
+// Consume returns an iterator over the messages received by c. A message is
+// acknowledged once the loop body has finished with it and moves on to the
+// next one. If the loop body breaks out of the loop, or panics, the message
+// is handed back to the queue instead, so that it is delivered again, to
+// this consumer or another one.
+//
+// Consuming stops without an error once ctx is done, which is how a consumer
+// stops after handling a message. It stops with an error if a message cannot
+// be received or acknowledged.
+//
+//	for msg, err := range queueseq.Consume(ctx, q) {
+//		if err != nil {
+//			return err
+//		}
+//
+//		if !handle(msg) {
+//			break // msg is delivered again
+//		}
+//	}
+func Consume(ctx context.Context, c Consumer) iter.Seq2[Msg, error] {
+	return func(yield func(Msg, error) bool) {
+		for {
+			msg, err := c.Receive(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					yield(Msg{}, fmt.Errorf("failed to receive message: %w", err))
+				}
+				return
+			}
+
+			// A message which arrived as ctx was cancelled is handed back,
+			// rather than left in flight
+			if ctx.Err() != nil {
+				c.Nack(context.WithoutCancel(ctx), msg)
+				return
+			}
+
+			more, err := deliver(ctx, c, msg, yield)
+			if err != nil {
+				yield(Msg{}, err)
+				return
+			}
+
+			if !more {
+				return
+			}
+		}
+	}
+}
+
+// deliver yields msg, and acknowledges it if the loop body asks for more
+// messages, or hands it back if the loop body breaks or panics
+func deliver(ctx context.Context, c Consumer, msg Msg, yield func(Msg, error) bool) (bool, error) {
+	// The message is settled even if ctx was cancelled while the loop body
+	// was handling it
+	ctx = context.WithoutCancel(ctx)
+
+	acked := false
+	defer func() {
+		if !acked {
+			c.Nack(ctx, msg)
+		}
+	}()
+
+	if !yield(msg, nil) {
+		return false, nil
+	}
+
+	acked = true
+
+	err := c.Ack(ctx, msg)
+	if err != nil {
+		return false, fmt.Errorf("failed to ack message %d: %w", msg.ID, err)
+	}
+
+	return true, nil
+}
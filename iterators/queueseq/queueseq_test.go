@@ -0,0 +1,182 @@
+package queueseq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func publish(q *MemQueue, n int) {
+	for i := range n {
+		q.Publish([]byte(fmt.Sprint(i + 1)))
+	}
+}
+
+func TestConsumeAcks(t *testing.T) {
+	q := NewMemQueue()
+	publish(q, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var bodies []string
+	for msg, err := range Consume(ctx, q) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		bodies = append(bodies, string(msg.Body))
+		if len(bodies) == 3 {
+			// Cancelling stops the iteration after this message is acked
+			cancel()
+		}
+	}
+
+	if fmt.Sprint(bodies) != "[1 2 3]" {
+		t.Fatalf("expected every message in order, got %v", bodies)
+	}
+
+	if ready, inFlight := q.Len(); ready != 0 || inFlight != 0 {
+		t.Fatalf("expected every message to be acked, got %d ready and %d in flight", ready, inFlight)
+	}
+}
+
+func TestConsumeBreakRedelivers(t *testing.T) {
+	q := NewMemQueue()
+	publish(q, 2)
+
+	ctx := context.Background()
+
+	for msg, err := range Consume(ctx, q) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if msg.ID == 2 {
+			break
+		}
+	}
+
+	if ready, inFlight := q.Len(); ready != 1 || inFlight != 0 {
+		t.Fatalf("expected the second message back in the queue, got %d ready and %d in flight", ready, inFlight)
+	}
+
+	msg, err := q.Receive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.ID != 2 || msg.Attempt != 2 {
+		t.Fatalf("expected the second attempt at message 2, got %+v", msg)
+	}
+}
+
+func TestConsumePanicRedelivers(t *testing.T) {
+	q := NewMemQueue()
+	publish(q, 1)
+
+	func() {
+		defer func() {
+			recover()
+		}()
+
+		for range Consume(context.Background(), q) {
+			panic("handler failed")
+		}
+	}()
+
+	if ready, inFlight := q.Len(); ready != 1 || inFlight != 0 {
+		t.Fatalf("expected the message back in the queue, got %d ready and %d in flight", ready, inFlight)
+	}
+}
+
+func TestConsumeWaits(t *testing.T) {
+	q := NewMemQueue()
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		publish(q, 1)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for msg, err := range Consume(ctx, q) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(msg.Body) != "1" {
+			t.Fatalf("expected the published message, got %q", msg.Body)
+		}
+
+		return
+	}
+
+	t.Fatal("expected a message before the timeout")
+}
+
+type failingConsumer struct {
+	*MemQueue
+}
+
+var errAck = errors.New("connection lost")
+
+func (failingConsumer) Ack(context.Context, Msg) error {
+	return errAck
+}
+
+func TestConsumeAckError(t *testing.T) {
+	q := NewMemQueue()
+	publish(q, 2)
+
+	var (
+		n   int
+		err error
+	)
+
+	for _, err = range Consume(context.Background(), failingConsumer{q}) {
+		if err != nil {
+			break
+		}
+
+		n++
+	}
+
+	if !errors.Is(err, errAck) || n != 1 {
+		t.Fatalf("expected the ack error after one message, got %d messages and %v", n, err)
+	}
+}
+
+// cancellingConsumer cancels the context of the consumer as soon as it has
+// received a message, like a shutdown which races with a delivery
+type cancellingConsumer struct {
+	*MemQueue
+	cancel context.CancelFunc
+}
+
+func (c cancellingConsumer) Receive(ctx context.Context) (Msg, error) {
+	msg, err := c.MemQueue.Receive(ctx)
+	c.cancel()
+
+	return msg, err
+}
+
+func TestConsumeCancelledAfterReceive(t *testing.T) {
+	q := NewMemQueue()
+	publish(q, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for msg, err := range Consume(ctx, cancellingConsumer{q, cancel}) {
+		t.Fatalf("expected no message once ctx is cancelled, got %+v and %v", msg, err)
+	}
+
+	// The message is handed back, instead of staying in flight
+	if ready, inFlight := q.Len(); ready != 1 || inFlight != 0 {
+		t.Fatalf("expected the message to be ready again, got %d ready and %d in flight", ready, inFlight)
+	}
+}
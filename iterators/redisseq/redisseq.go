@@ -0,0 +1,98 @@
+package redisseq
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Field is a field of a hash and its value
+type Field struct {
+	Name  string
+	Value string
+}
+
+// page fetches the page of results at cursor, and returns them along with
+// the cursor of the next page, which is zero after the last page
+type page func(ctx context.Context, cursor uint64) ([]string, uint64, error)
+
+// cursor returns an iterator over the results of every page, starting at
+// cursor zero. The next page is only fetched once the consumer has ranged
+// over the results of the one before it.
+func cursor(ctx context.Context, command string, fetch page) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		var cur uint64
+
+		for {
+			results, next, err := fetch(ctx, cur)
+			if err != nil {
+				yield("", fmt.Errorf("failed to %s at cursor %d: %w", command, cur, err))
+				return
+			}
+
+			for _, result := range results {
+				if !yield(result, nil) {
+					return
+				}
+			}
+
+			if next == 0 {
+				return
+			}
+
+			cur = next
+		}
+	}
+}
+
+// Scan returns an iterator over the keys matching the pattern match, such as
+// "course:*", or every key if match is empty. count is a hint of how many
+// keys each SCAN returns, and zero leaves it to the server.
+//
+// SCAN walks the keyspace in a way that never misses a key which exists for
+// the whole iteration, but it may return a key more than once, and keys added
+// or removed during the iteration may or may not be yielded. Fetching stops
+// at the first error, which is yielded along with an empty key.
+func Scan(ctx context.Context, c redis.Cmdable, match string, count int64) iter.Seq2[string, error] {
+	return cursor(ctx, "scan", func(ctx context.Context, cur uint64) ([]string, uint64, error) {
+		return c.Scan(ctx, cur, match, count).Result()
+	})
+}
+
+// SScan is like Scan, but iterates over the members of the set at key
+func SScan(ctx context.Context, c redis.Cmdable, key, match string, count int64) iter.Seq2[string, error] {
+	return cursor(ctx, "sscan", func(ctx context.Context, cur uint64) ([]string, uint64, error) {
+		return c.SScan(ctx, key, cur, match, count).Result()
+	})
+}
+
+// HScan is like Scan, but iterates over the fields of the hash at key whose
+// names match, along with their values
+func HScan(ctx context.Context, c redis.Cmdable, key, match string, count int64) iter.Seq2[Field, error] {
+	return func(yield func(Field, error) bool) {
+		// HSCAN returns the name of each field followed by its value
+		var name string
+		odd := false
+
+		for s, err := range cursor(ctx, "hscan", func(ctx context.Context, cur uint64) ([]string, uint64, error) {
+			return c.HScan(ctx, key, cur, match, count).Result()
+		}) {
+			if err != nil {
+				yield(Field{}, err)
+				return
+			}
+
+			odd = !odd
+			if odd {
+				name = s
+				continue
+			}
+
+			if !yield(Field{Name: name, Value: s}, nil) {
+				return
+			}
+		}
+	}
+}
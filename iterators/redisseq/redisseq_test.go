@@ -0,0 +1,157 @@
+package redisseq
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newClient(t *testing.T) (*redis.Client, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		client.Close()
+	})
+
+	return client, mr
+}
+
+// collect returns the sorted values of seq, since Redis does not guarantee
+// any order
+func collect[T any](t *testing.T, seq iter.Seq2[T, error], cmp func(a, b T) int) []T {
+	t.Helper()
+
+	var values []T
+	for v, err := range seq {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		values = append(values, v)
+	}
+
+	slices.SortFunc(values, cmp)
+	return values
+}
+
+func TestScan(t *testing.T) {
+	client, mr := newClient(t)
+
+	var expected []string
+	for i := range 25 {
+		key := fmt.Sprintf("course:%02d", i)
+		mr.Set(key, "Chem-1")
+		expected = append(expected, key)
+	}
+	mr.Set("student:1", "Ada")
+
+	keys := collect(t, Scan(context.Background(), client, "course:*", 10), strings.Compare)
+	if !slices.Equal(keys, expected) {
+		t.Fatalf("expected %q, got %q", expected, keys)
+	}
+}
+
+// miniredis returns every key from a single SCAN, but pages through the
+// members of a set like Redis does, so the pages are tested with SSCAN
+func TestSScan(t *testing.T) {
+	client, mr := newClient(t)
+
+	var expected []string
+	for i := range 25 {
+		member := fmt.Sprintf("%02d", i)
+		mr.SetAdd("university:UCSF", member)
+		expected = append(expected, member)
+	}
+
+	var scans int
+	client.AddHook(countHook{command: "sscan", n: &scans})
+
+	members := collect(t, SScan(context.Background(), client, "university:UCSF", "", 10), strings.Compare)
+	if !slices.Equal(members, expected) {
+		t.Fatalf("expected every member, got %q", members)
+	}
+
+	if scans != 3 {
+		t.Fatalf("expected 3 pages of 10 members, got %d", scans)
+	}
+}
+
+func TestSScanBreak(t *testing.T) {
+	client, mr := newClient(t)
+
+	for i := range 100 {
+		mr.SetAdd("university:UCSF", fmt.Sprint(i))
+	}
+
+	var scans int
+	client.AddHook(countHook{command: "sscan", n: &scans})
+
+	for range SScan(context.Background(), client, "university:UCSF", "", 10) {
+		break
+	}
+
+	if scans != 1 {
+		t.Fatalf("expected a single SSCAN, got %d", scans)
+	}
+}
+
+func TestHScan(t *testing.T) {
+	client, mr := newClient(t)
+
+	mr.HSet("course:1", "name", "Chem-1", "university", "UCSF")
+
+	fields := collect(t, HScan(context.Background(), client, "course:1", "", 0), func(a, b Field) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	expected := []Field{{"name", "Chem-1"}, {"university", "UCSF"}}
+	if !slices.Equal(fields, expected) {
+		t.Fatalf("expected %v, got %v", expected, fields)
+	}
+}
+
+func TestScanError(t *testing.T) {
+	client, mr := newClient(t)
+	mr.SetError("LOADING Redis is loading the dataset in memory")
+
+	var err error
+	for _, err = range Scan(context.Background(), client, "", 0) {
+	}
+
+	if err == nil || !strings.HasPrefix(err.Error(), "failed to scan at cursor 0: LOADING") {
+		t.Fatalf("expected the error of the server, got %v", err)
+	}
+}
+
+// countHook counts the commands with the given name which are sent
+type countHook struct {
+	command string
+	n       *int
+}
+
+func (h countHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h countHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if cmd.Name() == h.command {
+			*h.n++
+		}
+
+		return next(ctx, cmd)
+	}
+}
+
+func (h countHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
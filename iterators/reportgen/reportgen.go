@@ -0,0 +1,122 @@
+package reportgen
+
+import (
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"slices"
+	"text/template"
+)
+
+type (
+	// Row is what the row template of a report is executed with
+	Row[K, V any] struct {
+		// Index is the position of the row in the report, starting at 0
+		Index int
+		Key   K
+		Value V
+	}
+
+	// Summary is what the footer template of a report is executed with
+	Summary struct {
+		// Data is what the report was rendered with
+		Data any
+		// Rows is the number of rows in the report
+		Rows int
+	}
+
+	flusher interface {
+		Flush() error
+	}
+)
+
+// Text renders a report of rows through tmpl, which must define a template
+// named "row", and may define templates named "header" and "footer". The
+// header is executed once with data, the row template once per row with a
+// Row, and the footer once with a Summary, so the rows are never collected
+// into a slice. If w has a Flush method, such as a *tabwriter.Writer, it is
+// called once the report is written.
+//
+//	tmpl := template.Must(template.New("report").Parse(`
+//	{{- define "header"}}university\tcourses\n{{end}}
+//	{{- define "row"}}{{.Key}}\t{{.Value}}\n{{end}}`))
+//
+//	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+//	err := reportgen.Text(w, tmpl, nil, reportgen.Sorted(counts))
+func Text[K, V any](w io.Writer, tmpl *template.Template, data any, rows iter.Seq2[K, V]) error {
+	if tmpl.Lookup("row") == nil {
+		return fmt.Errorf("template %s does not define a row template", tmpl.Name())
+	}
+
+	if tmpl.Lookup("header") != nil {
+		err := tmpl.ExecuteTemplate(w, "header", data)
+		if err != nil {
+			return fmt.Errorf("failed to render header: %w", err)
+		}
+	}
+
+	n := 0
+	for key, value := range rows {
+		err := tmpl.ExecuteTemplate(w, "row", Row[K, V]{Index: n, Key: key, Value: value})
+		if err != nil {
+			return fmt.Errorf("failed to render row %d: %w", n, err)
+		}
+		n++
+	}
+
+	if tmpl.Lookup("footer") != nil {
+		err := tmpl.ExecuteTemplate(w, "footer", Summary{Data: data, Rows: n})
+		if err != nil {
+			return fmt.Errorf("failed to render footer: %w", err)
+		}
+	}
+
+	if f, ok := w.(flusher); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
+
+// CSV writes a report of rows to w as CSV, with header as its first record
+// unless it is nil. record turns a row into the fields of its record, and
+// the slice it returns may be reused for the next row.
+func CSV[K, V any](w io.Writer, header []string, rows iter.Seq2[K, V], record func(K, V) []string) error {
+	cw := csv.NewWriter(w)
+
+	if header != nil {
+		err := cw.Write(header)
+		if err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+	}
+
+	n := 0
+	for key, value := range rows {
+		err := cw.Write(record(key, value))
+		if err != nil {
+			return fmt.Errorf("failed to write row %d: %w", n, err)
+		}
+		n++
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// Sorted returns an iterator over the entries of m in the order of their
+// keys, which is how an aggregate held in a map is usually reported. Only
+// the keys are sorted up front; the values are looked up as the iteration
+// goes.
+func Sorted[K cmp.Ordered, V any](m map[K]V) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, key := range slices.Sorted(maps.Keys(m)) {
+			if !yield(key, m[key]) {
+				return
+			}
+		}
+	}
+}
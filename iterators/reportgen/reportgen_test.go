@@ -0,0 +1,147 @@
+package reportgen
+
+import (
+	"errors"
+	"iter"
+	"strings"
+	"testing"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+var counts = map[string]int{"UCSF": 4, "SJSU": 1, "UCB": 3, "SDSU": 2}
+
+func TestText(t *testing.T) {
+	tmpl := template.Must(template.New("report").Parse(`
+{{- define "header"}}university{{range .}}	{{.}}{{end}}
+{{end}}
+{{- define "row"}}{{.Key}}	{{.Value}}
+{{end}}
+{{- define "footer"}}{{.Rows}} universities, counted by {{index .Data 0}}
+{{end}}`))
+
+	var b strings.Builder
+	err := Text(tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0), tmpl, []string{"mutex"}, Sorted(counts))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `university  mutex
+SDSU        2
+SJSU        1
+UCB         3
+UCSF        4
+4 universities, counted by mutex
+`
+	if b.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, b.String())
+	}
+}
+
+func TestTextIndex(t *testing.T) {
+	tmpl := template.Must(template.New("report").Parse(`{{define "row"}}{{.Index}}:{{.Key}} {{end}}`))
+
+	var b strings.Builder
+	err := Text(&b, tmpl, nil, Sorted(counts))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "0:SDSU 1:SJSU 2:UCB 3:UCSF "
+	if b.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, b.String())
+	}
+}
+
+func TestTextNoRow(t *testing.T) {
+	tmpl := template.Must(template.New("report").Parse(`{{define "header"}}university{{end}}`))
+
+	err := Text(&strings.Builder{}, tmpl, nil, Sorted(counts))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestTextRowError(t *testing.T) {
+	tmpl := template.Must(template.New("report").Parse(`{{define "row"}}{{.Value.Missing}}{{end}}`))
+
+	// The rows after the one which failed are never asked for
+	pulled := 0
+	rows := func(yield func(string, int) bool) {
+		for key, value := range Sorted(counts) {
+			pulled++
+			if !yield(key, value) {
+				return
+			}
+		}
+	}
+
+	err := Text(&strings.Builder{}, tmpl, nil, rows)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "row 0") {
+		t.Fatalf("expected the error to name row 0, got %v", err)
+	}
+	if pulled != 1 {
+		t.Fatalf("expected 1 row to be pulled, got %d", pulled)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	var b strings.Builder
+	record := make([]string, 2)
+	err := CSV(&b, []string{"university", "courses"}, Sorted(map[string]string{"UCB": "1", "SJSU, CA": "2"}), func(k, v string) []string {
+		record[0], record[1] = k, v
+		return record
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "university,courses\n\"SJSU, CA\",2\nUCB,1\n"
+	if b.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, b.String())
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestCSVWriteError(t *testing.T) {
+	var rows iter.Seq2[string, int] = Sorted(counts)
+
+	err := CSV(failingWriter{}, nil, rows, func(k string, _ int) []string {
+		return []string{k}
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestSortedBreak(t *testing.T) {
+	var keys []string
+	for key := range Sorted(counts) {
+		keys = append(keys, key)
+		if len(keys) == 2 {
+			break
+		}
+	}
+
+	if strings.Join(keys, ",") != "SDSU,SJSU" {
+		t.Fatalf("expected SDSU,SJSU, got %v", keys)
+	}
+}
+
+func TestSortedConformance(t *testing.T) {
+	newSeq := func() iter.Seq2[string, int] { return Sorted(counts) }
+
+	seqtest.AssertYields2(t, newSeq(), []string{"SDSU", "SJSU", "UCB", "UCSF"}, []int{2, 1, 3, 4})
+	seqtest.AssertStopsEarly2(t, len(counts), newSeq)
+	seqtest.AssertNoYieldAfterFalse2(t, len(counts), newSeq)
+}
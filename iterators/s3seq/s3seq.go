@@ -0,0 +1,99 @@
+package s3seq
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/manedurphy/golang-university/iterators/ioseq"
+)
+
+// ObjectInfo describes an object of a bucket
+type ObjectInfo = minio.ObjectInfo
+
+// Client lists and downloads the objects of an S3-compatible object store,
+// such as MinIO or S3 itself
+type Client struct {
+	core     minio.Core
+	pageSize int
+}
+
+// New returns a Client which lists up to pageSize keys per request, or as
+// many as the server allows if pageSize is zero
+func New(client *minio.Client, pageSize int) *Client {
+	return &Client{core: minio.Core{Client: client}, pageSize: pageSize}
+}
+
+// ListObjects returns an iterator over the objects of bucket whose keys
+// start with prefix, in lexical order of their keys. Each request returns a
+// page of objects along with a continuation token, which is sent with the
+// request for the next page. The next page is only requested once the
+// consumer has ranged over the one before it. Listing stops at the first
+// error, which is yielded along with an empty ObjectInfo.
+func (c *Client) ListObjects(ctx context.Context, bucket, prefix string) iter.Seq2[ObjectInfo, error] {
+	return func(yield func(ObjectInfo, error) bool) {
+		token := ""
+
+		for page := 1; ; page++ {
+			// The client does not take a context for a single page, so it is
+			// checked between pages instead
+			err := ctx.Err()
+			if err != nil {
+				yield(ObjectInfo{}, err)
+				return
+			}
+
+			result, err := c.core.ListObjectsV2(bucket, prefix, "", token, "", c.pageSize)
+			if err != nil {
+				yield(ObjectInfo{}, fmt.Errorf("failed to list page %d of %s: %w", page, bucket, err))
+				return
+			}
+
+			for _, obj := range result.Contents {
+				if !yield(obj, nil) {
+					return
+				}
+			}
+
+			if !result.IsTruncated {
+				return
+			}
+
+			token = result.NextContinuationToken
+		}
+	}
+}
+
+// Download returns a reader over the content of the object at key, which is
+// streamed from the server as it is read, and must be closed
+func (c *Client) Download(ctx context.Context, bucket, key string) (io.ReadCloser, ObjectInfo, error) {
+	body, info, _, err := c.core.GetObject(ctx, bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, ObjectInfo{}, fmt.Errorf("failed to download %s/%s: %w", bucket, key, err)
+	}
+
+	return body, info, nil
+}
+
+// Chunks returns an iterator over the content of the object at key in chunks
+// of size bytes, as ioseq.Chunks does for any reader. The object is only
+// requested once the iteration starts, and its body is closed when it ends.
+func (c *Client) Chunks(ctx context.Context, bucket, key string, size int) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		body, _, err := c.Download(ctx, bucket, key)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer body.Close()
+
+		for chunk, err := range ioseq.Chunks(body, size) {
+			if !yield(chunk, err) || err != nil {
+				return
+			}
+		}
+	}
+}
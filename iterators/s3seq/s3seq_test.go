@@ -0,0 +1,120 @@
+package s3seq
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"github.com/manedurphy/golang-university/iterators/s3seq/s3test"
+)
+
+func newClient(t *testing.T, pageSize int) (*Client, *s3test.Server) {
+	t.Helper()
+
+	srv := s3test.NewServer()
+	t.Cleanup(srv.Close)
+
+	client, err := minio.New(srv.Endpoint(), &minio.Options{
+		Creds:        credentials.NewStaticV4("university", "university", ""),
+		Region:       "us-east-1",
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return New(client, pageSize), srv
+}
+
+func TestListObjects(t *testing.T) {
+	client, srv := newClient(t, 10)
+
+	var expected []string
+	for i := range 25 {
+		key := fmt.Sprintf("syllabi/%02d.txt", i)
+		srv.Put("courses", key, []byte("syllabus"))
+		expected = append(expected, key)
+	}
+	srv.Put("courses", "videos/01.mp4", []byte("video"))
+
+	var keys []string
+	for obj, err := range client.ListObjects(context.Background(), "courses", "syllabi/") {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		keys = append(keys, obj.Key)
+	}
+
+	if !slices.Equal(keys, expected) {
+		t.Fatalf("expected %q, got %q", expected, keys)
+	}
+
+	if lists, _ := srv.Requests(); lists != 3 {
+		t.Fatalf("expected 3 pages of 10 keys, got %d", lists)
+	}
+}
+
+func TestListObjectsBreak(t *testing.T) {
+	client, srv := newClient(t, 10)
+
+	for i := range 100 {
+		srv.Put("courses", fmt.Sprintf("syllabi/%02d.txt", i), nil)
+	}
+
+	for range client.ListObjects(context.Background(), "courses", "") {
+		break
+	}
+
+	if lists, _ := srv.Requests(); lists != 1 {
+		t.Fatalf("expected a single page, got %d", lists)
+	}
+}
+
+func TestListObjectsError(t *testing.T) {
+	client, _ := newClient(t, 0)
+
+	var err error
+	for _, err = range client.ListObjects(context.Background(), "missing", "") {
+	}
+
+	var resp minio.ErrorResponse
+	if !errors.As(err, &resp) || resp.Code != "NoSuchBucket" {
+		t.Fatalf("expected NoSuchBucket, got %v", err)
+	}
+}
+
+func TestChunks(t *testing.T) {
+	client, srv := newClient(t, 0)
+
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+	srv.Put("courses", "videos/01.mp4", data)
+
+	var got []byte
+	for chunk, err := range client.Chunks(context.Background(), "courses", "videos/01.mp4", 4096) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got = append(got, chunk...)
+	}
+
+	if !bytes.Equal(got, data) {
+		t.Fatalf("expected %d bytes, got %d", len(data), len(got))
+	}
+
+	var err error
+	for _, err = range client.Chunks(context.Background(), "courses", "videos/02.mp4", 4096) {
+	}
+
+	var resp minio.ErrorResponse
+	if !errors.As(err, &resp) || resp.Code != "NoSuchKey" {
+		t.Fatalf("expected NoSuchKey, got %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package s3test
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultMaxKeys is the number of keys a page holds when the request does
+// not ask for fewer, like the 1000 of S3
+const defaultMaxKeys = 1000
+
+type (
+	// Server is an in-memory object store which speaks enough of the S3 API
+	// to list the objects of a bucket with ListObjectsV2 and to download
+	// them. Objects are added with Put, since it does not implement uploads.
+	Server struct {
+		*httptest.Server
+
+		mu      sync.Mutex
+		buckets map[string]map[string]object
+
+		lists atomic.Int64
+		gets  atomic.Int64
+	}
+
+	object struct {
+		data     []byte
+		etag     string
+		modified time.Time
+	}
+
+	listBucketResult struct {
+		XMLName               xml.Name   `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+		Name                  string     `xml:"Name"`
+		Prefix                string     `xml:"Prefix"`
+		KeyCount              int        `xml:"KeyCount"`
+		MaxKeys               int        `xml:"MaxKeys"`
+		IsTruncated           bool       `xml:"IsTruncated"`
+		ContinuationToken     string     `xml:"ContinuationToken,omitempty"`
+		NextContinuationToken string     `xml:"NextContinuationToken,omitempty"`
+		Contents              []contents `xml:"Contents"`
+	}
+
+	contents struct {
+		Key          string `xml:"Key"`
+		LastModified string `xml:"LastModified"`
+		ETag         string `xml:"ETag"`
+		Size         int    `xml:"Size"`
+		StorageClass string `xml:"StorageClass"`
+	}
+
+	errorResponse struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string   `xml:"Code"`
+		Message string   `xml:"Message"`
+	}
+)
+
+// NewServer starts a server without any buckets. It must be closed.
+func NewServer() *Server {
+	s := &Server{buckets: make(map[string]map[string]object)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+
+	return s
+}
+
+// Endpoint returns the host and port of the server, which is what S3
+// clients are configured with
+func (s *Server) Endpoint() string {
+	return strings.TrimPrefix(s.URL, "http://")
+}
+
+// Put stores data as the object at key in bucket, creating the bucket if it
+// does not exist
+func (s *Server) Put(bucket, key string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.buckets[bucket] == nil {
+		s.buckets[bucket] = make(map[string]object)
+	}
+
+	sum := md5.Sum(data)
+	s.buckets[bucket][key] = object{data: data, etag: hex.EncodeToString(sum[:]), modified: time.Now().UTC()}
+}
+
+// Requests returns the number of pages of keys which have been listed, and
+// the number of objects which have been downloaded
+func (s *Server) Requests() (lists, gets int) {
+	return int(s.lists.Load()), int(s.gets.Load())
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusNotImplemented, "NotImplemented", "only GET is implemented")
+		return
+	}
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+
+	s.mu.Lock()
+	objects, ok := s.buckets[bucket]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist")
+		return
+	}
+
+	if key == "" {
+		s.list(w, r, bucket, objects)
+		return
+	}
+
+	s.get(w, bucket, key)
+}
+
+// list serves a page of the keys of a bucket in lexical order. The
+// continuation token is the last key of the page before, encoded so that
+// clients treat it as opaque.
+func (s *Server) list(w http.ResponseWriter, r *http.Request, bucket string, objects map[string]object) {
+	s.lists.Add(1)
+
+	q := r.URL.Query()
+	if q.Get("list-type") != "2" {
+		writeError(w, http.StatusNotImplemented, "NotImplemented", "only ListObjectsV2 is implemented")
+		return
+	}
+
+	maxKeys := defaultMaxKeys
+	if v := q.Get("max-keys"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "InvalidArgument", fmt.Sprintf("invalid max-keys %q", v))
+			return
+		}
+
+		maxKeys = min(n, defaultMaxKeys)
+	}
+
+	after := q.Get("start-after")
+	if token := q.Get("continuation-token"); token != "" {
+		b, err := base64.URLEncoding.DecodeString(token)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidArgument", "invalid continuation token")
+			return
+		}
+
+		after = string(b)
+	}
+
+	prefix := q.Get("prefix")
+
+	s.mu.Lock()
+	var keys []string
+	for key := range objects {
+		if strings.HasPrefix(key, prefix) && key > after {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	result := listBucketResult{
+		Name:              bucket,
+		Prefix:            prefix,
+		MaxKeys:           maxKeys,
+		ContinuationToken: q.Get("continuation-token"),
+	}
+
+	if len(keys) > maxKeys {
+		keys = keys[:maxKeys]
+		result.IsTruncated = true
+		result.NextContinuationToken = base64.URLEncoding.EncodeToString([]byte(keys[len(keys)-1]))
+	}
+
+	for _, key := range keys {
+		obj := objects[key]
+		result.Contents = append(result.Contents, contents{
+			Key:          key,
+			LastModified: obj.modified.Format("2006-01-02T15:04:05.000Z"),
+			ETag:         `"` + obj.etag + `"`,
+			Size:         len(obj.data),
+			StorageClass: "STANDARD",
+		})
+	}
+	s.mu.Unlock()
+
+	result.KeyCount = len(result.Contents)
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(result)
+}
+
+// get serves the content of an object
+func (s *Server) get(w http.ResponseWriter, bucket, key string) {
+	s.gets.Add(1)
+
+	s.mu.Lock()
+	obj, ok := s.buckets[bucket][key]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+	w.Header().Set("ETag", `"`+obj.etag+`"`)
+	w.Header().Set("Last-Modified", obj.modified.Format(http.TimeFormat))
+	w.Write(obj.data)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(errorResponse{Code: code, Message: message})
+}
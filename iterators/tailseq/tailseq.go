@@ -0,0 +1,188 @@
+package tailseq
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultInterval is how often Follow checks the file for new lines
+const DefaultInterval = 100 * time.Millisecond
+
+// Follow returns an iterator over the lines appended to the file at path,
+// like tail -f. Lines which are already in the file when the iteration
+// starts are skipped. The file is polled every DefaultInterval, so the
+// iterator never ends on its own: it stops without an error once ctx is
+// done, or with one if the file cannot be read.
+//
+// A line is only yielded once its line ending has been written, so a line
+// which is being written is never split in two. A file which is truncated,
+// such as by a logger which reuses it, is read again from its start. A file
+// which is rotated, by renaming it and creating a new file at path, is read
+// to its end before the new file is followed from its start.
+func Follow(ctx context.Context, path string) iter.Seq2[string, error] {
+	return FollowEvery(ctx, path, DefaultInterval)
+}
+
+// FollowEvery is like Follow, but polls the file every interval
+func FollowEvery(ctx context.Context, path string, interval time.Duration) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		t := &tail{path: path, buf: make([]byte, 32<<10)}
+
+		err := t.open(io.SeekEnd)
+		if err != nil {
+			yield("", err)
+			return
+		}
+		defer func() {
+			t.f.Close()
+		}()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			more, err := t.drain(yield)
+			if err != nil {
+				yield("", err)
+				return
+			}
+			if !more {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			more, err = t.check(yield)
+			if err != nil {
+				yield("", err)
+				return
+			}
+			if !more {
+				return
+			}
+		}
+	}
+}
+
+// tail is the file being followed
+type tail struct {
+	path   string
+	f      *os.File
+	info   os.FileInfo
+	offset int64
+	buf    []byte
+	// partial is the start of a line whose line ending has not been
+	// written yet, which is kept in line between reads
+	partial []byte
+	line    []byte
+}
+
+// open opens the file at path, and seeks to its start or its end
+func (t *tail) open(whence int) error {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", t.path, err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to open %s: %w", t.path, err)
+	}
+
+	offset, err := f.Seek(0, whence)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to open %s: %w", t.path, err)
+	}
+
+	t.f, t.info, t.offset, t.partial = f, info, offset, t.partial[:0]
+	return nil
+}
+
+// drain yields every complete line which has been appended to the file since
+// it was last read, and reports whether the consumer wants more
+func (t *tail) drain(yield func(string, error) bool) (bool, error) {
+	for {
+		n, err := t.f.Read(t.buf)
+		t.offset += int64(n)
+		t.partial = append(t.partial, t.buf[:n]...)
+
+		for {
+			i := bytes.IndexByte(t.partial, '\n')
+			if i < 0 {
+				break
+			}
+
+			line := strings.TrimSuffix(string(t.partial[:i]), "\r")
+			t.partial = t.partial[i+1:]
+
+			if !yield(line, nil) {
+				return false, nil
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			// Moving what is left of a line to the start of its buffer keeps
+			// the buffer from growing with every line read
+			t.line = append(t.line[:0], t.partial...)
+			t.partial = t.line
+			return true, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to read %s: %w", t.path, err)
+		}
+	}
+}
+
+// check handles a file which has been rotated or truncated since it was last
+// read, and reports whether the consumer wants more
+func (t *tail) check(yield func(string, error) bool) (bool, error) {
+	info, err := os.Stat(t.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// The file has been renamed, and the new one is not there yet
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check %s: %w", t.path, err)
+	}
+
+	if !os.SameFile(t.info, info) {
+		// Whatever was appended to the old file before it was renamed is
+		// still read from it, including a last line without a line ending
+		more, err := t.drain(yield)
+		if err != nil || !more {
+			return more, err
+		}
+
+		if len(t.partial) > 0 && !yield(string(t.partial), nil) {
+			return false, nil
+		}
+
+		t.f.Close()
+		return true, t.open(io.SeekStart)
+	}
+
+	if info.Size() < t.offset {
+		_, err = t.f.Seek(0, io.SeekStart)
+		if err != nil {
+			return false, fmt.Errorf("failed to read %s: %w", t.path, err)
+		}
+
+		t.offset, t.partial = 0, t.partial[:0]
+	}
+
+	return true, nil
+}
@@ -0,0 +1,196 @@
+package tailseq
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const interval = 5 * time.Millisecond
+
+// follow follows the file at path in the background, and sends the lines to
+// the returned channel, which is closed once the iteration ends
+func follow(t *testing.T, ctx context.Context, path string) <-chan string {
+	t.Helper()
+
+	lines := make(chan string)
+	started := make(chan struct{})
+	go func() {
+		defer close(lines)
+
+		first := true
+		for line, err := range FollowEvery(ctx, path, interval) {
+			if first {
+				close(started)
+				first = false
+			}
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if line == "" {
+				continue
+			}
+			lines <- line
+		}
+	}()
+
+	// Empty lines are written until one is yielded, since Follow skips
+	// what is already in the file when it opens it
+	deadline := time.After(time.Second)
+	for {
+		write(t, path, "\n")
+		select {
+		case <-started:
+			return lines
+		case <-deadline:
+			t.Fatal("expected the iteration to start")
+		case <-time.After(interval):
+		}
+	}
+}
+
+func write(t *testing.T, path, s string) {
+	t.Helper()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func expect(t *testing.T, lines <-chan string, expected ...string) {
+	t.Helper()
+
+	for _, e := range expected {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatalf("expected %q, got the end of the iteration", e)
+			}
+			if line != e {
+				t.Fatalf("expected %q, got %q", e, line)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected %q, got nothing", e)
+		}
+	}
+}
+
+func setup(t *testing.T) (context.Context, string) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	write(t, path, "old line\n")
+
+	return ctx, path
+}
+
+func TestFollow(t *testing.T) {
+	ctx, path := setup(t)
+	lines := follow(t, ctx, path)
+
+	write(t, path, "first\nsecond\r\n")
+	expect(t, lines, "first", "second")
+
+	// A line is not yielded until its line ending has been written
+	write(t, path, "thi")
+	time.Sleep(10 * interval)
+	write(t, path, "rd\n")
+	expect(t, lines, "third")
+}
+
+func TestFollowTruncate(t *testing.T) {
+	ctx, path := setup(t)
+	lines := follow(t, ctx, path)
+
+	write(t, path, "before truncation\n")
+	expect(t, lines, "before truncation")
+
+	err := os.Truncate(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * interval)
+
+	write(t, path, "after\n")
+	expect(t, lines, "after")
+}
+
+func TestFollowRotate(t *testing.T) {
+	ctx, path := setup(t)
+	lines := follow(t, ctx, path)
+
+	write(t, path, "before rotation\n")
+	expect(t, lines, "before rotation")
+
+	err := os.Rename(path, path+".1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Lines written to the old file after it was renamed are still read
+	write(t, path+".1", "late\nno line ending")
+	write(t, path, "new file\n")
+	expect(t, lines, "late", "no line ending", "new file")
+}
+
+func TestFollowCancel(t *testing.T) {
+	ctx, path := setup(t)
+	ctx, cancel := context.WithCancel(ctx)
+	lines := follow(t, ctx, path)
+
+	cancel()
+
+	select {
+	case line, ok := <-lines:
+		if ok {
+			t.Fatalf("expected the end of the iteration, got %q", line)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the iteration to end")
+	}
+}
+
+func TestFollowBreak(t *testing.T) {
+	_, path := setup(t)
+
+	go func() {
+		time.Sleep(10 * interval)
+		write(t, path, "first\nsecond\n")
+	}()
+
+	for line, err := range FollowEvery(context.Background(), path, interval) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if line != "first" {
+			t.Fatalf("expected %q, got %q", "first", line)
+		}
+		break
+	}
+}
+
+func TestFollowMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.log")
+
+	for _, err := range Follow(context.Background(), path) {
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		return
+	}
+
+	t.Fatal("expected an error")
+}
@@ -0,0 +1,49 @@
+package textio
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// MaxLineSize is the longest line Lines accepts, in bytes
+const MaxLineSize = 64 << 20
+
+// initialBufferSize is the size of the buffer lines are read into at first.
+// It doubles whenever a line does not fit, up to the longest line allowed, so
+// a file of short lines never allocates more than this.
+const initialBufferSize = 4096
+
+// Lines returns an iterator over the lines of r, without their line endings.
+// Only the current line is kept in memory, so it reads files of any size in
+// constant memory, as long as no line is longer than MaxLineSize. Reading
+// stops at the first error, which is yielded along with an empty line. Like
+// r itself, the iterator can only be ranged over once.
+func Lines(r io.Reader) iter.Seq2[string, error] {
+	return LinesLimit(r, MaxLineSize)
+}
+
+// LinesLimit is like Lines, except that lines may be up to max bytes long
+func LinesLimit(r io.Reader, max int) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, min(initialBufferSize, max)), max)
+
+		n := 0
+		for scanner.Scan() {
+			n++
+			if !yield(scanner.Text(), nil) {
+				return
+			}
+		}
+
+		err := scanner.Err()
+		if errors.Is(err, bufio.ErrTooLong) {
+			yield("", fmt.Errorf("line %d is longer than %d bytes: %w", n+1, max, err))
+		} else if err != nil {
+			yield("", fmt.Errorf("failed to read line %d: %w", n+1, err))
+		}
+	}
+}
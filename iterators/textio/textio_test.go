@@ -0,0 +1,82 @@
+package textio
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+// collect returns the lines of seq, and the error which stopped it
+func collect(t *testing.T, seq iter.Seq2[string, error]) ([]string, error) {
+	t.Helper()
+
+	var lines []string
+	for line, err := range seq {
+		if err != nil {
+			return lines, err
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+func TestLines(t *testing.T) {
+	long := strings.Repeat("x", 10*initialBufferSize)
+
+	lines, err := collect(t, Lines(strings.NewReader("one\r\n\n"+long+"\nlast")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"one", "", long, "last"}
+	if !slices.Equal(lines, expected) {
+		t.Fatalf("expected %d lines, got %q", len(expected), lines)
+	}
+}
+
+func TestLinesTooLong(t *testing.T) {
+	lines, err := collect(t, LinesLimit(strings.NewReader("short\n"+strings.Repeat("x", 100)+"\nafter\n"), 64))
+	if !errors.Is(err, bufio.ErrTooLong) {
+		t.Fatalf("expected bufio.ErrTooLong, got %v", err)
+	}
+
+	if err.Error() != "line 2 is longer than 64 bytes: bufio.Scanner: token too long" {
+		t.Fatalf("expected the error to name the line, got %q", err)
+	}
+
+	if !slices.Equal(lines, []string{"short"}) {
+		t.Fatalf("expected the lines before the long one, got %q", lines)
+	}
+}
+
+func TestLinesReadError(t *testing.T) {
+	errRead := errors.New("disk on fire")
+
+	r := io.MultiReader(strings.NewReader("one\ntwo\n"), iotest.ErrReader(errRead))
+
+	lines, err := collect(t, Lines(r))
+	if !errors.Is(err, errRead) {
+		t.Fatalf("expected the read error, got %v", err)
+	}
+
+	if !slices.Equal(lines, []string{"one", "two"}) {
+		t.Fatalf("expected the lines before the error, got %q", lines)
+	}
+}
+
+func TestLinesConformance(t *testing.T) {
+	newSeq := func() iter.Seq2[string, error] { return Lines(strings.NewReader("one\ntwo\nthree")) }
+
+	seqtest.AssertYields2(t, newSeq(), []string{"one", "two", "three"}, make([]error, 3))
+	seqtest.AssertStopsEarly2(t, 3, newSeq)
+	seqtest.AssertNoYieldAfterFalse2(t, 3, newSeq)
+}
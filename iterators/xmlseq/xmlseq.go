@@ -0,0 +1,63 @@
+package xmlseq
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// Elements returns an iterator which decodes every element named localName
+// in the XML read from r into a value of type T, wherever it is in the
+// document, so only the current element is kept in memory rather than the
+// whole document. The namespace of the name is ignored. An element is
+// decoded as a whole, including any elements of the same name nested in it,
+// which are not yielded on their own. Decoding stops at the first error, such
+// as malformed XML or an element which does not fit T, which is yielded
+// along with the zero value of T.
+//
+//	type Course struct {
+//		ID   int    `xml:"id,attr"`
+//		Name string `xml:"name"`
+//	}
+//
+//	for course, err := range xmlseq.Elements[Course](f, "course") {
+func Elements[T any](r io.Reader, localName string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		dec := xml.NewDecoder(r)
+
+		for i := 0; ; {
+			tok, err := dec.Token()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(zero, fmt.Errorf("failed to read XML: %w", err))
+				return
+			}
+
+			start, ok := tok.(xml.StartElement)
+			if !ok || start.Name.Local != localName {
+				continue
+			}
+
+			line, _ := dec.InputPos()
+
+			var val T
+
+			err = dec.DecodeElement(&val, &start)
+			if err != nil {
+				yield(zero, fmt.Errorf("failed to decode element %d on line %d: %w", i, line, err))
+				return
+			}
+
+			i++
+			if !yield(val, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package xmlseq
+
+import (
+	"iter"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+type course struct {
+	ID            int     `xml:"id,attr"`
+	Name          string  `xml:"name"`
+	Credits       float64 `xml:"credits"`
+	Prerequisites []struct {
+		ID int `xml:"id,attr"`
+	} `xml:"prerequisites>course"`
+}
+
+func TestElements(t *testing.T) {
+	f, err := os.Open("testdata/catalog.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var (
+		names         []string
+		credits       float64
+		prerequisites int
+	)
+
+	for c, err := range Elements[course](f, "course") {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		names = append(names, c.Name)
+		credits += c.Credits
+		prerequisites += len(c.Prerequisites)
+	}
+
+	// The course nested in the prerequisites of Chem-2 is part of it, and
+	// not yielded on its own
+	expected := []string{"Chem-1", "Chem-2", "Physics-1", "Calculus-1"}
+	if !slices.Equal(names, expected) {
+		t.Fatalf("expected %q, got %q", expected, names)
+	}
+
+	if credits != 15.5 || prerequisites != 1 {
+		t.Fatalf("expected 15.5 credits and 1 prerequisite, got %v and %d", credits, prerequisites)
+	}
+}
+
+func TestElementsBreak(t *testing.T) {
+	f, err := os.Open("testdata/catalog.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	type department struct {
+		Name string `xml:"name,attr"`
+	}
+
+	var first department
+	for d, err := range Elements[department](f, "department") {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		first = d
+		break
+	}
+
+	if first.Name != "Chemistry" {
+		t.Fatalf("expected the first department, got %q", first.Name)
+	}
+}
+
+func TestElementsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{
+			name: "malformed",
+			data: "<catalog><course id=\"1\"></catalog>",
+			err:  "failed to decode element 0 on line 1: XML syntax error on line 1: element <course> closed by </catalog>",
+		},
+		{
+			name: "invalid value",
+			data: "<catalog>\n<course id=\"1\"/>\n<course id=\"two\"/>\n</catalog>",
+			err:  `failed to decode element 1 on line 3: strconv.ParseInt: parsing "two": invalid syntax`,
+		},
+		{
+			name: "unclosed",
+			data: "<catalog>",
+			err:  "failed to read XML: XML syntax error on line 1: unexpected EOF",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var err error
+			for _, err = range Elements[course](strings.NewReader(test.data), "course") {
+				if err != nil {
+					break
+				}
+			}
+
+			if err == nil || err.Error() != test.err {
+				t.Fatalf("expected %q, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestElementsConformance(t *testing.T) {
+	const data = `<catalog><course id="1"><name>Chem-1</name></course><course id="2"><name>Chem-2</name></course></catalog>`
+
+	newSeq := func() iter.Seq2[course, error] { return Elements[course](strings.NewReader(data), "course") }
+
+	seqtest.AssertYields2(t, newSeq(), []course{{ID: 1, Name: "Chem-1"}, {ID: 2, Name: "Chem-2"}}, make([]error, 2))
+	seqtest.AssertStopsEarly2(t, 2, newSeq)
+	seqtest.AssertNoYieldAfterFalse2(t, 2, newSeq)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"unique"
+)
+
+type (
+	Course struct {
+		ID         int
+		Name       string
+		University string
+	}
+
+	// InternedCourse stores handles instead of strings. Every handle to the
+	// same string points to a single copy of it.
+	InternedCourse struct {
+		ID         int
+		Name       unique.Handle[string]
+		University unique.Handle[string]
+	}
+)
+
+var (
+	courseNames = []string{
+		"Introduction to Organic Chemistry",
+		"Classical Mechanics and Thermodynamics",
+		"Electricity, Magnetism, and Optics",
+		"Multivariable and Vector Calculus",
+	}
+
+	universities = []string{
+		"San Jose State University",
+		"San Diego State University",
+		"University of California, Berkeley",
+		"University of California, San Francisco",
+	}
+)
+
+// loadCourses simulates reading courses from a file or a database. Like the
+// strings returned by a decoder, every string is a new copy, even if it is
+// equal to a string which was already loaded.
+func loadCourses(numCourses int) []Course {
+	courses := make([]Course, numCourses)
+	for i := range courses {
+		courses[i] = Course{
+			ID:         i,
+			Name:       strings.Clone(courseNames[i%len(courseNames)]),
+			University: strings.Clone(universities[i%len(universities)]),
+		}
+	}
+
+	return courses
+}
+
+func intern(courses []Course) []InternedCourse {
+	interned := make([]InternedCourse, len(courses))
+	for i, course := range courses {
+		interned[i] = InternedCourse{
+			ID:         course.ID,
+			Name:       unique.Make(course.Name),
+			University: unique.Make(course.University),
+		}
+	}
+
+	return interned
+}
+
+// heapInUse returns the bytes occupied by live objects, once the garbage
+// collector has freed everything else
+func heapInUse() float64 {
+	runtime.GC()
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	return float64(mem.HeapAlloc) / 1e6
+}
+
+func main() {
+	const numCourses = 1000000
+
+	before := heapInUse()
+
+	courses := loadCourses(numCourses)
+	fmt.Printf("heap in use with strings: %.2f Mb\n", heapInUse()-before)
+
+	interned := intern(courses)
+	courses = nil
+	fmt.Printf("heap in use with handles: %.2f Mb\n", heapInUse()-before)
+
+	// Comparing two handles compares two pointers, however long the strings
+	// they point to are
+	a, b := interned[0], interned[len(courseNames)*len(universities)]
+	fmt.Printf("courses %d and %d are at the same university: %t\n", a.ID, b.ID, a.University == b.University)
+	fmt.Printf("university of course %d: %s\n", b.ID, b.University.Value())
+
+	runtime.KeepAlive(interned)
+}
@@ -0,0 +1,90 @@
+//go:build go1.24
+
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"weak"
+)
+
+type Course struct {
+	ID         int
+	Name       string
+	University string
+}
+
+// Cache remembers the courses which are still in use somewhere else in the
+// program. A weak pointer does not keep its course alive, so the cache never
+// stops the garbage collector from freeing a course.
+type Cache struct {
+	mu      sync.Mutex
+	courses map[int]weak.Pointer[Course]
+	evicted chan int
+}
+
+func NewCache() *Cache {
+	return &Cache{
+		courses: make(map[int]weak.Pointer[Course]),
+		evicted: make(chan int, 1),
+	}
+}
+
+// Get returns the course with the given ID, and loads it if it is not in use
+func (c *Cache) Get(id int) *Course {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if course := c.courses[id].Value(); course != nil {
+		fmt.Printf("cache hit: course %d\n", id)
+		return course
+	}
+
+	fmt.Printf("cache miss: loading course %d\n", id)
+	course := &Course{ID: id, Name: "Chem-1", University: "SJSU"}
+
+	// The cleanup runs once the course has been freed, and removes its
+	// entry, unless the course has been loaded again in the meantime
+	c.courses[id] = weak.Make(course)
+	runtime.AddCleanup(course, c.evict, id)
+
+	return course
+}
+
+func (c *Cache) evict(id int) {
+	c.mu.Lock()
+	if c.courses[id].Value() == nil {
+		delete(c.courses, id)
+	}
+	c.mu.Unlock()
+
+	c.evicted <- id
+}
+
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.courses)
+}
+
+func main() {
+	cache := NewCache()
+
+	course := cache.Get(1)
+	fmt.Printf("got %s at %s\n", course.Name, course.University)
+
+	// The course is still in use, so it is still in the cache
+	cache.Get(1)
+
+	// Once nothing else points to the course, the garbage collector frees
+	// it, and its cleanup runs on a separate goroutine
+	course = nil
+	runtime.GC()
+
+	id := <-cache.evicted
+	fmt.Printf("course %d was freed, %d courses are cached\n", id, cache.Len())
+
+	cache.Get(1)
+}
@@ -0,0 +1,17 @@
+//go:build !go1.24
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// The weak package and runtime.AddCleanup were added in Go 1.24. Older
+// toolchains build this file instead of the lesson, so that the rest of the
+// course still builds with them.
+func main() {
+	fmt.Fprintf(os.Stderr, "this lesson requires go1.24 or later, but it was built with %s\n", runtime.Version())
+	os.Exit(1)
+}
@@ -0,0 +1,117 @@
+package pack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path"
+	"strings"
+)
+
+// Prefix is the prefix of the name of a pack's program, which the CLI looks
+// for on the PATH
+const Prefix = "university-pack-"
+
+// The commands of the protocol between the CLI and a pack
+const (
+	// ListCommand writes the LessonInfo of every lesson of the pack to
+	// stdout, as a JSON array
+	ListCommand = "list"
+	// RunCommand runs the lesson whose ID follows it, with the arguments
+	// after the ID
+	RunCommand = "run"
+)
+
+// LessonInfo describes a lesson to the CLI, in the output of ListCommand
+type LessonInfo struct {
+	ID             string   `json:"id"`
+	Module         string   `json:"module"`
+	Metadata       Metadata `json:"metadata"`
+	ExpectedOutput string   `json:"expected_output,omitempty"`
+}
+
+// ErrUnknownLesson is returned for a lesson which no registered pack has
+var ErrUnknownLesson = errors.New("unknown lesson")
+
+// Main is the main function of a pack's program. It serves the commands of
+// the CLI with the registered packs, and exits.
+func Main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := Serve(ctx, os.Args[1:], os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", path.Base(os.Args[0]), err)
+		os.Exit(1)
+	}
+}
+
+// Serve serves a single command of the CLI, given by args, and writes its
+// output to stdout
+func Serve(ctx context.Context, args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("expected a command, %s or %s", ListCommand, RunCommand)
+	}
+
+	switch args[0] {
+	case ListCommand:
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(List())
+	case RunCommand:
+		if len(args) < 2 {
+			return errors.New("expected the ID of a lesson to run")
+		}
+
+		l, err := lookup(args[1])
+		if err != nil {
+			return err
+		}
+
+		return l.Run(ctx, stdout, args[2:])
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// List describes every lesson of the registered packs
+func List() []LessonInfo {
+	var infos []LessonInfo
+
+	for _, p := range Packs() {
+		for _, l := range p.Lessons() {
+			infos = append(infos, LessonInfo{
+				ID:             p.Module() + "/" + l.Name,
+				Module:         p.Module(),
+				Metadata:       l.Metadata,
+				ExpectedOutput: l.ExpectedOutput,
+			})
+		}
+	}
+
+	return infos
+}
+
+// lookup returns the lesson with the ID
+func lookup(id string) (Lesson, error) {
+	module, name, _ := strings.Cut(id, "/")
+
+	mu.Lock()
+	p, ok := packs[module]
+	mu.Unlock()
+
+	if ok {
+		for _, l := range p.Lessons() {
+			if l.Name == name {
+				return l, nil
+			}
+		}
+	}
+
+	return Lesson{}, fmt.Errorf("%w: %s", ErrUnknownLesson, id)
+}
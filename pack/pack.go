@@ -0,0 +1,128 @@
+// Package pack lets other repositories contribute modules of lessons to the
+// university CLI, such as the exercises of an instructor's own course.
+//
+// A pack is a Go program named university-pack-<name>, installed on the
+// PATH, whose main function calls Main once its LessonPacks are registered.
+// The CLI asks every pack on the PATH for its lessons, and runs a lesson of a
+// pack through the pack, so that it is listed, run, and checked like a lesson
+// of the course.
+//
+//	package main
+//
+//	import (
+//		"github.com/manedurphy/golang-university/pack"
+//		_ "example.com/course/lessons" // registers its LessonPack in init
+//	)
+//
+//	func main() {
+//		pack.Main()
+//	}
+package pack
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+type (
+	// Metadata describes a lesson, like the lesson.yaml of a lesson of the
+	// course
+	Metadata struct {
+		Title string `json:"title"`
+		// Difficulty is beginner, intermediate, or advanced
+		Difficulty string `json:"difficulty,omitempty"`
+		// Prerequisites are the IDs of the lessons which should be completed
+		// before this one, in the pack or in the course
+		Prerequisites []string `json:"prerequisites,omitempty"`
+		// Objectives are what a student should be able to do after
+		// completing the lesson
+		Objectives []string `json:"objectives,omitempty"`
+	}
+
+	// Lesson is a lesson of a pack
+	Lesson struct {
+		// Name identifies the lesson within its module, such as 01-hello. The
+		// ID of the lesson is module/name.
+		Name     string
+		Metadata Metadata
+		// ExpectedOutput is the expected output of the lesson, in the format
+		// of an expected_output.txt, placeholders and all. A lesson without
+		// one is not checked.
+		ExpectedOutput string
+		// Run runs the lesson, like the main function of a lesson of the
+		// course, and writes its output to stdout. args are the arguments
+		// the lesson was run with.
+		Run func(ctx context.Context, stdout io.Writer, args []string) error
+	}
+
+	// LessonPack is a module of lessons
+	LessonPack interface {
+		// Module is the name of the module the lessons belong to. It can not
+		// be the name of a module of the course, or of another pack.
+		Module() string
+		// Lessons returns the lessons of the module
+		Lessons() []Lesson
+	}
+)
+
+var (
+	mu    sync.Mutex
+	packs = make(map[string]LessonPack)
+)
+
+// Register registers a pack, usually from the init function of the package
+// which defines it. It panics if a pack of the same module is already
+// registered, if the module is not a valid name, or if a lesson has no name,
+// shares its name with another, or has no Run function.
+func Register(p LessonPack) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	module := p.Module()
+	if module == "" || strings.ContainsAny(module, `/\ `) {
+		panic(fmt.Sprintf("pack: invalid module name %q", module))
+	}
+
+	names := make(map[string]bool)
+	for _, l := range p.Lessons() {
+		switch {
+		case l.Name == "" || strings.Trim(l.Name, "/") != l.Name:
+			panic(fmt.Sprintf("pack: invalid lesson name %q in module %q", l.Name, module))
+		case names[l.Name]:
+			panic(fmt.Sprintf("pack: duplicate lesson %q in module %q", l.Name, module))
+		case l.Run == nil:
+			panic(fmt.Sprintf("pack: lesson %q in module %q has no Run function", l.Name, module))
+		}
+
+		names[l.Name] = true
+	}
+
+	if _, ok := packs[module]; ok {
+		panic(fmt.Sprintf("pack: Register called twice for module %q", module))
+	}
+
+	packs[module] = p
+}
+
+// Packs returns the registered packs, sorted by module
+func Packs() []LessonPack {
+	mu.Lock()
+	defer mu.Unlock()
+
+	modules := make([]string, 0, len(packs))
+	for module := range packs {
+		modules = append(modules, module)
+	}
+	slices.Sort(modules)
+
+	sorted := make([]LessonPack, 0, len(modules))
+	for _, module := range modules {
+		sorted = append(sorted, packs[module])
+	}
+
+	return sorted
+}
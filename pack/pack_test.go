@@ -0,0 +1,128 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// greetings is a pack whose lesson greets whoever its arguments name
+type greetings struct {
+	module  string
+	lessons []Lesson
+}
+
+func (g greetings) Module() string    { return g.module }
+func (g greetings) Lessons() []Lesson { return g.lessons }
+
+func hello(ctx context.Context, stdout io.Writer, args []string) error {
+	if len(args) == 0 {
+		return errors.New("expected a name")
+	}
+
+	_, err := fmt.Fprintf(stdout, "hello, %s\n", strings.Join(args, " and "))
+	return err
+}
+
+func init() {
+	Register(greetings{
+		module: "greetings",
+		lessons: []Lesson{{
+			Name:           "01-hello",
+			Metadata:       Metadata{Title: "Hello", Difficulty: "beginner"},
+			ExpectedOutput: "hello, {{word}}\n",
+			Run:            hello,
+		}},
+	})
+}
+
+func TestServeList(t *testing.T) {
+	var out bytes.Buffer
+
+	err := Serve(context.Background(), []string{ListCommand}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var infos []LessonInfo
+	err = json.Unmarshal(out.Bytes(), &infos)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i := slices.IndexFunc(infos, func(info LessonInfo) bool { return info.ID == "greetings/01-hello" })
+	if i < 0 {
+		t.Fatalf("expected greetings/01-hello to be listed, got %+v", infos)
+	}
+
+	if info := infos[i]; info.Module != "greetings" || info.Metadata.Title != "Hello" || info.ExpectedOutput == "" {
+		t.Fatalf("expected the module, metadata, and expected output of the lesson, got %+v", info)
+	}
+}
+
+func TestServeRun(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+		err      error
+	}{
+		{args: []string{RunCommand, "greetings/01-hello", "ada", "grace"}, expected: "hello, ada and grace\n"},
+		{args: []string{RunCommand, "greetings/02-goodbye"}, err: ErrUnknownLesson},
+		{args: []string{RunCommand, "farewells/01-hello"}, err: ErrUnknownLesson},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			var out bytes.Buffer
+
+			err := Serve(context.Background(), tt.args, &out)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+
+			if out.String() != tt.expected {
+				t.Fatalf("expected %q, got %q", tt.expected, out.String())
+			}
+		})
+	}
+
+	for _, args := range [][]string{nil, {RunCommand}, {"grade"}} {
+		if err := Serve(context.Background(), args, io.Discard); err == nil {
+			t.Errorf("expected an error for %q", args)
+		}
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	run := func(context.Context, io.Writer, []string) error { return nil }
+
+	tests := []struct {
+		name string
+		pack greetings
+	}{
+		{"same module", greetings{module: "greetings"}},
+		{"empty module", greetings{module: ""}},
+		{"module with a slash", greetings{module: "a/b"}},
+		{"empty lesson name", greetings{module: "empty", lessons: []Lesson{{Run: run}}}},
+		{"duplicate lessons", greetings{module: "twice", lessons: []Lesson{{Name: "a", Run: run}, {Name: "a", Run: run}}}},
+		{"no Run", greetings{module: "lazy", lessons: []Lesson{{Name: "a"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected Register to panic")
+				}
+			}()
+
+			Register(tt.pack)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/internal/profile"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/profiling/analytics"
+)
+
+func main() {
+	// -count is the number of courses the report is built from. The flags of
+	// the profile package choose which profiles are written.
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 1000000, Seed: 1})
+	db.Seed(cfg.Seed)
+
+	courses := slices.Collect(db.GenerateCourses(cfg.Count))
+
+	stop, err := profile.Start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start profiling: %v\n", err)
+		os.Exit(1)
+	}
+
+	start := time.Now()
+	report := analytics.Slow(courses, runtime.GOMAXPROCS(0))
+	elapsed := time.Since(start)
+
+	err = stop()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write profiles: %v\n", err)
+		os.Exit(1)
+	}
+
+	report.Print(os.Stdout)
+	fmt.Printf("\nbuilt in %v\n", elapsed.Round(time.Millisecond))
+}
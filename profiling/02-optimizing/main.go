@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/internal/profile"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/profiling/analytics"
+)
+
+// rounds is the number of times each version builds the report. The fastest
+// round is kept, as the others only add noise from the rest of the machine.
+const rounds = 3
+
+// fastest returns the report and the fastest of rounds runs of analyze, along
+// with the bytes and objects a run allocated
+func fastest(analyze func([]db.Course, int) analytics.Report, courses []db.Course) (analytics.Report, time.Duration, uint64, uint64) {
+	var (
+		report        analytics.Report
+		best          time.Duration
+		before, after runtime.MemStats
+	)
+
+	workers := runtime.GOMAXPROCS(0)
+
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	for i := range rounds {
+		start := time.Now()
+		report = analyze(courses, workers)
+
+		if elapsed := time.Since(start); i == 0 || elapsed < best {
+			best = elapsed
+		}
+	}
+
+	runtime.ReadMemStats(&after)
+
+	return report, best, (after.TotalAlloc - before.TotalAlloc) / rounds, (after.Mallocs - before.Mallocs) / rounds
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 1000000, Seed: 1})
+	db.Seed(cfg.Seed)
+
+	courses := slices.Collect(db.GenerateCourses(cfg.Count))
+
+	stop, err := profile.Start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start profiling: %v\n", err)
+		os.Exit(1)
+	}
+
+	slow, slowTime, slowBytes, slowAllocs := fastest(analytics.Slow, courses)
+	fast, fastTime, fastBytes, fastAllocs := fastest(analytics.Fast, courses)
+
+	err = stop()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write profiles: %v\n", err)
+		os.Exit(1)
+	}
+
+	// An optimization which changes the answer is a bug
+	if !reflect.DeepEqual(slow, fast) {
+		fmt.Fprintln(os.Stderr, "the reports differ")
+		os.Exit(1)
+	}
+
+	fast.Print(os.Stdout)
+
+	fmt.Println()
+	fmt.Printf("Slow: %v, %d B in %d allocations\n", slowTime.Round(time.Microsecond), slowBytes, slowAllocs)
+	fmt.Printf("Fast: %v, %d B in %d allocations\n", fastTime.Round(time.Microsecond), fastBytes, fastAllocs)
+	fmt.Printf("\n%.1fx faster\n", float64(slowTime)/float64(fastTime))
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/countmin"
+	"github.com/manedurphy/golang-university/iterators/itertools"
+)
+
+// k is the number of most common names which are reported
+const k = 3
+
+// names returns the names of count generated courses. The courses are
+// generated again from the seed on every call, so every pass sees the same
+// ones, without holding them.
+func names(seed uint64, count int) iter.Seq[string] {
+	db.Seed(seed)
+
+	return func(yield func(string) bool) {
+		for course := range db.GenerateCourses(count) {
+			if !yield(course.Name) {
+				return
+			}
+		}
+	}
+}
+
+// byCount orders counts by their count, and a tie by key, so that the first
+// key in alphabetical order is the greater one
+func byCount(a, b countmin.Count) bool {
+	if c := cmp.Compare(a.Count, b.Count); c != 0 {
+		return c < 0
+	}
+
+	return a.Key > b.Key
+}
+
+// printCounts prints the counts, along with how far each is from the exact count
+func printCounts(counts []countmin.Count, exact map[string]uint64) {
+	for _, c := range counts {
+		fmt.Printf("  %-10s %9d  %+d\n", c.Key, c.Count, int64(c.Count)-int64(exact[c.Key]))
+	}
+}
+
+func main() {
+	// -count 100000000 counts a hundred million courses, which takes a while,
+	// but does not hold any more memory than a million
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 1000000, Seed: 1})
+
+	// The exact counts need an entry per distinct name, which is fine for a
+	// handful of names, and not for millions of them
+	exact := make(map[string]uint64)
+	for name := range names(cfg.Seed, cfg.Count) {
+		exact[name]++
+	}
+
+	counts := func(yield func(countmin.Count) bool) {
+		for name, n := range exact {
+			if !yield(countmin.Count{Key: name, Count: n}) {
+				return
+			}
+		}
+	}
+
+	fmt.Printf("Most common of %d names in %d courses, exactly:\n", len(exact), cfg.Count)
+	printCounts(itertools.TopK(counts, k, byCount), exact)
+
+	// A sketch which is wide enough counts each name in a counter of its
+	// own in at least one row, and gets every count right. Its size is the
+	// same for a million distinct names as for a handful.
+	sketch := countmin.NewWithError(0.001, 0.01)
+	fmt.Printf("\nWith a sketch of %d rows of %d counters, %d bytes:\n", sketch.Depth(), sketch.Width(), sketch.Size())
+	printCounts(countmin.TopK(names(cfg.Seed, cfg.Count), k, sketch), exact)
+
+	// A sketch which is too narrow shares every counter between several
+	// names, and counts them too high, which puts Physics-3 in the place of
+	// Calculus-1
+	sketch = countmin.New(4, 2)
+	fmt.Printf("\nWith a sketch of %d rows of %d counters, %d bytes:\n", sketch.Depth(), sketch.Width(), sketch.Size())
+	printCounts(countmin.TopK(names(cfg.Seed, cfg.Count), k, sketch), exact)
+
+	// A count is never too low, and a name which has a counter of its own in
+	// a row is counted exactly
+	fmt.Println("\nEvery count of the narrow sketch:")
+	for _, name := range slices.Sorted(maps.Keys(exact)) {
+		fmt.Printf("  %-10s %9d  %+d\n", name, sketch.Count(name), int64(sketch.Count(name))-int64(exact[name]))
+	}
+}
@@ -0,0 +1,34 @@
+package analytics
+
+import (
+	"fmt"
+	"io"
+)
+
+type (
+	// Report sums up the courses of each university
+	Report struct {
+		Courses      int
+		Universities []UniversityStats
+	}
+
+	// UniversityStats are the numbers of a single university. Top is the name
+	// the most courses of the university have, the first of them in
+	// alphabetical order if several names have as many.
+	UniversityStats struct {
+		University string
+		Courses    int
+		Names      int
+		Top        string
+		TopCourses int
+	}
+)
+
+// Print writes the report as a table
+func (r Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "%-6s %8s %6s  %s\n", "", "courses", "names", "top")
+	for _, u := range r.Universities {
+		fmt.Fprintf(w, "%-6s %8d %6d  %s (%d)\n", u.University, u.Courses, u.Names, u.Top, u.TopCourses)
+	}
+	fmt.Fprintf(w, "%-6s %8d\n", "total", r.Courses)
+}
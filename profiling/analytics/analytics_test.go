@@ -0,0 +1,77 @@
+package analytics
+
+import (
+	"reflect"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// analyzers are the implementations of the report, in a fixed order for the
+// benchmarks
+var analyzers = []struct {
+	name    string
+	analyze func([]db.Course, int) Report
+}{
+	{"Slow", Slow},
+	{"Fast", Fast},
+}
+
+var courses = []db.Course{
+	{ID: 1, Name: "Chem-1", University: "UCB"},
+	{ID: 2, Name: "Physics-1", University: "UCB"},
+	{ID: 3, Name: "Chem-1", University: "SJSU"},
+	{ID: 4, Name: "Physics-1", University: "UCB"},
+	{ID: 5, Name: "Chem-1", University: "UCB"},
+	{ID: 6, Name: "Calculus-1", University: "SJSU"},
+}
+
+func TestReport(t *testing.T) {
+	expected := Report{
+		Courses: 6,
+		Universities: []UniversityStats{
+			// A tie goes to the first name in alphabetical order
+			{University: "SJSU", Courses: 2, Names: 2, Top: "Calculus-1", TopCourses: 1},
+			{University: "UCB", Courses: 4, Names: 2, Top: "Chem-1", TopCourses: 2},
+		},
+	}
+
+	for _, a := range analyzers {
+		for _, workers := range []int{0, 1, 4, 10} {
+			if got := a.analyze(courses, workers); !reflect.DeepEqual(got, expected) {
+				t.Fatalf("%s with %d workers: expected %+v, got %+v", a.name, workers, expected, got)
+			}
+		}
+
+		if got := a.analyze(nil, 4); got.Courses != 0 || len(got.Universities) != 0 {
+			t.Fatalf("%s: expected an empty report for no courses, got %+v", a.name, got)
+		}
+	}
+}
+
+func TestFastAgrees(t *testing.T) {
+	generated := slices.Collect(db.GenerateCourses(10000))
+
+	slow, fast := Slow(generated, 4), Fast(generated, 4)
+	if !reflect.DeepEqual(slow, fast) {
+		t.Fatalf("expected the same report, got %+v and %+v", slow, fast)
+	}
+}
+
+func BenchmarkReport(b *testing.B) {
+	generated := slices.Collect(db.GenerateCourses(100000))
+
+	for _, a := range analyzers {
+		b.Run(a.name, func(b *testing.B) {
+			// A worker per CPU, which -cpu changes
+			workers := runtime.GOMAXPROCS(0)
+			b.ReportAllocs()
+
+			for range b.N {
+				a.analyze(generated, workers)
+			}
+		})
+	}
+}
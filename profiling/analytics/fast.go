@@ -0,0 +1,75 @@
+package analytics
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// key is a name at a university. A struct key is hashed from its fields, and
+// is not allocated.
+type key struct {
+	university, name string
+}
+
+// Fast builds the same report as Slow. Each worker counts a share of the
+// courses into a map of its own, with nothing to wait for, and the maps are
+// merged once the workers are done. The few keys are sorted once.
+func Fast(courses []db.Course, workers int) Report {
+	workers = max(workers, 1)
+	shards := make([]map[key]int, workers)
+	size := (len(courses) + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		shards[w] = make(map[key]int)
+		share := courses[min(w*size, len(courses)):min((w+1)*size, len(courses))]
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			counts := shards[w]
+			for _, course := range share {
+				counts[key{course.University, course.Name}]++
+			}
+		}()
+	}
+	wg.Wait()
+
+	counts := shards[0]
+	for _, shard := range shards[1:] {
+		for k, n := range shard {
+			counts[k] += n
+		}
+	}
+
+	keys := make([]key, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b key) int {
+		return cmp.Or(cmp.Compare(a.university, b.university), cmp.Compare(a.name, b.name))
+	})
+
+	report := Report{Courses: len(courses)}
+	for _, k := range keys {
+		last := len(report.Universities) - 1
+		if last < 0 || report.Universities[last].University != k.university {
+			report.Universities = append(report.Universities, UniversityStats{University: k.university})
+			last++
+		}
+
+		stats := &report.Universities[last]
+		stats.Courses += counts[k]
+		stats.Names++
+
+		if counts[k] > stats.TopCourses {
+			stats.Top, stats.TopCourses = k.name, counts[k]
+		}
+	}
+
+	return report
+}
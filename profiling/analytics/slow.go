@@ -0,0 +1,83 @@
+package analytics
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// Slow builds the report the way a first version might. It is correct, and
+// each of its choices looks reasonable on its own, but together they make it
+// slow, which its profiles show:
+//
+//   - every course is sent to the workers over an unbuffered channel
+//   - the workers share a single map behind a single mutex
+//   - the key of each course is formatted into a new string, and split again
+//     to get the university back
+//   - the keys are sorted again for every university
+func Slow(courses []db.Course, workers int) Report {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		counts = make(map[string]int)
+		work   = make(chan db.Course)
+	)
+
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for course := range work {
+				key := fmt.Sprintf("%s/%s", course.University, course.Name)
+
+				mu.Lock()
+				counts[key]++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, course := range courses {
+		work <- course
+	}
+	close(work)
+	wg.Wait()
+
+	var universities []string
+	for key := range counts {
+		university := strings.Split(key, "/")[0]
+		if !slices.Contains(universities, university) {
+			universities = append(universities, university)
+		}
+	}
+	sort.Strings(universities)
+
+	report := Report{Courses: len(courses)}
+	for _, university := range universities {
+		stats := UniversityStats{University: university}
+
+		for _, key := range slices.Sorted(maps.Keys(counts)) {
+			parts := strings.Split(key, "/")
+			if parts[0] != university {
+				continue
+			}
+
+			stats.Courses += counts[key]
+			stats.Names++
+
+			if counts[key] > stats.TopCourses {
+				stats.Top, stats.TopCourses = parts[1], counts[key]
+			}
+		}
+
+		report.Universities = append(report.Universities, stats)
+	}
+
+	return report
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/reflection/structseq"
+)
+
+// enrollment embeds a course, whose fields are promoted to it
+type enrollment struct {
+	db.Course
+	Student string `json:"student"`
+	grade   string
+}
+
+// printFields prints the name, type, and json tag of every field of v, along
+// with its value and the index which leads to it
+func printFields(v any) {
+	for field, value := range structseq.Fields(v) {
+		fmt.Printf("  %-10s %-6s json:%-12q index:%v value:%v\n", field.Name, field.Type, field.Tag.Get("json"), field.Index, value)
+	}
+}
+
+func main() {
+	course := db.Course{ID: 1, Name: "Calculus-1", University: "UCB"}
+
+	// reflect.TypeOf and reflect.ValueOf describe any value at run time,
+	// which is what lets a function take a struct of a type it has never seen
+	fmt.Println("reflect.TypeOf:")
+	fmt.Printf("  %v, a %v with %d fields\n", reflect.TypeOf(course), reflect.TypeOf(course).Kind(), reflect.TypeOf(course).NumField())
+
+	fmt.Println("\nstructseq.Fields(db.Course):")
+	printFields(course)
+
+	// The fields of the embedded course are yielded in place of it, and the
+	// unexported grade is left out
+	fmt.Println("\nstructseq.Fields(enrollment):")
+	printFields(enrollment{Course: course, Student: "ada", grade: "A"})
+
+	// The fields of a struct passed by value cannot be set, as they belong to
+	// a copy. Through a pointer, they can.
+	fmt.Println("\nsetting fields through a pointer:")
+	var blank db.Course
+	for field, value := range structseq.Fields(&blank) {
+		switch value.Kind() {
+		case reflect.Int:
+			value.SetInt(42)
+		case reflect.String:
+			value.SetString("<" + field.Tag.Get("csv") + ">")
+		}
+	}
+	fmt.Printf("  %+v\n", blank)
+}
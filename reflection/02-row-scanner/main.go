@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type (
+	// universityCount is the row of a query which the database lesson has no
+	// type for. The count is scanned from the column named n.
+	universityCount struct {
+		University string
+		Courses    int `db:"n"`
+	}
+
+	// courseName has a field for one of the columns of the courses table
+	courseName struct {
+		Name string
+	}
+)
+
+// getCourses is the hand-written loop of the database lesson. Its Scan names
+// every field of db.Course, in the order of the columns of the table.
+func getCourses(ctx context.Context, conn *sql.DB) ([]db.Course, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT * FROM courses LIMIT 3`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var courses []db.Course
+	for rows.Next() {
+		var c db.Course
+
+		err = rows.Scan(&c.ID, &c.Name, &c.University)
+		if err != nil {
+			return nil, err
+		}
+
+		courses = append(courses, c)
+	}
+
+	return courses, rows.Err()
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 20, DataDir: os.TempDir(), Seed: 1})
+	db.Seed(cfg.Seed)
+
+	coursesDB, err := db.New(cfg.DataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create database: %v\n", err)
+		os.Exit(1)
+	}
+	defer coursesDB.Close()
+
+	err = coursesDB.Seed(cfg.Count)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to seed database: %v\n", err)
+		os.Exit(1)
+	}
+
+	// The lesson queries the table of the CoursesDB directly, which hides its
+	// connection
+	conn, err := sql.Open("sqlite3", filepath.Join(cfg.DataDir, "courses.db"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open database: %v\n", err)
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+
+	fmt.Println("rows.Scan:")
+	courses, err := getCourses(ctx, conn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get courses: %v\n", err)
+		os.Exit(1)
+	}
+	for _, course := range courses {
+		fmt.Printf("  %+v\n", course)
+	}
+
+	// The same rows, with the fields found by reflection instead of listed
+	fmt.Println("\nQuery[db.Course]:")
+	for course, err := range Query[db.Course](ctx, conn, `SELECT * FROM courses LIMIT 3`) {
+		if err != nil {
+			fmt.Printf("  error: %v\n", err)
+			break
+		}
+
+		fmt.Printf("  %+v\n", course)
+	}
+
+	// Columns are matched by name, so their order does not matter, and any
+	// query gets a struct of its own without a Scan written for it
+	fmt.Println("\nQuery[universityCount]:")
+	query := `SELECT COUNT(*) AS n, university FROM courses GROUP BY university ORDER BY university`
+	for count, err := range Query[universityCount](ctx, conn, query) {
+		if err != nil {
+			fmt.Printf("  error: %v\n", err)
+			break
+		}
+
+		fmt.Printf("  %+v\n", count)
+	}
+
+	// A column without a field is reported before any row is scanned
+	fmt.Println("\nQuery[courseName]:")
+	for name, err := range Query[courseName](ctx, conn, `SELECT * FROM courses`) {
+		if err != nil {
+			fmt.Printf("  error: %v\n", err)
+			break
+		}
+
+		fmt.Printf("  %+v\n", name)
+	}
+}
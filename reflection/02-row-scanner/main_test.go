@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+func newConn(t *testing.T) *sql.DB {
+	t.Helper()
+
+	conn, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	_, err = conn.Exec(`CREATE TABLE courses (id INTEGER, name TEXT, university TEXT);
+		INSERT INTO courses VALUES (1, 'Chem-1', 'UCB'), (2, 'Physics-1', 'SJSU'), (3, 'Chem-2', 'UCB')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conn
+}
+
+// collect returns the values of seq, and fails the test at its first error
+func collect[T any](t *testing.T, seq iter.Seq2[T, error]) []T {
+	t.Helper()
+
+	var values []T
+	for v, err := range seq {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		values = append(values, v)
+	}
+
+	return values
+}
+
+func TestQuery(t *testing.T) {
+	conn := newConn(t)
+
+	got := collect(t, Query[db.Course](context.Background(), conn, `SELECT university, id, name FROM courses`))
+	expected := []db.Course{{ID: 1, Name: "Chem-1", University: "UCB"}, {ID: 2, Name: "Physics-1", University: "SJSU"}, {ID: 3, Name: "Chem-2", University: "UCB"}}
+
+	if !slices.Equal(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	seqtest.AssertStopsEarly2(t, 2, func() iter.Seq2[db.Course, error] {
+		return Query[db.Course](context.Background(), conn, `SELECT * FROM courses`)
+	})
+}
+
+func TestQueryTags(t *testing.T) {
+	type row struct {
+		db.Course
+		Count   int    `db:"n"`
+		Ignored string `db:"-"`
+	}
+
+	query := `SELECT university, COUNT(*) AS n FROM courses WHERE id > ? GROUP BY university ORDER BY university`
+	got := collect(t, Query[row](context.Background(), newConn(t), query, 1))
+
+	expected := []row{{Course: db.Course{University: "SJSU"}, Count: 1}, {Course: db.Course{University: "UCB"}, Count: 1}}
+	if !slices.Equal(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	conn := newConn(t)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{"missing field", `SELECT id, name, university, 1 AS credits FROM courses`, `no field for column "credits"`},
+		{"invalid query", `SELECT * FROM students`, "no such table"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs []error
+			for _, err := range Query[db.Course](context.Background(), conn, tt.query) {
+				errs = append(errs, err)
+			}
+
+			if len(errs) != 1 || errs[0] == nil || !strings.Contains(errs[0].Error(), tt.expected) {
+				t.Fatalf("expected a single error containing %q, got %v", tt.expected, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"iter"
+	"reflect"
+	"strings"
+
+	"github.com/manedurphy/golang-university/reflection/structseq"
+)
+
+// Query runs the query each time the iterator is ranged over, and yields its
+// rows as values of the struct type T. A column is scanned into the field
+// named by its db tag, or by its name if it has none, compared without regard
+// to case. A column without a field is an error, while fields without a
+// column keep their zero value.
+//
+//	for course, err := range Query[db.Course](ctx, conn, `SELECT * FROM courses`) {
+func Query[T any](ctx context.Context, conn *sql.DB, query string, args ...any) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		rows, err := conn.QueryContext(ctx, query, args...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer rows.Close()
+
+		columns, err := rows.Columns()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		indexes, err := mapColumns[T](columns)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		dest := make([]any, len(columns))
+		for rows.Next() {
+			var val T
+
+			v := reflect.ValueOf(&val).Elem()
+			for i, index := range indexes {
+				dest[i] = v.FieldByIndex(index).Addr().Interface()
+			}
+
+			err = rows.Scan(dest...)
+			if !yield(val, err) {
+				return
+			}
+		}
+
+		err = rows.Err()
+		if err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// mapColumns returns the index of the field of T each column is scanned into
+func mapColumns[T any](columns []string) ([][]int, error) {
+	var zero T
+
+	fields := make(map[string][]int)
+	for field := range structseq.Fields(&zero) {
+		name := field.Tag.Get("db")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fields[strings.ToLower(name)] = field.Index
+	}
+
+	indexes := make([][]int, len(columns))
+	for i, column := range columns {
+		index, ok := fields[strings.ToLower(column)]
+		if !ok {
+			return nil, fmt.Errorf("%T has no field for column %q", zero, column)
+		}
+
+		indexes[i] = index
+	}
+
+	return indexes, nil
+}
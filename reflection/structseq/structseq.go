@@ -0,0 +1,64 @@
+package structseq
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+)
+
+// Fields returns an iterator over the exported fields of the struct v, and
+// their values, in the order they are declared. The fields of an embedded
+// struct are yielded in place of it, with an Index leading to them from v,
+// like reflect.VisibleFields does.
+//
+// v may be a struct, or a pointer to one. The values of the fields of a
+// pointer can be set, which is how a decoder fills in a struct:
+//
+//	var course db.Course
+//	for field, value := range structseq.Fields(&course) {
+//		value.SetString(...)
+//	}
+//
+// Fields panics if v is not a struct, or a non-nil pointer to one.
+func Fields(v any) iter.Seq2[reflect.StructField, reflect.Value] {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("structseq: Fields of %T, which is not a struct or a pointer to one", v))
+	}
+
+	return func(yield func(reflect.StructField, reflect.Value) bool) {
+		fields(rv, nil, yield)
+	}
+}
+
+// fields yields the exported fields of the struct v, whose index from the
+// outermost struct is prefix. It returns false once yield does.
+func fields(v reflect.Value, prefix []int, yield func(reflect.StructField, reflect.Value) bool) bool {
+	t := v.Type()
+
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		sf.Index = append(append([]int(nil), prefix...), i)
+
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			if !fields(v.Field(i), sf.Index, yield) {
+				return false
+			}
+			continue
+		}
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		if !yield(sf, v.Field(i)) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,85 @@
+package structseq
+
+import (
+	"iter"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+type (
+	base struct {
+		ID int
+	}
+
+	course struct {
+		base
+		Name       string `db:"name"`
+		university string
+		Credits    int
+	}
+)
+
+// names returns the names of the fields yielded by Fields
+func names(v any) []string {
+	var names []string
+	for field := range Fields(v) {
+		names = append(names, field.Name)
+	}
+
+	return names
+}
+
+func TestFields(t *testing.T) {
+	c := course{base: base{ID: 7}, Name: "Chem-1", university: "UCB", Credits: 4}
+
+	// The unexported field is left out, and the embedded struct is replaced
+	// by its fields
+	if got, expected := names(c), []string{"ID", "Name", "Credits"}; !slices.Equal(got, expected) {
+		t.Fatalf("expected the fields %q, got %q", expected, got)
+	}
+
+	for field, value := range Fields(c) {
+		if got := reflect.ValueOf(c).FieldByIndex(field.Index); got.Interface() != value.Interface() {
+			t.Fatalf("expected the index of %s to lead to %v, got %v", field.Name, value, got)
+		}
+
+		if value.CanSet() {
+			t.Fatalf("expected the fields of a struct which is not addressable to be read-only")
+		}
+	}
+
+	seqtest.AssertStopsEarly2(t, 2, func() iter.Seq2[reflect.StructField, reflect.Value] { return Fields(c) })
+}
+
+func TestFieldsSet(t *testing.T) {
+	var c course
+	for field, value := range Fields(&c) {
+		switch field.Name {
+		case "ID", "Credits":
+			value.SetInt(3)
+		case "Name":
+			value.SetString(field.Tag.Get("db"))
+		}
+	}
+
+	if expected := (course{base: base{ID: 3}, Name: "name", Credits: 3}); c != expected {
+		t.Fatalf("expected %+v, got %+v", expected, c)
+	}
+}
+
+func TestFieldsPanics(t *testing.T) {
+	for _, v := range []any{42, (*course)(nil), nil} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected Fields(%#v) to panic", v)
+				}
+			}()
+
+			Fields(v)
+		}()
+	}
+}
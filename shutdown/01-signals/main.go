@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"iter"
+	"log/slog"
+	"math"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessonlog"
+)
+
+var signalAfter time.Duration
+
+var logger *slog.Logger
+
+func init() {
+	flag.DurationVar(&signalAfter, "signal-after", 0, "Send an interrupt signal to this process after the specified duration (0 waits for Ctrl+C)")
+}
+
+func isPrime(n int) bool {
+	if n <= 1 {
+		return false
+	}
+
+	sqrtN := int(math.Sqrt(float64(n)))
+	for i := 2; i <= sqrtN; i++ {
+		if n%i == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func generatePrimeNumbers() iter.Seq[int] {
+	return func(yield func(i int) bool) {
+		defer logger.Info("prime number generator stopped")
+
+		n := 0
+
+		for {
+			if isPrime(n) {
+				if !yield(n) {
+					return
+				}
+			}
+
+			n++
+		}
+	}
+}
+
+// interruptAfter sends an interrupt signal to the current process, which is
+// the same thing that happens when Ctrl+C is pressed in a terminal
+func interruptAfter(d time.Duration) {
+	time.AfterFunc(d, func() {
+		p, _ := os.FindProcess(os.Getpid())
+		p.Signal(os.Interrupt)
+	})
+}
+
+func main() {
+	var (
+		count   int
+		largest int
+	)
+
+	// -v, -q, and -json configure the logger
+	logFlags := lessonlog.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	logger = lessonlog.New(logFlags)
+
+	// The context is cancelled when the process receives SIGINT or SIGTERM.
+	// Calling stop restores the default behavior, so a second Ctrl+C kills
+	// the process immediately.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if signalAfter > 0 {
+		interruptAfter(signalAfter)
+	}
+
+	logger.Info("searching for prime numbers, press Ctrl+C to stop")
+
+	for num := range generatePrimeNumbers() {
+		if ctx.Err() != nil {
+			break
+		}
+
+		count++
+		largest = num
+	}
+
+	stop()
+	logger.Info("received signal", lessonlog.KeyCount, count, "largest", largest)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessonlog"
+	"github.com/manedurphy/golang-university/internal/report"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+var (
+	numWorkers  int
+	signalAfter time.Duration
+)
+
+func init() {
+	flag.IntVar(&numWorkers, "workers", 4, "The number of workers processing courses")
+	flag.DurationVar(&signalAfter, "signal-after", 0, "Send an interrupt signal to this process after the specified duration (0 waits for Ctrl+C)")
+}
+
+// interruptAfter sends an interrupt signal to the current process, which is
+// the same thing that happens when Ctrl+C is pressed in a terminal
+func interruptAfter(d time.Duration) {
+	time.AfterFunc(d, func() {
+		p, _ := os.FindProcess(os.Getpid())
+		p.Signal(os.Interrupt)
+	})
+}
+
+// processCourse simulates a job which must not be interrupted half way
+func processCourse(course db.Course) {
+	time.Sleep(10 * time.Millisecond)
+}
+
+func main() {
+	var (
+		wg        sync.WaitGroup
+		yielded   atomic.Int64
+		processed atomic.Int64
+	)
+
+	// -v, -q, and -json configure the logger
+	logFlags := lessonlog.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	logger := lessonlog.New(logFlags)
+
+	// The run's report is written once main returns
+	defer report.Write()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if signalAfter > 0 {
+		interruptAfter(signalAfter)
+	}
+
+	// The buffer holds jobs which were accepted but not started yet. They are
+	// still processed during shutdown.
+	jobs := make(chan db.Course, numWorkers)
+
+	for worker := range numWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// Workers do not watch the context. They stop once the jobs
+			// channel is closed and drained, so no accepted job is lost.
+			for course := range jobs {
+				processCourse(course)
+				processed.Add(1)
+				report.Add("processed", 1)
+				logger.Debug("processed course", lessonlog.KeyWorker, worker, lessonlog.KeyCourse, course.ID)
+			}
+		}()
+	}
+
+	logger.Info("processing courses, press Ctrl+C to stop")
+
+	// The producer is the only part of the pipeline that watches the
+	// context. Breaking out of the loop stops the course iterator.
+	for course := range db.GenerateCourses(1000000) {
+		select {
+		case jobs <- course:
+			yielded.Add(1)
+			report.Add("yielded", 1)
+			continue
+		case <-ctx.Done():
+		}
+
+		break
+	}
+
+	stop()
+	logger.Info("received signal, draining in-flight courses", lessonlog.KeyCount, yielded.Load()-processed.Load())
+
+	done := report.Time("drain")
+	close(jobs)
+	wg.Wait()
+	done()
+
+	logger.Info("shutdown complete", "yielded", yielded.Load(), "processed", processed.Load())
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessonlog"
+	"github.com/manedurphy/golang-university/internal/report"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+var (
+	numWorkers      int
+	jobDuration     time.Duration
+	shutdownTimeout time.Duration
+	signalAfter     time.Duration
+)
+
+func init() {
+	flag.IntVar(&numWorkers, "workers", 4, "The number of workers processing courses")
+	flag.DurationVar(&jobDuration, "job-duration", 2*time.Second, "How long it takes to process a single course")
+	flag.DurationVar(&shutdownTimeout, "shutdown-timeout", 500*time.Millisecond, "How long to wait for in-flight courses during shutdown")
+	flag.DurationVar(&signalAfter, "signal-after", 0, "Send an interrupt signal to this process after the specified duration (0 waits for Ctrl+C)")
+}
+
+// interruptAfter sends an interrupt signal to the current process, which is
+// the same thing that happens when Ctrl+C is pressed in a terminal
+func interruptAfter(d time.Duration) {
+	time.AfterFunc(d, func() {
+		p, _ := os.FindProcess(os.Getpid())
+		p.Signal(os.Interrupt)
+	})
+}
+
+// processCourse simulates a slow job which can be aborted through its context
+func processCourse(ctx context.Context, course db.Course) error {
+	select {
+	case <-time.After(jobDuration):
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("course %d: %w", course.ID, ctx.Err())
+	}
+}
+
+func main() {
+	var (
+		wg        sync.WaitGroup
+		processed atomic.Int64
+		aborted   atomic.Int64
+	)
+
+	// -v, -q, and -json configure the logger
+	logFlags := lessonlog.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	logger := lessonlog.New(logFlags)
+
+	// The run's report is written once main returns, or by report.Exit
+	defer report.Write()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if signalAfter > 0 {
+		interruptAfter(signalAfter)
+	}
+
+	// The work context is separate from the signal context. It is only
+	// cancelled when the shutdown timeout expires, which aborts in-flight jobs.
+	workCtx, cancelWork := context.WithCancel(context.Background())
+	defer cancelWork()
+
+	jobs := make(chan db.Course)
+
+	for worker := range numWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for course := range jobs {
+				err := processCourse(workCtx, course)
+				if err != nil {
+					logger.Warn("aborted course", lessonlog.KeyWorker, worker, lessonlog.Err(err))
+					aborted.Add(1)
+					report.Add("aborted", 1)
+					continue
+				}
+
+				processed.Add(1)
+				report.Add("processed", 1)
+				logger.Debug("processed course", lessonlog.KeyWorker, worker, lessonlog.KeyCourse, course.ID)
+			}
+		}()
+	}
+
+	logger.Info("processing courses, press Ctrl+C to stop")
+
+	for course := range db.GenerateCourses(1000000) {
+		select {
+		case jobs <- course:
+			continue
+		case <-ctx.Done():
+		}
+
+		break
+	}
+
+	// After stop is called, a second Ctrl+C terminates the process
+	// immediately instead of waiting for the shutdown timeout
+	stop()
+	close(jobs)
+
+	done := make(chan struct{})
+	stopTimer := report.Time("shutdown")
+	go func() {
+		wg.Wait()
+		stopTimer()
+		close(done)
+	}()
+
+	logger.Info("received signal, waiting for in-flight courses", "timeout", shutdownTimeout.String())
+
+	select {
+	case <-done:
+		logger.Info("shutdown complete", "processed", processed.Load())
+	case <-time.After(shutdownTimeout):
+		cancelWork()
+		<-done
+
+		logger.Error("shutdown timed out", "processed", processed.Load(), "aborted", aborted.Load())
+		report.Exit(1)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/sorting/sorts"
+)
+
+// shapeSize is the number of values each shape of input has, which is too
+// many to show every step, but enough for the number of steps to tell the
+// shapes apart
+const shapeSize = 100
+
+// show prints s, and then every step of sorting it with a, as bars and as
+// numbers, and returns the number of steps
+func show(a sorts.Algorithm, s []int) int {
+	fmt.Printf("  %s  %v\n", sorts.Bars(s, len(s)), s)
+
+	var steps int
+	for state := range a.Steps(s) {
+		steps++
+		fmt.Printf("  %s  %v\n", sorts.Bars(state, len(state)), state)
+	}
+
+	return steps
+}
+
+// count returns the number of steps of seq
+func count(seq iter.Seq[[]int]) int {
+	var n int
+	for range seq {
+		n++
+	}
+
+	return n
+}
+
+// inversions returns the number of pairs of values of s which are out of
+// order
+func inversions(s []int) int {
+	var n int
+	for i := range s {
+		for j := i + 1; j < len(s); j++ {
+			if s[i] > s[j] {
+				n++
+			}
+		}
+	}
+
+	return n
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 8, Seed: 1})
+	r := cfg.Rand()
+
+	s := sorts.Random(r, cfg.Count)
+	pairs := inversions(s)
+
+	fmt.Println("Insertion sort:")
+	steps := show(sorts.Insertion, slices.Clone(s))
+	fmt.Printf("  %d steps for %d pairs out of order\n", steps, pairs)
+
+	// A swap of neighbours puts a single pair in order, so the steps depend
+	// on the order of the input rather than on its length alone
+	fmt.Printf("\nSteps for %d values:\n", shapeSize)
+	for _, shape := range sorts.Shapes() {
+		fmt.Printf("  %-13s %4d\n", shape.Name, count(sorts.Insertion.Steps(shape.Generate(r, shapeSize))))
+	}
+}
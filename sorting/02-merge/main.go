@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/sorting/sorts"
+)
+
+// shapeSize is the number of values each shape of input has
+const shapeSize = 100
+
+// show prints s, and then every step of sorting it with a, as bars and as
+// numbers, and returns the number of steps
+func show(a sorts.Algorithm, s []int) int {
+	fmt.Printf("  %s  %v\n", sorts.Bars(s, len(s)), s)
+
+	var steps int
+	for state := range a.Steps(s) {
+		steps++
+		fmt.Printf("  %s  %v\n", sorts.Bars(state, len(state)), state)
+	}
+
+	return steps
+}
+
+// count returns the number of steps of seq
+func count(seq iter.Seq[[]int]) int {
+	var n int
+	for range seq {
+		n++
+	}
+
+	return n
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 8, Seed: 1})
+	r := cfg.Rand()
+
+	s := sorts.Random(r, cfg.Count)
+
+	// A step is a whole merge, as the values of the left half are in the
+	// buffer while it runs. The halves of one value are merged first, then
+	// the halves of two, and so on.
+	fmt.Println("Merge sort:")
+	steps := show(sorts.Merge, slices.Clone(s))
+	fmt.Printf("  %d merges of at most %d\n", steps, len(s)-1)
+
+	// Every shape is split into the same halves, but the halves which are
+	// already in order are not merged
+	fmt.Printf("\nMerges for %d values:\n", shapeSize)
+	for _, shape := range sorts.Shapes() {
+		fmt.Printf("  %-13s %2d\n", shape.Name, count(sorts.Merge.Steps(shape.Generate(r, shapeSize))))
+	}
+}
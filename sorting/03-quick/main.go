@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/sorting/sorts"
+)
+
+const (
+	// shapeSize is the number of values each shape of input has
+	shapeSize = 100
+
+	// stopAfter is the number of steps after which the second sort is
+	// stopped
+	stopAfter = 3
+)
+
+// show prints s, and then every step of sorting it with a, as bars and as
+// numbers, and returns the number of steps
+func show(a sorts.Algorithm, s []int) int {
+	fmt.Printf("  %s  %v\n", sorts.Bars(s, len(s)), s)
+
+	var steps int
+	for state := range a.Steps(s) {
+		steps++
+		fmt.Printf("  %s  %v\n", sorts.Bars(state, len(state)), state)
+	}
+
+	return steps
+}
+
+// count returns the number of steps of seq
+func count(seq iter.Seq[[]int]) int {
+	var n int
+	for range seq {
+		n++
+	}
+
+	return n
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 8, Seed: 1})
+	r := cfg.Rand()
+
+	s := sorts.Random(r, cfg.Count)
+
+	// The middle value is swapped to the end as the pivot, the values less
+	// than it are swapped to the front, and the pivot is swapped in after
+	// them, which is its place in the sorted slice
+	fmt.Println("Quick sort:")
+	steps := show(sorts.Quick, slices.Clone(s))
+	fmt.Printf("  %d swaps\n", steps)
+
+	// The sort only runs while the loop asks for steps, so breaking out of
+	// it stops the sort where it is, and leaves the slice partly sorted
+	fmt.Printf("\nStopped after %d swaps:\n", stopAfter)
+	stopped := slices.Clone(s)
+	steps = 0
+	for range sorts.Quick.Steps(stopped) {
+		if steps++; steps == stopAfter {
+			break
+		}
+	}
+	fmt.Printf("  %s  %v\n", sorts.Bars(stopped, len(stopped)), stopped)
+
+	// With the middle value as the pivot, a sorted input is split in halves,
+	// rather than into a single value and all the others
+	fmt.Printf("\nSwaps for %d values:\n", shapeSize)
+	for _, shape := range sorts.Shapes() {
+		fmt.Printf("  %-13s %3d\n", shape.Name, count(sorts.Quick.Steps(shape.Generate(r, shapeSize))))
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/sorting/sorts"
+)
+
+// shapeSize is the number of values each shape of input has
+const shapeSize = 100
+
+// show prints s, and then every step of sorting it with a, as bars and as
+// numbers, and returns the number of steps
+func show(a sorts.Algorithm, s []int) int {
+	fmt.Printf("  %s  %v\n", sorts.Bars(s, len(s)), s)
+
+	var steps int
+	for state := range a.Steps(s) {
+		steps++
+		fmt.Printf("  %s  %v\n", sorts.Bars(state, len(state)), state)
+	}
+
+	return steps
+}
+
+// count returns the number of steps of seq
+func count(seq iter.Seq[[]int]) int {
+	var n int
+	for range seq {
+		n++
+	}
+
+	return n
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 8, Seed: 1})
+	r := cfg.Rand()
+
+	s := sorts.Random(r, cfg.Count)
+
+	// The first swaps make a max-heap of the slice, with the greatest value
+	// first. Then the first value is swapped to the end of the heap, which
+	// shrinks by one, and the value swapped in sinks back into place.
+	fmt.Println("Heap sort:")
+	steps := show(sorts.Heap, slices.Clone(s))
+	fmt.Printf("  %d swaps\n", steps)
+
+	// A sorted slice is the opposite of a max-heap, so heap sort moves every
+	// value, and a reversed one is already a max-heap
+	fmt.Printf("\nSwaps for %d values:\n", shapeSize)
+	for _, shape := range sorts.Shapes() {
+		fmt.Printf("  %-13s %3d\n", shape.Name, count(sorts.Heap.Steps(shape.Generate(r, shapeSize))))
+	}
+}
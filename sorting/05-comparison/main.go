@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/sorting/sorts"
+)
+
+const (
+	// raceSize is the number of values the algorithms race on, which is few
+	// enough to show every step of every one, and to give each value a bar
+	// of its own height
+	raceSize = 8
+
+	// rounds is how often each sort is timed. The fastest round is reported,
+	// which is the one the least disturbed by the rest of the machine.
+	rounds = 3
+)
+
+// sorter is a sort to time, either an algorithm of the track or one of the
+// standard library
+type sorter struct {
+	name string
+	sort func([]int)
+}
+
+// race steps every algorithm through the sort of its own copy of s, a step of
+// each per line, so that they can be watched side by side. The steps of an
+// algorithm are pulled, as a range loop can only step through one sequence
+// at a time.
+func race(s []int) {
+	algorithms := sorts.Algorithms()
+
+	nexts := make([]func() ([]int, bool), len(algorithms))
+	states := make([][]int, len(algorithms))
+	steps := make([]int, len(algorithms))
+
+	var header, start strings.Builder
+	for i, a := range algorithms {
+		next, stop := iter.Pull(a.Steps(slices.Clone(s)))
+		defer stop()
+
+		nexts[i] = next
+		states[i] = s
+		fmt.Fprintf(&header, "  %-*s", len(s), a.Name)
+		fmt.Fprintf(&start, "  %s", sorts.Bars(s, len(s)))
+	}
+	fmt.Println(strings.TrimRight(header.String(), " "))
+	fmt.Println(start.String())
+
+	for {
+		var line strings.Builder
+		running := false
+
+		for i, next := range nexts {
+			if next != nil {
+				if state, ok := next(); ok {
+					states[i] = state
+					steps[i]++
+					running = true
+				} else {
+					nexts[i] = nil
+				}
+			}
+
+			fmt.Fprintf(&line, "  %s", sorts.Bars(states[i], len(s)))
+		}
+
+		if !running {
+			break
+		}
+
+		fmt.Println(line.String())
+	}
+
+	fmt.Print("steps:")
+	for i, a := range algorithms {
+		fmt.Printf(" %s %d", a.Name, steps[i])
+		if i < len(algorithms)-1 {
+			fmt.Print(",")
+		}
+	}
+	fmt.Println()
+}
+
+// fastest returns the fastest of rounds sorts of a copy of input
+func fastest(sort func([]int), input []int) time.Duration {
+	var best time.Duration
+
+	s := make([]int, len(input))
+	for i := range rounds {
+		copy(s, input)
+
+		start := time.Now()
+		sort(s)
+		if elapsed := time.Since(start); i == 0 || elapsed < best {
+			best = elapsed
+		}
+	}
+
+	if !slices.IsSorted(s) {
+		panic("the values are not sorted")
+	}
+
+	return best
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 5000, Seed: 1})
+	r := cfg.Rand()
+
+	fmt.Printf("Race on %d values:\n", raceSize)
+	race(sorts.Random(r, raceSize))
+
+	// slices.Sort is a pattern-defeating quicksort, which switches to
+	// insertion sort for short runs and to heap sort for bad pivots, and
+	// spots the inputs which are already in order
+	sorters := []sorter{{name: "slices.Sort", sort: slices.Sort[[]int]}}
+	for _, a := range sorts.Algorithms() {
+		sorters = append(sorters, sorter{name: a.Name, sort: a.Sort})
+	}
+
+	for _, shape := range sorts.Shapes() {
+		fmt.Printf("\n%d %s values:\n", cfg.Count, shape.Name)
+
+		input := shape.Generate(r, cfg.Count)
+		for _, s := range sorters {
+			fmt.Printf("  %-11s %v\n", s.name, fastest(s.sort, input).Round(time.Microsecond))
+		}
+	}
+}
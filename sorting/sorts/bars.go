@@ -0,0 +1,25 @@
+package sorts
+
+import "strings"
+
+// bars are the blocks of Bars, from the lowest to the highest
+var bars = []rune("▁▂▃▄▅▆▇█")
+
+// Bars draws s as a row of bars, one per value, whose heights grow with the
+// values up to top, which must be positive. A value of top or more is drawn
+// as a full block, and a value less than 1 as a space.
+func Bars(s []int, top int) string {
+	var sb strings.Builder
+	for _, v := range s {
+		if v < 1 {
+			sb.WriteByte(' ')
+			continue
+		}
+
+		// The values from 1 to top are spread evenly over the heights
+		h := min((v*len(bars)+top-1)/top, len(bars))
+		sb.WriteRune(bars[h-1])
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,48 @@
+package sorts
+
+func heap(s []int, yield func([]int) bool) bool {
+	// The values with children, from the last one up to the root, are sifted
+	// down below the greater of their children, which makes s a max-heap
+	for i := len(s)/2 - 1; i >= 0; i-- {
+		if !siftDown(s, i, len(s), yield) {
+			return false
+		}
+	}
+
+	// The top of the heap is the greatest value left, which belongs right
+	// after the heap
+	for end := len(s) - 1; end > 0; end-- {
+		s[0], s[end] = s[end], s[0]
+		if !step(yield, s) || !siftDown(s, 0, end, yield) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// siftDown swaps the value at i with the greater of its children in s[:end],
+// until it is not less than either of them
+func siftDown(s []int, i, end int, yield func([]int) bool) bool {
+	for {
+		child := 2*i + 1
+		if child >= end {
+			return true
+		}
+
+		if right := child + 1; right < end && s[right] > s[child] {
+			child = right
+		}
+
+		if s[i] >= s[child] {
+			return true
+		}
+
+		s[i], s[child] = s[child], s[i]
+		if !step(yield, s) {
+			return false
+		}
+
+		i = child
+	}
+}
@@ -0,0 +1,14 @@
+package sorts
+
+func insertion(s []int, yield func([]int) bool) bool {
+	for i := 1; i < len(s); i++ {
+		for j := i; j > 0 && s[j] < s[j-1]; j-- {
+			s[j], s[j-1] = s[j-1], s[j]
+			if !step(yield, s) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,51 @@
+package sorts
+
+func mergeSort(s []int, yield func([]int) bool) bool {
+	// A single buffer, half the length of s, is enough for every merge, as
+	// only the left run is copied out of s
+	buf := make([]int, len(s)/2)
+
+	return mergeRuns(s, buf, s, yield)
+}
+
+// mergeRuns sorts part, a part of all, by sorting its halves and merging
+// them. The steps yield all, so that they show every value.
+func mergeRuns(all, buf, part []int, yield func([]int) bool) bool {
+	if len(part) < 2 {
+		return true
+	}
+
+	mid := len(part) / 2
+	if !mergeRuns(all, buf, part[:mid], yield) || !mergeRuns(all, buf, part[mid:], yield) {
+		return false
+	}
+
+	// The halves are already in order
+	if part[mid-1] <= part[mid] {
+		return true
+	}
+
+	left := buf[:mid]
+	copy(left, part[:mid])
+
+	// The next value written to part is at k, and k never overtakes j, the
+	// next value of the right run, so the right run is merged in place
+	i, j, k := 0, mid, 0
+	for i < len(left) && j < len(part) {
+		// Taking from the left run on a tie keeps equal values in the order
+		// they came in, so the sort is stable
+		if part[j] < left[i] {
+			part[k] = part[j]
+			j++
+		} else {
+			part[k] = left[i]
+			i++
+		}
+		k++
+	}
+
+	// What is left of the right run is already in place
+	copy(part[k:], left[i:])
+
+	return step(yield, all)
+}
@@ -0,0 +1,66 @@
+package sorts
+
+func quick(s []int, yield func([]int) bool) bool {
+	return quickRange(s, 0, len(s), yield)
+}
+
+// quickRange sorts s[lo:hi]. The steps yield all of s, so that they show
+// every value.
+func quickRange(s []int, lo, hi int, yield func([]int) bool) bool {
+	for hi-lo > 1 {
+		p, ok := partition(s, lo, hi, yield)
+		if !ok {
+			return false
+		}
+
+		// Recursing into the smaller side, and looping on the larger one,
+		// keeps the stack at log n calls however the pivots fall
+		if p-lo < hi-p {
+			if !quickRange(s, lo, p, yield) {
+				return false
+			}
+			lo = p + 1
+		} else {
+			if !quickRange(s, p+1, hi, yield) {
+				return false
+			}
+			hi = p
+		}
+	}
+
+	return true
+}
+
+// partition picks the middle value of s[lo:hi] as the pivot, moves the values
+// less than it before it and the others after it, and returns its index. The
+// middle value keeps a sorted or reversed s from picking the smallest or the
+// greatest value every time, which would make the sort quadratic.
+func partition(s []int, lo, hi int, yield func([]int) bool) (int, bool) {
+	last := hi - 1
+
+	swap := func(i, j int) bool {
+		if i == j {
+			return true
+		}
+
+		s[i], s[j] = s[j], s[i]
+		return step(yield, s)
+	}
+
+	if !swap(lo+(hi-lo)/2, last) {
+		return 0, false
+	}
+
+	pivot := s[last]
+	p := lo
+	for i := lo; i < last; i++ {
+		if s[i] < pivot {
+			if !swap(i, p) {
+				return 0, false
+			}
+			p++
+		}
+	}
+
+	return p, swap(p, last)
+}
@@ -0,0 +1,66 @@
+package sorts
+
+import (
+	"math/rand/v2"
+	"slices"
+)
+
+// Shape is an order of the values from 1 to n, as the input of a sort. The
+// same algorithm can take a few steps for one shape and a lot for another.
+type Shape struct {
+	// Name is the name of the shape, such as "random"
+	Name string
+	// Generate returns the values from 1 to n in the order of the shape. r
+	// is only used by the shapes which are random.
+	Generate func(r *rand.Rand, n int) []int
+}
+
+// Shapes returns the shapes of input the algorithms are compared on
+func Shapes() []Shape {
+	return []Shape{
+		{Name: "random", Generate: Random},
+		{Name: "sorted", Generate: Sorted},
+		{Name: "reversed", Generate: Reversed},
+		{Name: "nearly sorted", Generate: NearlySorted},
+	}
+}
+
+// Random returns the values from 1 to n in a random order
+func Random(r *rand.Rand, n int) []int {
+	s := r.Perm(n)
+	for i := range s {
+		s[i]++
+	}
+
+	return s
+}
+
+// Sorted returns the values from 1 to n in order
+func Sorted(_ *rand.Rand, n int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = i + 1
+	}
+
+	return s
+}
+
+// Reversed returns the values from n down to 1
+func Reversed(r *rand.Rand, n int) []int {
+	s := Sorted(r, n)
+	slices.Reverse(s)
+
+	return s
+}
+
+// NearlySorted returns the values from 1 to n in order, but for a swap of
+// two neighbours for every 20 values
+func NearlySorted(r *rand.Rand, n int) []int {
+	s := Sorted(r, n)
+	for range n / 20 {
+		i := r.IntN(n - 1)
+		s[i], s[i+1] = s[i+1], s[i]
+	}
+
+	return s
+}
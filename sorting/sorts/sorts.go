@@ -0,0 +1,63 @@
+// Package sorts implements insertion, merge, quick, and heap sort on slices of
+// ints. Every algorithm sorts a slice in place, and can also be run one step
+// at a time, as an iterator which yields the slice after each change it
+// makes, so that the lessons of the sorting track can show how it gets there.
+package sorts
+
+import "iter"
+
+// Algorithm is a sorting algorithm
+type Algorithm struct {
+	// Name is the name of the algorithm, such as "insertion"
+	Name string
+	// sort sorts s in place, and calls yield with s after each step, unless
+	// yield is nil. It stops as soon as yield returns false, and reports
+	// whether it sorted s.
+	sort func(s []int, yield func([]int) bool) bool
+}
+
+var (
+	// Insertion sorts by moving each value to the left, one swap at a time,
+	// until it is in order with the values before it. Every swap is a step,
+	// and every swap puts a single pair of values in order, so it takes as
+	// many steps as there are pairs out of order.
+	Insertion = Algorithm{Name: "insertion", sort: insertion}
+	// Merge sorts each half, and merges the sorted halves through a buffer.
+	// During a merge the values are partly in the buffer, so every merge is
+	// a single step.
+	Merge = Algorithm{Name: "merge", sort: mergeSort}
+	// Quick moves the values less than a pivot before it, and the others
+	// after it, and then sorts each side. Every swap is a step.
+	Quick = Algorithm{Name: "quick", sort: quick}
+	// Heap orders the values as a max-heap, and then swaps its top, the
+	// greatest value left, with the last value of the heap until the heap
+	// is empty. Every swap is a step.
+	Heap = Algorithm{Name: "heap", sort: heap}
+)
+
+// Algorithms returns the algorithms of the package, from the simplest to the
+// most involved
+func Algorithms() []Algorithm {
+	return []Algorithm{Insertion, Merge, Quick, Heap}
+}
+
+// Sort sorts s in place
+func (a Algorithm) Sort(s []int) {
+	a.sort(s, nil)
+}
+
+// Steps returns an iterator which sorts s in place, and yields s after each
+// step. It yields s itself rather than a copy, so a loop which keeps the
+// steps must clone them. Breaking out of the loop stops the sort, and leaves
+// s as it was at that step.
+func (a Algorithm) Steps(s []int) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		a.sort(s, yield)
+	}
+}
+
+// step calls yield with s, and reports whether to go on sorting. A nil yield
+// goes on without a step.
+func step(yield func([]int) bool, s []int) bool {
+	return yield == nil || yield(s)
+}
@@ -0,0 +1,188 @@
+package sorts
+
+import (
+	"fmt"
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/seqtest"
+)
+
+func newRand() *rand.Rand {
+	return rand.New(rand.NewPCG(1, 2))
+}
+
+func TestSort(t *testing.T) {
+	r := newRand()
+
+	for _, a := range Algorithms() {
+		for _, shape := range Shapes() {
+			for _, n := range []int{0, 1, 2, 3, 17, 100, 1000} {
+				s := shape.Generate(r, n)
+				a.Sort(s)
+
+				if !slices.Equal(s, Sorted(r, n)) {
+					t.Fatalf("%s of %d %s values: got %v", a.Name, n, shape.Name, s)
+				}
+			}
+		}
+
+		// Values which are not a permutation, with duplicates and negatives
+		s := []int{3, -1, 3, 0, 7, -1, 2, 3}
+		expected := slices.Sorted(slices.Values(s))
+
+		if a.Sort(s); !slices.Equal(s, expected) {
+			t.Fatalf("%s: expected %v, got %v", a.Name, expected, s)
+		}
+	}
+}
+
+func TestSteps(t *testing.T) {
+	r := newRand()
+
+	for _, a := range Algorithms() {
+		input := Random(r, 50)
+		s := slices.Clone(input)
+
+		var steps int
+		for state := range a.Steps(s) {
+			steps++
+
+			// Every step shows all the values, in some order
+			if !slices.Equal(slices.Sorted(slices.Values(state)), Sorted(r, 50)) {
+				t.Fatalf("%s: step %d lost values: %v", a.Name, steps, state)
+			}
+		}
+
+		if steps == 0 || !slices.IsSorted(s) {
+			t.Fatalf("%s: expected s to be sorted after %d steps, got %v", a.Name, steps, s)
+		}
+
+		seqtest.AssertStopsEarly(t, 3, func() iter.Seq[[]int] {
+			return a.Steps(slices.Clone(input))
+		})
+	}
+}
+
+// clones yields a copy of each slice of seq, which may reuse its slice
+func clones(seq iter.Seq[[]int]) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		for s := range seq {
+			if !yield(slices.Clone(s)) {
+				return
+			}
+		}
+	}
+}
+
+func TestInsertionStepsAreInversions(t *testing.T) {
+	s := []int{3, 1, 4, 2}
+
+	// (3, 1), (3, 2), and (4, 2) are out of order
+	seqtest.AssertYields(t, clones(Insertion.Steps(s)), [][]int{
+		{1, 3, 4, 2},
+		{1, 3, 2, 4},
+		{1, 2, 3, 4},
+	})
+}
+
+func TestNoStepsWhenSorted(t *testing.T) {
+	// Quick and heap sort move the values of a sorted slice, and put them
+	// back, but insertion sort finds nothing out of order, and merge sort
+	// finds every pair of halves already in order
+	for _, a := range []Algorithm{Insertion, Merge} {
+		for range a.Steps(Sorted(nil, 100)) {
+			t.Fatalf("%s: expected no step for sorted values", a.Name)
+		}
+	}
+}
+
+func TestStepsStop(t *testing.T) {
+	for _, a := range Algorithms() {
+		s := Reversed(nil, 20)
+
+		var last []int
+		for state := range a.Steps(s) {
+			last = slices.Clone(state)
+			break
+		}
+
+		// Nothing was sorted after the loop stopped
+		if !slices.Equal(s, last) {
+			t.Fatalf("%s: expected s to stay %v after the loop stopped, got %v", a.Name, last, s)
+		}
+	}
+}
+
+func TestBars(t *testing.T) {
+	tests := []struct {
+		s        []int
+		top      int
+		expected string
+	}{
+		{[]int{1, 2, 3, 4, 5, 6, 7, 8}, 8, "▁▂▃▄▅▆▇█"},
+		{[]int{16, 1, 8, 9}, 16, "█▁▄▅"},
+		{[]int{0, 3, 9}, 3, " ██"},
+		{nil, 1, ""},
+	}
+
+	for _, test := range tests {
+		if got := Bars(test.s, test.top); got != test.expected {
+			t.Fatalf("Bars(%v, %d): expected %q, got %q", test.s, test.top, test.expected, got)
+		}
+	}
+}
+
+func BenchmarkSort(b *testing.B) {
+	const n = 1000
+
+	for _, shape := range Shapes() {
+		input := shape.Generate(newRand(), n)
+
+		sorts := []struct {
+			name string
+			sort func([]int)
+		}{
+			{"slices.Sort", slices.Sort[[]int]},
+		}
+		for _, a := range Algorithms() {
+			sorts = append(sorts, struct {
+				name string
+				sort func([]int)
+			}{a.Name, a.Sort})
+		}
+
+		for _, sort := range sorts {
+			b.Run(fmt.Sprintf("%s/%s", shape.Name, sort.name), func(b *testing.B) {
+				s := make([]int, n)
+				b.ReportAllocs()
+
+				for range b.N {
+					copy(s, input)
+					sort.sort(s)
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkSteps measures what yielding every step costs, compared with the
+// Sort of the same algorithm
+func BenchmarkSteps(b *testing.B) {
+	input := Random(newRand(), 1000)
+
+	for _, a := range Algorithms() {
+		b.Run(a.Name, func(b *testing.B) {
+			s := make([]int, len(input))
+			b.ReportAllocs()
+
+			for range b.N {
+				copy(s, input)
+				for range a.Steps(s) {
+				}
+			}
+		})
+	}
+}
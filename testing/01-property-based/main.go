@@ -0,0 +1,186 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	linked_list "github.com/manedurphy/golang-university/iterators/02-range-over-func/03-linked-list/linked-list"
+	"pgregory.net/rapid"
+)
+
+// property is a statement which has to hold for every input rapid generates
+type property struct {
+	name  string
+	check func(t *rapid.T)
+}
+
+var properties = []property{
+	{"linked list: traversal yields the values in the order they were appended", appendOrder},
+	{"linked list: breaking out of the loop yields a prefix", breakPrefix},
+	{"tree: traversal is sorted", treeSorted},
+	{"tree: traversal yields every key once", treeUnique},
+}
+
+func appendOrder(t *rapid.T) {
+	values := rapid.SliceOf(rapid.Int()).Draw(t, "values")
+
+	list := linked_list.NewLinkedList()
+	for _, v := range values {
+		list.Append(v)
+	}
+
+	var got []int
+	for node := range list.Traverse() {
+		got = append(got, node.Value())
+	}
+
+	if !slices.Equal(got, values) {
+		t.Fatalf("expected %v, got %v", values, got)
+	}
+}
+
+func breakPrefix(t *rapid.T) {
+	values := rapid.SliceOfN(rapid.Int(), 1, -1).Draw(t, "values")
+	limit := rapid.IntRange(1, len(values)).Draw(t, "limit")
+
+	list := linked_list.NewLinkedList()
+	for _, v := range values {
+		list.Append(v)
+	}
+
+	var got []int
+	for node := range list.Traverse() {
+		got = append(got, node.Value())
+		if len(got) == limit {
+			break
+		}
+	}
+
+	if !slices.Equal(got, values[:limit]) {
+		t.Fatalf("expected %v, got %v", values[:limit], got)
+	}
+}
+
+// newTree inserts keys drawn by rapid into a new tree
+func newTree(t *rapid.T) (*Tree[int], []int) {
+	keys := rapid.SliceOf(rapid.Int()).Draw(t, "keys")
+
+	var tree Tree[int]
+	for _, key := range keys {
+		tree.Insert(key)
+	}
+
+	return &tree, keys
+}
+
+func treeSorted(t *rapid.T) {
+	tree, _ := newTree(t)
+
+	got := slices.Collect(tree.All())
+	if !slices.IsSorted(got) {
+		t.Fatalf("expected the keys in ascending order, got %v", got)
+	}
+}
+
+func treeUnique(t *rapid.T) {
+	tree, keys := newTree(t)
+
+	expected := slices.Compact(slices.Sorted(slices.Values(keys)))
+
+	got := slices.Collect(tree.All())
+	if !slices.Equal(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+// reporter collects what rapid reports about a property, so that it can be
+// checked outside of go test. It implements rapid.TB.
+type reporter struct {
+	name   string
+	failed bool
+	err    string
+	logs   []string
+}
+
+func (r *reporter) Helper()      {}
+func (r *reporter) Name() string { return r.name }
+func (r *reporter) Failed() bool { return r.failed }
+func (r *reporter) Fail()        { r.failed = true }
+func (r *reporter) FailNow()     { r.failed = true }
+
+func (r *reporter) Logf(format string, args ...any) {
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+func (r *reporter) Log(args ...any) { r.logs = append(r.logs, fmt.Sprint(args...)) }
+
+func (r *reporter) Errorf(format string, args ...any) {
+	r.failed = true
+	r.err = fmt.Sprintf(format, args...)
+}
+func (r *reporter) Error(args ...any)                 { r.Errorf("%s", fmt.Sprint(args...)) }
+func (r *reporter) Fatalf(format string, args ...any) { r.Errorf(format, args...) }
+func (r *reporter) Fatal(args ...any)                 { r.Error(args...) }
+
+func (r *reporter) Skipf(format string, args ...any) {}
+func (r *reporter) Skip(args ...any)                 {}
+func (r *reporter) SkipNow()                         {}
+
+// failure returns why the property failed, the first line of what rapid
+// reported, along with the smallest input it found that breaks the property
+func (r *reporter) failure() (string, []string) {
+	reason, _, _ := strings.Cut(strings.TrimPrefix(r.err, "[rapid] "), "\n")
+
+	var draws []string
+	for _, l := range r.logs {
+		if d, ok := strings.CutPrefix(l, "[rapid] draw "); ok {
+			draws = append(draws, d)
+		}
+	}
+
+	return reason, draws
+}
+
+// check runs p against the inputs rapid generates. Once an input breaks the
+// property, rapid shrinks it to the smallest input which still does.
+func check(p property) *reporter {
+	r := &reporter{name: p.name}
+	rapid.Check(r, p.check)
+
+	return r
+}
+
+func main() {
+	// rapid reads the flags of the testing package, which are only
+	// registered by go test
+	testing.Init()
+
+	cfg := lessoncfg.Load(lessoncfg.Config{Seed: 1})
+
+	// A failing property would otherwise leave a file behind to replay it
+	_ = flag.Set("rapid.nofailfile", "true")
+	_ = flag.Set("rapid.seed", strconv.FormatUint(cfg.Seed, 10))
+
+	checks := flag.Lookup("rapid.checks").Value.String()
+	fmt.Printf("checking %d properties against %s inputs each\n", len(properties), checks)
+
+	for _, p := range properties {
+		fmt.Printf("\n%s\n", p.name)
+
+		r := check(p)
+		if !r.failed {
+			fmt.Println("  ok")
+			continue
+		}
+
+		reason, draws := r.failure()
+		fmt.Printf("  %s\n", reason)
+		for _, d := range draws {
+			fmt.Printf("  smallest input: %s\n", d)
+		}
+	}
+}
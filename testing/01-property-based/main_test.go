@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"strings"
+	"testing"
+
+	"pgregory.net/rapid"
+)
+
+// The properties which hold are ordinary property tests, which go test runs
+// with rapid.Check and the -rapid flags
+func TestProperties(t *testing.T) {
+	for _, p := range properties[:3] {
+		t.Run(p.name, func(t *testing.T) {
+			rapid.Check(t, p.check)
+		})
+	}
+}
+
+func TestTreeUniqueFails(t *testing.T) {
+	err := flag.Set("rapid.nofailfile", "true")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := check(property{"tree unique", treeUnique})
+	if !r.failed {
+		t.Fatal("expected the duplicate keys to break the property")
+	}
+
+	// Shrinking leaves a single key inserted twice
+	_, draws := r.failure()
+	if len(draws) != 1 || strings.Count(draws[0], ",") != 1 {
+		t.Fatalf("expected two keys as the smallest input, got %q", draws)
+	}
+
+	keys := strings.Split(strings.Trim(strings.TrimPrefix(draws[0], "keys: []int"), "{}"), ", ")
+	if keys[0] != keys[1] {
+		t.Fatalf("expected the same key twice, got %q", draws[0])
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"cmp"
+	"iter"
+)
+
+type (
+	// Tree is the binary search tree of the BST iterator exercise, with a
+	// bug in Insert: it never checks whether the tree already contains the
+	// key, so a duplicate is added again to the right of the first copy.
+	Tree[K cmp.Ordered] struct {
+		root *node[K]
+	}
+
+	node[K cmp.Ordered] struct {
+		key         K
+		left, right *node[K]
+	}
+)
+
+// Insert adds key to the tree
+func (t *Tree[K]) Insert(key K) {
+	current := &t.root
+	for *current != nil {
+		if key < (*current).key {
+			current = &(*current).left
+		} else {
+			current = &(*current).right
+		}
+	}
+
+	*current = &node[K]{key: key}
+}
+
+// All returns an iterator over the keys of the tree in ascending order
+func (t *Tree[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		t.root.walk(yield)
+	}
+}
+
+// walk yields the keys of the subtree in order, and returns false once yield
+// has returned false
+func (n *node[K]) walk(yield func(K) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	return n.left.walk(yield) && yield(n.key) && n.right.walk(yield)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// ErrInvalidCourse is wrapped by every error of parseCourse
+var ErrInvalidCourse = errors.New("invalid course")
+
+// parseCourse parses a line of the form id,name,university, such as a line of
+// the CSV of the database lesson. Spaces around the fields are ignored.
+func parseCourse(line string) (db.Course, error) {
+	fields := strings.Split(line, ",")
+	if len(fields) != 3 {
+		return db.Course{}, fmt.Errorf("%w: expected 3 fields, got %d", ErrInvalidCourse, len(fields))
+	}
+
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+
+	id, err := strconv.Atoi(fields[0])
+	if err != nil || id < 1 {
+		return db.Course{}, fmt.Errorf("%w: id %q is not a positive number", ErrInvalidCourse, fields[0])
+	}
+
+	if fields[1] == "" {
+		return db.Course{}, fmt.Errorf("%w: course %d has no name", ErrInvalidCourse, id)
+	}
+
+	if fields[2] == "" {
+		return db.Course{}, fmt.Errorf("%w: course %d has no university", ErrInvalidCourse, id)
+	}
+
+	return db.Course{ID: id, Name: fields[1], University: fields[2]}, nil
+}
+
+func main() {
+	lines := []string{
+		"1,Calculus-1,SJSU",
+		" 2 , Physics-1 , UCB ",
+		"3,Chem-1",
+		"four,Chem-2,UCSF",
+		"5,,UCSF",
+	}
+
+	for _, line := range lines {
+		course, err := parseCourse(line)
+		if err != nil {
+			fmt.Printf("%-22q error: %v\n", line, err)
+			continue
+		}
+
+		fmt.Printf("%-22q %+v\n", line, course)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+func TestParseCourse(t *testing.T) {
+	// Each case is a row of the table. A new case is a single line, and the
+	// name of each one says what it covers.
+	tests := []struct {
+		name     string
+		line     string
+		expected db.Course
+		wantErr  bool
+	}{
+		{"valid", "1,Calculus-1,SJSU", db.Course{ID: 1, Name: "Calculus-1", University: "SJSU"}, false},
+		{"spaces", " 2 , Physics-1 , UCB ", db.Course{ID: 2, Name: "Physics-1", University: "UCB"}, false},
+		{"too few fields", "3,Chem-1", db.Course{}, true},
+		{"too many fields", "3,Chem-1,UCSF,extra", db.Course{}, true},
+		{"empty line", "", db.Course{}, true},
+		{"id not a number", "four,Chem-2,UCSF", db.Course{}, true},
+		{"id zero", "0,Chem-2,UCSF", db.Course{}, true},
+		{"no name", "5,,UCSF", db.Course{}, true},
+		{"no university", "6,Chem-2, ", db.Course{}, true},
+	}
+
+	for _, tt := range tests {
+		// Each case runs as a subtest, which fails on its own and can be run
+		// on its own with -run 'TestParseCourse/no_name'
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseCourse(tt.line)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCourse) {
+					t.Fatalf("expected ErrInvalidCourse, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if got != tt.expected {
+				t.Fatalf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/04-database/db/dbtest"
+)
+
+// countByUniversity counts the courses of each university. It depends on the
+// db.CoursesDB interface rather than on SQLite, so a test can hand it any
+// implementation.
+func countByUniversity(courses db.CoursesDB) (map[string]int, error) {
+	counts := make(map[string]int)
+	for course, err := range courses.GetCourses() {
+		if err != nil {
+			return nil, fmt.Errorf("failed to count courses: %w", err)
+		}
+
+		counts[course.University]++
+	}
+
+	return counts, nil
+}
+
+// lookup returns the courses with the IDs, skipping the IDs which are not
+// found. Any other error stops it, without looking up the rest.
+func lookup(ctx context.Context, courses db.CoursesDB, ids []int) ([]db.Course, error) {
+	var found []db.Course
+	for _, id := range ids {
+		course, err := courses.GetCourse(ctx, id)
+		if errors.Is(err, db.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return found, fmt.Errorf("failed to look up course %d: %w", id, err)
+		}
+
+		found = append(found, course)
+	}
+
+	return found, nil
+}
+
+// show runs both functions against courses
+func show(ctx context.Context, courses db.CoursesDB) {
+	counts, err := countByUniversity(courses)
+	if err != nil {
+		fmt.Printf("  count:  %v\n", err)
+	} else {
+		fmt.Printf("  count:  %v\n", counts)
+	}
+
+	found, err := lookup(ctx, courses, []int{1, 1000, 2})
+	fmt.Printf("  lookup: %v, err: %v\n", found, err)
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 10, DataDir: os.TempDir(), Seed: 1})
+	db.Seed(cfg.Seed)
+
+	coursesDB, err := db.New(cfg.DataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create database: %v\n", err)
+		os.Exit(1)
+	}
+	defer coursesDB.Close()
+
+	err = coursesDB.Seed(cfg.Count)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to seed database: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+
+	fmt.Println("SQLite:")
+	show(ctx, coursesDB)
+
+	// The fake holds the courses in memory, which a test writes down next to
+	// what it expects of them
+	fmt.Println("\ndbtest.Fake:")
+	fake := dbtest.NewFake(
+		db.Course{ID: 1, Name: "Calculus-1", University: "SJSU"},
+		db.Course{ID: 2, Name: "Physics-1", University: "SJSU"},
+		db.Course{ID: 3, Name: "Chem-1", University: "UCB"},
+	)
+	show(ctx, fake)
+
+	// A failure which is hard to cause in SQLite is a single call away
+	fmt.Println("\ndbtest.Fake, failing:")
+	fake.Fail(0, errors.New("connection reset"))
+	show(ctx, fake)
+
+	fmt.Printf("\ncalls: %q\n", fake.Calls())
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/04-database/db/dbtest"
+)
+
+var courses = []db.Course{
+	{ID: 1, Name: "Calculus-1", University: "SJSU"},
+	{ID: 2, Name: "Physics-1", University: "SJSU"},
+	{ID: 3, Name: "Chem-1", University: "UCB"},
+}
+
+// The fake stands in for the database, with courses the test chose
+func TestCountByUniversity(t *testing.T) {
+	got, err := countByUniversity(dbtest.NewFake(courses...))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]int{"SJSU": 2, "UCB": 1}
+	if !maps.Equal(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+// Used as a stub, the fake returns an error for the function to handle
+func TestCountByUniversityError(t *testing.T) {
+	errBroken := errors.New("broken")
+
+	fake := dbtest.NewFake(courses...)
+	fake.Fail(2, errBroken)
+
+	counts, err := countByUniversity(fake)
+	if !errors.Is(err, errBroken) {
+		t.Fatalf("expected the error of the database, got %v", err)
+	}
+
+	if counts != nil {
+		t.Fatalf("expected no counts from a failed count, got %v", counts)
+	}
+}
+
+func TestLookupSkipsNotFound(t *testing.T) {
+	got, err := lookup(context.Background(), dbtest.NewFake(courses...), []int{3, 7, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []db.Course{courses[2], courses[0]}; !slices.Equal(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+// Used as a spy, the fake records the calls made to it, which shows that
+// lookup stops at the first error instead of only checking what it returned
+func TestLookupStopsAtError(t *testing.T) {
+	fake := dbtest.NewFake(courses...)
+	fake.Fail(0, errors.New("broken"))
+
+	_, err := lookup(context.Background(), fake, []int{1, 2, 3})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if calls := fake.Calls(); !slices.Equal(calls, []string{"GetCourse(1)"}) {
+		t.Fatalf("expected a single call, got %q", calls)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+type server struct {
+	db db.CoursesDB
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /courses/{id}", s.getCourse)
+
+	return mux
+}
+
+func (s *server) getCourse(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid course ID %q", r.PathValue("id")), http.StatusBadRequest)
+		return
+	}
+
+	course, err := s.db.GetCourse(r.Context(), id)
+	if errors.Is(err, db.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(course)
+}
+
+// client gets courses from the server at baseURL
+type client struct {
+	http    *http.Client
+	baseURL string
+}
+
+// getCourse returns the course with the ID, or an error wrapping
+// db.ErrNotFound if the server has no such course
+func (c *client) getCourse(ctx context.Context, id int) (db.Course, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/courses/%d", c.baseURL, id), nil)
+	if err != nil {
+		return db.Course{}, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return db.Course{}, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return db.Course{}, fmt.Errorf("%w: course %d", db.ErrNotFound, id)
+	default:
+		return db.Course{}, fmt.Errorf("failed to get course %d: %s", id, resp.Status)
+	}
+
+	var course db.Course
+	err = json.NewDecoder(resp.Body).Decode(&course)
+	if err != nil {
+		return db.Course{}, fmt.Errorf("failed to decode course %d: %w", id, err)
+	}
+
+	return course, nil
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 10, DataDir: os.TempDir(), Seed: 1})
+	db.Seed(cfg.Seed)
+
+	coursesDB, err := db.New(cfg.DataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create database: %v\n", err)
+		os.Exit(1)
+	}
+	defer coursesDB.Close()
+
+	err = coursesDB.Seed(cfg.Count)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to seed database: %v\n", err)
+		os.Exit(1)
+	}
+
+	s := &server{db: coursesDB}
+
+	// A ResponseRecorder is a ResponseWriter which keeps what the handler
+	// writes. The handler is called directly, without a network in between.
+	fmt.Println("httptest.NewRecorder:")
+	for _, path := range []string{"/courses/3", "/courses/1000", "/courses/three"} {
+		rec := httptest.NewRecorder()
+		s.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		fmt.Printf("  GET %-15s %d %s", path, rec.Code, rec.Body)
+	}
+
+	// A Server listens on a port of the loopback interface, so a client
+	// talks to the handler over a real connection
+	srv := httptest.NewServer(s.routes())
+	defer srv.Close()
+
+	fmt.Println("\nhttptest.NewServer:")
+	c := &client{http: srv.Client(), baseURL: srv.URL}
+	for _, id := range []int{3, 1000} {
+		course, err := c.getCourse(context.Background(), id)
+		fmt.Printf("  getCourse(%d): %+v, err: %v\n", id, course, err)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+	"github.com/manedurphy/golang-university/iterators/04-database/db/dbtest"
+)
+
+var course = db.Course{ID: 3, Name: "Chem-1", University: "UCB"}
+
+func TestGetCourseHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		fail     error
+		expected int
+	}{
+		{"found", http.MethodGet, "/courses/3", nil, http.StatusOK},
+		{"not found", http.MethodGet, "/courses/4", nil, http.StatusNotFound},
+		{"invalid id", http.MethodGet, "/courses/three", nil, http.StatusBadRequest},
+		{"database error", http.MethodGet, "/courses/3", errors.New("connection reset"), http.StatusInternalServerError},
+		{"wrong method", http.MethodPost, "/courses/3", nil, http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := dbtest.NewFake(course)
+			if tt.fail != nil {
+				fake.Fail(0, tt.fail)
+			}
+
+			rec := httptest.NewRecorder()
+			(&server{db: fake}).routes().ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.expected {
+				t.Fatalf("expected status %d, got %d: %s", tt.expected, rec.Code, rec.Body)
+			}
+		})
+	}
+}
+
+func TestGetCourseHandlerBody(t *testing.T) {
+	rec := httptest.NewRecorder()
+	(&server{db: dbtest.NewFake(course)}).routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/courses/3", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected a JSON response, got %q", ct)
+	}
+
+	var got db.Course
+	err := json.NewDecoder(rec.Body).Decode(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != course {
+		t.Fatalf("expected %+v, got %+v", course, got)
+	}
+}
+
+// newClient starts the server of the lesson over fake, and returns a client
+// of it. The server is closed when the test ends.
+func newClient(t *testing.T, fake *dbtest.Fake) *client {
+	t.Helper()
+
+	srv := httptest.NewServer((&server{db: fake}).routes())
+	t.Cleanup(srv.Close)
+
+	return &client{http: srv.Client(), baseURL: srv.URL}
+}
+
+func TestClient(t *testing.T) {
+	c := newClient(t, dbtest.NewFake(course))
+
+	got, err := c.getCourse(context.Background(), 3)
+	if err != nil || got != course {
+		t.Fatalf("expected %+v, got %+v and %v", course, got, err)
+	}
+
+	_, err = c.getCourse(context.Background(), 4)
+	if !errors.Is(err, db.ErrNotFound) {
+		t.Fatalf("expected db.ErrNotFound, got %v", err)
+	}
+}
+
+func TestClientServerError(t *testing.T) {
+	fake := dbtest.NewFake(course)
+	fake.Fail(0, errors.New("connection reset"))
+
+	_, err := newClient(t, fake).getCourse(context.Background(), 3)
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Fatalf("expected the status of the server in the error, got %v", err)
+	}
+}
+
+// A server can be any handler, such as one which sends a body the client
+// does not expect
+func TestClientInvalidBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not json"))
+	}))
+	defer srv.Close()
+
+	c := &client{http: srv.Client(), baseURL: srv.URL}
+
+	_, err := c.getCourse(context.Background(), 3)
+	if err == nil || !strings.Contains(err.Error(), "failed to decode") {
+		t.Fatalf("expected a decoding error, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// ErrUnnumbered is returned for a course whose name does not end in a number
+var ErrUnnumbered = errors.New("course has no number")
+
+// level returns the level of a course from the number at the end of its
+// name, such as 2 for Physics-2
+func level(course db.Course) (string, error) {
+	_, number, ok := strings.Cut(course.Name, "-")
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnnumbered, course.Name)
+	}
+
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrUnnumbered, course.Name)
+	}
+
+	switch {
+	case n < 1:
+		return "", fmt.Errorf("invalid number %d of %s", n, course.Name)
+	case n == 1:
+		return "introductory", nil
+	case n == 2:
+		return "intermediate", nil
+	default:
+		return "advanced", nil
+	}
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 6, Seed: 1})
+	db.Seed(cfg.Seed)
+
+	courses := slices.Collect(db.GenerateCourses(cfg.Count))
+	courses = append(courses, db.Course{ID: len(courses) + 1, Name: "Seminar", University: "UCB"})
+
+	for _, course := range courses {
+		l, err := level(course)
+		if err != nil {
+			fmt.Printf("%-10s error: %v\n", course.Name, err)
+			continue
+		}
+
+		fmt.Printf("%-10s %s\n", course.Name, l)
+	}
+}
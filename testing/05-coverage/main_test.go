@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+// The cases cover the courses of the database, and no more. The coverage
+// profile shows which branches of level they leave out, which is the
+// exercise of this lesson.
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"Chem-1", "introductory"},
+		{"Physics-2", "intermediate"},
+		{"Calculus-3", "advanced"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := level(db.Course{Name: tt.name})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if got != tt.expected {
+				t.Fatalf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
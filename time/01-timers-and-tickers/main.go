@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// since returns the time since start, rounded to the millisecond
+func since(start time.Time) time.Duration {
+	return time.Since(start).Round(time.Millisecond)
+}
+
+func main() {
+	// A timer sends the time on its channel once, when it fires
+	fmt.Println("timer:")
+	start := time.Now()
+	timer := time.NewTimer(50 * time.Millisecond)
+	<-timer.C
+	fmt.Printf("  fired after %v\n", since(start))
+
+	// Stop reports whether it stopped the timer before it fired. Since Go
+	// 1.23, nothing is received from the channel of a stopped timer.
+	timer = time.NewTimer(50 * time.Millisecond)
+	fmt.Printf("  stopped before firing: %t\n", timer.Stop())
+	select {
+	case <-timer.C:
+		fmt.Println("  received from a stopped timer")
+	case <-time.After(100 * time.Millisecond):
+		fmt.Println("  nothing received from a stopped timer")
+	}
+
+	// Reset reuses a timer, which starts over from the time of the call
+	start = time.Now()
+	timer.Reset(30 * time.Millisecond)
+	<-timer.C
+	fmt.Printf("  fired %v after Reset\n", since(start))
+
+	// A ticker sends the time every interval, until it is stopped
+	fmt.Println("\nticker:")
+	start = time.Now()
+	ticker := time.NewTicker(20 * time.Millisecond)
+	for i := range 3 {
+		<-ticker.C
+		fmt.Printf("  tick %d after %v\n", i+1, since(start))
+	}
+
+	// Its channel holds a single tick. A receiver which is slower than the
+	// interval gets one tick for all the ones it missed, and the ticker
+	// keeps its schedule instead of catching up.
+	time.Sleep(90 * time.Millisecond)
+
+	waiting := 0
+	for {
+		select {
+		case <-ticker.C:
+			waiting++
+			continue
+		default:
+		}
+
+		break
+	}
+	fmt.Printf("  after 90ms without receiving, %d tick waiting\n", waiting)
+
+	ticker.Stop()
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// hasMonotonic reports whether t carries a monotonic clock reading, which
+// its String shows as m=±<seconds>
+func hasMonotonic(t time.Time) bool {
+	return strings.Contains(t.String(), " m=")
+}
+
+func main() {
+	// time.Now reads two clocks. The wall clock tells the time of day, and
+	// can be set, such as by NTP. The monotonic clock only ever moves
+	// forward, and is what durations are measured with.
+	start := time.Now()
+	fmt.Println("time.Now:")
+	fmt.Printf("  monotonic reading: %t\n", hasMonotonic(start))
+
+	time.Sleep(20 * time.Millisecond)
+	end := time.Now()
+	fmt.Printf("  end.Sub(start) >= 20ms: %t\n", end.Sub(start) >= 20*time.Millisecond)
+
+	// Round(0) strips the monotonic reading, and so does everything which
+	// computes a new time of day, such as Truncate, or encoding the time
+	wall := start.Round(0)
+	fmt.Println("\nstripped:")
+	fmt.Printf("  Round(0):  %t\n", hasMonotonic(wall))
+
+	data, _ := json.Marshal(start)
+	var decoded time.Time
+	_ = json.Unmarshal(data, &decoded)
+	fmt.Printf("  JSON:      %t\n", hasMonotonic(decoded))
+	fmt.Printf("  Add(1h):   %t\n", hasMonotonic(start.Add(time.Hour)))
+
+	// Without a monotonic reading on both sides, Sub falls back to the wall
+	// clock. Had the wall clock been set back an hour between the two
+	// readings, as a leap or a correction of NTP can do, the duration would
+	// come out negative.
+	setBack := end.Round(0).Add(-time.Hour)
+	fmt.Println("\nwall clock set back an hour:")
+	fmt.Printf("  monotonic duration is negative: %t\n", end.Sub(start) < 0)
+	fmt.Printf("  wall duration is negative:      %t\n", setBack.Sub(wall) < 0)
+
+	// == compares the fields of the struct, including the monotonic reading
+	// and the location, while Equal compares the instants
+	fmt.Println("\ncomparing:")
+	fmt.Printf("  start == wall:     %t\n", start == wall)
+	fmt.Printf("  start.Equal(wall): %t\n", start.Equal(wall))
+	fmt.Printf("  start.Equal(UTC):  %t\n", start.Equal(start.UTC()))
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/clock"
+)
+
+// collectAfter is meant to collect the values of ch for timeout, but calls
+// After in the loop. Every iteration starts a new timer, so the timeout is
+// the time between two values rather than the time since the start, and a
+// channel which sends more often than that is collected until it is closed.
+// Each of those timers is allocated, too, and before Go 1.23 it stayed in
+// memory until it fired, long after the loop had moved on.
+func collectAfter(c clock.Clock, ch <-chan int, timeout time.Duration) []int {
+	var values []int
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return values
+			}
+
+			values = append(values, v)
+		case <-c.After(timeout):
+			return values
+		}
+	}
+}
+
+// collectFor collects the values of ch for timeout, with a single timer which
+// is started before the loop
+func collectFor(c clock.Clock, ch <-chan int, timeout time.Duration) []int {
+	timer := c.NewTimer(timeout)
+	defer timer.Stop()
+
+	var values []int
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return values
+			}
+
+			values = append(values, v)
+		case <-timer.C():
+			return values
+		}
+	}
+}
+
+// collectIdle collects the values of ch until it has been idle for timeout,
+// which is what collectAfter does. It resets a single timer for each value
+// instead of allocating a new one.
+func collectIdle(c clock.Clock, ch <-chan int, timeout time.Duration) []int {
+	timer := c.NewTimer(timeout)
+	defer timer.Stop()
+
+	var values []int
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return values
+			}
+
+			values = append(values, v)
+			timer.Reset(timeout)
+		case <-timer.C():
+			return values
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/clock"
+)
+
+const (
+	timeout  = 100 * time.Millisecond
+	interval = 20 * time.Millisecond
+	values   = 15
+)
+
+// produce sends values on a channel, one every interval, and closes it. The
+// channel holds every value, so the producer is not left blocked when a
+// collector stops early.
+func produce() <-chan int {
+	ch := make(chan int, values)
+
+	go func() {
+		defer close(ch)
+
+		for i := range values {
+			time.Sleep(interval)
+			ch <- i
+		}
+	}()
+
+	return ch
+}
+
+// allocsPerValue returns the number of allocations collect makes for each
+// value of a channel which always has one ready
+func allocsPerValue(collect func(clock.Clock, <-chan int, time.Duration) []int) float64 {
+	const n = 10000
+
+	ch := make(chan int, n)
+
+	return testing.AllocsPerRun(10, func() {
+		for i := range n {
+			ch <- i
+		}
+		close(ch)
+
+		collect(clock.Real, ch, time.Hour)
+		ch = make(chan int, n)
+	}) / n
+}
+
+func main() {
+	collectors := []struct {
+		name    string
+		collect func(clock.Clock, <-chan int, time.Duration) []int
+	}{
+		{"collectAfter", collectAfter},
+		{"collectFor", collectFor},
+		{"collectIdle", collectIdle},
+	}
+
+	fmt.Printf("%d values, one every %v, collected for %v:\n", values, interval, timeout)
+	for _, c := range collectors {
+		start := time.Now()
+		got := c.collect(clock.Real, produce(), timeout)
+		fmt.Printf("  %-12s %2d values in %v\n", c.name, len(got), time.Since(start).Round(10*time.Millisecond))
+	}
+
+	fmt.Println("\nallocations per value:")
+	for _, c := range collectors {
+		fmt.Printf("  %-12s %.2f\n", c.name, allocsPerValue(c.collect))
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/clock"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const step = 50 * time.Millisecond
+
+// run collects from a new channel in a goroutine of its own, with a timeout
+// of 200ms, and returns the channel and where the values will be sent
+func run(fake *clock.Fake, collect func(clock.Clock, <-chan int, time.Duration) []int) (chan<- int, <-chan []int) {
+	ch := make(chan int)
+	result := make(chan []int, 1)
+
+	go func() {
+		result <- collect(fake, ch, 4*step)
+	}()
+
+	return ch, result
+}
+
+// The timeout of collectAfter starts over with every value, so it never
+// fires while the values come every 50ms
+func TestCollectAfterNeverTimesOut(t *testing.T) {
+	fake := clock.NewFake(start)
+	ch, result := run(fake, collectAfter)
+
+	for i := range 10 {
+		ch <- i
+		fake.Advance(step)
+	}
+
+	select {
+	case got := <-result:
+		t.Fatalf("expected collectAfter to still be collecting after 500ms, got %v", got)
+	default:
+	}
+
+	close(ch)
+	if got := <-result; len(got) != 10 {
+		t.Fatalf("expected every value, got %v", got)
+	}
+}
+
+func TestCollectFor(t *testing.T) {
+	fake := clock.NewFake(start)
+	ch, result := run(fake, collectFor)
+
+	// The timer is started before the first value
+	fake.BlockUntil(1)
+
+	for i := range 4 {
+		ch <- i
+		fake.Advance(step)
+	}
+
+	if got := <-result; !slices.Equal(got, []int{0, 1, 2, 3}) {
+		t.Fatalf("expected the values of the first 200ms, got %v", got)
+	}
+}
+
+func TestCollectIdle(t *testing.T) {
+	fake := clock.NewFake(start)
+	ch, result := run(fake, collectIdle)
+
+	fake.BlockUntil(1)
+
+	for i := range 10 {
+		ch <- i
+		fake.Advance(step)
+	}
+
+	select {
+	case got := <-result:
+		t.Fatalf("expected collectIdle to still be collecting while values come, got %v", got)
+	default:
+	}
+
+	// Without a value for 200ms, the timer fires
+	fake.Advance(4 * step)
+	if got := <-result; len(got) != 10 {
+		t.Fatalf("expected every value, got %v", got)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/clock"
+	"github.com/manedurphy/golang-university/internal/lessoncfg"
+	"github.com/manedurphy/golang-university/iterators/04-database/db"
+)
+
+const (
+	timeout = 100 * time.Millisecond
+	stall   = time.Second
+)
+
+// stalling yields the courses, and stalls for a second before the fourth
+// one, like a read from a server which stopped responding
+func stalling(c clock.Clock, courses []db.Course) iter.Seq[db.Course] {
+	return func(yield func(db.Course) bool) {
+		for i, course := range courses {
+			if i == 3 {
+				c.Sleep(stall)
+			}
+
+			if !yield(course) {
+				return
+			}
+		}
+	}
+}
+
+func main() {
+	cfg := lessoncfg.Load(lessoncfg.Config{Count: 5, Seed: 1})
+	db.Seed(cfg.Seed)
+
+	courses := slices.Collect(db.GenerateCourses(cfg.Count))
+
+	consumers := []struct {
+		name string
+		seq  iter.Seq2[db.Course, error]
+	}{
+		{"checkElapsed", checkElapsed(clock.Real, stalling(clock.Real, courses), timeout)},
+		{"WithTimeout", WithTimeout(clock.Real, stalling(clock.Real, courses), timeout)},
+	}
+
+	for i, c := range consumers {
+		if i > 0 {
+			fmt.Println()
+		}
+
+		fmt.Printf("%s, with a timeout of %v:\n", c.name, timeout)
+
+		start := time.Now()
+		for course, err := range c.seq {
+			if errors.Is(err, ErrTimeout) {
+				fmt.Printf("  %v, noticed after %v\n", err, time.Since(start).Round(10*time.Millisecond))
+				break
+			}
+
+			fmt.Printf("  %d %s\n", course.ID, course.Name)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"iter"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/clock"
+	"github.com/manedurphy/golang-university/internal/testutil"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// slow yields 1 and 2 at once, and 3 after sleeping for a second
+func slow(c clock.Clock) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		if !yield(1) || !yield(2) {
+			return
+		}
+
+		c.Sleep(time.Second)
+		yield(3)
+	}
+}
+
+type result struct {
+	value int
+	err   error
+}
+
+// consume ranges over seq in a goroutine of its own, and sends each pair it
+// yields
+func consume(seq iter.Seq2[int, error]) <-chan result {
+	results := make(chan result)
+
+	go func() {
+		defer close(results)
+
+		for v, err := range seq {
+			results <- result{v, err}
+		}
+	}()
+
+	return results
+}
+
+func TestWithTimeout(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	fake := clock.NewFake(start)
+	results := consume(WithTimeout(fake, slow(fake), 500*time.Millisecond))
+
+	for _, expected := range []int{1, 2} {
+		if got := <-results; got.value != expected || got.err != nil {
+			t.Fatalf("expected %d, got %+v", expected, got)
+		}
+	}
+
+	// The timer, once the loop body returns, and the sleep of slow
+	fake.BlockUntil(2)
+	fake.Advance(500 * time.Millisecond)
+
+	if got := <-results; !errors.Is(got.err, ErrTimeout) {
+		t.Fatalf("expected ErrTimeout after 500ms, got %+v", got)
+	}
+
+	if _, ok := <-results; ok {
+		t.Fatal("expected the loop to stop after the timeout")
+	}
+
+	// The goroutine of slow stops at its next yield
+	fake.Advance(time.Second)
+}
+
+func TestWithTimeoutSlowBody(t *testing.T) {
+	testutil.VerifyNoLeaks(t)
+
+	fake := clock.NewFake(start)
+
+	sleeping := make(chan struct{})
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+
+		// The loop body takes longer than the timeout, which does not count
+		for _, err := range WithTimeout(fake, slices.Values([]int{1, 2}), 500*time.Millisecond) {
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			sleeping <- struct{}{}
+			fake.Sleep(time.Second)
+		}
+	}()
+
+	// The timer is stopped while the loop body runs, so the only waiter is
+	// its sleep
+	for range 2 {
+		<-sleeping
+		fake.BlockUntil(1)
+		fake.Advance(time.Second)
+	}
+
+	if err := <-errs; err != nil {
+		t.Fatalf("expected no timeout, got %v", err)
+	}
+}
+
+func TestCheckElapsedWaits(t *testing.T) {
+	fake := clock.NewFake(start)
+	results := consume(checkElapsed(fake, slow(fake), 500*time.Millisecond))
+
+	<-results
+	<-results
+
+	fake.BlockUntil(1)
+	fake.Advance(500 * time.Millisecond)
+
+	// The timeout has passed, but nothing can notice until slow yields
+	select {
+	case got := <-results:
+		t.Fatalf("expected checkElapsed to still be waiting, got %+v", got)
+	default:
+	}
+
+	fake.Advance(500 * time.Millisecond)
+	if got := <-results; !errors.Is(got.err, ErrTimeout) {
+		t.Fatalf("expected ErrTimeout once slow yields, got %+v", got)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/manedurphy/golang-university/internal/clock"
+)
+
+// ErrTimeout is yielded when the next value takes longer than the timeout
+var ErrTimeout = errors.New("timed out waiting for the next value")
+
+// checkElapsed is meant to stop waiting for seq once a value takes longer
+// than timeout, but it can only check the time once seq yields. A value which
+// takes a minute is noticed after a minute, and one which never comes is
+// never noticed at all.
+func checkElapsed[T any](c clock.Clock, seq iter.Seq[T], timeout time.Duration) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		last := c.Now()
+		for v := range seq {
+			if waited := c.Now().Sub(last); waited > timeout {
+				var zero T
+				yield(zero, fmt.Errorf("%w after %v", ErrTimeout, waited.Round(time.Millisecond)))
+				return
+			}
+
+			if !yield(v, nil) {
+				return
+			}
+			last = c.Now()
+		}
+	}
+}
+
+// WithTimeout yields the values of seq, and ErrTimeout if the next value takes
+// longer than timeout, at which point it stops. seq runs in a goroutine of its
+// own, so the loop can wait for a value and the timer at the same time. The
+// time the loop body takes does not count against the timeout.
+//
+// Once the loop stops, the goroutine is told to stop at its next yield. A seq
+// which is stuck, such as in a read without a deadline, keeps the goroutine
+// until it gets there.
+func WithTimeout[T any](c clock.Clock, seq iter.Seq[T], timeout time.Duration) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		values := make(chan T)
+		done := make(chan struct{})
+		defer close(done)
+
+		go func() {
+			defer close(values)
+
+			for v := range seq {
+				select {
+				case values <- v:
+				case <-done:
+					return
+				}
+			}
+		}()
+
+		timer := c.NewTimer(timeout)
+		defer timer.Stop()
+
+		for {
+			select {
+			case v, ok := <-values:
+				if !ok {
+					return
+				}
+
+				timer.Stop()
+				if !yield(v, nil) {
+					return
+				}
+				timer.Reset(timeout)
+			case <-timer.C():
+				var zero T
+				yield(zero, fmt.Errorf("%w after %v", ErrTimeout, timeout))
+				return
+			}
+		}
+	}
+}